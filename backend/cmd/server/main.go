@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -15,11 +18,18 @@ import (
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/api"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/demo"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/loadtest"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/metrics"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/rbac"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/status"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/service"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/webhooks"
 
 	// _ "github.com/Forcebyte/flux-orchestrator/docs" // swagger docs - disabled for build compatibility
@@ -48,6 +58,9 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
+	preflight := flag.Bool("preflight", false, "validate configuration and connectivity, print a report and exit")
+	flag.Parse()
+
 	// Initialize logger
 	isDev := logging.IsDevelopment()
 	if err := logging.InitLogger(isDev); err != nil {
@@ -58,17 +71,13 @@ func main() {
 	logger := logging.GetLogger()
 	logger.Info("Starting Flux Orchestrator", zap.Bool("development", isDev))
 
-	// Load database configuration from environment
-	dbConfig := database.Config{
-		Driver:   getEnv("DB_DRIVER", "postgres"),
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnvInt("DB_PORT", 5432),
-		User:     getEnv("DB_USER", "postgres"),
-		Password: getEnv("DB_PASSWORD", "postgres"),
-		DBName:   getEnv("DB_NAME", "flux_orchestrator"),
-		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+	if *preflight {
+		os.Exit(runPreflight(os.Stdout))
 	}
 
+	// Load database configuration from environment
+	dbConfig := dbConfigFromEnv()
+
 	// Initialize encryption
 	encryptionKey := getEnv("ENCRYPTION_KEY", "")
 	if encryptionKey == "" {
@@ -109,13 +118,24 @@ func main() {
 		&models.Cluster{}, 
 		&models.FluxResource{}, 
 		&models.AzureSubscription{}, 
+		&models.RegistryCredential{},
 		&models.OAuthProvider{}, 
 		&models.Activity{},
+		&models.ClusterStatusSnapshot{},
+		&models.ResourceStatusTransition{},
+		&models.DeploymentEvent{},
+		&models.WebhookEvent{},
+		&models.ReportTemplate{},
+		&models.APIToken{},
+		&models.Session{},
+		&models.ProviderGrant{},
 		&models.User{},
 		&models.Role{},
 		&models.Permission{},
 		&models.UserRole{},
 		&models.RolePermission{},
+		&models.RoleMappingRule{},
+		&models.ClusterShare{},
 	); err != nil {
 		logger.Fatal("Failed to initialize schema", zap.Error(err))
 	}
@@ -129,6 +149,7 @@ func main() {
 
 	// Create Kubernetes client
 	k8sClient := k8s.NewClient()
+	k8sClient.SetClusterSource(service.NewClusterSource(db, encryptor))
 
 	// Check if we should scrape the cluster we're running in
 	scrapeInCluster := getEnv("SCRAPE_IN_CLUSTER", "false") == "true"
@@ -142,7 +163,7 @@ func main() {
 		var existingCluster models.Cluster
 		err := db.Where("name = ?", inClusterName).First(&existingCluster).Error
 		
-		if err != nil && err.Error() != "record not found" {
+		if err != nil && !repository.IsNotFound(err) {
 			logger.Warn("Failed to check for existing in-cluster config", zap.Error(err))
 		} else if existingCluster.ID == "" {
 			// Register in-cluster configuration
@@ -167,7 +188,7 @@ func main() {
 					KubeConfig:  "",
 					Status:      status,
 				}
-				err = db.Create(&cluster).Error
+				err = repository.NewClusterRepository(db).Create(&cluster)
 				
 				if err != nil {
 					logger.Warn("Failed to save in-cluster configuration to database", zap.Error(err))
@@ -177,6 +198,10 @@ func main() {
 			}
 		} else {
 			// In-cluster already exists, just ensure it's loaded
+			if err := k8sClient.SetClientLimits(existingCluster.ID, service.ClusterClientLimits(&existingCluster)); err != nil {
+				logger.Warn("Ignoring invalid client limits", zap.String("cluster_id", existingCluster.ID), zap.Error(err))
+			}
+			k8sClient.SetImpersonation(existingCluster.ID, existingCluster.ImpersonateUsers)
 			if err := k8sClient.AddInClusterConfig(existingCluster.ID); err != nil {
 				logger.Warn("Failed to reload in-cluster configuration", zap.Error(err))
 			} else {
@@ -185,9 +210,9 @@ func main() {
 		}
 	}
 
-	// Load existing clusters from database
+	// Load existing clusters from database; archived clusters stay disconnected
 	var clusters []models.Cluster
-	if err := db.Where("kubeconfig != ?", "").Find(&clusters).Error; err != nil {
+	if err := db.Where("kubeconfig != ? AND archived = ?", "", false).Find(&clusters).Error; err != nil {
 		logger.Warn("Failed to load existing clusters", zap.Error(err))
 	} else {
 		for _, cluster := range clusters {
@@ -198,6 +223,20 @@ func main() {
 				continue
 			}
 
+			// Backfill the server fingerprint for clusters registered before it was recorded
+			if cluster.ServerURL == "" {
+				if fingerprint, err := k8s.KubeconfigFingerprint(kubeconfig); err == nil {
+					db.Model(&models.Cluster{}).Where("id = ?", cluster.ID).Updates(map[string]interface{}{
+						"server_url":     fingerprint.Server,
+						"ca_fingerprint": fingerprint.CAFingerprint,
+					})
+				}
+			}
+
+			if err := k8sClient.SetClientLimits(cluster.ID, service.ClusterClientLimits(&cluster)); err != nil {
+				logger.Warn("Ignoring invalid client limits", zap.String("cluster_id", cluster.ID), zap.Error(err))
+			}
+			k8sClient.SetImpersonation(cluster.ID, cluster.ImpersonateUsers)
 			if err := k8sClient.AddCluster(cluster.ID, kubeconfig); err != nil {
 				logger.Warn("Failed to add cluster", zap.String("cluster_id", cluster.ID), zap.Error(err))
 			} else {
@@ -206,22 +245,32 @@ func main() {
 		}
 	}
 
-	// Configure OAuth if enabled
-	var oauthProvider *auth.OAuthProvider
-	if getEnv("OAUTH_ENABLED", "false") == "true" {
-		oauthConfig := auth.Config{
-			Enabled:      true,
-			Provider:     getEnv("OAUTH_PROVIDER", "github"), // "github" or "entra"
-			ClientID:     getEnv("OAUTH_CLIENT_ID", ""),
-			ClientSecret: getEnv("OAUTH_CLIENT_SECRET", ""),
-			RedirectURL:  getEnv("OAUTH_REDIRECT_URL", "http://localhost:8080/api/v1/auth/callback"),
-			Scopes:       strings.Split(getEnv("OAUTH_SCOPES", ""), ","),
+	// Seed synthetic clusters backed by in-memory fakes for evaluation and frontend development
+	if getEnv("DEMO_MODE", "false") == "true" {
+		logger.Warn("DEMO_MODE enabled - seeding synthetic clusters, resources and activity")
+		if err := demo.Seed(context.Background(), db, k8sClient); err != nil {
+			logger.Error("Failed to seed demo data", zap.Error(err))
 		}
+	}
 
-		// Parse allowed users if specified
-		if allowedUsersStr := getEnv("OAUTH_ALLOWED_USERS", ""); allowedUsersStr != "" {
-			oauthConfig.AllowedUsers = strings.Split(allowedUsersStr, ",")
+	// Seed many synthetic clusters for measuring list, tree and sync latency at scale
+	if loadtestClusters := getEnvInt("LOADTEST_CLUSTERS", 0); loadtestClusters > 0 {
+		loadtestResources := getEnvInt("LOADTEST_RESOURCES", 100)
+		logger.Warn("Load-test mode enabled - seeding synthetic clusters",
+			zap.Int("clusters", loadtestClusters),
+			zap.Int("resources_per_cluster", loadtestResources))
+		start := time.Now()
+		if err := loadtest.Seed(context.Background(), db, k8sClient, loadtestClusters, loadtestResources); err != nil {
+			logger.Error("Failed to seed load-test data", zap.Error(err))
+		} else {
+			logger.Info("Load-test data seeded", zap.Duration("duration", time.Since(start)))
 		}
+	}
+
+	// Configure OAuth if enabled
+	var oauthProvider *auth.OAuthProvider
+	if getEnv("OAUTH_ENABLED", "false") == "true" {
+		oauthConfig := oauthConfigFromEnv()
 
 		var err error
 		oauthProvider, err = auth.NewOAuthProvider(oauthConfig)
@@ -234,13 +283,26 @@ func main() {
 	}
 
 	// Configure webhooks
-	webhookURLsStr := getEnv("WEBHOOK_URLS", "")
-	webhookURLs := webhooks.ParseWebhookURLs(webhookURLsStr)
-	notifier := webhooks.NewNotifier(webhookURLs, logger.Named("webhooks"))
-	if len(webhookURLs) > 0 {
-		logger.Info("Webhook notifications enabled", zap.Int("webhook_count", len(webhookURLs)))
+	webhookSchemaVersion, err := strconv.Atoi(getEnv("WEBHOOK_SCHEMA_VERSION", "1"))
+	if err != nil {
+		logger.Fatal("Invalid WEBHOOK_SCHEMA_VERSION", zap.Error(err))
+	}
+	webhookEndpoints, err := webhooks.ParseEndpoints(getEnv("WEBHOOK_URLS", ""), getEnv("WEBHOOK_CONFIG", ""), webhookSchemaVersion)
+	if err != nil {
+		logger.Fatal("Invalid webhook configuration", zap.Error(err))
+	}
+	notifier := webhooks.NewNotifier(webhookEndpoints, logger.Named("webhooks"))
+	if len(webhookEndpoints) > 0 {
+		logger.Info("Webhook notifications enabled", zap.Int("webhook_count", len(webhookEndpoints)))
 	}
 
+	// Record events so reconnecting UI clients can replay what they missed
+	eventStore, err := webhooks.NewStore(db, getEnvInt("EVENT_BUFFER_SIZE", webhooks.DefaultStoreCapacity), logger.Named("events"))
+	if err != nil {
+		logger.Fatal("Failed to load recent events", zap.Error(err))
+	}
+	notifier.SetStore(eventStore)
+
 	// Create API server
 	apiServer := api.NewServer(db, k8sClient, encryptor, oauthProvider, notifier)
 
@@ -252,6 +314,19 @@ func main() {
 		close(syncDone)
 	}()
 
+	// Stream Flux resource changes into the database between periodic syncs
+	if getEnv("FLUX_WATCH_ENABLED", "true") == "true" {
+		k8sClient.WatchFluxResources(syncCtx, func(event k8s.FluxEvent) {
+			applyFluxEvent(db, notifier, event)
+		})
+		logger.Info("Watching Flux resources for live updates")
+	}
+
+	// Rebuild clients after persistent connection failures and drop those of removed clusters
+	if reconnectInterval := getEnvInt("K8S_RECONNECT_INTERVAL_SECONDS", 60); reconnectInterval > 0 {
+		go k8sClient.RunReconnector(syncCtx, time.Duration(reconnectInterval)*time.Second)
+	}
+
 	// Start HTTP server
 	port := getEnv("PORT", "8080")
 	addr := fmt.Sprintf(":%s", port)
@@ -269,6 +344,20 @@ func main() {
 		IdleTimeout:  time.Duration(idleTimeout) * time.Second,
 	}
 
+	// Serve HTTPS when a certificate is configured, verifying client certificates for mTLS
+	// authentication when a client CA is set
+	certFile, keyFile := getEnv("TLS_CERT_FILE", ""), getEnv("TLS_KEY_FILE", "")
+	if clientCAFile := getEnv("TLS_CLIENT_CA_FILE", ""); clientCAFile != "" {
+		if certFile == "" || keyFile == "" {
+			logger.Fatal("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		tlsConfig, err := clientCertTLSConfig(clientCAFile)
+		if err != nil {
+			logger.Fatal("Failed to configure client certificate authentication", zap.Error(err))
+		}
+		server.TLSConfig = tlsConfig
+	}
+
 	// Channel to listen for server errors
 	serverErrors := make(chan error, 1)
 	
@@ -277,9 +366,14 @@ func main() {
 		logger.Info("Server starting", 
 			zap.String("address", addr),
 			zap.Bool("oauth_enabled", oauthProvider != nil),
+			zap.Bool("tls", certFile != ""),
 			zap.Int("read_timeout", readTimeout),
 			zap.Int("write_timeout", writeTimeout),
 		)
+		if certFile != "" && keyFile != "" {
+			serverErrors <- server.ListenAndServeTLS(certFile, keyFile)
+			return
+		}
 		serverErrors <- server.ListenAndServe()
 	}()
 
@@ -329,13 +423,14 @@ func main() {
 }
 
 // syncWorker periodically syncs resources from all clusters
-func syncWorker(ctx context.Context, db *database.DB, k8sClient *k8s.Client, notifier *webhooks.Notifier) {
+func syncWorker(ctx context.Context, db *database.DB, k8sClient k8s.ClusterClient, notifier *webhooks.Notifier) {
 	logger := logging.GetLogger().Named("sync-worker")
 	
 	// Start with default interval
 	interval := 5 * time.Minute
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	status.SyncWorker.SetInterval(interval)
 
 	// Channel for dynamic interval updates
 	updateInterval := make(chan time.Duration, 1)
@@ -369,15 +464,27 @@ func syncWorker(ctx context.Context, db *database.DB, k8sClient *k8s.Client, not
 		case newInterval := <-updateInterval:
 			interval = newInterval
 			ticker.Reset(interval)
+			status.SyncWorker.SetInterval(interval)
 		case <-ticker.C:
 			logger.Info("Running periodic sync")
+			syncStart := time.Now()
+
+			var clusterCount int64
+			if err := db.Model(&models.Cluster{}).Count(&clusterCount).Error; err == nil {
+				metrics.ClustersTotal.Set(float64(clusterCount))
+			}
 
 			var clusters []models.Cluster
-			if err := db.Where("status = ?", "healthy").Find(&clusters).Error; err != nil {
+			if err := db.Where("status = ? AND archived = ?", "healthy", false).Find(&clusters).Error; err != nil {
 				logger.Error("Failed to query clusters", zap.Error(err))
+				status.SyncWorker.RecordRun(syncStart, 0, 0, err)
 				continue
 			}
 
+			healthyClusters := 0
+			syncFailures := 0
+			metrics.FluxResourcesTotal.Reset()
+
 		for _, cluster := range clusters {
 			clusterID := cluster.ID
 			clusterLogger := logger.With(zap.String("cluster_id", clusterID))
@@ -394,18 +501,55 @@ func syncWorker(ctx context.Context, db *database.DB, k8sClient *k8s.Client, not
 
 			if err != nil {
 				clusterLogger.Warn("Cluster is unhealthy", zap.Error(err))
+				metrics.SyncErrorsTotal.WithLabelValues(clusterID, "health_check").Inc()
 				notifier.NotifySyncFailed(clusterID, err.Error())
+				syncFailures++
 				continue
 			}
+			healthyClusters++
+
+			// Check that Flux itself is installed and running
+			if fluxHealth, err := k8sClient.CheckFluxInstallation(ctx, clusterID); err != nil {
+				clusterLogger.Warn("Failed to check Flux installation", zap.Error(err))
+			} else {
+				db.Model(&models.Cluster{}).Where("id = ?", clusterID).Updates(map[string]interface{}{
+					"flux_status":  fluxHealth.Status,
+					"flux_message": fluxHealth.Message,
+					"flux_version": fluxHealth.Version,
+				})
+				if cluster.FluxStatus != fluxHealth.Status {
+					notifier.NotifyFluxStatusChanged(clusterID, cluster.FluxStatus, fluxHealth.Status, fluxHealth.Message)
+				}
+			}
 
 			// Sync resources
 			resources, err := k8sClient.GetFluxResources(clusterID)
 			if err != nil {
 				clusterLogger.Error("Failed to get resources", zap.Error(err))
+				metrics.SyncErrorsTotal.WithLabelValues(clusterID, "list_resources").Inc()
 				notifier.NotifySyncFailed(clusterID, err.Error())
+				syncFailures++
 				continue
 			}
 
+			for _, res := range resources {
+				metrics.FluxResourcesTotal.WithLabelValues(clusterID, res.Kind, res.Status).Inc()
+			}
+
+			if err := repository.NewResourceRepository(db).AssignIDs(clusterID, resources); err != nil {
+				clusterLogger.Error("Failed to resolve resource IDs", zap.Error(err))
+				metrics.SyncErrorsTotal.WithLabelValues(clusterID, "save_resources").Inc()
+				syncFailures++
+				continue
+			}
+
+			if err := history.RecordTransitions(db, clusterID, resources); err != nil {
+				clusterLogger.Warn("Failed to record status transitions", zap.Error(err))
+			}
+			if err := history.RecordDeployments(db, clusterID, resources); err != nil {
+				clusterLogger.Warn("Failed to record deployments", zap.Error(err))
+			}
+
 			for _, res := range resources {
 				// Use GORM's Clauses for upsert
 				if err := db.Save(&res).Error; err != nil {
@@ -413,13 +557,70 @@ func syncWorker(ctx context.Context, db *database.DB, k8sClient *k8s.Client, not
 				}
 			}
 
+			if err := history.RecordClusterSnapshot(db, clusterID, resources); err != nil {
+				clusterLogger.Warn("Failed to record status history", zap.Error(err))
+			}
+
 			clusterLogger.Info("Synced resources", zap.Int("count", len(resources)))
 			notifier.NotifySyncCompleted(clusterID, len(resources))
 		}
+
+			metrics.ClustersHealthy.Set(float64(healthyClusters))
+			metrics.SyncDuration.Observe(time.Since(syncStart).Seconds())
+			status.SyncWorker.RecordRun(syncStart, len(clusters), syncFailures, nil)
 		}
 	}
 }
 
+// dbConfigFromEnv loads the database configuration from the environment
+func dbConfigFromEnv() database.Config {
+	return database.Config{
+		Driver:   getEnv("DB_DRIVER", "postgres"),
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnvInt("DB_PORT", 5432),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", "postgres"),
+		DBName:   getEnv("DB_NAME", "flux_orchestrator"),
+		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+	}
+}
+
+// oauthConfigFromEnv loads the OAuth configuration from the environment
+func oauthConfigFromEnv() auth.Config {
+	oauthConfig := auth.Config{
+		Enabled:      true,
+		Provider:     getEnv("OAUTH_PROVIDER", "github"), // "github" or "entra"
+		ClientID:     getEnv("OAUTH_CLIENT_ID", ""),
+		ClientSecret: getEnv("OAUTH_CLIENT_SECRET", ""),
+		RedirectURL:  getEnv("OAUTH_REDIRECT_URL", "http://localhost:8080/api/v1/auth/callback"),
+		Scopes:       strings.Split(getEnv("OAUTH_SCOPES", ""), ","),
+	}
+
+	// Parse allowed users if specified
+	if allowedUsersStr := getEnv("OAUTH_ALLOWED_USERS", ""); allowedUsersStr != "" {
+		oauthConfig.AllowedUsers = strings.Split(allowedUsersStr, ",")
+	}
+	return oauthConfig
+}
+
+// clientCertTLSConfig requests client certificates and verifies the ones presented against
+// the CA bundle in caFile. Clients without a certificate can still use other credentials.
+func clientCertTLSConfig(caFile string) (*tls.Config, error) {
+	pemData, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
 // getEnv gets an environment variable with a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// preflightTimeout bounds each network check made by --preflight
+const preflightTimeout = 15 * time.Second
+
+// preflightResult is the outcome of a single preflight check
+type preflightResult struct {
+	Name    string
+	Status  string // pass, fail, skip
+	Message string
+}
+
+// preflight collects check results for the report
+type preflight struct {
+	results []preflightResult
+}
+
+func (p *preflight) pass(name, format string, args ...interface{}) {
+	p.results = append(p.results, preflightResult{name, "pass", fmt.Sprintf(format, args...)})
+}
+
+func (p *preflight) fail(name, format string, args ...interface{}) {
+	p.results = append(p.results, preflightResult{name, "fail", fmt.Sprintf(format, args...)})
+}
+
+func (p *preflight) skip(name, format string, args ...interface{}) {
+	p.results = append(p.results, preflightResult{name, "skip", fmt.Sprintf(format, args...)})
+}
+
+// failed reports whether any check failed
+func (p *preflight) failed() bool {
+	for _, r := range p.results {
+		if r.Status == "fail" {
+			return true
+		}
+	}
+	return false
+}
+
+// runPreflight validates the configuration without starting the server or changing the
+// database. It writes a report to out and returns the process exit code: 0 when every
+// check passed or was skipped, 1 otherwise. Intended for init containers and CI gates.
+func runPreflight(out io.Writer) int {
+	p := &preflight{}
+
+	encryptor := p.checkEncryption()
+	db := p.checkDatabase()
+	if db != nil {
+		if sqlDB, err := db.DB.DB(); err == nil {
+			defer sqlDB.Close()
+		}
+	}
+	p.checkStoredSecrets(db, encryptor)
+	p.checkOAuth()
+	p.checkClusters(db, encryptor)
+
+	fmt.Fprintln(out, "Flux Orchestrator preflight")
+	for _, r := range p.results {
+		fmt.Fprintf(out, "  [%s] %-16s %s\n", r.Status, r.Name, r.Message)
+	}
+
+	if p.failed() {
+		fmt.Fprintln(out, "Preflight FAILED")
+		return 1
+	}
+	fmt.Fprintln(out, "Preflight passed")
+	return 0
+}
+
+// checkEncryption validates ENCRYPTION_KEY and round-trips a sample value
+func (p *preflight) checkEncryption() *encryption.Encryptor {
+	key := getEnv("ENCRYPTION_KEY", "")
+	if key == "" {
+		p.fail("encryption", "ENCRYPTION_KEY environment variable is required")
+		return nil
+	}
+
+	encryptor, err := encryption.NewEncryptor(key)
+	if err != nil {
+		p.fail("encryption", "Invalid ENCRYPTION_KEY: %v", err)
+		return nil
+	}
+
+	const sample = "flux-orchestrator-preflight"
+	encrypted, err := encryptor.Encrypt(sample)
+	if err != nil {
+		p.fail("encryption", "Failed to encrypt sample: %v", err)
+		return nil
+	}
+	decrypted, err := encryptor.Decrypt(encrypted)
+	if err != nil || decrypted != sample {
+		p.fail("encryption", "Sample did not decrypt back to its original value")
+		return nil
+	}
+
+	p.pass("encryption", "Key is valid")
+	return encryptor
+}
+
+// checkDatabase connects to the configured database and pings it
+func (p *preflight) checkDatabase() *database.DB {
+	cfg := dbConfigFromEnv()
+	db, err := database.New(cfg)
+	if err != nil {
+		p.fail("database", "Failed to connect: %v", err)
+		return nil
+	}
+
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		p.fail("database", "Failed to get database handle: %v", err)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		p.fail("database", "Ping failed: %v", err)
+		return nil
+	}
+
+	p.pass("database", "Connected to %s at %s:%d/%s", cfg.Driver, cfg.Host, cfg.Port, cfg.DBName)
+	return db
+}
+
+// checkStoredSecrets decrypts a stored credential to prove ENCRYPTION_KEY matches the data
+func (p *preflight) checkStoredSecrets(db *database.DB, encryptor *encryption.Encryptor) {
+	if db == nil || encryptor == nil {
+		p.skip("stored secrets", "Requires database and encryption checks to pass")
+		return
+	}
+
+	var sample, source string
+	if db.Migrator().HasTable(&models.Cluster{}) {
+		var cluster models.Cluster
+		if err := db.Where("kubeconfig != ?", "").First(&cluster).Error; err == nil {
+			sample, source = cluster.KubeConfig, "cluster "+cluster.ID
+		}
+	}
+	if sample == "" && db.Migrator().HasTable(&models.AzureSubscription{}) {
+		var sub models.AzureSubscription
+		if err := db.First(&sub).Error; err == nil {
+			sample, source = sub.Credentials, "Azure subscription "+sub.ID
+		}
+	}
+	if sample == "" && db.Migrator().HasTable(&models.RegistryCredential{}) {
+		var credential models.RegistryCredential
+		if err := db.First(&credential).Error; err == nil {
+			sample, source = credential.Credentials, "registry credential "+credential.Name
+		}
+	}
+	if sample == "" {
+		p.skip("stored secrets", "No encrypted data stored yet")
+		return
+	}
+
+	if _, err := encryptor.Decrypt(sample); err != nil {
+		p.fail("stored secrets", "Failed to decrypt %s: ENCRYPTION_KEY does not match stored data", source)
+		return
+	}
+	p.pass("stored secrets", "Decrypted %s", source)
+}
+
+// checkOAuth validates the OAuth configuration and that the provider is reachable
+func (p *preflight) checkOAuth() {
+	if getEnv("OAUTH_ENABLED", "false") != "true" {
+		p.skip("oauth", "OAuth is disabled")
+		return
+	}
+
+	cfg := oauthConfigFromEnv()
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		p.fail("oauth", "OAUTH_CLIENT_ID and OAUTH_CLIENT_SECRET are required")
+		return
+	}
+	provider, err := auth.NewOAuthProvider(cfg)
+	if err != nil {
+		p.fail("oauth", "Invalid configuration: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+	if err := provider.CheckReachable(ctx); err != nil {
+		p.fail("oauth", "Provider unreachable: %v", err)
+		return
+	}
+	p.pass("oauth", "%s provider is reachable", cfg.Provider)
+}
+
+// checkClusters loads the registered clusters and requires at least one to connect
+func (p *preflight) checkClusters(db *database.DB, encryptor *encryption.Encryptor) {
+	if db == nil || encryptor == nil {
+		p.skip("clusters", "Requires database and encryption checks to pass")
+		return
+	}
+	k8sClient := k8s.NewClient()
+
+	var clusters []models.Cluster
+	if db.Migrator().HasTable(&models.Cluster{}) {
+		if err := db.Find(&clusters).Error; err != nil {
+			p.fail("clusters", "Failed to load clusters: %v", err)
+			return
+		}
+	}
+
+	// The in-cluster configuration is registered at startup, so it may not be stored yet
+	if len(clusters) == 0 && getEnv("SCRAPE_IN_CLUSTER", "false") == "true" {
+		clusters = append(clusters, models.Cluster{ID: "in-cluster", Name: getEnv("IN_CLUSTER_NAME", "in-cluster")})
+	}
+	if len(clusters) == 0 {
+		p.fail("clusters", "No clusters registered")
+		return
+	}
+
+	var connected int
+	var lastErr error
+	for _, cluster := range clusters {
+		if err := addPreflightCluster(k8sClient, encryptor, cluster); err != nil {
+			lastErr = fmt.Errorf("%s: %w", cluster.Name, err)
+			continue
+		}
+		if _, err := k8sClient.CheckClusterHealth(cluster.ID); err != nil {
+			lastErr = fmt.Errorf("%s: %w", cluster.Name, err)
+			continue
+		}
+		connected++
+	}
+
+	if connected == 0 {
+		p.fail("clusters", "None of %d clusters connected (last error: %v)", len(clusters), lastErr)
+		return
+	}
+	p.pass("clusters", "%d of %d clusters connected", connected, len(clusters))
+}
+
+// addPreflightCluster registers a cluster with the client, using the in-cluster
+// configuration when no kubeconfig is stored
+func addPreflightCluster(k8sClient k8s.ClusterClient, encryptor *encryption.Encryptor, cluster models.Cluster) error {
+	if cluster.KubeConfig == "" {
+		return k8sClient.AddInClusterConfig(cluster.ID)
+	}
+
+	kubeconfig, err := encryptor.Decrypt(cluster.KubeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt kubeconfig: %w", err)
+	}
+	return k8sClient.AddCluster(cluster.ID, kubeconfig)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/webhooks"
+	"go.uber.org/zap"
+)
+
+// applyFluxEvent stores a change streamed by a cluster watch so the flux_resources table
+// is current between periodic syncs. Events for clusters that are not stored or are
+// archived are dropped; the periodic sync still catches anything missed.
+func applyFluxEvent(db *database.DB, notifier *webhooks.Notifier, event k8s.FluxEvent) {
+	res := event.Resource
+	logger := logging.GetLogger().Named("flux-watch").With(
+		zap.String("cluster_id", res.ClusterID),
+		zap.String("resource", res.Kind+"/"+res.Namespace+"/"+res.Name),
+	)
+
+	cluster, err := repository.NewClusterRepository(db).Get(res.ClusterID)
+	if err != nil || cluster.Archived {
+		return
+	}
+
+	repo := repository.NewResourceRepository(db)
+	if event.Type == k8s.FluxResourceDeleted {
+		if err := repo.DeleteByKey(res.ClusterID, res.Kind, res.Namespace, res.Name); err != nil {
+			logger.Warn("Failed to delete resource", zap.Error(err))
+		}
+		return
+	}
+
+	stored, err := repo.AssignID(&res)
+	if err != nil {
+		logger.Warn("Failed to resolve resource ID", zap.Error(err))
+		return
+	}
+
+	if err := history.RecordTransitions(db, res.ClusterID, []models.FluxResource{res}); err != nil {
+		logger.Warn("Failed to record status transition", zap.Error(err))
+	}
+	if err := history.RecordDeployments(db, res.ClusterID, []models.FluxResource{res}); err != nil {
+		logger.Warn("Failed to record deployment", zap.Error(err))
+	}
+	if err := repo.Save(&res); err != nil {
+		logger.Error("Failed to save resource", zap.Error(err))
+		return
+	}
+
+	// Readiness changes are what a user waits for after a push or a reconcile
+	if stored == nil || stored.Status == res.Status {
+		return
+	}
+	switch {
+	case res.Status == "NotReady":
+		notifier.NotifyReconciliationFailed(res.ClusterID, res.Kind, res.Namespace, res.Name, res.Message)
+	case res.Status == "Ready" && stored.Status == "NotReady":
+		notifier.NotifyResourceDeployed(res.ClusterID, res.Kind, res.Namespace, res.Name)
+	}
+}
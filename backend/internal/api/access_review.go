@@ -0,0 +1,37 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// reviewClusterAccess reports whether the cluster allows an action, given as verb and
+// resource with optional group, subresource, namespace and name query parameters, to the
+// stored credentials, or to the requesting user when the cluster impersonates users. The UI
+// uses it to disable actions the cluster would refuse.
+func (s *Server) reviewClusterAccess(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	req := k8s.AccessReviewRequest{
+		Verb:        query.Get("verb"),
+		Group:       query.Get("group"),
+		Resource:    query.Get("resource"),
+		Subresource: query.Get("subresource"),
+		Namespace:   query.Get("namespace"),
+		Name:        query.Get("name"),
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	review, err := s.k8sClient.ReviewAccess(s.impersonationContext(r.Context(), r), clusterID, req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to review access: %v", err))
+		return
+	}
+	respondJSON(w, http.StatusOK, review)
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Expensive actions limited per user and hour
+const (
+	quotaTreeBuilds   = "tree_builds"
+	quotaSyncs        = "syncs"
+	quotaExports      = "exports"
+	quotaDeviceLogins = "device_logins" // unauthenticated, so counted per client address
+)
+
+// quotaWindow is the sliding window quotas are counted over
+const quotaWindow = time.Hour
+
+// quotaDefaults are the hourly quotas of each action and the variables overriding them;
+// 0 lifts a quota
+var quotaDefaults = map[string]struct {
+	envKey string
+	limit  int
+}{
+	quotaTreeBuilds:   {"ACTION_QUOTA_TREE_BUILDS", 60},
+	quotaSyncs:        {"ACTION_QUOTA_SYNCS", 30},
+	quotaExports:      {"ACTION_QUOTA_EXPORTS", 20},
+	quotaDeviceLogins: {"ACTION_QUOTA_DEVICE_LOGINS", 10},
+}
+
+// quotaRoutes maps the method and route template of expensive requests to their action
+var quotaRoutes = map[string]string{
+	"GET /api/v1/clusters/{id}/resources/tree": quotaTreeBuilds, // only with refresh=true, see quotaAction
+	"POST /api/v1/clusters/{id}/sync":          quotaSyncs,
+	"GET /api/v1/clusters/{id}/export":         quotaExports,
+	"GET /api/v1/resources/export":             quotaExports,
+	"POST /api/v1/auth/device/start":           quotaDeviceLogins,
+}
+
+// actionQuotas counts the expensive actions of each user over a sliding hour. Counts are
+// kept in memory, so every replica enforces its quotas separately.
+type actionQuotas struct {
+	limits         map[string]int
+	trustedProxies cidrList
+
+	mu        sync.Mutex
+	used      map[string][]time.Time // by subject and action, oldest first
+	lastSweep time.Time
+}
+
+// QuotaUsage is how much of an hourly quota a user has used
+type QuotaUsage struct {
+	Action    string     `json:"action"`
+	Limit     int        `json:"limit"`
+	Used      int        `json:"used"`
+	Remaining int        `json:"remaining"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"` // when the oldest counted action leaves the window
+}
+
+// actionQuotasFromEnv reads the hourly quotas from the ACTION_QUOTA_* variables
+func actionQuotasFromEnv() *actionQuotas {
+	limits := make(map[string]int, len(quotaDefaults))
+	for action, quota := range quotaDefaults {
+		limits[action] = quota.limit
+		if value := os.Getenv(quota.envKey); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				log.Printf("Warning: Invalid %s %q, using %d", quota.envKey, value, quota.limit)
+				continue
+			}
+			limits[action] = parsed
+		}
+	}
+	return &actionQuotas{
+		limits:         limits,
+		trustedProxies: parseCIDRs("TRUSTED_PROXY_CIDRS", os.Getenv("TRUSTED_PROXY_CIDRS")),
+		used:           make(map[string][]time.Time),
+	}
+}
+
+// quotaAction returns the expensive action a request performs, or empty. Tree requests
+// only count when they rebuild the tree; cached trees are cheap to serve.
+func quotaAction(r *http.Request) string {
+	action := quotaRoutes[r.Method+" "+routeTemplate(r)]
+	if action == quotaTreeBuilds && r.URL.Query().Get("refresh") != "true" {
+		return ""
+	}
+	return action
+}
+
+// subject returns whom a request counts against: its user, or its client address for
+// anonymous requests
+func (q *actionQuotas) subject(r *http.Request) string {
+	if email := requestEmail(r); email != "" {
+		return "user:" + email
+	}
+	return "ip:" + clientIP(r, q.trustedProxies).String()
+}
+
+// take counts an action of a subject when it is within its quota. Otherwise it reports how
+// long until the oldest counted action leaves the window.
+func (q *actionQuotas) take(subject, action string, now time.Time) (bool, int, time.Duration) {
+	limit := q.limits[action]
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.sweep(now)
+	key := subject + "\x00" + action
+	used := q.prune(key, now)
+	if len(used) >= limit {
+		return false, 0, used[0].Add(quotaWindow).Sub(now)
+	}
+	q.used[key] = append(used, now)
+	return true, limit - len(used) - 1, 0
+}
+
+// prune drops the actions of a key that left the window, returning those left
+func (q *actionQuotas) prune(key string, now time.Time) []time.Time {
+	used := q.used[key]
+	cutoff := now.Add(-quotaWindow)
+	i := sort.Search(len(used), func(i int) bool { return used[i].After(cutoff) })
+	used = used[i:]
+	if len(used) == 0 {
+		delete(q.used, key)
+	} else {
+		q.used[key] = used
+	}
+	return used
+}
+
+// sweep prunes every key once per window, so subjects that went quiet are forgotten
+func (q *actionQuotas) sweep(now time.Time) {
+	if now.Sub(q.lastSweep) < quotaWindow {
+		return
+	}
+	q.lastSweep = now
+	for key := range q.used {
+		q.prune(key, now)
+	}
+}
+
+// usage reports the quotas of a subject, by action
+func (q *actionQuotas) usage(subject string, now time.Time) []QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := make([]QuotaUsage, 0, len(q.limits))
+	for action, limit := range q.limits {
+		used := q.prune(subject+"\x00"+action, now)
+		entry := QuotaUsage{Action: action, Limit: limit, Used: len(used)}
+		if limit > 0 {
+			entry.Remaining = max(limit-len(used), 0)
+		}
+		if len(used) > 0 {
+			resetsAt := used[0].Add(quotaWindow)
+			entry.ResetsAt = &resetsAt
+		}
+		usage = append(usage, entry)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Action < usage[j].Action })
+	return usage
+}
+
+// actionQuotaMiddleware answers 429 to expensive requests of users who used up their hourly
+// quota of the action, and reports the quota in X-Quota-Limit and X-Quota-Remaining
+func (s *Server) actionQuotaMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		action := quotaAction(r)
+		limit := s.quotas.limits[action]
+		if action == "" || limit == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		subject := s.quotas.subject(r)
+		allowed, remaining, retryAfter := s.quotas.take(subject, action, time.Now())
+		w.Header().Set("X-Quota-Limit", strconv.Itoa(limit))
+		w.Header().Set("X-Quota-Remaining", strconv.Itoa(remaining))
+		if !allowed {
+			seconds := max(int(retryAfter.Round(time.Second)/time.Second), 1)
+			log.Printf("Warning: %s exceeded the %s quota of %d per hour", subject, action, limit)
+			setRetryAfter(w, seconds)
+			respondJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+				"error":       fmt.Sprintf("Hourly quota of %d %s used up; retry in %s", limit, action, time.Duration(seconds)*time.Second),
+				"action":      action,
+				"limit":       limit,
+				"retry_after": seconds,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getMyQuotas reports the requesting user's hourly quotas of expensive actions; a limit of
+// 0 means the action is not limited
+func (s *Server) getMyQuotas(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"window_seconds": int(quotaWindow / time.Second),
+		"quotas":         s.quotas.usage(s.quotas.subject(r), time.Now()),
+	})
+}
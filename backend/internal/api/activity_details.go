@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// activityChange is one field an action changed. Old is omitted when it is unknown and
+// New when the field was removed.
+type activityChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// activityChangeSet is stored as the details of activities that change something, so the
+// activity view can show what changed and with which parameters instead of a message
+type activityChangeSet struct {
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Changes    []activityChange       `json:"changes,omitempty"`
+}
+
+// change records a field going from old to new, unless both are equal
+func (c *activityChangeSet) change(field string, old, new interface{}) {
+	if old != nil && reflect.DeepEqual(old, new) {
+		return
+	}
+	c.Changes = append(c.Changes, activityChange{Field: field, Old: old, New: new})
+}
+
+// param records a parameter of the action
+func (c *activityChangeSet) param(name string, value interface{}) {
+	if c.Parameters == nil {
+		c.Parameters = make(map[string]interface{})
+	}
+	c.Parameters[name] = value
+}
+
+// storedSuspend returns spec.suspend of a Flux resource as last synced, or nil when it is
+// not known
+func (s *Server) storedSuspend(clusterID, kind, namespace, name string) interface{} {
+	resource, err := s.resourceService.GetByKey(clusterID, kind, namespace, name)
+	if err != nil {
+		return nil
+	}
+	var object struct {
+		Spec struct {
+			Suspend bool `json:"suspend"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(resource.Metadata), &object); err != nil {
+		return nil
+	}
+	return object.Spec.Suspend
+}
+
+// logActivityDetails records an activity like logActivity, with details stored as JSON
+func (s *Server) logActivityDetails(actor, action, resourceType, resourceID, resourceName, clusterID, clusterName, status, message string, details interface{}) {
+	s.recordActivity(actor, &models.Activity{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ResourceName: resourceName,
+		ClusterID:    clusterID,
+		ClusterName:  clusterName,
+		Status:       status,
+		Message:      message,
+		Details:      encodeActivityDetails(details),
+	})
+}
+
+// recordActivity stores an activity performed by actor, as returned by requestActor,
+// reporting whether it was stored
+func (s *Server) recordActivity(actor string, activity *models.Activity) bool {
+	activity.UserID = actor
+	if err := s.activities.Create(activity); err != nil {
+		log.Printf("Warning: Failed to log activity: %v", err)
+		return false
+	}
+	return true
+}
+
+// encodeActivityDetails returns details as JSON, or an empty string when there are none
+func encodeActivityDetails(details interface{}) string {
+	if details == nil {
+		return ""
+	}
+	if changes, ok := details.(*activityChangeSet); ok && (changes == nil || (len(changes.Parameters) == 0 && len(changes.Changes) == 0)) {
+		return ""
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		log.Printf("Warning: Failed to encode activity details: %v", err)
+		return ""
+	}
+	return string(data)
+}
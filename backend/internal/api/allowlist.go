@@ -0,0 +1,195 @@
+package api
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"go.uber.org/zap"
+)
+
+// Route groups that can be restricted to client networks
+const (
+	routeGroupAuth  = "auth"
+	routeGroupAdmin = "admin"
+	routeGroupRead  = "read"
+)
+
+// allowlistEnv maps route groups to the environment variable holding their CIDRs
+var allowlistEnv = map[string]string{
+	routeGroupAuth:  "ALLOWED_CIDRS_AUTH",
+	routeGroupAdmin: "ALLOWED_CIDRS_ADMIN",
+	routeGroupRead:  "ALLOWED_CIDRS_READ",
+}
+
+// cidrList is a set of networks; a nil list allows every address
+type cidrList []*net.IPNet
+
+// contains reports whether ip is inside any network in the list
+func (l cidrList) contains(ip net.IP) bool {
+	for _, network := range l {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCIDRs parses a comma-separated list of CIDRs or bare IPs.
+// Invalid entries are skipped with a warning; a non-empty value always yields
+// a non-nil list so a fully invalid setting denies rather than allows.
+func parseCIDRs(envKey, value string) cidrList {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	list := cidrList{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 32
+				if ip.To4() == nil {
+					bits = 128
+				}
+				entry = ip.String() + "/" + strconv.Itoa(bits)
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("Warning: Ignoring invalid CIDR %q in %s: %v", entry, envKey, err)
+			continue
+		}
+		list = append(list, network)
+	}
+	return list
+}
+
+// adminGetRoutes are GET routes that change cluster state, such as WebSocket upgrades
+// opening an interactive shell. They belong to the admin group despite their method.
+var adminGetRoutes = []string{
+	"/clusters/{id}/pods/{namespace}/{name}/exec",
+}
+
+// adminGetPaths match the paths of adminGetRoutes, for requests forwarded from /api/v2
+// whose v1 route is only resolved by their handler
+var adminGetPaths = func() []*regexp.Regexp {
+	variable := regexp.MustCompile(`\{[^}]+\}`)
+	paths := make([]*regexp.Regexp, 0, len(adminGetRoutes))
+	for _, route := range adminGetRoutes {
+		pattern := variable.ReplaceAllString(route, `[^/]+`)
+		paths = append(paths, regexp.MustCompile(`^/api/v[12]`+pattern+`$`))
+	}
+	return paths
+}()
+
+// isAdminGetRoute reports whether a GET request matched one of adminGetRoutes
+func isAdminGetRoute(r *http.Request) bool {
+	template := routeTemplate(r)
+	for _, route := range adminGetRoutes {
+		if strings.HasSuffix(template, route) {
+			return true
+		}
+	}
+	for _, path := range adminGetPaths {
+		if path.MatchString(r.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeGroup classifies a request for allowlisting by its route; non-API paths have no
+// group. Routes are read or admin by method, except for adminGetRoutes.
+func routeGroup(r *http.Request) string {
+	path := r.URL.Path
+	if !strings.HasPrefix(path, "/api/") {
+		return ""
+	}
+	if strings.HasPrefix(path, "/api/v1/auth/") || strings.HasPrefix(path, "/api/v2/auth/") {
+		return routeGroupAuth
+	}
+	if r.Method == http.MethodGet && isAdminGetRoute(r) {
+		return routeGroupAdmin
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return routeGroupRead
+	default:
+		return routeGroupAdmin
+	}
+}
+
+// clientIP returns the originating client address. X-Forwarded-For is only
+// trusted when the direct peer is a trusted proxy, and is walked from the
+// right so that a client cannot spoof its address by prepending entries.
+func clientIP(r *http.Request, trustedProxies cidrList) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !trustedProxies.contains(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if hop == nil {
+			break
+		}
+		ip = hop
+		if !trustedProxies.contains(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// ipAllowlistMiddleware restricts route groups to the configured client networks.
+// Groups without a configured allowlist are open to every address.
+func ipAllowlistMiddleware(next http.Handler) http.Handler {
+	allowlists := make(map[string]cidrList)
+	for group, envKey := range allowlistEnv {
+		if list := parseCIDRs(envKey, os.Getenv(envKey)); list != nil {
+			allowlists[group] = list
+		}
+	}
+	trustedProxies := parseCIDRs("TRUSTED_PROXY_CIDRS", os.Getenv("TRUSTED_PROXY_CIDRS"))
+
+	if len(allowlists) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		group := routeGroup(r)
+		allowed, restricted := allowlists[group]
+		if !restricted {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := clientIP(r, trustedProxies)
+		if ip == nil || !allowed.contains(ip) {
+			logging.GetLogger().Warn("Request blocked by IP allowlist",
+				zap.String("group", group),
+				zap.String("client_ip", ip.String()),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+			)
+			respondError(w, http.StatusForbidden, "Access from this network is not allowed")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
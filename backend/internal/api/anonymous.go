@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// anonymousReadOnlySetting enables unauthenticated read access to anonymousReadRoutes
+const anonymousReadOnlySetting = "anonymous_read_only"
+
+// anonymousReadRoutes are the route templates served without login when
+// anonymous read-only mode is on. Logs, manifests, diffs, exports and
+// credential-bearing endpoints are deliberately left out.
+var anonymousReadRoutes = map[string]bool{
+	"/api/v1/clusters":                                     true,
+	"/api/v1/clusters/{id}":                                true,
+	"/api/v1/clusters/{id}/health":                         true,
+	"/api/v1/clusters/{id}/resources":                      true,
+	"/api/v1/clusters/{id}/resources/tree":                 true,
+	"/api/v1/clusters/{id}/flux/stats":                     true,
+	"/api/v1/clusters/{id}/flux/health":                    true,
+	"/api/v1/clusters/{id}/flux/version":                   true,
+	"/api/v1/clusters/{id}/flux/order":                     true,
+	"/api/v1/clusters/{id}/trends":                         true,
+	"/api/v1/clusters/{id}/flux/{kind}/{namespace}/{name}": true,
+	"/api/v1/resources":                                    true,
+	"/api/v1/resources/{id}":                               true,
+	"/api/v1/timeline":                                     true,
+	"/api/v2/clusters":                                     true,
+	"/api/v2/clusters/{id}":                                true,
+	"/api/v2/clusters/{id}/resources":                      true,
+	"/api/v2/resources":                                    true,
+	"/api/v2/resources/{id:.+}":                            true,
+}
+
+// anonymousReadOnlyEnabled reports whether the anonymous_read_only setting is on
+func (s *Server) anonymousReadOnlyEnabled() bool {
+	var setting models.Setting
+	if err := s.db.Where("setting_key = ?", anonymousReadOnlySetting).First(&setting).Error; err != nil {
+		return false
+	}
+	return setting.Value == "true"
+}
+
+// allowAnonymousRead reports whether an unauthenticated request may proceed
+func (s *Server) allowAnonymousRead(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if !anonymousReadRoutes[routeTemplate(r)] {
+		return false
+	}
+	return s.anonymousReadOnlyEnabled()
+}
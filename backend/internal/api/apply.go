@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// manifestApplyPermission is required to apply raw manifests to a cluster
+const manifestApplyPermission = "manifest.apply"
+
+// applyManifests server-side applies the YAML documents in the request body to a cluster,
+// for example to bootstrap Flux resources. Query parameters: namespace for objects that set
+// none, dry_run and force to take over fields owned by other managers. Requires the
+// manifest.apply permission; every object created or changed is recorded in the activity log.
+func (s *Server) applyManifests(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+	clusterName := s.clusterService.Name(clusterID)
+	query := r.URL.Query()
+	opts := k8s.ApplyOptions{
+		Namespace: query.Get("namespace"),
+		DryRun:    query.Get("dry_run") == "true",
+		Force:     query.Get("force") == "true",
+	}
+
+	if err := s.requirePermission(r, manifestApplyPermission); err != nil {
+		s.logActivity(requestActor(r), "apply", "Manifest", clusterID, clusterName, clusterID, clusterName, "failed", fmt.Sprintf("Denied apply: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Apply not allowed: %v", err))
+		return
+	}
+
+	manifests, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit))
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := s.k8sClient.ApplyManifests(s.provenanceContext(r, "apply"), clusterID, manifests, opts)
+	if err != nil {
+		var invalid *k8s.InvalidManifestError
+		if errors.As(err, &invalid) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid manifests: %v", err))
+			return
+		}
+		s.logActivity(requestActor(r), "apply", "Manifest", clusterID, clusterName, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to apply manifests: %v", err))
+		return
+	}
+
+	if !opts.DryRun {
+		for _, obj := range result.Objects {
+			resourceID := obj.Name
+			if obj.Namespace != "" {
+				resourceID = fmt.Sprintf("%s/%s", obj.Namespace, obj.Name)
+			}
+			switch obj.Action {
+			case "created", "configured":
+				s.logActivity(requestActor(r), "apply", obj.Kind, resourceID, obj.Name, clusterID, clusterName, "success",
+					fmt.Sprintf("Applied %s %s (%s)", obj.Kind, resourceID, obj.Action))
+			case "error":
+				s.logActivity(requestActor(r), "apply", obj.Kind, resourceID, obj.Name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %s", obj.Error))
+			}
+		}
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Authenticator identifies the user behind one kind of request credential, such as a session
+// cookie or a bearer token. authMiddleware tries the authenticators of a server in order and
+// the first to recognize a credential decides the request.
+type Authenticator interface {
+	// Name identifies the mechanism in logs
+	Name() string
+	// Authenticate returns the user a request's credential belongs to. It returns
+	// ErrNoCredential when the request carries no credential of its kind; any other error
+	// rejects the request with its message.
+	Authenticate(r *http.Request) (*auth.UserInfo, error)
+}
+
+// ErrNoCredential is returned by an Authenticator to pass a request on to the next one
+var ErrNoCredential = errors.New("no credential")
+
+// errUserDisabled rejects the credentials of users an administrator disabled
+var errUserDisabled = errors.New("User is disabled")
+
+// AddAuthenticator appends an authentication mechanism to the chain, after the built-in ones.
+// It must be called before the server starts serving.
+func (s *Server) AddAuthenticator(authenticator Authenticator) {
+	s.authenticators = append(s.authenticators, authenticator)
+}
+
+// defaultAuthenticators builds the built-in chain: trusted proxy headers when configured,
+// session cookie, API token, then JWT and mTLS client certificates when configured
+func (s *Server) defaultAuthenticators() []Authenticator {
+	var authenticators []Authenticator
+	if s.proxyAuth != nil {
+		// The proxy has already authenticated the user; its identity outranks a stale cookie
+		authenticators = append(authenticators, proxyAuthenticator{s, s.proxyAuth})
+	}
+	authenticators = append(authenticators, sessionAuthenticator{s}, apiTokenAuthenticator{s})
+	if jwtAuth, err := jwtAuthenticatorFromEnv(); err != nil {
+		log.Printf("Warning: JWT authentication disabled: %v", err)
+	} else if jwtAuth != nil {
+		authenticators = append(authenticators, jwtAuth)
+	}
+	if os.Getenv("TLS_CLIENT_CA_FILE") != "" {
+		authenticators = append(authenticators, clientCertAuthenticator{})
+	}
+	return authenticators
+}
+
+// authenticate runs the authenticator chain, returning ErrNoCredential when no authenticator
+// recognized a credential. Credentials of disabled users are rejected whichever
+// authenticator recognized them.
+func (s *Server) authenticate(r *http.Request) (*auth.UserInfo, error) {
+	for _, authenticator := range s.authenticators {
+		user, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrNoCredential) {
+			continue
+		}
+		if err == nil {
+			err = s.checkUserEnabled(user)
+		}
+		if err != nil {
+			log.Printf("Warning: %s authentication failed for %s: %v", authenticator.Name(), r.RemoteAddr, err)
+			return nil, err
+		}
+		return user, nil
+	}
+	return nil, ErrNoCredential
+}
+
+// checkUserEnabled returns errUserDisabled for users whose user record is disabled
+func (s *Server) checkUserEnabled(user *auth.UserInfo) error {
+	disabled, err := s.rbacManager.UserDisabled(user.Email)
+	if err != nil {
+		return fmt.Errorf("failed to load user %s: %w", user.Email, err)
+	}
+	if disabled {
+		return errUserDisabled
+	}
+	return nil
+}
+
+// sessionAuthenticator accepts the session cookie set by the OAuth login. Cookies of expired
+// sessions are ignored so other credentials, or anonymous read access, still apply.
+type sessionAuthenticator struct {
+	s *Server
+}
+
+func (a sessionAuthenticator) Name() string { return "session" }
+
+func (a sessionAuthenticator) Authenticate(r *http.Request) (*auth.UserInfo, error) {
+	token, err := a.s.readCookie(r, sessionCookieName)
+	if err != nil {
+		return nil, ErrNoCredential
+	}
+	session, exists := a.s.sessionStore.Get(token)
+	if !exists {
+		return nil, ErrNoCredential
+	}
+	return session.UserInfo, nil
+}
+
+// apiTokenAuthenticator accepts bearer API tokens from the device login flow. Bearer tokens
+// shaped like a JWT are left to the JWT authenticator.
+type apiTokenAuthenticator struct {
+	s *Server
+}
+
+func (a apiTokenAuthenticator) Name() string { return "api token" }
+
+func (a apiTokenAuthenticator) Authenticate(r *http.Request) (*auth.UserInfo, error) {
+	bearer := bearerToken(r)
+	if bearer == "" || (!strings.HasPrefix(bearer, auth.APITokenPrefix) && strings.Count(bearer, ".") == 2) {
+		return nil, ErrNoCredential
+	}
+	user, ok := a.s.authenticateAPIToken(bearer)
+	if !ok {
+		return nil, errors.New("Invalid or expired API token")
+	}
+	return user, nil
+}
+
+// jwtAuthenticator accepts bearer JWTs issued by an external identity provider, signed with a
+// shared secret or the private key of a configured public key
+type jwtAuthenticator struct {
+	key        interface{}
+	parser     *jwt.Parser
+	emailClaim string
+}
+
+// jwtAuthenticatorFromEnv configures JWT authentication from JWT_SECRET (HMAC) or
+// JWT_PUBLIC_KEY_FILE (RSA or ECDSA PEM), with optional JWT_ISSUER, JWT_AUDIENCE and
+// JWT_EMAIL_CLAIM (default email). It returns nil when neither key is set.
+func jwtAuthenticatorFromEnv() (*jwtAuthenticator, error) {
+	secret, keyFile := os.Getenv("JWT_SECRET"), os.Getenv("JWT_PUBLIC_KEY_FILE")
+	if secret == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	a := &jwtAuthenticator{emailClaim: os.Getenv("JWT_EMAIL_CLAIM")}
+	if a.emailClaim == "" {
+		a.emailClaim = "email"
+	}
+	var methods []string
+	switch {
+	case secret != "":
+		a.key = []byte(secret)
+		methods = []string{"HS256", "HS384", "HS512"}
+	default:
+		pemData, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT_PUBLIC_KEY_FILE: %w", err)
+		}
+		if key, err := jwt.ParseRSAPublicKeyFromPEM(pemData); err == nil {
+			a.key = key
+			methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+		} else if key, err := jwt.ParseECPublicKeyFromPEM(pemData); err == nil {
+			a.key = key
+			methods = []string{"ES256", "ES384", "ES512"}
+		} else {
+			return nil, fmt.Errorf("JWT_PUBLIC_KEY_FILE holds no RSA or ECDSA public key")
+		}
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+	a.parser = jwt.NewParser(options...)
+	return a, nil
+}
+
+func (a *jwtAuthenticator) Name() string { return "jwt" }
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (*auth.UserInfo, error) {
+	bearer := bearerToken(r)
+	if bearer == "" || strings.HasPrefix(bearer, auth.APITokenPrefix) {
+		return nil, ErrNoCredential
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(bearer, claims, func(*jwt.Token) (interface{}, error) { return a.key, nil }); err != nil {
+		return nil, fmt.Errorf("Invalid JWT: %v", err)
+	}
+
+	subject, _ := claims.GetSubject()
+	email, _ := claims[a.emailClaim].(string)
+	if email == "" {
+		return nil, fmt.Errorf("Invalid JWT: no %s claim", a.emailClaim)
+	}
+	name, _ := claims["name"].(string)
+	username, _ := claims["preferred_username"].(string)
+	if username == "" {
+		username = subject
+	}
+	return &auth.UserInfo{ID: subject, Email: email, Name: name, Username: username, Provider: "jwt"}, nil
+}
+
+// clientCertAuthenticator accepts TLS client certificates verified against TLS_CLIENT_CA_FILE
+// when the server terminates TLS itself. The user is the certificate's first email address,
+// or else its common name.
+type clientCertAuthenticator struct{}
+
+func (clientCertAuthenticator) Name() string { return "mtls" }
+
+func (clientCertAuthenticator) Authenticate(r *http.Request) (*auth.UserInfo, error) {
+	// Only chains the TLS handshake verified; unverified certificates never get this far
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, ErrNoCredential
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+
+	email := cert.Subject.CommonName
+	if len(cert.EmailAddresses) > 0 {
+		email = cert.EmailAddresses[0]
+	}
+	return &auth.UserInfo{
+		ID:       cert.Subject.CommonName,
+		Email:    email,
+		Name:     cert.Subject.CommonName,
+		Username: cert.Subject.CommonName,
+		Provider: "mtls",
+	}, nil
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// rootPaths stay reachable without the base path so probes and scrapers
+// configured against the pod do not need to know about the proxy prefix
+var rootPaths = map[string]bool{
+	"/health":  true,
+	"/healthz": true,
+	"/readyz":  true,
+	"/livez":   true,
+	"/metrics": true,
+}
+
+// basePathFromEnv returns BASE_PATH normalized to "/prefix" form, or "" when unset
+func basePathFromEnv() string {
+	basePath := strings.Trim(strings.TrimSpace(os.Getenv("BASE_PATH")), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// appPath prefixes an absolute application path with the base path
+func (s *Server) appPath(path string) string {
+	return s.basePath + path
+}
+
+// stripBasePath routes requests under the base path to the router with the
+// prefix removed, so route templates, metrics labels and allowlists stay unchanged
+func (s *Server) stripBasePath(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case path == s.basePath:
+		http.Redirect(w, r, s.basePath+"/", http.StatusMovedPermanently)
+		return
+	case strings.HasPrefix(path, s.basePath+"/"):
+		stripped := r.Clone(r.Context())
+		stripped.URL.Path = strings.TrimPrefix(path, s.basePath)
+		if r.URL.RawPath != "" {
+			stripped.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, s.basePath)
+		}
+		s.router.ServeHTTP(w, stripped)
+	case rootPaths[path]:
+		s.router.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
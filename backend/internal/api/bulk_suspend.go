@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/bulk"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// bulkSuspendRequest is the body of a cluster-wide suspend or resume
+type bulkSuspendRequest struct {
+	k8s.SuspendScope
+	DryRun bool `json:"dry_run"`
+}
+
+// bulkActionRequest is the body of a bulk action. The selector takes kind as a shorthand
+// for a single entry of kinds.
+type bulkActionRequest struct {
+	Action   string `json:"action"`
+	Selector struct {
+		Kind string `json:"kind,omitempty"`
+		k8s.SuspendScope
+	} `json:"selector"`
+	DryRun bool `json:"dry_run"`
+}
+
+// bulkActionLog words each action for the activity log: its past tense, and how resources
+// it skipped are described
+var bulkActionLog = map[string]struct{ past, skipped string }{
+	bulk.ActionSuspend:   {"Suspended", "already suspended"},
+	bulk.ActionResume:    {"Resumed", "already resumed"},
+	bulk.ActionReconcile: {"Requested reconcile of", "suspended"},
+}
+
+// bulkActionPermissions are the permissions each bulk action requires, as for the same
+// action on a single resource
+var bulkActionPermissions = map[string]string{
+	bulk.ActionSuspend:   "resource.suspend",
+	bulk.ActionResume:    "resource.resume",
+	bulk.ActionReconcile: "resource.reconcile",
+}
+
+// suspendAllFluxResources suspends every Kustomization and HelmRelease in a cluster, or
+// those in a namespace or matching a label selector, as a background job
+func (s *Server) suspendAllFluxResources(w http.ResponseWriter, r *http.Request) {
+	s.startBulkSuspend(w, r, bulk.ActionSuspend)
+}
+
+// resumeAllFluxResources resumes every Kustomization and HelmRelease in a scope
+func (s *Server) resumeAllFluxResources(w http.ResponseWriter, r *http.Request) {
+	s.startBulkSuspend(w, r, bulk.ActionResume)
+}
+
+// bulkFluxAction suspends, resumes or reconciles the Flux resources a selector matches, for
+// example every HelmRelease in a namespace, as a background job
+func (s *Server) bulkFluxAction(w http.ResponseWriter, r *http.Request) {
+	var req bulkActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !bulk.IsAction(req.Action) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("action must be %s, %s or %s", bulk.ActionSuspend, bulk.ActionResume, bulk.ActionReconcile))
+		return
+	}
+	scope := req.Selector.SuspendScope
+	if req.Selector.Kind != "" {
+		if len(scope.Kinds) > 0 {
+			respondError(w, http.StatusBadRequest, "selector takes kind or kinds, not both")
+			return
+		}
+		scope.Kinds = []string{req.Selector.Kind}
+	}
+	s.startBulkJob(w, r, req.Action, scope, req.DryRun)
+}
+
+// startBulkSuspend starts a suspend or resume job from a bulkSuspendRequest body
+func (s *Server) startBulkSuspend(w http.ResponseWriter, r *http.Request, action string) {
+	var req bulkSuspendRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	s.startBulkJob(w, r, action, req.SuspendScope, req.DryRun)
+}
+
+// startBulkJob lists the resources in a scope and starts a job applying action to them.
+// A dry run returns the matched resources without changing any; starting the job requires
+// the action's permission from bulkActionPermissions.
+func (s *Server) startBulkJob(w http.ResponseWriter, r *http.Request, action string, scope k8s.SuspendScope, dryRun bool) {
+	clusterID := mux.Vars(r)["id"]
+
+	if err := scope.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	targets, err := s.k8sClient.ListSuspendTargets(r.Context(), clusterID, scope)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list resources: %v", err))
+		return
+	}
+	if dryRun {
+		respondJSON(w, http.StatusOK, map[string]interface{}{"action": action, "scope": scope, "targets": targets})
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	allAction := action + "_all"
+	words := bulkActionLog[action]
+	description := describeSuspendScope(scope)
+
+	if err := s.requirePermission(r, bulkActionPermissions[action]); err != nil {
+		s.logActivity(requestActor(r), allAction, "Cluster", clusterID, clusterName, clusterID, clusterName, "failed", fmt.Sprintf("Denied %s of %s: %v", action, description, err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Bulk %s not allowed: %v", action, err))
+		return
+	}
+
+	ctx := s.provenanceContext(r, allAction)
+	job := s.bulkJobs.Start(ctx, clusterID, action, scope, requestActor(r), targets, func(job bulk.Job) {
+		for _, result := range job.Results {
+			if result.Status == bulk.ItemSkipped {
+				continue
+			}
+			status, message := "success", fmt.Sprintf("%s %s/%s by bulk job %s", words.past, result.Namespace, result.Name, job.ID)
+			if result.Status == bulk.ItemFailed {
+				status, message = "failed", fmt.Sprintf("Error: %s", result.Error)
+			}
+			s.logActivity(requestActor(r), action, result.Kind, fmt.Sprintf("%s/%s", result.Namespace, result.Name), result.Name, clusterID, clusterName, status, message)
+		}
+
+		status := "success"
+		if job.Status == bulk.JobFailed || job.Summary.Failed > 0 {
+			status = "failed"
+		}
+		s.logActivity(requestActor(r), allAction, "Cluster", clusterID, clusterName, clusterID, clusterName, status,
+			fmt.Sprintf("%s %s: %d changed, %d %s, %d failed", words.past, description,
+				job.Summary.Applied, job.Summary.Skipped, words.skipped, job.Summary.Failed))
+	})
+
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// getBulkJob returns the progress and report of a bulk suspend, resume or reconcile. Jobs
+// on clusters the caller cannot read are not found.
+func (s *Server) getBulkJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.bulkJobs.Get(mux.Vars(r)["jobId"])
+	if ok {
+		if status, err := s.checkClusterAccess(r, job.ClusterID, repository.AccessRead); err != nil {
+			if status != http.StatusNotFound {
+				respondError(w, status, err.Error())
+				return
+			}
+			ok = false
+		}
+	}
+	if !ok {
+		respondError(w, http.StatusNotFound, "Bulk job not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, job)
+}
+
+// describeSuspendScope summarizes a scope for the activity log
+func describeSuspendScope(scope k8s.SuspendScope) string {
+	description := "all " + strings.Join(scope.Kinds, "s and ") + "s"
+	if scope.Namespace != "" {
+		description += " in " + scope.Namespace
+	}
+	if scope.LabelSelector != "" {
+		description += " matching " + scope.LabelSelector
+	}
+	return description
+}
+
+// cleanupBulkJobs periodically removes expired bulk job reports
+func (s *Server) cleanupBulkJobs() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.bulkJobs.CleanExpired()
+	}
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// sharingPermission lets a user see every cluster and manage the shares of any cluster
+const sharingPermission = "sharing.manage"
+
+// sharedVisibilityFromEnv reports whether CLUSTER_VISIBILITY=shared restricts users to the
+// clusters shared with them. It needs authentication, without which there is nobody to
+// share clusters with.
+func sharedVisibilityFromEnv(authEnabled bool) bool {
+	switch visibility := os.Getenv("CLUSTER_VISIBILITY"); visibility {
+	case "", "all":
+		return false
+	case "shared":
+		if !authEnabled {
+			log.Printf("Warning: CLUSTER_VISIBILITY=shared ignored: authentication is disabled")
+			return false
+		}
+		return true
+	default:
+		log.Printf("Warning: Unknown CLUSTER_VISIBILITY %q, every user sees every cluster", visibility)
+		return false
+	}
+}
+
+// clusterScope returns the scope cluster and resource queries of a request run in. With
+// shared visibility it is restricted to the clusters shared with the caller, unless they
+// hold the sharing.manage permission; anonymous callers see no cluster.
+func (s *Server) clusterScope(r *http.Request) repository.Scope {
+	if !s.sharedVisibility {
+		return repository.Unrestricted
+	}
+	return s.userScope(r)
+}
+
+// userScope returns the caller's scope regardless of the visibility mode, so owners can
+// manage shares before shared visibility is turned on
+func (s *Server) userScope(r *http.Request) repository.Scope {
+	email := requestEmail(r)
+	if email == "" {
+		return repository.Scope{Restricted: true}
+	}
+	if s.rbacManager.UserHasPermission(email, sharingPermission) {
+		return repository.Unrestricted
+	}
+	roles, err := s.rbacManager.UserRoleIDs(email)
+	if err != nil {
+		log.Printf("Warning: Failed to load roles of %s: %v", email, err)
+	}
+	return repository.Scope{Restricted: true, Email: email, RoleIDs: roles}
+}
+
+// clusterAccessMiddleware refuses requests on a cluster the caller cannot access under
+// shared visibility: clusters not shared with them are not found, requests other than GET
+// need write access and deleting the cluster needs owner access
+func (s *Server) clusterAccessMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		if !s.sharedVisibility || r.Method == http.MethodOptions || id == "" || !isClusterRoute(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		wanted := repository.AccessWrite
+		switch {
+		case r.Method == http.MethodGet || r.Method == http.MethodHead:
+			wanted = repository.AccessRead
+		case r.Method == http.MethodDelete && strings.HasSuffix(routeTemplate(r), "/clusters/{id}"):
+			wanted = repository.AccessOwner
+		}
+		if status, err := s.checkClusterAccess(r, id, wanted); err != nil {
+			respondError(w, status, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkClusterAccess returns why the caller lacks wanted access to a cluster under shared
+// visibility, with the status to respond with, or nil. Clusters not shared with the caller
+// are not found. It also covers handlers taking the cluster from the request body.
+func (s *Server) checkClusterAccess(r *http.Request, clusterID, wanted string) (int, error) {
+	if !s.sharedVisibility {
+		return http.StatusOK, nil
+	}
+	access, err := s.clusterService.Access(s.clusterScope(r), clusterID)
+	if err != nil && !repository.IsNotFound(err) {
+		log.Printf("Failed to check access to cluster %s: %v", clusterID, err)
+		return http.StatusInternalServerError, errors.New("Failed to check cluster access")
+	}
+	if access == "" {
+		return http.StatusNotFound, errors.New("Cluster not found")
+	}
+	if !repository.HasAccess(access, wanted) {
+		return http.StatusForbidden, fmt.Errorf("You have %s access to this cluster; this needs %s access", access, wanted)
+	}
+	return http.StatusOK, nil
+}
+
+// visibleClusterIDs returns the IDs of the clusters the caller can read and whether they
+// are restricted to them; unrestricted callers read every cluster and get no IDs
+func (s *Server) visibleClusterIDs(r *http.Request) ([]string, bool, error) {
+	scope := s.clusterScope(r)
+	if !scope.Restricted {
+		return nil, false, nil
+	}
+	clusters, err := s.clusterService.List(scope, true)
+	if err != nil {
+		return nil, true, err
+	}
+	ids := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		ids = append(ids, cluster.ID)
+	}
+	return ids, true, nil
+}
+
+// isClusterRoute reports whether a request matched a route under /clusters/{id}
+func isClusterRoute(r *http.Request) bool {
+	template := routeTemplate(r) // also the v1 route of requests forwarded from /api/v2
+	return strings.HasSuffix(template, "/clusters/{id}") || strings.Contains(template, "/clusters/{id}/")
+}
+
+// requireShareManagement returns an error unless the caller owns the cluster or holds the
+// sharing.manage permission
+func (s *Server) requireShareManagement(r *http.Request, clusterID string) error {
+	access, err := s.clusterService.Access(s.userScope(r), clusterID)
+	if err != nil {
+		return err
+	}
+	if !repository.HasAccess(access, repository.AccessOwner) {
+		return fmt.Errorf("only owners of the cluster and holders of the %s permission manage its shares", sharingPermission)
+	}
+	return nil
+}
+
+// listClusterShares returns who a cluster is shared with
+func (s *Server) listClusterShares(w http.ResponseWriter, r *http.Request) {
+	shares, err := s.clusterService.Shares(mux.Vars(r)["id"])
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to list cluster shares")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"shares":             shares,
+		"visibility_enabled": s.sharedVisibility,
+	})
+}
+
+// putClusterShare shares a cluster with a user or role, or changes the access of an
+// existing share with them
+func (s *Server) putClusterShare(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+	var req struct {
+		SubjectType string `json:"subject_type"`
+		Subject     string `json:"subject"`
+		Access      string `json:"access"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	if err := s.requireShareManagement(r, clusterID); err != nil {
+		if repository.IsNotFound(err) {
+			respondError(w, http.StatusNotFound, "Cluster not found")
+			return
+		}
+		s.logActivity(requestActor(r), "share", "cluster", clusterID, clusterName, clusterID, clusterName, "failed", err.Error())
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if req.SubjectType == repository.ShareRole {
+		var count int64
+		if err := s.db.Model(&models.Role{}).Where("id = ?", req.Subject).Count(&count).Error; err != nil || count == 0 {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("Role %s does not exist", req.Subject))
+			return
+		}
+	}
+
+	share := models.ClusterShare{SubjectType: req.SubjectType, Subject: req.Subject, Access: req.Access, CreatedBy: requestEmail(r)}
+	stored, previous, err := s.clusterService.Share(clusterID, share)
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to share cluster")
+		return
+	}
+
+	changes := &activityChangeSet{}
+	changes.param("subject_type", stored.SubjectType)
+	changes.param("subject", stored.Subject)
+	if previous == "" {
+		changes.change("access", nil, stored.Access)
+	} else {
+		changes.change("access", previous, stored.Access)
+	}
+	s.logActivityDetails(requestActor(r), "share", "cluster", clusterID, clusterName, clusterID, clusterName, "success",
+		fmt.Sprintf("Gave %s %s %s access to cluster %s", stored.SubjectType, stored.Subject, stored.Access, clusterName), changes)
+	respondJSON(w, http.StatusOK, stored)
+}
+
+// deleteClusterShare stops sharing a cluster with a user or role
+func (s *Server) deleteClusterShare(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	shareID := vars["shareId"]
+
+	clusterName := s.clusterService.Name(clusterID)
+	if err := s.requireShareManagement(r, clusterID); err != nil {
+		if repository.IsNotFound(err) {
+			respondError(w, http.StatusNotFound, "Cluster not found")
+			return
+		}
+		s.logActivity(requestActor(r), "unshare", "cluster", clusterID, clusterName, clusterID, clusterName, "failed", err.Error())
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	share, err := s.clusterService.Unshare(clusterID, shareID)
+	if err != nil {
+		respondServiceError(w, err, "Share not found", "Failed to delete cluster share")
+		return
+	}
+
+	s.logActivity(requestActor(r), "unshare", "cluster", clusterID, clusterName, clusterID, clusterName, "success",
+		fmt.Sprintf("Removed the %s access of %s %s to cluster %s", share.Access, share.SubjectType, share.Subject, clusterName))
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Share deleted"})
+}
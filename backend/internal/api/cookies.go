@@ -0,0 +1,169 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+)
+
+// Cookie names used by the OAuth flow
+const (
+	sessionCookieName    = "session_token"
+	stateCookieName      = "oauth_state"
+	returnToCookieName   = "oauth_return_to"
+	grantStateCookieName = "oauth_grant_state"
+)
+
+// cookieConfig holds the attributes applied to auth cookies
+type cookieConfig struct {
+	Domain   string
+	Path     string
+	Secure   bool
+	SameSite http.SameSite
+	// encryptor seals cookie values when COOKIE_ENCRYPT is enabled
+	encryptor *encryption.Encryptor
+}
+
+// cookieConfigFromEnv reads cookie attributes from the environment:
+// COOKIE_DOMAIN, COOKIE_PATH (default: the base path, or "/"), COOKIE_SECURE
+// (default true), COOKIE_SAMESITE (lax, strict or none; default lax) and COOKIE_ENCRYPT.
+func cookieConfigFromEnv(encryptor *encryption.Encryptor, basePath string) cookieConfig {
+	cfg := cookieConfig{
+		Domain:   os.Getenv("COOKIE_DOMAIN"),
+		Path:     os.Getenv("COOKIE_PATH"),
+		Secure:   os.Getenv("COOKIE_SECURE") != "false",
+		SameSite: http.SameSiteLaxMode,
+	}
+	if cfg.Path == "" {
+		cfg.Path = basePath
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+
+	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
+	case "", "lax":
+	case "strict":
+		cfg.SameSite = http.SameSiteStrictMode
+	case "none":
+		cfg.SameSite = http.SameSiteNoneMode
+		if !cfg.Secure {
+			log.Printf("Warning: COOKIE_SAMESITE=none requires secure cookies; enabling COOKIE_SECURE")
+			cfg.Secure = true
+		}
+	default:
+		log.Printf("Warning: Unknown COOKIE_SAMESITE %q, using lax", os.Getenv("COOKIE_SAMESITE"))
+	}
+
+	if os.Getenv("COOKIE_ENCRYPT") == "true" {
+		cfg.encryptor = encryptor
+	}
+	return cfg
+}
+
+// sessionStoreFromEnv creates the session store SESSION_STORE selects: memory (the default)
+// or database, which keeps sessions across restarts and shares them between replicas
+func sessionStoreFromEnv(db *database.DB, encryptor *encryption.Encryptor) *auth.SessionStore {
+	switch strings.ToLower(os.Getenv("SESSION_STORE")) {
+	case "", "memory":
+	case "database":
+		store, err := auth.NewDatabaseSessionStore(db, encryptor)
+		if err == nil {
+			return store
+		}
+		log.Printf("Warning: Keeping sessions in memory: %v", err)
+	default:
+		log.Printf("Warning: Unknown SESSION_STORE %q, keeping sessions in memory", os.Getenv("SESSION_STORE"))
+	}
+	return auth.NewSessionStore()
+}
+
+// sameSiteFor returns the SameSite mode for a cookie. The flow cookies must be
+// sent on the redirect back from the identity provider, which is a cross-site
+// navigation, so Strict is relaxed to Lax for it.
+func (c cookieConfig) sameSiteFor(name string) http.SameSite {
+	if (name == stateCookieName || name == returnToCookieName || name == grantStateCookieName) && c.SameSite == http.SameSiteStrictMode {
+		return http.SameSiteLaxMode
+	}
+	return c.SameSite
+}
+
+// setCookie writes an auth cookie, encrypting the value when configured
+func (s *Server) setCookie(w http.ResponseWriter, name, value string, maxAge int) error {
+	if s.cookies.encryptor != nil {
+		sealed, err := s.cookies.encryptor.Encrypt(value)
+		if err != nil {
+			return err
+		}
+		value = sealed
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   s.cookies.Domain,
+		Path:     s.cookies.Path,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   s.cookies.Secure,
+		SameSite: s.cookies.sameSiteFor(name),
+	})
+	return nil
+}
+
+// clearCookie expires an auth cookie
+func (s *Server) clearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Domain:   s.cookies.Domain,
+		Path:     s.cookies.Path,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.cookies.Secure,
+		SameSite: s.cookies.sameSiteFor(name),
+	})
+}
+
+// readCookie returns the value of an auth cookie, decrypting it when configured
+func (s *Server) readCookie(r *http.Request, name string) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	if s.cookies.encryptor == nil {
+		return cookie.Value, nil
+	}
+	return s.cookies.encryptor.Decrypt(cookie.Value)
+}
+
+// maxReturnToLength bounds the stored post-login path
+const maxReturnToLength = 2048
+
+// sanitizeReturnTo returns raw when it is a same-origin relative path and ""
+// otherwise, so the login flow cannot be used as an open redirect
+func sanitizeReturnTo(raw string) string {
+	if raw == "" || len(raw) > maxReturnToLength {
+		return ""
+	}
+	// Require a single leading slash; "//host" and "/\host" are treated as
+	// absolute URLs by browsers
+	if raw[0] != '/' || (len(raw) > 1 && (raw[1] == '/' || raw[1] == '\\')) {
+		return ""
+	}
+	if strings.ContainsAny(raw, "\r\n\t\\") {
+		return ""
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "" || parsed.Host != "" {
+		return ""
+	}
+	return raw
+}
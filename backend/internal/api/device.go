@@ -0,0 +1,268 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/oauth2"
+)
+
+// defaultAPITokenTTL applies when API_TOKEN_TTL_HOURS is not set
+const defaultAPITokenTTL = 30 * 24 * time.Hour
+
+// apiTokenLastUsedResolution limits how often last_used_at is written per token
+const apiTokenLastUsedResolution = time.Minute
+
+// apiTokenTTL returns the lifetime of newly issued API tokens
+func apiTokenTTL() time.Duration {
+	return time.Duration(envInt64("API_TOKEN_TTL_HOURS", int64(defaultAPITokenTTL/time.Hour))) * time.Hour
+}
+
+// handleDeviceStart begins an OAuth device authorization for CLI clients
+func (s *Server) handleDeviceStart(w http.ResponseWriter, r *http.Request) {
+	da, err := s.oauthProvider.DeviceAuth(r.Context())
+	if err != nil {
+		log.Printf("OAuth device authorization failed: %v", err)
+		respondError(w, http.StatusBadGateway, "Failed to start device authorization")
+		return
+	}
+
+	deviceCode, err := auth.GenerateState()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate device code")
+		return
+	}
+
+	expiresAt := da.Expiry
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(15 * time.Minute)
+	}
+	interval := int(da.Interval)
+	if interval == 0 {
+		interval = 5
+	}
+
+	if err := s.deviceLogins.Add(deviceCode, &auth.DeviceLogin{
+		Status:    auth.DeviceLoginPending,
+		ExpiresAt: expiresAt,
+		Interval:  interval,
+	}); err != nil {
+		log.Printf("Warning: Refusing device authorization: %v", err)
+		setRetryAfter(w, interval)
+		respondError(w, http.StatusServiceUnavailable, "Too many device logins in progress; try again later")
+		return
+	}
+	go s.completeDeviceLogin(deviceCode, da, expiresAt)
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":               deviceCode,
+		"user_code":                 da.UserCode,
+		"verification_uri":          da.VerificationURI,
+		"verification_uri_complete": da.VerificationURIComplete,
+		"expires_in":                int(time.Until(expiresAt).Seconds()),
+		"interval":                  interval,
+	})
+}
+
+// completeDeviceLogin waits for the user to approve the device authorization, until it
+// expires, and issues an API token
+func (s *Server) completeDeviceLogin(deviceCode string, da *oauth2.DeviceAuthResponse, expiresAt time.Time) {
+	ctx := context.Background()
+
+	pollCtx, cancel := context.WithDeadline(ctx, expiresAt)
+	token, err := s.oauthProvider.DeviceAccessToken(pollCtx, da)
+	cancel()
+	if err != nil {
+		status := auth.DeviceLoginDenied
+		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "expired_token") {
+			status = auth.DeviceLoginExpired
+		}
+		log.Printf("OAuth device authorization did not complete: %v", err)
+		s.deviceLogins.Resolve(deviceCode, status, "", "")
+		return
+	}
+
+	userInfo, err := s.oauthProvider.GetUserInfo(ctx, token)
+	if err != nil {
+		log.Printf("Failed to get user info: %v", err)
+		s.deviceLogins.Resolve(deviceCode, auth.DeviceLoginDenied, "", "")
+		return
+	}
+
+	if !s.oauthProvider.IsUserAllowed(userInfo) {
+		log.Printf("User not allowed: %s", userInfo.Email)
+		s.logActivity(userInfo.Email, "device_login", "api_token", "", userInfo.Email, "", "", "failed", "User not allowed")
+		s.deviceLogins.Resolve(deviceCode, auth.DeviceLoginDenied, "", "")
+		return
+	}
+
+	if disabled, err := s.rbacManager.UserDisabled(userInfo.Email); err != nil || disabled {
+		log.Printf("Not issuing an API token to %s: disabled or unknown user state", userInfo.Email)
+		s.logActivity(userInfo.Email, "device_login", "api_token", "", userInfo.Email, "", "", "failed", "User is disabled")
+		s.deviceLogins.Resolve(deviceCode, auth.DeviceLoginDenied, "", "")
+		return
+	}
+
+	apiToken, record, err := s.issueAPIToken(userInfo, "Device login")
+	if err != nil {
+		log.Printf("Failed to issue API token: %v", err)
+		s.deviceLogins.Resolve(deviceCode, auth.DeviceLoginDenied, "", "")
+		return
+	}
+
+	s.logActivity(userInfo.Email, "device_login", "api_token", record.ID, record.Name, "", "", "success",
+		fmt.Sprintf("Issued API token for %s", userInfo.Email))
+	s.deviceLogins.Resolve(deviceCode, auth.DeviceLoginComplete, apiToken, record.ID)
+}
+
+// handleDevicePoll returns the API token once the device authorization is approved
+func (s *Server) handleDevicePoll(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DeviceCode string `json:"device_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.DeviceCode == "" {
+		respondError(w, http.StatusBadRequest, "device_code is required")
+		return
+	}
+
+	login, exists := s.deviceLogins.Poll(req.DeviceCode)
+	if !exists {
+		respondError(w, http.StatusBadRequest, auth.DeviceLoginExpired)
+		return
+	}
+
+	switch login.Status {
+	case auth.DeviceLoginPending:
+		respondJSON(w, http.StatusAccepted, map[string]interface{}{
+			"status":   login.Status,
+			"interval": login.Interval,
+		})
+	case auth.DeviceLoginComplete:
+		var record models.APIToken
+		s.db.First(&record, "id = ?", login.TokenID)
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"status":       login.Status,
+			"access_token": login.Token,
+			"token_type":   "Bearer",
+			"token_id":     login.TokenID,
+			"expires_at":   record.ExpiresAt,
+		})
+	default:
+		respondError(w, http.StatusBadRequest, login.Status)
+	}
+}
+
+// issueAPIToken creates and stores a new API token for a user.
+// The plaintext token is returned once and never stored.
+func (s *Server) issueAPIToken(userInfo *auth.UserInfo, name string) (string, *models.APIToken, error) {
+	token, hash, err := auth.GenerateAPIToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	record := &models.APIToken{
+		ID:          uuid.New().String(),
+		Name:        name,
+		TokenHash:   hash,
+		UserID:      userInfo.ID,
+		Email:       userInfo.Email,
+		Username:    userInfo.Username,
+		DisplayName: userInfo.Name,
+		Provider:    userInfo.Provider,
+		ExpiresAt:   time.Now().Add(apiTokenTTL()),
+	}
+	if err := s.db.Create(record).Error; err != nil {
+		return "", nil, err
+	}
+	return token, record, nil
+}
+
+// authenticateAPIToken resolves a bearer token to the user it was issued to
+func (s *Server) authenticateAPIToken(token string) (*auth.UserInfo, bool) {
+	if !strings.HasPrefix(token, auth.APITokenPrefix) {
+		return nil, false
+	}
+
+	var record models.APIToken
+	if err := s.db.First(&record, "token_hash = ?", auth.HashAPIToken(token)).Error; err != nil {
+		return nil, false
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, false
+	}
+
+	if record.LastUsedAt == nil || time.Since(*record.LastUsedAt) > apiTokenLastUsedResolution {
+		if err := s.db.Model(&record).Update("last_used_at", time.Now()).Error; err != nil {
+			log.Printf("Warning: Failed to update API token last use: %v", err)
+		}
+	}
+
+	return &auth.UserInfo{
+		ID:       record.UserID,
+		Email:    record.Email,
+		Name:     record.DisplayName,
+		Username: record.Username,
+		Provider: record.Provider,
+	}, true
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// listAPITokens returns the caller's API tokens
+func (s *Server) listAPITokens(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value("user").(*auth.UserInfo)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var tokens []models.APIToken
+	if err := s.db.Where("user_id = ? AND provider = ?", user.ID, user.Provider).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list API tokens: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// revokeAPIToken deletes one of the caller's API tokens
+func (s *Server) revokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	user, _ := r.Context().Value("user").(*auth.UserInfo)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	result := s.db.Where("id = ? AND user_id = ? AND provider = ?", id, user.ID, user.Provider).Delete(&models.APIToken{})
+	if result.Error != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to revoke API token: %v", result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondError(w, http.StatusNotFound, "API token not found")
+		return
+	}
+
+	s.logActivity(requestActor(r), "revoke", "api_token", id, id, "", "", "success", "Revoked API token")
+	respondJSON(w, http.StatusOK, map[string]string{"message": "API token revoked"})
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// Response DTOs decouple the API from GORM models so that new columns,
+// in particular secrets, are never serialized unless added here explicitly.
+
+// ClusterResponse is the API representation of a cluster
+type ClusterResponse struct {
+	ID                  string     `json:"id"`
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	Status              string     `json:"status"`
+	Source              string     `json:"source"`
+	SourceID            string     `json:"source_id"`
+	IsFavorite          bool       `json:"is_favorite"`
+	Archived            bool       `json:"archived"`
+	ArchivedAt          *time.Time `json:"archived_at,omitempty"`
+	HealthCheckInterval int        `json:"health_check_interval"`
+	ClientQPS           float32    `json:"client_qps"`
+	ClientBurst         int        `json:"client_burst"`
+	ClientTimeout       int        `json:"client_timeout"`
+	ImpersonateUsers    bool       `json:"impersonate_users"`
+	ResourceCount       int        `json:"resource_count"`
+	FluxStatus          string     `json:"flux_status"`
+	FluxMessage         string     `json:"flux_message"`
+	FluxVersion         string     `json:"flux_version,omitempty"`
+	ServerURL           string     `json:"server_url"`
+	CAFingerprint       string     `json:"ca_fingerprint"`
+	Version             int        `json:"version"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// ClusterImportResponse is the API representation of one context of an imported kubeconfig
+type ClusterImportResponse struct {
+	Context         k8s.KubeconfigContext `json:"context"`
+	Cluster         *ClusterResponse      `json:"cluster,omitempty"`
+	ExistingCluster *ClusterResponse      `json:"existing_cluster,omitempty"`
+	Error           string                `json:"error,omitempty"`
+}
+
+// AzureSubscriptionResponse is the API representation of an Azure subscription
+type AzureSubscriptionResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	TenantID       string    `json:"tenant_id"`
+	HasCredentials bool      `json:"has_credentials"`
+	Status         string    `json:"status"`
+	ClusterCount   int       `json:"cluster_count"`
+	LastSyncedAt   time.Time `json:"last_synced_at"`
+	Version        int       `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// RegistryCredentialResponse is the API representation of a container registry credential
+type RegistryCredentialResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Provider       string    `json:"provider"`
+	Registry       string    `json:"registry"`
+	Username       string    `json:"username"`
+	HasCredentials bool      `json:"has_credentials"`
+	Status         string    `json:"status"`
+	StatusMessage  string    `json:"status_message,omitempty"`
+	LastTestedAt   time.Time `json:"last_tested_at"`
+	Version        int       `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// OAuthProviderResponse is the API representation of an OAuth provider
+type OAuthProviderResponse struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Provider        string    `json:"provider"`
+	ClientID        string    `json:"client_id"`
+	HasClientSecret bool      `json:"has_client_secret"`
+	TenantID        string    `json:"tenant_id"`
+	RedirectURL     string    `json:"redirect_url"`
+	Scopes          string    `json:"scopes"`
+	AllowedUsers    string    `json:"allowed_users"`
+	Enabled         bool      `json:"enabled"`
+	Status          string    `json:"status"`
+	Version         int       `json:"version"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// newClusterResponse converts a cluster model to its response DTO
+func newClusterResponse(c *models.Cluster) ClusterResponse {
+	return ClusterResponse{
+		ID:                  c.ID,
+		Name:                c.Name,
+		Description:         c.Description,
+		Status:              c.Status,
+		Source:              c.Source,
+		SourceID:            c.SourceID,
+		IsFavorite:          c.IsFavorite,
+		Archived:            c.Archived,
+		ArchivedAt:          c.ArchivedAt,
+		HealthCheckInterval: c.HealthCheckInterval,
+		ClientQPS:           c.ClientQPS,
+		ClientBurst:         c.ClientBurst,
+		ClientTimeout:       c.ClientTimeout,
+		ImpersonateUsers:    c.ImpersonateUsers,
+		ResourceCount:       c.ResourceCount,
+		FluxStatus:          c.FluxStatus,
+		FluxMessage:         c.FluxMessage,
+		FluxVersion:         c.FluxVersion,
+		ServerURL:           c.ServerURL,
+		CAFingerprint:       c.CAFingerprint,
+		Version:             c.Version,
+		CreatedAt:           c.CreatedAt,
+		UpdatedAt:           c.UpdatedAt,
+	}
+}
+
+// newClusterResponses converts cluster models to response DTOs
+func newClusterResponses(clusters []models.Cluster) []ClusterResponse {
+	responses := make([]ClusterResponse, 0, len(clusters))
+	for i := range clusters {
+		responses = append(responses, newClusterResponse(&clusters[i]))
+	}
+	return responses
+}
+
+// newAzureSubscriptionResponse converts an Azure subscription model to its response DTO
+func newAzureSubscriptionResponse(sub *models.AzureSubscription) AzureSubscriptionResponse {
+	return AzureSubscriptionResponse{
+		ID:             sub.ID,
+		Name:           sub.Name,
+		TenantID:       sub.TenantID,
+		HasCredentials: sub.Credentials != "",
+		Status:         sub.Status,
+		ClusterCount:   sub.ClusterCount,
+		LastSyncedAt:   sub.LastSyncedAt,
+		Version:        sub.Version,
+		CreatedAt:      sub.CreatedAt,
+		UpdatedAt:      sub.UpdatedAt,
+	}
+}
+
+// newAzureSubscriptionResponses converts Azure subscription models to response DTOs
+func newAzureSubscriptionResponses(subs []models.AzureSubscription) []AzureSubscriptionResponse {
+	responses := make([]AzureSubscriptionResponse, 0, len(subs))
+	for i := range subs {
+		responses = append(responses, newAzureSubscriptionResponse(&subs[i]))
+	}
+	return responses
+}
+
+// newRegistryCredentialResponse converts a registry credential model to its response DTO
+func newRegistryCredentialResponse(c *models.RegistryCredential) RegistryCredentialResponse {
+	return RegistryCredentialResponse{
+		ID:             c.ID,
+		Name:           c.Name,
+		Provider:       c.Provider,
+		Registry:       c.Registry,
+		Username:       c.Username,
+		HasCredentials: c.Credentials != "",
+		Status:         c.Status,
+		StatusMessage:  c.StatusMessage,
+		LastTestedAt:   c.LastTestedAt,
+		Version:        c.Version,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+// newRegistryCredentialResponses converts registry credential models to response DTOs
+func newRegistryCredentialResponses(credentials []models.RegistryCredential) []RegistryCredentialResponse {
+	responses := make([]RegistryCredentialResponse, 0, len(credentials))
+	for i := range credentials {
+		responses = append(responses, newRegistryCredentialResponse(&credentials[i]))
+	}
+	return responses
+}
+
+// newOAuthProviderResponse converts an OAuth provider model to its response DTO
+func newOAuthProviderResponse(p *models.OAuthProvider) OAuthProviderResponse {
+	return OAuthProviderResponse{
+		ID:              p.ID,
+		Name:            p.Name,
+		Provider:        p.Provider,
+		ClientID:        p.ClientID,
+		HasClientSecret: p.ClientSecret != "",
+		TenantID:        p.TenantID,
+		RedirectURL:     p.RedirectURL,
+		Scopes:          p.Scopes,
+		AllowedUsers:    p.AllowedUsers,
+		Enabled:         p.Enabled,
+		Status:          p.Status,
+		Version:         p.Version,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+// newOAuthProviderResponses converts OAuth provider models to response DTOs
+func newOAuthProviderResponses(providers []models.OAuthProvider) []OAuthProviderResponse {
+	responses := make([]OAuthProviderResponse, 0, len(providers))
+	for i := range providers {
+		responses = append(responses, newOAuthProviderResponse(&providers[i]))
+	}
+	return responses
+}
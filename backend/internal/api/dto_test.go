@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// secretKeys are JSON keys a response must never carry; has_credentials and
+// has_client_secret report whether a secret is set without revealing it
+var secretKeys = map[string]bool{
+	"kubeconfig":    true,
+	"kube_config":   true,
+	"client_secret": true,
+	"credentials":   true,
+	"password":      true,
+	"token":         true,
+}
+
+func TestResponseDTOsOmitSecrets(t *testing.T) {
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	encryptor, err := encryption.NewEncryptor(key)
+	if err != nil {
+		t.Fatalf("create encryptor: %v", err)
+	}
+	encrypt := func(plaintext string) string {
+		t.Helper()
+		ciphertext, err := encryptor.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("encrypt: %v", err)
+		}
+		return ciphertext
+	}
+
+	const (
+		kubeconfig     = "apiVersion: v1\nkind: Config\nusers:\n- user:\n    token: kubeconfig-bearer-token\n"
+		azureSecret    = `{"client_id":"azure-app","client_secret":"azure-client-secret"}`
+		oauthSecret    = "oauth-client-secret"
+		registrySecret = "registry-password"
+	)
+	now := time.Now()
+	cluster := models.Cluster{ID: "prod", Name: "prod", KubeConfig: encrypt(kubeconfig), Status: "healthy", CreatedAt: now, UpdatedAt: now}
+	subscription := models.AzureSubscription{ID: "sub-1", Name: "Production", TenantID: "tenant", Credentials: encrypt(azureSecret), Status: "healthy"}
+	provider := models.OAuthProvider{ID: "github", Name: "GitHub", Provider: "github", ClientID: "client", ClientSecret: encrypt(oauthSecret), Enabled: true}
+	registry := models.RegistryCredential{ID: "acr", Name: "ACR", Provider: "acr", Registry: "example.azurecr.io", Username: "puller", Credentials: encrypt(registrySecret)}
+	clusterResponse := newClusterResponse(&cluster)
+
+	responses := map[string]interface{}{
+		"cluster":              clusterResponse,
+		"clusters":             newClusterResponses([]models.Cluster{cluster}),
+		"cluster import":       ClusterImportResponse{Cluster: &clusterResponse, ExistingCluster: &clusterResponse},
+		"Azure subscription":   newAzureSubscriptionResponse(&subscription),
+		"Azure subscriptions":  newAzureSubscriptionResponses([]models.AzureSubscription{subscription}),
+		"OAuth provider":       newOAuthProviderResponse(&provider),
+		"OAuth providers":      newOAuthProviderResponses([]models.OAuthProvider{provider}),
+		"registry credential":  newRegistryCredentialResponse(&registry),
+		"registry credentials": newRegistryCredentialResponses([]models.RegistryCredential{registry}),
+	}
+	// Encrypted values, and the secrets they hold
+	secrets := []string{
+		cluster.KubeConfig, subscription.Credentials, provider.ClientSecret, registry.Credentials,
+		"kubeconfig-bearer-token", "azure-client-secret", oauthSecret, registrySecret,
+	}
+
+	for name, response := range responses {
+		data, err := json.Marshal(response)
+		if err != nil {
+			t.Fatalf("marshal %s: %v", name, err)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshal %s: %v", name, err)
+		}
+		for _, key := range jsonKeys(decoded) {
+			if secretKeys[strings.ToLower(key)] {
+				t.Errorf("%s response has key %q: %s", name, key, data)
+			}
+		}
+		for _, secret := range secrets {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s response contains secret %q: %s", name, secret, data)
+			}
+		}
+	}
+
+	if !newAzureSubscriptionResponse(&subscription).HasCredentials {
+		t.Error("Azure subscription response does not report its credentials are set")
+	}
+	if !newOAuthProviderResponse(&provider).HasClientSecret {
+		t.Error("OAuth provider response does not report its client secret is set")
+	}
+}
+
+// jsonKeys returns every object key in a decoded JSON value, at any depth
+func jsonKeys(value interface{}) []string {
+	var keys []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			keys = append(keys, key)
+			keys = append(keys, jsonKeys(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			keys = append(keys, jsonKeys(child)...)
+		}
+	}
+	return keys
+}
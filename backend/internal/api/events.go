@@ -0,0 +1,218 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/webhooks"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Event stream keepalive; the server pings well within the client's pong deadline
+const (
+	eventStreamWriteWait  = 10 * time.Second
+	eventStreamPongWait   = 60 * time.Second
+	eventStreamPingPeriod = 30 * time.Second
+)
+
+// maxRecentEvents caps how many events one request returns
+const maxRecentEvents = 1000
+
+// eventStreamMessage is a frame sent over the event stream. Replayed events are sent
+// before the "replayed" marker, live events after it.
+type eventStreamMessage struct {
+	Type           string                `json:"type"` // event, replayed
+	Event          *webhooks.StoredEvent `json:"event,omitempty"`
+	LatestSequence int64                 `json:"latest_sequence,omitempty"`
+}
+
+// isWebSocketUpgrade reports whether the request asks to switch to the WebSocket protocol
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+// eventQuery parses the since, limit and cluster_id parameters shared by the event endpoints
+func eventQuery(r *http.Request) (since int64, limit int, clusterID string, ok bool) {
+	query := r.URL.Query()
+	if value := query.Get("since"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return 0, 0, "", false
+		}
+		since = parsed
+	}
+	limit = 100
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return 0, 0, "", false
+		}
+		limit = min(parsed, maxRecentEvents)
+	}
+	return since, limit, query.Get("cluster_id"), true
+}
+
+// eventFilter selects the events of clusterID, or of every cluster when empty, that the
+// caller can read: with shared visibility only those of clusters shared with them
+func (s *Server) eventFilter(r *http.Request, clusterID string) (webhooks.EventFilter, error) {
+	ids, restricted, err := s.visibleClusterIDs(r)
+	return webhooks.EventFilter{ClusterID: clusterID, Restricted: restricted, ClusterIDs: ids}, err
+}
+
+// getRecentEvents returns recorded notifier events after a sequence number
+func (s *Server) getRecentEvents(w http.ResponseWriter, r *http.Request) {
+	store := s.webhooks.Store()
+	if store == nil {
+		respondError(w, http.StatusServiceUnavailable, "Event history is not enabled")
+		return
+	}
+
+	since, limit, clusterID, ok := eventQuery(r)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "since and limit must be non-negative integers")
+		return
+	}
+
+	filter, err := s.eventFilter(r, clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to query events")
+		return
+	}
+	events, err := store.Recent(since, limit, filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to query events")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"events":          events,
+		"latest_sequence": store.LastSequence(),
+	})
+}
+
+// streamEvents upgrades to a WebSocket, replays the events after ?since= and then streams
+// new events as they are recorded. Clients reconnect with the last sequence they saw.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	store := s.webhooks.Store()
+	if store == nil {
+		respondError(w, http.StatusServiceUnavailable, "Event history is not enabled")
+		return
+	}
+
+	since, _, clusterID, ok := eventQuery(r)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "since and limit must be non-negative integers")
+		return
+	}
+
+	filter, err := s.eventFilter(r, clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to query events")
+		return
+	}
+
+	upgrader := websocket.Upgrader{CheckOrigin: s.checkEventStreamOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		return
+	}
+	defer conn.Close()
+
+	// Subscribe before replaying so nothing recorded in between is missed
+	live, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	sent := since
+	for since > 0 {
+		replay, err := store.Recent(sent, maxRecentEvents, filter)
+		if err != nil {
+			logging.GetLogger().Warn("Failed to replay events", zap.Int64("since", sent), zap.Error(err))
+			break
+		}
+		for i := range replay {
+			if !writeEventFrame(conn, eventStreamMessage{Type: "event", Event: &replay[i]}) {
+				return
+			}
+			sent = replay[i].Sequence
+		}
+		if len(replay) < maxRecentEvents {
+			break
+		}
+	}
+	if !writeEventFrame(conn, eventStreamMessage{Type: "replayed", LatestSequence: store.LastSequence()}) {
+		return
+	}
+
+	// The client only sends control frames; reading detects when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventStreamPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case event, ok := <-live:
+			if !ok {
+				// Fell behind; the client reconnects and replays from its last sequence
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "event stream lagged"),
+					time.Now().Add(eventStreamWriteWait))
+				return
+			}
+			if event.Sequence <= sent || !filter.Matches(event.Event) {
+				continue
+			}
+			if !writeEventFrame(conn, eventStreamMessage{Type: "event", Event: &event}) {
+				return
+			}
+			sent = event.Sequence
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventStreamWriteWait)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeEventFrame sends one frame, reporting false once the connection is unusable
+func writeEventFrame(conn *websocket.Conn, message eventStreamMessage) bool {
+	conn.SetWriteDeadline(time.Now().Add(eventStreamWriteWait))
+	return conn.WriteJSON(message) == nil
+}
+
+// checkEventStreamOrigin allows cross-origin event streams only when authentication is
+// disabled; with it enabled a foreign page could otherwise ride the session cookie
+func (s *Server) checkEventStreamOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !s.authEnabled {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
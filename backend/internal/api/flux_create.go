@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// resourceCreatePermission is required to create Flux resources
+const resourceCreatePermission = "resource.create"
+
+// defaultFluxNamespace is where created resources go when the request names no namespace
+const defaultFluxNamespace = "flux-system"
+
+// createFluxResource creates a Flux resource: a Kustomization, HelmRelease, GitRepository or
+// HelmRepository to onboard an app without hand-written YAML, or an Alert, Provider or
+// Receiver. The body is either {"manifest": {...}} or guided fields (k8s.FluxResourceFields)
+// the manifest is built from; notification-controller kinds take a manifest. With dry_run=true the manifest is returned without creating it. Requires the
+// resource.create permission.
+func (s *Server) createFluxResource(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	kind := vars["kind"]
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	if !k8s.IsCreatableFluxKind(kind) && !k8s.IsNotificationKind(kind) {
+		respondError(w, http.StatusBadRequest, "Only Kustomizations, HelmReleases, GitRepositories, HelmRepositories, Alerts, Providers and Receivers can be created")
+		return
+	}
+
+	data, ok := readJSONBody(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Manifest map[string]interface{} `json:"manifest"`
+		k8s.FluxResourceFields
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	manifest, namespace, name, err := fluxManifest(kind, req.Manifest, req.FluxResourceFields)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid %s: %v", kind, err))
+		return
+	}
+	if dryRun {
+		respondJSON(w, http.StatusOK, map[string]interface{}{"dry_run": true, "manifest": manifest})
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+	if err := s.requirePermission(r, resourceCreatePermission); err != nil {
+		s.logActivity(requestActor(r), "create", kind, resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Denied creation: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Creation not allowed: %v", err))
+		return
+	}
+
+	ctx := s.provenanceContext(r, "create")
+	if err := s.resourceService.Create(ctx, clusterID, kind, namespace, manifest); err != nil {
+		s.logActivity(requestActor(r), "create", kind, resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		respondServiceError(w, err, "Cluster not found", fmt.Sprintf("Failed to create resource: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "create", kind, resourceID, name, clusterID, clusterName, "success",
+		fmt.Sprintf("Created %s %s requested by %s", kind, resourceID, requestActor(r)))
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message":  "Resource created successfully",
+		"manifest": manifest,
+	})
+}
+
+// fluxManifest returns the manifest to create and its namespace and name: the manifest
+// given as is, or else the one built from the guided fields. The namespace defaults to
+// flux-system.
+func fluxManifest(kind string, manifest map[string]interface{}, fields k8s.FluxResourceFields) (map[string]interface{}, string, string, error) {
+	if manifest == nil {
+		if k8s.IsNotificationKind(kind) {
+			return nil, "", "", fmt.Errorf("%ss are created from a manifest, not guided fields", kind)
+		}
+		if fields.Namespace == "" {
+			fields.Namespace = defaultFluxNamespace
+		}
+		built, err := k8s.BuildFluxManifest(kind, fields)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return built, fields.Namespace, fields.Name, nil
+	}
+
+	if manifestKind, _ := manifest["kind"].(string); manifestKind != "" && manifestKind != kind {
+		return nil, "", "", fmt.Errorf("manifest kind %s does not match the %s route", manifestKind, kind)
+	}
+	metadata, _ := manifest["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if name == "" {
+		return nil, "", "", fmt.Errorf("metadata.name is required")
+	}
+	if _, ok := manifest["spec"].(map[string]interface{}); !ok {
+		return nil, "", "", fmt.Errorf("spec is required")
+	}
+	namespace, _ := metadata["namespace"].(string)
+	if namespace == "" {
+		namespace = defaultFluxNamespace
+	}
+	return manifest, namespace, name, nil
+}
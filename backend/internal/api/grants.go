@@ -0,0 +1,251 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// defaultGrantScopes are asked for when GITHUB_GRANT_SCOPES is not set: enough to read
+// commits of private repositories and open pull requests
+const defaultGrantScopes = "repo"
+
+// grantScopes returns the scopes a user is asked to grant
+func grantScopes() []string {
+	value := os.Getenv("GITHUB_GRANT_SCOPES")
+	if strings.TrimSpace(value) == "" {
+		value = defaultGrantScopes
+	}
+	var scopes []string
+	for _, scope := range strings.Split(value, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// grantsAvailable reports whether users can grant their provider token
+func (s *Server) grantsAvailable() bool {
+	return s.oauthProvider != nil && s.oauthProvider.SupportsGrants()
+}
+
+// listProviderGrants returns the caller's provider grants and whether granting is possible
+func (s *Server) listProviderGrants(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value("user").(*auth.UserInfo)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	grants := []models.ProviderGrant{}
+	if err := s.db.Where("user_id = ? AND provider = ?", user.ID, user.Provider).Find(&grants).Error; err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list grants: %v", err))
+		return
+	}
+
+	available := []string{}
+	if s.grantsAvailable() && user.Provider == s.oauthProvider.ProviderType() {
+		available = append(available, user.Provider)
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"available": available,
+		"scopes":    grantScopes(),
+		"grants":    grants,
+	})
+}
+
+// authorizeProviderGrant sends the caller to the provider's consent page for a token the
+// orchestrator may keep. Only users signed in with that provider can grant it, so the
+// token always belongs to the account they signed in as.
+func (s *Server) authorizeProviderGrant(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+	user, _ := r.Context().Value("user").(*auth.UserInfo)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if !s.grantsAvailable() || provider != s.oauthProvider.ProviderType() {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("Provider %s does not support token grants", provider))
+		return
+	}
+	if user.Provider != provider {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Sign in with %s to grant access to it", provider))
+		return
+	}
+
+	state, err := auth.GenerateState()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate state")
+		return
+	}
+	if err := s.setCookie(w, grantStateCookieName, state, 600); err != nil { // 10 minutes
+		respondError(w, http.StatusInternalServerError, "Failed to store state")
+		return
+	}
+
+	http.Redirect(w, r, s.oauthProvider.GrantURL(state, grantScopes()), http.StatusTemporaryRedirect)
+}
+
+// completeProviderGrant stores the token from a consent the caller gave, reached through
+// the login callback. The outcome is reported to the settings page as ?grant=.
+func (s *Server) completeProviderGrant(w http.ResponseWriter, r *http.Request) {
+	s.clearCookie(w, grantStateCookieName)
+	done := func(outcome string) {
+		http.Redirect(w, r, s.appPath("/settings?grant="+outcome), http.StatusTemporaryRedirect)
+	}
+
+	user, err := s.authenticate(r)
+	if err != nil || user.Provider != s.oauthProvider.ProviderType() {
+		done("unauthenticated")
+		return
+	}
+	if r.URL.Query().Get("error") != "" {
+		done("denied")
+		return
+	}
+
+	token, err := s.oauthProvider.Exchange(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		log.Printf("OAuth grant token exchange failed: %v", err)
+		done("token_exchange_failed")
+		return
+	}
+	// Another account may be signed in at the provider; its token must not be kept as this user's
+	owner, err := s.oauthProvider.GetUserInfo(r.Context(), token)
+	if err != nil || owner.ID != user.ID {
+		if err == nil {
+			err = fmt.Errorf("granted by %s", owner.Username)
+		}
+		log.Printf("Rejected OAuth grant for %s: %v", user.Email, err)
+		done("account_mismatch")
+		return
+	}
+
+	if err := s.storeProviderGrant(user, token); err != nil {
+		log.Printf("Failed to store OAuth grant: %v", err)
+		done("failed")
+		return
+	}
+	s.logActivity(requestActor(r), "grant", "provider_grant", user.Provider, user.Email, "", "", "success",
+		fmt.Sprintf("Granted %s access with scopes %s", user.Provider, strings.Join(auth.GrantedScopes(token), ",")))
+	done("granted")
+}
+
+// storeProviderGrant saves a granted token, replacing the user's previous one
+func (s *Server) storeProviderGrant(user *auth.UserInfo, token *oauth2.Token) error {
+	accessToken, err := s.encryptor.Encrypt(token.AccessToken)
+	if err != nil {
+		return err
+	}
+	refreshToken, err := s.encryptor.Encrypt(token.RefreshToken)
+	if err != nil {
+		return err
+	}
+	grant := models.ProviderGrant{
+		ID:           uuid.New().String(),
+		UserID:       user.ID,
+		Provider:     user.Provider,
+		Username:     user.Username,
+		Scopes:       strings.Join(auth.GrantedScopes(token), ","),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}
+	if !token.Expiry.IsZero() {
+		grant.ExpiresAt = &token.Expiry
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND provider = ?", user.ID, user.Provider).Delete(&models.ProviderGrant{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&grant).Error
+	})
+}
+
+// revokeProviderGrant deletes the caller's grant and revokes its token at the provider
+func (s *Server) revokeProviderGrant(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+	user, _ := r.Context().Value("user").(*auth.UserInfo)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var grant models.ProviderGrant
+	err := s.db.First(&grant, "user_id = ? AND provider = ?", user.ID, provider).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("No %s grant to revoke", provider))
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to load grant: %v", err))
+		return
+	}
+
+	message := fmt.Sprintf("Revoked %s access", provider)
+	if accessToken, err := s.encryptor.Decrypt(grant.AccessToken); err != nil {
+		log.Printf("Warning: Failed to decrypt %s grant of %s: %v", provider, user.Email, err)
+	} else if s.grantsAvailable() && provider == s.oauthProvider.ProviderType() {
+		if err := s.oauthProvider.RevokeToken(r.Context(), accessToken); err != nil {
+			// The stored copy goes regardless; the user can still revoke it at the provider
+			log.Printf("Warning: Failed to revoke %s token of %s: %v", provider, user.Email, err)
+			message = fmt.Sprintf("Deleted the stored %s token, but the provider did not confirm revoking it; revoke it in your %s settings", provider, provider)
+		}
+	}
+
+	if err := s.db.Delete(&grant).Error; err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete grant: %v", err))
+		return
+	}
+	s.logActivity(requestActor(r), "revoke", "provider_grant", provider, user.Email, "", "", "success", message)
+	respondJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// providerClient returns an HTTP client calling the provider's API as user with the token
+// they granted, or nil when they granted none. Git provider features fall back to
+// anonymous access without one.
+func (s *Server) providerClient(ctx context.Context, user *auth.UserInfo) (*http.Client, error) {
+	if user == nil || !s.grantsAvailable() || user.Provider != s.oauthProvider.ProviderType() {
+		return nil, nil
+	}
+
+	var grant models.ProviderGrant
+	err := s.db.First(&grant, "user_id = ? AND provider = ?", user.ID, user.Provider).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load grant: %w", err)
+	}
+
+	token := &oauth2.Token{TokenType: "Bearer"}
+	if token.AccessToken, err = s.encryptor.Decrypt(grant.AccessToken); err != nil {
+		return nil, fmt.Errorf("failed to decrypt grant: %w", err)
+	}
+	if token.RefreshToken, err = s.encryptor.Decrypt(grant.RefreshToken); err != nil {
+		return nil, fmt.Errorf("failed to decrypt grant: %w", err)
+	}
+	if grant.ExpiresAt != nil {
+		token.Expiry = *grant.ExpiresAt
+	}
+
+	if grant.LastUsedAt == nil || time.Since(*grant.LastUsedAt) > apiTokenLastUsedResolution {
+		if err := s.db.Model(&grant).Update("last_used_at", time.Now()).Error; err != nil {
+			log.Printf("Warning: Failed to update grant last use: %v", err)
+		}
+	}
+	return s.oauthProvider.Client(ctx, token), nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// resourceUpdatePermission is required to change what a HelmRelease deploys
+const resourceUpdatePermission = "resource.update"
+
+// getHelmReleaseHistory lists the revisions of a HelmRelease's Helm release with their
+// status and chart version. Each revision's values are included only for users with the
+// secret.reveal permission, since Helm stores them in Secrets, and that is audited.
+func (s *Server) getHelmReleaseHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if vars["kind"] != "HelmRelease" {
+		respondError(w, http.StatusBadRequest, "History is only available for HelmReleases")
+		return
+	}
+
+	reveal := s.requirePermission(r, secretRevealPermission) == nil
+	history, err := s.k8sClient.GetHelmReleaseHistory(r.Context(), clusterID, namespace, name, reveal)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get release history: %v", err))
+		return
+	}
+
+	if reveal && len(history.Revisions) > 0 {
+		resourceID := fmt.Sprintf("%s/%s", namespace, name)
+		s.logActivity(requestActor(r), "reveal", "HelmRelease", resourceID, name, clusterID, s.clusterService.Name(clusterID), "success",
+			fmt.Sprintf("Revealed the values of %d revisions of HelmRelease %s", len(history.Revisions), resourceID))
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
+
+// rollbackHelmRelease points a HelmRelease back at an earlier revision's chart version,
+// and its values when it has no valuesFrom, then requests a reconcile. Both fields must be
+// editable under the spec update allowlist, and the resource.update permission is required.
+func (s *Server) rollbackHelmRelease(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if vars["kind"] != "HelmRelease" {
+		respondError(w, http.StatusBadRequest, "Rollback is only available for HelmReleases")
+		return
+	}
+
+	var req struct {
+		Revision int `json:"revision"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Revision < 1 {
+		respondError(w, http.StatusBadRequest, "A positive revision is required")
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+
+	if err := s.requirePermission(r, resourceUpdatePermission); err != nil {
+		s.logActivity(requestActor(r), "rollback", "HelmRelease", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Denied rollback to revision %d: %v", req.Revision, err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Rollback not allowed: %v", err))
+		return
+	}
+
+	patch := map[string]interface{}{"spec": map[string]interface{}{"chart": nil, "values": nil}}
+	if err := s.specUpdatePolicy().check("HelmRelease", patch); err != nil {
+		s.logActivity(requestActor(r), "rollback", "HelmRelease", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Rollback to revision %d blocked: %v", req.Revision, err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Rollback blocked: %v", err))
+		return
+	}
+
+	result, err := s.k8sClient.RollbackHelmRelease(s.provenanceContext(r, "rollback"), clusterID, namespace, name, req.Revision)
+	if err != nil {
+		s.logActivity(requestActor(r), "rollback", "HelmRelease", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to roll back: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "rollback", "HelmRelease", resourceID, name, clusterID, clusterName, "success", fmt.Sprintf("Rolled back to revision %d: %s", req.Revision, result.Message))
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// forceReconcileHelmRelease requests a reconcile of a HelmRelease that runs a Helm install
+// or upgrade even when its chart and values did not change
+func (s *Server) forceReconcileHelmRelease(w http.ResponseWriter, r *http.Request) {
+	s.remediateHelmRelease(w, r, "force_reconcile", s.k8sClient.ForceReconcileHelmRelease)
+}
+
+// resetHelmReleaseFailures clears the install and upgrade failure counts of a HelmRelease
+// that exhausted its remediation retries and requests a reconcile
+func (s *Server) resetHelmReleaseFailures(w http.ResponseWriter, r *http.Request) {
+	s.remediateHelmRelease(w, r, "reset_failures", s.k8sClient.ResetHelmReleaseFailures)
+}
+
+// remediateHelmRelease runs a remediation of a HelmRelease, which requires the
+// resource.update permission since it makes Helm install or upgrade the release again
+func (s *Server) remediateHelmRelease(w http.ResponseWriter, r *http.Request, action string,
+	remediate func(ctx context.Context, clusterID, namespace, name string) (*k8s.HelmRemediationResult, error)) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if vars["kind"] != "HelmRelease" {
+		respondError(w, http.StatusBadRequest, "Remediation is only available for HelmReleases")
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+
+	if err := s.requirePermission(r, resourceUpdatePermission); err != nil {
+		s.logActivity(requestActor(r), action, "HelmRelease", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Denied: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Remediation not allowed: %v", err))
+		return
+	}
+
+	result, err := remediate(s.provenanceContext(r, action), clusterID, namespace, name)
+	if err != nil {
+		s.logActivity(requestActor(r), action, "HelmRelease", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to remediate: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), action, "HelmRelease", resourceID, name, clusterID, clusterName, "success", result.Message)
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// getHelmReleaseValues returns the effective values of a HelmRelease: its valuesFrom
+// ConfigMaps and Secrets merged in order with spec.values, and which source set each key.
+// Values from Secrets are redacted unless the user has the secret.reveal permission, in
+// which case the reveal is recorded in the activity log.
+func (s *Server) getHelmReleaseValues(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if vars["kind"] != "HelmRelease" {
+		respondError(w, http.StatusBadRequest, "Effective values are only available for HelmReleases")
+		return
+	}
+
+	reveal := s.requirePermission(r, secretRevealPermission) == nil
+	values, err := s.k8sClient.GetHelmReleaseValues(r.Context(), clusterID, namespace, name, reveal)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to resolve values: %v", err))
+		return
+	}
+
+	if reveal && values.HasSecretValues() {
+		resourceID := fmt.Sprintf("%s/%s", namespace, name)
+		s.logActivity(requestActor(r), "reveal", "HelmRelease", resourceID, name, clusterID, s.clusterService.Name(clusterID), "success",
+			fmt.Sprintf("Revealed Secret-sourced values of HelmRelease %s", resourceID))
+	}
+
+	respondJSON(w, http.StatusOK, values)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// imageFreshnessReport is the image freshness report of a cluster
+type imageFreshnessReport struct {
+	ClusterID   string `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+	*k8s.ImageFreshnessReport
+}
+
+// getImageFreshness compares the images of every workload a Kustomization or HelmRelease
+// applied with the newest tags in their registries, grouped by that Flux resource.
+// Query parameter: outdated=true to keep only outdated images and their owners.
+func (s *Server) getImageFreshness(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+
+	report, err := s.k8sClient.GetImageFreshness(r.Context(), clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to check image freshness: %v", err))
+		return
+	}
+
+	if r.URL.Query().Get("outdated") == "true" {
+		owners := []k8s.ImageOwner{}
+		for _, owner := range report.Owners {
+			if owner.Outdated == 0 {
+				continue
+			}
+			images := []k8s.ImageStatus{}
+			for _, image := range owner.Images {
+				if image.Status == k8s.ImageOutdated {
+					images = append(images, image)
+				}
+			}
+			owner.Images = images
+			owners = append(owners, owner)
+		}
+		report.Owners = owners
+	}
+
+	respondJSON(w, http.StatusOK, imageFreshnessReport{
+		ClusterID:            clusterID,
+		ClusterName:          s.clusterService.Name(clusterID),
+		ImageFreshnessReport: report,
+	})
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+)
+
+// defaultImpersonationGroupPrefix is put before role IDs to form the groups users are
+// impersonated with, when K8S_IMPERSONATE_GROUP_PREFIX is not set
+const defaultImpersonationGroupPrefix = "flux-orchestrator:"
+
+// impersonationGroupPrefix returns the prefix of the impersonated groups; set to an empty
+// value, the groups are the bare role IDs
+func impersonationGroupPrefix() string {
+	if value, ok := os.LookupEnv("K8S_IMPERSONATE_GROUP_PREFIX"); ok {
+		return value
+	}
+	return defaultImpersonationGroupPrefix
+}
+
+// impersonation returns who the requesting user is on clusters that impersonate users:
+// their email, or username without one, with a group per orchestrator role, such as
+// flux-orchestrator:operator. Anonymous requests have no identity.
+func (s *Server) impersonation(r *http.Request) (k8s.Impersonation, bool) {
+	userInfo, ok := r.Context().Value("user").(*auth.UserInfo)
+	if !ok || userInfo == nil {
+		return k8s.Impersonation{}, false
+	}
+	identity := k8s.Impersonation{User: userInfo.Email}
+	if identity.User == "" {
+		identity.User = userInfo.Username
+	}
+	if identity.User == "" {
+		return k8s.Impersonation{}, false
+	}
+	if userInfo.Email == "" {
+		return identity, true
+	}
+
+	roleIDs, err := s.rbacManager.UserRoleIDs(userInfo.Email)
+	if err != nil {
+		// Without groups the user is impersonated with less access, never more
+		log.Printf("Warning: Failed to load roles of %s for impersonation: %v", userInfo.Email, err)
+	}
+	prefix := impersonationGroupPrefix()
+	for _, roleID := range roleIDs {
+		identity.Groups = append(identity.Groups, prefix+roleID)
+	}
+	return identity, true
+}
+
+// impersonationContext returns ctx carrying the requesting user's identity, so that
+// clusters impersonating users act, and answer access reviews, as that user
+func (s *Server) impersonationContext(ctx context.Context, r *http.Request) context.Context {
+	if identity, ok := s.impersonation(r); ok {
+		return k8s.WithImpersonation(ctx, identity)
+	}
+	return ctx
+}
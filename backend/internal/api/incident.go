@@ -0,0 +1,164 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
+	"github.com/gorilla/mux"
+)
+
+// Incident feed limits
+const (
+	defaultIncidentWindow = time.Hour
+	defaultIncidentLimit  = 500
+	maxIncidentLimit      = 2000
+)
+
+// Sources of incident feed entries
+const (
+	incidentActivity   = "activity"   // an action taken through the orchestrator
+	incidentTransition = "transition" // a Flux resource's readiness changed
+	incidentDeployment = "deployment" // a Flux resource moved to a new revision
+	incidentEvent      = "event"      // a Kubernetes event
+)
+
+// incidentEntry is one entry of an incident feed. Detail holds the record it came from.
+type incidentEntry struct {
+	Time      time.Time   `json:"time"`
+	Source    string      `json:"source"`
+	Severity  string      `json:"severity"` // info, warning, error
+	Kind      string      `json:"kind"`
+	Namespace string      `json:"namespace,omitempty"`
+	Name      string      `json:"name"`
+	Summary   string      `json:"summary"`
+	Detail    interface{} `json:"detail"`
+}
+
+// getIncidentFeed merges what happened to a cluster within a time window into one
+// chronological feed for post-incident review: orchestrator activities, Flux readiness
+// transitions, deployed revisions and Kubernetes events. The window is since/until, or
+// around with window either side of it, and defaults to the last hour; namespace narrows
+// transitions, deployments and events. Each source is capped at its latest limit entries,
+// and a source that cannot be read is reported under warnings instead of failing the request.
+func (s *Server) getIncidentFeed(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	namespace := query.Get("namespace")
+
+	since, until, err := timeWindow(query, defaultIncidentWindow)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := limitParam(query, defaultIncidentLimit, maxIncidentLimit)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cluster, err := s.clusterService.Get(clusterID)
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to query cluster")
+		return
+	}
+
+	entries := []incidentEntry{}
+	warnings := []string{}
+	counts := map[string]int{}
+	add := func(entry incidentEntry) {
+		entries = append(entries, entry)
+		counts[entry.Source]++
+	}
+
+	activities, err := s.activities.ListBetween(clusterID, since, until, limit)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("activities: %v", err))
+	}
+	for _, activity := range activities {
+		severity := "info"
+		if activity.Status == "failed" {
+			severity = "error"
+		}
+		add(incidentEntry{
+			Time: activity.CreatedAt, Source: incidentActivity, Severity: severity,
+			Kind: activity.ResourceType, Name: activity.ResourceName,
+			Summary: fmt.Sprintf("%s by %s: %s", activity.Action, activity.UserID, activity.Message),
+			Detail:  activity,
+		})
+	}
+
+	transitions, err := history.Transitions(s.db, clusterID, namespace, since, until, limit)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("transitions: %v", err))
+	}
+	for _, transition := range transitions {
+		severity := "info"
+		if transition.NewStatus == "NotReady" {
+			severity = "error"
+		}
+		oldStatus := transition.OldStatus
+		if oldStatus == "" {
+			oldStatus = "new"
+		}
+		summary := fmt.Sprintf("%s → %s", oldStatus, transition.NewStatus)
+		if transition.Message != "" {
+			summary += ": " + transition.Message
+		}
+		add(incidentEntry{
+			Time: transition.RecordedAt, Source: incidentTransition, Severity: severity,
+			Kind: transition.Kind, Namespace: transition.Namespace, Name: transition.Name,
+			Summary: summary, Detail: transition,
+		})
+	}
+
+	deployments, err := history.Timeline(s.db, history.TimelineQuery{ClusterID: clusterID, Namespace: namespace, Since: since, Until: until, Limit: limit})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("deployments: %v", err))
+	}
+	for _, deployment := range deployments {
+		summary := fmt.Sprintf("%s %s", deployment.Type, deployment.NewRevision)
+		if deployment.OldRevision != "" {
+			summary = fmt.Sprintf("%s %s → %s", deployment.Type, deployment.OldRevision, deployment.NewRevision)
+		}
+		add(incidentEntry{
+			Time: deployment.RecordedAt, Source: incidentDeployment, Severity: "info",
+			Kind: deployment.Kind, Namespace: deployment.Namespace, Name: deployment.Name,
+			Summary: summary, Detail: deployment,
+		})
+	}
+
+	events, err := s.k8sClient.ListClusterEvents(r.Context(), clusterID, namespace, since, until, limit)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("kubernetes events: %v", err))
+	}
+	for _, event := range events {
+		severity := "info"
+		if event.Type == "Warning" {
+			severity = "warning"
+		}
+		summary := fmt.Sprintf("%s: %s", event.Reason, strings.TrimSpace(event.Message))
+		if event.Count > 1 {
+			summary += fmt.Sprintf(" (×%d)", event.Count)
+		}
+		add(incidentEntry{
+			Time: event.LastSeen, Source: incidentEvent, Severity: severity,
+			Kind: event.Object.Kind, Namespace: event.Object.Namespace, Name: event.Object.Name,
+			Summary: summary, Detail: event,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"cluster_id":   clusterID,
+		"cluster_name": cluster.Name,
+		"since":        since,
+		"until":        until,
+		"entries":      entries,
+		"counts":       counts,
+		"warnings":     warnings,
+	})
+}
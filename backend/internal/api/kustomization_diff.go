@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// getKustomizationDiff previews what reconciling a Kustomization would change: the
+// manifests built from its source's current artifact are server-side dry-run applied and
+// diffed against the live objects. Post-build variables from Secrets are redacted unless
+// the user has the secret.reveal permission, in which case the reveal is recorded in the
+// activity log.
+func (s *Server) getKustomizationDiff(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if vars["kind"] != "Kustomization" {
+		respondError(w, http.StatusBadRequest, "Diff is only available for Kustomizations")
+		return
+	}
+
+	reveal := s.requirePermission(r, secretRevealPermission) == nil
+	diff, err := s.k8sClient.DiffKustomization(r.Context(), clusterID, namespace, name, reveal)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to diff kustomization: %v", err))
+		return
+	}
+
+	if reveal && diff.SecretVariables > 0 {
+		resourceID := fmt.Sprintf("%s/%s", namespace, name)
+		s.logActivity(requestActor(r), "reveal", "Kustomization", resourceID, name, clusterID, s.clusterService.Name(clusterID), "success",
+			fmt.Sprintf("Revealed %d variables substituted from Secrets in the diff of Kustomization %s", diff.SecretVariables, resourceID))
+	}
+
+	respondJSON(w, http.StatusOK, diff)
+}
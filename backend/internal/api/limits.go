@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"go.uber.org/zap"
+)
+
+// Default request body limits, overridable via environment
+const (
+	defaultMaxBodyBytes       int64 = 1 << 20  // 1 MiB
+	defaultMaxUploadBodyBytes int64 = 10 << 20 // 10 MiB for kubeconfig uploads
+	defaultMaxJSONDepth             = 32
+	defaultMaxJSONElements          = 10000
+)
+
+// uploadRoutes are route templates that accept kubeconfigs and get the larger body limit
+var uploadRoutes = map[string]bool{
+	"/api/v1/clusters":        true,
+	"/api/v1/clusters/import": true,
+	"/api/v1/clusters/{id}":   true,
+}
+
+// envInt64 reads a positive integer from the environment
+func envInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// bodyLimitMiddleware caps request body size, allowing a larger limit on upload routes
+func bodyLimitMiddleware(next http.Handler) http.Handler {
+	maxBody := envInt64("MAX_REQUEST_BODY_BYTES", defaultMaxBodyBytes)
+	maxUpload := envInt64("MAX_UPLOAD_BODY_BYTES", defaultMaxUploadBodyBytes)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Method == http.MethodGet || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := maxBody
+		if uploadRoutes[routeTemplate(r)] {
+			limit = maxUpload
+		}
+
+		if r.ContentLength > limit {
+			logging.GetLogger().Warn("Request body too large",
+				zap.String("path", r.URL.Path),
+				zap.Int64("content_length", r.ContentLength),
+				zap.Int64("limit", limit),
+			)
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", limit))
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateJSONShape rejects JSON documents nested deeper than maxDepth or
+// containing more than maxElements values, without building the full object
+func validateJSONShape(data []byte, maxDepth, maxElements int) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	depth, elements := 0, 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+				if depth > maxDepth {
+					return fmt.Errorf("JSON nesting exceeds %d levels", maxDepth)
+				}
+			case '}', ']':
+				depth--
+			}
+			continue
+		}
+
+		elements++
+		if elements > maxElements {
+			return fmt.Errorf("JSON exceeds %d values", maxElements)
+		}
+	}
+}
+
+// decodePatch reads a JSON patch body, enforcing size and shape limits.
+// It writes the error response and returns false when the body is rejected.
+func decodePatch(w http.ResponseWriter, r *http.Request, patch *map[string]interface{}) bool {
+	data, ok := readJSONBody(w, r)
+	if !ok {
+		return false
+	}
+
+	if err := json.Unmarshal(data, patch); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+// readJSONBody reads a JSON body, enforcing size and shape limits.
+// It writes the error response and returns false when the body is rejected.
+func readJSONBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit))
+			return nil, false
+		}
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+
+	maxDepth := int(envInt64("MAX_JSON_DEPTH", defaultMaxJSONDepth))
+	maxElements := int(envInt64("MAX_JSON_ELEMENTS", defaultMaxJSONElements))
+	if err := validateJSONShape(data, maxDepth, maxElements); err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logbundle"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// defaultSyncBundleContainers is how many containers a direct download may cover before
+// the caller is pointed at a background bundle, overridable with LOG_BUNDLE_SYNC_MAX_CONTAINERS
+const defaultSyncBundleContainers = 20
+
+// logBundleRequest is the body of a background bundle request
+type logBundleRequest struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	TailLines int64  `json:"tail_lines"`
+	Previous  bool   `json:"previous"`
+}
+
+// downloadPodLogs streams a zip of the logs of every container in a pod.
+// Query parameters: tail, previous.
+func (s *Server) downloadPodLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	s.streamLogBundle(w, r, vars["id"], "Pod", vars["namespace"], vars["name"])
+}
+
+// downloadWorkloadLogs streams a zip of the logs of every pod of a workload, or of every
+// pod a Kustomization or HelmRelease deploys. Query parameters: tail, previous.
+func (s *Server) downloadWorkloadLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	s.streamLogBundle(w, r, vars["id"], vars["kind"], vars["namespace"], vars["name"])
+}
+
+// streamLogBundle writes a bundle straight to the response, refusing bundles with more
+// containers than a request should wait on
+func (s *Server) streamLogBundle(w http.ResponseWriter, r *http.Request, clusterID, kind, namespace, name string) {
+	opts, err := logBundleOptions(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	containers, err := s.k8sClient.ListLogContainers(r.Context(), clusterID, kind, namespace, name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list containers: %v", err))
+		return
+	}
+	if len(containers) == 0 {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("No containers found for %s %s/%s", kind, namespace, name))
+		return
+	}
+	if limit := syncBundleContainers(); len(containers) > limit {
+		respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf(
+			"%d containers exceed the direct download limit of %d; request a background bundle with POST /api/v1/clusters/%s/log-bundles",
+			len(containers), limit, clusterID))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", logbundle.FileName(kind, namespace, name)))
+	w.WriteHeader(http.StatusOK)
+
+	manifest, err := logbundle.Write(r.Context(), s.k8sClient, clusterID, kind, namespace, name, containers, opts, w)
+	if err != nil {
+		// The response has started, so the client only sees a broken archive
+		logging.GetLogger().Warn("Log bundle download failed",
+			zap.String("cluster_id", clusterID),
+			zap.String("kind", kind),
+			zap.String("namespace", namespace),
+			zap.String("name", name),
+			zap.Error(err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "download_logs", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, "", "success",
+		fmt.Sprintf("Downloaded logs of %d containers (%d bytes)", len(manifest.Entries), manifest.TotalBytes))
+}
+
+// createLogBundle starts collecting a bundle in the background and returns the pending job
+func (s *Server) createLogBundle(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+
+	var req logBundleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Kind == "" || req.Namespace == "" || req.Name == "" {
+		respondError(w, http.StatusBadRequest, "kind, namespace and name are required")
+		return
+	}
+	if req.TailLines < 0 {
+		respondError(w, http.StatusBadRequest, "tail_lines must not be negative")
+		return
+	}
+
+	containers, err := s.k8sClient.ListLogContainers(r.Context(), clusterID, req.Kind, req.Namespace, req.Name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list containers: %v", err))
+		return
+	}
+	if len(containers) == 0 {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("No containers found for %s %s/%s", req.Kind, req.Namespace, req.Name))
+		return
+	}
+
+	opts := logbundle.OptionsFromEnv()
+	opts.TailLines = req.TailLines
+	opts.Previous = req.Previous
+
+	job := s.logBundles.Start(clusterID, req.Kind, req.Namespace, req.Name, requestActor(r), containers, opts)
+
+	s.logActivity(requestActor(r), "create_log_bundle", req.Kind, fmt.Sprintf("%s/%s", req.Namespace, req.Name), req.Name, clusterID, "", "success",
+		fmt.Sprintf("Started log bundle %s for %d containers", job.ID, len(containers)))
+
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// getLogBundle returns the status of a background bundle
+func (s *Server) getLogBundle(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.logBundles.Get(mux.Vars(r)["bundleId"])
+	if !ok || job.RequestedBy != requestActor(r) {
+		respondError(w, http.StatusNotFound, "Log bundle not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, job)
+}
+
+// downloadLogBundle serves the archive of a completed background bundle
+func (s *Server) downloadLogBundle(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["bundleId"]
+
+	job, ok := s.logBundles.Get(id)
+	if !ok || job.RequestedBy != requestActor(r) {
+		respondError(w, http.StatusNotFound, "Log bundle not found")
+		return
+	}
+	if job.Status != logbundle.JobCompleted {
+		respondError(w, http.StatusConflict, fmt.Sprintf("Log bundle is %s", job.Status))
+		return
+	}
+
+	file, job, err := s.logBundles.Open(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to open log bundle: %v", err))
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	if info, err := file.Stat(); err == nil {
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+// logBundleOptions reads the tail and previous query parameters of a direct download
+func logBundleOptions(r *http.Request) (logbundle.Options, error) {
+	opts := logbundle.OptionsFromEnv()
+	query := r.URL.Query()
+	opts.Previous = query.Get("previous") == "true"
+	if value := query.Get("tail"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return opts, fmt.Errorf("tail must be a positive integer")
+		}
+		opts.TailLines = parsed
+	}
+	return opts, nil
+}
+
+// requestActor identifies who made a request: the user's email or name, or "system" for
+// anonymous requests. It owns log bundles and is recorded as the user of activities.
+func requestActor(r *http.Request) string {
+	if userInfo, ok := r.Context().Value("user").(*auth.UserInfo); ok && userInfo != nil {
+		if userInfo.Email != "" {
+			return userInfo.Email
+		}
+		return userInfo.Username
+	}
+	return "system"
+}
+
+// syncBundleContainers returns the direct download container limit
+func syncBundleContainers() int {
+	if value := os.Getenv("LOG_BUNDLE_SYNC_MAX_CONTAINERS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultSyncBundleContainers
+}
+
+// cleanupLogBundles periodically removes expired background bundles
+func (s *Server) cleanupLogBundles() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.logBundles.CleanExpired()
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// searchFluxResourceLogs greps the logs of every pod a Kustomization or HelmRelease deploys
+// and returns the matches with context lines, grouped per pod and container.
+// Query parameters: q (required), regex, ignore_case, context, tail, max_matches, previous.
+func (s *Server) searchFluxResourceLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	kind := vars["kind"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if !k8s.OwnsWorkloads(kind) {
+		respondError(w, http.StatusBadRequest, "Log search is only available for Kustomizations and HelmReleases")
+		return
+	}
+
+	query := r.URL.Query()
+	opts := k8s.LogSearchOptions{
+		Query:      query.Get("q"),
+		Regex:      query.Get("regex") == "true",
+		IgnoreCase: query.Get("ignore_case") == "true",
+		Previous:   query.Get("previous") == "true",
+		Context:    2,
+	}
+	for param, target := range map[string]*int{"context": &opts.Context, "max_matches": &opts.MaxMatches} {
+		if value := query.Get(param); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("%s must be a non-negative integer", param))
+				return
+			}
+			*target = parsed
+		}
+	}
+	if value := query.Get("tail"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			respondError(w, http.StatusBadRequest, "tail must be a positive integer")
+			return
+		}
+		opts.TailLines = parsed
+	}
+	if err := opts.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.k8sClient.SearchFluxResourceLogs(r.Context(), clusterID, kind, namespace, name, opts)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to search logs: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
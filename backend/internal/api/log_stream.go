@@ -0,0 +1,162 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// defaultLogStreamTail is how many existing lines a log stream starts with
+const defaultLogStreamTail = 100
+
+// logStreamMessage is a frame sent over a pod log stream. An "end" frame is sent when the
+// log ends, either because follow is off or because the container stopped.
+type logStreamMessage struct {
+	Type      string `json:"type"` // line, end, error
+	Timestamp string `json:"timestamp,omitempty"`
+	Line      string `json:"line,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// streamPodLogs upgrades to a WebSocket and streams a container's log line by line.
+// Query parameters: container, follow (default true), tail (default 100, 0 for the whole
+// log), since_seconds, previous.
+func (s *Server) streamPodLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	container := k8s.LogContainer{Namespace: vars["namespace"], Pod: vars["name"], Container: r.URL.Query().Get("container")}
+
+	opts, err := logStreamOptions(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Open the log before upgrading so a missing pod or container is a plain HTTP error
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	stream, err := s.k8sClient.StreamContainerLogs(ctx, clusterID, container, opts)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to stream logs: %v", err))
+		return
+	}
+	defer stream.Close()
+
+	upgrader := websocket.Upgrader{CheckOrigin: s.checkEventStreamOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		return
+	}
+	defer conn.Close()
+
+	lines := make(chan logStreamMessage, 256)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stream)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- logLine(scanner.Text()):
+			case <-ctx.Done():
+				return
+			}
+		}
+		message := logStreamMessage{Type: "end"}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			message = logStreamMessage{Type: "error", Error: err.Error()}
+		}
+		select {
+		case lines <- message:
+		case <-ctx.Done():
+		}
+	}()
+
+	// The client only sends control frames; reading detects when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventStreamPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case message, ok := <-lines:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, "log ended"),
+					time.Now().Add(eventStreamWriteWait))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(eventStreamWriteWait))
+			if err := conn.WriteJSON(message); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventStreamWriteWait)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// logStreamOptions reads the follow, tail, since_seconds and previous query parameters
+func logStreamOptions(r *http.Request) (k8s.LogStreamOptions, error) {
+	query := r.URL.Query()
+	opts := k8s.LogStreamOptions{
+		Follow:    query.Get("follow") != "false",
+		Previous:  query.Get("previous") == "true",
+		TailLines: defaultLogStreamTail,
+	}
+	if value := query.Get("tail"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return opts, fmt.Errorf("tail must be a non-negative integer")
+		}
+		opts.TailLines = parsed
+	}
+	if value := query.Get("since_seconds"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return opts, fmt.Errorf("since_seconds must be a positive integer")
+		}
+		opts.SinceSeconds = parsed
+	}
+	if opts.Follow && opts.Previous {
+		return opts, fmt.Errorf("the previous container's log cannot be followed")
+	}
+	return opts, nil
+}
+
+// logLine splits the timestamp the API server prefixes each line with from the line
+func logLine(text string) logStreamMessage {
+	if timestamp, line, ok := strings.Cut(text, " "); ok {
+		if _, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			return logStreamMessage{Type: "line", Timestamp: timestamp, Line: line}
+		}
+	}
+	return logStreamMessage{Type: "line", Line: text}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/metrics"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
@@ -26,6 +27,12 @@ func loggingMiddleware(next http.Handler) http.Handler {
 		// Create logger with request context
 		logger := logging.WithRequestID(requestID)
 		
+		// Expose request ID to handlers and clients
+		w.Header().Set("X-Request-ID", requestID)
+		r = r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, requestID))
+		served := &servedRoute{}
+		r = r.WithContext(context.WithValue(r.Context(), servedRouteContextKey{}, served))
+		
 		// Wrap response writer to capture status code
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 		
@@ -43,16 +50,17 @@ func loggingMiddleware(next http.Handler) http.Handler {
 		// Calculate duration
 		duration := time.Since(start)
 		
-		// Update metrics
+		// Update metrics, labelled by route template to keep cardinality bounded
+		routePath := metricRoute(r, served, wrapped.statusCode)
 		metrics.HTTPRequestsTotal.WithLabelValues(
 			r.Method,
-			r.URL.Path,
+			routePath,
 			strconv.Itoa(wrapped.statusCode),
 		).Inc()
 		
 		metrics.HTTPRequestDuration.WithLabelValues(
 			r.Method,
-			r.URL.Path,
+			routePath,
 		).Observe(duration.Seconds())
 		
 		// Log response
@@ -65,6 +73,65 @@ func loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// routeTemplate returns the matched mux route template for metric labels.
+// Raw paths embed cluster IDs and pod names and must never be used as labels.
+// Requests forwarded from /api/v2 report the v1 route serving them.
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if compat, ok := r.Context().Value(v1CompatRouteContextKey{}).(*mux.Route); ok {
+		route = compat
+	}
+	if route == nil {
+		return "unmatched"
+	}
+	if template, err := route.GetPathTemplate(); err == nil {
+		return template
+	}
+	if template, err := route.GetPathRegexp(); err == nil {
+		return template
+	}
+	return "unmatched"
+}
+
+// servedRouteContextKey is the context key holding the *servedRoute of a request
+type servedRouteContextKey struct{}
+
+// servedRoute records the v1 route v1Compat forwards a /api/v2 request to, which the
+// request loggingMiddleware sees does not carry
+type servedRoute struct {
+	route *mux.Route
+}
+
+// setServedRoute records the route serving a request for its metrics
+func setServedRoute(r *http.Request, route *mux.Route) {
+	if served, ok := r.Context().Value(servedRouteContextKey{}).(*servedRoute); ok {
+		served.route = route
+	}
+}
+
+// metricRoute returns the path label of a request's metrics: the template of the route
+// that served it, or unmatched when only a fallback route answered with 404, so unknown
+// paths do not each add a series
+func metricRoute(r *http.Request, served *servedRoute, status int) string {
+	if served.route != nil {
+		r = r.WithContext(context.WithValue(r.Context(), v1CompatRouteContextKey{}, served.route))
+	}
+	template := routeTemplate(r)
+	if status == http.StatusNotFound && (template == "/" || template == apiV2Prefix+"/") {
+		return "unmatched"
+	}
+	return template
+}
+
+// requestIDContextKey is the context key holding the request ID
+type requestIDContextKey struct{}
+
+// requestIDFromContext returns the request ID assigned by loggingMiddleware
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
 // responseWriter wraps http.ResponseWriter to capture status code
 type responseWriter struct {
 	http.ResponseWriter
@@ -76,6 +143,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController, e.g. for WebSocket hijacking
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // timeoutMiddleware adds request timeout
 func timeoutMiddleware(next http.Handler) http.Handler {
 	// Get timeout from env or default to 30 seconds
@@ -89,6 +161,12 @@ func timeoutMiddleware(next http.Handler) http.Handler {
 	timeout := time.Duration(timeoutSeconds) * time.Second
 	
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Long-lived WebSocket connections and reconcile waits manage their own deadlines
+		if isWebSocketUpgrade(r) || isReconcileWait(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(r.Context(), timeout)
 		defer cancel()
 		
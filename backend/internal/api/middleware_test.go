@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/metrics"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/service/fake"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHTTPMetricsAreLabelledByRouteTemplate(t *testing.T) {
+	clusters := fake.NewClusterService(
+		models.Cluster{ID: "cluster-a", Name: "a"},
+		models.Cluster{ID: "cluster-b", Name: "b"},
+		models.Cluster{ID: "cluster-c", Name: "c"},
+	)
+	ts := newTestServer(t, clusters, nil, nil)
+	metrics.HTTPRequestsTotal.Reset()
+
+	// Requests for distinct clusters and pods, including ones forwarded from /api/v2,
+	// must each collapse into the series of their route template
+	requests := map[string][]string{
+		"/api/v1/clusters/{id}": {
+			"/api/v1/clusters/cluster-a", "/api/v1/clusters/cluster-b", "/api/v1/clusters/cluster-c",
+		},
+		"/api/v1/clusters/{id}/pods/{namespace}/{name}/containers": {
+			"/api/v1/clusters/cluster-a/pods/default/web-1/containers",
+			"/api/v1/clusters/cluster-b/pods/default/web-2/containers",
+			"/api/v1/clusters/cluster-c/pods/apps/worker-7f9c/containers",
+		},
+		"/api/v1/clusters/{id}/flux/stats": {
+			"/api/v2/clusters/cluster-a/flux/stats", "/api/v2/clusters/cluster-b/flux/stats",
+		},
+		"unmatched": {
+			"/api/v1/no-such-endpoint", "/api/v2/no-such-endpoint/cluster-a", "/no-such-page",
+		},
+	}
+
+	for template, paths := range requests {
+		status := 0
+		for _, path := range paths {
+			rec := ts.do(t, http.MethodGet, path, nil)
+			if status != 0 && rec.Code != status {
+				t.Fatalf("%s answered %d, other requests for %s %d", path, rec.Code, template, status)
+			}
+			status = rec.Code
+		}
+		if template == "unmatched" && status != http.StatusNotFound {
+			t.Fatalf("unknown paths answered %d, want 404", status)
+		}
+
+		got := testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, template, strconv.Itoa(status)))
+		if got != float64(len(paths)) {
+			t.Errorf("%s %d counted %v requests, want %d", template, status, got, len(paths))
+		}
+	}
+
+	if series := testutil.CollectAndCount(metrics.HTTPRequestsTotal); series != len(requests) {
+		t.Errorf("%d series of %s, want one per route template (%d)", series, metrics.Name("http_requests_total"), len(requests))
+	}
+}
+
+func TestMetricRouteLabelsFallbackNotFound(t *testing.T) {
+	ts := newTestServer(t, nil, nil, nil)
+	metrics.HTTPRequestsTotal.Reset()
+
+	for i := 0; i < 5; i++ {
+		ts.do(t, http.MethodGet, fmt.Sprintf("/api/v1/unknown-%d", i), nil)
+	}
+
+	if got := testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "unmatched", "404")); got != 5 {
+		t.Errorf("unmatched 404s = %v, want 5", got)
+	}
+	if series := testutil.CollectAndCount(metrics.HTTPRequestsTotal); series != 1 {
+		t.Errorf("%d series, want only the unmatched one", series)
+	}
+}
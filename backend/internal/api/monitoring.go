@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/metrics"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// alertThresholds loads alert thresholds from settings, falling back to the defaults
+func (s *Server) alertThresholds() metrics.AlertThresholds {
+	thresholds := metrics.DefaultAlertThresholds()
+
+	values := map[string]*float64{
+		"alert_http_error_rate_percent":   &thresholds.HTTPErrorRatePercent,
+		"alert_http_latency_p95_seconds":  &thresholds.HTTPLatencyP95Seconds,
+		"alert_sync_errors_per_15m":       &thresholds.SyncErrorsPer15m,
+		"alert_sync_duration_p95_seconds": &thresholds.SyncDurationP95Seconds,
+		"alert_not_ready_resources":       &thresholds.NotReadyResources,
+	}
+
+	keys := []string{"alert_for_minutes"}
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	var settings []models.Setting
+	if err := s.db.Where("setting_key IN ?", keys).Find(&settings).Error; err != nil {
+		return thresholds
+	}
+
+	for _, setting := range settings {
+		if setting.Key == "alert_for_minutes" {
+			if minutes, err := strconv.Atoi(setting.Value); err == nil && minutes > 0 {
+				thresholds.ForMinutes = minutes
+			}
+			continue
+		}
+		if target, ok := values[setting.Key]; ok {
+			if value, err := strconv.ParseFloat(setting.Value, 64); err == nil && value >= 0 {
+				*target = value
+			}
+		}
+	}
+
+	return thresholds
+}
+
+// getPrometheusRules renders recommended PrometheusRule YAML for the orchestrator's metrics
+func (s *Server) getPrometheusRules(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		namespace = "monitoring"
+	}
+
+	data, err := metrics.PrometheusRuleYAML(namespace, s.alertThresholds())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to render alert rules: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", "attachment; filename=flux-orchestrator-rules.yaml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// listGrafanaDashboards lists the bundled Grafana dashboards
+func (s *Server) listGrafanaDashboards(w http.ResponseWriter, r *http.Request) {
+	names, err := metrics.DashboardNames()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list dashboards: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"metric_prefix": metrics.Prefix,
+		"dashboards":    names,
+	})
+}
+
+// getGrafanaDashboard returns a Grafana dashboard with this installation's metric prefix
+func (s *Server) getGrafanaDashboard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	data, err := metrics.Dashboard(name)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "Dashboard not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Query().Get("download") == "true" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", name))
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
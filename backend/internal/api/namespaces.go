@@ -0,0 +1,269 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Permissions required to create and delete namespaces
+const (
+	namespaceCreatePermission = "namespace.create"
+	namespaceDeletePermission = "namespace.delete"
+)
+
+// Settings holding the namespace guardrails
+const (
+	namespaceNamePatternSetting = "namespace_name_pattern" // regular expression created names must match
+	namespaceProtectedSetting   = "namespace_protected"    // comma-separated, path.Match patterns allowed
+)
+
+// systemNamespaces are protected whatever the settings say
+var systemNamespaces = []string{"default", "kube-system", "kube-public", "kube-node-lease", "flux-system"}
+
+// namespacePolicy is the set of guardrails every namespace change must pass
+type namespacePolicy struct {
+	NamePattern *regexp.Regexp
+	Protected   []string
+}
+
+// namespacePolicy loads the namespace guardrails from settings. An unset name pattern allows
+// any valid name; an invalid one blocks every creation until it is fixed.
+func (s *Server) namespacePolicy() (namespacePolicy, error) {
+	policy := namespacePolicy{Protected: append([]string{}, systemNamespaces...)}
+
+	var settings []models.Setting
+	if err := s.db.Where("setting_key IN ?", []string{namespaceNamePatternSetting, namespaceProtectedSetting}).Find(&settings).Error; err != nil {
+		return policy, fmt.Errorf("failed to load namespace settings: %w", err)
+	}
+
+	for _, setting := range settings {
+		switch setting.Key {
+		case namespaceNamePatternSetting:
+			pattern := strings.TrimSpace(setting.Value)
+			if pattern == "" {
+				continue
+			}
+			// Anchored so the whole name must follow the convention
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return policy, fmt.Errorf("invalid %s setting: %w", namespaceNamePatternSetting, err)
+			}
+			policy.NamePattern = re
+		case namespaceProtectedSetting:
+			for _, namespace := range strings.Split(setting.Value, ",") {
+				if namespace = strings.TrimSpace(namespace); namespace != "" {
+					policy.Protected = append(policy.Protected, namespace)
+				}
+			}
+		}
+	}
+
+	return policy, nil
+}
+
+// protected returns the pattern protecting a namespace, or ""
+func (p namespacePolicy) protected(name string) string {
+	for _, pattern := range p.Protected {
+		if matched, _ := path.Match(pattern, name); matched {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// checkCreate returns why creating a namespace is not allowed, or nil
+func (p namespacePolicy) checkCreate(req k8s.NamespaceRequest) error {
+	if errs := validation.IsDNS1123Label(req.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s", req.Name, strings.Join(errs, "; "))
+	}
+	if p.NamePattern != nil && !p.NamePattern.MatchString(req.Name) {
+		return fmt.Errorf("name %s does not match the naming convention %s (%s setting)", req.Name, p.NamePattern.String(), namespaceNamePatternSetting)
+	}
+	if pattern := p.protected(req.Name); pattern != "" {
+		return fmt.Errorf("namespace %s is protected (%s)", req.Name, pattern)
+	}
+	for key, value := range req.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value for label %s: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	for key := range req.Annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// checkDelete returns why deleting a namespace is not allowed, or nil
+func (p namespacePolicy) checkDelete(name string) error {
+	if pattern := p.protected(name); pattern != "" {
+		return fmt.Errorf("namespace %s is protected (%s)", name, pattern)
+	}
+	return nil
+}
+
+// namespaceView is a namespace with the number of stored Flux resources in it
+type namespaceView struct {
+	k8s.NamespaceInfo
+	FluxResources int `json:"flux_resources"`
+}
+
+// listNamespaces lists the namespaces of a cluster with their status and labels, for
+// namespace filters. Query parameters: labelSelector and fieldSelector.
+func (s *Server) listNamespaces(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+
+	filter, err := listFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	namespaces, err := s.k8sClient.ListNamespaces(r.Context(), clusterID, filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to list namespaces: %v", err))
+		return
+	}
+
+	resources, err := s.resourceService.ListByCluster(clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list resources")
+		return
+	}
+	counts := map[string]int{}
+	for _, res := range resources {
+		counts[res.Namespace]++
+	}
+
+	views := make([]namespaceView, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		views = append(views, namespaceView{NamespaceInfo: namespace, FluxResources: counts[namespace.Name]})
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"namespaces": views,
+		"count":      len(views),
+	})
+}
+
+// createNamespace creates a namespace, for example to bootstrap an app before pointing Flux
+// at it. Requires the namespace.create permission; the name must follow the naming
+// convention and not be protected. Every attempt is recorded in the activity log.
+func (s *Server) createNamespace(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+	clusterName := s.clusterService.Name(clusterID)
+
+	var req k8s.NamespaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := s.requirePermission(r, namespaceCreatePermission); err != nil {
+		s.logActivity(requestActor(r), "create", "Namespace", req.Name, req.Name, clusterID, clusterName, "failed", fmt.Sprintf("Denied namespace creation: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Namespace creation not allowed: %v", err))
+		return
+	}
+
+	policy, err := s.namespacePolicy()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := policy.checkCreate(req); err != nil {
+		s.logActivity(requestActor(r), "create", "Namespace", req.Name, req.Name, clusterID, clusterName, "failed", fmt.Sprintf("Blocked by namespace policy: %v", err))
+		respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Namespace creation blocked: %v", err))
+		return
+	}
+
+	if err := s.k8sClient.CreateNamespace(s.provenanceContext(r, "create"), clusterID, req); err != nil {
+		s.logActivity(requestActor(r), "create", "Namespace", req.Name, req.Name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		if errors.Is(err, k8s.ErrNamespaceExists) {
+			respondError(w, http.StatusConflict, fmt.Sprintf("Namespace %s already exists", req.Name))
+			return
+		}
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create namespace: %v", err))
+		return
+	}
+	s.logActivity(requestActor(r), "create", "Namespace", req.Name, req.Name, clusterID, clusterName, "success",
+		fmt.Sprintf("Created namespace %s requested by %s", req.Name, requestActor(r)))
+
+	respondJSON(w, http.StatusCreated, map[string]string{"message": "Namespace created successfully", "name": req.Name})
+}
+
+// deleteNamespace deletes a namespace and everything in it. Requires the namespace.delete
+// permission; protected namespaces and namespaces holding Flux resources are refused.
+// Every attempt is recorded in the activity log.
+func (s *Server) deleteNamespace(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	name := vars["namespace"]
+	clusterName := s.clusterService.Name(clusterID)
+
+	if err := s.requirePermission(r, namespaceDeletePermission); err != nil {
+		s.logActivity(requestActor(r), "delete", "Namespace", name, name, clusterID, clusterName, "failed", fmt.Sprintf("Denied namespace deletion: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Namespace deletion not allowed: %v", err))
+		return
+	}
+
+	policy, err := s.namespacePolicy()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := policy.checkDelete(name); err != nil {
+		s.logActivity(requestActor(r), "delete", "Namespace", name, name, clusterID, clusterName, "failed", fmt.Sprintf("Blocked by namespace policy: %v", err))
+		respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Namespace deletion blocked: %v", err))
+		return
+	}
+
+	// Deleting a namespace Flux still manages would remove the resources from under it
+	resources, err := s.resourceService.ListByCluster(clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list resources")
+		return
+	}
+	var held int
+	for _, res := range resources {
+		if res.Namespace == name {
+			held++
+		}
+	}
+	if held > 0 {
+		err := fmt.Errorf("namespace %s holds %d Flux resources; remove them first", name, held)
+		s.logActivity(requestActor(r), "delete", "Namespace", name, name, clusterID, clusterName, "failed", fmt.Sprintf("Blocked by namespace policy: %v", err))
+		respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Namespace deletion blocked: %v", err))
+		return
+	}
+
+	if err := s.k8sClient.DeleteNamespace(s.provenanceContext(r, "delete"), clusterID, name); err != nil {
+		s.logActivity(requestActor(r), "delete", "Namespace", name, name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		switch {
+		case errors.Is(err, k8s.ErrNamespaceNotFound):
+			respondError(w, http.StatusNotFound, fmt.Sprintf("Namespace %s not found", name))
+		case errors.Is(err, k8s.ErrNamespaceTerminating):
+			respondError(w, http.StatusConflict, fmt.Sprintf("Namespace %s is already being deleted", name))
+		default:
+			respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete namespace: %v", err))
+		}
+		return
+	}
+	s.logActivity(requestActor(r), "delete", "Namespace", name, name, clusterID, clusterName, "success",
+		fmt.Sprintf("Deleted namespace %s requested by %s", name, requestActor(r)))
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Namespace deletion started"})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// resourceDeletePermission is required to delete Flux resources
+const resourceDeletePermission = "resource.delete"
+
+// deleteNotificationResource deletes an Alert, Provider or Receiver. Like creating them, it
+// requires a permission: resource.delete.
+func (s *Server) deleteNotificationResource(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	kind := vars["kind"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if !k8s.IsNotificationKind(kind) {
+		respondError(w, http.StatusBadRequest, "Only Alerts, Providers and Receivers can be deleted")
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	if err := s.requirePermission(r, resourceDeletePermission); err != nil {
+		s.logActivity(requestActor(r), "delete", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "failed", fmt.Sprintf("Denied delete: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Delete not allowed: %v", err))
+		return
+	}
+
+	ctx := s.provenanceContext(r, "delete")
+	if err := s.resourceService.Delete(ctx, clusterID, kind, namespace, name); err != nil {
+		s.logActivity(requestActor(r), "delete", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		respondServiceError(w, err, "Resource not found", fmt.Sprintf("Failed to delete resource: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "delete", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "success", fmt.Sprintf("Deleted %s %s/%s", kind, namespace, name))
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Resource deleted successfully"})
+}
@@ -0,0 +1,336 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// onboardingSettingKey is the setting holding the persisted onboarding progress
+const onboardingSettingKey = "onboarding_progress"
+
+// Onboarding step states
+const (
+	stepComplete = "complete"
+	stepPending  = "pending"
+	stepSkipped  = "skipped"
+)
+
+// onboardingStepDef describes a setup step; check reports whether it is done and why
+type onboardingStepDef struct {
+	ID          string
+	Title       string
+	Description string
+	Optional    bool
+	check       func(s *Server) (bool, string)
+}
+
+// onboardingSteps are presented to new installs in this order
+var onboardingSteps = []onboardingStepDef{
+	{
+		ID:          "encryption",
+		Title:       "Encryption key",
+		Description: "Set ENCRYPTION_KEY to a Fernet key used to encrypt kubeconfigs and credentials at rest.",
+		check:       (*Server).checkOnboardingEncryption,
+	},
+	{
+		ID:          "cluster",
+		Title:       "Add your first cluster",
+		Description: "Register a Kubernetes cluster running Flux by pasting its kubeconfig or importing it from Azure.",
+		check:       (*Server).checkOnboardingCluster,
+	},
+	{
+		ID:          "oauth",
+		Title:       "Configure OAuth",
+		Description: "Require users to sign in with GitHub or Entra ID before they can manage clusters.",
+		Optional:    true,
+		check:       (*Server).checkOnboardingOAuth,
+	},
+	{
+		ID:          "webhook",
+		Title:       "Add a webhook",
+		Description: "Send cluster health, sync and reconciliation events to Slack, Teams or any HTTP endpoint.",
+		Optional:    true,
+		check:       (*Server).checkOnboardingWebhook,
+	},
+}
+
+// onboardingProgress is the part of the onboarding state that cannot be derived
+// from the configuration and is stored in the settings table
+type onboardingProgress struct {
+	Skipped     []string   `json:"skipped"`
+	DismissedAt *time.Time `json:"dismissed_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// OnboardingStep is a setup step with its current state
+type OnboardingStep struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Optional    bool   `json:"optional"`
+	Status      string `json:"status"` // complete, pending, skipped
+	Message     string `json:"message"`
+}
+
+// OnboardingState is the first-run setup flow as shown to the user
+type OnboardingState struct {
+	Steps       []OnboardingStep `json:"steps"`
+	CurrentStep string           `json:"current_step,omitempty"`
+	Completed   bool             `json:"completed"`
+	Dismissed   bool             `json:"dismissed"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// getOnboarding returns the setup steps and which of them are done
+func (s *Server) getOnboarding(w http.ResponseWriter, r *http.Request) {
+	state, err := s.onboardingState()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, state)
+}
+
+// updateOnboardingStep skips an optional step or brings a skipped step back
+func (s *Server) updateOnboardingStep(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	stepID := vars["step"]
+
+	var req struct {
+		Skipped bool `json:"skipped"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	step := findOnboardingStep(stepID)
+	if step == nil {
+		respondError(w, http.StatusNotFound, "Onboarding step not found")
+		return
+	}
+	if req.Skipped && !step.Optional {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Step %s is required and cannot be skipped", stepID))
+		return
+	}
+
+	progress, err := s.loadOnboardingProgress()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	skipped := make([]string, 0, len(progress.Skipped)+1)
+	for _, id := range progress.Skipped {
+		if id != stepID {
+			skipped = append(skipped, id)
+		}
+	}
+	if req.Skipped {
+		skipped = append(skipped, stepID)
+	}
+	progress.Skipped = skipped
+	if err := s.saveOnboardingProgress(progress); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.getOnboarding(w, r)
+}
+
+// generateEncryptionKey returns a new Fernet key for ENCRYPTION_KEY. The key is not stored
+// or applied; it is meant for preparing the secret of a new install.
+func (s *Server) generateEncryptionKey(w http.ResponseWriter, r *http.Request) {
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to generate key: %v", err))
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"key": key})
+}
+
+// dismissOnboarding hides the setup flow without completing it
+func (s *Server) dismissOnboarding(w http.ResponseWriter, r *http.Request) {
+	progress, err := s.loadOnboardingProgress()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	now := time.Now()
+	progress.DismissedAt = &now
+	if err := s.saveOnboardingProgress(progress); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.getOnboarding(w, r)
+}
+
+// resetOnboarding clears skipped steps and shows the setup flow again
+func (s *Server) resetOnboarding(w http.ResponseWriter, r *http.Request) {
+	if err := s.saveOnboardingProgress(&onboardingProgress{}); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.getOnboarding(w, r)
+}
+
+// onboardingState evaluates every step against the current configuration.
+// The first time all steps are complete or skipped the completion time is persisted.
+func (s *Server) onboardingState() (*OnboardingState, error) {
+	progress, err := s.loadOnboardingProgress()
+	if err != nil {
+		return nil, err
+	}
+	skipped := make(map[string]bool, len(progress.Skipped))
+	for _, id := range progress.Skipped {
+		skipped[id] = true
+	}
+
+	state := &OnboardingState{
+		Steps:     make([]OnboardingStep, 0, len(onboardingSteps)),
+		Dismissed: progress.DismissedAt != nil,
+	}
+	for _, def := range onboardingSteps {
+		done, message := def.check(s)
+		step := OnboardingStep{
+			ID:          def.ID,
+			Title:       def.Title,
+			Description: def.Description,
+			Optional:    def.Optional,
+			Status:      stepPending,
+			Message:     message,
+		}
+		switch {
+		case done:
+			step.Status = stepComplete
+		case def.Optional && skipped[def.ID]:
+			step.Status = stepSkipped
+		case state.CurrentStep == "":
+			state.CurrentStep = def.ID
+		}
+		state.Steps = append(state.Steps, step)
+	}
+
+	state.Completed = state.CurrentStep == ""
+	if state.Completed && progress.CompletedAt == nil {
+		now := time.Now()
+		progress.CompletedAt = &now
+		if err := s.saveOnboardingProgress(progress); err != nil {
+			return nil, err
+		}
+	}
+	state.CompletedAt = progress.CompletedAt
+	return state, nil
+}
+
+// findOnboardingStep returns the step definition with the given ID
+func findOnboardingStep(id string) *onboardingStepDef {
+	for i := range onboardingSteps {
+		if onboardingSteps[i].ID == id {
+			return &onboardingSteps[i]
+		}
+	}
+	return nil
+}
+
+// loadOnboardingProgress reads the stored progress; a missing setting is an empty progress
+func (s *Server) loadOnboardingProgress() (*onboardingProgress, error) {
+	progress := &onboardingProgress{}
+
+	var setting models.Setting
+	result := s.db.Where("setting_key = ?", onboardingSettingKey).Limit(1).Find(&setting)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to load onboarding progress: %w", result.Error)
+	}
+	if result.RowsAffected == 0 || setting.Value == "" {
+		return progress, nil
+	}
+	if err := json.Unmarshal([]byte(setting.Value), progress); err != nil {
+		return nil, fmt.Errorf("failed to parse onboarding progress: %w", err)
+	}
+	return progress, nil
+}
+
+// saveOnboardingProgress stores the progress in the settings table
+func (s *Server) saveOnboardingProgress(progress *onboardingProgress) error {
+	value, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode onboarding progress: %w", err)
+	}
+
+	setting := models.Setting{Key: onboardingSettingKey, Value: string(value)}
+	if err := s.db.Where(models.Setting{Key: onboardingSettingKey}).Assign(models.Setting{Value: string(value)}).FirstOrCreate(&setting).Error; err != nil {
+		return fmt.Errorf("failed to save onboarding progress: %w", err)
+	}
+	return nil
+}
+
+// checkOnboardingEncryption round-trips a value and decrypts a stored kubeconfig to prove
+// the key matches existing data
+func (s *Server) checkOnboardingEncryption() (bool, string) {
+	if s.encryptor == nil {
+		return false, "ENCRYPTION_KEY is not configured; generate one with go run ./tools/generate-key"
+	}
+
+	const sample = "flux-orchestrator-onboarding"
+	encrypted, err := s.encryptor.Encrypt(sample)
+	if err != nil {
+		return false, fmt.Sprintf("Failed to encrypt a sample value: %v", err)
+	}
+	if decrypted, err := s.encryptor.Decrypt(encrypted); err != nil || decrypted != sample {
+		return false, "A sample value did not decrypt back to its original value"
+	}
+
+	var cluster models.Cluster
+	result := s.db.Select("id", "kubeconfig").Where("kubeconfig != ?", "").Limit(1).Find(&cluster)
+	if result.Error == nil && result.RowsAffected > 0 {
+		if _, err := s.encryptor.Decrypt(cluster.KubeConfig); err != nil {
+			return false, fmt.Sprintf("ENCRYPTION_KEY does not decrypt the stored kubeconfig of cluster %s", cluster.ID)
+		}
+	}
+	return true, "Encryption key is valid"
+}
+
+// checkOnboardingCluster requires at least one active cluster
+func (s *Server) checkOnboardingCluster() (bool, string) {
+	var count int64
+	if err := s.db.Model(&models.Cluster{}).Where("archived = ?", false).Count(&count).Error; err != nil {
+		return false, fmt.Sprintf("Failed to count clusters: %v", err)
+	}
+	if count == 0 {
+		return false, "No clusters registered yet"
+	}
+	return true, fmt.Sprintf("%d clusters registered", count)
+}
+
+// checkOnboardingOAuth reports whether sign-in is enforced
+func (s *Server) checkOnboardingOAuth() (bool, string) {
+	if s.proxyAuth != nil {
+		return true, "Sign-in is enforced by the authenticating proxy"
+	}
+	if s.authEnabled {
+		return true, "OAuth sign-in is enabled"
+	}
+
+	var count int64
+	s.db.Model(&models.OAuthProvider{}).Where("enabled = ?", true).Count(&count)
+	if count > 0 {
+		return false, fmt.Sprintf("%d OAuth providers configured; set OAUTH_ENABLED=true and restart to require sign-in", count)
+	}
+	return false, "Set OAUTH_ENABLED=true with an OAuth client ID and secret, or add a provider under Settings"
+}
+
+// checkOnboardingWebhook reports whether webhook notifications are configured
+func (s *Server) checkOnboardingWebhook() (bool, string) {
+	if s.webhooks.Enabled() {
+		return true, fmt.Sprintf("%d webhook endpoints configured", s.webhooks.EndpointCount())
+	}
+	return false, "Set WEBHOOK_URLS to receive notifications"
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// patchContentTypes are the Content-Types that send an update as a patch instead of a JSON
+// object to server-side apply
+var patchContentTypes = map[string]types.PatchType{
+	string(types.JSONPatchType):           types.JSONPatchType,
+	string(types.MergePatchType):          types.MergePatchType,
+	string(types.StrategicMergePatchType): types.StrategicMergePatchType,
+}
+
+// updateBody is the body of a resource update
+type updateBody struct {
+	// PatchType is the patch format of Raw, or empty for a JSON object to server-side apply
+	PatchType types.PatchType
+	Raw       []byte
+	// Fields holds the top-level fields the update changes, shaped like a JSON object body,
+	// for the update policies and the audit log. For a JSON patch, a field's value is only
+	// known when an operation sets the whole field.
+	Fields map[string]interface{}
+}
+
+// jsonPatchOperation is one operation of an RFC 6902 JSON patch
+type jsonPatchOperation struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	From string `json:"from"`
+	// Value is left raw so it can tell an explicit null from a missing value
+	Value json.RawMessage `json:"value"`
+}
+
+// decodeUpdate reads an update body. Its Content-Type picks the format: a JSON patch, a
+// JSON merge patch, a strategic merge patch, or otherwise a JSON object.
+// It writes the error response and returns false when the body is rejected.
+func decodeUpdate(w http.ResponseWriter, r *http.Request) (*updateBody, bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	body := &updateBody{PatchType: patchContentTypes[mediaType]}
+
+	data, ok := readJSONBody(w, r)
+	if !ok {
+		return nil, false
+	}
+	body.Raw = data
+
+	if body.PatchType != types.JSONPatchType {
+		if err := json.Unmarshal(data, &body.Fields); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return nil, false
+		}
+		if body.PatchType != "" {
+			// Replacing or deleting a whole top-level field would hide the fields it changes
+			for key, value := range body.Fields {
+				if _, ok := value.(map[string]interface{}); !ok {
+					respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid patch: %s must be an object of the fields to change", key))
+					return nil, false
+				}
+			}
+		}
+		return body, true
+	}
+
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(data, &operations); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body: a JSON patch is an array of operations")
+		return nil, false
+	}
+	fields, err := jsonPatchFields(operations)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid JSON patch: %v", err))
+		return nil, false
+	}
+	body.Fields = fields
+	return body, true
+}
+
+// jsonPatchFields returns the top-level fields the operations of a JSON patch change,
+// shaped like a JSON object body. Every path must point into a field, such as
+// /spec/replicas, so the update policies see which fields are touched.
+func jsonPatchFields(operations []jsonPatchOperation) (map[string]interface{}, error) {
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("no operations")
+	}
+
+	fields := map[string]interface{}{}
+	touch := func(op jsonPatchOperation, path string) error {
+		segments := strings.Split(path, "/")
+		if len(segments) < 3 || segments[0] != "" || segments[1] == "" || segments[2] == "" {
+			return fmt.Errorf("%s %q must point into a field, such as /spec/replicas", op.Op, path)
+		}
+		key, field := unescapeJSONPointer(segments[1]), unescapeJSONPointer(segments[2])
+
+		values, ok := fields[key].(map[string]interface{})
+		if !ok {
+			values = map[string]interface{}{}
+			fields[key] = values
+		}
+		var value interface{}
+		if len(segments) == 3 && (op.Op == "add" || op.Op == "replace") && path == op.Path {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return fmt.Errorf("%s %q has an invalid value", op.Op, path)
+			}
+		}
+		values[field] = value
+		return nil
+	}
+
+	for _, op := range operations {
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%s %q has no value", op.Op, op.Path)
+			}
+		case "remove", "copy":
+		case "move":
+			// Moving removes the source field
+			if err := touch(op, op.From); err != nil {
+				return nil, err
+			}
+		case "test":
+			// Tests change nothing
+			continue
+		default:
+			return nil, fmt.Errorf("unknown operation %q", op.Op)
+		}
+		if err := touch(op, op.Path); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// unescapeJSONPointer decodes a JSON pointer segment
+func unescapeJSONPointer(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+}
+
+// checkUpdate applies the update policies to an update: the allowlist of editable kinds and
+// spec fields, the secret.update permission for Secrets, and the scale guardrails for
+// spec.replicas. It writes the response and returns false when the update is blocked.
+func (s *Server) checkUpdate(w http.ResponseWriter, r *http.Request, clusterID, kind, namespace, name string, body *updateBody) bool {
+	var policyErr error
+	if kind == "Secret" {
+		policyErr = s.checkSecretUpdate(r, body.Fields)
+	} else {
+		policyErr = s.specUpdatePolicy().check(kind, body.Fields)
+	}
+	if policyErr != nil {
+		fields := strings.Join(patchFields(body.Fields, "spec", "data", "stringData"), ", ")
+		s.logActivity(requestActor(r), "update", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, s.clusterService.Name(clusterID), "failed", fmt.Sprintf("Blocked edit of %s: %v", fields, policyErr))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Update blocked: %v", policyErr))
+		return false
+	}
+
+	// Setting spec.replicas is a scale and passes the same guardrails
+	if replicas, ok, err := patchReplicas(body.Fields); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return false
+	} else if ok {
+		if err := s.scalePolicy().check(namespace, replicas); err != nil {
+			respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Update blocked: %v", err))
+			return false
+		}
+	}
+	return true
+}
+
+// logUpdate records the outcome of an update. Like the message, the details name the
+// changed fields but never their values.
+func (s *Server) logUpdate(r *http.Request, clusterID, kind, namespace, name string, body *updateBody, force bool, err error) {
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+	if err != nil {
+		s.logActivity(requestActor(r), "update", kind, resourceID, name, clusterID, s.clusterService.Name(clusterID), "failed", fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	fields := patchFields(body.Fields, "spec", "data", "stringData")
+	message := fmt.Sprintf("Updated %s", strings.Join(fields, ", "))
+	if body.PatchType != "" {
+		message += fmt.Sprintf(" (%s)", body.PatchType)
+	}
+	if force {
+		message += " (forced)"
+	}
+	changes := &activityChangeSet{}
+	changes.param("fields", fields)
+	changes.param("patch_type", body.PatchType)
+	changes.param("force", force)
+	s.logActivityDetails(requestActor(r), "update", kind, resourceID, name, clusterID, s.clusterService.Name(clusterID), "success", message, changes)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/rbac"
+)
+
+// requestEmail returns the email of the requesting user, empty for anonymous requests
+func requestEmail(r *http.Request) string {
+	if userInfo, ok := r.Context().Value("user").(*auth.UserInfo); ok && userInfo != nil {
+		return userInfo.Email
+	}
+	return ""
+}
+
+// getMyPermissions returns the roles of the requesting user and the permissions they
+// grant, with the roles granting each. Anonymous requests get an empty report.
+func (s *Server) getMyPermissions(w http.ResponseWriter, r *http.Request) {
+	email := requestEmail(r)
+	if email == "" {
+		respondJSON(w, http.StatusOK, rbac.UserAccess{Roles: []rbac.RoleRef{}, Permissions: []rbac.PermissionGrant{}})
+		return
+	}
+
+	access, err := s.rbacManager.UserAccess(email)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to load permissions: %v", err))
+		return
+	}
+	respondJSON(w, http.StatusOK, access)
+}
+
+// explainMyPermission reports whether the requesting user holds a permission, given as
+// permission=resource.action or as resource and action, and what decided it: the roles
+// granting it, or why none do along with the roles that would. While authentication is
+// disabled everything is allowed.
+func (s *Server) explainMyPermission(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	permID := query.Get("permission")
+	if permID == "" {
+		resource, action := query.Get("resource"), query.Get("action")
+		if resource == "" || action == "" {
+			respondError(w, http.StatusBadRequest, "permission, or resource and action, are required")
+			return
+		}
+		permID = resource + "." + action
+	}
+
+	decision, err := s.rbacManager.Explain(requestEmail(r), permID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to explain permission: %v", err))
+		return
+	}
+	if !s.authEnabled {
+		// Open mode allows everything, whatever the roles say
+		decision.Allowed = true
+		decision.Reason = "Authentication is disabled, so every action is allowed"
+	}
+	respondJSON(w, http.StatusOK, decision)
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// podDeletePermission is required to delete or evict pods; a batch dry run needs none
+const podDeletePermission = "pod.delete"
+
+// Batch pod deletion limits. A request may lower max_count but never raise it above
+// POD_BATCH_DELETE_MAX_COUNT.
+const (
+	defaultPodBatchDeleteCount = 10
+	defaultPodBatchDeleteLimit = 50
+)
+
+// deletePods deletes the pods of a namespace matching a label selector or a list of names.
+// Body: {"selector": "app=podinfo"} or {"pods": ["a", "b"]}, plus optional max_count,
+// dry_run and evict. When more pods match than max_count nothing is deleted and 409 lists
+// them; when evict is set and a PodDisruptionBudget blocks some pods, 429 lists them.
+// Anything but a dry run requires the pod.delete permission.
+func (s *Server) deletePods(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+
+	data, ok := readJSONBody(w, r)
+	if !ok {
+		return
+	}
+	var req k8s.PodDeletionRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.MaxCount == 0 {
+		req.MaxCount = defaultPodBatchDeleteCount
+	}
+	if limit := podBatchDeleteLimit(); req.MaxCount > limit {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("max_count cannot exceed %d", limit))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	target := req.Selector
+	if target == "" {
+		target = strings.Join(req.Pods, ",")
+	}
+	action := "delete"
+	if req.Evict {
+		action = "evict"
+	}
+	if !req.DryRun {
+		if err := s.requirePermission(r, podDeletePermission); err != nil {
+			s.logActivity(requestActor(r), action, "Pod", fmt.Sprintf("%s/%s", namespace, target), target, clusterID, clusterName, "failed", fmt.Sprintf("Denied %s: %v", action, err))
+			respondError(w, http.StatusForbidden, fmt.Sprintf("Pod deletion not allowed: %v", err))
+			return
+		}
+	}
+
+	result, err := s.k8sClient.DeletePods(s.provenanceContext(r, "delete"), clusterID, namespace, req)
+	var tooMany *k8s.TooManyPodsError
+	if errors.As(err, &tooMany) {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":  fmt.Sprintf("%v; narrow the selector or raise max_count", err),
+			"result": result,
+		})
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete pods: %v", err))
+		return
+	}
+
+	if !req.DryRun {
+		verb := "Deleted"
+		if req.Evict {
+			verb = "Evicted"
+		}
+		status := "success"
+		if len(result.Failed) > 0 || len(result.Blocked) > 0 {
+			status = "failed"
+		}
+		message := fmt.Sprintf("%s %d of %d pods in %s (%s)", verb, len(result.Deleted), len(result.Matched), namespace, target)
+		if len(result.Blocked) > 0 {
+			message += fmt.Sprintf("; %d blocked by disruption budgets", len(result.Blocked))
+		}
+		s.logActivity(requestActor(r), action, "Pod", fmt.Sprintf("%s/%s", namespace, target), target, clusterID, clusterName, status, message)
+	}
+
+	if len(result.Blocked) > 0 {
+		setRetryAfter(w, result.RetryAfter)
+		respondJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error":  fmt.Sprintf("%d pod(s) could not be evicted without violating a PodDisruptionBudget; retry later", len(result.Blocked)),
+			"result": result,
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// evictPod evicts a single pod, answering 429 when a PodDisruptionBudget blocks it.
+// The caller has checked the pod.delete permission.
+func (s *Server) evictPod(w http.ResponseWriter, r *http.Request, clusterID, namespace, name string) {
+	clusterName := s.clusterService.Name(clusterID)
+
+	err := s.k8sClient.EvictPod(s.provenanceContext(r, "evict"), clusterID, namespace, name)
+	var blocked *k8s.PodEvictionBlockedError
+	if errors.As(err, &blocked) {
+		s.logActivity(requestActor(r), "evict", "Pod", fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "failed", blocked.Error())
+		setRetryAfter(w, blocked.RetryAfter)
+		respondError(w, http.StatusTooManyRequests, blocked.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to evict pod: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "evict", "Pod", fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "success", fmt.Sprintf("Evicted pod %s/%s", namespace, name))
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Pod evicted successfully"})
+}
+
+// setRetryAfter sets the Retry-After header when the API server suggested a delay
+func setRetryAfter(w http.ResponseWriter, seconds int) {
+	if seconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+}
+
+// podBatchDeleteLimit returns the largest max_count a batch deletion may use
+func podBatchDeleteLimit() int {
+	if value := os.Getenv("POD_BATCH_DELETE_MAX_COUNT"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultPodBatchDeleteLimit
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// podExecPermission is required to open an exec session
+const podExecPermission = "pod.exec"
+
+// maxExecMessageSize caps one frame of terminal input
+const maxExecMessageSize = 64 * 1024
+
+// execClientMessage is a text frame sent by the client: input or a terminal resize.
+// Binary frames are sent to stdin as they are.
+type execClientMessage struct {
+	Type string `json:"type"` // stdin, resize
+	Data string `json:"data,omitempty"`
+	Cols uint16 `json:"cols,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+}
+
+// execServerMessage is the text frame that ends a session. The command's output is sent
+// as binary frames before it.
+type execServerMessage struct {
+	Type  string `json:"type"` // exit, error
+	Code  int    `json:"code"`
+	Error string `json:"error,omitempty"`
+}
+
+// execPod upgrades to a WebSocket and attaches it to a command in a container, by default
+// an interactive /bin/sh. Query parameters: container, command (repeated for each
+// argument), tty (default true). Requires the pod.exec permission.
+func (s *Server) execPod(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	query := r.URL.Query()
+	opts := k8s.ExecOptions{
+		Container: query.Get("container"),
+		Command:   query["command"],
+		TTY:       query.Get("tty") != "false",
+	}
+	if len(opts.Command) == 0 {
+		opts.Command = []string{"/bin/sh"}
+	}
+
+	if err := s.requirePermission(r, podExecPermission); err != nil {
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Exec not allowed: %v", err))
+		return
+	}
+
+	upgrader := websocket.Upgrader{CheckOrigin: s.checkEventStreamOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxExecMessageSize)
+
+	// The session ends when the command exits or the client goes away
+	ctx, cancel := context.WithCancel(s.provenanceContext(r, "exec"))
+	defer cancel()
+
+	out := &execOutput{conn: conn}
+	stdin, stdinWriter := io.Pipe()
+	resize := make(chan k8s.TerminalSize, 1)
+	opts.Stdin, opts.Stdout, opts.Stderr, opts.Resize = stdin, out, out, resize
+
+	go func() {
+		defer cancel()
+		defer stdinWriter.Close()
+		conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(eventStreamPongWait))
+		})
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if messageType == websocket.BinaryMessage {
+				if _, err := stdinWriter.Write(data); err != nil {
+					return
+				}
+				continue
+			}
+			var message execClientMessage
+			if json.Unmarshal(data, &message) != nil {
+				continue
+			}
+			switch message.Type {
+			case "stdin":
+				if _, err := stdinWriter.Write([]byte(message.Data)); err != nil {
+					return
+				}
+			case "resize":
+				// Only the latest size matters
+				select {
+				case <-resize:
+				default:
+				}
+				resize <- k8s.TerminalSize{Width: message.Cols, Height: message.Rows}
+			}
+		}
+	}()
+
+	go func() {
+		ping := time.NewTicker(eventStreamPingPeriod)
+		defer ping.Stop()
+		for {
+			select {
+			case <-ping.C:
+				if err := out.ping(); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	clusterName := s.clusterService.Name(clusterID)
+	command := strings.Join(opts.Command, " ")
+	target := name
+	if opts.Container != "" {
+		target = fmt.Sprintf("%s (container %s)", name, opts.Container)
+	}
+	s.logActivity(requestActor(r), "exec", "Pod", fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "success",
+		fmt.Sprintf("Opened exec session in %s/%s: %s", namespace, target, command))
+
+	code, err := s.k8sClient.ExecPod(ctx, clusterID, namespace, name, opts)
+	stdin.Close()
+	result := execServerMessage{Type: "exit", Code: code}
+	if err != nil && ctx.Err() == nil {
+		result = execServerMessage{Type: "error", Error: err.Error()}
+		s.logActivity(requestActor(r), "exec", "Pod", fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "failed",
+			fmt.Sprintf("Exec session in %s/%s failed: %v", namespace, target, err))
+	}
+	out.finish(result)
+}
+
+// execOutput forwards command output as binary frames; writes come from the stdout and
+// stderr copiers and the keepalive, so they are serialized
+type execOutput struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (o *execOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.conn.SetWriteDeadline(time.Now().Add(eventStreamWriteWait))
+	if err := o.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (o *execOutput) ping() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventStreamWriteWait))
+}
+
+// finish sends the closing frame and closes the connection normally
+func (o *execOutput) finish(result execServerMessage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.conn.SetWriteDeadline(time.Now().Add(eventStreamWriteWait))
+	if o.conn.WriteJSON(result) != nil {
+		return
+	}
+	o.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, "session ended"),
+		time.Now().Add(eventStreamWriteWait))
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// getPodMetrics returns the CPU and memory usage of a pod's containers, next to their
+// requests and limits and the usage of the pod's node, as sampled by metrics-server
+func (s *Server) getPodMetrics(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	metrics, err := s.k8sClient.GetPodMetrics(r.Context(), clusterID, namespace, name)
+	switch {
+	case errors.Is(err, k8s.ErrPodNotFound):
+		respondError(w, http.StatusNotFound, fmt.Sprintf("Pod %s/%s not found", namespace, name))
+		return
+	case errors.Is(err, k8s.ErrMetricsUnavailable):
+		respondError(w, http.StatusServiceUnavailable, fmt.Sprintf("Pod metrics unavailable: %v", err))
+		return
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get pod metrics: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, metrics)
+}
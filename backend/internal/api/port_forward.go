@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// podPortForwardPermission is required to send requests through a port-forward
+const podPortForwardPermission = "pod.portforward"
+
+// portForwardPod sends one HTTP request to a pod port through a port-forward opened with
+// the cluster's stored credentials and returns the pod's response, to reach in-cluster
+// endpoints such as a failing app's health check. Requires the pod.portforward permission;
+// every request is recorded in the activity log without its headers or body.
+func (s *Server) portForwardPod(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	var req k8s.PortForwardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	clusterName := s.clusterService.Name(clusterID)
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+	target := fmt.Sprintf("%s %s:%d%s", req.Method, resourceID, req.Port, req.Path)
+
+	if err := s.requirePermission(r, podPortForwardPermission); err != nil {
+		s.logActivity(requestActor(r), "portforward", "Pod", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Denied port-forward %s: %v", target, err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Port-forward not allowed: %v", err))
+		return
+	}
+
+	resp, err := s.k8sClient.PortForwardHTTP(s.provenanceContext(r, "portforward"), clusterID, namespace, name, req)
+	if err != nil {
+		s.logActivity(requestActor(r), "portforward", "Pod", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Port-forward %s failed: %v", target, err))
+		respondError(w, http.StatusBadGateway, fmt.Sprintf("Port-forward failed: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "portforward", "Pod", resourceID, name, clusterID, clusterName, "success", fmt.Sprintf("Port-forward %s returned %d", target, resp.StatusCode))
+	respondJSON(w, http.StatusOK, resp)
+}
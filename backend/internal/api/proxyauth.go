@@ -0,0 +1,160 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/rbac"
+)
+
+// proxyAuthConfig is the trusted reverse-proxy header mode, for deployments behind an
+// authenticating proxy such as oauth2-proxy or Pomerium
+type proxyAuthConfig struct {
+	trusted     cidrList // direct peers whose identity headers are believed
+	userHeader  string
+	emailHeader string
+	nameHeader  string
+	groupHeader string
+	groupRoles  map[string]string // proxy group to RBAC role
+	logoutURL   string
+}
+
+// proxyAuthConfigFromEnv configures proxy header authentication from AUTH_PROXY_TRUSTED_CIDRS,
+// which enables it, and the optional AUTH_PROXY_*_HEADER names, AUTH_PROXY_GROUP_ROLES and
+// AUTH_PROXY_LOGOUT_URL. It returns nil when the mode is off.
+func proxyAuthConfigFromEnv() (*proxyAuthConfig, error) {
+	value := os.Getenv("AUTH_PROXY_TRUSTED_CIDRS")
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	trusted := parseCIDRs("AUTH_PROXY_TRUSTED_CIDRS", value)
+	if len(trusted) == 0 {
+		return nil, fmt.Errorf("AUTH_PROXY_TRUSTED_CIDRS holds no valid CIDR")
+	}
+
+	header := func(key, fallback string) string {
+		if name := strings.TrimSpace(os.Getenv(key)); name != "" {
+			return http.CanonicalHeaderKey(name)
+		}
+		return fallback
+	}
+	cfg := &proxyAuthConfig{
+		trusted:     trusted,
+		userHeader:  header("AUTH_PROXY_USER_HEADER", "X-Forwarded-User"),
+		emailHeader: header("AUTH_PROXY_EMAIL_HEADER", "X-Forwarded-Email"),
+		nameHeader:  header("AUTH_PROXY_NAME_HEADER", "X-Forwarded-Preferred-Username"),
+		groupHeader: header("AUTH_PROXY_GROUPS_HEADER", "X-Forwarded-Groups"),
+		logoutURL:   os.Getenv("AUTH_PROXY_LOGOUT_URL"),
+	}
+
+	// group=role pairs, such as platform-admins=admin,sre=operator
+	if mapping := os.Getenv("AUTH_PROXY_GROUP_ROLES"); mapping != "" {
+		cfg.groupRoles = map[string]string{}
+		for _, pair := range strings.Split(mapping, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			group, role, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || strings.TrimSpace(group) == "" || strings.TrimSpace(role) == "" {
+				return nil, fmt.Errorf("AUTH_PROXY_GROUP_ROLES entry %q is not group=role", pair)
+			}
+			cfg.groupRoles[strings.TrimSpace(group)] = strings.TrimSpace(role)
+		}
+	}
+	return cfg, nil
+}
+
+// roles returns the RBAC roles a user's proxy groups map to, none when no group does
+func (c *proxyAuthConfig) roles(groups []string) []string {
+	seen := map[string]bool{}
+	var roles []string
+	for _, group := range groups {
+		if role, ok := c.groupRoles[group]; ok && !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// proxyAuthenticator accepts the identity headers an authenticating reverse proxy sets, from
+// trusted proxy addresses only. Users are created on first sight with the roles the role
+// mapping rules give them and, with AUTH_PROXY_GROUP_ROLES, get the roles their groups map
+// to on every request.
+type proxyAuthenticator struct {
+	s   *Server
+	cfg *proxyAuthConfig
+}
+
+func (a proxyAuthenticator) Name() string { return "proxy" }
+
+func (a proxyAuthenticator) Authenticate(r *http.Request) (*auth.UserInfo, error) {
+	user := strings.TrimSpace(r.Header.Get(a.cfg.userHeader))
+	email := strings.TrimSpace(r.Header.Get(a.cfg.emailHeader))
+	if user == "" && email == "" {
+		return nil, ErrNoCredential
+	}
+
+	// Only the direct peer counts; X-Forwarded-For is as easy to forge as the headers
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip == nil || !a.cfg.trusted.contains(ip) {
+		return nil, errors.New("Proxy authentication headers are only accepted from trusted proxies")
+	}
+
+	if email == "" {
+		email = user
+	}
+	if user == "" {
+		user = email
+	}
+	name := strings.TrimSpace(r.Header.Get(a.cfg.nameHeader))
+	if name == "" {
+		name = user
+	}
+
+	var groups []string
+	for _, group := range strings.Split(r.Header.Get(a.cfg.groupHeader), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+
+	account, err := a.s.rbacManager.GetOrCreateUser(email, name, "proxy", groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user %s: %w", email, err)
+	}
+	if !account.Enabled {
+		return nil, errUserDisabled
+	}
+	if a.cfg.groupRoles != nil {
+		if err := a.syncGroupRoles(account, groups); err != nil {
+			log.Printf("Warning: Failed to apply proxy group roles to %s: %v", email, err)
+		}
+	}
+
+	return &auth.UserInfo{ID: user, Email: email, Name: name, Username: user, Provider: "proxy"}, nil
+}
+
+// syncGroupRoles gives a user the roles their proxy groups map to or, when none of their
+// groups is mapped, the roles of the role mapping rules
+func (a proxyAuthenticator) syncGroupRoles(account *models.User, groups []string) error {
+	roles := a.cfg.roles(groups)
+	if len(roles) == 0 {
+		login, err := a.s.rbacManager.EvaluateLogin(rbac.LoginIdentity{Email: account.Email, Provider: "proxy", Groups: groups})
+		if err != nil {
+			return err
+		}
+		roles = login.Roles
+	}
+	return a.s.rbacManager.SyncUserRoles(account, roles)
+}
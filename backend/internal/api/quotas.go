@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// quotaAppKinds are the Flux kinds that deploy workloads and so consume namespace quota
+var quotaAppKinds = map[string]bool{
+	"Kustomization": true,
+	"HelmRelease":   true,
+}
+
+// quotaApp is a Flux app deploying into a namespace
+type quotaApp struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	// AtRisk is set for apps that are not ready in a namespace near its quota
+	AtRisk bool `json:"at_risk"`
+}
+
+// namespaceQuotaView joins a namespace's quotas with the Flux apps deployed there
+type namespaceQuotaView struct {
+	k8s.NamespaceQuotas
+	Apps       []quotaApp     `json:"apps"`
+	AppsByKind map[string]int `json:"apps_by_kind"`
+}
+
+// appTargetNamespace returns the namespace an app deploys into: spec.targetNamespace
+// when set, otherwise the namespace of the Flux object itself
+func appTargetNamespace(resource models.FluxResource) string {
+	var obj struct {
+		Spec struct {
+			TargetNamespace string `json:"targetNamespace"`
+		} `json:"spec"`
+	}
+	if resource.Metadata != "" && json.Unmarshal([]byte(resource.Metadata), &obj) == nil && obj.Spec.TargetNamespace != "" {
+		return obj.Spec.TargetNamespace
+	}
+	return resource.Namespace
+}
+
+// getClusterQuotas summarizes ResourceQuotas and LimitRanges per namespace alongside the
+// Flux apps deployed there, flagging NotReady apps in namespaces near their quota
+func (s *Server) getClusterQuotas(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+
+	threshold := k8s.DefaultQuotaThreshold
+	if value := r.URL.Query().Get("threshold"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			respondError(w, http.StatusBadRequest, "Threshold must be a number between 0 and 1")
+			return
+		}
+		threshold = parsed
+	}
+
+	quotas, err := s.k8sClient.GetNamespaceQuotas(r.Context(), clusterID, threshold)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get namespace quotas: %v", err))
+		return
+	}
+
+	resources, err := s.resourceService.ListByCluster(clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to query resources")
+		return
+	}
+
+	views := make(map[string]*namespaceQuotaView, len(quotas))
+	for _, quota := range quotas {
+		views[quota.Namespace] = &namespaceQuotaView{NamespaceQuotas: quota, Apps: []quotaApp{}, AppsByKind: map[string]int{}}
+	}
+
+	atRisk := 0
+	for _, resource := range resources {
+		if !quotaAppKinds[resource.Kind] {
+			continue
+		}
+		namespace := appTargetNamespace(resource)
+		view, ok := views[namespace]
+		if !ok {
+			// Namespaces without quotas or limit ranges are not listed
+			continue
+		}
+		app := quotaApp{
+			ID:        resource.ID,
+			Kind:      resource.Kind,
+			Name:      resource.Name,
+			Namespace: resource.Namespace,
+			Status:    resource.Status,
+			Message:   resource.Message,
+			AtRisk:    view.NearLimit && resource.Status == "NotReady",
+		}
+		if app.AtRisk {
+			atRisk++
+		}
+		view.Apps = append(view.Apps, app)
+		view.AppsByKind[resource.Kind]++
+	}
+
+	namespaces := make([]namespaceQuotaView, 0, len(views))
+	nearLimit := 0
+	for _, quota := range quotas {
+		view := views[quota.Namespace]
+		sort.Slice(view.Apps, func(i, j int) bool {
+			if view.Apps[i].Kind != view.Apps[j].Kind {
+				return view.Apps[i].Kind < view.Apps[j].Kind
+			}
+			return view.Apps[i].Name < view.Apps[j].Name
+		})
+		if view.NearLimit {
+			nearLimit++
+		}
+		namespaces = append(namespaces, *view)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"cluster_id": clusterID,
+		"threshold":  threshold,
+		"namespaces": namespaces,
+		"near_limit": nearLimit,
+		"at_risk":    atRisk,
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/rbac"
+	"sigs.k8s.io/yaml"
+)
+
+// Permissions guarding the RBAC configuration. Importing can grant any role to any user, so
+// it needs both role.update and user.update.
+const (
+	roleReadPermission   = "role.read"
+	roleUpdatePermission = "role.update"
+	userUpdatePermission = "user.update"
+)
+
+// exportRBAC returns the roles, permissions and user-role bindings as JSON, or as YAML with
+// format=yaml, in the form importRBAC accepts. Requires the role.read permission.
+func (s *Server) exportRBAC(w http.ResponseWriter, r *http.Request) {
+	if err := s.requirePermission(r, roleReadPermission); err != nil {
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Export not allowed: %v", err))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "yaml" {
+		respondError(w, http.StatusBadRequest, "format must be json or yaml")
+		return
+	}
+
+	config, err := s.rbacManager.ExportConfig()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to export RBAC configuration: %v", err))
+		return
+	}
+
+	var data []byte
+	if format == "yaml" {
+		data, err = yaml.Marshal(config)
+		w.Header().Set("Content-Type", "application/yaml")
+	} else {
+		data, err = json.MarshalIndent(config, "", "  ")
+		w.Header().Set("Content-Type", "application/json")
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to encode RBAC configuration: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=rbac.%s", format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// importRBAC applies an RBAC configuration in YAML or JSON, as exported by exportRBAC.
+// Importing is idempotent: listed entries are created or brought in line and anything not
+// listed is kept. With dry_run=true nothing is written and the response only reports what
+// would change. Requires the role.update and user.update permissions.
+func (s *Server) importRBAC(w http.ResponseWriter, r *http.Request) {
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	for _, permission := range []string{roleUpdatePermission, userUpdatePermission} {
+		if err := s.requirePermission(r, permission); err != nil {
+			s.logActivity(requestActor(r), "import", "rbac", "", "RBAC configuration", "", "", "failed", fmt.Sprintf("Denied import: %v", err))
+			respondError(w, http.StatusForbidden, fmt.Sprintf("Import not allowed: %v", err))
+			return
+		}
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit))
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	config, err := rbac.ParseConfig(data)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.rbacManager.ImportConfig(config, dryRun)
+	if err != nil {
+		if errors.Is(err, rbac.ErrInvalidConfig) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.logActivity(requestActor(r), "import", "rbac", "", "RBAC configuration", "", "", "failed", fmt.Sprintf("Error: %v", err))
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to import RBAC configuration: %v", err))
+		return
+	}
+
+	if !dryRun && (len(result.Created) > 0 || len(result.Updated) > 0) {
+		changes := &activityChangeSet{}
+		changes.param("created", result.Created)
+		changes.param("updated", result.Updated)
+		s.logActivityDetails(requestActor(r), "import", "rbac", "", "RBAC configuration", "", "", "success",
+			fmt.Sprintf("Imported RBAC configuration: %d created, %d updated, %d unchanged", len(result.Created), len(result.Updated), len(result.Unchanged)), changes)
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
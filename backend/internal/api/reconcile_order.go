@@ -0,0 +1,36 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// getReconcileOrder returns the stages Flux reconciles Kustomizations and HelmReleases in
+// by their dependsOn, with dependency cycles and missing dependencies reported as issues.
+// ?source=Kind/namespace/name limits it to what a new artifact of that source triggers.
+func (s *Server) getReconcileOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+
+	var source *k8s.EventObject
+	if value := r.URL.Query().Get("source"); value != "" {
+		parts := strings.Split(value, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			respondError(w, http.StatusBadRequest, "source must be Kind/namespace/name")
+			return
+		}
+		source = &k8s.EventObject{Kind: parts[0], Namespace: parts[1], Name: parts[2]}
+	}
+
+	order, err := s.k8sClient.GetReconcileOrder(r.Context(), clusterID, source)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to compute reconcile order: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, order)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+)
+
+// reconcileWaitTimeout is how long a reconcile with ?wait=true waits for the resource by
+// default; ?timeout= takes a duration up to maxReconcileWaitTimeout
+const (
+	reconcileWaitTimeout    = 5 * time.Minute
+	maxReconcileWaitTimeout = 30 * time.Minute
+)
+
+// sseKeepAlive is how often a comment is sent on an idle event stream so proxies keep it open
+const sseKeepAlive = 15 * time.Second
+
+// isReconcileWait reports whether a request asks a reconcile endpoint to wait for the
+// resource. Those requests bound themselves with ?timeout instead of the request timeout.
+func isReconcileWait(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/reconcile") && r.URL.Query().Get("wait") == "true"
+}
+
+// wantsEventStream reports whether the client accepts Server-Sent Events
+func wantsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// eventStream writes Server-Sent Events, flushing each one
+type eventStream struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	stream := &eventStream{w: w, controller: http.NewResponseController(w)}
+	stream.controller.Flush()
+	return stream
+}
+
+func (s *eventStream) send(event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload)
+	s.controller.Flush()
+}
+
+func (s *eventStream) keepAlive() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprint(s.w, ": keep-alive\n\n")
+	s.controller.Flush()
+}
+
+// reconcileAndWait requests a reconcile, with the source first when withSource is set, and
+// waits until the resource is Ready, fails or the timeout passes. Clients accepting
+// text/event-stream get a "source" event for a reconciled source, a "progress" event for
+// every change of conditions and a final "done" or "error" event; other clients get the
+// final state as JSON.
+func (s *Server) reconcileAndWait(w http.ResponseWriter, r *http.Request, ctx context.Context, clusterID, clusterName, kind, namespace, name string, withSource bool) {
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+
+	if kind == "Alert" || kind == "Provider" {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("%ss report no readiness to wait for", kind))
+		return
+	}
+	timeout := reconcileWaitTimeout
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxReconcileWaitTimeout {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("timeout must be a duration up to %s", maxReconcileWaitTimeout))
+			return
+		}
+		timeout = parsed
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stream *eventStream
+	if wantsEventStream(r) {
+		stream = newEventStream(w)
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(sseKeepAlive)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					stream.keepAlive()
+				}
+			}
+		}()
+	}
+	fail := func(status int, message string, err error, progress *k8s.ReconcileProgress) {
+		s.logActivity(requestActor(r), "reconcile", kind, resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		if stream != nil {
+			stream.send("error", map[string]interface{}{"error": fmt.Sprintf("%s: %v", message, err), "progress": progress})
+			return
+		}
+		respondError(w, status, fmt.Sprintf("%s: %v", message, err))
+	}
+
+	var source *k8s.SourceReconcileResult
+	if withSource {
+		var err error
+		if source, err = s.resourceService.ReconcileWithSource(ctx, clusterID, kind, namespace, name); err != nil {
+			fail(http.StatusInternalServerError, "Failed to reconcile", err, nil)
+			return
+		}
+		if stream != nil {
+			stream.send("source", source)
+		}
+	} else if err := s.resourceService.Reconcile(ctx, clusterID, kind, namespace, name); err != nil {
+		fail(http.StatusInternalServerError, "Failed to reconcile", err, nil)
+		return
+	}
+
+	progress, err := s.resourceService.WaitForReconcile(ctx, clusterID, kind, namespace, name, func(p k8s.ReconcileProgress) {
+		if stream != nil {
+			stream.send("progress", p)
+		}
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if ctx.Err() != nil {
+			status = http.StatusGatewayTimeout
+		}
+		fail(status, "Reconciliation did not complete", err, progress)
+		return
+	}
+
+	message := fmt.Sprintf("Reconciled %s and it is ready", resourceID)
+	if progress.Revision != "" {
+		message += fmt.Sprintf(" at %s", progress.Revision)
+	}
+	s.logActivityDetails(requestActor(r), "reconcile", kind, resourceID, name, clusterID, clusterName, "success", message, reconcileChanges(ctx))
+
+	result := map[string]interface{}{"message": message, "progress": progress}
+	if source != nil {
+		result["source"] = source
+	}
+	if stream != nil {
+		stream.send("done", result)
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// recoveryMiddleware turns handler panics into a logged, structured 500 response.
+// It must run inside timeoutMiddleware, whose handler goroutine would otherwise crash the process.
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tracked := &recoveryResponseWriter{ResponseWriter: w}
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// Let net/http handle deliberate aborts as usual
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			requestID := requestIDFromContext(r.Context())
+			route := routeTemplate(r)
+			metrics.HTTPPanicsTotal.WithLabelValues(r.Method, route).Inc()
+
+			logging.WithRequestID(requestID).Error("Panic while handling request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("route", route),
+				zap.String("panic", fmt.Sprint(recovered)),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			if tracked.written {
+				return
+			}
+			respondJSON(w, http.StatusInternalServerError, map[string]string{
+				"error":      "Internal server error",
+				"request_id": requestID,
+			})
+		}()
+
+		next.ServeHTTP(tracked, r)
+	})
+}
+
+// recoveryResponseWriter records whether the response has been started
+type recoveryResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *recoveryResponseWriter) WriteHeader(code int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recoveryResponseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *recoveryResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
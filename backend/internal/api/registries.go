@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// Container registry credential handlers. Stored credentials are used by the image reports
+// for registries no image pull Secret in the cluster covers.
+
+// loadRegistryCredentials registers stored registry credentials with the Kubernetes client
+func (s *Server) loadRegistryCredentials() {
+	if err := s.registryService.LoadCredentials(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+func (s *Server) listRegistryCredentials(w http.ResponseWriter, r *http.Request) {
+	credentials, err := s.registryService.List()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list registry credentials")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newRegistryCredentialResponses(credentials))
+}
+
+// createRegistryCredential stores a registry login after testing it against the registry.
+// Provider is acr, ecr, gcr, dockerhub or generic; ECR and GCR default the username to AWS
+// and _json_key, and Docker Hub defaults the registry to docker.io.
+func (s *Server) createRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name     string `json:"name"`
+		Provider string `json:"provider"`
+		Registry string `json:"registry"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credential, err := s.registryService.Create(r.Context(), service.RegistryCredentialInput{
+		Name:     req.Name,
+		Provider: req.Provider,
+		Registry: req.Registry,
+		Username: req.Username,
+		Password: req.Password,
+	})
+	if err != nil {
+		respondServiceError(w, err, "Registry credential not found", "Failed to save registry credential")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, newRegistryCredentialResponse(credential))
+}
+
+func (s *Server) getRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	credential, err := s.registryService.Get(id)
+	if err != nil {
+		respondServiceError(w, err, "Registry credential not found", "Failed to query registry credential")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newRegistryCredentialResponse(credential))
+}
+
+// updateRegistryCredential changes a stored credential; an empty password keeps the stored
+// one. Changed logins are tested before they are saved.
+func (s *Server) updateRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var req struct {
+		Name     string `json:"name"`
+		Registry string `json:"registry"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Version  *int   `json:"version"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credential, err := s.registryService.Update(r.Context(), id, service.RegistryCredentialUpdate{
+		Name:     req.Name,
+		Registry: req.Registry,
+		Username: req.Username,
+		Password: req.Password,
+		Version:  req.Version,
+	})
+	if err != nil {
+		respondServiceError(w, err, "Registry credential not found", "Failed to update registry credential")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newRegistryCredentialResponse(credential))
+}
+
+func (s *Server) deleteRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := s.registryService.Delete(id); err != nil {
+		respondServiceError(w, err, "Registry credential not found", "Failed to delete registry credential")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Registry credential deleted successfully"})
+}
+
+func (s *Server) testRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := s.registryService.Test(r.Context(), id); err != nil {
+		respondServiceError(w, err, "Registry credential not found", "Connection test failed")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "healthy", "message": "Connection successful"})
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/reports"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// reportSchedulerInterval is how often the scheduler checks whether a weekly report is due
+const reportSchedulerInterval = time.Hour
+
+// getWeeklyReport compiles the weekly report as JSON or rendered HTML, covering the
+// clusters the caller can read
+func (s *Server) getWeeklyReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var clusterIDs []string
+	if ids := query.Get("cluster_id"); ids != "" {
+		for _, id := range strings.Split(ids, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				clusterIDs = append(clusterIDs, id)
+			}
+		}
+	}
+
+	end := time.Now()
+	if endParam := query.Get("end"); endParam != "" {
+		parsed, err := time.Parse(time.RFC3339, endParam)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "End must be an RFC3339 timestamp")
+			return
+		}
+		end = parsed
+	}
+
+	report, err := reports.Build(s.db, s.clusterScope(r), clusterIDs, end.Add(-reports.ReportPeriod), end)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to build report: %v", err))
+		return
+	}
+
+	if query.Get("format") != "html" {
+		respondJSON(w, http.StatusOK, report)
+		return
+	}
+
+	tmpl, err := reports.LoadTemplate(s.db, query.Get("template_id"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, "Report template not found")
+		return
+	}
+
+	_, body, err := reports.Render(tmpl, report)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to render report: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body))
+}
+
+// sendWeeklyReportNow compiles the weekly report of the clusters the caller can read and
+// delivers it immediately
+func (s *Server) sendWeeklyReportNow(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ClusterIDs []string `json:"cluster_ids"`
+		TemplateID string   `json:"template_id"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	delivered, err := s.deliverWeeklyReport(s.clusterScope(r), req.ClusterIDs, req.TemplateID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to send report: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":   "Report sent",
+		"delivered": delivered,
+	})
+}
+
+// deliverWeeklyReport builds, renders and sends the weekly report by email and webhook.
+// It returns the channels the report was delivered to.
+func (s *Server) deliverWeeklyReport(scope repository.Scope, clusterIDs []string, templateID string) ([]string, error) {
+	smtpConfig := reports.SMTPConfigFromEnv()
+	if smtpConfig == nil && !s.webhooks.Enabled() {
+		return nil, fmt.Errorf("no delivery channel configured: set SMTP_HOST and REPORT_RECIPIENTS or WEBHOOK_URLS")
+	}
+
+	end := time.Now()
+	report, err := reports.Build(s.db, scope, clusterIDs, end.Add(-reports.ReportPeriod), end)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := reports.LoadTemplate(s.db, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, body, err := reports.Render(tmpl, report)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivered []string
+	if smtpConfig != nil {
+		if err := reports.SendEmail(smtpConfig, subject, body); err != nil {
+			s.logActivity("system", "send", "report", "weekly", subject, "", "", "failed", err.Error())
+			return nil, err
+		}
+		delivered = append(delivered, "email")
+	}
+	if s.webhooks.Enabled() {
+		s.webhooks.NotifyReportGenerated(subject, report)
+		delivered = append(delivered, "webhook")
+	}
+
+	s.logActivity("system", "send", "report", "weekly", subject, "", "", "success",
+		fmt.Sprintf("Weekly report delivered via %s", strings.Join(delivered, ", ")))
+
+	return delivered, nil
+}
+
+// scheduleWeeklyReports sends the weekly report when enabled and a week has passed since the last one
+func (s *Server) scheduleWeeklyReports() {
+	ticker := time.NewTicker(reportSchedulerInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var enabled models.Setting
+		if err := s.db.Where("setting_key = ?", "weekly_report_enabled").First(&enabled).Error; err != nil || enabled.Value != "true" {
+			continue
+		}
+
+		var lastSent models.Setting
+		if err := s.db.Where("setting_key = ?", "weekly_report_last_sent").First(&lastSent).Error; err == nil {
+			if sentAt, err := time.Parse(time.RFC3339, lastSent.Value); err == nil && time.Since(sentAt) < reports.ReportPeriod {
+				continue
+			}
+		}
+
+		if _, err := s.deliverWeeklyReport(repository.Unrestricted, nil, ""); err != nil {
+			log.Printf("Warning: Failed to send weekly report: %v", err)
+			continue
+		}
+
+		now := time.Now().Format(time.RFC3339)
+		setting := models.Setting{Key: "weekly_report_last_sent", Value: now}
+		if err := s.db.Where(models.Setting{Key: "weekly_report_last_sent"}).Assign(models.Setting{Value: now}).FirstOrCreate(&setting).Error; err != nil {
+			log.Printf("Warning: Failed to record weekly report timestamp: %v", err)
+		}
+	}
+}
+
+// listReportTemplates returns all stored report templates
+func (s *Server) listReportTemplates(w http.ResponseWriter, r *http.Request) {
+	var templates []models.ReportTemplate
+	if err := s.db.Order("name ASC").Find(&templates).Error; err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to fetch report templates: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, templates)
+}
+
+// getReportTemplate returns a single report template
+func (s *Server) getReportTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var tmpl models.ReportTemplate
+	if err := s.db.First(&tmpl, "id = ?", id).Error; err != nil {
+		respondError(w, http.StatusNotFound, "Report template not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tmpl)
+}
+
+// createReportTemplate stores a new report template
+func (s *Server) createReportTemplate(w http.ResponseWriter, r *http.Request) {
+	var tmpl models.ReportTemplate
+	if err := json.NewDecoder(r.Body).Decode(&tmpl); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if tmpl.Name == "" || tmpl.Body == "" {
+		respondError(w, http.StatusBadRequest, "Name and body are required")
+		return
+	}
+	if err := reports.ValidateTemplate(tmpl.Subject, tmpl.Body); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tmpl.ID = uuid.New().String()
+	if err := s.saveReportTemplate(&tmpl); err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create report template: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "create", "report_template", tmpl.ID, tmpl.Name, "", "", "success", "Created report template")
+	respondJSON(w, http.StatusCreated, tmpl)
+}
+
+// updateReportTemplate updates an existing report template
+func (s *Server) updateReportTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var tmpl models.ReportTemplate
+	if err := s.db.First(&tmpl, "id = ?", id).Error; err != nil {
+		respondError(w, http.StatusNotFound, "Report template not found")
+		return
+	}
+
+	var req struct {
+		Name      *string `json:"name"`
+		Subject   *string `json:"subject"`
+		Body      *string `json:"body"`
+		IsDefault *bool   `json:"is_default"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Name != nil {
+		tmpl.Name = *req.Name
+	}
+	if req.Subject != nil {
+		tmpl.Subject = *req.Subject
+	}
+	if req.Body != nil {
+		tmpl.Body = *req.Body
+	}
+	if req.IsDefault != nil {
+		tmpl.IsDefault = *req.IsDefault
+	}
+
+	if tmpl.Name == "" || tmpl.Body == "" {
+		respondError(w, http.StatusBadRequest, "Name and body are required")
+		return
+	}
+	if err := reports.ValidateTemplate(tmpl.Subject, tmpl.Body); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.saveReportTemplate(&tmpl); err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update report template: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "update", "report_template", tmpl.ID, tmpl.Name, "", "", "success", "Updated report template")
+	respondJSON(w, http.StatusOK, tmpl)
+}
+
+// deleteReportTemplate removes a report template
+func (s *Server) deleteReportTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := s.db.Delete(&models.ReportTemplate{}, "id = ?", id).Error; err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete report template: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "delete", "report_template", id, id, "", "", "success", "Deleted report template")
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Report template deleted successfully"})
+}
+
+// saveReportTemplate persists a template, clearing the default flag on others when it is the default
+func (s *Server) saveReportTemplate(tmpl *models.ReportTemplate) error {
+	if tmpl.IsDefault {
+		if err := s.db.Model(&models.ReportTemplate{}).Where("id <> ?", tmpl.ID).Update("is_default", false).Error; err != nil {
+			return err
+		}
+	}
+	return s.db.Save(tmpl).Error
+}
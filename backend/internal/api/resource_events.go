@@ -0,0 +1,41 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// getResourceEvents returns the Kubernetes events of a Flux resource or workload.
+// Query: kind and name are required, namespace and limit are optional.
+func (s *Server) getResourceEvents(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	kind := query.Get("kind")
+	namespace := query.Get("namespace")
+	name := query.Get("name")
+
+	if kind == "" || name == "" {
+		respondError(w, http.StatusBadRequest, "kind and name are required")
+		return
+	}
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+
+	events, err := s.k8sClient.GetResourceEvents(r.Context(), clusterID, kind, namespace, name, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get events: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"events": events})
+}
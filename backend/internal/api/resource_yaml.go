@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// getResourceYAML returns the live object of any resource as YAML, without
+// metadata.managedFields. Secret values are redacted unless the user has the secret.reveal
+// permission, in which case the reveal is recorded in the activity log; redacted
+// responses carry an X-Secret-Values-Redacted header.
+func (s *Server) getResourceYAML(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	kind := vars["kind"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	reveal := kind == "Secret" && s.requirePermission(r, secretRevealPermission) == nil
+	result, err := s.k8sClient.GetResourceYAML(r.Context(), clusterID, kind, namespace, name, reveal)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get resource: %v", err))
+		return
+	}
+
+	if reveal {
+		resourceID := fmt.Sprintf("%s/%s", namespace, name)
+		s.logActivity(requestActor(r), "reveal", kind, resourceID, name, clusterID, s.clusterService.Name(clusterID), "success",
+			fmt.Sprintf("Viewed the YAML of Secret %s with its values", resourceID))
+	}
+
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	if result.Redacted {
+		w.Header().Set("X-Secret-Values-Redacted", "true")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(result.YAML)
+}
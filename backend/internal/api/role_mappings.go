@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/rbac"
+	"github.com/gorilla/mux"
+)
+
+// roleMappingRequest is the body of mapping rule creates and updates
+type roleMappingRequest struct {
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	RoleID string `json:"role_id"`
+}
+
+func (req roleMappingRequest) rule() models.RoleMappingRule {
+	return models.RoleMappingRule{Type: req.Type, Value: req.Value, RoleID: req.RoleID}
+}
+
+// respondRoleMappingError maps role mapping errors to their status codes
+func respondRoleMappingError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, rbac.ErrInvalidRoleMapping):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, rbac.ErrRoleMappingNotFound):
+		respondError(w, http.StatusNotFound, "Role mapping rule not found")
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// denyRoleMappingChange responds 403 and records the attempt unless the caller holds the
+// role.update permission: mapping rules and the default role decide the roles of new users
+func (s *Server) denyRoleMappingChange(w http.ResponseWriter, r *http.Request, action, resourceID, resourceName string) bool {
+	err := s.requirePermission(r, roleUpdatePermission)
+	if err == nil {
+		return false
+	}
+	s.logActivity(requestActor(r), action, "role_mapping", resourceID, resourceName, "", "", "failed", fmt.Sprintf("Denied: %v", err))
+	respondError(w, http.StatusForbidden, fmt.Sprintf("Changing role mappings not allowed: %v", err))
+	return true
+}
+
+// listRoleMappings returns the rules giving users their roles on first login and the role
+// of users matching none
+func (s *Server) listRoleMappings(w http.ResponseWriter, r *http.Request) {
+	if err := s.requirePermission(r, roleReadPermission); err != nil {
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Listing role mappings not allowed: %v", err))
+		return
+	}
+	mappings, err := s.rbacManager.RoleMappings()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, mappings)
+}
+
+// createRoleMapping adds a mapping rule. Requires the role.update permission, like every
+// change to the mappings.
+func (s *Server) createRoleMapping(w http.ResponseWriter, r *http.Request) {
+	var req roleMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if s.denyRoleMappingChange(w, r, "create", "", req.Type+"="+req.Value) {
+		return
+	}
+
+	rule, err := s.rbacManager.CreateRoleMapping(req.rule())
+	if err != nil {
+		respondRoleMappingError(w, err)
+		return
+	}
+
+	changes := &activityChangeSet{}
+	changes.param("type", rule.Type)
+	changes.param("value", rule.Value)
+	changes.param("role_id", rule.RoleID)
+	s.logActivityDetails(requestActor(r), "create", "role_mapping", rule.ID, rule.Type+"="+rule.Value, "", "", "success",
+		fmt.Sprintf("New users matching %s %s get the %s role", rule.Type, rule.Value, rule.RoleID), changes)
+	respondJSON(w, http.StatusCreated, rule)
+}
+
+// updateRoleMapping replaces the type, value and role of a mapping rule
+func (s *Server) updateRoleMapping(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	var req roleMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if s.denyRoleMappingChange(w, r, "update", id, req.Type+"="+req.Value) {
+		return
+	}
+
+	previous, rule, err := s.rbacManager.UpdateRoleMapping(id, req.rule())
+	if err != nil {
+		respondRoleMappingError(w, err)
+		return
+	}
+
+	changes := &activityChangeSet{}
+	changes.change("type", previous.Type, rule.Type)
+	changes.change("value", previous.Value, rule.Value)
+	changes.change("role_id", previous.RoleID, rule.RoleID)
+	s.logActivityDetails(requestActor(r), "update", "role_mapping", rule.ID, rule.Type+"="+rule.Value, "", "", "success",
+		fmt.Sprintf("New users matching %s %s get the %s role", rule.Type, rule.Value, rule.RoleID), changes)
+	respondJSON(w, http.StatusOK, rule)
+}
+
+// deleteRoleMapping removes a mapping rule; users it gave roles to keep them
+func (s *Server) deleteRoleMapping(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if s.denyRoleMappingChange(w, r, "delete", id, id) {
+		return
+	}
+	rule, err := s.rbacManager.DeleteRoleMapping(id)
+	if err != nil {
+		respondRoleMappingError(w, err)
+		return
+	}
+
+	s.logActivity(requestActor(r), "delete", "role_mapping", rule.ID, rule.Type+"="+rule.Value, "", "", "success",
+		fmt.Sprintf("Removed the %s role mapping for %s %s", rule.RoleID, rule.Type, rule.Value))
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Role mapping rule deleted"})
+}
+
+// setDefaultRole sets the role of new users no mapping rule matches; an empty role_id gives
+// them none
+func (s *Server) setDefaultRole(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RoleID string `json:"role_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if s.denyRoleMappingChange(w, r, "update", rbac.DefaultRoleSetting, rbac.DefaultRoleSetting) {
+		return
+	}
+
+	previous, err := s.rbacManager.DefaultRole()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := s.rbacManager.SetDefaultRole(req.RoleID); err != nil {
+		respondRoleMappingError(w, err)
+		return
+	}
+
+	changes := &activityChangeSet{}
+	changes.change("default_role", previous, req.RoleID)
+	s.logActivityDetails(requestActor(r), "update", "setting", rbac.DefaultRoleSetting, rbac.DefaultRoleSetting, "", "", "success",
+		fmt.Sprintf("Set the default role of new users to %q", req.RoleID), changes)
+	respondJSON(w, http.StatusOK, map[string]string{"default_role": req.RoleID})
+}
+
+// evaluateRoleMappings reports the roles a user signing in for the first time would get,
+// given as email, provider and repeated group query parameters
+func (s *Server) evaluateRoleMappings(w http.ResponseWriter, r *http.Request) {
+	if err := s.requirePermission(r, roleReadPermission); err != nil {
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Evaluating role mappings not allowed: %v", err))
+		return
+	}
+	query := r.URL.Query()
+	identity := rbac.LoginIdentity{
+		Email:    query.Get("email"),
+		Provider: query.Get("provider"),
+		Groups:   query["group"],
+	}
+
+	login, err := s.rbacManager.EvaluateLogin(identity)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, login)
+}
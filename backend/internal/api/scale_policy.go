@@ -0,0 +1,85 @@
+package api
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// Settings holding the scale guardrails
+const (
+	scaleProtectedNamespacesSetting = "scale_protected_namespaces" // comma-separated, path.Match patterns allowed
+	scaleMaxReplicasSetting         = "scale_max_replicas"         // 0 or unset means unlimited
+)
+
+// scalePolicy is the set of guardrails every scale request must pass
+type scalePolicy struct {
+	ProtectedNamespaces []string
+	MaxReplicas         int32
+}
+
+// scalePolicy loads the scale guardrails from settings; unset or invalid values disable
+// the corresponding guard
+func (s *Server) scalePolicy() scalePolicy {
+	var policy scalePolicy
+
+	var settings []models.Setting
+	if err := s.db.Where("setting_key IN ?", []string{scaleProtectedNamespacesSetting, scaleMaxReplicasSetting}).Find(&settings).Error; err != nil {
+		return policy
+	}
+
+	for _, setting := range settings {
+		switch setting.Key {
+		case scaleProtectedNamespacesSetting:
+			for _, namespace := range strings.Split(setting.Value, ",") {
+				if namespace = strings.TrimSpace(namespace); namespace != "" {
+					policy.ProtectedNamespaces = append(policy.ProtectedNamespaces, namespace)
+				}
+			}
+		case scaleMaxReplicasSetting:
+			if value, err := strconv.ParseInt(setting.Value, 10, 32); err == nil && value > 0 {
+				policy.MaxReplicas = int32(value)
+			}
+		}
+	}
+
+	return policy
+}
+
+// check returns why scaling a workload in namespace to replicas is not allowed, or nil
+func (p scalePolicy) check(namespace string, replicas int32) error {
+	if replicas < 0 {
+		return fmt.Errorf("replicas cannot be negative")
+	}
+	if replicas == 0 {
+		for _, pattern := range p.ProtectedNamespaces {
+			if matched, _ := path.Match(pattern, namespace); matched {
+				return fmt.Errorf("namespace %s is protected from scaling to zero (%s setting)", namespace, scaleProtectedNamespacesSetting)
+			}
+		}
+	}
+	if p.MaxReplicas > 0 && replicas > p.MaxReplicas {
+		return fmt.Errorf("%d replicas exceeds the maximum of %d (%s setting)", replicas, p.MaxReplicas, scaleMaxReplicasSetting)
+	}
+	return nil
+}
+
+// patchReplicas returns spec.replicas from a spec patch, if the patch sets it
+func patchReplicas(patch map[string]interface{}) (int32, bool, error) {
+	spec, ok := patch["spec"].(map[string]interface{})
+	if !ok {
+		return 0, false, nil
+	}
+	value, ok := spec["replicas"]
+	if !ok {
+		return 0, false, nil
+	}
+	number, ok := value.(float64)
+	if !ok || number != float64(int32(number)) {
+		return 0, true, fmt.Errorf("spec.replicas must be an integer")
+	}
+	return int32(number), true, nil
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// secretRevealPermission is required to list the keys of a Secret
+const secretRevealPermission = "secret.reveal"
+
+// getSecretKeys lists the keys of a Secret and the sizes of their values, never the values.
+// Requires the secret.reveal permission; every attempt is recorded in the activity log.
+func (s *Server) getSecretKeys(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	clusterName := s.clusterService.Name(clusterID)
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+
+	if err := s.requirePermission(r, secretRevealPermission); err != nil {
+		s.logActivity(requestActor(r), "reveal", "Secret", resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Denied listing keys of Secret %s: %v", resourceID, err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Listing Secret keys not allowed: %v", err))
+		return
+	}
+
+	keys, err := s.k8sClient.GetSecretKeys(r.Context(), clusterID, namespace, name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get secret keys: %v", err))
+		return
+	}
+
+	s.logActivity(requestActor(r), "reveal", "Secret", resourceID, name, clusterID, clusterName, "success", fmt.Sprintf("Listed %d keys of Secret %s", len(keys.Keys), resourceID))
+	respondJSON(w, http.StatusOK, keys)
+}
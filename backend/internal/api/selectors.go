@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// listFilter parses the labelSelector and fieldSelector parameters, in Kubernetes selector
+// syntax such as app.kubernetes.io/part-of=payments
+func listFilter(r *http.Request) (k8s.ListFilter, error) {
+	query := r.URL.Query()
+	return k8s.NewListFilter(query.Get("labelSelector"), query.Get("fieldSelector"))
+}
+
+// storedListFilter parses the selectors of a list served from the database, whose field
+// selector can only use metadata.name and metadata.namespace
+func storedListFilter(r *http.Request) (k8s.ListFilter, error) {
+	filter, err := listFilter(r)
+	if err != nil {
+		return filter, err
+	}
+	return filter, filter.CheckStoredFields()
+}
+
+// filterResources returns the stored resources whose objects match a filter
+func filterResources(resources []models.FluxResource, filter k8s.ListFilter) []models.FluxResource {
+	if filter.IsZero() {
+		return resources
+	}
+	filtered := []models.FluxResource{}
+	for _, res := range resources {
+		obj := &unstructured.Unstructured{}
+		if res.Metadata == "" || json.Unmarshal([]byte(res.Metadata), &obj.Object) != nil {
+			// Without a stored object only the name and namespace are known
+			obj.Object = map[string]interface{}{}
+			obj.SetName(res.Name)
+			obj.SetNamespace(res.Namespace)
+		}
+		if filter.Matches(obj) {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
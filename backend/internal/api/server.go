@@ -3,21 +3,28 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/azure"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/bulk"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logbundle"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/rbac"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/service"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/webhooks"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/webui"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -26,32 +33,95 @@ import (
 
 // Server represents the API server
 type Server struct {
-	db            *database.DB
-	k8sClient     *k8s.Client
-	azureClient   *azure.Client
-	router        *mux.Router
-	encryptor     *encryption.Encryptor
-	oauthProvider *auth.OAuthProvider
-	sessionStore  *auth.SessionStore
-	authEnabled   bool
-	webhooks      *webhooks.Notifier
-	rbacManager   *rbac.Manager
+	db              *database.DB
+	k8sClient       k8s.ClusterClient
+	router          *mux.Router
+	basePath        string
+	apiV1           *mux.Router
+	encryptor       *encryption.Encryptor
+	oauthProvider   *auth.OAuthProvider
+	sessionStore    *auth.SessionStore
+	cookies         cookieConfig
+	deviceLogins    *auth.DeviceLoginStore
+	frontend        http.Handler
+	authEnabled     bool
+	webhooks        *webhooks.Notifier
+	rbacManager     *rbac.Manager
+	activities      *repository.ActivityRepository
+	clusterService  service.ClusterService
+	resourceService service.ResourceService
+	azureService    service.AzureService
+	registryService service.RegistryService
+	authenticators  []Authenticator // tried in order by authMiddleware
+	proxyAuth       *proxyAuthConfig // trusted reverse-proxy headers, see proxyAuthConfigFromEnv
+	logBundles      *logbundle.Manager
+	bulkJobs        *bulk.Manager
+	quotas          *actionQuotas
+	syncJobs        *syncJobs
+
+	// sharedVisibility limits users to the clusters shared with them, see cluster_sharing.go
+	sharedVisibility bool
+}
+
+// Services groups the domain services the API handlers depend on
+type Services struct {
+	Clusters  service.ClusterService
+	Resources service.ResourceService
+	Azure      service.AzureService
+	Registries service.RegistryService
+}
+
+// DefaultServices builds the database, Kubernetes and Azure backed services
+func DefaultServices(db *database.DB, k8sClient k8s.ClusterClient, encryptor *encryption.Encryptor) Services {
+	return Services{
+		Clusters:   service.NewClusterService(db, k8sClient, encryptor),
+		Resources:  service.NewResourceService(db, k8sClient),
+		Azure:      service.NewAzureService(db, azure.NewClient(), k8sClient, encryptor),
+		Registries: service.NewRegistryService(db, k8sClient, encryptor),
+	}
 }
 
 // NewServer creates a new API server
-func NewServer(db *database.DB, k8sClient *k8s.Client, encryptor *encryption.Encryptor, oauthProvider *auth.OAuthProvider, notifier *webhooks.Notifier) *Server {
-	s := &Server{
-		db:            db,
-		k8sClient:     k8sClient,
-		azureClient:   azure.NewClient(),
-		router:        mux.NewRouter(),
-		encryptor:     encryptor,
-		oauthProvider: oauthProvider,
-		sessionStore:  auth.NewSessionStore(),
-		authEnabled:   oauthProvider != nil,
-		webhooks:      notifier,
-		rbacManager:   rbac.NewManager(db),
+func NewServer(db *database.DB, k8sClient k8s.ClusterClient, encryptor *encryption.Encryptor, oauthProvider *auth.OAuthProvider, notifier *webhooks.Notifier) *Server {
+	return NewServerWithServices(db, k8sClient, encryptor, oauthProvider, notifier, DefaultServices(db, k8sClient, encryptor))
+}
+
+// NewServerWithServices creates a new API server using the given services,
+// allowing handlers to run against fakes
+func NewServerWithServices(db *database.DB, k8sClient k8s.ClusterClient, encryptor *encryption.Encryptor, oauthProvider *auth.OAuthProvider, notifier *webhooks.Notifier, services Services) *Server {
+	basePath := basePathFromEnv()
+	proxyAuth, proxyErr := proxyAuthConfigFromEnv()
+	if proxyErr != nil {
+		// Fail closed: the operator asked for authentication
+		log.Printf("Warning: proxy header authentication disabled, only anonymous access applies: %v", proxyErr)
 	}
+	s := &Server{
+		db:              db,
+		k8sClient:       k8sClient,
+		router:          mux.NewRouter(),
+		basePath:        basePath,
+		encryptor:       encryptor,
+		oauthProvider:   oauthProvider,
+		sessionStore:    sessionStoreFromEnv(db, encryptor),
+		cookies:         cookieConfigFromEnv(encryptor, basePath),
+		deviceLogins:    auth.NewDeviceLoginStore(),
+		frontend:        newFrontendHandler(basePath),
+		authEnabled:     oauthProvider != nil || proxyAuth != nil || proxyErr != nil,
+		proxyAuth:       proxyAuth,
+		webhooks:        notifier,
+		rbacManager:     rbac.NewManager(db),
+		activities:      repository.NewActivityRepository(db),
+		clusterService:  services.Clusters,
+		resourceService: services.Resources,
+		azureService:    services.Azure,
+		registryService: services.Registries,
+		logBundles:      logbundle.NewManager(k8sClient),
+		bulkJobs:        bulk.NewManager(services.Resources),
+		quotas:          actionQuotasFromEnv(),
+		syncJobs:        newSyncJobs(),
+	}
+	s.sharedVisibility = sharedVisibilityFromEnv(s.authEnabled)
+	s.authenticators = s.defaultAuthenticators()
 	s.routes()
 	
 	// Start session cleanup goroutine
@@ -61,9 +131,24 @@ func NewServer(db *database.DB, k8sClient *k8s.Client, encryptor *encryption.Enc
 	
 	// Start audit log cleanup goroutine
 	go s.cleanupAuditLogs()
+
+	// Start log bundle cleanup goroutine
+	go s.cleanupLogBundles()
+
+	// Start bulk job cleanup goroutine
+	go s.cleanupBulkJobs()
+
+	// Start sync job cleanup goroutine
+	go s.cleanupSyncJobs()
+
+	// Start weekly report scheduler
+	go s.scheduleWeeklyReports()
 	
 	// Load existing Azure subscriptions from database
 	s.loadAzureSubscriptions()
+
+	// Load stored registry credentials for image reports
+	s.loadRegistryCredentials()
 	
 	return s
 }
@@ -76,35 +161,55 @@ func (s *Server) routes() {
 	// Enable security headers
 	s.router.Use(securityHeadersMiddleware)
 	
+	// Enable per route group IP allowlists
+	s.router.Use(ipAllowlistMiddleware)
+	
 	// Enable input validation
 	s.router.Use(inputValidationMiddleware)
 	
+	// Enable request body size limits
+	s.router.Use(bodyLimitMiddleware)
+	
 	// Enable timeout middleware
 	s.router.Use(timeoutMiddleware)
 	
 	// Enable logging middleware
 	s.router.Use(loggingMiddleware)
+	
+	// Recover from handler panics (innermost, so the panic is logged and counted above)
+	s.router.Use(recoveryMiddleware)
 
 	// Auth routes (public)
 	if s.authEnabled {
-		s.router.HandleFunc("/api/v1/auth/login", s.handleAuthLogin).Methods("GET", "OPTIONS")
-		s.router.HandleFunc("/api/v1/auth/callback", s.handleAuthCallback).Methods("GET", "OPTIONS")
 		s.router.HandleFunc("/api/v1/auth/logout", s.handleAuthLogout).Methods("POST", "OPTIONS")
 		s.router.HandleFunc("/api/v1/auth/me", s.handleAuthMe).Methods("GET", "OPTIONS")
 		s.router.HandleFunc("/api/v1/auth/status", s.handleAuthStatus).Methods("GET", "OPTIONS")
 	}
+	// Sign-in flows need OAuth; behind an authenticating proxy the proxy signs users in
+	if s.oauthProvider != nil {
+		s.router.HandleFunc("/api/v1/auth/login", s.handleAuthLogin).Methods("GET", "OPTIONS")
+		s.router.HandleFunc("/api/v1/auth/callback", s.handleAuthCallback).Methods("GET", "OPTIONS")
+		s.router.Handle("/api/v1/auth/device/start", s.actionQuotaMiddleware(http.HandlerFunc(s.handleDeviceStart))).Methods("POST", "OPTIONS")
+		s.router.HandleFunc("/api/v1/auth/device/poll", s.handleDevicePoll).Methods("POST", "OPTIONS")
+	}
 
 	// API routes
 	api := s.router.PathPrefix("/api/v1").Subrouter()
+	api.Use(v1DeprecationMiddleware)
+	s.apiV1 = api
 	
 	// Apply auth middleware if enabled
 	if s.authEnabled {
 		api.Use(s.authMiddleware)
+		api.Use(s.clusterAccessMiddleware)
 	}
+	// Limit expensive actions per user and hour, or per client address without authentication
+	api.Use(s.actionQuotaMiddleware)
 
 	// Cluster management
 	api.HandleFunc("/clusters", s.listClusters).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters", s.createCluster).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/import", s.importClusters).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}", s.getCluster).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters/{id}", s.updateCluster).Methods("PUT", "OPTIONS")
 	api.HandleFunc("/clusters/{id}", s.deleteCluster).Methods("DELETE", "OPTIONS")
@@ -114,42 +219,119 @@ func (s *Server) routes() {
 	api.HandleFunc("/clusters/{id}/resources", s.listClusterResources).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/resources/tree", s.getResourceTree).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/flux/stats", s.getFluxStats).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/health", s.getFluxHealth).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/version", s.getFluxVersion).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/capabilities", s.getClusterCapabilities).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/access-review", s.reviewClusterAccess).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/order", s.getReconcileOrder).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/apply", s.applyManifests).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/suspend", s.suspendAllFluxResources).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/resume", s.resumeAllFluxResources).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/bulk", s.bulkFluxAction).Methods("POST", "OPTIONS")
+	api.HandleFunc("/bulk-jobs/{jobId}", s.getBulkJob).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/trends", s.getClusterTrends).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/quotas", s.getClusterQuotas).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/unmanaged", s.getUnmanagedResources).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/images", s.getImageFreshness).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}", s.createFluxResource).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}", s.getFluxResource).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}", s.updateFluxResource).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}", s.deleteNotificationResource).Methods("DELETE", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/reconcile", s.reconcileFluxResource).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/suspend", s.suspendFluxResource).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/resume", s.resumeFluxResource).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/resources", s.getFluxResourceChildren).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/triage", s.getFluxResourceTriage).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/commit", s.getSourceCommit).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/refs", s.getSourceRefs).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/logs/search", s.searchFluxResourceLogs).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/values", s.getHelmReleaseValues).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/history", s.getHelmReleaseHistory).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/rollback", s.rollbackHelmRelease).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/force", s.forceReconcileHelmRelease).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/reset", s.resetHelmReleaseFailures).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/flux/{kind}/{namespace}/{name}/diff", s.getKustomizationDiff).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/events", s.getResourceEvents).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/incident", s.getIncidentFeed).Methods("GET", "OPTIONS")
 	api.HandleFunc("/resources", s.listAllResources).Methods("GET", "OPTIONS")
 	api.HandleFunc("/resources/{id}", s.getResource).Methods("GET", "OPTIONS")
 	api.HandleFunc("/resources/reconcile", s.reconcileResource).Methods("POST", "OPTIONS")
 
 	// Sync resources from cluster
 	api.HandleFunc("/clusters/{id}/sync", s.syncClusterResources).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/sync/{jobId}", s.getSyncJob).Methods("GET", "OPTIONS")
 
 	// Resource management
 	api.HandleFunc("/clusters/{id}/resources/{kind}/{namespace}/{name}/scale", s.scaleResource).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/resources/{kind}/{namespace}/{name}/restart", s.restartResource).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/resources/{kind}/{namespace}/{name}/spec", s.updateResourceSpec).Methods("PUT", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}/logs", s.getPodLogs).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}/logs/stream", s.streamPodLogs).Methods("GET")
+	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}/exec", s.execPod).Methods("GET")
+	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}/portforward", s.portForwardPod).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/secrets/{namespace}/{name}/keys", s.getSecretKeys).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}/containers", s.getPodContainers).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}/metrics", s.getPodMetrics).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}/logs/download", s.downloadPodLogs).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/workloads/{kind}/{namespace}/{name}/logs/download", s.downloadWorkloadLogs).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/log-bundles", s.createLogBundle).Methods("POST", "OPTIONS")
+	api.HandleFunc("/log-bundles/{bundleId}", s.getLogBundle).Methods("GET", "OPTIONS")
+	api.HandleFunc("/log-bundles/{bundleId}/download", s.downloadLogBundle).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/pods/{namespace}/{name}", s.deletePod).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/namespaces/{namespace}/pods/delete", s.deletePods).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/namespaces", s.listNamespaces).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/namespaces", s.createNamespace).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/namespaces/{namespace}", s.deleteNamespace).Methods("DELETE", "OPTIONS")
 
 	// Resource diff and logs
 	api.HandleFunc("/clusters/{id}/resources/{kind}/{namespace}/{name}/manifest", s.getResourceManifest).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/resources/{kind}/{namespace}/{name}/yaml", s.getResourceYAML).Methods("GET", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/resources/{kind}/{namespace}/{name}/diff", s.getResourceDiff).Methods("GET", "OPTIONS")
 	api.HandleFunc("/logs/aggregated", s.getAggregatedLogs).Methods("GET", "OPTIONS")
 
+	// Monitoring integration
+	api.HandleFunc("/monitoring/prometheus-rules", s.getPrometheusRules).Methods("GET", "OPTIONS")
+	api.HandleFunc("/monitoring/grafana-dashboards", s.listGrafanaDashboards).Methods("GET", "OPTIONS")
+	api.HandleFunc("/monitoring/grafana-dashboards/{name}", s.getGrafanaDashboard).Methods("GET", "OPTIONS")
+
+	// System status
+	api.HandleFunc("/system/status", s.getSystemStatus).Methods("GET", "OPTIONS")
+
+	// Recorded events: history and a WebSocket stream that replays missed events
+	api.HandleFunc("/events/recent", s.getRecentEvents).Methods("GET", "OPTIONS")
+	api.HandleFunc("/events/stream", s.streamEvents).Methods("GET")
+
+	// Deployment timeline across clusters
+	api.HandleFunc("/timeline", s.getDeploymentTimeline).Methods("GET", "OPTIONS")
+
+	// First-run onboarding
+	api.HandleFunc("/onboarding", s.getOnboarding).Methods("GET", "OPTIONS")
+	api.HandleFunc("/onboarding/steps/{step}", s.updateOnboardingStep).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/onboarding/dismiss", s.dismissOnboarding).Methods("POST", "OPTIONS")
+	api.HandleFunc("/onboarding/reset", s.resetOnboarding).Methods("POST", "OPTIONS")
+	api.HandleFunc("/onboarding/encryption-key", s.generateEncryptionKey).Methods("POST", "OPTIONS")
+
 	// Settings
 	api.HandleFunc("/settings", s.getSettings).Methods("GET", "OPTIONS")
 	api.HandleFunc("/settings/{key}", s.updateSetting).Methods("PUT", "OPTIONS")
 
+	// Reports
+	api.HandleFunc("/reports/weekly", s.getWeeklyReport).Methods("GET", "OPTIONS")
+	api.HandleFunc("/reports/weekly/send", s.sendWeeklyReportNow).Methods("POST", "OPTIONS")
+	api.HandleFunc("/reports/templates", s.listReportTemplates).Methods("GET", "OPTIONS")
+	api.HandleFunc("/reports/templates", s.createReportTemplate).Methods("POST", "OPTIONS")
+	api.HandleFunc("/reports/templates/{id}", s.getReportTemplate).Methods("GET", "OPTIONS")
+	api.HandleFunc("/reports/templates/{id}", s.updateReportTemplate).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/reports/templates/{id}", s.deleteReportTemplate).Methods("DELETE", "OPTIONS")
+
 	// RBAC - Users
 	api.HandleFunc("/rbac/users", s.listUsers).Methods("GET", "OPTIONS")
 	api.HandleFunc("/rbac/users/{id}", s.getUser).Methods("GET", "OPTIONS")
 	api.HandleFunc("/rbac/users/{id}", s.updateUser).Methods("PUT", "OPTIONS")
 	api.HandleFunc("/rbac/users/{id}", s.deleteUser).Methods("DELETE", "OPTIONS")
 	api.HandleFunc("/rbac/users/{id}/roles", s.assignUserRoles).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/rbac/users/{id}/disable", s.disableUser).Methods("POST", "OPTIONS")
+	api.HandleFunc("/rbac/users/{id}/enable", s.enableUser).Methods("POST", "OPTIONS")
 
 	// RBAC - Roles
 	api.HandleFunc("/rbac/roles", s.listRoles).Methods("GET", "OPTIONS")
@@ -162,6 +344,23 @@ func (s *Server) routes() {
 	// RBAC - Permissions
 	api.HandleFunc("/rbac/permissions", s.listPermissions).Methods("GET", "OPTIONS")
 
+	// RBAC - Roles of new users
+	api.HandleFunc("/rbac/role-mappings", s.listRoleMappings).Methods("GET", "OPTIONS")
+	api.HandleFunc("/rbac/role-mappings", s.createRoleMapping).Methods("POST", "OPTIONS")
+	api.HandleFunc("/rbac/role-mappings/default", s.setDefaultRole).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/rbac/role-mappings/evaluate", s.evaluateRoleMappings).Methods("GET", "OPTIONS")
+	api.HandleFunc("/rbac/role-mappings/{id}", s.updateRoleMapping).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/rbac/role-mappings/{id}", s.deleteRoleMapping).Methods("DELETE", "OPTIONS")
+
+	// RBAC - The requesting user's own access
+	api.HandleFunc("/me/permissions", s.getMyPermissions).Methods("GET", "OPTIONS")
+	api.HandleFunc("/me/permissions/explain", s.explainMyPermission).Methods("GET", "OPTIONS")
+	api.HandleFunc("/me/quotas", s.getMyQuotas).Methods("GET", "OPTIONS")
+
+	// RBAC - Configuration as code
+	api.HandleFunc("/rbac/export", s.exportRBAC).Methods("GET", "OPTIONS")
+	api.HandleFunc("/rbac/import", s.importRBAC).Methods("POST", "OPTIONS")
+
 	// Activities (audit log)
 	api.HandleFunc("/activities", s.listActivities).Methods("GET", "OPTIONS")
 	api.HandleFunc("/activities/{id}", s.getActivity).Methods("GET", "OPTIONS")
@@ -169,7 +368,12 @@ func (s *Server) routes() {
 
 	// Cluster operations
 	api.HandleFunc("/clusters/{id}/favorite", s.toggleFavorite).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/archive", s.archiveCluster).Methods("POST", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/unarchive", s.unarchiveCluster).Methods("POST", "OPTIONS")
 	api.HandleFunc("/clusters/{id}/export", s.exportCluster).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/shares", s.listClusterShares).Methods("GET", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/shares", s.putClusterShare).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/clusters/{id}/shares/{shareId}", s.deleteClusterShare).Methods("DELETE", "OPTIONS")
 	api.HandleFunc("/resources/export", s.exportResources).Methods("GET", "OPTIONS")
 
 	// Azure AKS integration
@@ -181,7 +385,22 @@ func (s *Server) routes() {
 	api.HandleFunc("/azure/subscriptions/{id}/clusters", s.discoverAKSClusters).Methods("GET", "OPTIONS")
 	api.HandleFunc("/azure/subscriptions/{id}/sync", s.syncAKSClusters).Methods("POST", "OPTIONS")
 
+	// Container registry credentials
+	api.HandleFunc("/registries", s.listRegistryCredentials).Methods("GET", "OPTIONS")
+	api.HandleFunc("/registries", s.createRegistryCredential).Methods("POST", "OPTIONS")
+	api.HandleFunc("/registries/{id}", s.getRegistryCredential).Methods("GET", "OPTIONS")
+	api.HandleFunc("/registries/{id}", s.updateRegistryCredential).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/registries/{id}", s.deleteRegistryCredential).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/registries/{id}/test", s.testRegistryCredential).Methods("POST", "OPTIONS")
+
 	// OAuth provider management
+	// API tokens issued to the current user
+	api.HandleFunc("/auth/tokens", s.listAPITokens).Methods("GET", "OPTIONS")
+	api.HandleFunc("/auth/tokens/{id}", s.revokeAPIToken).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/auth/grants", s.listProviderGrants).Methods("GET", "OPTIONS")
+	api.HandleFunc("/auth/grants/{provider}/authorize", s.authorizeProviderGrant).Methods("GET", "OPTIONS")
+	api.HandleFunc("/auth/grants/{provider}", s.revokeProviderGrant).Methods("DELETE", "OPTIONS")
+
 	api.HandleFunc("/oauth/providers", s.listOAuthProviders).Methods("GET", "OPTIONS")
 	api.HandleFunc("/oauth/providers", s.createOAuthProvider).Methods("POST", "OPTIONS")
 	api.HandleFunc("/oauth/providers/{id}", s.getOAuthProvider).Methods("GET", "OPTIONS")
@@ -189,6 +408,9 @@ func (s *Server) routes() {
 	api.HandleFunc("/oauth/providers/{id}", s.deleteOAuthProvider).Methods("DELETE", "OPTIONS")
 	api.HandleFunc("/oauth/providers/{id}/test", s.testOAuthProvider).Methods("POST", "OPTIONS")
 
+	// API v2 (typed responses, falls back to v1 handlers)
+	s.routesV2()
+
 	// Health check endpoints
 	s.router.HandleFunc("/health", s.health).Methods("GET")
 	s.router.HandleFunc("/healthz", s.health).Methods("GET")
@@ -202,7 +424,7 @@ func (s *Server) routes() {
 
 	// Swagger documentation
 	s.router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"),
+		httpSwagger.URL(s.appPath("/swagger/doc.json")),
 		httpSwagger.DeepLinking(true),
 		httpSwagger.DocExpansion("list"),
 		httpSwagger.DomID("swagger-ui"),
@@ -230,6 +452,10 @@ func corsMiddleware(next http.Handler) http.Handler {
 
 // ServeHTTP implements http.Handler
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.basePath != "" {
+		s.stripBasePath(w, r)
+		return
+	}
 	s.router.ServeHTTP(w, r)
 }
 
@@ -291,59 +517,31 @@ func (s *Server) liveness(w http.ResponseWriter, r *http.Request) {
 
 // serveFrontend serves the frontend SPA and handles client-side routing
 func (s *Server) serveFrontend(w http.ResponseWriter, r *http.Request) {
-	// For SPA routing, serve index.html for all non-API routes
-	fs := http.Dir("./frontend/dist")
-	file, err := fs.Open(r.URL.Path)
-	if err != nil {
-		// File not found, serve index.html for SPA routing
-		indexFile, err := fs.Open("index.html")
-		if err != nil {
-			http.NotFound(w, r)
-			return
-		}
-		defer indexFile.Close()
-		http.ServeContent(w, r, "index.html", time.Now(), indexFile.(io.ReadSeeker))
-		return
-	}
-	defer file.Close()
-
-	// Check if it's a directory
-	stat, err := file.Stat()
-	if err != nil || stat.IsDir() {
-		// Serve index.html for directories
-		indexFile, err := fs.Open("index.html")
-		if err != nil {
-			http.NotFound(w, r)
-			return
-		}
-		defer indexFile.Close()
-		http.ServeContent(w, r, "index.html", time.Now(), indexFile.(io.ReadSeeker))
-		return
-	}
-
-	// Serve the actual file
-	http.ServeContent(w, r, r.URL.Path, stat.ModTime(), file.(io.ReadSeeker))
+	s.frontend.ServeHTTP(w, r)
 }
 
-// listClusters returns all registered clusters
+// listClusters returns registered clusters; archived clusters require ?include_archived=true
 func (s *Server) listClusters(w http.ResponseWriter, r *http.Request) {
-	var clusters []models.Cluster
-	if err := s.db.Select("id", "name", "description", "status", "created_at", "updated_at").
-		Order("created_at DESC").
-		Find(&clusters).Error; err != nil {
+	clusters, err := s.clusterService.List(s.clusterScope(r), r.URL.Query().Get("include_archived") == "true")
+	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to query clusters")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, clusters)
+	respondJSON(w, http.StatusOK, newClusterResponses(clusters))
 }
 
 // createCluster creates a new cluster
 func (s *Server) createCluster(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		KubeConfig  string `json:"kubeconfig"`
+		Name             string  `json:"name"`
+		Description      string  `json:"description"`
+		KubeConfig       string  `json:"kubeconfig"`
+		ClientQPS        float32 `json:"client_qps"`
+		ClientBurst      int     `json:"client_burst"`
+		ClientTimeout    int     `json:"client_timeout"`
+		ImpersonateUsers bool    `json:"impersonate_users"`
+		AllowDuplicate   bool    `json:"allow_duplicate"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -351,52 +549,105 @@ func (s *Server) createCluster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Name == "" || req.KubeConfig == "" {
-		respondError(w, http.StatusBadRequest, "Name and kubeconfig are required")
+	cluster, err := s.clusterService.Create(r.Context(), service.ClusterInput{
+		Name:           req.Name,
+		Description:    req.Description,
+		KubeConfig:     req.KubeConfig,
+		Limits: k8s.ClientLimits{
+			QPS:     req.ClientQPS,
+			Burst:   req.ClientBurst,
+			Timeout: time.Duration(req.ClientTimeout) * time.Second,
+		},
+		ImpersonateUsers: req.ImpersonateUsers,
+		Owner:            requestEmail(r),
+		AllowDuplicate:   req.AllowDuplicate,
+	})
+	var duplicate *service.DuplicateClusterError
+	if errors.As(err, &duplicate) {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":            duplicate.Error() + "; set allow_duplicate to register it anyway",
+			"existing_cluster": newClusterResponse(duplicate.Existing),
+		})
 		return
 	}
-
-	// Generate cluster ID
-	clusterID := uuid.New().String()
-
-	// Add cluster to k8s client
-	if err := s.k8sClient.AddCluster(clusterID, req.KubeConfig); err != nil {
-		respondError(w, http.StatusBadRequest, fmt.Sprintf("Failed to connect to cluster: %v", err))
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalid) && !errors.Is(err, service.ErrUnreachable) {
+			s.logActivity(requestActor(r), "create", "cluster", "", req.Name, "", req.Name, "failed", fmt.Sprintf("Error: %v", err))
+		}
+		respondServiceError(w, err, "Cluster not found", "Failed to save cluster")
 		return
 	}
 
-	// Check cluster health
-	status, _ := s.k8sClient.CheckClusterHealth(clusterID)
+	// Log successful creation
+	s.logActivity(requestActor(r), "create", "cluster", cluster.ID, cluster.Name, cluster.ID, cluster.Name, "success", fmt.Sprintf("Cluster created with status: %s", cluster.Status))
 
-	// Encrypt kubeconfig before storing
-	encryptedKubeconfig, err := s.encryptor.Encrypt(req.KubeConfig)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to encrypt kubeconfig")
-		log.Printf("Encryption error: %v", err)
-		return
+	respondJSON(w, http.StatusCreated, newClusterResponse(cluster))
+}
+
+// importClusters registers contexts of a multi-context kubeconfig as separate clusters,
+// the selected ones or all of them. With dry_run the contexts are only listed, with the
+// cluster already registered for each API server, so callers can choose which to import.
+func (s *Server) importClusters(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		KubeConfig     string            `json:"kubeconfig"`
+		Contexts       []string          `json:"contexts"`
+		Names          map[string]string `json:"names"`
+		Description    string            `json:"description"`
+		AllowDuplicate bool              `json:"allow_duplicate"`
+		DryRun         bool              `json:"dry_run"`
 	}
 
-	// Save to database with encrypted kubeconfig
-	cluster := models.Cluster{
-		ID:          clusterID,
-		Name:        req.Name,
-		Description: req.Description,
-		KubeConfig:  encryptedKubeconfig,
-		Status:      status,
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
 	}
 
-	if err := s.db.Create(&cluster).Error; err != nil {
-		s.logActivity("create", "cluster", clusterID, req.Name, clusterID, req.Name, "failed", fmt.Sprintf("Database error: %v", err))
-		respondError(w, http.StatusInternalServerError, "Failed to save cluster")
+	results, err := s.clusterService.Import(r.Context(), service.ClusterImport{
+		KubeConfig:     req.KubeConfig,
+		Contexts:       req.Contexts,
+		Names:          req.Names,
+		Description:    req.Description,
+		Owner:          requestEmail(r),
+		AllowDuplicate: req.AllowDuplicate,
+		DryRun:         req.DryRun,
+	})
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to import clusters")
 		return
 	}
 
-	// Log successful creation
-	s.logActivity("create", "cluster", clusterID, req.Name, clusterID, req.Name, "success", fmt.Sprintf("Cluster created with status: %s", status))
+	imported := 0
+	responses := make([]ClusterImportResponse, 0, len(results))
+	for _, result := range results {
+		response := ClusterImportResponse{Context: result.Context}
+		if result.Existing != nil {
+			existing := newClusterResponse(result.Existing)
+			response.ExistingCluster = &existing
+		}
+		var svcErr *service.Error
+		switch {
+		case result.Err == nil && result.Cluster != nil:
+			cluster := newClusterResponse(result.Cluster)
+			response.Cluster = &cluster
+			imported++
+			s.logActivity(requestActor(r), "create", "cluster", result.Cluster.ID, result.Cluster.Name, result.Cluster.ID, result.Cluster.Name, "success",
+				fmt.Sprintf("Cluster imported from kubeconfig context %s with status: %s", result.Context.Name, result.Cluster.Status))
+		case errors.Is(result.Err, service.ErrDuplicate):
+			response.Error = result.Err.Error() + "; set allow_duplicate to register it anyway"
+		case errors.As(result.Err, &svcErr):
+			response.Error = svcErr.Error()
+		case result.Err != nil:
+			log.Printf("Warning: Failed to import kubeconfig context %s: %v", result.Context.Name, result.Err)
+			s.logActivity(requestActor(r), "create", "cluster", "", result.Context.Name, "", result.Context.Name, "failed", fmt.Sprintf("Error: %v", result.Err))
+			response.Error = "Failed to save cluster"
+		}
+		responses = append(responses, response)
+	}
 
-	// Clear kubeconfig from response
-	cluster.KubeConfig = ""
-	respondJSON(w, http.StatusCreated, cluster)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"imported": imported,
+		"results":  responses,
+	})
 }
 
 // getCluster returns a specific cluster
@@ -404,19 +655,13 @@ func (s *Server) getCluster(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	var cluster models.Cluster
-	if err := s.db.Select("id", "name", "description", "status", "created_at", "updated_at").
-		Where("id = ?", id).
-		First(&cluster).Error; err != nil {
-		if err.Error() == "record not found" {
-			respondError(w, http.StatusNotFound, "Cluster not found")
-		} else {
-			respondError(w, http.StatusInternalServerError, "Failed to query cluster")
-		}
+	cluster, err := s.clusterService.Get(id)
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to query cluster")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, cluster)
+	respondJSON(w, http.StatusOK, newClusterResponse(cluster))
 }
 
 // updateCluster updates a cluster
@@ -425,10 +670,16 @@ func (s *Server) updateCluster(w http.ResponseWriter, r *http.Request) {
 	id := vars["id"]
 
 	var req struct {
-		Name                string `json:"name"`
-		Description         string `json:"description"`
-		KubeConfig          string `json:"kubeconfig"`
-		HealthCheckInterval *int   `json:"health_check_interval"`
+		Name                string   `json:"name"`
+		Description         string   `json:"description"`
+		KubeConfig          string   `json:"kubeconfig"`
+		HealthCheckInterval *int     `json:"health_check_interval"`
+		ClientQPS           *float32 `json:"client_qps"`
+		ClientBurst         *int     `json:"client_burst"`
+		ClientTimeout       *int     `json:"client_timeout"`
+		ImpersonateUsers    *bool    `json:"impersonate_users"`
+		Version             *int     `json:"version"`
+		ConfirmServerChange bool     `json:"confirm_server_change"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -436,53 +687,47 @@ func (s *Server) updateCluster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update k8s client if kubeconfig is provided
-	if req.KubeConfig != "" {
-		if err := s.k8sClient.AddCluster(id, req.KubeConfig); err != nil {
-			respondError(w, http.StatusBadRequest, fmt.Sprintf("Failed to connect to cluster: %v", err))
-			return
-		}
+	clusterName := s.clusterService.Name(id)
+	before, _ := s.clusterService.Get(id)
 
-		// Encrypt kubeconfig before storing
-		encryptedKubeconfig, err := s.encryptor.Encrypt(req.KubeConfig)
-		if err != nil {
-			respondError(w, http.StatusInternalServerError, "Failed to encrypt kubeconfig")
-			log.Printf("Encryption error: %v", err)
-			return
+	updateFields, err := s.clusterService.Update(r.Context(), id, service.ClusterUpdate{
+		Name:                req.Name,
+		Description:         req.Description,
+		KubeConfig:          req.KubeConfig,
+		HealthCheckInterval: req.HealthCheckInterval,
+		ClientQPS:           req.ClientQPS,
+		ClientBurst:         req.ClientBurst,
+		ClientTimeout:       req.ClientTimeout,
+		ImpersonateUsers:    req.ImpersonateUsers,
+		Version:             req.Version,
+		ConfirmServerChange: req.ConfirmServerChange,
+	})
+	if err != nil {
+		if !repository.IsNotFound(err) && !repository.IsConflict(err) && !errors.Is(err, service.ErrUnreachable) {
+			s.logActivity(requestActor(r), "update", "cluster", id, clusterName, id, clusterName, "failed", fmt.Sprintf("Database error: %v", err))
 		}
-		req.KubeConfig = encryptedKubeconfig
+		respondServiceError(w, err, "Cluster not found", "Failed to update cluster")
+		return
 	}
 
-	// Update database
-	updates := make(map[string]interface{})
-	if req.Name != "" {
-		updates["name"] = req.Name
-	}
-	if req.Description != "" {
-		updates["description"] = req.Description
+	// Log successful update with the old and new values; the kubeconfig itself is never recorded
+	changes := &activityChangeSet{}
+	if after, err := s.clusterService.Get(id); err == nil && before != nil {
+		changes.change("name", before.Name, after.Name)
+		changes.change("description", before.Description, after.Description)
+		changes.change("health_check_interval", before.HealthCheckInterval, after.HealthCheckInterval)
+		changes.change("client_qps", before.ClientQPS, after.ClientQPS)
+		changes.change("client_burst", before.ClientBurst, after.ClientBurst)
+		changes.change("client_timeout", before.ClientTimeout, after.ClientTimeout)
+		changes.change("impersonate_users", before.ImpersonateUsers, after.ImpersonateUsers)
+		changes.change("server_url", before.ServerURL, after.ServerURL)
+		changes.change("ca_fingerprint", before.CAFingerprint, after.CAFingerprint)
 	}
 	if req.KubeConfig != "" {
-		updates["kubeconfig"] = req.KubeConfig
-	}
-	if req.HealthCheckInterval != nil {
-		updates["health_check_interval"] = *req.HealthCheckInterval
+		changes.param("kubeconfig_replaced", true)
+		changes.param("confirm_server_change", req.ConfirmServerChange)
 	}
-
-	var cluster models.Cluster
-	s.db.Select("name").Where("id = ?", id).First(&cluster)
-
-	if err := s.db.Model(&models.Cluster{}).Where("id = ?", id).Updates(updates).Error; err != nil {
-		s.logActivity("update", "cluster", id, cluster.Name, id, cluster.Name, "failed", fmt.Sprintf("Database error: %v", err))
-		respondError(w, http.StatusInternalServerError, "Failed to update cluster")
-		return
-	}
-
-	// Log successful update
-	updateFields := []string{}
-	for k := range updates {
-		updateFields = append(updateFields, k)
-	}
-	s.logActivity("update", "cluster", id, cluster.Name, id, cluster.Name, "success", fmt.Sprintf("Updated fields: %v", updateFields))
+	s.logActivityDetails(requestActor(r), "update", "cluster", id, clusterName, id, clusterName, "success", fmt.Sprintf("Updated fields: %v", updateFields), changes)
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Cluster updated"})
 }
@@ -492,17 +737,18 @@ func (s *Server) deleteCluster(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	var cluster models.Cluster
-	s.db.Select("name").Where("id = ?", id).First(&cluster)
+	clusterName := s.clusterService.Name(id)
 
-	if err := s.db.Delete(&models.Cluster{}, "id = ?", id).Error; err != nil {
-		s.logActivity("delete", "cluster", id, cluster.Name, id, cluster.Name, "failed", fmt.Sprintf("Database error: %v", err))
-		respondError(w, http.StatusInternalServerError, "Failed to delete cluster")
+	if err := s.clusterService.Delete(id); err != nil {
+		if !repository.IsNotFound(err) {
+			s.logActivity(requestActor(r), "delete", "cluster", id, clusterName, id, clusterName, "failed", fmt.Sprintf("Database error: %v", err))
+		}
+		respondServiceError(w, err, "Cluster not found", "Failed to delete cluster")
 		return
 	}
 
 	// Log successful deletion
-	s.logActivity("delete", "cluster", id, cluster.Name, id, cluster.Name, "success", "Cluster deleted")
+	s.logActivity(requestActor(r), "delete", "cluster", id, clusterName, id, clusterName, "success", "Cluster deleted")
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Cluster deleted"})
 }
@@ -512,43 +758,41 @@ func (s *Server) checkClusterHealth(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	status, err := s.k8sClient.CheckClusterHealth(id)
+	health, err := s.clusterService.CheckHealth(r.Context(), id)
 	if err != nil {
-		// Update database
-		s.db.Model(&models.Cluster{}).Where("id = ?", id).Update("status", status)
-		respondError(w, http.StatusServiceUnavailable, fmt.Sprintf("Cluster unhealthy: %v", err))
+		if errors.Is(err, service.ErrUnreachable) {
+			respondError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		respondServiceError(w, err, "Cluster not found", "Failed to check cluster health")
 		return
 	}
 
-	// Update database
-	s.db.Model(&models.Cluster{}).Where("id = ?", id).Update("status", status)
+	response := map[string]string{"status": health.Status}
+	if health.FluxStatus != "" {
+		response["flux_status"] = health.FluxStatus
+		response["flux_message"] = health.FluxMessage
+	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"status": status})
+	respondJSON(w, http.StatusOK, response)
 }
 
-// syncClusterResources syncs resources from a cluster to the database
-func (s *Server) syncClusterResources(w http.ResponseWriter, r *http.Request) {
+// getFluxHealth inspects the Flux controllers of a cluster and reports which are degraded
+func (s *Server) getFluxHealth(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	clusterID := vars["id"]
+	id := vars["id"]
 
-	// Get resources from cluster
-	resources, err := s.k8sClient.GetFluxResources(clusterID)
+	health, err := s.clusterService.FluxHealth(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get resources: %v", err))
-		return
-	}
-
-	// Save to database
-	for _, res := range resources {
-		if err := s.db.Save(&res).Error; err != nil {
-			log.Printf("Failed to save resource %s: %v", res.ID, err)
+		if errors.Is(err, service.ErrUnreachable) {
+			respondError(w, http.StatusServiceUnavailable, err.Error())
+			return
 		}
+		respondServiceError(w, err, "Cluster not found", "Failed to check Flux health")
+		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "Resources synced",
-		"count":   len(resources),
-	})
+	respondJSON(w, http.StatusOK, health)
 }
 
 // listClusterResources lists all resources for a specific cluster
@@ -556,36 +800,38 @@ func (s *Server) listClusterResources(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	clusterID := vars["id"]
 
-	var resources []models.FluxResource
-	if err := s.db.Where("cluster_id = ?", clusterID).
-		Order("kind, namespace, name").
-		Find(&resources).Error; err != nil {
+	filter, err := storedListFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resources, err := s.resourceService.ListByCluster(clusterID)
+	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to query resources")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resources)
+	respondJSON(w, http.StatusOK, filterResources(resources, filter))
 }
 
 // listAllResources lists all resources across all clusters
 func (s *Server) listAllResources(w http.ResponseWriter, r *http.Request) {
 	kind := r.URL.Query().Get("kind")
 
-	query := s.db.Model(&models.FluxResource{})
-
-	if kind != "" {
-		query = query.Where("kind = ?", kind).Order("cluster_id, namespace, name")
-	} else {
-		query = query.Order("cluster_id, kind, namespace, name")
+	filter, err := storedListFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	var resources []models.FluxResource
-	if err := query.Find(&resources).Error; err != nil {
+	resources, err := s.resourceService.List(s.clusterScope(r), kind)
+	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to query resources")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resources)
+	respondJSON(w, http.StatusOK, filterResources(resources, filter))
 }
 
 // getResource returns a specific resource
@@ -593,13 +839,9 @@ func (s *Server) getResource(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	var res models.FluxResource
-	if err := s.db.Where("id = ?", id).First(&res).Error; err != nil {
-		if err.Error() == "record not found" {
-			respondError(w, http.StatusNotFound, "Resource not found")
-		} else {
-			respondError(w, http.StatusInternalServerError, "Failed to query resource")
-		}
+	res, err := s.resourceService.Get(s.clusterScope(r), id)
+	if err != nil {
+		respondServiceError(w, err, "Resource not found", "Failed to query resource")
 		return
 	}
 
@@ -615,14 +857,37 @@ func (s *Server) reconcileResource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := context.Background()
-	err := s.k8sClient.ReconcileResource(ctx, req.ClusterID, req.Kind, req.Namespace, req.Name)
+	// The cluster comes from the body, so clusterAccessMiddleware does not cover it
+	if status, err := s.checkClusterAccess(r, req.ClusterID, repository.AccessWrite); err != nil {
+		respondError(w, status, err.Error())
+		return
+	}
+	ctx, status, err := s.reconcileContext(r, req.Kind, req.Force)
+	if err != nil {
+		respondError(w, status, err.Error())
+		return
+	}
+	if r.URL.Query().Get("wait") == "true" {
+		s.reconcileAndWait(w, r, ctx, req.ClusterID, s.clusterService.Name(req.ClusterID), req.Kind, req.Namespace, req.Name, req.WithSource)
+		return
+	}
+	if req.WithSource {
+		source, err := s.resourceService.ReconcileWithSource(ctx, req.ClusterID, req.Kind, req.Namespace, req.Name)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to reconcile: %v", err))
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]interface{}{"message": "Reconciliation triggered", "source": source})
+		return
+	}
+
+	err = s.resourceService.Reconcile(ctx, req.ClusterID, req.Kind, req.Namespace, req.Name)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to reconcile: %v", err))
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Reconciliation triggered"})
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "Reconciliation triggered", "forced": req.Force})
 }
 
 // getFluxStats returns statistics about Flux resources in a cluster
@@ -639,6 +904,34 @@ func (s *Server) getFluxStats(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, stats)
 }
 
+// getFluxVersion reports the Flux distribution version and components installed on a cluster
+func (s *Server) getFluxVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+
+	version, err := s.k8sClient.GetFluxVersion(r.Context(), clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get Flux version: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, version)
+}
+
+// getClusterCapabilities reports which Flux kinds a cluster serves and at which versions
+func (s *Server) getClusterCapabilities(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+
+	capabilities, err := s.k8sClient.GetCapabilities(clusterID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to discover cluster capabilities: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, capabilities)
+}
+
 // getFluxResource returns details of a specific Flux resource
 func (s *Server) getFluxResource(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -656,6 +949,10 @@ func (s *Server) getFluxResource(w http.ResponseWriter, r *http.Request) {
 	// Find the matching resource
 	for _, res := range resources {
 		if res.Kind == kind && res.Namespace == namespace && res.Name == name {
+			// Live objects carry no ID; report the stored one once the resource has been synced
+			if stored, err := s.resourceService.GetByKey(clusterID, kind, namespace, name); err == nil {
+				res.ID = stored.ID
+			}
 			respondJSON(w, http.StatusOK, res)
 			return
 		}
@@ -672,21 +969,92 @@ func (s *Server) reconcileFluxResource(w http.ResponseWriter, r *http.Request) {
 	namespace := vars["namespace"]
 	name := vars["name"]
 
-	var cluster models.Cluster
-	s.db.Select("name").Where("id = ?", clusterID).First(&cluster)
+	clusterName := s.clusterService.Name(clusterID)
 
-	ctx := context.Background()
-	err := s.k8sClient.ReconcileResource(ctx, clusterID, kind, namespace, name)
+	ctx, status, err := s.reconcileContext(r, kind, r.URL.Query().Get("force") == "true")
+	if err != nil {
+		respondError(w, status, err.Error())
+		return
+	}
+	if r.URL.Query().Get("wait") == "true" {
+		s.reconcileAndWait(w, r, ctx, clusterID, clusterName, kind, namespace, name, r.URL.Query().Get("with_source") == "true")
+		return
+	}
+	if r.URL.Query().Get("with_source") == "true" {
+		s.reconcileFluxResourceWithSource(w, r, ctx, clusterID, clusterName, kind, namespace, name)
+		return
+	}
+
+	err = s.resourceService.Reconcile(ctx, clusterID, kind, namespace, name)
 	if err != nil {
-		s.logActivity("reconcile", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, cluster.Name, "failed", fmt.Sprintf("Error: %v", err))
+		s.logActivity(requestActor(r), "reconcile", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
 		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to reconcile: %v", err))
 		return
 	}
 
 	// Log successful reconciliation
-	s.logActivity("reconcile", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, cluster.Name, "success", fmt.Sprintf("Reconciled %s/%s", namespace, name))
+	message := fmt.Sprintf("Reconciled %s/%s", namespace, name)
+	if k8s.ForceFromContext(ctx) {
+		message = fmt.Sprintf("Force reconciled %s/%s", namespace, name)
+	}
+	s.logActivityDetails(requestActor(r), "reconcile", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "success", message, reconcileChanges(ctx))
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "Reconciliation triggered", "forced": k8s.ForceFromContext(ctx)})
+}
+
+// reconcileContext returns the context of a reconcile request, marked as forced when force
+// is set, which only HelmReleases support, like flux reconcile --force. Forcing requires the
+// resource.update permission, as the force endpoint does. Errors come with their status.
+func (s *Server) reconcileContext(r *http.Request, kind string, force bool) (context.Context, int, error) {
+	ctx := s.provenanceContext(r, "reconcile")
+	if !force {
+		return ctx, 0, nil
+	}
+	if kind != "HelmRelease" {
+		return nil, http.StatusBadRequest, fmt.Errorf("force is only supported for HelmReleases, not %ss", kind)
+	}
+	if err := s.requirePermission(r, resourceUpdatePermission); err != nil {
+		return nil, http.StatusForbidden, fmt.Errorf("forced reconcile not allowed: %v", err)
+	}
+	return k8s.WithForce(ctx), 0, nil
+}
+
+// reconcileChanges records a forced reconcile in its activity, or returns nil
+func reconcileChanges(ctx context.Context) *activityChangeSet {
+	if !k8s.ForceFromContext(ctx) {
+		return nil
+	}
+	changes := &activityChangeSet{}
+	changes.param("force", true)
+	return changes
+}
+
+// reconcileFluxResourceWithSource reconciles the source of a Kustomization or HelmRelease,
+// waits for its artifact and then reconciles the resource, like flux reconcile --with-source
+func (s *Server) reconcileFluxResourceWithSource(w http.ResponseWriter, r *http.Request, ctx context.Context, clusterID, clusterName, kind, namespace, name string) {
+	resourceID := fmt.Sprintf("%s/%s", namespace, name)
+	source, err := s.resourceService.ReconcileWithSource(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		s.logActivity(requestActor(r), "reconcile", kind, resourceID, name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to reconcile: %v", err))
+		return
+	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Reconciliation triggered"})
+	message := fmt.Sprintf("Reconciled %s with source %s %s/%s", resourceID, source.Kind, source.Namespace, source.Name)
+	if source.Updated {
+		message += fmt.Sprintf(" (revision %s)", source.Revision)
+	}
+	changes := &activityChangeSet{}
+	changes.param("with_source", true)
+	if k8s.ForceFromContext(ctx) {
+		changes.param("force", true)
+	}
+	changes.param("source", fmt.Sprintf("%s/%s/%s", source.Kind, source.Namespace, source.Name))
+	if source.Updated {
+		changes.change("source.revision", nil, source.Revision)
+	}
+	s.logActivityDetails(requestActor(r), "reconcile", kind, resourceID, name, clusterID, clusterName, "success", message, changes)
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "Reconciliation triggered", "source": source})
 }
 
 // suspendFluxResource suspends reconciliation for a Flux resource
@@ -697,19 +1065,22 @@ func (s *Server) suspendFluxResource(w http.ResponseWriter, r *http.Request) {
 	namespace := vars["namespace"]
 	name := vars["name"]
 
-	var cluster models.Cluster
-	s.db.Select("name").Where("id = ?", clusterID).First(&cluster)
+	clusterName := s.clusterService.Name(clusterID)
 
-	ctx := context.Background()
-	err := s.k8sClient.SuspendResource(ctx, clusterID, kind, namespace, name)
+	changes := &activityChangeSet{}
+	wasSuspended := s.storedSuspend(clusterID, kind, namespace, name)
+
+	ctx := s.provenanceContext(r, "suspend")
+	err := s.resourceService.Suspend(ctx, clusterID, kind, namespace, name)
 	if err != nil {
-		s.logActivity("suspend", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, cluster.Name, "failed", fmt.Sprintf("Error: %v", err))
+		s.logActivity(requestActor(r), "suspend", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
 		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to suspend: %v", err))
 		return
 	}
 
 	// Log successful suspension
-	s.logActivity("suspend", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, cluster.Name, "success", fmt.Sprintf("Suspended %s/%s", namespace, name))
+	changes.change("spec.suspend", wasSuspended, true)
+	s.logActivityDetails(requestActor(r), "suspend", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "success", fmt.Sprintf("Suspended %s/%s", namespace, name), changes)
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Resource suspended"})
 }
@@ -722,19 +1093,22 @@ func (s *Server) resumeFluxResource(w http.ResponseWriter, r *http.Request) {
 	namespace := vars["namespace"]
 	name := vars["name"]
 
-	var cluster models.Cluster
-	s.db.Select("name").Where("id = ?", clusterID).First(&cluster)
+	clusterName := s.clusterService.Name(clusterID)
 
-	ctx := context.Background()
-	err := s.k8sClient.ResumeResource(ctx, clusterID, kind, namespace, name)
+	changes := &activityChangeSet{}
+	wasSuspended := s.storedSuspend(clusterID, kind, namespace, name)
+
+	ctx := s.provenanceContext(r, "resume")
+	err := s.resourceService.Resume(ctx, clusterID, kind, namespace, name)
 	if err != nil {
-		s.logActivity("resume", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, cluster.Name, "failed", fmt.Sprintf("Error: %v", err))
+		s.logActivity(requestActor(r), "resume", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "failed", fmt.Sprintf("Error: %v", err))
 		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to resume: %v", err))
 		return
 	}
 
 	// Log successful resume
-	s.logActivity("resume", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, cluster.Name, "success", fmt.Sprintf("Resumed %s/%s", namespace, name))
+	changes.change("spec.suspend", wasSuspended, false)
+	s.logActivityDetails(requestActor(r), "resume", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, clusterName, "success", fmt.Sprintf("Resumed %s/%s", namespace, name), changes)
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Resource resumed"})
 }
@@ -772,6 +1146,30 @@ func respondError(w http.ResponseWriter, status int, message string) {
 	respondJSON(w, status, map[string]string{"error": message})
 }
 
+// respondServiceError maps service and repository errors to HTTP responses
+func respondServiceError(w http.ResponseWriter, err error, notFoundMessage, failureMessage string) {
+	var svcErr *service.Error
+	switch {
+	case repository.IsNotFound(err):
+		respondError(w, http.StatusNotFound, notFoundMessage)
+	case repository.IsConflict(err):
+		respondError(w, http.StatusConflict, "The record was modified by another request; reload it and try again")
+	case errors.As(err, &svcErr) && errors.Is(err, service.ErrInvalid):
+		respondError(w, http.StatusBadRequest, svcErr.Error())
+	case errors.As(err, &svcErr) && errors.Is(err, service.ErrUnreachable):
+		respondError(w, http.StatusBadRequest, svcErr.Error())
+	case errors.As(err, &svcErr) && errors.Is(err, service.ErrUnauthorized):
+		respondError(w, http.StatusUnauthorized, svcErr.Error())
+	case errors.As(err, &svcErr) && errors.Is(err, service.ErrServerChange):
+		respondError(w, http.StatusConflict, svcErr.Error())
+	case errors.As(err, &svcErr) && errors.Is(err, service.ErrArchived):
+		respondError(w, http.StatusConflict, svcErr.Error())
+	default:
+		log.Printf("Warning: %v", err)
+		respondError(w, http.StatusInternalServerError, failureMessage)
+	}
+}
+
 // getSettings returns all settings
 func (s *Server) getSettings(w http.ResponseWriter, r *http.Request) {
 	var settings []models.Setting
@@ -802,6 +1200,14 @@ func (s *Server) updateSetting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	changes := &activityChangeSet{}
+	var previous models.Setting
+	if err := s.db.Where("setting_key = ?", key).First(&previous).Error; err == nil {
+		changes.change("value", previous.Value, req.Value)
+	} else {
+		changes.change("value", nil, req.Value)
+	}
+
 	// Use GORM's Save which does an upsert (insert or update)
 	setting := models.Setting{
 		Key:   key,
@@ -810,24 +1216,36 @@ func (s *Server) updateSetting(w http.ResponseWriter, r *http.Request) {
 	
 	// Save will update if exists, create if not
 	if err := s.db.Where(models.Setting{Key: key}).Assign(models.Setting{Value: req.Value}).FirstOrCreate(&setting).Error; err != nil {
-		s.logActivity("update", "setting", key, key, "", "", "failed", fmt.Sprintf("Database error: %v", err))
+		s.logActivity(requestActor(r), "update", "setting", key, key, "", "", "failed", fmt.Sprintf("Database error: %v", err))
 		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to save setting: %v", err))
 		return
 	}
 
 	// Log successful settings update
-	s.logActivity("update", "setting", key, key, "", "", "success", fmt.Sprintf("Updated %s to %s", key, req.Value))
+	s.logActivityDetails(requestActor(r), "update", "setting", key, key, "", "", "success", fmt.Sprintf("Updated %s to %s", key, req.Value), changes)
 
 	respondJSON(w, http.StatusOK, setting)
 }
 
-// getResourceTree returns hierarchical tree of all resources in a cluster
+// getResourceTree returns hierarchical tree of all resources in a cluster, narrowed by the
+// labelSelector and fieldSelector parameters
 func (s *Server) getResourceTree(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	clusterID := vars["id"]
 
+	filter, err := listFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Trees are cached for a short while; refresh=true rebuilds it, such as after a change
+	if r.URL.Query().Get("refresh") == "true" {
+		s.k8sClient.InvalidateResourceTree(clusterID)
+	}
+
 	ctx := r.Context()
-	tree, err := s.k8sClient.GetResourceTree(ctx, clusterID)
+	tree, err := s.k8sClient.GetResourceTree(ctx, clusterID, filter)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get resource tree: %v", err))
 		return
@@ -839,7 +1257,9 @@ func (s *Server) getResourceTree(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// updateFluxResource updates a Flux resource configuration
+// updateFluxResource updates a Flux resource configuration from a JSON object of spec
+// fields, or a patch of its spec; see decodeUpdate. Like spec updates, it passes the spec
+// update allowlist and scale guardrails.
 func (s *Server) updateFluxResource(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	clusterID := vars["id"]
@@ -847,15 +1267,22 @@ func (s *Server) updateFluxResource(w http.ResponseWriter, r *http.Request) {
 	namespace := vars["namespace"]
 	name := vars["name"]
 
-	var patch map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	body, ok := decodeUpdate(w, r)
+	if !ok || !s.checkUpdate(w, r, clusterID, kind, namespace, name, body) {
 		return
 	}
 
-	ctx := r.Context()
-	if err := s.k8sClient.UpdateFluxResource(ctx, clusterID, kind, namespace, name, patch); err != nil {
-		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update resource: %v", err))
+	ctx := s.provenanceContext(r, "update")
+	force := r.URL.Query().Get("force") == "true" && body.PatchType == ""
+	var err error
+	if body.PatchType == "" {
+		err = s.resourceService.Update(ctx, clusterID, kind, namespace, name, body.Fields, force)
+	} else {
+		err = s.resourceService.Patch(ctx, clusterID, kind, namespace, name, k8s.ResourcePatch{Type: body.PatchType, Data: body.Raw})
+	}
+	s.logUpdate(r, clusterID, kind, namespace, name, body, force, err)
+	if err != nil {
+		respondUpdateError(w, err)
 		return
 	}
 
@@ -880,11 +1307,23 @@ respondError(w, http.StatusBadRequest, "Invalid request body")
 return
 }
 
-ctx := r.Context()
+if err := s.scalePolicy().check(namespace, req.Replicas); err != nil {
+s.logActivity(requestActor(r), "scale", kind, fmt.Sprintf("%s/%s", namespace, name), name, clusterID, s.clusterService.Name(clusterID), "failed", fmt.Sprintf("Blocked by scale policy: %v", err))
+respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Scale blocked: %v", err))
+return
+}
+
+ctx := s.provenanceContext(r, "scale")
+details := workloadActionDetails{
+RequestedReplicas: &req.Replicas,
+Before:            s.snapshotWorkload(ctx, clusterID, kind, namespace, name),
+}
 if err := s.k8sClient.ScaleResource(ctx, clusterID, kind, namespace, name, req.Replicas); err != nil {
+s.logWorkloadAction(requestActor(r), "scale", kind, namespace, name, clusterID, "failed", fmt.Sprintf("Error: %v", err), details)
 respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to scale resource: %v", err))
 return
 }
+s.logWorkloadAction(requestActor(r), "scale", kind, namespace, name, clusterID, "success", fmt.Sprintf("Scaled %s/%s to %d replicas", namespace, name, req.Replicas), details)
 
 respondJSON(w, http.StatusOK, map[string]string{"message": "Resource scaled successfully"})
 }
@@ -897,16 +1336,24 @@ kind := vars["kind"]
 namespace := vars["namespace"]
 name := vars["name"]
 
-ctx := r.Context()
+ctx := s.provenanceContext(r, "restart")
+details := workloadActionDetails{
+Before: s.snapshotWorkload(ctx, clusterID, kind, namespace, name),
+}
 if err := s.k8sClient.RestartResource(ctx, clusterID, kind, namespace, name); err != nil {
+s.logWorkloadAction(requestActor(r), "restart", kind, namespace, name, clusterID, "failed", fmt.Sprintf("Error: %v", err), details)
 respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to restart resource: %v", err))
 return
 }
+s.logWorkloadAction(requestActor(r), "restart", kind, namespace, name, clusterID, "success", fmt.Sprintf("Restarted %s/%s", namespace, name), details)
 
 respondJSON(w, http.StatusOK, map[string]string{"message": "Resource restarted successfully"})
 }
 
-// updateResourceSpec updates a resource's spec
+// updateResourceSpec updates a resource's spec, restricted to the kinds and fields of the
+// spec update allowlist. Secret data needs the secret.update permission. The body is a JSON
+// object to server-side apply, or a JSON patch, JSON merge patch or strategic merge patch
+// as its Content-Type says.
 func (s *Server) updateResourceSpec(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 clusterID := vars["id"]
@@ -914,21 +1361,44 @@ kind := vars["kind"]
 namespace := vars["namespace"]
 name := vars["name"]
 
-var patch map[string]interface{}
-if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
-respondError(w, http.StatusBadRequest, "Invalid request body")
+body, ok := decodeUpdate(w, r)
+if !ok || !s.checkUpdate(w, r, clusterID, kind, namespace, name, body) {
 return
 }
 
-ctx := r.Context()
-if err := s.k8sClient.UpdateResourceSpec(ctx, clusterID, kind, namespace, name, patch); err != nil {
-respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update resource: %v", err))
+ctx := s.provenanceContext(r, "update")
+force := r.URL.Query().Get("force") == "true" && body.PatchType == ""
+var err error
+if body.PatchType == "" {
+err = s.k8sClient.UpdateResourceSpec(ctx, clusterID, kind, namespace, name, body.Fields, force)
+} else {
+err = s.k8sClient.PatchResource(ctx, clusterID, kind, namespace, name, k8s.ResourcePatch{Type: body.PatchType, Data: body.Raw})
+}
+s.logUpdate(r, clusterID, kind, namespace, name, body, force, err)
+if err != nil {
+respondUpdateError(w, err)
 return
 }
 
 respondJSON(w, http.StatusOK, map[string]string{"message": "Resource updated successfully"})
 }
 
+// respondUpdateError responds to a failed resource update, with 409 when it would change
+// fields another field manager owns
+func respondUpdateError(w http.ResponseWriter, err error) {
+var conflict *k8s.UpdateConflictError
+if errors.As(err, &conflict) {
+respondError(w, http.StatusConflict, fmt.Sprintf("Update conflicts with another field manager: %v. Retry with force=true to take over these fields", conflict))
+return
+}
+var invalid *k8s.InvalidPatchError
+if errors.As(err, &invalid) {
+respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Invalid patch: %v", invalid))
+return
+}
+respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update resource: %v", err))
+}
+
 // getPodLogs retrieves logs from a pod
 func (s *Server) getPodLogs(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
@@ -973,14 +1443,30 @@ return
 respondJSON(w, http.StatusOK, map[string]interface{}{"containers": containers})
 }
 
-// deletePod deletes a pod
+// deletePod deletes a pod, or evicts it with ?evict=true so PodDisruptionBudgets apply.
+// Both require the pod.delete permission.
 func (s *Server) deletePod(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 clusterID := vars["id"]
 namespace := vars["namespace"]
 podName := vars["name"]
 
-ctx := r.Context()
+evict := r.URL.Query().Get("evict") == "true"
+action := "delete"
+if evict {
+action = "evict"
+}
+if err := s.requirePermission(r, podDeletePermission); err != nil {
+s.logActivity(requestActor(r), action, "Pod", fmt.Sprintf("%s/%s", namespace, podName), podName, clusterID, s.clusterService.Name(clusterID), "failed", fmt.Sprintf("Denied %s: %v", action, err))
+respondError(w, http.StatusForbidden, fmt.Sprintf("Pod deletion not allowed: %v", err))
+return
+}
+
+ctx := s.provenanceContext(r, "delete")
+if evict {
+s.evictPod(w, r, clusterID, namespace, podName)
+return
+}
 if err := s.k8sClient.DeletePod(ctx, clusterID, namespace, podName); err != nil {
 respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete pod: %v", err))
 return
@@ -992,9 +1478,17 @@ respondJSON(w, http.StatusOK, map[string]string{"message": "Pod deleted successf
 // Auth handlers
 
 func (s *Server) handleAuthStatus(w http.ResponseWriter, r *http.Request) {
-respondJSON(w, http.StatusOK, map[string]interface{}{
-"enabled": s.authEnabled,
-})
+status := map[string]interface{}{
+"enabled":             s.authEnabled,
+"anonymous_read_only": s.authEnabled && s.anonymousReadOnlyEnabled(),
+"mode":                "oauth",
+}
+// Behind an authenticating proxy, sign-in and sign-out go through the proxy
+if s.proxyAuth != nil {
+status["mode"] = "proxy"
+status["logout_url"] = s.proxyAuth.logoutURL
+}
+respondJSON(w, http.StatusOK, status)
 }
 
 func (s *Server) handleAuthLogin(w http.ResponseWriter, r *http.Request) {
@@ -1005,31 +1499,40 @@ func (s *Server) handleAuthLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Store state in cookie for validation
-	http.SetCookie(w, &http.Cookie{
-		Name:     "oauth_state",
-		Value:    state,
-		Path:     "/",
-		MaxAge:   600, // 10 minutes
-		HttpOnly: true,
-		Secure:   true, // Ensure cookie is only sent over HTTPS
-		SameSite: http.SameSiteLaxMode,
-	})
+	if err := s.setCookie(w, stateCookieName, state, 600); err != nil { // 10 minutes
+		respondError(w, http.StatusInternalServerError, "Failed to store state")
+		return
+	}
+
+	// Remember where to send the user after the callback
+	if returnTo := sanitizeReturnTo(r.URL.Query().Get("return_to")); returnTo != "" {
+		if err := s.setCookie(w, returnToCookieName, returnTo, 600); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to store return path")
+			return
+		}
+	}
 
 	authURL := s.oauthProvider.GetAuthURL(state)
 	http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
 }
 
 func (s *Server) handleAuthCallback(w http.ResponseWriter, r *http.Request) {
+// Consent for a stored provider token returns here too, with its own state
+if grantState, err := s.readCookie(r, grantStateCookieName); err == nil && grantState != "" && r.URL.Query().Get("state") == grantState {
+s.completeProviderGrant(w, r)
+return
+}
+
 // Verify state
-stateCookie, err := r.Cookie("oauth_state")
+expectedState, err := s.readCookie(r, stateCookieName)
 if err != nil {
-http.Redirect(w, r, "/?error=invalid_state", http.StatusTemporaryRedirect)
+http.Redirect(w, r, s.appPath("/?error=invalid_state"), http.StatusTemporaryRedirect)
 return
 }
 
 state := r.URL.Query().Get("state")
-if state != stateCookie.Value {
-http.Redirect(w, r, "/?error=state_mismatch", http.StatusTemporaryRedirect)
+if state != expectedState {
+http.Redirect(w, r, s.appPath("/?error=state_mismatch"), http.StatusTemporaryRedirect)
 return
 }
 
@@ -1038,7 +1541,7 @@ code := r.URL.Query().Get("code")
 token, err := s.oauthProvider.Exchange(r.Context(), code)
 if err != nil {
 log.Printf("OAuth token exchange failed: %v", err)
-http.Redirect(w, r, "/?error=token_exchange_failed", http.StatusTemporaryRedirect)
+http.Redirect(w, r, s.appPath("/?error=token_exchange_failed"), http.StatusTemporaryRedirect)
 return
 }
 
@@ -1046,86 +1549,84 @@ return
 userInfo, err := s.oauthProvider.GetUserInfo(r.Context(), token)
 if err != nil {
 log.Printf("Failed to get user info: %v", err)
-http.Redirect(w, r, "/?error=user_info_failed", http.StatusTemporaryRedirect)
+http.Redirect(w, r, s.appPath("/?error=user_info_failed"), http.StatusTemporaryRedirect)
 return
 }
 
 // Check if user is allowed
 if !s.oauthProvider.IsUserAllowed(userInfo) {
 log.Printf("User not allowed: %s", userInfo.Email)
-http.Redirect(w, r, "/?error=unauthorized", http.StatusTemporaryRedirect)
+http.Redirect(w, r, s.appPath("/?error=unauthorized"), http.StatusTemporaryRedirect)
+return
+}
+
+// Create the user on first login, with the roles the role mapping rules give them
+if userInfo.Email != "" {
+account, err := s.rbacManager.GetOrCreateUser(userInfo.Email, userInfo.Name, userInfo.Provider, userInfo.Groups)
+if err != nil {
+log.Printf("Warning: Failed to create user %s: %v", userInfo.Email, err)
+} else if !account.Enabled {
+log.Printf("Disabled user tried to log in: %s", userInfo.Email)
+s.logActivity(requestActor(r), "login", "user", account.ID, userInfo.Email, "", "", "failed", "User is disabled")
+http.Redirect(w, r, s.appPath("/?error=user_disabled"), http.StatusTemporaryRedirect)
 return
 }
+}
 
 // Create session
 sessionToken, err := s.sessionStore.Create(userInfo)
 if err != nil {
 log.Printf("Failed to create session: %v", err)
-http.Redirect(w, r, "/?error=session_failed", http.StatusTemporaryRedirect)
+http.Redirect(w, r, s.appPath("/?error=session_failed"), http.StatusTemporaryRedirect)
 return
 }
 
 // Set session cookie
-http.SetCookie(w, &http.Cookie{
-Name:     "session_token",
-Value:    sessionToken,
-Path:     "/",
-MaxAge:   86400, // 24 hours
-HttpOnly: true,
-Secure:   true, // Ensure cookie is only sent over HTTPS
-SameSite: http.SameSiteLaxMode,
-})
+if err := s.setCookie(w, sessionCookieName, sessionToken, 86400); err != nil { // 24 hours
+log.Printf("Failed to set session cookie: %v", err)
+http.Redirect(w, r, s.appPath("/?error=session_failed"), http.StatusTemporaryRedirect)
+return
+}
 
 // Clear state cookie
-http.SetCookie(w, &http.Cookie{
-		Name:     "oauth_state",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteLaxMode,
-	})
+	s.clearCookie(w, stateCookieName)
 
-	http.Redirect(w, r, "/", http.StatusTemporaryRedirect)
+	returnTo := s.appPath("/")
+	if stored, err := s.readCookie(r, returnToCookieName); err == nil {
+		if sanitized := sanitizeReturnTo(stored); sanitized != "" {
+			returnTo = sanitized
+		}
+		s.clearCookie(w, returnToCookieName)
+	}
+
+	http.Redirect(w, r, returnTo, http.StatusTemporaryRedirect)
 }
 
 func (s *Server) handleAuthLogout(w http.ResponseWriter, r *http.Request) {
-	cookie, err := r.Cookie("session_token")
-	if err == nil {
-		s.sessionStore.Delete(cookie.Value)
-	}
-
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteLaxMode,
-	})
+	if token, err := s.readCookie(r, sessionCookieName); err == nil {
+		s.sessionStore.Delete(token)
+	}
+
+	s.clearCookie(w, sessionCookieName)
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
 }
 
 func (s *Server) handleAuthMe(w http.ResponseWriter, r *http.Request) {
-	cookie, err := r.Cookie("session_token")
-	if err != nil {
+	userInfo, err := s.authenticate(r)
+	if errors.Is(err, ErrNoCredential) {
 		respondError(w, http.StatusUnauthorized, "Not authenticated")
 		return
 	}
-
-	session, exists := s.sessionStore.Get(cookie.Value)
-	if !exists {
-		respondError(w, http.StatusUnauthorized, "Invalid session")
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, session.UserInfo)
+	respondJSON(w, http.StatusOK, userInfo)
 }
 
-// Auth middleware
+// Auth middleware, authenticating requests with the server's authenticator chain
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 if r.Method == "OPTIONS" {
@@ -1133,20 +1634,22 @@ next.ServeHTTP(w, r)
 return
 }
 
-cookie, err := r.Cookie("session_token")
-if err != nil {
+userInfo, err := s.authenticate(r)
+if errors.Is(err, ErrNoCredential) {
+if s.allowAnonymousRead(r) {
+next.ServeHTTP(w, r)
+return
+}
 respondError(w, http.StatusUnauthorized, "Authentication required")
 return
 }
-
-session, exists := s.sessionStore.Get(cookie.Value)
-if !exists {
-respondError(w, http.StatusUnauthorized, "Invalid or expired session")
+if err != nil {
+respondError(w, http.StatusUnauthorized, err.Error())
 return
 }
 
 // Add user info to context
-ctx := context.WithValue(r.Context(), "user", session.UserInfo)
+ctx := context.WithValue(r.Context(), "user", userInfo)
 next.ServeHTTP(w, r.WithContext(ctx))
 })
 }
@@ -1158,6 +1661,7 @@ defer ticker.Stop()
 
 for range ticker.C {
 s.sessionStore.CleanExpired()
+s.deviceLogins.CleanExpired()
 }
 }
 
@@ -1165,40 +1669,19 @@ s.sessionStore.CleanExpired()
 
 // loadAzureSubscriptions loads existing Azure subscriptions from database
 func (s *Server) loadAzureSubscriptions() {
-var subscriptions []models.AzureSubscription
-if err := s.db.Find(&subscriptions).Error; err != nil {
-log.Printf("Warning: Failed to load Azure subscriptions: %v", err)
-return
-}
-
-for _, sub := range subscriptions {
-// Decrypt credentials
-decrypted, err := s.encryptor.Decrypt(sub.Credentials)
-if err != nil {
-log.Printf("Warning: Failed to decrypt credentials for subscription %s: %v", sub.ID, err)
-continue
-}
-
-// Decode credentials
-creds, err := azure.DecodeCredentials(decrypted)
-if err != nil {
-log.Printf("Warning: Failed to decode credentials for subscription %s: %v", sub.ID, err)
-continue
-}
-
-s.azureClient.AddCredentials(sub.ID, creds)
-log.Printf("Loaded Azure subscription: %s", sub.Name)
+if err := s.azureService.LoadSubscriptions(); err != nil {
+log.Printf("Warning: %v", err)
 }
 }
 
 func (s *Server) listAzureSubscriptions(w http.ResponseWriter, r *http.Request) {
-var subscriptions []models.AzureSubscription
-if err := s.db.Find(&subscriptions).Error; err != nil {
+subscriptions, err := s.azureService.ListSubscriptions()
+if err != nil {
 respondError(w, http.StatusInternalServerError, "Failed to list Azure subscriptions")
 return
 }
 
-respondJSON(w, http.StatusOK, subscriptions)
+respondJSON(w, http.StatusOK, newAzureSubscriptionResponses(subscriptions))
 }
 
 func (s *Server) createAzureSubscription(w http.ResponseWriter, r *http.Request) {
@@ -1215,93 +1698,43 @@ respondError(w, http.StatusBadRequest, "Invalid request body")
 return
 }
 
-// Validate required fields
-if req.Name == "" || req.SubscriptionID == "" || req.TenantID == "" || req.ClientID == "" || req.ClientSecret == "" {
-respondError(w, http.StatusBadRequest, "Missing required fields")
-return
-}
-
-// Create Azure credentials
-creds := &azure.Credentials{
+subscription, err := s.azureService.CreateSubscription(r.Context(), service.AzureSubscriptionInput{
+Name:           req.Name,
+SubscriptionID: req.SubscriptionID,
 TenantID:       req.TenantID,
 ClientID:       req.ClientID,
 ClientSecret:   req.ClientSecret,
-SubscriptionID: req.SubscriptionID,
-}
-
-// Test connection
-s.azureClient.AddCredentials(req.SubscriptionID, creds)
-if err := s.azureClient.TestConnection(r.Context(), req.SubscriptionID); err != nil {
-s.azureClient.RemoveCredentials(req.SubscriptionID)
-respondError(w, http.StatusUnauthorized, fmt.Sprintf("Failed to authenticate with Azure: %v", err))
-return
-}
-
-// Encode credentials
-encoded, err := azure.EncodeCredentials(creds)
-if err != nil {
-s.azureClient.RemoveCredentials(req.SubscriptionID)
-respondError(w, http.StatusInternalServerError, "Failed to encode credentials")
-return
-}
-
-// Encrypt credentials
-encrypted, err := s.encryptor.Encrypt(encoded)
+})
 if err != nil {
-s.azureClient.RemoveCredentials(req.SubscriptionID)
-respondError(w, http.StatusInternalServerError, "Failed to encrypt credentials")
-return
-}
-
-// Create database record
-subscription := models.AzureSubscription{
-ID:          req.SubscriptionID,
-Name:        req.Name,
-TenantID:    req.TenantID,
-Credentials: encrypted,
-Status:      "healthy",
-}
-
-if err := s.db.Create(&subscription).Error; err != nil {
-s.azureClient.RemoveCredentials(req.SubscriptionID)
-respondError(w, http.StatusInternalServerError, "Failed to save Azure subscription")
+respondServiceError(w, err, "Azure subscription not found", "Failed to save Azure subscription")
 return
 }
 
-respondJSON(w, http.StatusCreated, subscription)
+respondJSON(w, http.StatusCreated, newAzureSubscriptionResponse(subscription))
 }
 
 func (s *Server) getAzureSubscription(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 id := vars["id"]
 
-var subscription models.AzureSubscription
-if err := s.db.First(&subscription, "id = ?", id).Error; err != nil {
-respondError(w, http.StatusNotFound, "Azure subscription not found")
+subscription, err := s.azureService.GetSubscription(id)
+if err != nil {
+respondServiceError(w, err, "Azure subscription not found", "Failed to query Azure subscription")
 return
 }
 
-respondJSON(w, http.StatusOK, subscription)
+respondJSON(w, http.StatusOK, newAzureSubscriptionResponse(subscription))
 }
 
 func (s *Server) deleteAzureSubscription(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 id := vars["id"]
 
-// Delete from database
-if err := s.db.Delete(&models.AzureSubscription{}, "id = ?", id).Error; err != nil {
+if err := s.azureService.DeleteSubscription(id); err != nil {
 respondError(w, http.StatusInternalServerError, "Failed to delete Azure subscription")
 return
 }
 
-// Remove from Azure client
-s.azureClient.RemoveCredentials(id)
-
-// Delete all associated clusters
-if err := s.db.Where("source = ? AND source_id LIKE ?", "azure-aks", fmt.Sprintf("/subscriptions/%s/%%", id)).Delete(&models.Cluster{}).Error; err != nil {
-log.Printf("Warning: Failed to delete associated clusters: %v", err)
-}
-
 respondJSON(w, http.StatusOK, map[string]string{"message": "Azure subscription deleted successfully"})
 }
 
@@ -1309,8 +1742,8 @@ func (s *Server) testAzureConnection(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 id := vars["id"]
 
-if err := s.azureClient.TestConnection(r.Context(), id); err != nil {
-respondError(w, http.StatusUnauthorized, fmt.Sprintf("Connection test failed: %v", err))
+if err := s.azureService.TestConnection(r.Context(), id); err != nil {
+respondServiceError(w, err, "Azure subscription not found", "Connection test failed")
 return
 }
 
@@ -1321,7 +1754,7 @@ func (s *Server) discoverAKSClusters(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 subscriptionID := vars["id"]
 
-clusters, err := s.azureClient.DiscoverClusters(r.Context(), subscriptionID)
+clusters, err := s.azureService.DiscoverClusters(r.Context(), subscriptionID)
 if err != nil {
 respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to discover AKS clusters: %v", err))
 return
@@ -1337,95 +1770,19 @@ func (s *Server) syncAKSClusters(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 subscriptionID := vars["id"]
 
-// Discover clusters
-aksClusters, err := s.azureClient.DiscoverClusters(r.Context(), subscriptionID)
+result, err := s.azureService.SyncClusters(r.Context(), subscriptionID)
 if err != nil {
 respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to discover AKS clusters: %v", err))
 return
 }
 
-var syncedClusters []models.Cluster
-var errors []string
-
-for _, aksCluster := range aksClusters {
-// Generate kubeconfig with Azure AD auth
-kubeconfig, err := s.azureClient.GenerateKubeconfig(r.Context(), aksCluster)
-if err != nil {
-errors = append(errors, fmt.Sprintf("Failed to generate kubeconfig for %s: %v", aksCluster.Name, err))
-continue
-}
-
-// Encrypt kubeconfig
-encryptedKubeconfig, err := s.encryptor.Encrypt(kubeconfig)
-if err != nil {
-errors = append(errors, fmt.Sprintf("Failed to encrypt kubeconfig for %s: %v", aksCluster.Name, err))
-continue
-}
-
-// Create or update cluster record
-clusterID := fmt.Sprintf("aks-%s", aksCluster.Name)
-cluster := models.Cluster{
-ID:          clusterID,
-Name:        aksCluster.Name,
-Description: fmt.Sprintf("AKS cluster in %s (%s nodes, k8s %s)", aksCluster.Location, fmt.Sprint(aksCluster.NodeCount), aksCluster.KubernetesVersion),
-KubeConfig:  encryptedKubeconfig,
-Status:      "unknown",
-Source:      "azure-aks",
-SourceID:    aksCluster.ID,
-}
-
-// Check if cluster already exists
-var existing models.Cluster
-if err := s.db.First(&existing, "id = ?", clusterID).Error; err == nil {
-// Update existing cluster
-cluster.CreatedAt = existing.CreatedAt
-if err := s.db.Save(&cluster).Error; err != nil {
-errors = append(errors, fmt.Sprintf("Failed to update cluster %s: %v", aksCluster.Name, err))
-continue
-}
-} else {
-// Create new cluster
-if err := s.db.Create(&cluster).Error; err != nil {
-errors = append(errors, fmt.Sprintf("Failed to create cluster %s: %v", aksCluster.Name, err))
-continue
-}
-}
-
-// Add to k8s client
-if err := s.k8sClient.AddCluster(clusterID, kubeconfig); err != nil {
-errors = append(errors, fmt.Sprintf("Failed to add cluster %s to k8s client: %v", aksCluster.Name, err))
-continue
-}
-
-// Check health
-status, err := s.k8sClient.CheckClusterHealth(clusterID)
-if err != nil {
-log.Printf("Warning: Failed to check health for cluster %s: %v", aksCluster.Name, err)
-status = "unhealthy"
-}
-cluster.Status = status
-s.db.Save(&cluster)
-
-syncedClusters = append(syncedClusters, cluster)
-}
-
-// Update subscription last synced time and cluster count
-if err := s.db.Model(&models.AzureSubscription{}).
-Where("id = ?", subscriptionID).
-Updates(map[string]interface{}{
-"last_synced_at": time.Now(),
-"cluster_count":  len(syncedClusters),
-}).Error; err != nil {
-log.Printf("Warning: Failed to update subscription sync time: %v", err)
-}
-
 response := map[string]interface{}{
-"synced":   len(syncedClusters),
-"clusters": syncedClusters,
+"synced":   len(result.Clusters),
+"clusters": newClusterResponses(result.Clusters),
 }
 
-if len(errors) > 0 {
-response["errors"] = errors
+if len(result.Errors) > 0 {
+response["errors"] = result.Errors
 }
 
 respondJSON(w, http.StatusOK, response)
@@ -1436,25 +1793,49 @@ func (s *Server) toggleFavorite(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 clusterID := vars["id"]
 
-var cluster models.Cluster
-if err := s.db.First(&cluster, "id = ?", clusterID).Error; err != nil {
-respondError(w, http.StatusNotFound, "Cluster not found")
+cluster, err := s.clusterService.ToggleFavorite(clusterID)
+if err != nil {
+respondServiceError(w, err, "Cluster not found", "Failed to update cluster")
 return
 }
 
-// Toggle favorite status
-cluster.IsFavorite = !cluster.IsFavorite
+// Log activity
+s.logActivity(requestActor(r), "toggle_favorite", "cluster", clusterID, cluster.Name, clusterID, cluster.Name, "success", "")
 
-if err := s.db.Save(&cluster).Error; err != nil {
-log.Printf("Failed to toggle favorite: %v", err)
-respondError(w, http.StatusInternalServerError, "Failed to update cluster")
-return
+respondJSON(w, http.StatusOK, newClusterResponse(cluster))
 }
 
-// Log activity
-s.logActivity("toggle_favorite", "cluster", clusterID, cluster.Name, clusterID, cluster.Name, "success", "")
+// archiveCluster stops syncing a cluster and hides it from default lists.
+// Its credentials, resources and history are kept.
+func (s *Server) archiveCluster(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
 
-respondJSON(w, http.StatusOK, cluster)
+	cluster, err := s.clusterService.Archive(clusterID)
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to archive cluster")
+		return
+	}
+
+	s.logActivity(requestActor(r), "archive", "cluster", clusterID, cluster.Name, clusterID, cluster.Name, "success", "Cluster archived")
+
+	respondJSON(w, http.StatusOK, newClusterResponse(cluster))
+}
+
+// unarchiveCluster reconnects an archived cluster and resumes syncing it
+func (s *Server) unarchiveCluster(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+
+	cluster, err := s.clusterService.Unarchive(clusterID)
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to unarchive cluster")
+		return
+	}
+
+	s.logActivity(requestActor(r), "unarchive", "cluster", clusterID, cluster.Name, clusterID, cluster.Name, "success", "Cluster restored")
+
+	respondJSON(w, http.StatusOK, newClusterResponse(cluster))
 }
 
 // listActivities returns recent activities
@@ -1469,14 +1850,8 @@ limit = parsedLimit
 
 clusterID := r.URL.Query().Get("cluster_id")
 
-var activities []models.Activity
-query := s.db.Order("created_at DESC").Limit(limit)
-
-if clusterID != "" {
-query = query.Where("cluster_id = ?", clusterID)
-}
-
-if err := query.Find(&activities).Error; err != nil {
+activities, err := s.activities.List(s.clusterScope(r), clusterID, limit)
+if err != nil {
 log.Printf("Failed to list activities: %v", err)
 respondError(w, http.StatusInternalServerError, "Failed to list activities")
 return
@@ -1490,9 +1865,9 @@ func (s *Server) getActivity(w http.ResponseWriter, r *http.Request) {
 vars := mux.Vars(r)
 id := vars["id"]
 
-var activity models.Activity
-if err := s.db.First(&activity, "id = ?", id).Error; err != nil {
-respondError(w, http.StatusNotFound, "Activity not found")
+activity, err := s.activities.Get(s.clusterScope(r), id)
+if err != nil {
+respondServiceError(w, err, "Activity not found", "Failed to query activity")
 return
 }
 
@@ -1508,15 +1883,15 @@ if format == "" {
 format = "json"
 }
 
-var cluster models.Cluster
-if err := s.db.First(&cluster, "id = ?", clusterID).Error; err != nil {
-respondError(w, http.StatusNotFound, "Cluster not found")
+cluster, err := s.clusterService.Get(clusterID)
+if err != nil {
+respondServiceError(w, err, "Cluster not found", "Failed to query cluster")
 return
 }
 
 // Get resources for this cluster
-var resources []models.FluxResource
-if err := s.db.Where("cluster_id = ?", clusterID).Find(&resources).Error; err != nil {
+resources, err := s.resourceService.ListByCluster(clusterID)
+if err != nil {
 log.Printf("Failed to get resources: %v", err)
 respondError(w, http.StatusInternalServerError, "Failed to get resources")
 return
@@ -1550,7 +1925,7 @@ json.NewEncoder(w).Encode(exportData)
 }
 
 // Log activity
-s.logActivity("export", "cluster", clusterID, cluster.Name, clusterID, cluster.Name, "success", fmt.Sprintf("Exported as %s", format))
+s.logActivity(requestActor(r), "export", "cluster", clusterID, cluster.Name, clusterID, cluster.Name, "success", fmt.Sprintf("Exported as %s", format))
 }
 
 // exportResources exports all resources across all clusters
@@ -1563,20 +1938,21 @@ format = "json"
 status := r.URL.Query().Get("status")
 kind := r.URL.Query().Get("kind")
 
-query := s.db.Model(&models.FluxResource{})
-if status != "" {
-query = query.Where("status = ?", status)
-}
-if kind != "" {
-query = query.Where("kind = ?", kind)
-}
-
-var resources []models.FluxResource
-if err := query.Find(&resources).Error; err != nil {
+resources, err := s.resourceService.List(s.clusterScope(r), kind)
+if err != nil {
 log.Printf("Failed to get resources: %v", err)
 respondError(w, http.StatusInternalServerError, "Failed to get resources")
 return
 }
+if status != "" {
+matching := resources[:0]
+for _, res := range resources {
+if res.Status == status {
+matching = append(matching, res)
+}
+}
+resources = matching
+}
 
 if format == "csv" {
 w.Header().Set("Content-Type", "text/csv")
@@ -1610,26 +1986,48 @@ json.NewEncoder(w).Encode(exportData)
 }
 
 // Log activity
-s.logActivity("export", "resources", "all", fmt.Sprintf("%d resources", len(resources)), "", "", "success", fmt.Sprintf("Exported as %s", format))
+s.logActivity(requestActor(r), "export", "resources", "all", fmt.Sprintf("%d resources", len(resources)), "", "", "success", fmt.Sprintf("Exported as %s", format))
 }
 
-// logActivity logs an action to the activity table
-func (s *Server) logActivity(action, resourceType, resourceID, resourceName, clusterID, clusterName, status, message string) {
-activity := models.Activity{
-Action:       action,
-ResourceType: resourceType,
-ResourceID:   resourceID,
-ResourceName: resourceName,
-ClusterID:    clusterID,
-ClusterName:  clusterName,
-Status:       status,
-Message:      message,
-UserID:       "system", // TODO: Get from auth context
+// provenanceContext returns the request context annotated with provenance for k8s mutations,
+// and with the requesting user for clusters that impersonate users
+func (s *Server) provenanceContext(r *http.Request, action string) context.Context {
+	actor := "system"
+	if userInfo, ok := r.Context().Value("user").(*auth.UserInfo); ok && userInfo != nil {
+		actor = userInfo.Email
+		if actor == "" {
+			actor = userInfo.Username
+		}
+	}
+
+	ctx := k8s.WithProvenance(r.Context(), k8s.Provenance{
+		Action:    action,
+		Actor:     actor,
+		RequestID: requestIDFromContext(r.Context()),
+	})
+	return s.impersonationContext(ctx, r)
 }
 
-if err := s.db.Create(&activity).Error; err != nil {
-log.Printf("Warning: Failed to log activity: %v", err)
+// requirePermission returns why the requesting user does not hold an RBAC permission, or
+// nil. While authentication is disabled the server runs in open mode and every request holds
+// every permission; otherwise anonymous requests hold none.
+func (s *Server) requirePermission(r *http.Request, permID string) error {
+	if !s.authEnabled {
+		return nil
+	}
+	userInfo, ok := r.Context().Value("user").(*auth.UserInfo)
+	if !ok || userInfo == nil || userInfo.Email == "" {
+		return fmt.Errorf("an authenticated user with the %s permission is required", permID)
+	}
+	if !s.rbacManager.UserHasPermission(userInfo.Email, permID) {
+		return fmt.Errorf("the %s permission is required", permID)
+	}
+	return nil
 }
+
+// logActivity logs an action to the activity table
+func (s *Server) logActivity(actor, action, resourceType, resourceID, resourceName, clusterID, clusterName, status, message string) {
+s.logActivityDetails(actor, action, resourceType, resourceID, resourceName, clusterID, clusterName, status, message, nil)
 }
 
 // cleanupAuditLogs runs periodically to clean up old audit logs based on retention setting
@@ -1668,6 +2066,34 @@ func (s *Server) performAuditLogCleanup() {
 	if result.RowsAffected > 0 {
 		log.Printf("Cleaned up %d audit log entries older than %d days", result.RowsAffected, retentionDays)
 	}
+
+	// Prune status history (default 30 days, enough for the longest trend range)
+	historyDays := 30
+	if err := s.db.Where("setting_key = ?", "status_history_retention_days").First(&setting).Error; err == nil && setting.Value != "" {
+		if days, err := strconv.Atoi(setting.Value); err == nil && days > 0 {
+			historyDays = days
+		}
+	}
+	if pruned, err := history.Prune(s.db, time.Now().AddDate(0, 0, -historyDays)); err != nil {
+		log.Printf("Error cleaning up status history: %v", err)
+	} else if pruned > 0 {
+		log.Printf("Cleaned up %d status history entries older than %d days", pruned, historyDays)
+	}
+
+	// Prune recorded events (default 7 days); recent ones stay in memory regardless
+	if store := s.webhooks.Store(); store != nil {
+		eventDays := 7
+		if err := s.db.Where("setting_key = ?", "event_retention_days").First(&setting).Error; err == nil && setting.Value != "" {
+			if days, err := strconv.Atoi(setting.Value); err == nil && days > 0 {
+				eventDays = days
+			}
+		}
+		if pruned, err := store.Prune(time.Now().AddDate(0, 0, -eventDays)); err != nil {
+			log.Printf("Error cleaning up events: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Cleaned up %d events older than %d days", pruned, eventDays)
+		}
+	}
 }
 
 // cleanupAuditLogsNow manually triggers audit log cleanup
@@ -1693,7 +2119,7 @@ func (s *Server) listOAuthProviders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, providers)
+	respondJSON(w, http.StatusOK, newOAuthProviderResponses(providers))
 }
 
 func (s *Server) createOAuthProvider(w http.ResponseWriter, r *http.Request) {
@@ -1762,9 +2188,7 @@ func (s *Server) createOAuthProvider(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Clear client secret from response
-	provider.ClientSecret = ""
-	respondJSON(w, http.StatusCreated, provider)
+	respondJSON(w, http.StatusCreated, newOAuthProviderResponse(&provider))
 }
 
 func (s *Server) getOAuthProvider(w http.ResponseWriter, r *http.Request) {
@@ -1777,9 +2201,7 @@ func (s *Server) getOAuthProvider(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Clear client secret from response
-	provider.ClientSecret = ""
-	respondJSON(w, http.StatusOK, provider)
+	respondJSON(w, http.StatusOK, newOAuthProviderResponse(&provider))
 }
 
 func (s *Server) updateOAuthProvider(w http.ResponseWriter, r *http.Request) {
@@ -1795,6 +2217,7 @@ func (s *Server) updateOAuthProvider(w http.ResponseWriter, r *http.Request) {
 		Scopes       *string `json:"scopes"`
 		AllowedUsers *string `json:"allowed_users"`
 		Enabled      *bool   `json:"enabled"`
+		Version      *int    `json:"version"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1802,6 +2225,16 @@ func (s *Server) updateOAuthProvider(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var provider models.OAuthProvider
+	if err := s.db.Select("id", "version").First(&provider, "id = ?", id).Error; err != nil {
+		respondError(w, http.StatusNotFound, "OAuth provider not found")
+		return
+	}
+	version := provider.Version
+	if req.Version != nil {
+		version = *req.Version
+	}
+
 	// Build updates map
 	updates := make(map[string]interface{})
 	
@@ -1836,8 +2269,8 @@ func (s *Server) updateOAuthProvider(w http.ResponseWriter, r *http.Request) {
 		updates["enabled"] = *req.Enabled
 	}
 
-	if err := s.db.Model(&models.OAuthProvider{}).Where("id = ?", id).Updates(updates).Error; err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to update OAuth provider")
+	if err := repository.UpdateVersioned(s.db, &models.OAuthProvider{}, id, version, updates); err != nil {
+		respondServiceError(w, err, "OAuth provider not found", "Failed to update OAuth provider")
 		return
 	}
 
@@ -1967,6 +2400,17 @@ func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
 
 	var user models.User
 	s.db.Preload("Roles").Where("id = ?", id).First(&user)
+
+	// Disabling here revokes access as POST /rbac/users/{id}/disable does
+	if req.Enabled != nil && !*req.Enabled && user.Email != "" {
+		sessions, tokens, err := s.revokeUserCredentials(user.Email)
+		if err != nil {
+			log.Printf("Warning: Failed to revoke credentials of %s: %v", user.Email, err)
+		} else {
+			s.logActivity(requestActor(r), "disable", "user", user.ID, user.Email, "", "", "success",
+				fmt.Sprintf("Disabled %s, revoking %d sessions and %d API tokens", user.Email, sessions, tokens))
+		}
+	}
 	respondJSON(w, http.StatusOK, user)
 }
 
@@ -2148,6 +2592,8 @@ func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete role: %v", err))
 		return
 	}
+	// Mapping rules giving the role would match without effect
+	s.db.Where("role_id = ?", id).Delete(&models.RoleMappingRule{})
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Role deleted"})
 }
@@ -2248,6 +2694,24 @@ func (s *Server) getAggregatedLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Under shared visibility only clusters shared with the caller are read, all of them
+	// when none are asked for
+	visible, restricted, err := s.visibleClusterIDs(r)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get aggregated logs: %v", err))
+		return
+	}
+	if restricted {
+		if len(clusterIDs) > 0 {
+			visible = slices.DeleteFunc(visible, func(id string) bool { return !slices.Contains(clusterIDs, id) })
+		}
+		clusterIDs = visible
+		if len(clusterIDs) == 0 {
+			respondJSON(w, http.StatusOK, map[string]interface{}{"logs": []interface{}{}, "count": 0})
+			return
+		}
+	}
+
 	filters := map[string]interface{}{
 		"cluster_ids":    clusterIDs,
 		"namespace":      namespace,
@@ -2267,3 +2731,13 @@ func (s *Server) getAggregatedLogs(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+
+// newFrontendHandler loads the SPA assets, serving 404s when none are available
+func newFrontendHandler(basePath string) http.Handler {
+	handler, err := webui.New(webui.FS(), basePath)
+	if err != nil {
+		log.Printf("Warning: Frontend assets not available: %v", err)
+		return http.NotFoundHandler()
+	}
+	return handler
+}
@@ -0,0 +1,380 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/bulk"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	k8sfake "github.com/Forcebyte/flux-orchestrator/backend/internal/k8s/fake"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/service/fake"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/status"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/webhooks"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+// testServer is a Server running against in-memory fakes
+type testServer struct {
+	*Server
+	clusters   *fake.ClusterService
+	resources  *fake.ResourceService
+	azure      *fake.AzureService
+	registries *fake.RegistryService
+	k8s        *k8sfake.Client
+}
+
+// newTestServer creates a server without authentication whose services are the given
+// fakes, nil ones replaced by empty fakes. The database runs dry, so activity logging and
+// RBAC lookups succeed without storing or finding anything.
+func newTestServer(t *testing.T, clusters *fake.ClusterService, resources *fake.ResourceService, azure *fake.AzureService) *testServer {
+	t.Helper()
+	for _, env := range []string{"AUTH_PROXY_TRUSTED_CIDRS", "BASE_PATH", "CLUSTER_VISIBILITY"} {
+		t.Setenv(env, "")
+	}
+
+	gormDB, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true, Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open dry-run database: %v", err)
+	}
+	if clusters == nil {
+		clusters = fake.NewClusterService()
+	}
+	if resources == nil {
+		resources = fake.NewResourceService()
+	}
+	if azure == nil {
+		azure = fake.NewAzureService()
+	}
+	ts := &testServer{
+		clusters:   clusters,
+		resources:  resources,
+		azure:      azure,
+		registries: fake.NewRegistryService(),
+		k8s:        k8sfake.NewClient(1),
+	}
+	ts.Server = NewServerWithServices(&database.DB{DB: gormDB}, ts.k8s, nil, nil, webhooks.NewNotifier(nil, zap.NewNop()), Services{
+		Clusters:   ts.clusters,
+		Resources:  ts.resources,
+		Azure:      ts.azure,
+		Registries: ts.registries,
+	})
+	return ts
+}
+
+// do sends a request through the router, encoding body as JSON when set
+func (ts *testServer) do(t *testing.T, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("encode request body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	ts.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode decodes a JSON response, failing the test unless it has the wanted status
+func decode(t *testing.T, rec *httptest.ResponseRecorder, status int, v interface{}) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, status, rec.Body.String())
+	}
+	if v == nil {
+		return
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+}
+
+func TestClusterHandlers(t *testing.T) {
+	clusters := fake.NewClusterService(
+		models.Cluster{ID: "prod", Name: "prod", Status: "healthy", KubeConfig: "encrypted-kubeconfig", Version: 1},
+		models.Cluster{ID: "staging", Name: "staging", Status: "healthy", KubeConfig: "encrypted-kubeconfig", Version: 1},
+	)
+	ts := newTestServer(t, clusters, nil, nil)
+
+	var list []ClusterResponse
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/clusters", nil), http.StatusOK, &list)
+	if len(list) != 2 {
+		t.Fatalf("listed %d clusters, want 2", len(list))
+	}
+
+	rec := ts.do(t, http.MethodGet, "/api/v1/clusters/prod", nil)
+	var cluster ClusterResponse
+	decode(t, rec, http.StatusOK, &cluster)
+	if cluster.Name != "prod" || strings.Contains(rec.Body.String(), "encrypted-kubeconfig") {
+		t.Errorf("get cluster = %s", rec.Body.String())
+	}
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/clusters/missing", nil), http.StatusNotFound, nil)
+
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters", map[string]string{"name": "dev"}), http.StatusBadRequest, nil)
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters", map[string]string{"name": "dev", "kubeconfig": "apiVersion: v1"}), http.StatusCreated, &cluster)
+	if cluster.Name != "dev" || cluster.ID == "" {
+		t.Fatalf("created cluster = %+v", cluster)
+	}
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/clusters", nil), http.StatusOK, &list)
+	if len(list) != 3 {
+		t.Errorf("listed %d clusters after create, want 3", len(list))
+	}
+
+	decode(t, ts.do(t, http.MethodPut, "/api/v1/clusters/prod", map[string]interface{}{"name": "production", "version": 7}), http.StatusConflict, nil)
+	decode(t, ts.do(t, http.MethodPut, "/api/v1/clusters/prod", map[string]interface{}{"name": "production", "version": 1}), http.StatusOK, nil)
+	if name := clusters.Name("prod"); name != "production" {
+		t.Errorf("cluster name after update = %q, want production", name)
+	}
+	decode(t, ts.do(t, http.MethodPut, "/api/v1/clusters/missing", map[string]string{"name": "x"}), http.StatusNotFound, nil)
+
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/staging", nil), http.StatusOK, nil)
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/clusters/staging", nil), http.StatusNotFound, nil)
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/staging", nil), http.StatusNotFound, nil)
+}
+
+func TestResourceHandlers(t *testing.T) {
+	resources := fake.NewResourceService(
+		models.FluxResource{ID: "r1", ClusterID: "prod", Kind: "Kustomization", Namespace: "flux-system", Name: "apps", Status: "Ready"},
+		models.FluxResource{ID: "r2", ClusterID: "prod", Kind: "HelmRelease", Namespace: "web", Name: "frontend", Status: "NotReady"},
+	)
+	ts := newTestServer(t, fake.NewClusterService(models.Cluster{ID: "prod", Name: "prod"}), resources, nil)
+
+	var list []models.FluxResource
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/resources", nil), http.StatusOK, &list)
+	if len(list) != 2 {
+		t.Errorf("listed %d resources, want 2", len(list))
+	}
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/resources?kind=HelmRelease", nil), http.StatusOK, &list)
+	if len(list) != 1 || list[0].Name != "frontend" {
+		t.Errorf("HelmReleases = %+v", list)
+	}
+
+	var res models.FluxResource
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/resources/r1", nil), http.StatusOK, &res)
+	if res.Name != "apps" {
+		t.Errorf("get resource = %+v", res)
+	}
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/resources/missing", nil), http.StatusNotFound, nil)
+
+	reconcile := models.ReconcileRequest{ClusterID: "prod", Kind: "Kustomization", Namespace: "flux-system", Name: "apps"}
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/resources/reconcile", reconcile), http.StatusOK, nil)
+	reconcile.WithSource = true
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/resources/reconcile", reconcile), http.StatusOK, nil)
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters/prod/flux/HelmRelease/web/frontend/suspend", nil), http.StatusOK, nil)
+
+	// Forcing needs resource.update, which open mode grants and anonymous callers never hold
+	forced := models.ReconcileRequest{ClusterID: "prod", Kind: "HelmRelease", Namespace: "web", Name: "frontend", Force: true}
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/resources/reconcile", forced), http.StatusOK, nil)
+	ts.authEnabled = true
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/resources/reconcile", forced), http.StatusForbidden, nil)
+	ts.authEnabled = false
+
+	want := []fake.Call{
+		{Method: "Reconcile", ClusterID: "prod", Kind: "Kustomization", Namespace: "flux-system", Name: "apps"},
+		{Method: "ReconcileWithSource", ClusterID: "prod", Kind: "Kustomization", Namespace: "flux-system", Name: "apps"},
+		{Method: "Suspend", ClusterID: "prod", Kind: "HelmRelease", Namespace: "web", Name: "frontend"},
+		{Method: "Reconcile", ClusterID: "prod", Kind: "HelmRelease", Namespace: "web", Name: "frontend"},
+	}
+	if len(resources.Calls) != len(want) {
+		t.Fatalf("calls = %+v, want %+v", resources.Calls, want)
+	}
+	for i := range want {
+		if resources.Calls[i] != want[i] {
+			t.Errorf("call %d = %+v, want %+v", i, resources.Calls[i], want[i])
+		}
+	}
+
+	resources.ActionErr = errors.New("cluster unreachable")
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters/prod/flux/HelmRelease/web/frontend/resume", nil), http.StatusInternalServerError, nil)
+}
+
+func TestAzureSubscriptionHandlers(t *testing.T) {
+	azure := fake.NewAzureService()
+	ts := newTestServer(t, nil, nil, azure)
+
+	input := map[string]string{
+		"name":            "Production",
+		"subscription_id": "sub-1",
+		"tenant_id":       "tenant",
+		"client_id":       "app",
+		"client_secret":   "azure-client-secret",
+	}
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/azure/subscriptions", map[string]string{"name": "Production"}), http.StatusBadRequest, nil)
+
+	azure.ConnectionErr = errors.New("invalid client secret")
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/azure/subscriptions", input), http.StatusUnauthorized, nil)
+	azure.ConnectionErr = nil
+
+	rec := ts.do(t, http.MethodPost, "/api/v1/azure/subscriptions", input)
+	var subscription AzureSubscriptionResponse
+	decode(t, rec, http.StatusCreated, &subscription)
+	if subscription.ID != "sub-1" || strings.Contains(rec.Body.String(), "azure-client-secret") {
+		t.Errorf("created subscription = %s", rec.Body.String())
+	}
+
+	var list []AzureSubscriptionResponse
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/azure/subscriptions", nil), http.StatusOK, &list)
+	if len(list) != 1 {
+		t.Errorf("listed %d subscriptions, want 1", len(list))
+	}
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/azure/subscriptions/sub-1", nil), http.StatusOK, &subscription)
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/azure/subscriptions/missing", nil), http.StatusNotFound, nil)
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/azure/subscriptions/sub-1/test", nil), http.StatusOK, nil)
+
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/azure/subscriptions/sub-1", nil), http.StatusOK, nil)
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/azure/subscriptions", nil), http.StatusOK, &list)
+	if len(list) != 0 {
+		t.Errorf("listed %d subscriptions after delete, want 0", len(list))
+	}
+}
+
+func TestUpdateFluxResourcePassesEditPolicy(t *testing.T) {
+	resources := fake.NewResourceService()
+	ts := newTestServer(t, fake.NewClusterService(models.Cluster{ID: "prod", Name: "prod"}), resources, nil)
+
+	patch := func(kind, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/clusters/prod/flux/"+kind+"/web/frontend", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		rec := httptest.NewRecorder()
+		ts.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Kinds and fields outside the spec update allowlist, and scales the guardrails refuse
+	decode(t, patch("Pod", `{"spec":{"activeDeadlineSeconds":1}}`), http.StatusForbidden, nil)
+	decode(t, patch("HelmRelease", `{"metadata":{"labels":{"team":"web"}}}`), http.StatusForbidden, nil)
+	decode(t, patch("HelmRelease", `{"spec":{"replicas":-1}}`), http.StatusUnprocessableEntity, nil)
+	if len(resources.Calls) != 0 {
+		t.Fatalf("blocked patches reached the cluster: %+v", resources.Calls)
+	}
+
+	decode(t, patch("HelmRelease", `{"spec":{"suspend":true}}`), http.StatusOK, nil)
+	want := fake.Call{Method: "Patch", ClusterID: "prod", Kind: "HelmRelease", Namespace: "web", Name: "frontend"}
+	if len(resources.Calls) != 1 || resources.Calls[0] != want {
+		t.Errorf("calls = %+v, want %+v", resources.Calls, want)
+	}
+}
+
+func TestDeleteNotificationResourceNeedsPermission(t *testing.T) {
+	resources := fake.NewResourceService(
+		models.FluxResource{ID: "a1", ClusterID: "prod", Kind: "Alert", Namespace: "flux-system", Name: "on-call"},
+	)
+	ts := newTestServer(t, fake.NewClusterService(models.Cluster{ID: "prod", Name: "prod"}), resources, nil)
+
+	ts.authEnabled = true
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/prod/flux/Alert/flux-system/on-call", nil), http.StatusForbidden, nil)
+	if len(resources.Calls) != 0 {
+		t.Fatalf("denied delete reached the cluster: %+v", resources.Calls)
+	}
+
+	ts.authEnabled = false
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/prod/flux/Alert/flux-system/on-call", nil), http.StatusOK, nil)
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/resources/a1", nil), http.StatusNotFound, nil)
+}
+
+func TestDeletePodsNeedsPermissionUnlessDryRun(t *testing.T) {
+	ts := newTestServer(t, fake.NewClusterService(models.Cluster{ID: "prod", Name: "prod"}), nil, nil)
+	ts.k8s.SetCluster("prod", k8sfake.Cluster{})
+	ts.authEnabled = true
+
+	var result k8s.PodDeletionResult
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters/prod/namespaces/apps/pods/delete",
+		map[string]interface{}{"pods": []string{"web-1"}, "dry_run": true}), http.StatusOK, &result)
+	if len(result.Matched) != 1 || len(result.Deleted) != 0 {
+		t.Fatalf("dry run result = %+v", result)
+	}
+
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters/prod/namespaces/apps/pods/delete",
+		map[string]interface{}{"pods": []string{"web-1"}}), http.StatusForbidden, nil)
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters/prod/namespaces/apps/pods/delete",
+		map[string]interface{}{"pods": []string{"web-1"}, "evict": true}), http.StatusForbidden, nil)
+	if got := ts.k8s.CallCount("DeletePods"); got != 1 {
+		t.Fatalf("DeletePods called %d times, want only the dry run", got)
+	}
+
+	ts.authEnabled = false
+	decode(t, ts.do(t, http.MethodPost, "/api/v1/clusters/prod/namespaces/apps/pods/delete",
+		map[string]interface{}{"pods": []string{"web-1"}}), http.StatusOK, &result)
+	if len(result.Deleted) != 1 {
+		t.Fatalf("delete result = %+v", result)
+	}
+}
+
+func TestDeletePodNeedsPermission(t *testing.T) {
+	ts := newTestServer(t, fake.NewClusterService(models.Cluster{ID: "prod", Name: "prod"}), nil, nil)
+	ts.k8s.SetCluster("prod", k8sfake.Cluster{})
+
+	ts.authEnabled = true
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/prod/pods/apps/web-1", nil), http.StatusForbidden, nil)
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/prod/pods/apps/web-1?evict=true", nil), http.StatusForbidden, nil)
+	if len(ts.k8s.Calls) != 0 {
+		t.Fatalf("denied deletions reached the cluster: %+v", ts.k8s.Calls)
+	}
+
+	ts.authEnabled = false
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/prod/pods/apps/web-1", nil), http.StatusOK, nil)
+	decode(t, ts.do(t, http.MethodDelete, "/api/v1/clusters/prod/pods/apps/web-1?evict=true", nil), http.StatusOK, nil)
+	if ts.k8s.CallCount("DeletePod") != 1 || ts.k8s.CallCount("EvictPod") != 1 {
+		t.Fatalf("calls = %+v", ts.k8s.Calls)
+	}
+}
+
+func TestSystemStatusCountsVisibleClusters(t *testing.T) {
+	ts := newTestServer(t, fake.NewClusterService(
+		models.Cluster{ID: "prod", Name: "prod", Status: "healthy"},
+		models.Cluster{ID: "staging", Name: "staging", Status: "unhealthy"},
+	), nil, nil)
+
+	clusterTotal := func() interface{} {
+		t.Helper()
+		rec := ts.do(t, http.MethodGet, "/api/v1/system/status", nil)
+		var report status.Report
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+		}
+		for _, c := range report.Components {
+			if c.Name == "clusters" {
+				return c.Details["total"]
+			}
+		}
+		t.Fatalf("no clusters component: %s", rec.Body.String())
+		return nil
+	}
+
+	if total := clusterTotal(); total != float64(2) {
+		t.Fatalf("total = %v, want 2", total)
+	}
+
+	// Anonymous callers under shared visibility can read no cluster
+	ts.authEnabled = true
+	ts.sharedVisibility = true
+	if total := clusterTotal(); total != float64(0) {
+		t.Fatalf("total = %v, want 0 for a caller without shares", total)
+	}
+}
+
+func TestGetBulkJobHidesJobsOfUnsharedClusters(t *testing.T) {
+	ts := newTestServer(t, fake.NewClusterService(models.Cluster{ID: "prod", Name: "prod"}), nil, nil)
+	job := ts.bulkJobs.Start(context.Background(), "prod", bulk.ActionSuspend, k8s.SuspendScope{}, "ops@example.com", nil, nil)
+
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/bulk-jobs/"+job.ID, nil), http.StatusOK, nil)
+
+	ts.authEnabled = true
+	ts.sharedVisibility = true
+	decode(t, ts.do(t, http.MethodGet, "/api/v1/bulk-jobs/"+job.ID, nil), http.StatusNotFound, nil)
+}
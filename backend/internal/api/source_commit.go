@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
+	"github.com/gorilla/mux"
+)
+
+// SourceCommit is the commit a GitRepository's artifact was built from, as GitHub reports it
+type SourceCommit struct {
+	Repository  string    `json:"repository"` // owner/name
+	SHA         string    `json:"sha"`
+	Message     string    `json:"message"`
+	AuthorName  string    `json:"author_name"`
+	AuthorEmail string    `json:"author_email"`
+	Date        time.Time `json:"date"`
+	URL         string    `json:"url"`
+	// AsUser is set when the commit was read with the caller's granted token rather than
+	// anonymously
+	AsUser bool `json:"as_user"`
+}
+
+// gitHubRepoPattern matches the owner and name in https, ssh and scp-like GitHub URLs
+var gitHubRepoPattern = regexp.MustCompile(`^(?:https?://|ssh://)?(?:[^@/]+@)?github\.com[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// revisionSHAPattern matches the commit in artifact revisions such as main@sha1:<sha>,
+// sha1:<sha> and the older main/<sha>
+var revisionSHAPattern = regexp.MustCompile(`(?:sha1:|sha256:|/|^)([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// getSourceCommit returns the commit a GitRepository hosted on GitHub last fetched. It is
+// read as the caller when they granted their GitHub token, which private repositories need.
+func (s *Server) getSourceCommit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	kind := vars["kind"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if kind != "GitRepository" {
+		respondError(w, http.StatusBadRequest, "Commits can only be looked up for GitRepositories")
+		return
+	}
+	resource, err := s.resourceService.GetByKey(clusterID, kind, namespace, name)
+	if err != nil {
+		respondServiceError(w, err, "Resource not found", "Failed to get resource")
+		return
+	}
+
+	var object struct {
+		Spec struct {
+			URL string `json:"url"`
+		} `json:"spec"`
+		Status struct {
+			Artifact *struct {
+				Revision string `json:"revision"`
+			} `json:"artifact"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(resource.Metadata), &object); err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to read resource: %v", err))
+		return
+	}
+	repo := gitHubRepoPattern.FindStringSubmatch(object.Spec.URL)
+	if repo == nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("%s is not a GitHub repository", object.Spec.URL))
+		return
+	}
+	if object.Status.Artifact == nil {
+		respondError(w, http.StatusNotFound, "The GitRepository has not fetched a revision yet")
+		return
+	}
+	sha := revisionSHAPattern.FindStringSubmatch(object.Status.Artifact.Revision)
+	if sha == nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Revision %s names no commit", object.Status.Artifact.Revision))
+		return
+	}
+
+	user, _ := r.Context().Value("user").(*auth.UserInfo)
+	client, err := s.providerClient(r.Context(), user)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	commit := &SourceCommit{Repository: repo[1] + "/" + repo[2], SHA: sha[1], AsUser: client != nil}
+	if client == nil {
+		client = httpclient.New(30 * time.Second)
+	}
+
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s", url.PathEscape(repo[1]), url.PathEscape(repo[2]), sha[1])
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, apiURL, nil)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create request: %v", err))
+		return
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := client.Do(req)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, fmt.Sprintf("Failed to reach GitHub: %v", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && !commit.AsUser:
+		// GitHub hides private repositories from anonymous requests
+		respondError(w, http.StatusNotFound, "Commit not found; grant GitHub access in Settings to read private repositories")
+		return
+	case resp.StatusCode == http.StatusNotFound:
+		respondError(w, http.StatusNotFound, "Commit not found, or your GitHub token cannot read the repository")
+		return
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		respondError(w, http.StatusBadGateway, fmt.Sprintf("GitHub returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+		return
+	}
+
+	var payload struct {
+		HTMLURL string `json:"html_url"`
+		Commit  struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		respondError(w, http.StatusBadGateway, fmt.Sprintf("Failed to parse GitHub response: %v", err))
+		return
+	}
+	commit.Message = payload.Commit.Message
+	commit.AuthorName = payload.Commit.Author.Name
+	commit.AuthorEmail = payload.Commit.Author.Email
+	commit.Date = payload.Commit.Author.Date
+	commit.URL = payload.HTMLURL
+
+	respondJSON(w, http.StatusOK, commit)
+}
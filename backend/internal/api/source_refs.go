@@ -0,0 +1,317 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
+	"github.com/gorilla/mux"
+)
+
+// maxRefAdvertisement caps how much of a git server's ref advertisement is read
+const maxRefAdvertisement = 10 << 20
+
+// GitRef is a branch or tag and the commit it points at
+type GitRef struct {
+	Name string `json:"name"`
+	SHA  string `json:"sha"`
+}
+
+// SourceRefs are the branches and tags of a GitRepository's remote, to pick its ref from
+type SourceRefs struct {
+	Namespace string   `json:"namespace"` // of the GitRepository, which a Kustomization may name
+	Name      string   `json:"name"`
+	URL       string   `json:"url"`
+	Branch    string   `json:"branch,omitempty"` // spec.ref.branch as last synced
+	Tag       string   `json:"tag,omitempty"`    // spec.ref.tag as last synced
+	Branches  []GitRef `json:"branches"`
+	Tags      []GitRef `json:"tags"`
+	// AsUser is set when the refs were read from GitHub with the caller's granted token
+	AsUser bool `json:"as_user"`
+}
+
+// errRemoteNotFound is returned when a repository does not exist or its server refused an
+// anonymous request
+var errRemoteNotFound = errors.New("repository not found")
+
+// getSourceRefs lists the branches and tags of a GitRepository's remote, or of the
+// GitRepository a Kustomization takes its source from. GitHub repositories are read through
+// its API, as the caller when they granted their GitHub token; other https remotes are read
+// anonymously like git ls-remote.
+func (s *Server) getSourceRefs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	kind := vars["kind"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	switch kind {
+	case "GitRepository":
+	case "Kustomization":
+		source, err := s.kustomizationGitSource(clusterID, namespace, name)
+		if err != nil {
+			respondServiceError(w, err, "Resource not found", "Failed to get resource")
+			return
+		}
+		if source == nil {
+			respondError(w, http.StatusBadRequest, "The Kustomization's source is not a GitRepository")
+			return
+		}
+		namespace, name = source[0], source[1]
+	default:
+		respondError(w, http.StatusBadRequest, "Refs can only be listed for GitRepositories and Kustomizations")
+		return
+	}
+
+	resource, err := s.resourceService.GetByKey(clusterID, "GitRepository", namespace, name)
+	if err != nil {
+		respondServiceError(w, err, "GitRepository not found", "Failed to get resource")
+		return
+	}
+	var object struct {
+		Spec struct {
+			URL string `json:"url"`
+			Ref struct {
+				Branch string `json:"branch"`
+				Tag    string `json:"tag"`
+			} `json:"ref"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(resource.Metadata), &object); err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to read resource: %v", err))
+		return
+	}
+
+	refs := &SourceRefs{
+		Namespace: namespace,
+		Name:      name,
+		URL:       object.Spec.URL,
+		Branch:    object.Spec.Ref.Branch,
+		Tag:       object.Spec.Ref.Tag,
+	}
+	var all []GitRef
+	if repo := gitHubRepoPattern.FindStringSubmatch(object.Spec.URL); repo != nil {
+		user, _ := r.Context().Value("user").(*auth.UserInfo)
+		client, err := s.providerClient(r.Context(), user)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		refs.AsUser = client != nil
+		if client == nil {
+			client = httpclient.New(30 * time.Second)
+		}
+		all, err = gitHubRefs(r.Context(), client, repo[1], repo[2])
+		if errors.Is(err, errRemoteNotFound) {
+			if refs.AsUser {
+				respondError(w, http.StatusNotFound, "Repository not found, or your GitHub token cannot read it")
+			} else {
+				respondError(w, http.StatusNotFound, "Repository not found; grant GitHub access in Settings to read private repositories")
+			}
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+	} else {
+		remote, err := url.Parse(object.Spec.URL)
+		if err != nil || (remote.Scheme != "https" && remote.Scheme != "http") {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("Refs can only be listed for https and GitHub repositories, not %s", object.Spec.URL))
+			return
+		}
+		all, err = lsRemote(r.Context(), httpclient.New(30*time.Second), remote)
+		if errors.Is(err, errRemoteNotFound) {
+			respondError(w, http.StatusNotFound, "Repository not found, or it needs credentials; only public repositories can be browsed")
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+	}
+
+	refs.Branches, refs.Tags = splitRefs(all)
+	respondJSON(w, http.StatusOK, refs)
+}
+
+// kustomizationGitSource returns the namespace and name of the GitRepository a Kustomization
+// takes its source from, or nil for other source kinds
+func (s *Server) kustomizationGitSource(clusterID, namespace, name string) ([]string, error) {
+	resource, err := s.resourceService.GetByKey(clusterID, "Kustomization", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	var object struct {
+		Spec struct {
+			SourceRef struct {
+				Kind      string `json:"kind"`
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"sourceRef"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(resource.Metadata), &object); err != nil {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+	source := object.Spec.SourceRef
+	if source.Kind != "GitRepository" || source.Name == "" {
+		return nil, nil
+	}
+	if source.Namespace == "" {
+		source.Namespace = namespace
+	}
+	return []string{source.Namespace, source.Name}, nil
+}
+
+// gitHubRefs lists the branches and tags of a GitHub repository, as full ref names
+func gitHubRefs(ctx context.Context, client *http.Client, owner, repo string) ([]GitRef, error) {
+	var refs []GitRef
+	for _, prefix := range []string{"heads", "tags"} {
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/matching-refs/%s", url.PathEscape(owner), url.PathEscape(repo), prefix)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach GitHub: %w", err)
+		}
+		var payload []struct {
+			Ref    string `json:"ref"`
+			Object struct {
+				SHA string `json:"sha"`
+			} `json:"object"`
+		}
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			err = errRemoteNotFound
+		case resp.StatusCode != http.StatusOK:
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			err = fmt.Errorf("GitHub returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		default:
+			if decodeErr := json.NewDecoder(resp.Body).Decode(&payload); decodeErr != nil {
+				err = fmt.Errorf("failed to parse GitHub response: %w", decodeErr)
+			}
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range payload {
+			refs = append(refs, GitRef{Name: ref.Ref, SHA: ref.Object.SHA})
+		}
+	}
+	return refs, nil
+}
+
+// lsRemote reads the refs a git server advertises over the smart HTTP protocol, as
+// git ls-remote does, returning them as full ref names
+func lsRemote(ctx context.Context, client *http.Client, remote *url.URL) ([]GitRef, error) {
+	infoRefs := *remote
+	infoRefs.Path = strings.TrimSuffix(infoRefs.Path, "/") + "/info/refs"
+	infoRefs.RawQuery = "service=git-upload-pack"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, infoRefs.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// Some servers only speak the smart protocol to git clients
+	req.Header.Set("User-Agent", "git/2.0 (flux-orchestrator)")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", remote.Host, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		return nil, errRemoteNotFound
+	default:
+		return nil, fmt.Errorf("%s returned status %d", remote.Host, resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Type") != "application/x-git-upload-pack-advertisement" {
+		return nil, fmt.Errorf("%s does not speak the git smart HTTP protocol", remote.Host)
+	}
+	return parseRefAdvertisement(io.LimitReader(resp.Body, maxRefAdvertisement))
+}
+
+// parseRefAdvertisement parses the pkt-lines of a git-upload-pack ref advertisement. Peeled
+// annotated tags (ref^{}) replace the tag object with the commit it points at.
+func parseRefAdvertisement(body io.Reader) ([]GitRef, error) {
+	reader := bufio.NewReader(body)
+	var refs []GitRef
+	index := map[string]int{}
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(reader, size[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return refs, nil
+			}
+			return nil, fmt.Errorf("malformed ref advertisement: %w", err)
+		}
+		length, err := strconv.ParseUint(string(size[:]), 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("malformed ref advertisement: bad pkt-line length %q", size)
+		}
+		if length == 0 {
+			continue // flush-pkt between the service line and the refs, and at the end
+		}
+		if length < 4 {
+			return nil, fmt.Errorf("malformed ref advertisement: bad pkt-line length %q", size)
+		}
+		line := make([]byte, length-4)
+		if _, err := io.ReadFull(reader, line); err != nil {
+			return nil, fmt.Errorf("malformed ref advertisement: %w", err)
+		}
+
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		if bytes.HasPrefix(line, []byte("#")) {
+			continue // # service=git-upload-pack
+		}
+		if i := bytes.IndexByte(line, 0); i >= 0 {
+			line = line[:i] // capabilities follow the first ref
+		}
+		sha, name, ok := strings.Cut(string(line), " ")
+		if !ok {
+			continue
+		}
+		if peeled, isPeeled := strings.CutSuffix(name, "^{}"); isPeeled {
+			if i, exists := index[peeled]; exists {
+				refs[i].SHA = sha
+			}
+			continue
+		}
+		index[name] = len(refs)
+		refs = append(refs, GitRef{Name: name, SHA: sha})
+	}
+}
+
+// splitRefs sorts full ref names into branches and tags, by short name
+func splitRefs(refs []GitRef) ([]GitRef, []GitRef) {
+	branches, tags := []GitRef{}, []GitRef{}
+	for _, ref := range refs {
+		if name, ok := strings.CutPrefix(ref.Name, "refs/heads/"); ok {
+			branches = append(branches, GitRef{Name: name, SHA: ref.SHA})
+		} else if name, ok := strings.CutPrefix(ref.Name, "refs/tags/"); ok {
+			tags = append(tags, GitRef{Name: name, SHA: ref.SHA})
+		}
+	}
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return branches, tags
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// specUpdateAllowlistSetting holds the kinds and spec fields editable through spec updates,
+// as comma-separated Kind or Kind.field entries
+const specUpdateAllowlistSetting = "spec_update_allowlist"
+
+// secretUpdatePermission is required to edit Secret contents; Secrets are never covered by
+// the allowlist
+const secretUpdatePermission = "secret.update"
+
+// defaultSpecUpdateAllowlist applies while the setting is unset: Flux resources and
+// workloads, every spec field
+const defaultSpecUpdateAllowlist = "Kustomization,HelmRelease,GitRepository,HelmRepository,OCIRepository,Bucket,Alert,Provider,Receiver,Deployment,StatefulSet,DaemonSet,CronJob"
+
+// specUpdatePolicy maps each editable kind to its editable spec fields; a nil list allows
+// every field
+type specUpdatePolicy map[string][]string
+
+// parseSpecUpdateAllowlist parses Kind and Kind.field entries. Kind and Kind.* allow every
+// field of the kind.
+func parseSpecUpdateAllowlist(value string) specUpdatePolicy {
+	policy := specUpdatePolicy{}
+	allFields := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, field, hasField := strings.Cut(entry, ".")
+		if !hasField || field == "*" {
+			allFields[kind] = true
+			policy[kind] = nil
+			continue
+		}
+		if !allFields[kind] {
+			policy[kind] = append(policy[kind], field)
+		}
+	}
+	return policy
+}
+
+// specUpdatePolicy loads the spec update allowlist from settings
+func (s *Server) specUpdatePolicy() specUpdatePolicy {
+	var setting models.Setting
+	if err := s.db.Where("setting_key = ?", specUpdateAllowlistSetting).First(&setting).Error; err != nil || strings.TrimSpace(setting.Value) == "" {
+		return parseSpecUpdateAllowlist(defaultSpecUpdateAllowlist)
+	}
+	return parseSpecUpdateAllowlist(setting.Value)
+}
+
+// check returns why patch may not be applied to a resource of kind, or nil
+func (p specUpdatePolicy) check(kind string, patch map[string]interface{}) error {
+	for key := range patch {
+		if key != "spec" {
+			return fmt.Errorf("only spec can be updated, not %s", key)
+		}
+	}
+
+	fields, ok := p[kind]
+	if !ok {
+		return fmt.Errorf("%s resources cannot be edited (%s setting)", kind, specUpdateAllowlistSetting)
+	}
+	if fields == nil {
+		return nil
+	}
+	for _, field := range patchFields(patch, "spec") {
+		allowed := false
+		for _, f := range fields {
+			if f == field {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("spec.%s of %s resources cannot be edited (%s setting)", field, kind, specUpdateAllowlistSetting)
+		}
+	}
+	return nil
+}
+
+// checkSecretUpdate returns why the requesting user may not edit a Secret with patch, or nil
+func (s *Server) checkSecretUpdate(r *http.Request, patch map[string]interface{}) error {
+	for key := range patch {
+		if key != "data" && key != "stringData" {
+			return fmt.Errorf("only data and stringData of a Secret can be updated, not %s", key)
+		}
+	}
+
+	if err := s.requirePermission(r, secretUpdatePermission); err != nil {
+		return fmt.Errorf("editing Secrets: %w", err)
+	}
+	return nil
+}
+
+// patchFields returns the sorted top-level fields a patch sets under each of keys, for
+// auditing without recording values
+func patchFields(patch map[string]interface{}, keys ...string) []string {
+	var fields []string
+	for _, key := range keys {
+		values, ok := patch[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for field := range values {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/bulk"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/jobs"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/webhooks"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxConcurrentSyncs caps full cluster syncs running at once; more wait for a slot
+const maxConcurrentSyncs = 4
+
+// SyncJob is a full sync of a cluster's Flux resources run in the background. Its status
+// is one of the bulk job states.
+type SyncJob struct {
+	ID          string     `json:"id"`
+	ClusterID   string     `json:"cluster_id"`
+	Status      string     `json:"status"`
+	Count       int        `json:"count"` // resources synced
+	Error       string     `json:"error,omitempty"`
+	RequestedBy string     `json:"requested_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+}
+
+// finished reports whether a job completed or failed
+func (j *SyncJob) finished() bool {
+	return j.Status == bulk.JobCompleted || j.Status == bulk.JobFailed
+}
+
+// syncJobs runs full cluster syncs and keeps their outcome for bulk.JobTTL
+type syncJobs struct {
+	jobs *jobs.Store[SyncJob]
+}
+
+func newSyncJobs() *syncJobs {
+	return &syncJobs{jobs: jobs.NewStore[SyncJob](maxConcurrentSyncs)}
+}
+
+// start queues a sync of a cluster, or returns the one already queued or running for it
+// and false. run performs the sync and done is called with the finished job.
+func (m *syncJobs) start(clusterID, requestedBy string, run func() (int, error), done func(SyncJob)) (SyncJob, bool) {
+	now := time.Now()
+	job := &SyncJob{
+		ID:          uuid.New().String(),
+		ClusterID:   clusterID,
+		Status:      bulk.JobPending,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(bulk.JobTimeout + bulk.JobTTL),
+	}
+	snapshot, added := m.jobs.AddUnless(job.ID, job, func(existing *SyncJob) bool {
+		return existing.ClusterID == clusterID && !existing.finished()
+	})
+	if added {
+		go m.run(job.ID, run, done)
+	}
+	return snapshot, added
+}
+
+// run syncs once a slot is free
+func (m *syncJobs) run(id string, run func() (int, error), done func(SyncJob)) {
+	m.jobs.Acquire()
+	defer m.jobs.Release()
+
+	m.jobs.Update(id, func(j *SyncJob) {
+		started := time.Now()
+		j.Status = bulk.JobRunning
+		j.StartedAt = &started
+	})
+
+	count, err := run()
+
+	var job SyncJob
+	m.jobs.Update(id, func(j *SyncJob) {
+		completed := time.Now()
+		j.CompletedAt = &completed
+		j.ExpiresAt = completed.Add(bulk.JobTTL)
+		j.Count = count
+		j.Status = bulk.JobCompleted
+		if err != nil {
+			j.Status = bulk.JobFailed
+			j.Error = err.Error()
+		}
+		job = *j
+	})
+	done(job)
+}
+
+// get returns a copy of a job
+func (m *syncJobs) get(id string) (SyncJob, bool) {
+	return m.jobs.Get(id)
+}
+
+// cleanExpired removes expired jobs
+func (m *syncJobs) cleanExpired() {
+	now := time.Now()
+	m.jobs.RemoveIf(func(job *SyncJob) bool {
+		return job.finished() && now.After(job.ExpiresAt)
+	})
+}
+
+// syncClusterResources queues a full sync of a cluster's Flux resources and answers 202 with
+// the job, or the sync already queued or running for the cluster. Clients poll the job's
+// Location, or wait for the sync.completed or sync.failed event carrying its job_id.
+func (s *Server) syncClusterResources(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+
+	cluster, err := s.clusterService.Get(clusterID)
+	if err != nil {
+		respondServiceError(w, err, "Cluster not found", "Failed to sync resources")
+		return
+	}
+	if cluster.Archived {
+		respondError(w, http.StatusConflict, fmt.Sprintf("Cluster %s is archived; unarchive it to sync", cluster.Name))
+		return
+	}
+
+	job, _ := s.syncJobs.start(clusterID, requestActor(r), func() (int, error) {
+		return s.resourceService.Sync(clusterID)
+	}, func(job SyncJob) {
+		s.notifySyncJob(job)
+		status, message := "success", fmt.Sprintf("Synced %d resources", job.Count)
+		if job.Status == bulk.JobFailed {
+			status, message = "failed", fmt.Sprintf("Error: %s", job.Error)
+		}
+		s.logActivity(job.RequestedBy, "sync", "Cluster", clusterID, cluster.Name, clusterID, cluster.Name, status, message)
+	})
+
+	w.Header().Set("Location", s.appPath(fmt.Sprintf("/api/v1/clusters/%s/sync/%s", clusterID, job.ID)))
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// notifySyncJob records the outcome of a sync job on the events channel
+func (s *Server) notifySyncJob(job SyncJob) {
+	if s.webhooks == nil {
+		return
+	}
+	event := webhooks.Event{
+		Type:      webhooks.EventSyncCompleted,
+		ClusterID: job.ClusterID,
+		Resource:  map[string]interface{}{"job_id": job.ID, "count": job.Count},
+		Message:   fmt.Sprintf("Synced %d resources from cluster %s", job.Count, job.ClusterID),
+		Severity:  "info",
+	}
+	if job.Status == bulk.JobFailed {
+		event.Type = webhooks.EventSyncFailed
+		event.Message = fmt.Sprintf("Failed to sync cluster %s: %s", job.ClusterID, job.Error)
+		event.Severity = "error"
+	}
+	s.webhooks.Notify(event)
+}
+
+// getSyncJob returns the progress and outcome of a cluster sync
+func (s *Server) getSyncJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	job, ok := s.syncJobs.get(vars["jobId"])
+	if !ok || job.ClusterID != vars["id"] {
+		respondError(w, http.StatusNotFound, "Sync job not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, job)
+}
+
+// cleanupSyncJobs periodically removes expired sync jobs
+func (s *Server) cleanupSyncJobs() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.syncJobs.cleanExpired()
+	}
+}
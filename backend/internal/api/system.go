@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/status"
+)
+
+// systemStatusTimeout bounds the database checks made for the status endpoint
+const systemStatusTimeout = 5 * time.Second
+
+// syncStaleFactor is how many sync intervals may pass without a run before the worker is degraded
+const syncStaleFactor = 3
+
+// getSystemStatus reports the state of each subsystem. It responds 503 when any component is down
+// so it can be polled by external monitoring as well as the admin UI.
+func (s *Server) getSystemStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), systemStatusTimeout)
+	defer cancel()
+
+	report := status.NewReport([]status.Component{
+		s.databaseStatus(ctx),
+		s.syncWorkerStatus(),
+		s.webhookStatus(),
+		s.sessionStatus(),
+		s.clusterStatus(r),
+		s.azureStatus(ctx),
+	})
+
+	code := http.StatusOK
+	if report.Status == status.StateDown {
+		code = http.StatusServiceUnavailable
+	}
+	respondJSON(w, code, report)
+}
+
+// databaseStatus pings the database and reports connection pool usage
+func (s *Server) databaseStatus(ctx context.Context) status.Component {
+	c := status.Component{Name: "database"}
+	if s.db == nil {
+		c.Status = status.StateDown
+		c.Message = "Database is not configured"
+		return c
+	}
+
+	sqlDB, err := s.db.DB.DB()
+	if err != nil {
+		c.Status = status.StateDown
+		c.Message = fmt.Sprintf("Failed to get database handle: %v", err)
+		return c
+	}
+	start := time.Now()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		c.Status = status.StateDown
+		c.Message = fmt.Sprintf("Ping failed: %v", err)
+		return c
+	}
+
+	stats := sqlDB.Stats()
+	c.Status = status.StateOK
+	c.Details = map[string]interface{}{
+		"latency_ms":       time.Since(start).Milliseconds(),
+		"open_connections": stats.OpenConnections,
+		"in_use":           stats.InUse,
+		"idle":             stats.Idle,
+	}
+	return c
+}
+
+// syncWorkerStatus reports when the periodic sync last ran and whether it is overdue
+func (s *Server) syncWorkerStatus() status.Component {
+	c := status.Component{Name: "sync_worker", Status: status.StateOK}
+	run := status.SyncWorker.Snapshot()
+
+	details := map[string]interface{}{
+		"interval_seconds": run.Interval.Seconds(),
+	}
+	c.Details = details
+
+	if run.LastEnd.IsZero() {
+		c.Message = "Waiting for first sync run"
+		return c
+	}
+
+	details["last_run_at"] = run.LastStart
+	details["last_duration_ms"] = run.LastEnd.Sub(run.LastStart).Milliseconds()
+	details["clusters"] = run.Clusters
+	details["failures"] = run.Failures
+
+	switch {
+	case run.LastError != "":
+		c.Status = status.StateDegraded
+		c.Message = run.LastError
+	case run.Interval > 0 && time.Since(run.LastEnd) > syncStaleFactor*run.Interval:
+		c.Status = status.StateDegraded
+		c.Message = fmt.Sprintf("No sync run since %s", run.LastEnd.Format(time.RFC3339))
+	case run.Failures > 0:
+		c.Status = status.StateDegraded
+		c.Message = fmt.Sprintf("%d of %d clusters failed to sync", run.Failures, run.Clusters)
+	}
+	return c
+}
+
+// webhookStatus reports the webhook delivery queue
+func (s *Server) webhookStatus() status.Component {
+	c := status.Component{Name: "webhooks"}
+	if !s.webhooks.Enabled() {
+		c.Status = status.StateDisabled
+		return c
+	}
+
+	c.Status = status.StateOK
+	c.Details = map[string]interface{}{
+		"endpoints": s.webhooks.EndpointCount(),
+		"pending":   s.webhooks.Pending(),
+		"failures":  s.webhooks.Failures(),
+	}
+	return c
+}
+
+// sessionStatus reports the session store
+func (s *Server) sessionStatus() status.Component {
+	c := status.Component{Name: "sessions"}
+	if !s.authEnabled {
+		c.Status = status.StateDisabled
+		c.Message = "Authentication is disabled"
+		return c
+	}
+
+	store := "memory"
+	if s.sessionStore.Persistent() {
+		store = "database"
+	}
+	active, err := s.sessionStore.Count()
+	if err != nil {
+		c.Status = status.StateDown
+		c.Message = err.Error()
+		return c
+	}
+
+	c.Status = status.StateOK
+	c.Details = map[string]interface{}{
+		"active": active,
+		"store":  store,
+	}
+	return c
+}
+
+// clusterStatus reports how many of the clusters the caller can read are reachable;
+// archived clusters are not counted
+func (s *Server) clusterStatus(r *http.Request) status.Component {
+	c := status.Component{Name: "clusters"}
+
+	clusters, err := s.clusterService.List(s.clusterScope(r), false)
+	if err != nil {
+		c.Status = status.StateDown
+		c.Message = fmt.Sprintf("Failed to count clusters: %v", err)
+		return c
+	}
+	byStatus := make(map[string]int64)
+	for _, cluster := range clusters {
+		byStatus[cluster.Status]++
+	}
+	counts := make([]statusCount, 0, len(byStatus))
+	for clusterStatus, count := range byStatus {
+		counts = append(counts, statusCount{Status: clusterStatus, Count: count})
+	}
+
+	c.Status, c.Details = summarizeCounts(counts, "healthy")
+	if c.Status == status.StateDisabled {
+		c.Message = "No clusters registered"
+	}
+	return c
+}
+
+// azureStatus reports the state of the configured Azure subscriptions
+func (s *Server) azureStatus(ctx context.Context) status.Component {
+	c := status.Component{Name: "azure"}
+
+	var counts []statusCount
+	if err := s.db.WithContext(ctx).Model(&models.AzureSubscription{}).Select("status, count(*) as count").Group("status").Scan(&counts).Error; err != nil {
+		c.Status = status.StateDown
+		c.Message = fmt.Sprintf("Failed to count Azure subscriptions: %v", err)
+		return c
+	}
+
+	c.Status, c.Details = summarizeCounts(counts, "healthy")
+	if c.Status == status.StateDisabled {
+		c.Message = "No Azure subscriptions configured"
+	}
+	return c
+}
+
+// statusCount is a row of a GROUP BY status count query
+type statusCount struct {
+	Status string
+	Count  int64
+}
+
+// summarizeCounts turns per-status row counts into a component state: disabled when there are
+// no rows, degraded when any row is not in the healthy status
+func summarizeCounts(counts []statusCount, healthy string) (status.State, map[string]interface{}) {
+	var total, ok int64
+	byStatus := make(map[string]int64)
+	for _, row := range counts {
+		byStatus[row.Status] = row.Count
+		total += row.Count
+		if row.Status == healthy {
+			ok += row.Count
+		}
+	}
+
+	details := map[string]interface{}{
+		"total":     total,
+		"by_status": byStatus,
+	}
+	switch {
+	case total == 0:
+		return status.StateDisabled, details
+	case ok < total:
+		return status.StateDegraded, details
+	default:
+		return status.StateOK, details
+	}
+}
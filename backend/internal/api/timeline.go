@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// Deployment timeline limits
+const (
+	defaultTimelineWindow = 24 * time.Hour
+	defaultTimelineLimit  = 200
+	maxTimelineLimit      = 1000
+)
+
+// timelineEvent is a deployment event with the name of its cluster
+type timelineEvent struct {
+	models.DeploymentEvent
+	ClusterName string `json:"cluster_name"`
+}
+
+// getDeploymentTimeline returns the revisions Flux deployed across clusters, newest first,
+// to correlate what changed around an incident. The window is since/until (RFC 3339),
+// or around with window either side of it, and defaults to the last 24 hours. cluster_id,
+// kind and namespace narrow it.
+func (s *Server) getDeploymentTimeline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	since, until, err := timeWindow(query, defaultTimelineWindow)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := limitParam(query, defaultTimelineLimit, maxTimelineLimit)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	timeline := history.TimelineQuery{
+		Scope:     s.clusterScope(r),
+		ClusterID: query.Get("cluster_id"),
+		Kind:      query.Get("kind"),
+		Namespace: query.Get("namespace"),
+		Since:     since,
+		Until:     until,
+		Limit:     limit,
+	}
+
+	deployments, err := history.Timeline(s.db, timeline)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get timeline: %v", err))
+		return
+	}
+
+	clusterNames := map[string]string{}
+	events := make([]timelineEvent, 0, len(deployments))
+	for _, deployment := range deployments {
+		name, ok := clusterNames[deployment.ClusterID]
+		if !ok {
+			name = s.clusterService.Name(deployment.ClusterID)
+			clusterNames[deployment.ClusterID] = name
+		}
+		events = append(events, timelineEvent{DeploymentEvent: deployment, ClusterName: name})
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"since":     timeline.Since,
+		"until":     timeline.Until,
+		"events":    events,
+		"truncated": len(events) == timeline.Limit,
+	})
+}
+
+// timeWindow parses since and until (RFC 3339), or around with window either side of it
+// (30 minutes by default). Without them the window is the last fallback up to now.
+func timeWindow(query url.Values, fallback time.Duration) (time.Time, time.Time, error) {
+	now := time.Now()
+	since, until := now.Add(-fallback), now
+
+	if value := query.Get("around"); value != "" {
+		around, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return since, until, errors.New("around must be an RFC 3339 time")
+		}
+		window := 30 * time.Minute
+		if value := query.Get("window"); value != "" {
+			window, err = time.ParseDuration(value)
+			if err != nil || window <= 0 {
+				return since, until, errors.New("window must be a positive duration such as 30m")
+			}
+		}
+		return around.Add(-window), around.Add(window), nil
+	}
+
+	for param, target := range map[string]*time.Time{"since": &since, "until": &until} {
+		if value := query.Get(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return since, until, fmt.Errorf("%s must be an RFC 3339 time", param)
+			}
+			*target = parsed
+		}
+	}
+	if !since.Before(until) {
+		return since, until, errors.New("since must be before until")
+	}
+	return since, until, nil
+}
+
+// limitParam parses the limit parameter, capped at max
+func limitParam(query url.Values, fallback, max int) (int, error) {
+	value := query.Get("limit")
+	if value == "" {
+		return fallback, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	return min(limit, max), nil
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
+	"github.com/gorilla/mux"
+)
+
+// getClusterTrends returns time-bucketed ready/notReady counts for a cluster
+func (s *Server) getClusterTrends(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+
+	rangeParam := r.URL.Query().Get("range")
+	if rangeParam == "" {
+		rangeParam = "24h"
+	}
+
+	trendRange, ok := history.TrendRanges[rangeParam]
+	if !ok {
+		respondError(w, http.StatusBadRequest, "Range must be one of 24h, 7d, 30d")
+		return
+	}
+
+	buckets, err := history.ClusterTrend(s.db, clusterID, trendRange.Window, trendRange.Bucket, time.Now())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get trends: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"cluster_id":     clusterID,
+		"range":          rangeParam,
+		"bucket_seconds": int(trendRange.Bucket.Seconds()),
+		"buckets":        buckets,
+	})
+}
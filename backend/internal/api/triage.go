@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// getFluxResourceTriage returns the failing pods of a Kustomization or HelmRelease with
+// their restart counts, termination reasons and recent events
+func (s *Server) getFluxResourceTriage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+	kind := vars["kind"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if !k8s.OwnsWorkloads(kind) {
+		respondError(w, http.StatusBadRequest, "Triage is only available for Kustomizations and HelmReleases")
+		return
+	}
+
+	triage, err := s.k8sClient.GetPodTriage(r.Context(), clusterID, kind, namespace, name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to triage pods: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, triage)
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/gorilla/mux"
+)
+
+// unmanagedReport is the unmanaged resources report of a cluster
+type unmanagedReport struct {
+	ClusterID   string `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+	Namespace   string `json:"namespace,omitempty"`
+	*k8s.UnmanagedReport
+}
+
+// getUnmanagedResources reports the workloads of a cluster no Flux resource manages: no Flux
+// ownership labels and in no Kustomization's inventory, such as things deployed by hand.
+// Query parameter: namespace to scan one namespace, including kube-system.
+func (s *Server) getUnmanagedResources(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["id"]
+	namespace := r.URL.Query().Get("namespace")
+
+	report, err := s.k8sClient.GetUnmanagedResources(r.Context(), clusterID, namespace)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to scan for unmanaged resources: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, unmanagedReport{
+		ClusterID:       clusterID,
+		ClusterName:     s.clusterService.Name(clusterID),
+		Namespace:       namespace,
+		UnmanagedReport: report,
+	})
+}
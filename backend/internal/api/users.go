@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/auth"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/gorilla/mux"
+)
+
+// userStateResponse is the answer to disabling or enabling a user
+type userStateResponse struct {
+	User             models.User `json:"user"`
+	RevokedSessions  int         `json:"revoked_sessions"`
+	RevokedAPITokens int         `json:"revoked_api_tokens"`
+}
+
+// disableUser disables a user and revokes their sessions and API tokens at once. Until
+// re-enabled they cannot log in, and credentials the orchestrator does not issue, such as
+// JWTs and client certificates, are refused for them. Requires the user.update permission;
+// the activity log records who disabled whom.
+func (s *Server) disableUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.loadUser(w, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+	if err := s.requirePermission(r, userUpdatePermission); err != nil {
+		s.logActivity(requestActor(r), "disable", "user", user.ID, user.Email, "", "", "failed", fmt.Sprintf("Denied disabling: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Disabling users not allowed: %v", err))
+		return
+	}
+	if requester, _ := r.Context().Value("user").(*auth.UserInfo); requester != nil && strings.EqualFold(requester.Email, user.Email) {
+		respondError(w, http.StatusConflict, "You cannot disable your own account")
+		return
+	}
+
+	wasEnabled := user.Enabled
+	if err := s.db.Model(&models.User{}).Where("id = ?", user.ID).Update("enabled", false).Error; err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to disable user: %v", err))
+		return
+	}
+	sessions, tokens, err := s.revokeUserCredentials(user.Email)
+	if err != nil {
+		// The user is disabled, so their remaining credentials are refused regardless
+		s.logActivity(requestActor(r), "disable", "user", user.ID, user.Email, "", "", "failed", err.Error())
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	changes := &activityChangeSet{}
+	changes.change("enabled", wasEnabled, false)
+	changes.param("revoked_sessions", sessions)
+	changes.param("revoked_api_tokens", tokens)
+	s.logActivityDetails(requestActor(r), "disable", "user", user.ID, user.Email, "", "", "success",
+		fmt.Sprintf("%s disabled %s, revoking %d sessions and %d API tokens", requestActor(r), user.Email, sessions, tokens), changes)
+
+	user.Enabled = false
+	respondJSON(w, http.StatusOK, userStateResponse{User: *user, RevokedSessions: sessions, RevokedAPITokens: tokens})
+}
+
+// enableUser re-enables a disabled user, who can then log in again. Requires the
+// user.update permission.
+func (s *Server) enableUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.loadUser(w, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+	if err := s.requirePermission(r, userUpdatePermission); err != nil {
+		s.logActivity(requestActor(r), "enable", "user", user.ID, user.Email, "", "", "failed", fmt.Sprintf("Denied enabling: %v", err))
+		respondError(w, http.StatusForbidden, fmt.Sprintf("Enabling users not allowed: %v", err))
+		return
+	}
+
+	wasEnabled := user.Enabled
+	if err := s.db.Model(&models.User{}).Where("id = ?", user.ID).Update("enabled", true).Error; err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to enable user: %v", err))
+		return
+	}
+
+	changes := &activityChangeSet{}
+	changes.change("enabled", wasEnabled, true)
+	s.logActivityDetails(requestActor(r), "enable", "user", user.ID, user.Email, "", "", "success",
+		fmt.Sprintf("%s enabled %s", requestActor(r), user.Email), changes)
+
+	user.Enabled = true
+	respondJSON(w, http.StatusOK, userStateResponse{User: *user})
+}
+
+// revokeUserCredentials deletes the sessions and API tokens of a user, returning how many of
+// each were deleted
+func (s *Server) revokeUserCredentials(email string) (int, int, error) {
+	sessions, err := s.sessionStore.RevokeUser(email)
+	if err != nil {
+		return 0, 0, err
+	}
+	result := s.db.Where("LOWER(email) = ?", strings.ToLower(email)).Delete(&models.APIToken{})
+	if result.Error != nil {
+		return sessions, 0, fmt.Errorf("failed to revoke API tokens: %w", result.Error)
+	}
+	return sessions, int(result.RowsAffected), nil
+}
+
+// loadUser loads a user with their roles, responding 404 when there is none
+func (s *Server) loadUser(w http.ResponseWriter, id string) (*models.User, bool) {
+	var user models.User
+	if err := s.db.Preload("Roles").Where("id = ?", id).First(&user).Error; err != nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return nil, false
+	}
+	return &user, true
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// API versions served by the orchestrator
+const (
+	apiV1Prefix = "/api/v1"
+	apiV2Prefix = "/api/v2"
+)
+
+// v1CompatContextKey marks requests forwarded from /api/v2 to a v1 handler
+type v1CompatContextKey struct{}
+
+// v1CompatRouteContextKey holds the v1 route a forwarded request matched; mux only records
+// the v2 catch-all as its current route
+type v1CompatRouteContextKey struct{}
+
+// v1DeprecationMiddleware advertises the successor API version on v1 responses.
+// API_V1_SUNSET sets the Sunset header (RFC3339 date) once a removal date is agreed.
+func v1DeprecationMiddleware(next http.Handler) http.Handler {
+	var sunset string
+	if value := os.Getenv("API_V1_SUNSET"); value != "" {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			sunset = t.UTC().Format(http.TimeFormat)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if compat, _ := r.Context().Value(v1CompatContextKey{}).(bool); compat {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("API-Version", "v1")
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", `</api/v2>; rel="successor-version"`)
+		if sunset != "" {
+			w.Header().Set("Sunset", sunset)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// v2VersionMiddleware tags v2 responses with their API version
+func v2VersionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", "v2")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routesV2 registers the v2 API. Endpoints that have not moved to typed v2
+// responses yet are served by their v1 handler through v1Compat.
+func (s *Server) routesV2() {
+	v2 := s.router.PathPrefix(apiV2Prefix).Subrouter()
+	v2.Use(v2VersionMiddleware)
+	if s.authEnabled {
+		v2.Use(s.authMiddleware)
+		v2.Use(s.clusterAccessMiddleware)
+	}
+
+	v2.HandleFunc("/clusters", s.listClustersV2).Methods("GET", "OPTIONS")
+	v2.HandleFunc("/clusters/{id}", s.getClusterV2).Methods("GET", "OPTIONS")
+	v2.HandleFunc("/clusters/{id}/resources", s.listClusterResourcesV2).Methods("GET", "OPTIONS")
+	v2.HandleFunc("/resources", s.listResourcesV2).Methods("GET", "OPTIONS")
+	v2.HandleFunc("/resources/{id:.+}", s.getResourceV2).Methods("GET", "OPTIONS")
+
+	v2.PathPrefix("/").HandlerFunc(s.v1Compat)
+}
+
+// v1Compat serves a v2 path with the matching v1 handler
+func (s *Server) v1Compat(w http.ResponseWriter, r *http.Request) {
+	compat := r.Clone(context.WithValue(r.Context(), v1CompatContextKey{}, true))
+	compat.URL.Path = apiV1Prefix + strings.TrimPrefix(r.URL.Path, apiV2Prefix)
+	compat.URL.RawPath = ""
+
+	var match mux.RouteMatch
+	if !s.apiV1.Match(compat, &match) || match.Handler == nil {
+		respondV2Error(w, http.StatusNotFound, "not_found", "Endpoint not found")
+		return
+	}
+
+	setServedRoute(r, match.Route)
+	compat = compat.WithContext(context.WithValue(compat.Context(), v1CompatRouteContextKey{}, match.Route))
+	match.Handler.ServeHTTP(w, mux.SetURLVars(compat, match.Vars))
+}
+
+// ClusterV2 is the v2 representation of a cluster
+type ClusterV2 struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	FluxStatus  string    `json:"flux_status"`
+	FluxMessage string    `json:"flux_message,omitempty"`
+	FluxVersion string    `json:"flux_version,omitempty"`
+	IsFavorite  bool      `json:"is_favorite"`
+	Archived    bool      `json:"archived"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ResourceV2 is the v2 representation of a Flux resource; the raw object is not included
+type ResourceV2 struct {
+	ID            string     `json:"id"`
+	ClusterID     string     `json:"cluster_id"`
+	Kind          string     `json:"kind"`
+	Namespace     string     `json:"namespace"`
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Message       string     `json:"message,omitempty"`
+	Suspended     bool       `json:"suspended"`
+	LastReconcile *time.Time `json:"last_reconcile,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ListV2 wraps v2 collection responses
+type ListV2[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+// ErrorV2 is the v2 error body
+type ErrorV2 struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// respondV2Error writes a v2 error response
+func respondV2Error(w http.ResponseWriter, status int, code, message string) {
+	var body ErrorV2
+	body.Error.Code = code
+	body.Error.Message = message
+	respondJSON(w, status, body)
+}
+
+// respondV2ServiceError maps service errors to v2 error responses
+func respondV2ServiceError(w http.ResponseWriter, err error, notFoundMessage, failureMessage string) {
+	if repository.IsNotFound(err) {
+		respondV2Error(w, http.StatusNotFound, "not_found", notFoundMessage)
+		return
+	}
+	if repository.IsConflict(err) {
+		respondV2Error(w, http.StatusConflict, "conflict", "The record was modified by another request; reload it and try again")
+		return
+	}
+	respondV2Error(w, http.StatusInternalServerError, "internal", failureMessage)
+}
+
+// toClusterV2 converts a cluster model to its v2 representation
+func toClusterV2(c models.Cluster) ClusterV2 {
+	return ClusterV2{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Status:      c.Status,
+		FluxStatus:  c.FluxStatus,
+		FluxMessage: c.FluxMessage,
+		FluxVersion: c.FluxVersion,
+		IsFavorite:  c.IsFavorite,
+		Archived:    c.Archived,
+		Version:     c.Version,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+// toResourceV2 converts a resource model to its v2 representation
+func toResourceV2(res models.FluxResource) ResourceV2 {
+	dto := ResourceV2{
+		ID:        res.ID,
+		ClusterID: res.ClusterID,
+		Kind:      res.Kind,
+		Namespace: res.Namespace,
+		Name:      res.Name,
+		Status:    res.Status,
+		Message:   res.Message,
+		UpdatedAt: res.UpdatedAt,
+	}
+	if !res.LastReconcile.IsZero() {
+		lastReconcile := res.LastReconcile
+		dto.LastReconcile = &lastReconcile
+	}
+
+	var obj struct {
+		Spec struct {
+			Suspend bool `json:"suspend"`
+		} `json:"spec"`
+	}
+	if res.Metadata != "" && json.Unmarshal([]byte(res.Metadata), &obj) == nil {
+		dto.Suspended = obj.Spec.Suspend
+	}
+	return dto
+}
+
+// toResourceListV2 converts resource models to a v2 list
+func toResourceListV2(resources []models.FluxResource) ListV2[ResourceV2] {
+	items := make([]ResourceV2, 0, len(resources))
+	for _, res := range resources {
+		items = append(items, toResourceV2(res))
+	}
+	return ListV2[ResourceV2]{Items: items, Total: len(items)}
+}
+
+// listClustersV2 returns clusters as typed DTOs; archived clusters require ?include_archived=true
+func (s *Server) listClustersV2(w http.ResponseWriter, r *http.Request) {
+	clusters, err := s.clusterService.List(s.clusterScope(r), r.URL.Query().Get("include_archived") == "true")
+	if err != nil {
+		respondV2ServiceError(w, err, "Cluster not found", "Failed to query clusters")
+		return
+	}
+
+	items := make([]ClusterV2, 0, len(clusters))
+	for _, cluster := range clusters {
+		items = append(items, toClusterV2(cluster))
+	}
+	respondJSON(w, http.StatusOK, ListV2[ClusterV2]{Items: items, Total: len(items)})
+}
+
+// getClusterV2 returns a single cluster as a typed DTO
+func (s *Server) getClusterV2(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	cluster, err := s.clusterService.Get(id)
+	if err != nil {
+		respondV2ServiceError(w, err, "Cluster not found", "Failed to query cluster")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toClusterV2(*cluster))
+}
+
+// listClusterResourcesV2 returns a cluster's resources as typed DTOs
+func (s *Server) listClusterResourcesV2(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["id"]
+
+	resources, err := s.resourceService.ListByCluster(clusterID)
+	if err != nil {
+		respondV2ServiceError(w, err, "Cluster not found", "Failed to query resources")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toResourceListV2(resources))
+}
+
+// listResourcesV2 returns resources across clusters as typed DTOs
+func (s *Server) listResourcesV2(w http.ResponseWriter, r *http.Request) {
+	resources, err := s.resourceService.List(s.clusterScope(r), r.URL.Query().Get("kind"))
+	if err != nil {
+		respondV2ServiceError(w, err, "Resource not found", "Failed to query resources")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toResourceListV2(resources))
+}
+
+// getResourceV2 returns a single resource as a typed DTO
+func (s *Server) getResourceV2(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	res, err := s.resourceService.Get(s.clusterScope(r), id)
+	if err != nil {
+		respondV2ServiceError(w, err, "Resource not found", "Failed to query resource")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toResourceV2(*res))
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// How long a scale or restart is followed before its after snapshot is final
+const (
+	workloadSettleTimeout  = 5 * time.Minute
+	workloadSettleInterval = 5 * time.Second
+)
+
+// workloadActionDetails is stored as the details of scale and restart activities so
+// reviewers can see the effect of the action, not just that it happened
+type workloadActionDetails struct {
+	RequestedReplicas *int32                `json:"requested_replicas,omitempty"`
+	Before            *k8s.WorkloadSnapshot `json:"before,omitempty"`
+	After             *k8s.WorkloadSnapshot `json:"after,omitempty"`
+	Settled           bool                  `json:"settled"`   // the rollout finished before the after snapshot
+	Following         bool                  `json:"following"` // the after snapshot is still being updated
+}
+
+// snapshotWorkload captures a workload, returning nil when it cannot be read
+func (s *Server) snapshotWorkload(ctx context.Context, clusterID, kind, namespace, name string) *k8s.WorkloadSnapshot {
+	snapshot, err := s.k8sClient.GetWorkloadSnapshot(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		log.Printf("Warning: Failed to snapshot %s %s/%s: %v", kind, namespace, name, err)
+		return nil
+	}
+	return snapshot
+}
+
+// logWorkloadAction records a scale or restart with its snapshots. After a successful
+// action the workload is followed in the background until its rollout settles or
+// workloadSettleTimeout passes, and the after snapshot is updated in place.
+func (s *Server) logWorkloadAction(actor, action, kind, namespace, name, clusterID, status, message string, details workloadActionDetails) {
+	if status == "success" {
+		details.After = s.snapshotWorkload(context.Background(), clusterID, kind, namespace, name)
+		details.Settled = details.After != nil && details.After.Settled()
+		details.Following = details.After != nil && !details.Settled
+	}
+
+	activity := models.Activity{
+		Action:       action,
+		ResourceType: kind,
+		ResourceID:   fmt.Sprintf("%s/%s", namespace, name),
+		ResourceName: name,
+		ClusterID:    clusterID,
+		ClusterName:  s.clusterService.Name(clusterID),
+		Status:       status,
+		Message:      message,
+		Details:      encodeWorkloadDetails(details),
+	}
+	if !s.recordActivity(actor, &activity) {
+		return
+	}
+
+	if details.Following {
+		go s.followWorkloadAction(activity.ID, clusterID, kind, namespace, name, details)
+	}
+}
+
+// followWorkloadAction polls a workload until it settles and stores the final snapshot
+func (s *Server) followWorkloadAction(activityID uint, clusterID, kind, namespace, name string, details workloadActionDetails) {
+	ctx, cancel := context.WithTimeout(context.Background(), workloadSettleTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(workloadSettleInterval)
+	defer ticker.Stop()
+
+	for !details.Settled {
+		select {
+		case <-ctx.Done():
+			details.Following = false
+			s.updateWorkloadDetails(activityID, details)
+			return
+		case <-ticker.C:
+		}
+
+		snapshot, err := s.k8sClient.GetWorkloadSnapshot(ctx, clusterID, kind, namespace, name)
+		if err != nil {
+			continue
+		}
+		details.After = snapshot
+		details.Settled = snapshot.Settled()
+	}
+
+	details.Following = false
+	s.updateWorkloadDetails(activityID, details)
+}
+
+func (s *Server) updateWorkloadDetails(activityID uint, details workloadActionDetails) {
+	if err := s.activities.UpdateDetails(activityID, encodeWorkloadDetails(details)); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+func encodeWorkloadDetails(details workloadActionDetails) string {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// APITokenPrefix marks tokens issued by the orchestrator so they are easy to recognise in scripts and scanners
+const APITokenPrefix = "fo_"
+
+// GenerateAPIToken returns a new API token and the hash to store for it
+func GenerateAPIToken() (string, string, error) {
+	random, err := GenerateState()
+	if err != nil {
+		return "", "", err
+	}
+	token := APITokenPrefix + random
+	return token, HashAPIToken(token), nil
+}
+
+// HashAPIToken returns the stored form of an API token
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeviceAuth starts an OAuth device authorization with the provider
+func (p *OAuthProvider) DeviceAuth(ctx context.Context) (*oauth2.DeviceAuthResponse, error) {
+	return p.config.DeviceAuth(withHTTPClient(ctx))
+}
+
+// DeviceAccessToken polls the provider until the user approves the device
+// authorization, it is denied, or it expires
+func (p *OAuthProvider) DeviceAccessToken(ctx context.Context, da *oauth2.DeviceAuthResponse) (*oauth2.Token, error) {
+	return p.config.DeviceAccessToken(withHTTPClient(ctx), da)
+}
+
+// Device login states
+const (
+	DeviceLoginPending  = "authorization_pending"
+	DeviceLoginComplete = "complete"
+	DeviceLoginDenied   = "access_denied"
+	DeviceLoginExpired  = "expired_token"
+)
+
+// MaxPendingDeviceLogins caps device logins awaiting approval, each of which polls the
+// provider in the background until it completes or expires
+const MaxPendingDeviceLogins = 100
+
+// ErrTooManyDeviceLogins is returned by Add when MaxPendingDeviceLogins are pending
+var ErrTooManyDeviceLogins = errors.New("too many device logins are pending")
+
+// DeviceLogin tracks a device authorization until the client collects its token
+type DeviceLogin struct {
+	Status    string
+	Token     string
+	TokenID   string
+	ExpiresAt time.Time
+	Interval  int
+}
+
+// DeviceLoginStore holds pending device logins keyed by the handle given to the client
+type DeviceLoginStore struct {
+	mu     sync.Mutex
+	logins map[string]*DeviceLogin
+}
+
+func NewDeviceLoginStore() *DeviceLoginStore {
+	return &DeviceLoginStore{
+		logins: make(map[string]*DeviceLogin),
+	}
+}
+
+// Add registers a pending device login, unless MaxPendingDeviceLogins unexpired logins
+// are pending already
+func (s *DeviceLoginStore) Add(id string, login *DeviceLogin) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := 0
+	now := time.Now()
+	for _, existing := range s.logins {
+		if existing.Status == DeviceLoginPending && now.Before(existing.ExpiresAt) {
+			pending++
+		}
+	}
+	if pending >= MaxPendingDeviceLogins {
+		return ErrTooManyDeviceLogins
+	}
+	s.logins[id] = login
+	return nil
+}
+
+// Resolve records the outcome of a device login
+func (s *DeviceLoginStore) Resolve(id, status, token, tokenID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if login, exists := s.logins[id]; exists {
+		login.Status = status
+		login.Token = token
+		login.TokenID = tokenID
+	}
+}
+
+// Poll returns a copy of the device login. Completed and failed logins are
+// removed so the token can only be collected once.
+func (s *DeviceLoginStore) Poll(id string) (DeviceLogin, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	login, exists := s.logins[id]
+	if !exists {
+		return DeviceLogin{}, false
+	}
+	if login.Status == DeviceLoginPending && time.Now().After(login.ExpiresAt) {
+		login.Status = DeviceLoginExpired
+	}
+	if login.Status != DeviceLoginPending {
+		delete(s.logins, id)
+	}
+	return *login, true
+}
+
+// CleanExpired removes device logins that were never collected
+func (s *DeviceLoginStore) CleanExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, login := range s.logins {
+		if time.Now().After(login.ExpiresAt) {
+			delete(s.logins, id)
+		}
+	}
+}
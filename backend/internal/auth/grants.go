@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
+	"golang.org/x/oauth2"
+)
+
+// Provider grants let the orchestrator keep a user's own provider token, with their
+// consent, to call the git provider as them. Only GitHub supports them.
+
+// SupportsGrants reports whether the provider can grant tokens to act as the user
+func (p *OAuthProvider) SupportsGrants() bool {
+	return p.providerType == "github"
+}
+
+// ProviderType returns the provider's name, such as github or entra
+func (p *OAuthProvider) ProviderType() string {
+	return p.providerType
+}
+
+// GrantURL returns the consent page asking the user for a token with scopes. It returns
+// through the login callback, which tells the two flows apart by their state cookies.
+func (p *OAuthProvider) GrantURL(state string, scopes []string) string {
+	config := *p.config
+	config.Scopes = scopes
+	return config.AuthCodeURL(state)
+}
+
+// Client returns an HTTP client calling the provider's API with a user's token
+func (p *OAuthProvider) Client(ctx context.Context, token *oauth2.Token) *http.Client {
+	return p.config.Client(withHTTPClient(ctx), token)
+}
+
+// RevokeToken invalidates a granted token at the provider, so it stops working even if a
+// copy of it survived
+func (p *OAuthProvider) RevokeToken(ctx context.Context, accessToken string) error {
+	if !p.SupportsGrants() {
+		return fmt.Errorf("provider %s does not support token grants", p.providerType)
+	}
+
+	body, err := json.Marshal(map[string]string{"access_token": accessToken})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("https://api.github.com/applications/%s/token", p.config.ClientID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(p.config.ClientID, p.config.ClientSecret)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := httpclient.New(30 * time.Second).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 404 means the token was already revoked or expired
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("GitHub returned status %d revoking the token", resp.StatusCode)
+	}
+	return nil
+}
+
+// GrantedScopes returns the scopes the provider granted a token, which may be fewer than
+// were asked for
+func GrantedScopes(token *oauth2.Token) []string {
+	scope, _ := token.Extra("scope").(string)
+	var scopes []string
+	for _, s := range strings.Split(scope, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"time"
 
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
 	"golang.org/x/oauth2/microsoft"
@@ -36,6 +38,7 @@ type UserInfo struct {
 	Name     string
 	Username string
 	Provider string
+	Groups   []string // identity provider groups, for role mapping rules; empty when unavailable
 }
 
 func NewOAuthProvider(cfg Config) (*OAuthProvider, error) {
@@ -90,8 +93,33 @@ func (p *OAuthProvider) GetAuthURL(state string) string {
 	return p.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
 }
 
+// withHTTPClient makes oauth2 token and API calls use the shared outbound client
+func withHTTPClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, httpclient.New(30*time.Second))
+}
+
+// CheckReachable verifies the provider's authorization endpoint answers over the outbound client.
+// Any response below 500 counts as reachable since the endpoint expects browser parameters.
+func (p *OAuthProvider) CheckReachable(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Endpoint.AuthURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpclient.New(10 * time.Second).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", p.config.Endpoint.AuthURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s returned status %d", p.config.Endpoint.AuthURL, resp.StatusCode)
+	}
+	return nil
+}
+
 func (p *OAuthProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
-	return p.config.Exchange(ctx, code)
+	return p.config.Exchange(withHTTPClient(ctx), code)
 }
 
 func (p *OAuthProvider) GetUserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo, error) {
@@ -106,7 +134,7 @@ func (p *OAuthProvider) GetUserInfo(ctx context.Context, token *oauth2.Token) (*
 }
 
 func (p *OAuthProvider) getGitHubUserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo, error) {
-	client := p.config.Client(ctx, token)
+	client := p.config.Client(withHTTPClient(ctx), token)
 
 	resp, err := client.Get("https://api.github.com/user")
 	if err != nil {
@@ -152,17 +180,29 @@ func (p *OAuthProvider) getGitHubUserInfo(ctx context.Context, token *oauth2.Tok
 		}
 	}
 
+	// Organizations stand in for groups; private memberships need the read:org scope
+	var orgs []struct {
+		Login string `json:"login"`
+	}
+	var groups []string
+	if getJSON(client, "https://api.github.com/user/orgs", &orgs) == nil {
+		for _, org := range orgs {
+			groups = append(groups, org.Login)
+		}
+	}
+
 	return &UserInfo{
 		ID:       fmt.Sprintf("%d", githubUser.ID),
 		Email:    githubUser.Email,
 		Name:     githubUser.Name,
 		Username: githubUser.Login,
 		Provider: "github",
+		Groups:   groups,
 	}, nil
 }
 
 func (p *OAuthProvider) getEntraUserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo, error) {
-	client := p.config.Client(ctx, token)
+	client := p.config.Client(withHTTPClient(ctx), token)
 
 	resp, err := client.Get("https://graph.microsoft.com/v1.0/me")
 	if err != nil {
@@ -193,15 +233,44 @@ func (p *OAuthProvider) getEntraUserInfo(ctx context.Context, token *oauth2.Toke
 		email = msUser.UserPrincipalName
 	}
 
+	// Group names need the GroupMember.Read.All scope; without it the user has no groups
+	var memberOf struct {
+		Value []struct {
+			DisplayName string `json:"displayName"`
+		} `json:"value"`
+	}
+	var groups []string
+	if getJSON(client, "https://graph.microsoft.com/v1.0/me/memberOf?$select=displayName", &memberOf) == nil {
+		for _, group := range memberOf.Value {
+			if group.DisplayName != "" {
+				groups = append(groups, group.DisplayName)
+			}
+		}
+	}
+
 	return &UserInfo{
 		ID:       msUser.ID,
 		Email:    email,
 		Name:     msUser.DisplayName,
 		Username: msUser.UserPrincipalName,
 		Provider: "entra",
+		Groups:   groups,
 	}, nil
 }
 
+// getJSON decodes the JSON answer to a GET request, failing on non-200 responses
+func getJSON(client *http.Client, url string, out interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
 func (p *OAuthProvider) IsUserAllowed(userInfo *UserInfo) bool {
 	if len(p.allowedUsers) == 0 {
 		return true // No restrictions
@@ -217,61 +286,3 @@ func GenerateState() (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(b), nil
 }
-
-// Session management
-type Session struct {
-	Token     string
-	UserInfo  *UserInfo
-	ExpiresAt time.Time
-}
-
-type SessionStore struct {
-	sessions map[string]*Session
-}
-
-func NewSessionStore() *SessionStore {
-	return &SessionStore{
-		sessions: make(map[string]*Session),
-	}
-}
-
-func (s *SessionStore) Create(userInfo *UserInfo) (string, error) {
-	token, err := GenerateState()
-	if err != nil {
-		return "", err
-	}
-
-	s.sessions[token] = &Session{
-		Token:     token,
-		UserInfo:  userInfo,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-	}
-
-	return token, nil
-}
-
-func (s *SessionStore) Get(token string) (*Session, bool) {
-	session, exists := s.sessions[token]
-	if !exists {
-		return nil, false
-	}
-
-	if time.Now().After(session.ExpiresAt) {
-		delete(s.sessions, token)
-		return nil, false
-	}
-
-	return session, true
-}
-
-func (s *SessionStore) Delete(token string) {
-	delete(s.sessions, token)
-}
-
-func (s *SessionStore) CleanExpired() {
-	for token, session := range s.sessions {
-		if time.Now().After(session.ExpiresAt) {
-			delete(s.sessions, token)
-		}
-	}
-}
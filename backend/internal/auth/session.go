@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SessionTTL is how long a login session lasts
+const SessionTTL = 24 * time.Hour
+
+// Session management
+type Session struct {
+	Token     string
+	UserInfo  *UserInfo
+	ExpiresAt time.Time
+}
+
+// SessionStore holds login sessions, in memory or, when created with a database, in the
+// sessions table so they survive restarts and are shared between replicas. Stored sessions
+// keep only the SHA-256 hash of their token and the user encrypted with the Fernet key;
+// the provider's OAuth tokens are never kept. It is safe for concurrent use.
+type SessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]*Session
+	db        *database.DB
+	encryptor *encryption.Encryptor
+}
+
+func NewSessionStore() *SessionStore {
+	return &SessionStore{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// NewDatabaseSessionStore creates a session store persisting sessions in db, with the user
+// of each encrypted by encryptor
+func NewDatabaseSessionStore(db *database.DB, encryptor *encryption.Encryptor) (*SessionStore, error) {
+	if db == nil || encryptor == nil {
+		return nil, errors.New("a database session store needs a database and an encryption key")
+	}
+	return &SessionStore{db: db, encryptor: encryptor}, nil
+}
+
+// Persistent reports whether sessions are stored in the database
+func (s *SessionStore) Persistent() bool {
+	return s.db != nil
+}
+
+func (s *SessionStore) Create(userInfo *UserInfo) (string, error) {
+	token, err := GenerateState()
+	if err != nil {
+		return "", err
+	}
+	expiresAt := time.Now().Add(SessionTTL)
+
+	if s.db != nil {
+		data, err := json.Marshal(userInfo)
+		if err != nil {
+			return "", err
+		}
+		sealed, err := s.encryptor.Encrypt(string(data))
+		if err != nil {
+			return "", err
+		}
+		record := &models.Session{
+			TokenHash: hashSessionToken(token),
+			UserHash:  hashSessionUser(userInfo.Email),
+			UserInfo:  sealed,
+			ExpiresAt: expiresAt,
+		}
+		if err := s.db.Create(record).Error; err != nil {
+			return "", fmt.Errorf("failed to store session: %w", err)
+		}
+		return token, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[token] = &Session{
+		Token:     token,
+		UserInfo:  userInfo,
+		ExpiresAt: expiresAt,
+	}
+
+	return token, nil
+}
+
+func (s *SessionStore) Get(token string) (*Session, bool) {
+	if s.db != nil {
+		return s.getStored(token)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, exists := s.sessions[token]
+	if !exists {
+		return nil, false
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		delete(s.sessions, token)
+		return nil, false
+	}
+
+	return session, true
+}
+
+// getStored loads a session from the database. Sessions that expired or no longer decrypt,
+// after the encryption key changed, are deleted.
+func (s *SessionStore) getStored(token string) (*Session, bool) {
+	var record models.Session
+	err := s.db.First(&record, "token_hash = ?", hashSessionToken(token)).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to load session: %v", err)
+		return nil, false
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		s.deleteStored(record.TokenHash)
+		return nil, false
+	}
+	data, err := s.encryptor.Decrypt(record.UserInfo)
+	if err != nil {
+		log.Printf("Warning: Dropping session that failed to decrypt: %v", err)
+		s.deleteStored(record.TokenHash)
+		return nil, false
+	}
+	var userInfo UserInfo
+	if err := json.Unmarshal([]byte(data), &userInfo); err != nil {
+		log.Printf("Warning: Dropping malformed session: %v", err)
+		s.deleteStored(record.TokenHash)
+		return nil, false
+	}
+
+	return &Session{Token: token, UserInfo: &userInfo, ExpiresAt: record.ExpiresAt}, true
+}
+
+func (s *SessionStore) Delete(token string) {
+	if s.db != nil {
+		s.deleteStored(hashSessionToken(token))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, token)
+}
+
+// RevokeUser deletes every session of the user with the given email, returning how many
+// were deleted. Stored sessions created before sessions recorded their user are not found;
+// they expire with SessionTTL.
+func (s *SessionStore) RevokeUser(email string) (int, error) {
+	if email == "" {
+		return 0, nil
+	}
+	if s.db != nil {
+		result := s.db.Where("user_hash = ?", hashSessionUser(email)).Delete(&models.Session{})
+		if result.Error != nil {
+			return 0, fmt.Errorf("failed to revoke sessions: %w", result.Error)
+		}
+		return int(result.RowsAffected), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revoked := 0
+	for token, session := range s.sessions {
+		if session.UserInfo != nil && strings.EqualFold(session.UserInfo.Email, email) {
+			delete(s.sessions, token)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (s *SessionStore) deleteStored(tokenHash string) {
+	if err := s.db.Delete(&models.Session{}, "token_hash = ?", tokenHash).Error; err != nil {
+		log.Printf("Warning: Failed to delete session: %v", err)
+	}
+}
+
+// Count returns the number of stored sessions, including expired in-memory ones not yet
+// cleaned
+func (s *SessionStore) Count() (int, error) {
+	if s.db != nil {
+		var count int64
+		if err := s.db.Model(&models.Session{}).Where("expires_at > ?", time.Now()).Count(&count).Error; err != nil {
+			return 0, fmt.Errorf("failed to count sessions: %w", err)
+		}
+		return int(count), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions), nil
+}
+
+func (s *SessionStore) CleanExpired() {
+	if s.db != nil {
+		if err := s.db.Where("expires_at < ?", time.Now()).Delete(&models.Session{}).Error; err != nil {
+			log.Printf("Warning: Failed to clean expired sessions: %v", err)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token, session := range s.sessions {
+		if time.Now().After(session.ExpiresAt) {
+			delete(s.sessions, token)
+		}
+	}
+}
+
+// hashSessionToken returns the stored form of a session token
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashSessionUser returns the stored form of a session's user email
+func hashSessionUser(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return hex.EncodeToString(sum[:])
+}
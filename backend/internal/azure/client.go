@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
 )
 
 // Credentials represents Azure service principal credentials
@@ -43,6 +46,23 @@ func (c *Client) RemoveCredentials(subscriptionID string) {
 	log.Printf("Removed Azure credentials for subscription: %s", subscriptionID)
 }
 
+// sdkClientOptions routes Azure SDK requests through the shared outbound transport
+func sdkClientOptions() azcore.ClientOptions {
+	return azcore.ClientOptions{
+		Transport: httpclient.New(0),
+	}
+}
+
+// credentialOptions returns the options used for service principal credentials
+func credentialOptions() *azidentity.ClientSecretCredentialOptions {
+	return &azidentity.ClientSecretCredentialOptions{ClientOptions: sdkClientOptions()}
+}
+
+// armClientOptions returns the options used for resource manager clients
+func armClientOptions() *arm.ClientOptions {
+	return &arm.ClientOptions{ClientOptions: sdkClientOptions()}
+}
+
 // AKSCluster represents an AKS cluster with its configuration
 type AKSCluster struct {
 	ID                string
@@ -68,14 +88,14 @@ func (c *Client) DiscoverClusters(ctx context.Context, subscriptionID string) ([
 		creds.TenantID,
 		creds.ClientID,
 		creds.ClientSecret,
-		nil,
+		credentialOptions(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
 	// Create AKS client
-	clientFactory, err := armcontainerservice.NewClientFactory(subscriptionID, credential, nil)
+	clientFactory, err := armcontainerservice.NewClientFactory(subscriptionID, credential, armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create AKS client: %w", err)
 	}
@@ -144,14 +164,14 @@ func (c *Client) GenerateKubeconfig(ctx context.Context, cluster AKSCluster) (st
 		creds.TenantID,
 		creds.ClientID,
 		creds.ClientSecret,
-		nil,
+		credentialOptions(),
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
 	// Create AKS client
-	clientFactory, err := armcontainerservice.NewClientFactory(cluster.SubscriptionID, credential, nil)
+	clientFactory, err := armcontainerservice.NewClientFactory(cluster.SubscriptionID, credential, armClientOptions())
 	if err != nil {
 		return "", fmt.Errorf("failed to create AKS client: %w", err)
 	}
@@ -223,14 +243,14 @@ func (c *Client) GetClusterAdminCredentials(ctx context.Context, cluster AKSClus
 		creds.TenantID,
 		creds.ClientID,
 		creds.ClientSecret,
-		nil,
+		credentialOptions(),
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
 	// Create AKS client
-	clientFactory, err := armcontainerservice.NewClientFactory(cluster.SubscriptionID, credential, nil)
+	clientFactory, err := armcontainerservice.NewClientFactory(cluster.SubscriptionID, credential, armClientOptions())
 	if err != nil {
 		return "", fmt.Errorf("failed to create AKS client: %w", err)
 	}
@@ -263,14 +283,14 @@ func (c *Client) TestConnection(ctx context.Context, subscriptionID string) erro
 		creds.TenantID,
 		creds.ClientID,
 		creds.ClientSecret,
-		nil,
+		credentialOptions(),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
 	// Test by creating an AKS client and listing clusters
-	clientFactory, err := armcontainerservice.NewClientFactory(subscriptionID, credential, nil)
+	clientFactory, err := armcontainerservice.NewClientFactory(subscriptionID, credential, armClientOptions())
 	if err != nil {
 		return fmt.Errorf("failed to create AKS client: %w", err)
 	}
@@ -0,0 +1,227 @@
+// Package bulk runs suspend, resume and reconcile operations across many Flux resources
+// of a cluster in the background and keeps a summary of each run.
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/jobs"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Job states
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// Actions a job applies to every resource in its scope
+const (
+	ActionSuspend   = "suspend"
+	ActionResume    = "resume"
+	ActionReconcile = "reconcile"
+)
+
+// IsAction reports whether action is one a job can apply
+func IsAction(action string) bool {
+	return action == ActionSuspend || action == ActionResume || action == ActionReconcile
+}
+
+// Outcomes of a job for a single resource
+const (
+	ItemApplied = "applied"
+	ItemSkipped = "skipped" // already in the requested state, or suspended when reconciling
+	ItemFailed  = "failed"
+)
+
+// Background job limits
+const (
+	// JobTTL is how long a finished job's report stays available
+	JobTTL = time.Hour
+	// JobTimeout bounds how long a single job may take
+	JobTimeout = 15 * time.Minute
+	// maxConcurrentJobs caps jobs running at once
+	maxConcurrentJobs = 2
+)
+
+// ResourceActions suspends, resumes and reconciles single Flux resources
+type ResourceActions interface {
+	Suspend(ctx context.Context, clusterID, kind, namespace, name string) error
+	Resume(ctx context.Context, clusterID, kind, namespace, name string) error
+	Reconcile(ctx context.Context, clusterID, kind, namespace, name string) error
+}
+
+// ItemResult is the outcome of a job for one resource
+type ItemResult struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Summary counts the outcomes of a job
+type Summary struct {
+	Matched int `json:"matched"`
+	Applied int `json:"applied"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// Job suspends, resumes or reconciles every resource in a scope
+type Job struct {
+	ID          string           `json:"id"`
+	ClusterID   string           `json:"cluster_id"`
+	Action      string           `json:"action"`
+	Scope       k8s.SuspendScope `json:"scope"`
+	Status      string           `json:"status"`
+	Error       string           `json:"error,omitempty"`
+	RequestedBy string           `json:"requested_by"`
+	Summary     Summary          `json:"summary"`
+	Results     []ItemResult     `json:"results"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt time.Time        `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// Manager runs background jobs and keeps their reports until they expire
+type Manager struct {
+	actions ResourceActions
+	jobs    *jobs.Store[Job]
+}
+
+// NewManager creates a manager that acts on resources through actions
+func NewManager(actions ResourceActions) *Manager {
+	return &Manager{
+		actions: actions,
+		jobs:    jobs.NewStore[Job](maxConcurrentJobs),
+	}
+}
+
+// Start queues a job over the given targets and returns it pending. The job runs with the
+// values of ctx, such as provenance, but not its cancellation; done is called with the
+// finished job.
+func (m *Manager) Start(ctx context.Context, clusterID, action string, scope k8s.SuspendScope, requestedBy string, targets []k8s.SuspendTarget, done func(Job)) Job {
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.New().String(),
+		ClusterID:   clusterID,
+		Action:      action,
+		Scope:       scope,
+		Status:      JobPending,
+		RequestedBy: requestedBy,
+		Summary:     Summary{Matched: len(targets)},
+		Results:     []ItemResult{},
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(JobTimeout + JobTTL),
+	}
+
+	snapshot := m.jobs.Add(job.ID, job)
+	go m.run(context.WithoutCancel(ctx), job.ID, targets, done)
+	return snapshot
+}
+
+// run applies a job's action to each target once a slot is free
+func (m *Manager) run(ctx context.Context, id string, targets []k8s.SuspendTarget, done func(Job)) {
+	m.jobs.Acquire()
+	defer m.jobs.Release()
+
+	var job Job
+	m.jobs.Update(id, func(j *Job) {
+		j.Status = JobRunning
+		job = *j
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, JobTimeout)
+	defer cancel()
+
+	for _, target := range targets {
+		result := ItemResult{Kind: target.Kind, Namespace: target.Namespace, Name: target.Name}
+		switch {
+		case skips(job.Action, target):
+			result.Status = ItemSkipped
+		case ctx.Err() != nil:
+			result.Status, result.Error = ItemFailed, "job timed out before reaching this resource"
+		default:
+			var err error
+			switch job.Action {
+			case ActionSuspend:
+				err = m.actions.Suspend(ctx, job.ClusterID, target.Kind, target.Namespace, target.Name)
+			case ActionResume:
+				err = m.actions.Resume(ctx, job.ClusterID, target.Kind, target.Namespace, target.Name)
+			default:
+				err = m.actions.Reconcile(ctx, job.ClusterID, target.Kind, target.Namespace, target.Name)
+			}
+			result.Status = ItemApplied
+			if err != nil {
+				result.Status, result.Error = ItemFailed, err.Error()
+			}
+		}
+		m.jobs.Update(id, func(j *Job) {
+			j.Results = append(j.Results, result)
+			switch result.Status {
+			case ItemApplied:
+				j.Summary.Applied++
+			case ItemSkipped:
+				j.Summary.Skipped++
+			default:
+				j.Summary.Failed++
+			}
+		})
+	}
+
+	m.jobs.Update(id, func(j *Job) {
+		j.CompletedAt = time.Now()
+		j.ExpiresAt = j.CompletedAt.Add(JobTTL)
+		j.Status = JobCompleted
+		if ctx.Err() != nil {
+			j.Status = JobFailed
+			j.Error = fmt.Sprintf("timed out after %s", JobTimeout)
+		}
+		job = *j
+	})
+	if job.Summary.Failed > 0 {
+		logging.GetLogger().Warn("Bulk job finished with failures",
+			zap.String("job_id", job.ID),
+			zap.String("cluster_id", job.ClusterID),
+			zap.String("action", job.Action),
+			zap.Int("failed", job.Summary.Failed))
+	}
+	if done != nil {
+		done(job)
+	}
+}
+
+// skips reports whether an action leaves a target as it is: already in the requested state,
+// or suspended when reconciling, since Flux ignores reconcile requests while suspended
+func skips(action string, target k8s.SuspendTarget) bool {
+	if action == ActionResume {
+		return !target.Suspended
+	}
+	return target.Suspended
+}
+
+// Get returns a copy of a job
+func (m *Manager) Get(id string) (Job, bool) {
+	job, ok := m.jobs.Get(id)
+	if !ok {
+		return Job{}, false
+	}
+	job.Results = append([]ItemResult{}, job.Results...)
+	return job, true
+}
+
+// CleanExpired removes expired jobs
+func (m *Manager) CleanExpired() {
+	now := time.Now()
+	m.jobs.RemoveIf(func(job *Job) bool {
+		return job.Status != JobPending && job.Status != JobRunning && now.After(job.ExpiresAt)
+	})
+}
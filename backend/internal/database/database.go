@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/google/uuid"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -114,6 +115,44 @@ func (db *DB) InitSchema(entities ...interface{}) error {
 		}
 	}
 
+	if migrator.HasTable(&models.FluxResource{}) {
+		if err := db.migrateResourceIDs(); err != nil {
+			log.Printf("Warning: failed to migrate legacy resource IDs: %v", err)
+		}
+	}
+
 	log.Println("Database schema initialized successfully")
 	return nil
 }
+
+// migrateResourceIDs replaces legacy "clusterID/kind/namespace/name" resource keys with
+// surrogate UUIDs, updating the status transitions that reference them. Legacy IDs are
+// still resolved through the resource's unique index by the repository.
+func (db *DB) migrateResourceIDs() error {
+	var legacyIDs []string
+	if err := db.Model(&models.FluxResource{}).Where("id LIKE ?", "%/%").Pluck("id", &legacyIDs).Error; err != nil {
+		return err
+	}
+	if len(legacyIDs) == 0 {
+		return nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, legacyID := range legacyIDs {
+			newID := uuid.New().String()
+			if err := tx.Model(&models.FluxResource{}).Where("id = ?", legacyID).Update("id", newID).Error; err != nil {
+				return fmt.Errorf("failed to migrate resource %s: %w", legacyID, err)
+			}
+			if err := tx.Model(&models.ResourceStatusTransition{}).Where("resource_id = ?", legacyID).Update("resource_id", newID).Error; err != nil {
+				return fmt.Errorf("failed to migrate transitions of resource %s: %w", legacyID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Migrated %d resources to surrogate IDs", len(legacyIDs))
+	return nil
+}
@@ -0,0 +1,156 @@
+// Package demo seeds synthetic clusters, Flux resources and activity so the orchestrator
+// can be evaluated, and the frontend developed, without real clusters.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+)
+
+// Source marks clusters created by demo mode
+const Source = "demo"
+
+// demoUser is recorded as the author of seeded activities
+const demoUser = "demo"
+
+// clusterDef describes a demo cluster and the problems it simulates
+type clusterDef struct {
+	ID                    string
+	Name                  string
+	Description           string
+	Environment           string
+	FailingRelease        bool   // the redis HelmRelease is not ready
+	SuspendApps           bool   // the apps Kustomization is suspended
+	UnavailableController string // a Flux controller with no available replicas
+	FluxVersion           string // Flux distribution version, a key of fluxReleases
+}
+
+// clusters are the demo clusters, from healthy to degraded
+var clusters = []clusterDef{
+	{
+		ID:          "demo-production",
+		Name:        "demo-production",
+		Description: "Demo production cluster; every Flux resource is ready",
+		Environment: "production",
+		FluxVersion: "v2.4.0",
+	},
+	{
+		ID:             "demo-staging",
+		Name:           "demo-staging",
+		Description:    "Demo staging cluster with a failing HelmRelease",
+		Environment:    "staging",
+		FailingRelease: true,
+		FluxVersion:    "v2.4.0",
+	},
+	{
+		ID:                    "demo-development",
+		Name:                  "demo-development",
+		Description:           "Demo development cluster with suspended apps and an unavailable helm-controller",
+		Environment:           "development",
+		SuspendApps:           true,
+		UnavailableController: "helm-controller",
+		FluxVersion:           "v2.2.3",
+	},
+}
+
+// Seed registers the demo clusters with the Kubernetes client and stores them, their
+// resources and sample activity in the database. It is safe to run on every start:
+// existing demo clusters are re-registered and resynced, and activity is only added once.
+func Seed(ctx context.Context, db *database.DB, k8sClient *k8s.Client) error {
+	clusterRepo := repository.NewClusterRepository(db)
+	resourceRepo := repository.NewResourceRepository(db)
+
+	for _, def := range clusters {
+		k8sClient.AddFakeCluster(def.ID, fluxObjects(def), typedObjects(def))
+
+		if _, err := clusterRepo.Get(def.ID); repository.IsNotFound(err) {
+			cluster := &models.Cluster{
+				ID:          def.ID,
+				Name:        def.Name,
+				Description: def.Description,
+				Status:      "unknown",
+				Source:      Source,
+				ServerURL:   fmt.Sprintf("https://%s.demo.invalid", def.ID),
+			}
+			if err := clusterRepo.Create(cluster); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		status, err := k8sClient.CheckClusterHealth(def.ID)
+		if err != nil {
+			return fmt.Errorf("demo cluster %s: %w", def.ID, err)
+		}
+		updates := map[string]interface{}{"status": status}
+		if fluxHealth, err := k8sClient.CheckFluxInstallation(ctx, def.ID); err == nil {
+			updates["flux_status"] = fluxHealth.Status
+			updates["flux_message"] = fluxHealth.Message
+			updates["flux_version"] = fluxHealth.Version
+		}
+
+		resources, err := k8sClient.GetFluxResources(def.ID)
+		if err != nil {
+			return fmt.Errorf("demo cluster %s: %w", def.ID, err)
+		}
+		if err := resourceRepo.AssignIDs(def.ID, resources); err != nil {
+			return err
+		}
+		if err := history.RecordTransitions(db, def.ID, resources); err != nil {
+			return err
+		}
+		for i := range resources {
+			if err := resourceRepo.Save(&resources[i]); err != nil {
+				return err
+			}
+		}
+		if err := history.RecordClusterSnapshot(db, def.ID, resources); err != nil {
+			return err
+		}
+		updates["resource_count"] = len(resources)
+		if err := clusterRepo.Update(def.ID, updates); err != nil {
+			return err
+		}
+	}
+
+	return seedActivities(db)
+}
+
+// seedActivities records a sample audit trail the first time demo mode runs
+func seedActivities(db *database.DB) error {
+	var count int64
+	if err := db.Model(&models.Activity{}).Where("user_id = ?", demoUser).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count demo activities: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	activities := []models.Activity{
+		{Action: "create", ResourceType: "cluster", ResourceID: "demo-production", ResourceName: "demo-production", ClusterID: "demo-production", ClusterName: "demo-production", Message: "Cluster registered", CreatedAt: now.Add(-72 * time.Hour)},
+		{Action: "create", ResourceType: "cluster", ResourceID: "demo-staging", ResourceName: "demo-staging", ClusterID: "demo-staging", ClusterName: "demo-staging", Message: "Cluster registered", CreatedAt: now.Add(-71 * time.Hour)},
+		{Action: "create", ResourceType: "cluster", ResourceID: "demo-development", ResourceName: "demo-development", ClusterID: "demo-development", ClusterName: "demo-development", Message: "Cluster registered", CreatedAt: now.Add(-70 * time.Hour)},
+		{Action: "reconcile", ResourceType: "kustomization", ResourceID: "flux-system/apps", ResourceName: "apps", ClusterID: "demo-production", ClusterName: "demo-production", Message: "Reconciliation requested", CreatedAt: now.Add(-6 * time.Hour)},
+		{Action: "suspend", ResourceType: "kustomization", ResourceID: "flux-system/apps", ResourceName: "apps", ClusterID: "demo-development", ClusterName: "demo-development", Message: "Suspended while the helm-controller is investigated", CreatedAt: now.Add(-3 * time.Hour)},
+		{Action: "reconcile", ResourceType: "helmrelease", ResourceID: "data/redis", ResourceName: "redis", ClusterID: "demo-staging", ClusterName: "demo-staging", Status: "failed", Message: "context deadline exceeded", CreatedAt: now.Add(-45 * time.Minute)},
+	}
+	for i := range activities {
+		activities[i].UserID = demoUser
+		if activities[i].Status == "" {
+			activities[i].Status = "success"
+		}
+	}
+	if err := db.Create(&activities).Error; err != nil {
+		return fmt.Errorf("failed to seed demo activities: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+package demo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fluxObject builds an unstructured Flux resource with a Ready condition
+func fluxObject(apiVersion, kind, namespace, name string, ready bool, message string, spec map[string]interface{}) *unstructured.Unstructured {
+	now := time.Now().UTC()
+	status := "True"
+	reason := "ReconciliationSucceeded"
+	if !ready {
+		status = "False"
+		reason = "ReconciliationFailed"
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         namespace,
+			"creationTimestamp": now.Add(-72 * time.Hour).Format(time.RFC3339),
+		},
+		"spec": spec,
+		"status": map[string]interface{}{
+			"lastHandledReconcileAt": now.Add(-5 * time.Minute).Format(time.RFC3339),
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":               "Ready",
+					"status":             status,
+					"reason":             reason,
+					"message":            message,
+					"lastTransitionTime": now.Add(-5 * time.Minute).Format(time.RFC3339),
+				},
+			},
+		},
+	}}
+	return obj
+}
+
+// fluxObjects returns the Flux resources of a demo cluster
+func fluxObjects(cluster clusterDef) []runtime.Object {
+	gitURL := fmt.Sprintf("https://github.com/example/fleet-infra/clusters/%s", cluster.Environment)
+	objects := []runtime.Object{
+		fluxObject("source.toolkit.fluxcd.io/v1", "GitRepository", "flux-system", "flux-system", true,
+			"stored artifact for revision 'main@sha1:4f2c9e1'",
+			map[string]interface{}{"url": gitURL, "interval": "1m", "ref": map[string]interface{}{"branch": "main"}}),
+		fluxObject("source.toolkit.fluxcd.io/v1", "HelmRepository", "flux-system", "podinfo", true,
+			"stored artifact: revision 'sha256:8c1d2a'",
+			map[string]interface{}{"url": "https://stefanprodan.github.io/podinfo", "interval": "10m"}),
+		fluxObject("source.toolkit.fluxcd.io/v1", "HelmRepository", "flux-system", "bitnami", true,
+			"stored artifact: revision 'sha256:1b7e44'",
+			map[string]interface{}{"url": "https://charts.bitnami.com/bitnami", "interval": "30m"}),
+		fluxObject("source.toolkit.fluxcd.io/v1beta2", "OCIRepository", "flux-system", "podinfo-manifests", true,
+			"stored artifact for digest 'latest@sha256:3f1d5b'",
+			map[string]interface{}{"url": "oci://ghcr.io/stefanprodan/manifests/podinfo", "interval": "10m",
+				"ref": map[string]interface{}{"tag": "latest"}}),
+		fluxObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "flux-system", true,
+			"Applied revision: main@sha1:4f2c9e1",
+			map[string]interface{}{"path": "./clusters/" + cluster.Environment, "interval": "10m", "prune": true,
+				"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system"}}),
+		fluxObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "infrastructure", true,
+			"Applied revision: main@sha1:4f2c9e1",
+			map[string]interface{}{"path": "./infrastructure", "interval": "10m", "prune": true,
+				"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system"}}),
+		fluxObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "apps", true,
+			"Applied revision: main@sha1:4f2c9e1",
+			map[string]interface{}{"path": "./apps/" + cluster.Environment, "interval": "5m", "prune": true,
+				"suspend":   cluster.SuspendApps,
+				"dependsOn": []interface{}{map[string]interface{}{"name": "infrastructure"}},
+				"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system"}}),
+		fluxObject("helm.toolkit.fluxcd.io/v2", "HelmRelease", "apps", "podinfo", true,
+			"Helm upgrade succeeded for release apps/podinfo.v3 with chart podinfo@6.7.1",
+			map[string]interface{}{"interval": "5m", "chart": map[string]interface{}{"spec": map[string]interface{}{
+				"chart": "podinfo", "version": "6.7.1",
+				"sourceRef": map[string]interface{}{"kind": "HelmRepository", "name": "podinfo", "namespace": "flux-system"}}},
+				"dependsOn": []interface{}{map[string]interface{}{"name": "redis", "namespace": "data"}}}),
+		fluxObject("helm.toolkit.fluxcd.io/v2", "HelmRelease", "data", "redis", !cluster.FailingRelease,
+			redisMessage(cluster.FailingRelease),
+			map[string]interface{}{"interval": "10m", "chart": map[string]interface{}{"spec": map[string]interface{}{
+				"chart": "redis", "version": "20.6.2",
+				"sourceRef": map[string]interface{}{"kind": "HelmRepository", "name": "bitnami", "namespace": "flux-system"}}}}),
+	}
+
+	objects = append(objects, notificationObjects(cluster)...)
+
+	// Workloads are listed through the dynamic client by the resource tree
+	for _, obj := range workloadObjects(cluster) {
+		if u, err := toUnstructured(obj); err == nil {
+			objects = append(objects, u)
+		}
+	}
+	return objects
+}
+
+// notificationObjects returns the notification-controller resources of a demo cluster
+func notificationObjects(cluster clusterDef) []runtime.Object {
+	return []runtime.Object{
+		staticFluxObject("notification.toolkit.fluxcd.io/v1beta3", "Provider", "flux-system", "slack",
+			map[string]interface{}{"type": "slack", "channel": "#flux-" + cluster.Environment,
+				"secretRef": map[string]interface{}{"name": "slack-webhook"}}),
+		staticFluxObject("notification.toolkit.fluxcd.io/v1beta3", "Alert", "flux-system", "on-call",
+			map[string]interface{}{"eventSeverity": "error",
+				"providerRef": map[string]interface{}{"name": "slack"},
+				"eventSources": []interface{}{
+					map[string]interface{}{"kind": "Kustomization", "name": "*"},
+					map[string]interface{}{"kind": "HelmRelease", "name": "*", "namespace": "*"},
+				}}),
+		staticFluxObject("notification.toolkit.fluxcd.io/v1beta3", "Alert", "flux-system", "deployments",
+			map[string]interface{}{"eventSeverity": "info",
+				"providerRef": map[string]interface{}{"name": "slack"},
+				"eventSources": []interface{}{
+					map[string]interface{}{"kind": "HelmRelease", "name": "*", "namespace": "*"},
+				}}),
+		fluxObject("notification.toolkit.fluxcd.io/v1", "Receiver", "flux-system", "github-webhook", true,
+			"Receiver initialized for path: /hook/5d1c7a2f",
+			map[string]interface{}{"type": "github", "events": []interface{}{"ping", "push"},
+				"secretRef": map[string]interface{}{"name": "webhook-token"},
+				"resources": []interface{}{
+					map[string]interface{}{"kind": "GitRepository", "name": "flux-system"},
+				}}),
+	}
+}
+
+// staticFluxObject builds a Flux object that has no status, like v1beta3 Alerts and Providers
+func staticFluxObject(apiVersion, kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := fluxObject(apiVersion, kind, namespace, name, true, "", spec)
+	delete(obj.Object, "status")
+	return obj
+}
+
+// redisMessage returns the Ready message of the redis release
+func redisMessage(failing bool) string {
+	if failing {
+		return "Helm upgrade failed for release data/redis with chart redis@20.6.2: context deadline exceeded"
+	}
+	return "Helm install succeeded for release data/redis.v1 with chart redis@20.6.2"
+}
+
+// typedObjects returns the Flux controller deployments and workloads of a demo cluster.
+// The pod of the unavailable controller is crash looping.
+func typedObjects(cluster clusterDef) []runtime.Object {
+	var objects []runtime.Object
+	for _, name := range k8s.FluxControllers {
+		controller := deployment("flux-system", name, 1)
+		fluxManaged(controller, cluster.FluxVersion)
+		controllerPod := pod("flux-system", name+"-6c7b8d9f5-r4t7w", name)
+		controllerPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: name, Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		}}
+		if name == cluster.UnavailableController {
+			controller = deployment("flux-system", name, 0)
+			fluxManaged(controller, cluster.FluxVersion)
+			controllerPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:         name,
+				RestartCount: 12,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "CrashLoopBackOff",
+					Message: "back-off 5m0s restarting failed container=" + name,
+				}},
+			}}
+		}
+		objects = append(objects, controller, controllerPod)
+	}
+	objects = append(objects, quotaObjects(cluster)...)
+	objects = append(objects, workloadObjects(cluster)...)
+	return append(objects, triageEvents(cluster)...)
+}
+
+// quotaObjects returns the namespace quotas of a demo cluster. The data namespace is
+// nearly out of memory where the redis release fails, which explains the failed upgrade.
+func quotaObjects(cluster clusterDef) []runtime.Object {
+	usedMemory := "3Gi"
+	if cluster.FailingRelease {
+		usedMemory = "7800Mi"
+	}
+	return []runtime.Object{
+		resourceQuota("apps", "compute", corev1.ResourceList{
+			corev1.ResourceRequestsCPU:    resource.MustParse("4"),
+			corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+			corev1.ResourcePods:           resource.MustParse("20"),
+		}, corev1.ResourceList{
+			corev1.ResourceRequestsCPU:    resource.MustParse("500m"),
+			corev1.ResourceRequestsMemory: resource.MustParse("512Mi"),
+			corev1.ResourcePods:           resource.MustParse("2"),
+		}),
+		resourceQuota("data", "compute", corev1.ResourceList{
+			corev1.ResourceRequestsCPU:    resource.MustParse("2"),
+			corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+		}, corev1.ResourceList{
+			corev1.ResourceRequestsCPU:    resource.MustParse("1500m"),
+			corev1.ResourceRequestsMemory: resource.MustParse(usedMemory),
+		}),
+		&corev1.LimitRange{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "LimitRange"},
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "data"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Max:            corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				Default:        corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				DefaultRequest: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			}}},
+		},
+	}
+}
+
+// resourceQuota builds a ResourceQuota with its observed usage
+func resourceQuota(namespace, name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuota"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+// workloadObjects returns the application workloads deployed by the demo HelmReleases.
+// Where the redis release fails, its pod is crash looping after running out of memory.
+func workloadObjects(cluster clusterDef) []runtime.Object {
+	podinfo := deployment("apps", "podinfo", 2)
+	helmManaged(&podinfo.ObjectMeta, "apps", "podinfo")
+	redis := deployment("data", "redis-master", 1)
+	helmManaged(&redis.ObjectMeta, "data", "redis")
+
+	redisPod := pod("data", "redis-master-5f8d7c9b6-h4n2k", "redis-master")
+	redisPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: "redis-master", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}}
+	if cluster.FailingRelease {
+		redis.Status.ReadyReplicas = 0
+		redis.Status.AvailableReplicas = 0
+		redisPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:         "redis-master",
+			RestartCount: 7,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "CrashLoopBackOff",
+				Message: "back-off 5m0s restarting failed container=redis-master",
+			}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Reason:     "OOMKilled",
+				ExitCode:   137,
+				FinishedAt: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+			}},
+		}}
+	}
+
+	return []runtime.Object{
+		podinfo,
+		pod("apps", "podinfo-7d9c6b5f4-x2k8p", "podinfo"),
+		pod("apps", "podinfo-7d9c6b5f4-q9m3z", "podinfo"),
+		redis,
+		redisPod,
+	}
+}
+
+// fluxReleases maps the Flux versions the demo clusters run to their controller versions
+var fluxReleases = map[string]map[string]string{
+	"v2.4.0": {"source-controller": "v1.4.1", "kustomize-controller": "v1.4.0", "helm-controller": "v1.1.0", "notification-controller": "v1.4.0"},
+	"v2.2.3": {"source-controller": "v1.2.5", "kustomize-controller": "v1.2.2", "helm-controller": "v0.37.4", "notification-controller": "v1.2.4"},
+}
+
+// fluxManaged sets the labels and image flux install gives a controller deployment
+func fluxManaged(controller *appsv1.Deployment, version string) {
+	// The deployment shares its labels map with its selector, which must not change
+	controller.Labels = map[string]string{
+		"app":                       controller.Name,
+		"app.kubernetes.io/part-of": "flux",
+		"app.kubernetes.io/version": version,
+	}
+	controller.Spec.Template.Spec.Containers[0].Name = "manager"
+	controller.Spec.Template.Spec.Containers[0].Image = "ghcr.io/fluxcd/" + controller.Name + ":" + fluxReleases[version][controller.Name]
+}
+
+// helmManaged sets the labels helm-controller adds to the objects of a release
+func helmManaged(meta *metav1.ObjectMeta, releaseNamespace, releaseName string) {
+	// Copied, as the label map is shared with the selector
+	labels := map[string]string{}
+	for key, value := range meta.Labels {
+		labels[key] = value
+	}
+	meta.Labels = labels
+	meta.Labels["helm.toolkit.fluxcd.io/name"] = releaseName
+	meta.Labels["helm.toolkit.fluxcd.io/namespace"] = releaseNamespace
+}
+
+// triageEvents returns the release and pod events behind the failing redis release
+func triageEvents(cluster clusterDef) []runtime.Object {
+	if !cluster.FailingRelease {
+		return nil
+	}
+	lastSeen := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	return []runtime.Object{
+		&corev1.Event{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+			ObjectMeta: metav1.ObjectMeta{Name: "redis.upgradefailed", Namespace: "data"},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "HelmRelease", Namespace: "data", Name: "redis",
+			},
+			Source:        corev1.EventSource{Component: "helm-controller"},
+			Type:          corev1.EventTypeWarning,
+			Reason:        "UpgradeFailed",
+			Message:       redisMessage(true),
+			Count:         4,
+			LastTimestamp: lastSeen,
+		},
+		&corev1.Event{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+			ObjectMeta: metav1.ObjectMeta{Name: "redis-master-5f8d7c9b6-h4n2k.backoff", Namespace: "data"},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "Pod", Namespace: "data", Name: "redis-master-5f8d7c9b6-h4n2k",
+			},
+			Type:          corev1.EventTypeWarning,
+			Reason:        "BackOff",
+			Message:       "Back-off restarting failed container redis-master in pod redis-master-5f8d7c9b6-h4n2k",
+			Count:         31,
+			LastTimestamp: lastSeen,
+		},
+	}
+}
+
+// deployment builds a single-container Deployment with the given number of available replicas
+func deployment(namespace, name string, available int32) *appsv1.Deployment {
+	replicas := available
+	if replicas == 0 {
+		replicas = 1
+	}
+	labels := map[string]string{"app": name}
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: "ghcr.io/example/" + name + ":latest"}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: replicas, ReadyReplicas: available, AvailableReplicas: available},
+	}
+}
+
+// pod builds a running pod owned by the given app
+func pod(namespace, name, app string) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: app, Image: "ghcr.io/example/" + app + ":latest"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+// toUnstructured converts a typed object to its unstructured form for the dynamic client
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
@@ -0,0 +1,150 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+)
+
+// Deployment event types
+const (
+	DeploymentArtifact = "artifact" // a source fetched a new revision
+	DeploymentApplied  = "applied"  // a Kustomization applied a new revision
+	DeploymentChart    = "chart"    // a HelmRelease deployed a new chart version
+)
+
+// deploymentKinds are the kinds whose revisions are tracked. HelmRepositories are left out:
+// their revision is the digest of an index that changes with every chart published to it.
+var deploymentKinds = []string{"GitRepository", "OCIRepository", "Bucket", "HelmChart", "Kustomization", "HelmRelease"}
+
+// revisionStatus holds the status fields a deployed revision is read from
+type revisionStatus struct {
+	Status struct {
+		Artifact *struct {
+			Revision string `json:"revision"`
+		} `json:"artifact"`
+		LastAppliedRevision string `json:"lastAppliedRevision"`
+		History             []struct {
+			ChartName    string `json:"chartName"`
+			ChartVersion string `json:"chartVersion"`
+		} `json:"history"`
+	} `json:"status"`
+}
+
+// deployedRevision returns the type of revision a resource deploys and the revision its
+// stored object reports, or an empty revision if it has none yet
+func deployedRevision(res models.FluxResource) (string, string) {
+	var object revisionStatus
+	if res.Metadata == "" || json.Unmarshal([]byte(res.Metadata), &object) != nil {
+		return "", ""
+	}
+	status := object.Status
+
+	switch res.Kind {
+	case "Kustomization":
+		return DeploymentApplied, status.LastAppliedRevision
+	case "HelmRelease":
+		// helm.toolkit.fluxcd.io/v2 keeps the latest release first in status.history;
+		// v2beta1 reports the chart version as the last applied revision
+		if len(status.History) > 0 && status.History[0].ChartVersion != "" {
+			return DeploymentChart, status.History[0].ChartName + "@" + status.History[0].ChartVersion
+		}
+		return DeploymentChart, status.LastAppliedRevision
+	default:
+		if status.Artifact == nil {
+			return DeploymentArtifact, ""
+		}
+		return DeploymentArtifact, status.Artifact.Revision
+	}
+}
+
+// RecordDeployments stores revision changes between the stored and freshly synced
+// resources. Resources seen for the first time are not recorded, so adding a cluster does
+// not flood the timeline. It must be called before the synced resources are saved.
+func RecordDeployments(db *database.DB, clusterID string, resources []models.FluxResource) error {
+	tx := db.Select("id", "kind", "metadata").Where("cluster_id = ? AND kind IN ?", clusterID, deploymentKinds)
+	if len(resources) == 1 {
+		// Watch events store one resource at a time
+		tx = tx.Where("id = ?", resources[0].ID)
+	}
+	var existing []models.FluxResource
+	if err := tx.Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to load stored resources: %w", err)
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	previous := make(map[string]string, len(existing))
+	for _, res := range existing {
+		_, previous[res.ID] = deployedRevision(res)
+	}
+
+	now := time.Now()
+	var events []models.DeploymentEvent
+	for _, res := range resources {
+		oldRevision, known := previous[res.ID]
+		if !known {
+			continue
+		}
+		eventType, newRevision := deployedRevision(res)
+		if newRevision == "" || newRevision == oldRevision {
+			continue
+		}
+		events = append(events, models.DeploymentEvent{
+			ClusterID:   clusterID,
+			ResourceID:  res.ID,
+			Kind:        res.Kind,
+			Namespace:   res.Namespace,
+			Name:        res.Name,
+			Type:        eventType,
+			OldRevision: oldRevision,
+			NewRevision: newRevision,
+			RecordedAt:  now,
+		})
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	if err := db.Create(&events).Error; err != nil {
+		return fmt.Errorf("failed to record deployments: %w", err)
+	}
+	return nil
+}
+
+// TimelineQuery selects deployment events. Empty fields match everything; the zero Scope
+// sees every cluster.
+type TimelineQuery struct {
+	Scope     repository.Scope
+	ClusterID string
+	Kind      string
+	Namespace string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// Timeline returns the deployment events recorded within a query's window, newest first
+func Timeline(db *database.DB, query TimelineQuery) ([]models.DeploymentEvent, error) {
+	tx := query.Scope.Apply(db, db.Where("recorded_at >= ? AND recorded_at < ?", query.Since, query.Until), "cluster_id")
+	if query.ClusterID != "" {
+		tx = tx.Where("cluster_id = ?", query.ClusterID)
+	}
+	if query.Kind != "" {
+		tx = tx.Where("kind = ?", query.Kind)
+	}
+	if query.Namespace != "" {
+		tx = tx.Where("namespace = ?", query.Namespace)
+	}
+
+	events := []models.DeploymentEvent{}
+	if err := tx.Order("recorded_at DESC").Order("id DESC").Limit(query.Limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to query deployments: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,176 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// RecordClusterSnapshot stores the readiness of a cluster's Flux resources after a sync
+func RecordClusterSnapshot(db *database.DB, clusterID string, resources []models.FluxResource) error {
+	snapshot := models.ClusterStatusSnapshot{
+		ClusterID:  clusterID,
+		Total:      len(resources),
+		RecordedAt: time.Now(),
+	}
+
+	for _, res := range resources {
+		switch res.Status {
+		case "Ready":
+			snapshot.Ready++
+		case "NotReady":
+			snapshot.NotReady++
+		default:
+			snapshot.Unknown++
+		}
+	}
+
+	if err := db.Create(&snapshot).Error; err != nil {
+		return fmt.Errorf("failed to record status snapshot: %w", err)
+	}
+	return nil
+}
+
+// RecordTransitions stores status changes between the stored and freshly synced resources.
+// It must be called before the synced resources are saved.
+func RecordTransitions(db *database.DB, clusterID string, resources []models.FluxResource) error {
+	var existing []models.FluxResource
+	if err := db.Select("id", "status").Where("cluster_id = ?", clusterID).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to load stored resources: %w", err)
+	}
+
+	previous := make(map[string]string, len(existing))
+	for _, res := range existing {
+		previous[res.ID] = res.Status
+	}
+
+	now := time.Now()
+	var transitions []models.ResourceStatusTransition
+	for _, res := range resources {
+		oldStatus, known := previous[res.ID]
+		if known && oldStatus == res.Status {
+			continue
+		}
+		transitions = append(transitions, models.ResourceStatusTransition{
+			ClusterID:  clusterID,
+			ResourceID: res.ID,
+			Kind:       res.Kind,
+			Namespace:  res.Namespace,
+			Name:       res.Name,
+			OldStatus:  oldStatus,
+			NewStatus:  res.Status,
+			Message:    res.Message,
+			RecordedAt: now,
+		})
+	}
+
+	if len(transitions) == 0 {
+		return nil
+	}
+	if err := db.Create(&transitions).Error; err != nil {
+		return fmt.Errorf("failed to record status transitions: %w", err)
+	}
+	return nil
+}
+
+// Transitions returns the status transitions of a cluster, or of one of its namespaces,
+// recorded within a time window, newest first
+func Transitions(db *database.DB, clusterID, namespace string, since, until time.Time, limit int) ([]models.ResourceStatusTransition, error) {
+	tx := db.Where("cluster_id = ? AND recorded_at >= ? AND recorded_at < ?", clusterID, since, until)
+	if namespace != "" {
+		tx = tx.Where("namespace = ?", namespace)
+	}
+	transitions := []models.ResourceStatusTransition{}
+	if err := tx.Order("recorded_at DESC").Order("id DESC").Limit(limit).Find(&transitions).Error; err != nil {
+		return nil, fmt.Errorf("failed to query status transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+// Prune deletes history entries recorded before the cutoff
+func Prune(db *database.DB, cutoff time.Time) (int64, error) {
+	result := db.Where("recorded_at < ?", cutoff).Delete(&models.ClusterStatusSnapshot{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune status history: %w", result.Error)
+	}
+	pruned := result.RowsAffected
+
+	result = db.Where("recorded_at < ?", cutoff).Delete(&models.ResourceStatusTransition{})
+	if result.Error != nil {
+		return pruned, fmt.Errorf("failed to prune status transitions: %w", result.Error)
+	}
+	pruned += result.RowsAffected
+
+	result = db.Where("recorded_at < ?", cutoff).Delete(&models.DeploymentEvent{})
+	if result.Error != nil {
+		return pruned, fmt.Errorf("failed to prune deployment events: %w", result.Error)
+	}
+	return pruned + result.RowsAffected, nil
+}
+
+// TrendBucket aggregates the snapshots recorded within one time bucket
+type TrendBucket struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Samples      int       `json:"samples"`
+	Total        int       `json:"total"`
+	Ready        int       `json:"ready"`
+	NotReady     int       `json:"not_ready"`
+	Unknown      int       `json:"unknown"`
+	ReadyPercent *float64  `json:"ready_percent"`
+}
+
+// TrendRanges maps supported trend ranges to their window and bucket size
+var TrendRanges = map[string]struct {
+	Window time.Duration
+	Bucket time.Duration
+}{
+	"24h": {Window: 24 * time.Hour, Bucket: time.Hour},
+	"7d":  {Window: 7 * 24 * time.Hour, Bucket: 6 * time.Hour},
+	"30d": {Window: 30 * 24 * time.Hour, Bucket: 24 * time.Hour},
+}
+
+// ClusterTrend returns time-bucketed readiness counts for a cluster.
+// Each bucket reports the latest snapshot recorded within it.
+func ClusterTrend(db *database.DB, clusterID string, window, bucket time.Duration, now time.Time) ([]TrendBucket, error) {
+	end := now.Truncate(bucket).Add(bucket)
+	start := end.Add(-window)
+
+	var snapshots []models.ClusterStatusSnapshot
+	if err := db.Where("cluster_id = ? AND recorded_at >= ? AND recorded_at < ?", clusterID, start, end).
+		Order("recorded_at ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+
+	count := int(window / bucket)
+	buckets := make([]TrendBucket, count)
+	for i := range buckets {
+		buckets[i].Start = start.Add(time.Duration(i) * bucket)
+		buckets[i].End = buckets[i].Start.Add(bucket)
+	}
+
+	for _, snap := range snapshots {
+		idx := int(snap.RecordedAt.Sub(start) / bucket)
+		if idx < 0 || idx >= count {
+			continue
+		}
+		b := &buckets[idx]
+		b.Samples++
+		b.Total = snap.Total
+		b.Ready = snap.Ready
+		b.NotReady = snap.NotReady
+		b.Unknown = snap.Unknown
+	}
+
+	for i := range buckets {
+		if buckets[i].Samples > 0 && buckets[i].Total > 0 {
+			percent := float64(buckets[i].Ready) / float64(buckets[i].Total) * 100
+			buckets[i].ReadyPercent = &percent
+		}
+	}
+
+	return buckets, nil
+}
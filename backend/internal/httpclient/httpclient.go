@@ -0,0 +1,95 @@
+// Package httpclient builds the HTTP clients used for outbound calls
+// (webhooks, OAuth providers, Azure, container registries) so they share proxy configuration.
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Config controls how outbound requests are proxied.
+// NoProxy uses the NO_PROXY syntax: hostnames, domain suffixes, IPs, CIDRs and host:port pairs.
+type Config struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+var (
+	mu        sync.RWMutex
+	proxyFunc func(*url.URL) (*url.URL, error)
+)
+
+// ConfigFromEnv reads the standard HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables.
+// OUTBOUND_HTTP_PROXY and OUTBOUND_HTTPS_PROXY take precedence for the orchestrator only,
+// and OUTBOUND_NO_PROXY adds destinations that bypass the proxy.
+func ConfigFromEnv() Config {
+	env := httpproxy.FromEnvironment()
+	cfg := Config{
+		HTTPProxy:  env.HTTPProxy,
+		HTTPSProxy: env.HTTPSProxy,
+		NoProxy:    env.NoProxy,
+	}
+
+	if value := os.Getenv("OUTBOUND_HTTP_PROXY"); value != "" {
+		cfg.HTTPProxy = value
+	}
+	if value := os.Getenv("OUTBOUND_HTTPS_PROXY"); value != "" {
+		cfg.HTTPSProxy = value
+	}
+	if value := os.Getenv("OUTBOUND_NO_PROXY"); value != "" {
+		cfg.NoProxy = strings.Trim(cfg.NoProxy+","+value, ",")
+	}
+	return cfg
+}
+
+// Configure sets the proxy configuration used by clients created afterwards
+// and by existing transports from this package
+func Configure(cfg Config) {
+	fn := (&httpproxy.Config{
+		HTTPProxy:  cfg.HTTPProxy,
+		HTTPSProxy: cfg.HTTPSProxy,
+		NoProxy:    cfg.NoProxy,
+	}).ProxyFunc()
+
+	mu.Lock()
+	proxyFunc = fn
+	mu.Unlock()
+}
+
+// Proxy returns the proxy for a request, reading the environment on first use
+// when Configure has not been called
+func Proxy(req *http.Request) (*url.URL, error) {
+	mu.RLock()
+	fn := proxyFunc
+	mu.RUnlock()
+
+	if fn == nil {
+		Configure(ConfigFromEnv())
+		mu.RLock()
+		fn = proxyFunc
+		mu.RUnlock()
+	}
+	return fn(req.URL)
+}
+
+// NewTransport returns a transport with the default pooling and timeouts that honors the proxy configuration
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = Proxy
+	return transport
+}
+
+// New returns a client using NewTransport. A zero timeout means no client timeout.
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(),
+		Timeout:   timeout,
+	}
+}
@@ -0,0 +1,92 @@
+// Package jobs keeps background jobs in memory until they expire and limits how many of
+// them run at once. Bulk actions, log bundles and cluster syncs each keep their jobs in a
+// Store of their own job type.
+package jobs
+
+import "sync"
+
+// Store holds jobs of type J by ID. Jobs are changed in place under the store's lock and
+// handed out as copies.
+type Store[J any] struct {
+	slots chan struct{}
+
+	mu   sync.Mutex
+	jobs map[string]*J
+}
+
+// NewStore creates a store running at most concurrency jobs at once
+func NewStore[J any](concurrency int) *Store[J] {
+	return &Store[J]{
+		slots: make(chan struct{}, concurrency),
+		jobs:  make(map[string]*J),
+	}
+}
+
+// Add stores a job and returns a copy of it
+func (s *Store[J]) Add(id string, job *J) J {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id] = job
+	return *job
+}
+
+// AddUnless stores a job unless a stored job matches, in which case it returns a copy of
+// that job and false instead
+func (s *Store[J]) AddUnless(id string, job *J, match func(*J) bool) (J, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.jobs {
+		if match(existing) {
+			return *existing, false
+		}
+	}
+	s.jobs[id] = job
+	return *job, true
+}
+
+// Update applies fn to a job under the lock
+func (s *Store[J]) Update(id string, fn func(*J)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+// Get returns a copy of a job. Slices in the copy share their backing arrays with the
+// stored job, so jobs may only append to them.
+func (s *Store[J]) Get(id string) (J, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		var zero J
+		return zero, false
+	}
+	return *job, true
+}
+
+// RemoveIf removes the jobs expired reports true for and returns them
+func (s *Store[J]) RemoveIf(expired func(*J) bool) []J {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var removed []J
+	for id, job := range s.jobs {
+		if expired(job) {
+			removed = append(removed, *job)
+			delete(s.jobs, id)
+		}
+	}
+	return removed
+}
+
+// Acquire waits until fewer than the store's concurrency of jobs run. Every Acquire is
+// followed by a Release once the job is done.
+func (s *Store[J]) Acquire() {
+	s.slots <- struct{}{}
+}
+
+// Release frees the slot of a finished job
+func (s *Store[J]) Release() {
+	<-s.slots
+}
@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccessReviewRequest is an action on a cluster to check. Resource may carry its API group
+// as in deployments.apps when Group is empty; an empty namespace means all namespaces.
+type AccessReviewRequest struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// Validate checks that the request names a verb and a resource
+func (r AccessReviewRequest) Validate() error {
+	if r.Verb == "" {
+		return errors.New("verb is required")
+	}
+	if r.Resource == "" {
+		return errors.New("resource is required")
+	}
+	return nil
+}
+
+// normalized returns the request with a group given in Resource moved to Group
+func (r AccessReviewRequest) normalized() AccessReviewRequest {
+	if r.Group == "" {
+		if resource, group, ok := strings.Cut(r.Resource, "."); ok {
+			r.Resource, r.Group = resource, group
+		}
+	}
+	return r
+}
+
+// AccessReview is the cluster's answer to whether an action is allowed. User is the
+// impersonated user it was answered for; empty, it was answered for the kubeconfig's identity.
+type AccessReview struct {
+	AccessReviewRequest
+	Allowed         bool   `json:"allowed"`
+	Denied          bool   `json:"denied,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	EvaluationError string `json:"evaluation_error,omitempty"`
+	User            string `json:"user,omitempty"`
+}
+
+// ReviewAccess asks a cluster through a SelfSubjectAccessReview whether its credentials may
+// perform an action, or the user of ctx may when the cluster impersonates users
+func (c *Client) ReviewAccess(ctx context.Context, clusterID string, req AccessReviewRequest) (*AccessReview, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	req = req.normalized()
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:        req.Verb,
+				Group:       req.Group,
+				Resource:    req.Resource,
+				Subresource: req.Subresource,
+				Namespace:   req.Namespace,
+				Name:        req.Name,
+			},
+		},
+	}
+	result, err := typedClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review access: %w", err)
+	}
+
+	access := &AccessReview{
+		AccessReviewRequest: req,
+		Allowed:             result.Status.Allowed,
+		Denied:              result.Status.Denied,
+		Reason:              result.Status.Reason,
+		EvaluationError:     result.Status.EvaluationError,
+	}
+	if identity, ok := ImpersonationFromContext(ctx); ok && c.impersonates(clusterID) {
+		access.User = identity.User
+	}
+	return access, nil
+}
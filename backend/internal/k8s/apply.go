@@ -0,0 +1,223 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// applyFieldManager is the field manager manifests applied through the orchestrator own
+// their fields under
+const applyFieldManager = "flux-orchestrator"
+
+// maxApplyObjects caps the documents a single apply may contain
+const maxApplyObjects = 200
+
+// ApplyOptions controls how raw manifests are applied
+type ApplyOptions struct {
+	Namespace string // for namespaced objects that do not set one
+	DryRun    bool   // server-side dry run; nothing is persisted
+	Force     bool   // take ownership of fields other managers own
+}
+
+// AppliedObject is the outcome of applying one object
+type AppliedObject struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Action     string `json:"action"` // created, configured, unchanged, error
+	Error      string `json:"error,omitempty"`
+}
+
+// ApplyResult reports every object of an apply, in the order they were applied
+type ApplyResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Objects []AppliedObject `json:"objects"`
+	Summary map[string]int  `json:"summary"` // objects per action
+}
+
+// InvalidManifestError is returned when manifests cannot be parsed
+type InvalidManifestError struct {
+	Reason string
+}
+
+func (e *InvalidManifestError) Error() string {
+	return e.Reason
+}
+
+// UpdateConflictError is returned when an update would change fields another field manager
+// owns, such as the kustomize-controller for objects applied from Git
+type UpdateConflictError struct {
+	Reason string
+}
+
+func (e *UpdateConflictError) Error() string {
+	return e.Reason
+}
+
+// ApplyManifests server-side applies the objects of one or more YAML documents, like
+// kubectl apply --server-side. Namespaces and CustomResourceDefinitions are applied first
+// so objects depending on them can follow in the same request. An object that fails does
+// not stop the rest; its error is reported with it.
+func (c *Client) ApplyManifests(ctx context.Context, clusterID string, manifests []byte, opts ApplyOptions) (*ApplyResult, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	if _, ok := c.restConfig(clusterID); !ok {
+		return nil, fmt.Errorf("apply is not supported on cluster %s", clusterID)
+	}
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := decodeManifests("manifests", manifests, true)
+	if err != nil {
+		return nil, &InvalidManifestError{Reason: err.Error()}
+	}
+	if len(objects) == 0 {
+		return nil, &InvalidManifestError{Reason: "no Kubernetes objects found"}
+	}
+	if len(objects) > maxApplyObjects {
+		return nil, &InvalidManifestError{Reason: fmt.Sprintf("%d objects exceed the limit of %d per apply", len(objects), maxApplyObjects)}
+	}
+	for i, obj := range objects {
+		if obj.GetName() == "" {
+			return nil, &InvalidManifestError{Reason: fmt.Sprintf("document %d (%s) has no metadata.name", i+1, obj.GetKind())}
+		}
+	}
+	sort.SliceStable(objects, func(i, j int) bool { return applyPriority(objects[i]) < applyPriority(objects[j]) })
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(typedClient.Discovery()))
+	result := &ApplyResult{DryRun: opts.DryRun, Objects: []AppliedObject{}, Summary: map[string]int{}}
+	for _, obj := range objects {
+		applied := AppliedObject{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Action: "error"}
+		if err := applyObject(ctx, client, mapper, obj, opts, &applied); err != nil {
+			applied.Error = err.Error()
+		}
+		// Kinds of a CRD applied just now are only known after rediscovery
+		if obj.GetKind() == "CustomResourceDefinition" && applied.Action != "error" {
+			mapper.Reset()
+		}
+		result.Objects = append(result.Objects, applied)
+		result.Summary[applied.Action]++
+	}
+	return result, nil
+}
+
+// applyObject server-side applies one object and records whether it was created, changed
+// or left as it was
+func applyObject(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, opts ApplyOptions, applied *AppliedObject) error {
+	resource, err := resourceFor(client, mapper, obj.GetAPIVersion(), obj.GetKind(), namespaceOrDefault(obj.GetNamespace(), opts.Namespace))
+	if err != nil {
+		return err
+	}
+	if resource.namespaced {
+		obj.SetNamespace(namespaceOrDefault(obj.GetNamespace(), opts.Namespace))
+		if obj.GetNamespace() == "" {
+			return fmt.Errorf("namespace is not set")
+		}
+	} else {
+		obj.SetNamespace("")
+	}
+	applied.Namespace = obj.GetNamespace()
+	applyProvenance(ctx, obj)
+
+	live, err := resource.client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get live object: %w", err)
+	}
+
+	options := metav1.ApplyOptions{FieldManager: applyFieldManager, Force: opts.Force}
+	if opts.DryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	merged, err := resource.client.Apply(ctx, obj.GetName(), obj, options)
+	switch {
+	case apierrors.IsNotFound(err) && live == nil && opts.DryRun:
+		// Its namespace is created by the same request
+	case err != nil:
+		return fmt.Errorf("apply failed: %w", err)
+	}
+
+	switch {
+	case live == nil:
+		applied.Action = "created"
+	case diffYAML(withoutProvenance(merged)) == diffYAML(withoutProvenance(live)):
+		applied.Action = "unchanged"
+	default:
+		applied.Action = "configured"
+	}
+	return nil
+}
+
+// applyFields server-side applies top-level fields, such as spec, to an existing object as
+// the orchestrator's field manager. Fields left out stay as they are, and fields the
+// orchestrator set before and are now left out are removed. Without force, changing a
+// field another manager owns fails with an UpdateConflictError.
+func applyFields(ctx context.Context, client dynamic.ResourceInterface, live *unstructured.Unstructured, fields map[string]interface{}, force bool) error {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for key, value := range fields {
+		obj.Object[key] = value
+	}
+	obj.SetAPIVersion(live.GetAPIVersion())
+	obj.SetKind(live.GetKind())
+	obj.SetName(live.GetName())
+	obj.SetNamespace(live.GetNamespace())
+	applyProvenance(ctx, obj)
+
+	_, err := client.Apply(ctx, live.GetName(), obj, metav1.ApplyOptions{FieldManager: applyFieldManager, Force: force})
+	if apierrors.IsConflict(err) {
+		return &UpdateConflictError{Reason: err.Error()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply update: %w", err)
+	}
+	return nil
+}
+
+// withoutProvenance returns a copy of obj without the provenance annotations, which change
+// on every request
+func withoutProvenance(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	copied := obj.DeepCopy()
+	annotations := copied.GetAnnotations()
+	for _, key := range []string{AnnotationLastAction, AnnotationActor, AnnotationRequestID} {
+		delete(annotations, key)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	copied.SetAnnotations(annotations)
+	return copied
+}
+
+// applyPriority orders Namespaces, then CRDs, before every other object
+func applyPriority(obj *unstructured.Unstructured) int {
+	switch obj.GetKind() {
+	case "Namespace":
+		return 0
+	case "CustomResourceDefinition":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func namespaceOrDefault(namespace, fallback string) string {
+	if namespace != "" {
+		return namespace
+	}
+	return fallback
+}
@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SuspendableKinds are the kinds a cluster-wide suspend or resume acts on
+var SuspendableKinds = []string{"Kustomization", "HelmRelease"}
+
+// SuspendScope narrows a cluster-wide suspend or resume. Empty fields match everything.
+type SuspendScope struct {
+	Kinds         []string `json:"kinds"`
+	Namespace     string   `json:"namespace,omitempty"`
+	LabelSelector string   `json:"label_selector,omitempty"`
+}
+
+// SuspendTarget is a resource in a suspend scope and whether it is suspended now
+type SuspendTarget struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Suspended bool   `json:"suspended"`
+}
+
+// Validate defaults an empty kind list to every suspendable kind and checks the kinds and
+// the label selector
+func (s *SuspendScope) Validate() error {
+	if len(s.Kinds) == 0 {
+		s.Kinds = append([]string{}, SuspendableKinds...)
+	}
+	for _, kind := range s.Kinds {
+		if kind != "Kustomization" && kind != "HelmRelease" {
+			return fmt.Errorf("only Kustomizations and HelmReleases can be suspended in bulk, not %s", kind)
+		}
+	}
+	if _, err := labels.Parse(s.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label selector: %w", err)
+	}
+	return nil
+}
+
+// ListSuspendTargets lists the resources of the scope's kinds in its namespace, or every
+// namespace, that match its label selector
+func (c *Client) ListSuspendTargets(ctx context.Context, clusterID string, scope SuspendScope) ([]SuspendTarget, error) {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []SuspendTarget{}
+	for _, kind := range scope.Kinds {
+		gvr, err := c.getGVRForKind(clusterID, kind)
+		if err != nil {
+			return nil, err
+		}
+		list, err := client.Resource(gvr).Namespace(scope.Namespace).List(ctx, metav1.ListOptions{LabelSelector: scope.LabelSelector})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+		}
+		for _, item := range list.Items {
+			suspended, _, _ := unstructured.NestedBool(item.Object, "spec", "suspend")
+			targets = append(targets, SuspendTarget{Kind: kind, Namespace: item.GetNamespace(), Name: item.GetName(), Suspended: suspended})
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// FluxKindCapability reports whether a cluster serves a Flux kind and under which versions
+type FluxKindCapability struct {
+	Kind      string `json:"kind"`
+	Group     string `json:"group"`
+	Installed bool   `json:"installed"`
+	// Version is the one the orchestrator reads and writes the kind at; empty when the kind
+	// is not installed
+	Version  string   `json:"version,omitempty"`
+	Versions []string `json:"versions"` // every served version carrying the kind
+}
+
+// ClusterCapabilities lists the Flux kinds a cluster does and does not serve, so callers can
+// leave out actions on kinds whose CRDs are missing
+type ClusterCapabilities struct {
+	Kinds []FluxKindCapability `json:"kinds"`
+}
+
+// AssumedCapabilities returns the capabilities of a cluster serving every Flux kind the
+// client works with at its fallback version
+func AssumedCapabilities() *ClusterCapabilities {
+	capabilities := &ClusterCapabilities{Kinds: []FluxKindCapability{}}
+	for _, k := range fluxKinds {
+		capabilities.Kinds = append(capabilities.Kinds, FluxKindCapability{
+			Kind:      k.Kind,
+			Group:     k.Group,
+			Installed: true,
+			Version:   k.Fallback,
+			Versions:  []string{k.Fallback},
+		})
+	}
+	return capabilities
+}
+
+// GetCapabilities discovers which Flux kinds a cluster serves. A kind is installed when any
+// version of its group carries its resource; the version used for it is picked the way
+// resolveFluxGVR picks it and is cached for later requests.
+func (c *Client) GetCapabilities(clusterID string) (*ClusterCapabilities, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	discovery := typedClient.Discovery()
+
+	groups, err := discovery.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+	served := make(map[string][]string) // group to its versions, preferred first
+	for _, group := range groups.Groups {
+		versions := []string{group.PreferredVersion.Version}
+		for _, version := range group.Versions {
+			if version.Version != group.PreferredVersion.Version {
+				versions = append(versions, version.Version)
+			}
+		}
+		served[group.Name] = versions
+	}
+
+	// Resources per group version, shared by the kinds of a group
+	resources := make(map[string]map[string]bool)
+	carries := func(groupVersion, resource string) (bool, error) {
+		if names, ok := resources[groupVersion]; ok {
+			return names[resource], nil
+		}
+		list, err := discovery.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to discover %s: %w", groupVersion, err)
+		}
+		names := make(map[string]bool)
+		if list != nil {
+			for _, r := range list.APIResources {
+				names[r.Name] = true
+			}
+		}
+		resources[groupVersion] = names
+		return names[resource], nil
+	}
+
+	capabilities := &ClusterCapabilities{Kinds: []FluxKindCapability{}}
+	for _, k := range fluxKinds {
+		capability := FluxKindCapability{Kind: k.Kind, Group: k.Group, Versions: []string{}}
+		for _, version := range served[k.Group] {
+			ok, err := carries(k.Group+"/"+version, k.Resource)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				capability.Versions = append(capability.Versions, version)
+			}
+		}
+		capability.Installed = len(capability.Versions) > 0
+		if capability.Installed {
+			capability.Version = pickVersion(k, served[k.Group][0], capability.Versions)
+			c.gvrs.set(clusterID, k.Kind, k.gvr(capability.Version))
+		}
+		capabilities.Kinds = append(capabilities.Kinds, capability)
+	}
+	return capabilities, nil
+}
+
+// pickVersion returns the version a kind is used at out of the served versions carrying it:
+// the group's preferred version, else the newest version the client knows, else the first
+// the cluster serves
+func pickVersion(k fluxKind, preferred string, carrying []string) string {
+	for _, candidate := range append([]string{preferred}, k.Versions...) {
+		for _, version := range carrying {
+			if version == candidate {
+				return version
+			}
+		}
+	}
+	return carrying[0]
+}
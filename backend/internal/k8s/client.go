@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
@@ -22,10 +24,21 @@ import (
 
 // Client manages Kubernetes clients for multiple clusters
 type Client struct {
+	mu            sync.RWMutex // guards clients, typedClients, configs, inCluster, limits and impersonate
 	clients       map[string]dynamic.Interface
-	typedClients  map[string]*kubernetes.Clientset
+	typedClients  map[string]kubernetes.Interface
 	configs       map[string]*rest.Config
+	inCluster     map[string]bool // clusters added with AddInClusterConfig
+	limits        map[string]ClientLimits // per-cluster rate limits, see SetClientLimits
+	impersonate   map[string]bool         // clusters impersonating users, see SetImpersonation
+	health        connHealth      // connection failures per cluster, see Reconnect
+	source        ClusterSource   // registered clusters and their kubeconfigs, see SetClusterSource
 	timeout       time.Duration
+	gvrs          gvrCache // Flux API versions discovered per cluster
+	watches       watcher  // live Flux resource watches, see WatchFluxResources
+	registries    registryStore // stored registry credentials, see SetRegistryCredential
+	trees         treeCache     // resource trees built per cluster, see GetResourceTree
+	treeTTL       time.Duration
 }
 
 // NewClient creates a new multi-cluster Kubernetes client
@@ -37,12 +50,23 @@ func NewClient() *Client {
 			timeout = time.Duration(val) * time.Second
 		}
 	}
+
+	treeTTL := defaultTreeCacheTTL
+	if ttlStr := os.Getenv("RESOURCE_TREE_CACHE_TTL_SECONDS"); ttlStr != "" {
+		if val, err := strconv.Atoi(ttlStr); err == nil && val >= 0 {
+			treeTTL = time.Duration(val) * time.Second
+		}
+	}
 	
 	return &Client{
 		clients:      make(map[string]dynamic.Interface),
-		typedClients: make(map[string]*kubernetes.Clientset),
+		typedClients: make(map[string]kubernetes.Interface),
 		configs:      make(map[string]*rest.Config),
+		inCluster:    make(map[string]bool),
+		limits:       make(map[string]ClientLimits),
+		impersonate:  make(map[string]bool),
 		timeout:      timeout,
+		treeTTL:      treeTTL,
 	}
 }
 
@@ -52,24 +76,8 @@ func (c *Client) AddCluster(clusterID, kubeconfig string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse kubeconfig: %w", err)
 	}
-	
-	// Set timeouts on the config
-	config.Timeout = c.timeout
-
-	client, err := dynamic.NewForConfig(config)
-	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %w", err)
-	}
-
-	typedClient, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return fmt.Errorf("failed to create typed client: %w", err)
-	}
 
-	c.clients[clusterID] = client
-	c.typedClients[clusterID] = typedClient
-	c.configs[clusterID] = config
-	return nil
+	return c.buildClients(clusterID, config, false)
 }
 
 // AddInClusterConfig adds a cluster client using in-cluster configuration
@@ -78,29 +86,84 @@ func (c *Client) AddInClusterConfig(clusterID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get in-cluster config: %w", err)
 	}
-	
-	// Set timeouts on the config
-	config.Timeout = c.timeout
 
-	client, err := dynamic.NewForConfig(config)
-	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %w", err)
-	}
-
-	typedClient, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return fmt.Errorf("failed to create typed client: %w", err)
-	}
+	return c.buildClients(clusterID, config, true)
+}
 
+// setClients registers the clients of a cluster, replacing any it had, and restarts its watch
+func (c *Client) setClients(clusterID string, client dynamic.Interface, typedClient kubernetes.Interface, config *rest.Config, inCluster bool) {
+	c.mu.Lock()
 	c.clients[clusterID] = client
 	c.typedClients[clusterID] = typedClient
-	c.configs[clusterID] = config
-	return nil
+	if config != nil {
+		c.configs[clusterID] = config
+	} else {
+		delete(c.configs, clusterID)
+	}
+	c.inCluster[clusterID] = inCluster
+	c.mu.Unlock()
+
+	c.health.reset(clusterID)
+	c.gvrs.forget(clusterID)
+	c.trees.forget(clusterID)
+	c.startWatch(clusterID)
+}
+
+// RemoveCluster drops the clients for a cluster; unknown IDs are ignored
+func (c *Client) RemoveCluster(clusterID string) {
+	c.mu.Lock()
+	delete(c.clients, clusterID)
+	delete(c.typedClients, clusterID)
+	delete(c.configs, clusterID)
+	delete(c.inCluster, clusterID)
+	delete(c.limits, clusterID)
+	delete(c.impersonate, clusterID)
+	c.mu.Unlock()
+
+	c.health.forget(clusterID)
+	c.gvrs.forget(clusterID)
+	c.trees.forget(clusterID)
+	c.stopWatch(clusterID)
+}
+
+// dynamicClient returns the dynamic client of a cluster
+func (c *Client) dynamicClient(clusterID string) (dynamic.Interface, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	client, ok := c.clients[clusterID]
+	return client, ok
+}
+
+// typedClient returns the typed client of a cluster
+func (c *Client) typedClient(clusterID string) (kubernetes.Interface, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	client, ok := c.typedClients[clusterID]
+	return client, ok
+}
+
+// restConfig returns the REST config of a cluster; fake clusters have none
+func (c *Client) restConfig(clusterID string) (*rest.Config, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	config, ok := c.configs[clusterID]
+	return config, ok
+}
+
+// clusterIDs returns the IDs of the clusters with clients
+func (c *Client) clusterIDs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ids := make([]string, 0, len(c.clients))
+	for id := range c.clients {
+		ids = append(ids, id)
+	}
+	return ids
 }
 
 // GetClient returns the Kubernetes client for a cluster
 func (c *Client) GetClient(clusterID string) (dynamic.Interface, error) {
-	client, ok := c.clients[clusterID]
+	client, ok := c.dynamicClient(clusterID)
 	if !ok {
 		return nil, fmt.Errorf("cluster %s not found", clusterID)
 	}
@@ -114,7 +177,7 @@ func (c *Client) CheckClusterHealth(clusterID string) (string, error) {
 		return "unknown", err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout(clusterID))
 	defer cancel()
 
 	// Try to list namespaces as a health check
@@ -125,6 +188,7 @@ func (c *Client) CheckClusterHealth(clusterID string) (string, error) {
 	}
 
 	_, err = client.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1})
+	c.observeConnection(clusterID, err)
 	if err != nil {
 		return "unhealthy", err
 	}
@@ -142,54 +206,19 @@ func (c *Client) GetFluxResources(clusterID string) ([]models.FluxResource, erro
 	ctx := context.Background()
 	resources := []models.FluxResource{}
 
-	// Define Flux CRDs to query
-	fluxGVRs := []struct {
-		gvr  schema.GroupVersionResource
-		kind string
-	}{
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "kustomize.toolkit.fluxcd.io",
-				Version:  "v1",
-				Resource: "kustomizations",
-			},
-			kind: "Kustomization",
-		},
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "helm.toolkit.fluxcd.io",
-				Version:  "v2",
-				Resource: "helmreleases",
-			},
-			kind: "HelmRelease",
-		},
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "source.toolkit.fluxcd.io",
-				Version:  "v1",
-				Resource: "gitrepositories",
-			},
-			kind: "GitRepository",
-		},
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "source.toolkit.fluxcd.io",
-				Version:  "v1",
-				Resource: "helmrepositories",
-			},
-			kind: "HelmRepository",
-		},
-	}
-
-	for _, item := range fluxGVRs {
-		list, err := client.Resource(item.gvr).Namespace("").List(ctx, metav1.ListOptions{})
+	for _, kind := range fluxKinds {
+		gvr, err := c.resolveFluxGVR(clusterID, kind.Kind)
+		if err != nil {
+			continue
+		}
+		list, err := client.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{})
 		if err != nil {
 			// If CRD doesn't exist, skip it
 			continue
 		}
 
 		for _, obj := range list.Items {
-			resource := c.parseFluxResource(clusterID, item.kind, &obj)
+			resource := c.parseFluxResource(clusterID, kind.Kind, &obj)
 			resources = append(resources, resource)
 		}
 	}
@@ -227,6 +256,11 @@ func (c *Client) parseFluxResource(clusterID, kind string, obj *unstructured.Uns
 		}
 	}
 
+	// Static objects such as v1beta3 Alerts and Providers are never given a Ready condition
+	if status == "Unknown" && !reportsReadiness(obj) {
+		status = "Ready"
+	}
+
 	// Extract last reconcile time
 	lastReconcileStr, found, _ := unstructured.NestedString(obj.Object, "status", "lastHandledReconcileAt")
 	if found {
@@ -237,7 +271,6 @@ func (c *Client) parseFluxResource(clusterID, kind string, obj *unstructured.Uns
 	metadata, _ := json.Marshal(obj.Object)
 
 	return models.FluxResource{
-		ID:            fmt.Sprintf("%s/%s/%s/%s", clusterID, kind, obj.GetNamespace(), obj.GetName()),
 		ClusterID:     clusterID,
 		Kind:          kind,
 		Name:          obj.GetName(),
@@ -258,7 +291,7 @@ func (c *Client) ReconcileResource(ctx context.Context, clusterID, kind, namespa
 		return err
 	}
 
-	gvr, err := c.getGVRForKind(kind)
+	gvr, err := c.getGVRForKind(clusterID, kind)
 	if err != nil {
 		return err
 	}
@@ -274,8 +307,14 @@ func (c *Client) ReconcileResource(ctx context.Context, clusterID, kind, namespa
 	if annotations == nil {
 		annotations = make(map[string]string)
 	}
-	annotations["reconcile.fluxcd.io/requestedAt"] = time.Now().Format(time.RFC3339)
+	// Nanoseconds keep back-to-back requests distinct, so a wait never matches an earlier one
+	requestedAt := time.Now().Format(time.RFC3339Nano)
+	annotations["reconcile.fluxcd.io/requestedAt"] = requestedAt
+	if kind == "HelmRelease" && ForceFromContext(ctx) {
+		annotations[helmForceAnnotation] = requestedAt
+	}
 	resource.SetAnnotations(annotations)
+	applyProvenance(ctx, resource)
 
 	// Update the resource
 	_, err = client.Resource(gvr).Namespace(namespace).Update(ctx, resource, metav1.UpdateOptions{})
@@ -286,36 +325,9 @@ func (c *Client) ReconcileResource(ctx context.Context, clusterID, kind, namespa
 	return nil
 }
 
-// getGVRForKind returns the GroupVersionResource for a Flux kind
-func (c *Client) getGVRForKind(kind string) (schema.GroupVersionResource, error) {
-	switch kind {
-	case "Kustomization":
-		return schema.GroupVersionResource{
-			Group:    "kustomize.toolkit.fluxcd.io",
-			Version:  "v1",
-			Resource: "kustomizations",
-		}, nil
-	case "HelmRelease":
-		return schema.GroupVersionResource{
-			Group:    "helm.toolkit.fluxcd.io",
-			Version:  "v2",
-			Resource: "helmreleases",
-		}, nil
-	case "GitRepository":
-		return schema.GroupVersionResource{
-			Group:    "source.toolkit.fluxcd.io",
-			Version:  "v1",
-			Resource: "gitrepositories",
-		}, nil
-	case "HelmRepository":
-		return schema.GroupVersionResource{
-			Group:    "source.toolkit.fluxcd.io",
-			Version:  "v1",
-			Resource: "helmrepositories",
-		}, nil
-	default:
-		return schema.GroupVersionResource{}, fmt.Errorf("unknown kind: %s", kind)
-	}
+// getGVRForKind returns the GroupVersionResource a cluster serves a Flux kind under
+func (c *Client) getGVRForKind(clusterID, kind string) (schema.GroupVersionResource, error) {
+	return c.resolveFluxGVR(clusterID, kind)
 }
 
 // SuspendResource suspends reconciliation for a Flux resource
@@ -335,7 +347,7 @@ func (c *Client) setSuspended(ctx context.Context, clusterID, kind, namespace, n
 		return err
 	}
 
-	gvr, err := c.getGVRForKind(kind)
+	gvr, err := c.getGVRForKind(clusterID, kind)
 	if err != nil {
 		return err
 	}
@@ -350,6 +362,7 @@ func (c *Client) setSuspended(ctx context.Context, clusterID, kind, namespace, n
 	if err := unstructured.SetNestedField(resource.Object, suspended, "spec", "suspend"); err != nil {
 		return fmt.Errorf("failed to set suspend field: %w", err)
 	}
+	applyProvenance(ctx, resource)
 
 	// Update the resource
 	_, err = client.Resource(gvr).Namespace(namespace).Update(ctx, resource, metav1.UpdateOptions{})
@@ -360,51 +373,30 @@ func (c *Client) setSuspended(ctx context.Context, clusterID, kind, namespace, n
 	return nil
 }
 
-// UpdateFluxResource updates spec fields of a Flux resource
-func (c *Client) UpdateFluxResource(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}) error {
+// UpdateFluxResource server-side applies spec fields of a Flux resource. Without force,
+// fields owned by another field manager are not taken over; see applyFields.
+func (c *Client) UpdateFluxResource(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error {
 	client, err := c.GetClient(clusterID)
 	if err != nil {
 		return err
 	}
 
-	gvr, err := c.getGVRForKind(kind)
+	gvr, err := c.getGVRForKind(clusterID, kind)
 	if err != nil {
 		return err
 	}
 
-	// Get the resource
+	// Apply would create a resource that does not exist
 	resource, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get resource: %w", err)
 	}
 
-	// Apply patch to spec
-	if specPatch, ok := patch["spec"].(map[string]interface{}); ok {
-		currentSpec, found, err := unstructured.NestedMap(resource.Object, "spec")
-		if err != nil {
-			return fmt.Errorf("failed to get spec: %w", err)
-		}
-		if !found {
-			currentSpec = make(map[string]interface{})
-		}
-
-		// Merge patch into current spec
-		for key, value := range specPatch {
-			currentSpec[key] = value
-		}
-
-		if err := unstructured.SetNestedMap(resource.Object, currentSpec, "spec"); err != nil {
-			return fmt.Errorf("failed to set spec: %w", err)
-		}
+	fields := map[string]interface{}{}
+	if spec, ok := patch["spec"].(map[string]interface{}); ok {
+		fields["spec"] = spec
 	}
-
-	// Update the resource
-	_, err = client.Resource(gvr).Namespace(namespace).Update(ctx, resource, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update resource: %w", err)
-	}
-
-	return nil
+	return applyFields(ctx, client.Resource(gvr).Namespace(namespace), resource, fields, force)
 }
 
 // GetResourcesCreatedByFlux gets all resources created by a specific Flux resource
@@ -414,7 +406,7 @@ func (c *Client) GetResourcesCreatedByFlux(ctx context.Context, clusterID, kind,
 		return nil, err
 	}
 
-	gvr, err := c.getGVRForKind(kind)
+	gvr, err := c.getGVRForKind(clusterID, kind)
 	if err != nil {
 		return nil, err
 	}
@@ -425,13 +417,18 @@ func (c *Client) GetResourcesCreatedByFlux(ctx context.Context, clusterID, kind,
 		return nil, fmt.Errorf("failed to get flux resource: %w", err)
 	}
 
+	return inventoryEntries(fluxResource), nil
+}
+
+// inventoryEntries returns the objects in the status inventory of a Kustomization or
+// HelmRelease, with their ID, version and the Group, Kind, Namespace and Name parsed from it
+func inventoryEntries(fluxResource *unstructured.Unstructured) []map[string]interface{} {
 	// Get the inventory from status
 	inventory, found, err := unstructured.NestedSlice(fluxResource.Object, "status", "inventory", "entries")
 	if err != nil || !found {
-		return []map[string]interface{}{}, nil
+		return []map[string]interface{}{}
 	}
 
-	// Fetch details for each resource in inventory
 	resources := []map[string]interface{}{}
 	for _, entry := range inventory {
 		entryMap, ok := entry.(map[string]interface{})
@@ -468,7 +465,7 @@ func (c *Client) GetResourcesCreatedByFlux(ctx context.Context, clusterID, kind,
 		resources = append(resources, resourceInfo)
 	}
 
-	return resources, nil
+	return resources
 }
 
 // splitInventoryID splits a Flux inventory ID into components
@@ -507,53 +504,36 @@ func (c *Client) GetFluxStats(clusterID string) (map[string]interface{}, error)
 		"helmReleases":      map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
 		"gitRepositories":   map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
 		"helmRepositories":  map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
-	}
-
-	fluxGVRs := []struct {
-		gvr      schema.GroupVersionResource
-		statsKey string
-	}{
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "kustomize.toolkit.fluxcd.io",
-				Version:  "v1",
-				Resource: "kustomizations",
-			},
-			statsKey: "kustomizations",
-		},
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "helm.toolkit.fluxcd.io",
-				Version:  "v2",
-				Resource: "helmreleases",
-			},
-			statsKey: "helmReleases",
-		},
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "source.toolkit.fluxcd.io",
-				Version:  "v1",
-				Resource: "gitrepositories",
-			},
-			statsKey: "gitRepositories",
-		},
-		{
-			gvr: schema.GroupVersionResource{
-				Group:    "source.toolkit.fluxcd.io",
-				Version:  "v1",
-				Resource: "helmrepositories",
-			},
-			statsKey: "helmRepositories",
-		},
-	}
-
-	for _, item := range fluxGVRs {
-		list, err := client.Resource(item.gvr).Namespace("").List(ctx, metav1.ListOptions{})
+		"ociRepositories":   map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
+		"buckets":           map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
+		"alerts":            map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
+		"providers":         map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
+		"receivers":         map[string]int{"total": 0, "ready": 0, "notReady": 0, "suspended": 0},
+	}
+
+	statsKeys := map[string]string{
+		"Kustomization":  "kustomizations",
+		"HelmRelease":    "helmReleases",
+		"GitRepository":  "gitRepositories",
+		"HelmRepository": "helmRepositories",
+		"OCIRepository":  "ociRepositories",
+		"Bucket":         "buckets",
+		"Alert":          "alerts",
+		"Provider":       "providers",
+		"Receiver":       "receivers",
+	}
+
+	for _, kind := range fluxKinds {
+		gvr, err := c.resolveFluxGVR(clusterID, kind.Kind)
+		if err != nil {
+			continue
+		}
+		list, err := client.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{})
 		if err != nil {
 			continue
 		}
 
-		resourceStats := stats[item.statsKey].(map[string]int)
+		resourceStats := stats[statsKeys[kind.Kind]].(map[string]int)
 		resourceStats["total"] = len(list.Items)
 
 		for _, obj := range list.Items {
@@ -564,6 +544,12 @@ func (c *Client) GetFluxStats(clusterID string) (map[string]interface{}, error)
 				continue
 			}
 
+			// Static objects have no Ready condition and count as ready once accepted
+			if !reportsReadiness(&obj) {
+				resourceStats["ready"]++
+				continue
+			}
+
 			// Check Ready condition
 			conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
 			if err == nil && found && len(conditions) > 0 {
@@ -607,30 +593,40 @@ type ResourceNode struct {
 	CreatedAt   string         `json:"created_at"`
 	Children    []ResourceNode `json:"children,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	// Usage is the CPU and memory use of a pod, or the total of the pods below a resource,
+	// when the cluster runs metrics-server
+	Usage       *ResourceUsage `json:"usage,omitempty"`
 }
 
-// GetResourceTree builds a hierarchical tree of all Kubernetes resources in a cluster
-func (c *Client) GetResourceTree(ctx context.Context, clusterID string) ([]ResourceNode, error) {
+// buildResourceTree builds a hierarchical tree of all Kubernetes resources in a cluster.
+// Relationships come from the owner references and Flux inventories of the listed objects,
+// so building takes one List per kind however large the cluster is.
+// A filter is applied to every list by the API server; resources matching it whose parent
+// does not become roots of their own, and kinds rejecting its field selector are left out.
+func (c *Client) buildResourceTree(ctx context.Context, clusterID string, filter ListFilter) ([]ResourceNode, error) {
 	client, err := c.GetClient(clusterID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get all resource types to query
-	resourceTypes := []struct {
+	type resourceType struct {
 		gvr       schema.GroupVersionResource
 		kind      string
 		namespaced bool
-	}{
-		// Flux resources
-		{schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}, "Kustomization", true},
-		{schema.GroupVersionResource{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}, "HelmRelease", true},
-		{schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"}, "GitRepository", true},
-		{schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "helmrepositories"}, "HelmRepository", true},
-		{schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "buckets"}, "Bucket", true},
-		{schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "ocirepositories"}, "OCIRepository", true},
-		
-		// Core Kubernetes resources
+	}
+
+	// Flux resources, at the versions this cluster serves
+	var resourceTypes []resourceType
+	for _, kind := range []string{"Kustomization", "HelmRelease", "GitRepository", "HelmRepository", "Bucket", "OCIRepository"} {
+		gvr, err := c.getGVRForKind(clusterID, kind)
+		if err != nil {
+			continue
+		}
+		resourceTypes = append(resourceTypes, resourceType{gvr, kind, true})
+	}
+
+	// Core Kubernetes resources
+	resourceTypes = append(resourceTypes, []resourceType{
 		{schema.GroupVersionResource{Group: "", Version: "v1", Resource: "namespaces"}, "Namespace", false},
 		{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, "Deployment", true},
 		{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, "ReplicaSet", true},
@@ -643,21 +639,27 @@ func (c *Client) GetResourceTree(ctx context.Context, clusterID string) ([]Resou
 		{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, "Ingress", true},
 		{schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, "Job", true},
 		{schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, "CronJob", true},
-	}
+	}...)
 
 	allResources := make(map[string]*ResourceNode)
+	owners := make(map[string][]metav1.OwnerReference)
+	inventories := make(map[string][]map[string]interface{})
 	var fluxResources []string
-	var namespaces []string
 
 	// Fetch all resources
 	for _, rt := range resourceTypes {
-		var list *unstructured.UnstructuredList
+		var items []unstructured.Unstructured
 		var err error
 
-		if rt.namespaced {
-			list, err = client.Resource(rt.gvr).List(ctx, metav1.ListOptions{})
+		if rt.kind == "Secret" {
+			// Only Secret metadata is ever fetched
+			items, err = c.listSecretMetadata(ctx, clusterID, filter.listOptions())
 		} else {
-			list, err = client.Resource(rt.gvr).List(ctx, metav1.ListOptions{})
+			var list *unstructured.UnstructuredList
+			list, err = client.Resource(rt.gvr).List(ctx, filter.listOptions())
+			if list != nil {
+				items = list.Items
+			}
 		}
 
 		if err != nil {
@@ -665,9 +667,10 @@ func (c *Client) GetResourceTree(ctx context.Context, clusterID string) ([]Resou
 			continue
 		}
 
-		for _, obj := range list.Items {
+		for _, obj := range items {
 			node := c.parseResourceNode(&obj, rt.kind)
 			allResources[node.ID] = &node
+			owners[node.ID] = obj.GetOwnerReferences()
 
 			// Track Flux resources separately (these are top-level)
 			if rt.kind == "Kustomization" || rt.kind == "HelmRelease" || 
@@ -675,16 +678,29 @@ func (c *Client) GetResourceTree(ctx context.Context, clusterID string) ([]Resou
 			   rt.kind == "Bucket" || rt.kind == "OCIRepository" {
 				fluxResources = append(fluxResources, node.ID)
 			}
-			
-			// Track namespaces separately
-			if rt.kind == "Namespace" {
-				namespaces = append(namespaces, node.ID)
+
+			// The inventory is read from the listed object rather than fetched again
+			if rt.kind == "Kustomization" || rt.kind == "HelmRelease" {
+				inventories[node.ID] = inventoryEntries(&obj)
 			}
 		}
 	}
 
-	// Build parent-child relationships for workload resources
-	for _, res := range allResources {
+	ids := make([]string, 0, len(allResources))
+	for id := range allResources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// Relationships are collected as IDs first and the nodes copied into the tree last, so
+	// every copy carries its complete subtree
+	children := make(map[string][]string)
+	placeholders := make(map[string]ResourceNode)
+	attached := make(map[string]bool)
+
+	// Workload resources under their owners, as captured when they were listed
+	for _, id := range ids {
+		res := allResources[id]
 		// Skip Flux resources and Namespaces from this loop
 		if res.Kind == "Kustomization" || res.Kind == "HelmRelease" || 
 		   res.Kind == "GitRepository" || res.Kind == "HelmRepository" ||
@@ -692,106 +708,93 @@ func (c *Client) GetResourceTree(ctx context.Context, clusterID string) ([]Resou
 			continue
 		}
 
-		// Find this resource's object to get owner references
-		for _, rt := range resourceTypes {
-			if rt.kind != res.Kind {
-				continue
-			}
-
-			var obj *unstructured.Unstructured
-			var err error
-
-			if rt.namespaced {
-				obj, err = client.Resource(rt.gvr).Namespace(res.Namespace).Get(ctx, res.Name, metav1.GetOptions{})
-			} else {
-				obj, err = client.Resource(rt.gvr).Get(ctx, res.Name, metav1.GetOptions{})
+		for _, owner := range owners[id] {
+			parentID := fmt.Sprintf("%s/%s/%s", res.Namespace, string(owner.Kind), owner.Name)
+			if _, exists := allResources[parentID]; exists {
+				children[parentID] = append(children[parentID], id)
+				attached[id] = true
 			}
+		}
+	}
 
-			if err != nil {
+	// Flux-managed resources under their Kustomization/HelmRelease
+	for _, id := range ids {
+		for _, managedRes := range inventories[id] {
+			version, _ := managedRes["version"].(string)
+			kind, _ := managedRes["Kind"].(string)
+			namespace, _ := managedRes["Namespace"].(string)
+			name, _ := managedRes["Name"].(string)
+			if kind == "" || name == "" {
 				continue
 			}
 
-			owners := obj.GetOwnerReferences()
-			for _, owner := range owners {
-				parentID := fmt.Sprintf("%s/%s/%s", res.Namespace, string(owner.Kind), owner.Name)
-				if parent, exists := allResources[parentID]; exists {
-					parent.Children = append(parent.Children, *res)
+			// Create resource ID matching our format
+			managedID := fmt.Sprintf("%s/%s/%s", namespace, kind, name)
+			if _, exists := allResources[managedID]; exists {
+				children[id] = append(children[id], managedID)
+				attached[managedID] = true
+			} else if filter.IsZero() {
+				// Resource not in our list, create a simple node for it
+				placeholders[managedID] = ResourceNode{
+					ID:        managedID,
+					Kind:      kind,
+					Name:      name,
+					Namespace: namespace,
+					Status:    "Unknown",
+					Health:    "Unknown",
+					CreatedAt: "",
+					Children:  []ResourceNode{},
+					Metadata: map[string]interface{}{
+						"version": version,
+						"source":  "flux-inventory",
+					},
 				}
+				children[id] = append(children[id], managedID)
 			}
-			break
 		}
 	}
 
-	// Add Flux-managed resources as children of their parent Kustomization/HelmRelease
-	for _, res := range allResources {
-		if res.Kind == "Kustomization" || res.Kind == "HelmRelease" {
-			// Get managed resources from inventory
-			managedResources, err := c.GetResourcesCreatedByFlux(ctx, clusterID, res.Kind, res.Namespace, res.Name)
-			if err == nil && len(managedResources) > 0 {
-				for _, managedRes := range managedResources {
-					// Parse the resource ID (format: kind_namespace_name_version)
-					id, _ := managedRes["id"].(string)
-					version, _ := managedRes["version"].(string)
-					
-					if id != "" {
-						// Try to find this resource in our allResources map
-						// The ID format from inventory is "kind_namespace_name"
-						parts := []string{}
-						for _, part := range []string{"Group", "Version", "Kind", "Namespace", "Name"} {
-							if val, ok := managedRes[part].(string); ok && val != "" {
-								parts = append(parts, val)
-							}
-						}
-						
-						kind, _ := managedRes["Kind"].(string)
-						namespace, _ := managedRes["Namespace"].(string)
-						name, _ := managedRes["Name"].(string)
-						
-						if kind != "" && name != "" {
-							// Create resource ID matching our format
-							var managedID string
-							if namespace != "" {
-								managedID = fmt.Sprintf("%s/%s/%s", namespace, kind, name)
-							} else {
-								managedID = fmt.Sprintf("/%s/%s", kind, name)
-							}
-							
-							// If we found this resource, add it as a child
-							if managedNode, exists := allResources[managedID]; exists {
-								res.Children = append(res.Children, *managedNode)
-							} else {
-								// Resource not in our list, create a simple node for it
-								simpleNode := ResourceNode{
-									ID:        managedID,
-									Kind:      kind,
-									Name:      name,
-									Namespace: namespace,
-									Status:    "Unknown",
-									Health:    "Unknown",
-									CreatedAt: "",
-									Children:  []ResourceNode{},
-									Metadata: map[string]interface{}{
-										"version": version,
-										"source":  "flux-inventory",
-									},
-								}
-								res.Children = append(res.Children, simpleNode)
-							}
-						}
-					}
-				}
+	// build copies a node with its subtree; a Kustomization listing one of its ancestors in
+	// its inventory is not descended into again
+	path := make(map[string]bool)
+	var build func(id string) ResourceNode
+	build = func(id string) ResourceNode {
+		node, exists := allResources[id]
+		if !exists {
+			return placeholders[id]
+		}
+		out := *node
+		path[id] = true
+		for _, childID := range children[id] {
+			if !path[childID] {
+				out.Children = append(out.Children, build(childID))
 			}
 		}
+		delete(path, id)
+		return out
 	}
 
 	// Build tree from Flux resources (these are our root nodes)
 	var tree []ResourceNode
 	for _, fluxID := range fluxResources {
-		if fluxNode, exists := allResources[fluxID]; exists {
-			tree = append(tree, *fluxNode)
+		tree = append(tree, build(fluxID))
+		attached[fluxID] = true
+	}
+
+	// A filter may match resources without their Flux parent
+	if !filter.IsZero() {
+		for _, id := range ids {
+			if !attached[id] && allResources[id].Kind != "Namespace" {
+				tree = append(tree, build(id))
+			}
 		}
 	}
 
+	// Usage is best effort; clusters without metrics-server show health alone
+	if podUsage := c.listPodUsage(ctx, client, filter); podUsage != nil {
+		annotateUsage(tree, podUsage)
+	}
+
 	return tree, nil
 }
 
@@ -991,7 +994,7 @@ func (c *Client) GetResourceByKind(ctx context.Context, clusterID, kind, namespa
 		return nil, schema.GroupVersionResource{}, err
 	}
 
-	gvr, err := c.getGVRForGenericKind(kind)
+	gvr, err := c.getGVRForGenericKind(clusterID, kind)
 	if err != nil {
 		return nil, schema.GroupVersionResource{}, err
 	}
@@ -1011,7 +1014,7 @@ func (c *Client) GetResourceByKind(ctx context.Context, clusterID, kind, namespa
 }
 
 // getGVRForGenericKind returns GVR for common Kubernetes resources
-func (c *Client) getGVRForGenericKind(kind string) (schema.GroupVersionResource, error) {
+func (c *Client) getGVRForGenericKind(clusterID, kind string) (schema.GroupVersionResource, error) {
 	switch kind {
 	// Apps
 	case "Deployment":
@@ -1041,17 +1044,12 @@ func (c *Client) getGVRForGenericKind(kind string) (schema.GroupVersionResource,
 		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, nil
 	case "CronJob":
 		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, nil
-	// Flux
-	case "Kustomization":
-		return c.getGVRForKind(kind)
-	case "HelmRelease":
-		return c.getGVRForKind(kind)
-	case "GitRepository":
-		return c.getGVRForKind(kind)
-	case "HelmRepository":
-		return c.getGVRForKind(kind)
+	// HelmCharts are created by the helm-controller and not listed, so they skip discovery
+	case "HelmChart":
+		return schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "helmcharts"}, nil
+	// Flux kinds are resolved through discovery
 	default:
-		return schema.GroupVersionResource{}, fmt.Errorf("unknown kind: %s", kind)
+		return c.getGVRForKind(clusterID, kind)
 	}
 }
 
@@ -1066,6 +1064,7 @@ func (c *Client) ScaleResource(ctx context.Context, clusterID, kind, namespace,
 	if err := unstructured.SetNestedField(resource.Object, int64(replicas), "spec", "replicas"); err != nil {
 		return fmt.Errorf("failed to set replicas: %w", err)
 	}
+	applyProvenance(ctx, resource)
 
 	client, _ := c.GetClient(clusterID)
 	_, err = client.Resource(gvr).Namespace(namespace).Update(ctx, resource, metav1.UpdateOptions{})
@@ -1090,6 +1089,7 @@ func (c *Client) RestartResource(ctx context.Context, clusterID, kind, namespace
 	}
 	annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
 	resource.SetAnnotations(annotations)
+	applyProvenance(ctx, resource)
 
 	client, _ := c.GetClient(clusterID)
 	_, err = client.Resource(gvr).Namespace(namespace).Update(ctx, resource, metav1.UpdateOptions{})
@@ -1116,45 +1116,34 @@ func (c *Client) DeletePod(ctx context.Context, clusterID, namespace, name strin
 	return nil
 }
 
-// UpdateResourceSpec updates a resource's spec with a patch
-func (c *Client) UpdateResourceSpec(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}) error {
+// UpdateResourceSpec server-side applies a patch's spec fields to a resource. For Secrets,
+// which have no spec, the patch's data and stringData are applied instead. Without force,
+// fields owned by another field manager are not taken over; see applyFields.
+func (c *Client) UpdateResourceSpec(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error {
 	resource, gvr, err := c.GetResourceByKind(ctx, clusterID, kind, namespace, name)
 	if err != nil {
 		return err
 	}
 
-	// Apply patch to spec
-	if specPatch, ok := patch["spec"].(map[string]interface{}); ok {
-		currentSpec, found, err := unstructured.NestedMap(resource.Object, "spec")
-		if err != nil {
-			return fmt.Errorf("failed to get spec: %w", err)
-		}
-		if !found {
-			currentSpec = make(map[string]interface{})
-		}
-
-		// Deep merge patch into current spec
-		for key, value := range specPatch {
-			currentSpec[key] = value
-		}
-
-		if err := unstructured.SetNestedMap(resource.Object, currentSpec, "spec"); err != nil {
-			return fmt.Errorf("failed to set spec: %w", err)
+	fields := map[string]interface{}{}
+	if spec, ok := patch["spec"].(map[string]interface{}); ok {
+		fields["spec"] = spec
+	}
+	if kind == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			if values, ok := patch[field].(map[string]interface{}); ok {
+				fields[field] = values
+			}
 		}
 	}
 
 	client, _ := c.GetClient(clusterID)
-	_, err = client.Resource(gvr).Namespace(namespace).Update(ctx, resource, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update resource: %w", err)
-	}
-
-	return nil
+	return applyFields(ctx, client.Resource(gvr).Namespace(namespace), resource, fields, force)
 }
 
 // GetPodLogs retrieves logs from a pod
 func (c *Client) GetPodLogs(ctx context.Context, clusterID, namespace, podName, containerName string, tailLines int64, follow bool) (string, error) {
-typedClient, ok := c.typedClients[clusterID]
+typedClient, ok := c.typedClient(clusterID)
 if !ok {
 return "", fmt.Errorf("cluster %s not found", clusterID)
 }
@@ -1186,7 +1175,7 @@ return string(buf[:n]), nil
 
 // GetPodContainers gets the list of containers in a pod
 func (c *Client) GetPodContainers(ctx context.Context, clusterID, namespace, podName string) ([]string, error) {
-typedClient, ok := c.typedClients[clusterID]
+typedClient, ok := c.typedClient(clusterID)
 if !ok {
 return nil, fmt.Errorf("cluster %s not found", clusterID)
 }
@@ -1208,12 +1197,12 @@ return containers, nil
 }
 // GetResourceManifest gets the full manifest of a resource
 func (c *Client) GetResourceManifest(ctx context.Context, clusterID, kind, namespace, name string) (map[string]interface{}, error) {
-	client, ok := c.clients[clusterID]
+	client, ok := c.dynamicClient(clusterID)
 	if !ok {
 		return nil, fmt.Errorf("cluster %s not found", clusterID)
 	}
 
-	gvr, err := c.getGVRForKind(kind)
+	gvr, err := c.getGVRForKind(clusterID, kind)
 	if err != nil {
 		return nil, err
 	}
@@ -1287,14 +1276,12 @@ func (c *Client) GetAggregatedLogs(ctx context.Context, filters map[string]inter
 
 	// If no cluster IDs specified, use all
 	if len(clusterIDs) == 0 {
-		for id := range c.typedClients {
-			clusterIDs = append(clusterIDs, id)
-		}
+		clusterIDs = c.clusterIDs()
 	}
 
 	// Get logs from each cluster
 	for _, clusterID := range clusterIDs {
-		typedClient, ok := c.typedClients[clusterID]
+		typedClient, ok := c.typedClient(clusterID)
 		if !ok {
 			continue
 		}
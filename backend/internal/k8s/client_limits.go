@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// maxClientTimeout bounds the per-cluster request timeout
+const maxClientTimeout = 10 * time.Minute
+
+// ClientLimits are the client-side rate limits and request timeout of a cluster's clients.
+// Zero values keep the defaults: client-go's 5 QPS with a burst of 10, and
+// K8S_REQUEST_TIMEOUT_SECONDS. Large clusters often need more to sync without throttling.
+type ClientLimits struct {
+	QPS     float32
+	Burst   int
+	Timeout time.Duration
+}
+
+// Validate checks that the limits are usable
+func (l ClientLimits) Validate() error {
+	if l.QPS < 0 {
+		return fmt.Errorf("QPS must not be negative")
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("burst must not be negative")
+	}
+	if l.Burst > 0 && l.QPS > 0 && float32(l.Burst) < l.QPS {
+		return fmt.Errorf("burst must be at least the QPS")
+	}
+	if l.Timeout < 0 || l.Timeout > maxClientTimeout {
+		return fmt.Errorf("timeout must be between 0 and %s", maxClientTimeout)
+	}
+	return nil
+}
+
+// SetClientLimits sets the rate limits and timeout of a cluster's clients. They apply to
+// clients built afterwards, so they are set before AddCluster; a cluster that is already
+// connected has its clients rebuilt with them.
+func (c *Client) SetClientLimits(clusterID string, limits ClientLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.limits == nil {
+		c.limits = make(map[string]ClientLimits)
+	}
+	changed := c.limits[clusterID] != limits
+	c.limits[clusterID] = limits
+	config, connected := c.configs[clusterID]
+	inCluster := c.inCluster[clusterID]
+	c.mu.Unlock()
+
+	if !changed || !connected {
+		return nil
+	}
+	return c.buildClients(clusterID, rest.CopyConfig(config), inCluster)
+}
+
+// requestTimeout returns the request timeout of a cluster
+func (c *Client) requestTimeout(clusterID string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if timeout := c.limits[clusterID].Timeout; timeout > 0 {
+		return timeout
+	}
+	return c.timeout
+}
+
+// buildClients builds and registers the clients of a cluster from config, with the
+// cluster's limits applied
+func (c *Client) buildClients(clusterID string, config *rest.Config, inCluster bool) error {
+	c.mu.RLock()
+	limits := c.limits[clusterID]
+	c.mu.RUnlock()
+
+	config.Timeout = c.timeout
+	if limits.Timeout > 0 {
+		config.Timeout = limits.Timeout
+	}
+	config.QPS = limits.QPS
+	config.Burst = limits.Burst
+	// Set rather than wrapped, so configs rebuilt from a stored one are not wrapped twice;
+	// configs loaded from kubeconfigs carry no wrapper of their own
+	config.WrapTransport = c.impersonationWrapper(clusterID)
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	typedClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create typed client: %w", err)
+	}
+
+	c.setClients(clusterID, client, typedClient, config, inCluster)
+	return nil
+}
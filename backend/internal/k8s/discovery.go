@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// gvrCacheTTL is how long a resolved version is trusted before discovery is asked again,
+// so a Flux upgrade is picked up without re-adding the cluster
+const gvrCacheTTL = 10 * time.Minute
+
+// fluxKind is a Flux kind and the API versions it has been served under, newest first.
+// Fallback is used when discovery is unavailable or the CRD is not installed.
+type fluxKind struct {
+	Kind     string
+	Group    string
+	Resource string
+	Versions []string
+	Fallback string
+}
+
+// fluxKinds lists every Flux kind the client works with
+var fluxKinds = []fluxKind{
+	{Kind: "Kustomization", Group: "kustomize.toolkit.fluxcd.io", Resource: "kustomizations", Versions: []string{"v1", "v1beta2", "v1beta1"}, Fallback: "v1"},
+	{Kind: "HelmRelease", Group: "helm.toolkit.fluxcd.io", Resource: "helmreleases", Versions: []string{"v2", "v2beta2", "v2beta1"}, Fallback: "v2"},
+	{Kind: "GitRepository", Group: "source.toolkit.fluxcd.io", Resource: "gitrepositories", Versions: []string{"v1", "v1beta2", "v1beta1"}, Fallback: "v1"},
+	{Kind: "HelmRepository", Group: "source.toolkit.fluxcd.io", Resource: "helmrepositories", Versions: []string{"v1", "v1beta2", "v1beta1"}, Fallback: "v1"},
+	{Kind: "OCIRepository", Group: "source.toolkit.fluxcd.io", Resource: "ocirepositories", Versions: []string{"v1", "v1beta2"}, Fallback: "v1beta2"},
+	{Kind: "Bucket", Group: "source.toolkit.fluxcd.io", Resource: "buckets", Versions: []string{"v1", "v1beta2", "v1beta1"}, Fallback: "v1beta2"},
+	{Kind: "Alert", Group: "notification.toolkit.fluxcd.io", Resource: "alerts", Versions: []string{"v1beta3", "v1beta2", "v1beta1"}, Fallback: "v1beta3"},
+	{Kind: "Provider", Group: "notification.toolkit.fluxcd.io", Resource: "providers", Versions: []string{"v1beta3", "v1beta2", "v1beta1"}, Fallback: "v1beta3"},
+	{Kind: "Receiver", Group: "notification.toolkit.fluxcd.io", Resource: "receivers", Versions: []string{"v1", "v1beta2", "v1beta1"}, Fallback: "v1"},
+}
+
+// lookupFluxKind returns the definition of a Flux kind
+func lookupFluxKind(kind string) (fluxKind, bool) {
+	for _, k := range fluxKinds {
+		if k.Kind == kind {
+			return k, true
+		}
+	}
+	return fluxKind{}, false
+}
+
+func (k fluxKind) gvr(version string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: k.Group, Version: version, Resource: k.Resource}
+}
+
+type resolvedGVR struct {
+	gvr        schema.GroupVersionResource
+	resolvedAt time.Time
+}
+
+// gvrCache holds the versions discovered per cluster, keyed by cluster ID and kind
+type gvrCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string]resolvedGVR
+}
+
+func (g *gvrCache) get(clusterID, kind string) (schema.GroupVersionResource, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	entry, ok := g.entries[clusterID][kind]
+	if !ok || time.Since(entry.resolvedAt) > gvrCacheTTL {
+		return schema.GroupVersionResource{}, false
+	}
+	return entry.gvr, true
+}
+
+func (g *gvrCache) set(clusterID, kind string, gvr schema.GroupVersionResource) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[string]map[string]resolvedGVR)
+	}
+	if g.entries[clusterID] == nil {
+		g.entries[clusterID] = make(map[string]resolvedGVR)
+	}
+	g.entries[clusterID][kind] = resolvedGVR{gvr: gvr, resolvedAt: time.Now()}
+}
+
+// forget drops everything resolved for a cluster
+func (g *gvrCache) forget(clusterID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.entries, clusterID)
+}
+
+// resolveFluxGVR returns the GroupVersionResource a cluster serves a Flux kind under.
+// The group's preferred version is used when it serves the kind, otherwise the newest
+// version that does. When the CRD is missing the fallback version is cached; when
+// discovery itself fails the fallback is returned without caching.
+func (c *Client) resolveFluxGVR(clusterID, kind string) (schema.GroupVersionResource, error) {
+	k, ok := lookupFluxKind(kind)
+	if !ok {
+		return schema.GroupVersionResource{}, fmt.Errorf("unknown kind: %s", kind)
+	}
+	if gvr, ok := c.gvrs.get(clusterID, kind); ok {
+		return gvr, nil
+	}
+
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return k.gvr(k.Fallback), nil
+	}
+	discovery := typedClient.Discovery()
+
+	groups, err := discovery.ServerGroups()
+	if err != nil {
+		return k.gvr(k.Fallback), nil
+	}
+
+	served := make(map[string]bool)
+	var candidates []string
+	for _, group := range groups.Groups {
+		if group.Name != k.Group {
+			continue
+		}
+		for _, version := range group.Versions {
+			served[version.Version] = true
+		}
+		candidates = append(candidates, group.PreferredVersion.Version)
+	}
+	candidates = append(candidates, k.Versions...)
+
+	tried := make(map[string]bool)
+	for _, version := range candidates {
+		if !served[version] || tried[version] {
+			continue
+		}
+		tried[version] = true
+
+		resources, err := discovery.ServerResourcesForGroupVersion(k.Group + "/" + version)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return k.gvr(k.Fallback), nil
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == k.Resource {
+				gvr := k.gvr(version)
+				c.gvrs.set(clusterID, kind, gvr)
+				return gvr, nil
+			}
+		}
+	}
+
+	gvr := k.gvr(k.Fallback)
+	c.gvrs.set(clusterID, kind, gvr)
+	return gvr, nil
+}
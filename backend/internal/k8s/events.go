@@ -0,0 +1,163 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// MaxResourceEvents caps the events returned for one resource
+const MaxResourceEvents = 500
+
+// EventObject identifies the object an event is about
+type EventObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// ResourceEvent is a Kubernetes event about a resource or an object it drives
+type ResourceEvent struct {
+	TriageEvent
+	FirstSeen time.Time   `json:"first_seen,omitempty"`
+	Source    string      `json:"source,omitempty"` // reporting controller
+	Object    EventObject `json:"object"`
+}
+
+// GetResourceEvents returns the events of a Flux resource or workload, newest first.
+// HelmReleases include the events of their chart, where fetch failures are reported, and
+// Deployments those of their ReplicaSets, where pod creation failures are reported.
+// Events outlive their objects, so the resource does not have to exist.
+func (c *Client) GetResourceEvents(ctx context.Context, clusterID, kind, namespace, name string, limit int) ([]ResourceEvent, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	related := []EventObject{{Kind: kind, Namespace: namespace, Name: name}}
+	if kind == "HelmRelease" {
+		if chart, ok := c.helmReleaseChart(ctx, clusterID, namespace, name); ok {
+			related = append(related, chart)
+		}
+	}
+	matches := func(ref corev1.ObjectReference) bool {
+		for _, obj := range related {
+			if ref.Kind == obj.Kind && ref.Name == obj.Name && ref.Namespace == obj.Namespace {
+				return true
+			}
+		}
+		return kind == "Deployment" && ref.Kind == "ReplicaSet" && ref.Namespace == namespace && strings.HasPrefix(ref.Name, name+"-")
+	}
+
+	events := []ResourceEvent{}
+	listed := make(map[string]bool)
+	for _, obj := range related {
+		if listed[obj.Namespace] {
+			continue
+		}
+		listed[obj.Namespace] = true
+
+		list, err := typedClient.CoreV1().Events(obj.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+		for _, event := range list.Items {
+			if matches(event.InvolvedObject) {
+				events = append(events, resourceEvent(event))
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].LastSeen.After(events[j].LastSeen) })
+	if limit <= 0 || limit > MaxResourceEvents {
+		limit = MaxResourceEvents
+	}
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// ListClusterEvents returns the events of a cluster, or of one namespace, that occurred
+// within a time window, oldest first. A repeated event is included when any occurrence
+// falls in the window. The API server keeps events for an hour by default, so older
+// windows come back empty.
+func (c *Client) ListClusterEvents(ctx context.Context, clusterID, namespace string, since, until time.Time, limit int) ([]ResourceEvent, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	list, err := typedClient.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	events := []ResourceEvent{}
+	for _, item := range list.Items {
+		event := resourceEvent(item)
+		firstSeen := event.FirstSeen
+		if firstSeen.IsZero() {
+			firstSeen = event.LastSeen
+		}
+		if event.LastSeen.Before(since) || !firstSeen.Before(until) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].LastSeen.Before(events[j].LastSeen) })
+	if limit > 0 && len(events) > limit {
+		// Keep the latest; they are closest to what is being investigated
+		events = events[len(events)-limit:]
+	}
+	return events, nil
+}
+
+// helmReleaseChart returns the object a HelmRelease's chart events are recorded on: the
+// chartRef target, or the HelmChart the helm-controller creates next to the source
+func (c *Client) helmReleaseChart(ctx context.Context, clusterID, namespace, name string) (EventObject, bool) {
+	release, _, err := c.GetResourceByKind(ctx, clusterID, "HelmRelease", namespace, name)
+	if err != nil {
+		return EventObject{}, false
+	}
+
+	if refKind, _, _ := unstructured.NestedString(release.Object, "spec", "chartRef", "kind"); refKind != "" {
+		refName, _, _ := unstructured.NestedString(release.Object, "spec", "chartRef", "name")
+		refNamespace, _, _ := unstructured.NestedString(release.Object, "spec", "chartRef", "namespace")
+		if refNamespace == "" {
+			refNamespace = namespace
+		}
+		return EventObject{Kind: refKind, Namespace: refNamespace, Name: refName}, true
+	}
+
+	sourceNamespace, _, _ := unstructured.NestedString(release.Object, "spec", "chart", "spec", "sourceRef", "namespace")
+	if sourceNamespace == "" {
+		sourceNamespace = namespace
+	}
+	return EventObject{Kind: "HelmChart", Namespace: sourceNamespace, Name: namespace + "-" + name}, true
+}
+
+// resourceEvent converts an event, preferring the events.k8s.io reporting fields
+func resourceEvent(event corev1.Event) ResourceEvent {
+	source := event.ReportingController
+	if source == "" {
+		source = event.Source.Component
+	}
+	return ResourceEvent{
+		TriageEvent: triageEvent(event),
+		FirstSeen:   event.FirstTimestamp.Time,
+		Source:      source,
+		Object: EventObject{
+			Kind:      event.InvolvedObject.Kind,
+			Namespace: event.InvolvedObject.Namespace,
+			Name:      event.InvolvedObject.Name,
+		},
+	}
+}
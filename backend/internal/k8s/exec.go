@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// TerminalSize is the size of an exec session's terminal in characters
+type TerminalSize struct {
+	Width  uint16
+	Height uint16
+}
+
+// ExecOptions describes an exec session in a container. Stdin, Stdout and Stderr may be
+// nil; with TTY set Stderr is merged into Stdout by the container runtime.
+type ExecOptions struct {
+	Container string
+	Command   []string
+	TTY       bool
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+	Resize    <-chan TerminalSize // terminal size changes, only used with TTY
+}
+
+// ExecPod runs a command in a container and streams its input and output until it exits
+// or ctx is cancelled. A command exiting non-zero is not an error; its status is returned.
+func (c *Client) ExecPod(ctx context.Context, clusterID, namespace, pod string, opts ExecOptions) (int, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return 0, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	config, ok := c.restConfig(clusterID)
+	if !ok {
+		return 0, fmt.Errorf("exec is not supported on cluster %s", clusterID)
+	}
+	if len(opts.Command) == 0 {
+		return 0, fmt.Errorf("command is required")
+	}
+
+	req := typedClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    opts.Stderr != nil && !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	// Sessions last as long as the user keeps them open, unlike regular API requests
+	execConfig := rest.CopyConfig(config)
+	execConfig.Timeout = 0
+
+	// Prefer the WebSocket protocol and fall back to SPDY for API servers older than 1.30
+	websocketExec, err := remotecommand.NewWebSocketExecutor(execConfig, "GET", req.URL().String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create exec session: %w", err)
+	}
+	spdyExec, err := remotecommand.NewSPDYExecutor(execConfig, "POST", req.URL())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create exec session: %w", err)
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocketExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create exec session: %w", err)
+	}
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Tty:    opts.TTY,
+	}
+	if !opts.TTY {
+		streamOpts.Stderr = opts.Stderr
+	}
+	if opts.TTY && opts.Resize != nil {
+		streamOpts.TerminalSizeQueue = terminalSizeQueue{ctx: ctx, sizes: opts.Resize}
+	}
+
+	err = executor.StreamWithContext(ctx, streamOpts)
+	var exitErr utilexec.CodeExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("exec failed: %w", err)
+	}
+	return 0, nil
+}
+
+// terminalSizeQueue adapts a channel of sizes to remotecommand.TerminalSizeQueue
+type terminalSizeQueue struct {
+	ctx   context.Context
+	sizes <-chan TerminalSize
+}
+
+// Next blocks until the next size change; nil ends the resize stream
+func (q terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size, ok := <-q.sizes:
+		if !ok {
+			return nil
+		}
+		return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+	case <-q.ctx.Done():
+		return nil
+	}
+}
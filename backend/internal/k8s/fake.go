@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeListKinds maps every resource the client lists to its list kind. The fake
+// dynamic client refuses to list resources that are not registered here; Flux kinds
+// are added for every version in fluxKinds.
+var fakeListKinds = map[schema.GroupVersionResource]string{
+	{Group: "", Version: "v1", Resource: "namespaces"}:                 "NamespaceList",
+	{Group: "", Version: "v1", Resource: "pods"}:                       "PodList",
+	{Group: "", Version: "v1", Resource: "services"}:                   "ServiceList",
+	{Group: "", Version: "v1", Resource: "configmaps"}:                 "ConfigMapList",
+	{Group: "", Version: "v1", Resource: "secrets"}:                    "SecretList",
+	{Group: "apps", Version: "v1", Resource: "deployments"}:            "DeploymentList",
+	{Group: "apps", Version: "v1", Resource: "replicasets"}:            "ReplicaSetList",
+	{Group: "apps", Version: "v1", Resource: "statefulsets"}:           "StatefulSetList",
+	{Group: "apps", Version: "v1", Resource: "daemonsets"}:             "DaemonSetList",
+	{Group: "batch", Version: "v1", Resource: "jobs"}:                  "JobList",
+	{Group: "batch", Version: "v1", Resource: "cronjobs"}:              "CronJobList",
+	{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}: "IngressList",
+	podMetricsGVR:  "PodMetricsList",
+	nodeMetricsGVR: "NodeMetricsList",
+}
+
+func init() {
+	for _, kind := range fluxKinds {
+		for _, version := range kind.Versions {
+			fakeListKinds[kind.gvr(version)] = kind.Kind + "List"
+		}
+	}
+}
+
+// AddFakeCluster registers an in-memory cluster instead of connecting to a real API server.
+// objects seed the dynamic client and must be *unstructured.Unstructured with apiVersion and
+// kind set; typedObjects (Deployments, Pods, ...) seed the typed client used for controller
+// health and pod logs. Used by demo mode.
+func (c *Client) AddFakeCluster(clusterID string, objects []runtime.Object, typedObjects []runtime.Object) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), fakeListKinds, objects...)
+	typedClient := kubernetesfake.NewClientset(typedObjects...)
+	typedClient.Resources = fakeFluxResources()
+	c.setClients(clusterID, client, typedClient, nil, false)
+}
+
+// fakeFluxResources is what the discovery of a fake cluster serves: every Flux kind at its
+// fallback version
+func fakeFluxResources() []*metav1.APIResourceList {
+	lists := map[string]*metav1.APIResourceList{}
+	var ordered []*metav1.APIResourceList
+	for _, kind := range fluxKinds {
+		groupVersion := kind.Group + "/" + kind.Fallback
+		if lists[groupVersion] == nil {
+			lists[groupVersion] = &metav1.APIResourceList{GroupVersion: groupVersion}
+			ordered = append(ordered, lists[groupVersion])
+		}
+		lists[groupVersion].APIResources = append(lists[groupVersion].APIResources, metav1.APIResource{
+			Name:       kind.Resource,
+			Kind:       kind.Kind,
+			Namespaced: true,
+		})
+	}
+	return ordered
+}
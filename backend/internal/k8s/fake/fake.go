@@ -0,0 +1,1309 @@
+// Package fake provides an in-memory k8s.ClusterClient with scriptable failures and
+// latency, for handler and sync worker tests and for local development without clusters.
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Cluster is the state of a fake cluster
+type Cluster struct {
+	Resources  []models.FluxResource
+	Flux       k8s.FluxHealth
+	Tree       []k8s.ResourceNode
+	Logs       string
+	Containers []string
+	Quotas     []k8s.NamespaceQuotas
+	Triage     []k8s.TriagePod // failing pods reported for every Flux resource
+	Namespaces []string
+	Unmanaged  []k8s.UnmanagedObject
+	Images     []k8s.ImageOwner
+	Metrics    []k8s.PodMetrics // metrics-server samples; none means metrics-server is missing
+	// MissingKinds are Flux kinds whose CRDs the cluster lacks; every other kind is served
+	MissingKinds []string
+	// Limits are the client limits last set for the cluster
+	Limits k8s.ClientLimits
+	// Impersonate is whether impersonation was last enabled for the cluster
+	Impersonate bool
+}
+
+// Fault scripts failures. Calls to Method (every method when empty) on ClusterID (every
+// cluster when empty) fail with Err. Times limits how many calls fail, 0 means every call;
+// Rate between 0 and 1 fails only that fraction of matching calls.
+type Fault struct {
+	Method    string
+	ClusterID string
+	Err       error
+	Times     int
+	Rate      float64
+}
+
+// Call records a method invoked on the fake client
+type Call struct {
+	Method    string
+	ClusterID string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Client is an in-memory k8s.ClusterClient
+type Client struct {
+	mu         sync.Mutex
+	clusters   map[string]*Cluster
+	registries map[string]k8s.RegistryCredential
+	faults     []*Fault
+	rand       *rand.Rand
+
+	// Latency delays every call; Jitter adds up to that much random extra delay.
+	// Delays end early when the call's context is cancelled.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// Calls lists every method invoked, in order
+	Calls []Call
+}
+
+// NewClient creates a fake client with no clusters. Random faults and jitter are
+// drawn from a source seeded with seed so chaos runs are reproducible.
+func NewClient(seed int64) *Client {
+	return &Client{
+		clusters:   make(map[string]*Cluster),
+		registries: make(map[string]k8s.RegistryCredential),
+		rand:       rand.New(rand.NewSource(seed)),
+	}
+}
+
+// SetCluster registers a cluster with the given state, replacing any existing one.
+// Resources are assigned to the cluster; Flux defaults to healthy.
+func (f *Client) SetCluster(clusterID string, cluster Cluster) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range cluster.Resources {
+		cluster.Resources[i].ClusterID = clusterID
+	}
+	if cluster.Flux.Status == "" {
+		cluster.Flux.Status = k8s.FluxStatusHealthy
+	}
+	f.clusters[clusterID] = &cluster
+}
+
+// Inject adds a scripted fault
+func (f *Client) Inject(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &fault)
+}
+
+// ClearFaults removes every scripted fault
+func (f *Client) ClearFaults() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = nil
+}
+
+// CallCount returns how many times a method was invoked
+func (f *Client) CallCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var count int
+	for _, call := range f.Calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// call records the call, waits for the configured latency and returns a scripted fault, if any
+func (f *Client) call(ctx context.Context, c Call) error {
+	f.mu.Lock()
+	f.Calls = append(f.Calls, c)
+	delay := f.Latency
+	if f.Jitter > 0 {
+		delay += time.Duration(f.rand.Int63n(int64(f.Jitter)))
+	}
+	err := f.fault(c)
+	f.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// fault returns the error of the first fault matching the call; f.mu must be held
+func (f *Client) fault(c Call) error {
+	for i, fault := range f.faults {
+		if fault.Method != "" && fault.Method != c.Method {
+			continue
+		}
+		if fault.ClusterID != "" && fault.ClusterID != c.ClusterID {
+			continue
+		}
+		if fault.Rate > 0 && f.rand.Float64() >= fault.Rate {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				f.faults = append(f.faults[:i:i], f.faults[i+1:]...)
+			}
+		}
+		return fault.Err
+	}
+	return nil
+}
+
+// cluster returns a registered cluster; f.mu must not be held
+func (f *Client) cluster(clusterID string) (*Cluster, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cluster, ok := f.clusters[clusterID]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	return cluster, nil
+}
+
+// resource returns a stored Flux resource; f.mu must be held
+func (c *Cluster) resource(kind, namespace, name string) (*models.FluxResource, error) {
+	for i := range c.Resources {
+		res := &c.Resources[i]
+		if res.Kind == kind && res.Namespace == namespace && res.Name == name {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s/%s not found", kind, namespace, name)
+}
+
+func (f *Client) AddCluster(clusterID, kubeconfig string) error {
+	if err := f.call(context.Background(), Call{Method: "AddCluster", ClusterID: clusterID}); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.clusters[clusterID]; !ok {
+		f.clusters[clusterID] = &Cluster{Flux: k8s.FluxHealth{Status: k8s.FluxStatusEmpty}}
+	}
+	return nil
+}
+
+func (f *Client) SetClientLimits(clusterID string, limits k8s.ClientLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	if err := f.call(context.Background(), Call{Method: "SetClientLimits", ClusterID: clusterID}); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.clusters[clusterID]; !ok {
+		f.clusters[clusterID] = &Cluster{Flux: k8s.FluxHealth{Status: k8s.FluxStatusEmpty}}
+	}
+	f.clusters[clusterID].Limits = limits
+	return nil
+}
+
+func (f *Client) SetImpersonation(clusterID string, enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.clusters[clusterID]; !ok {
+		f.clusters[clusterID] = &Cluster{Flux: k8s.FluxHealth{Status: k8s.FluxStatusEmpty}}
+	}
+	f.clusters[clusterID].Impersonate = enabled
+}
+
+func (f *Client) AddInClusterConfig(clusterID string) error {
+	return f.AddCluster(clusterID, "")
+}
+
+func (f *Client) RemoveCluster(clusterID string) {
+	f.call(context.Background(), Call{Method: "RemoveCluster", ClusterID: clusterID})
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.clusters, clusterID)
+}
+
+func (f *Client) CheckClusterHealth(clusterID string) (string, error) {
+	if err := f.call(context.Background(), Call{Method: "CheckClusterHealth", ClusterID: clusterID}); err != nil {
+		return "unhealthy", err
+	}
+	if _, err := f.cluster(clusterID); err != nil {
+		return "unknown", err
+	}
+	return "healthy", nil
+}
+
+func (f *Client) CheckFluxInstallation(ctx context.Context, clusterID string) (*k8s.FluxHealth, error) {
+	if err := f.call(ctx, Call{Method: "CheckFluxInstallation", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	health := cluster.Flux
+	health.HasResources = len(cluster.Resources) > 0
+	return &health, nil
+}
+
+// GetFluxVersion reports the standard controllers as installed at the cluster's Flux version
+func (f *Client) GetFluxVersion(ctx context.Context, clusterID string) (*k8s.FluxVersion, error) {
+	if err := f.call(ctx, Call{Method: "GetFluxVersion", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	version := &k8s.FluxVersion{Namespace: "flux-system", Distribution: cluster.Flux.Version, APIs: []k8s.FluxAPIGroup{}}
+	for _, name := range k8s.FluxControllers {
+		version.Components = append(version.Components, k8s.FluxComponent{Name: name, Installed: true})
+	}
+	return version, nil
+}
+
+// GetCapabilities reports every Flux kind as served at its fallback version, except the
+// cluster's MissingKinds
+func (f *Client) GetCapabilities(clusterID string) (*k8s.ClusterCapabilities, error) {
+	if err := f.call(context.Background(), Call{Method: "GetCapabilities", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	capabilities := k8s.AssumedCapabilities()
+	for i := range capabilities.Kinds {
+		kind := &capabilities.Kinds[i]
+		for _, missing := range cluster.MissingKinds {
+			if kind.Kind == missing {
+				kind.Installed, kind.Version, kind.Versions = false, "", []string{}
+			}
+		}
+	}
+	return capabilities, nil
+}
+
+// ReviewAccess allows every action; fake clusters have no RBAC
+func (f *Client) ReviewAccess(ctx context.Context, clusterID string, req k8s.AccessReviewRequest) (*k8s.AccessReview, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := f.call(ctx, Call{Method: "ReviewAccess", ClusterID: clusterID, Namespace: req.Namespace, Name: req.Name}); err != nil {
+		return nil, err
+	}
+	if _, err := f.cluster(clusterID); err != nil {
+		return nil, err
+	}
+	return &k8s.AccessReview{AccessReviewRequest: req, Allowed: true}, nil
+}
+
+func (f *Client) GetFluxResources(clusterID string) ([]models.FluxResource, error) {
+	if err := f.call(context.Background(), Call{Method: "GetFluxResources", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resources := make([]models.FluxResource, len(cluster.Resources))
+	copy(resources, cluster.Resources)
+	return resources, nil
+}
+
+func (f *Client) GetFluxStats(clusterID string) (map[string]interface{}, error) {
+	resources, err := f.GetFluxResources(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]interface{})
+	for _, res := range resources {
+		key := strings.ToLower(res.Kind) + "s"
+		counts, _ := stats[key].(map[string]int)
+		if counts == nil {
+			counts = map[string]int{"total": 0, "ready": 0, "notReady": 0}
+			stats[key] = counts
+		}
+		counts["total"]++
+		if res.Status == "Ready" {
+			counts["ready"]++
+		} else {
+			counts["notReady"]++
+		}
+	}
+	return stats, nil
+}
+
+// GetResourceTree returns the configured tree. A filter keeps the nodes matching it and
+// their ancestors; tree nodes carry no labels.
+func (f *Client) GetResourceTree(ctx context.Context, clusterID string, filter k8s.ListFilter) ([]k8s.ResourceNode, error) {
+	if err := f.call(ctx, Call{Method: "GetResourceTree", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if filter.IsZero() {
+		return cluster.Tree, nil
+	}
+	return filterTree(cluster.Tree, filter), nil
+}
+
+// InvalidateResourceTree records the call; the fake builds no trees to cache
+func (f *Client) InvalidateResourceTree(clusterID string) {
+	f.call(context.Background(), Call{Method: "InvalidateResourceTree", ClusterID: clusterID})
+}
+
+func filterTree(nodes []k8s.ResourceNode, filter k8s.ListFilter) []k8s.ResourceNode {
+	var filtered []k8s.ResourceNode
+	for _, node := range nodes {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetName(node.Name)
+		obj.SetNamespace(node.Namespace)
+		children := filterTree(node.Children, filter)
+		if filter.Matches(obj) || len(children) > 0 {
+			node.Children = children
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// GetReconcileOrder places every stored Kustomization and HelmRelease in the first stage,
+// since stored resources carry no dependsOn
+func (f *Client) GetReconcileOrder(ctx context.Context, clusterID string, source *k8s.EventObject) (*k8s.ReconcileOrder, error) {
+	if err := f.call(ctx, Call{Method: "GetReconcileOrder", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	order := &k8s.ReconcileOrder{Source: source, Stages: [][]k8s.ReconcileOrderNode{}, Blocked: []k8s.ReconcileOrderNode{}, Issues: []k8s.ReconcileOrderIssue{}}
+	var stage []k8s.ReconcileOrderNode
+	for _, res := range cluster.Resources {
+		if res.Kind != "Kustomization" && res.Kind != "HelmRelease" {
+			continue
+		}
+		stage = append(stage, k8s.ReconcileOrderNode{
+			EventObject: k8s.EventObject{Kind: res.Kind, Namespace: res.Namespace, Name: res.Name},
+			DependsOn:   []k8s.EventObject{},
+			Affected:    source != nil,
+			Ready:       res.Status == "Ready",
+		})
+	}
+	if len(stage) > 0 {
+		order.Stages = append(order.Stages, stage)
+	}
+	return order, nil
+}
+
+// GetSecretKeys reports a single opaque key for any Secret
+func (f *Client) GetSecretKeys(ctx context.Context, clusterID, namespace, name string) (*k8s.SecretKeys, error) {
+	if err := f.workload(ctx, "GetSecretKeys", clusterID, "Secret", namespace, name); err != nil {
+		return nil, err
+	}
+	return &k8s.SecretKeys{Namespace: namespace, Name: name, Type: "Opaque", Keys: []k8s.SecretKey{{Name: "value", Size: 16}}}, nil
+}
+
+// fluxResource records a call reading a stored Flux resource and checks that it exists
+func (f *Client) fluxResource(ctx context.Context, method, clusterID, kind, namespace, name string) error {
+	if err := f.call(ctx, Call{Method: method, ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err = cluster.resource(kind, namespace, name)
+	return err
+}
+
+// GetHelmReleaseValues reports a stored HelmRelease with no values and no valuesFrom
+// references
+func (f *Client) GetHelmReleaseValues(ctx context.Context, clusterID, namespace, name string, revealSecrets bool) (*k8s.HelmReleaseValues, error) {
+	if err := f.fluxResource(ctx, "GetHelmReleaseValues", clusterID, "HelmRelease", namespace, name); err != nil {
+		return nil, err
+	}
+	return &k8s.HelmReleaseValues{
+		Namespace: namespace,
+		Name:      name,
+		Values:    map[string]interface{}{},
+		Sources:   []k8s.ValuesSource{{Kind: "HelmRelease", Name: name, Status: "applied"}},
+		Keys:      []k8s.ValueOrigin{},
+	}, nil
+}
+
+// GetHelmReleaseHistory reports a single deployed revision of a stored HelmRelease
+func (f *Client) GetHelmReleaseHistory(ctx context.Context, clusterID, namespace, name string, includeValues bool) (*k8s.HelmReleaseHistory, error) {
+	if err := f.fluxResource(ctx, "GetHelmReleaseHistory", clusterID, "HelmRelease", namespace, name); err != nil {
+		return nil, err
+	}
+	revision := k8s.HelmRevision{Revision: 1, Status: "deployed", ChartName: name, ChartVersion: "1.0.0", Description: "Install complete"}
+	if includeValues {
+		revision.Values = map[string]interface{}{}
+	}
+	return &k8s.HelmReleaseHistory{
+		Namespace:        namespace,
+		Name:             name,
+		ReleaseName:      name,
+		StorageNamespace: namespace,
+		Revisions:        []k8s.HelmRevision{revision},
+		ValuesRedacted:   !includeValues,
+	}, nil
+}
+
+// DiffKustomization reports a stored Kustomization as having nothing to change
+func (f *Client) DiffKustomization(ctx context.Context, clusterID, namespace, name string, revealSecrets bool) (*k8s.KustomizationDiff, error) {
+	if err := f.fluxResource(ctx, "DiffKustomization", clusterID, "Kustomization", namespace, name); err != nil {
+		return nil, err
+	}
+	return &k8s.KustomizationDiff{
+		Namespace: namespace,
+		Name:      name,
+		Source:    k8s.EventObject{Kind: "GitRepository", Namespace: namespace, Name: name},
+		Objects:   []k8s.ObjectDiff{},
+		Summary:   map[string]int{},
+		Warnings:  []string{},
+	}, nil
+}
+
+// RollbackHelmRelease accepts revision 1 of a stored HelmRelease
+func (f *Client) RollbackHelmRelease(ctx context.Context, clusterID, namespace, name string, revision int) (*k8s.HelmRollbackResult, error) {
+	if err := f.update(ctx, "RollbackHelmRelease", clusterID, "HelmRelease", namespace, name, func(*models.FluxResource) {}); err != nil {
+		return nil, err
+	}
+	if revision != 1 {
+		return nil, fmt.Errorf("revision %d of release %s not found", revision, name)
+	}
+	return &k8s.HelmRollbackResult{Revision: 1, ChartVersion: "1.0.0", ValuesRestored: true, Message: "Pinned chart to 1.0.0"}, nil
+}
+
+func (f *Client) ForceReconcileHelmRelease(ctx context.Context, clusterID, namespace, name string) (*k8s.HelmRemediationResult, error) {
+	if err := f.update(ctx, "ForceReconcileHelmRelease", clusterID, "HelmRelease", namespace, name, func(res *models.FluxResource) {
+		res.LastReconcile = time.Now()
+	}); err != nil {
+		return nil, err
+	}
+	return &k8s.HelmRemediationResult{RequestedAt: time.Now().Format(time.RFC3339Nano), Forced: true, Message: "Requested a forced Helm install or upgrade"}, nil
+}
+
+func (f *Client) ResetHelmReleaseFailures(ctx context.Context, clusterID, namespace, name string) (*k8s.HelmRemediationResult, error) {
+	if err := f.update(ctx, "ResetHelmReleaseFailures", clusterID, "HelmRelease", namespace, name, func(res *models.FluxResource) {
+		res.LastReconcile = time.Now()
+	}); err != nil {
+		return nil, err
+	}
+	return &k8s.HelmRemediationResult{RequestedAt: time.Now().Format(time.RFC3339Nano), Reset: true, Message: "Requested a reconcile; it had no failures to reset"}, nil
+}
+
+func (f *Client) GetNamespaceQuotas(ctx context.Context, clusterID string, threshold float64) ([]k8s.NamespaceQuotas, error) {
+	if err := f.call(ctx, Call{Method: "GetNamespaceQuotas", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Quotas, nil
+}
+
+func (f *Client) GetImageFreshness(ctx context.Context, clusterID string) (*k8s.ImageFreshnessReport, error) {
+	if err := f.call(ctx, Call{Method: "GetImageFreshness", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	report := &k8s.ImageFreshnessReport{Owners: append([]k8s.ImageOwner{}, cluster.Images...), Warnings: []string{}}
+	for _, owner := range cluster.Images {
+		report.Images += len(owner.Images)
+		report.Outdated += owner.Outdated
+	}
+	return report, nil
+}
+
+func (f *Client) SetRegistryCredential(credential k8s.RegistryCredential) {
+	f.call(context.Background(), Call{Method: "SetRegistryCredential", Name: credential.Registry})
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registries[credential.Registry] = credential
+}
+
+func (f *Client) RemoveRegistryCredential(registry string) {
+	f.call(context.Background(), Call{Method: "RemoveRegistryCredential", Name: registry})
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.registries, registry)
+}
+
+// TestRegistryCredential succeeds unless a fault is scripted for it
+func (f *Client) TestRegistryCredential(ctx context.Context, credential k8s.RegistryCredential) error {
+	return f.call(ctx, Call{Method: "TestRegistryCredential", Name: credential.Registry})
+}
+
+// RegistryCredential returns the registered credential of a registry
+func (f *Client) RegistryCredential(registry string) (k8s.RegistryCredential, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	credential, ok := f.registries[registry]
+	return credential, ok
+}
+
+func (f *Client) GetUnmanagedResources(ctx context.Context, clusterID, namespace string) (*k8s.UnmanagedReport, error) {
+	if err := f.call(ctx, Call{Method: "GetUnmanagedResources", ClusterID: clusterID, Namespace: namespace}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	report := &k8s.UnmanagedReport{Objects: []k8s.UnmanagedObject{}, ByNamespace: map[string]int{}, Warnings: []string{}}
+	for _, obj := range cluster.Unmanaged {
+		if namespace != "" && obj.Namespace != namespace {
+			continue
+		}
+		report.Objects = append(report.Objects, obj)
+		report.ByNamespace[obj.Namespace]++
+	}
+	report.Scanned = len(report.Objects)
+	return report, nil
+}
+
+func (f *Client) GetPodTriage(ctx context.Context, clusterID, kind, namespace, name string) (*k8s.PodTriage, error) {
+	if err := f.call(ctx, Call{Method: "GetPodTriage", ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	pods := append([]k8s.TriagePod{}, cluster.Triage...)
+	return &k8s.PodTriage{Kind: kind, Namespace: namespace, Name: name, TotalPods: len(pods), Pods: pods, Workloads: []k8s.TriageWorkload{}}, nil
+}
+
+// GetResourceEvents returns the events of the fake cluster's triage pods for a Pod
+func (f *Client) GetResourceEvents(ctx context.Context, clusterID, kind, namespace, name string, limit int) ([]k8s.ResourceEvent, error) {
+	if err := f.workload(ctx, "GetResourceEvents", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	events := []k8s.ResourceEvent{}
+	for _, pod := range cluster.Triage {
+		if kind != "Pod" || pod.Namespace != namespace || pod.Name != name {
+			continue
+		}
+		for _, event := range pod.Events {
+			events = append(events, k8s.ResourceEvent{TriageEvent: event, Object: k8s.EventObject{Kind: kind, Namespace: namespace, Name: name}})
+		}
+	}
+	return events, nil
+}
+
+// ListClusterEvents returns the events of the fake cluster's triage pods within a window
+func (f *Client) ListClusterEvents(ctx context.Context, clusterID, namespace string, since, until time.Time, limit int) ([]k8s.ResourceEvent, error) {
+	if err := f.call(ctx, Call{Method: "ListClusterEvents", ClusterID: clusterID, Namespace: namespace}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	events := []k8s.ResourceEvent{}
+	for _, pod := range cluster.Triage {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+		for _, event := range pod.Events {
+			if event.LastSeen.Before(since) || !event.LastSeen.Before(until) {
+				continue
+			}
+			events = append(events, k8s.ResourceEvent{TriageEvent: event, Object: k8s.EventObject{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].LastSeen.Before(events[j].LastSeen) })
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, nil
+}
+
+func (f *Client) GetResourcesCreatedByFlux(ctx context.Context, clusterID, kind, namespace, name string) ([]map[string]interface{}, error) {
+	if err := f.call(ctx, Call{Method: "GetResourcesCreatedByFlux", ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return nil, err
+	}
+	if _, err := f.cluster(clusterID); err != nil {
+		return nil, err
+	}
+	return []map[string]interface{}{}, nil
+}
+
+// update records the call and applies a change to a stored Flux resource
+func (f *Client) update(ctx context.Context, method, clusterID, kind, namespace, name string, apply func(res *models.FluxResource)) error {
+	if err := f.call(ctx, Call{Method: method, ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res, err := cluster.resource(kind, namespace, name)
+	if err != nil {
+		return err
+	}
+	apply(res)
+	res.UpdatedAt = time.Now()
+	return nil
+}
+
+func (f *Client) ReconcileResource(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return f.update(ctx, "ReconcileResource", clusterID, kind, namespace, name, func(res *models.FluxResource) {
+		res.LastReconcile = time.Now()
+	})
+}
+
+// ReconcileWithSource reconciles the resource and reports its source as unchanged
+func (f *Client) ReconcileWithSource(ctx context.Context, clusterID, kind, namespace, name string) (*k8s.SourceReconcileResult, error) {
+	if err := f.update(ctx, "ReconcileWithSource", clusterID, kind, namespace, name, func(res *models.FluxResource) {
+		res.LastReconcile = time.Now()
+	}); err != nil {
+		return nil, err
+	}
+	return &k8s.SourceReconcileResult{Kind: "GitRepository", Namespace: namespace, Name: name}, nil
+}
+
+// WaitForReconcile reports a stored resource as ready straight away
+func (f *Client) WaitForReconcile(ctx context.Context, clusterID, kind, namespace, name string, progress func(k8s.ReconcileProgress)) (*k8s.ReconcileProgress, error) {
+	if err := f.fluxResource(ctx, "WaitForReconcile", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	result := k8s.ReconcileProgress{
+		Phase:      "ready",
+		Handled:    true,
+		Reason:     "ReconciliationSucceeded",
+		Conditions: []k8s.ReconcileCondition{{Type: "Ready", Status: "True", Reason: "ReconciliationSucceeded"}},
+	}
+	progress(result)
+	return &result, nil
+}
+
+func (f *Client) SuspendResource(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return f.update(ctx, "SuspendResource", clusterID, kind, namespace, name, func(res *models.FluxResource) {
+		setSuspended(res, true)
+	})
+}
+
+func (f *Client) ResumeResource(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return f.update(ctx, "ResumeResource", clusterID, kind, namespace, name, func(res *models.FluxResource) {
+		setSuspended(res, false)
+	})
+}
+
+// ListSuspendTargets matches stored resources against the scope, reading labels and
+// spec.suspend from their metadata
+func (f *Client) ListSuspendTargets(ctx context.Context, clusterID string, scope k8s.SuspendScope) ([]k8s.SuspendTarget, error) {
+	if err := f.call(ctx, Call{Method: "ListSuspendTargets", ClusterID: clusterID, Namespace: scope.Namespace}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := labels.Parse(scope.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	targets := []k8s.SuspendTarget{}
+	for _, kind := range scope.Kinds {
+		for i := range cluster.Resources {
+			res := &cluster.Resources[i]
+			if res.Kind != kind || (scope.Namespace != "" && res.Namespace != scope.Namespace) {
+				continue
+			}
+			object := resourceObject(res)
+			metadata, _ := object["metadata"].(map[string]interface{})
+			resLabels := labels.Set{}
+			if values, ok := metadata["labels"].(map[string]interface{}); ok {
+				for key, value := range values {
+					resLabels[key], _ = value.(string)
+				}
+			}
+			if !selector.Matches(resLabels) {
+				continue
+			}
+			spec, _ := object["spec"].(map[string]interface{})
+			suspended, _ := spec["suspend"].(bool)
+			targets = append(targets, k8s.SuspendTarget{Kind: res.Kind, Namespace: res.Namespace, Name: res.Name, Suspended: suspended})
+		}
+	}
+	return targets, nil
+}
+
+// resourceObject decodes the object stored in a resource's metadata, or an empty one
+func resourceObject(res *models.FluxResource) map[string]interface{} {
+	object := map[string]interface{}{}
+	if res.Metadata != "" {
+		json.Unmarshal([]byte(res.Metadata), &object)
+	}
+	return object
+}
+
+// setSuspended records spec.suspend in a resource's stored object
+func setSuspended(res *models.FluxResource, suspended bool) {
+	object := resourceObject(res)
+	spec, ok := object["spec"].(map[string]interface{})
+	if !ok {
+		spec = map[string]interface{}{}
+		object["spec"] = spec
+	}
+	spec["suspend"] = suspended
+	if data, err := json.Marshal(object); err == nil {
+		res.Metadata = string(data)
+	}
+}
+
+func (f *Client) UpdateFluxResource(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error {
+	return f.update(ctx, "UpdateFluxResource", clusterID, kind, namespace, name, func(res *models.FluxResource) {})
+}
+
+func (f *Client) PatchFluxResource(ctx context.Context, clusterID, kind, namespace, name string, patch k8s.ResourcePatch) error {
+	return f.update(ctx, "PatchFluxResource", clusterID, kind, namespace, name, func(res *models.FluxResource) {})
+}
+
+func (f *Client) CreateNotificationResource(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	return f.create(ctx, "CreateNotificationResource", clusterID, kind, namespace, manifest)
+}
+
+func (f *Client) CreateFluxResource(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	return f.create(ctx, "CreateFluxResource", clusterID, kind, namespace, manifest)
+}
+
+// create stores a created resource as Ready
+func (f *Client) create(ctx context.Context, method, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	metadata, _ := manifest["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if err := f.call(ctx, Call{Method: method, ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := cluster.resource(kind, namespace, name); err == nil {
+		return fmt.Errorf("%s %s/%s already exists", kind, namespace, name)
+	}
+	now := time.Now()
+	cluster.Resources = append(cluster.Resources, models.FluxResource{
+		ClusterID: clusterID,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Status:    "Ready",
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	return nil
+}
+
+// ApplyManifests stores applied Flux objects as resources of the cluster; other kinds are
+// reported as created without being kept
+func (f *Client) ApplyManifests(ctx context.Context, clusterID string, manifests []byte, opts k8s.ApplyOptions) (*k8s.ApplyResult, error) {
+	if err := f.call(ctx, Call{Method: "ApplyManifests", ClusterID: clusterID, Namespace: opts.Namespace}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]interface{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, &k8s.InvalidManifestError{Reason: err.Error()}
+		}
+		if object != nil {
+			objects = append(objects, object)
+		}
+	}
+	if len(objects) == 0 {
+		return nil, &k8s.InvalidManifestError{Reason: "no Kubernetes objects found"}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := &k8s.ApplyResult{DryRun: opts.DryRun, Objects: []k8s.AppliedObject{}, Summary: map[string]int{}}
+	for _, object := range objects {
+		metadata, _ := object["metadata"].(map[string]interface{})
+		applied := k8s.AppliedObject{Action: "created"}
+		applied.APIVersion, _ = object["apiVersion"].(string)
+		applied.Kind, _ = object["kind"].(string)
+		applied.Name, _ = metadata["name"].(string)
+		applied.Namespace, _ = metadata["namespace"].(string)
+		if applied.Namespace == "" && applied.Kind != "Namespace" {
+			applied.Namespace = opts.Namespace
+		}
+		if applied.Kind == "" || applied.Name == "" {
+			return nil, &k8s.InvalidManifestError{Reason: "every document needs a kind and metadata.name"}
+		}
+
+		if strings.Contains(applied.APIVersion, "toolkit.fluxcd.io") {
+			data, _ := json.Marshal(object)
+			if res, err := cluster.resource(applied.Kind, applied.Namespace, applied.Name); err == nil {
+				applied.Action = "configured"
+				if !opts.DryRun {
+					res.Metadata = string(data)
+					res.UpdatedAt = time.Now()
+				}
+			} else if !opts.DryRun {
+				now := time.Now()
+				cluster.Resources = append(cluster.Resources, models.FluxResource{
+					ClusterID: clusterID,
+					Kind:      applied.Kind,
+					Namespace: applied.Namespace,
+					Name:      applied.Name,
+					Status:    "Unknown",
+					Metadata:  string(data),
+					CreatedAt: now,
+					UpdatedAt: now,
+				})
+			}
+		}
+		result.Objects = append(result.Objects, applied)
+		result.Summary[applied.Action]++
+	}
+	return result, nil
+}
+
+func (f *Client) DeleteNotificationResource(ctx context.Context, clusterID, kind, namespace, name string) error {
+	if err := f.call(ctx, Call{Method: "DeleteNotificationResource", ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, res := range cluster.Resources {
+		if res.Kind == kind && res.Namespace == namespace && res.Name == name {
+			cluster.Resources = append(cluster.Resources[:i], cluster.Resources[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s/%s not found", kind, namespace, name)
+}
+
+// workload records a call against a workload of a registered cluster
+func (f *Client) workload(ctx context.Context, method, clusterID, kind, namespace, name string) error {
+	if err := f.call(ctx, Call{Method: method, ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return err
+	}
+	_, err := f.cluster(clusterID)
+	return err
+}
+
+func (f *Client) ScaleResource(ctx context.Context, clusterID, kind, namespace, name string, replicas int32) error {
+	return f.workload(ctx, "ScaleResource", clusterID, kind, namespace, name)
+}
+
+func (f *Client) RestartResource(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return f.workload(ctx, "RestartResource", clusterID, kind, namespace, name)
+}
+
+// GetWorkloadSnapshot reports a single settled replica
+func (f *Client) GetWorkloadSnapshot(ctx context.Context, clusterID, kind, namespace, name string) (*k8s.WorkloadSnapshot, error) {
+	if err := f.workload(ctx, "GetWorkloadSnapshot", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	return &k8s.WorkloadSnapshot{
+		Replicas:           1,
+		ReadyReplicas:      1,
+		UpdatedReplicas:    1,
+		AvailableReplicas:  1,
+		Generation:         1,
+		ObservedGeneration: 1,
+		CapturedAt:         time.Now().UTC(),
+	}, nil
+}
+
+func (f *Client) UpdateResourceSpec(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error {
+	return f.workload(ctx, "UpdateResourceSpec", clusterID, kind, namespace, name)
+}
+
+func (f *Client) PatchResource(ctx context.Context, clusterID, kind, namespace, name string, patch k8s.ResourcePatch) error {
+	return f.workload(ctx, "PatchResource", clusterID, kind, namespace, name)
+}
+
+func (f *Client) ListNamespaces(ctx context.Context, clusterID string, filter k8s.ListFilter) ([]k8s.NamespaceInfo, error) {
+	if err := f.call(ctx, Call{Method: "ListNamespaces", ClusterID: clusterID}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	namespaces := []k8s.NamespaceInfo{}
+	for _, name := range cluster.Namespaces {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetName(name)
+		if filter.Matches(obj) {
+			namespaces = append(namespaces, k8s.NamespaceInfo{Name: name, Status: "Active", Labels: map[string]string{}})
+		}
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces, nil
+}
+
+func (f *Client) CreateNamespace(ctx context.Context, clusterID string, req k8s.NamespaceRequest) error {
+	if err := f.call(ctx, Call{Method: "CreateNamespace", ClusterID: clusterID, Name: req.Name}); err != nil {
+		return err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, name := range cluster.Namespaces {
+		if name == req.Name {
+			return fmt.Errorf("%w: %s", k8s.ErrNamespaceExists, req.Name)
+		}
+	}
+	cluster.Namespaces = append(cluster.Namespaces, req.Name)
+	return nil
+}
+
+func (f *Client) DeleteNamespace(ctx context.Context, clusterID, name string) error {
+	if err := f.call(ctx, Call{Method: "DeleteNamespace", ClusterID: clusterID, Name: name}); err != nil {
+		return err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, existing := range cluster.Namespaces {
+		if existing == name {
+			cluster.Namespaces = append(cluster.Namespaces[:i], cluster.Namespaces[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", k8s.ErrNamespaceNotFound, name)
+}
+
+func (f *Client) GetResourceManifest(ctx context.Context, clusterID, kind, namespace, name string) (map[string]interface{}, error) {
+	if err := f.workload(ctx, "GetResourceManifest", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"name": name, "namespace": namespace},
+	}, nil
+}
+
+// GetResourceYAML serializes the stored object of a Flux resource, or a bare object for
+// other kinds
+func (f *Client) GetResourceYAML(ctx context.Context, clusterID, kind, namespace, name string, revealSecrets bool) (*k8s.ResourceYAML, error) {
+	if err := f.workload(ctx, "GetResourceYAML", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"name": name, "namespace": namespace},
+	}
+	f.mu.Lock()
+	if res, err := cluster.resource(kind, namespace, name); err == nil && res.Metadata != "" {
+		object = resourceObject(res)
+	}
+	f.mu.Unlock()
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return &k8s.ResourceYAML{YAML: data}, nil
+}
+
+func (f *Client) GetResourceDiff(ctx context.Context, clusterID, kind, namespace, name string) (map[string]interface{}, error) {
+	if err := f.workload(ctx, "GetResourceDiff", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"has_diff": false}, nil
+}
+
+func (f *Client) DeletePod(ctx context.Context, clusterID, namespace, name string) error {
+	return f.workload(ctx, "DeletePod", clusterID, "Pod", namespace, name)
+}
+
+// DeletePods matches the named pods; fake clusters have no pods for selectors to match
+func (f *Client) DeletePods(ctx context.Context, clusterID, namespace string, req k8s.PodDeletionRequest) (*k8s.PodDeletionResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := f.workload(ctx, "DeletePods", clusterID, "Pod", namespace, req.Selector); err != nil {
+		return nil, err
+	}
+	result := &k8s.PodDeletionResult{
+		Namespace: namespace,
+		Selector:  req.Selector,
+		DryRun:    req.DryRun,
+		Evict:     req.Evict,
+		Matched:   append([]string{}, req.Pods...),
+		Deleted:   []string{},
+	}
+	if len(result.Matched) > req.MaxCount {
+		return result, &k8s.TooManyPodsError{Matched: len(result.Matched), MaxCount: req.MaxCount}
+	}
+	if !req.DryRun {
+		result.Deleted = append(result.Deleted, result.Matched...)
+	}
+	return result, nil
+}
+
+// EvictPod always succeeds; fake clusters have no PodDisruptionBudgets
+func (f *Client) EvictPod(ctx context.Context, clusterID, namespace, name string) error {
+	return f.workload(ctx, "EvictPod", clusterID, "Pod", namespace, name)
+}
+
+// ExecPod echoes stdin to stdout until stdin ends, like running cat
+func (f *Client) ExecPod(ctx context.Context, clusterID, namespace, pod string, opts k8s.ExecOptions) (int, error) {
+	if err := f.workload(ctx, "ExecPod", clusterID, "Pod", namespace, pod); err != nil {
+		return 0, err
+	}
+	if opts.Stdin != nil && opts.Stdout != nil {
+		if _, err := io.Copy(opts.Stdout, opts.Stdin); err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+// PortForwardHTTP answers every forwarded request with 200 OK and the request line
+func (f *Client) PortForwardHTTP(ctx context.Context, clusterID, namespace, pod string, req k8s.PortForwardRequest) (*k8s.PortForwardResponse, error) {
+	if err := f.workload(ctx, "PortForwardHTTP", clusterID, "Pod", namespace, pod); err != nil {
+		return nil, err
+	}
+	return &k8s.PortForwardResponse{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Headers:    map[string]string{"Content-Type": "text/plain"},
+		Body:       fmt.Sprintf("%s %s on port %d\n", req.Method, req.Path, req.Port),
+	}, nil
+}
+
+func (f *Client) GetPodLogs(ctx context.Context, clusterID, namespace, podName, containerName string, tailLines int64, follow bool) (string, error) {
+	if err := f.workload(ctx, "GetPodLogs", clusterID, "Pod", namespace, podName); err != nil {
+		return "", err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return "", err
+	}
+	return cluster.Logs, nil
+}
+
+func (f *Client) GetPodContainers(ctx context.Context, clusterID, namespace, podName string) ([]string, error) {
+	if err := f.workload(ctx, "GetPodContainers", clusterID, "Pod", namespace, podName); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Containers, nil
+}
+
+// GetPodMetrics returns the scripted sample of a pod
+func (f *Client) GetPodMetrics(ctx context.Context, clusterID, namespace, name string) (*k8s.PodMetrics, error) {
+	if err := f.workload(ctx, "GetPodMetrics", clusterID, "Pod", namespace, name); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cluster.Metrics) == 0 {
+		return nil, fmt.Errorf("%w: the cluster does not serve metrics.k8s.io/v1beta1; install metrics-server", k8s.ErrMetricsUnavailable)
+	}
+	for i := range cluster.Metrics {
+		if cluster.Metrics[i].Namespace == namespace && cluster.Metrics[i].Name == name {
+			metrics := cluster.Metrics[i]
+			return &metrics, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s/%s", k8s.ErrPodNotFound, namespace, name)
+}
+
+// SearchFluxResourceLogs greps the scripted Logs of every scripted container, as if the
+// Flux resource owned a single pod named after it
+func (f *Client) SearchFluxResourceLogs(ctx context.Context, clusterID, kind, namespace, name string, opts k8s.LogSearchOptions) (*k8s.LogSearchResult, error) {
+	if err := f.call(ctx, Call{Method: "SearchFluxResourceLogs", ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return nil, err
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &k8s.LogSearchResult{
+		Kind:               kind,
+		Namespace:          namespace,
+		Name:               name,
+		Query:              opts.Query,
+		PodsSearched:       1,
+		ContainersSearched: len(cluster.Containers),
+		Results:            []k8s.ContainerLogMatches{},
+	}
+	for _, container := range cluster.Containers {
+		matches, truncated, err := k8s.SearchLogText(strings.NewReader(cluster.Logs), opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		result.TotalMatches += len(matches)
+		result.Results = append(result.Results, k8s.ContainerLogMatches{
+			Namespace: namespace, Pod: name + "-0", Container: container, Matches: matches, Truncated: truncated,
+		})
+	}
+	return result, nil
+}
+
+// ListLogContainers reports the scripted Containers of a single pod named after the resource
+func (f *Client) ListLogContainers(ctx context.Context, clusterID, kind, namespace, name string) ([]k8s.LogContainer, error) {
+	if err := f.call(ctx, Call{Method: "ListLogContainers", ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name}); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	pod := name
+	if kind != "Pod" {
+		pod = name + "-0"
+	}
+	containers := []k8s.LogContainer{}
+	for _, container := range cluster.Containers {
+		containers = append(containers, k8s.LogContainer{Namespace: namespace, Pod: pod, Container: container})
+	}
+	return containers, nil
+}
+
+func (f *Client) StreamContainerLogs(ctx context.Context, clusterID string, container k8s.LogContainer, opts k8s.LogStreamOptions) (io.ReadCloser, error) {
+	if err := f.workload(ctx, "StreamContainerLogs", clusterID, "Pod", container.Namespace, container.Pod); err != nil {
+		return nil, err
+	}
+	cluster, err := f.cluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(cluster.Logs)), nil
+}
+
+func (f *Client) GetAggregatedLogs(ctx context.Context, filters map[string]interface{}) ([]k8s.AggregatedLogEntry, error) {
+	if err := f.call(ctx, Call{Method: "GetAggregatedLogs"}); err != nil {
+		return nil, err
+	}
+	clusterIDs, _ := filters["cluster_ids"].([]string)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(clusterIDs) == 0 {
+		for id := range f.clusters {
+			clusterIDs = append(clusterIDs, id)
+		}
+	}
+
+	logs := []k8s.AggregatedLogEntry{}
+	for _, id := range clusterIDs {
+		cluster, ok := f.clusters[id]
+		if !ok || cluster.Logs == "" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(cluster.Logs, "\n"), "\n") {
+			logs = append(logs, k8s.AggregatedLogEntry{
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				ClusterID: id,
+				Message:   line,
+			})
+		}
+	}
+	return logs, nil
+}
+
+var _ k8s.ClusterClient = (*Client)(nil)
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Fingerprint identifies the API server a kubeconfig points at
+type Fingerprint struct {
+	Server        string
+	CAFingerprint string // hex SHA-256 of the CA certificate, empty when the kubeconfig has none
+}
+
+// KubeconfigFingerprint returns the API server URL and CA fingerprint of the kubeconfig's current context
+func KubeconfigFingerprint(kubeconfig string) (*Fingerprint, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+
+	caData := config.CAData
+	if len(caData) == 0 && config.CAFile != "" {
+		if caData, err = os.ReadFile(config.CAFile); err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+	}
+
+	return &Fingerprint{
+		Server:        strings.TrimSuffix(strings.ToLower(config.Host), "/"),
+		CAFingerprint: caFingerprint(caData),
+	}, nil
+}
+
+// ChangedFrom reports whether f points at a different server than the stored values.
+// Empty stored values are unknown and never count as a change.
+func (f *Fingerprint) ChangedFrom(server, caFingerprint string) bool {
+	if server != "" && f.Server != server {
+		return true
+	}
+	return caFingerprint != "" && f.CAFingerprint != "" && f.CAFingerprint != caFingerprint
+}
+
+// caFingerprint hashes the first certificate in PEM data, or the raw data when it is not PEM
+func caFingerprint(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
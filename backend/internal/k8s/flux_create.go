@@ -0,0 +1,248 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// defaultFluxInterval is the reconcile interval of created resources that set none
+const defaultFluxInterval = "10m"
+
+// resourceNamePattern matches valid Kubernetes object names (RFC 1123 subdomains)
+var resourceNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// IsCreatableFluxKind reports whether kind is a Kustomization, HelmRelease, GitRepository
+// or HelmRepository, the kinds an app is onboarded with
+func IsCreatableFluxKind(kind string) bool {
+	switch kind {
+	case "Kustomization", "HelmRelease", "GitRepository", "HelmRepository":
+		return true
+	}
+	return false
+}
+
+// FluxResourceFields describe a Flux resource to create without writing its manifest.
+// Which fields apply depends on the kind; interval defaults to 10m.
+type FluxResourceFields struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Interval  string `json:"interval,omitempty"`
+
+	// Kustomization and HelmRelease: the source to deploy from. Its namespace defaults to
+	// the resource's.
+	Source *EventObject `json:"source,omitempty"`
+
+	// Kustomization
+	Path            string `json:"path,omitempty"`
+	Prune           *bool  `json:"prune,omitempty"` // defaults to true
+	TargetNamespace string `json:"target_namespace,omitempty"`
+
+	// HelmRelease
+	Chart   string                 `json:"chart,omitempty"`
+	Version string                 `json:"version,omitempty"`
+	Values  map[string]interface{} `json:"values,omitempty"`
+
+	// GitRepository and HelmRepository. Type "oci" makes a HelmRepository an OCI registry.
+	URL       string `json:"url,omitempty"`
+	Branch    string `json:"branch,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Semver    string `json:"semver,omitempty"`
+	SecretRef string `json:"secret_ref,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// BuildFluxManifest returns the manifest of a Kustomization, HelmRelease, GitRepository
+// or HelmRepository described by guided fields. The apiVersion is left out; it is filled
+// in with the version the cluster serves when the resource is created.
+func BuildFluxManifest(kind string, fields FluxResourceFields) (map[string]interface{}, error) {
+	if !IsCreatableFluxKind(kind) {
+		return nil, fmt.Errorf("%s cannot be created from fields", kind)
+	}
+	if !resourceNamePattern.MatchString(fields.Name) || len(fields.Name) > 253 {
+		return nil, fmt.Errorf("name %q is not a valid Kubernetes name", fields.Name)
+	}
+	interval := fields.Interval
+	if interval == "" {
+		interval = defaultFluxInterval
+	}
+	if _, err := time.ParseDuration(interval); err != nil {
+		return nil, fmt.Errorf("interval %q is not a duration such as 10m", interval)
+	}
+
+	spec := map[string]interface{}{"interval": interval}
+	var err error
+	switch kind {
+	case "Kustomization":
+		err = kustomizationSpec(spec, fields)
+	case "HelmRelease":
+		err = helmReleaseSpec(spec, fields)
+	case "GitRepository":
+		err = gitRepositorySpec(spec, fields)
+	case "HelmRepository":
+		err = helmRepositorySpec(spec, fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{"name": fields.Name}
+	if fields.Namespace != "" {
+		metadata["namespace"] = fields.Namespace
+	}
+	return map[string]interface{}{
+		"kind":     kind,
+		"metadata": metadata,
+		"spec":     spec,
+	}, nil
+}
+
+// sourceRef returns the sourceRef of a Kustomization or HelmRelease, checking its kind is
+// one of allowed
+func sourceRef(fields FluxResourceFields, allowed ...string) (map[string]interface{}, error) {
+	source := fields.Source
+	if source == nil || source.Kind == "" || source.Name == "" {
+		return nil, errors.New("source with a kind and name is required")
+	}
+	valid := false
+	for _, kind := range allowed {
+		valid = valid || source.Kind == kind
+	}
+	if !valid {
+		return nil, fmt.Errorf("source kind must be one of %v, not %s", allowed, source.Kind)
+	}
+	ref := map[string]interface{}{"kind": source.Kind, "name": source.Name}
+	if source.Namespace != "" && source.Namespace != fields.Namespace {
+		ref["namespace"] = source.Namespace
+	}
+	return ref, nil
+}
+
+func kustomizationSpec(spec map[string]interface{}, fields FluxResourceFields) error {
+	ref, err := sourceRef(fields, "GitRepository", "OCIRepository", "Bucket")
+	if err != nil {
+		return err
+	}
+	spec["sourceRef"] = ref
+	path := fields.Path
+	if path == "" {
+		path = "./"
+	}
+	spec["path"] = path
+	spec["prune"] = fields.Prune == nil || *fields.Prune
+	if fields.TargetNamespace != "" {
+		spec["targetNamespace"] = fields.TargetNamespace
+	}
+	return nil
+}
+
+func helmReleaseSpec(spec map[string]interface{}, fields FluxResourceFields) error {
+	if fields.Chart == "" {
+		return errors.New("chart is required")
+	}
+	ref, err := sourceRef(fields, "HelmRepository", "GitRepository", "Bucket")
+	if err != nil {
+		return err
+	}
+	chartSpec := map[string]interface{}{"chart": fields.Chart, "sourceRef": ref}
+	if fields.Version != "" {
+		chartSpec["version"] = fields.Version
+	}
+	spec["chart"] = map[string]interface{}{"spec": chartSpec}
+	if fields.TargetNamespace != "" {
+		spec["targetNamespace"] = fields.TargetNamespace
+	}
+	if len(fields.Values) > 0 {
+		spec["values"] = fields.Values
+	}
+	return nil
+}
+
+func gitRepositorySpec(spec map[string]interface{}, fields FluxResourceFields) error {
+	if fields.URL == "" {
+		return errors.New("url is required")
+	}
+	spec["url"] = fields.URL
+	ref := map[string]interface{}{}
+	switch {
+	case fields.Semver != "":
+		ref["semver"] = fields.Semver
+	case fields.Tag != "":
+		ref["tag"] = fields.Tag
+	case fields.Branch != "":
+		ref["branch"] = fields.Branch
+	default:
+		ref["branch"] = "main"
+	}
+	spec["ref"] = ref
+	if fields.SecretRef != "" {
+		spec["secretRef"] = map[string]interface{}{"name": fields.SecretRef}
+	}
+	return nil
+}
+
+func helmRepositorySpec(spec map[string]interface{}, fields FluxResourceFields) error {
+	if fields.URL == "" {
+		return errors.New("url is required")
+	}
+	spec["url"] = fields.URL
+	switch fields.Type {
+	case "", "default":
+	case "oci":
+		spec["type"] = "oci"
+	default:
+		return fmt.Errorf("type must be default or oci, not %s", fields.Type)
+	}
+	if fields.SecretRef != "" {
+		spec["secretRef"] = map[string]interface{}{"name": fields.SecretRef}
+	}
+	return nil
+}
+
+// CreateFluxResource creates a Kustomization, HelmRelease, GitRepository or HelmRepository
+// from its manifest. The apiVersion and kind are filled in when missing; the namespace
+// argument wins over the manifest's.
+func (c *Client) CreateFluxResource(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	if !IsCreatableFluxKind(kind) {
+		return fmt.Errorf("%s cannot be created", kind)
+	}
+	return c.createResource(ctx, clusterID, kind, namespace, manifest)
+}
+
+// createResource creates a Flux resource of kind from its manifest with the version the
+// cluster serves
+func (c *Client) createResource(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return err
+	}
+
+	gvr, err := c.getGVRForKind(clusterID, kind)
+	if err != nil {
+		return err
+	}
+
+	resource := &unstructured.Unstructured{Object: manifest}
+	if resource.GetAPIVersion() == "" {
+		resource.SetAPIVersion(gvr.GroupVersion().String())
+	}
+	if resource.GetKind() != kind {
+		resource.SetKind(kind)
+	}
+	resource.SetNamespace(namespace)
+	if resource.GetName() == "" {
+		return fmt.Errorf("metadata.name is required")
+	}
+	applyProvenance(ctx, resource)
+
+	if _, err := client.Resource(gvr).Namespace(namespace).Create(ctx, resource, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Flux installation states reported for a cluster
+const (
+	FluxStatusHealthy      = "healthy"       // CRDs present, controllers available, resources found
+	FluxStatusEmpty        = "empty"         // Flux installed but no Flux resources exist
+	FluxStatusDegraded     = "degraded"      // One or more controllers unavailable
+	FluxStatusNotInstalled = "not_installed" // Flux CRDs missing
+	FluxStatusUnknown      = "unknown"
+)
+
+// FluxControllers lists the controllers deployed by a standard Flux installation
+var FluxControllers = []string{
+	"source-controller",
+	"kustomize-controller",
+	"helm-controller",
+	"notification-controller",
+}
+
+// requiredFluxControllers must be running for Flux to reconcile anything at all
+var requiredFluxControllers = map[string]bool{
+	"source-controller":    true,
+	"kustomize-controller": true,
+}
+
+// FluxControllerStatus describes the state of a single Flux controller deployment
+type FluxControllerStatus struct {
+	Name          string `json:"name"`
+	Installed     bool   `json:"installed"`
+	Required      bool   `json:"required"`
+	Ready         bool   `json:"ready"`
+	Degraded      bool   `json:"degraded"`
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"ready_replicas"`
+	Version       string `json:"version,omitempty"` // image tag of the manager container
+	Restarts      int32  `json:"restarts"`          // container restarts across the controller's pods
+	Reason        string `json:"reason,omitempty"`  // e.g. CrashLoopBackOff, OOMKilled, ProgressDeadlineExceeded
+	Message       string `json:"message,omitempty"`
+}
+
+// FluxHealth summarizes whether Flux is installed and working on a cluster
+type FluxHealth struct {
+	Status        string                 `json:"status"`
+	Namespace     string                 `json:"namespace"`
+	CRDsInstalled bool                   `json:"crds_installed"`
+	HasResources  bool                   `json:"has_resources"`
+	Controllers   []FluxControllerStatus `json:"controllers"`
+	Degraded      []string               `json:"degraded"`          // names of degraded controllers
+	Version       string                 `json:"version,omitempty"` // Flux distribution version, see GetFluxVersion
+	Message       string                 `json:"message,omitempty"`
+}
+
+// fluxNamespace returns the namespace Flux controllers are installed in
+func fluxNamespace() string {
+	if ns := os.Getenv("FLUX_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return "flux-system"
+}
+
+// CheckFluxInstallation inspects Flux CRDs and controller deployments on a cluster
+func (c *Client) CheckFluxInstallation(ctx context.Context, clusterID string) (*FluxHealth, error) {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	health := &FluxHealth{
+		Status:    FluxStatusUnknown,
+		Namespace: fluxNamespace(),
+		Degraded:  []string{},
+	}
+
+	// A missing CRD surfaces as NotFound when listing the resource
+	for _, kind := range []string{"Kustomization", "HelmRelease", "GitRepository", "HelmRepository"} {
+		gvr, err := c.getGVRForKind(clusterID, kind)
+		if err != nil {
+			return nil, err
+		}
+		list, err := client.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+		}
+		health.CRDsInstalled = true
+		if len(list.Items) > 0 {
+			health.HasResources = true
+		}
+	}
+
+	for _, name := range FluxControllers {
+		status, err := fluxControllerStatus(ctx, typedClient, health.Namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		if status.Degraded {
+			health.Degraded = append(health.Degraded, name)
+		}
+		health.Controllers = append(health.Controllers, *status)
+	}
+	if health.CRDsInstalled {
+		if health.Version, err = fluxDistributionVersion(ctx, typedClient, health.Namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case !health.CRDsInstalled:
+		health.Status = FluxStatusNotInstalled
+		health.Message = "Flux CRDs are not installed"
+	case len(health.Degraded) > 0:
+		health.Status = FluxStatusDegraded
+		health.Message = fmt.Sprintf("Degraded controllers: %s", strings.Join(health.Degraded, ", "))
+	case !health.HasResources:
+		health.Status = FluxStatusEmpty
+		health.Message = "No Flux resources found"
+	default:
+		health.Status = FluxStatusHealthy
+	}
+
+	return health, nil
+}
+
+// fluxControllerStatus reads a controller's deployment and pods. A controller is degraded
+// when it has fewer available replicas than desired, its rollout stalled or a pod is
+// crash looping; a missing deployment only degrades Flux for required controllers.
+func fluxControllerStatus(ctx context.Context, typedClient kubernetes.Interface, namespace, name string) (*FluxControllerStatus, error) {
+	status := &FluxControllerStatus{Name: name, Required: requiredFluxControllers[name]}
+
+	deployment, err := typedClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get deployment %s: %w", name, err)
+		}
+		status.Message = "deployment not found"
+		status.Degraded = status.Required
+		return status, nil
+	}
+
+	status.Installed = true
+	if deployment.Spec.Replicas != nil {
+		status.Replicas = *deployment.Spec.Replicas
+	}
+	status.ReadyReplicas = deployment.Status.AvailableReplicas
+	status.Ready = status.Replicas > 0 && status.ReadyReplicas >= status.Replicas
+	status.Version = controllerVersion(deployment)
+	if !status.Ready {
+		status.Message = fmt.Sprintf("%d/%d replicas available", status.ReadyReplicas, status.Replicas)
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			status.Reason = condition.Reason
+			status.Message = condition.Message
+		}
+	}
+
+	// Pods show why replicas are unavailable, and a pod that restarted after passing its
+	// readiness probe can still be crash looping while the deployment reports available
+	if deployment.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector on deployment %s: %w", name, err)
+		}
+		pods, err := typedClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods of %s: %w", name, err)
+		}
+		for _, pod := range pods.Items {
+			for _, container := range pod.Status.ContainerStatuses {
+				status.Restarts += container.RestartCount
+				if reason, message := containerFailure(container); reason != "" && status.Reason == "" {
+					status.Reason = reason
+					status.Message = fmt.Sprintf("pod %s: %s", pod.Name, message)
+				}
+			}
+		}
+	}
+
+	status.Degraded = !status.Ready || status.Reason != ""
+	return status, nil
+}
+
+// controllerImage returns the image of the manager container, or of the first container
+// when the deployment has no container named manager
+func controllerImage(deployment *appsv1.Deployment) string {
+	containers := deployment.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return ""
+	}
+	image := containers[0].Image
+	for _, container := range containers {
+		if container.Name == "manager" {
+			image = container.Image
+		}
+	}
+	return image
+}
+
+// controllerVersion returns the tag of the controller's image
+func controllerVersion(deployment *appsv1.Deployment) string {
+	image, _, _ := strings.Cut(controllerImage(deployment), "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// containerFailure reports a container stuck waiting to restart or pull its image, or
+// one whose last run was killed for running out of memory
+func containerFailure(container corev1.ContainerStatus) (string, string) {
+	if waiting := container.State.Waiting; waiting != nil {
+		switch waiting.Reason {
+		case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError":
+			message := waiting.Reason
+			if waiting.Message != "" {
+				message = waiting.Message
+			}
+			return waiting.Reason, message
+		}
+	}
+	if terminated := container.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" && !container.Ready {
+		return terminated.Reason, fmt.Sprintf("container %s was OOMKilled", container.Name)
+	}
+	return "", ""
+}
@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// fluxVersionLabel is set by flux install and bootstrap on the Flux namespace, CRDs and
+// controller deployments to the version of the Flux distribution
+const fluxVersionLabel = "app.kubernetes.io/version"
+
+// optionalFluxComponents are installed only with flux install --components-extra
+var optionalFluxComponents = []string{
+	"image-reflector-controller",
+	"image-automation-controller",
+}
+
+// FluxComponent is a Flux controller and the image it runs
+type FluxComponent struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Image     string `json:"image,omitempty"`
+	Version   string `json:"version,omitempty"` // image tag, e.g. v1.3.0
+}
+
+// FluxAPIGroup is a Flux API group and the versions the cluster serves it under
+type FluxAPIGroup struct {
+	Group     string   `json:"group"`
+	Preferred string   `json:"preferred"`
+	Versions  []string `json:"versions"`
+}
+
+// FluxVersion reports the Flux distribution installed on a cluster and its components
+type FluxVersion struct {
+	Namespace    string          `json:"namespace"`
+	Distribution string          `json:"distribution,omitempty"` // e.g. v2.3.0; empty when Flux was not installed by the flux CLI
+	Components   []FluxComponent `json:"components"`
+	APIs         []FluxAPIGroup  `json:"apis"`
+}
+
+// GetFluxVersion reads the Flux distribution version from the labels flux install sets,
+// the image tag of every controller and the API versions the Flux groups are served at
+func (c *Client) GetFluxVersion(ctx context.Context, clusterID string) (*FluxVersion, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	version := &FluxVersion{
+		Namespace:  fluxNamespace(),
+		Components: []FluxComponent{},
+		APIs:       []FluxAPIGroup{},
+	}
+	distribution, err := fluxDistributionVersion(ctx, typedClient, version.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	version.Distribution = distribution
+
+	for _, name := range append(append([]string{}, FluxControllers...), optionalFluxComponents...) {
+		component := FluxComponent{Name: name}
+		deployment, err := typedClient.AppsV1().Deployments(version.Namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return nil, fmt.Errorf("failed to get deployment %s: %w", name, err)
+		default:
+			component.Installed = true
+			component.Image = controllerImage(deployment)
+			component.Version = controllerVersion(deployment)
+		}
+		version.Components = append(version.Components, component)
+	}
+
+	groups, err := typedClient.Discovery().ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover API groups: %w", err)
+	}
+	fluxGroups := make(map[string]bool)
+	for _, kind := range fluxKinds {
+		fluxGroups[kind.Group] = true
+	}
+	fluxGroups["image.toolkit.fluxcd.io"] = true
+	for _, group := range groups.Groups {
+		if !fluxGroups[group.Name] {
+			continue
+		}
+		api := FluxAPIGroup{Group: group.Name, Preferred: group.PreferredVersion.Version, Versions: []string{}}
+		for _, v := range group.Versions {
+			api.Versions = append(api.Versions, v.Version)
+		}
+		version.APIs = append(version.APIs, api)
+	}
+	sort.Slice(version.APIs, func(i, j int) bool { return version.APIs[i].Group < version.APIs[j].Group })
+
+	return version, nil
+}
+
+// fluxDistributionVersion reads the Flux version label from the Flux namespace, or from
+// the controller deployments when the namespace was created separately
+func fluxDistributionVersion(ctx context.Context, typedClient kubernetes.Interface, namespace string) (string, error) {
+	ns, err := typedClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+		return "", fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	if err == nil && ns.Labels[fluxVersionLabel] != "" {
+		return ns.Labels[fluxVersionLabel], nil
+	}
+
+	deployments, err := typedClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/part-of=flux"})
+	if err != nil {
+		return "", fmt.Errorf("failed to list deployments in %s: %w", namespace, err)
+	}
+	for _, deployment := range deployments.Items {
+		if version := deployment.Labels[fluxVersionLabel]; version != "" {
+			return version, nil
+		}
+	}
+	return "", nil
+}
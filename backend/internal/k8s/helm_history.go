@@ -0,0 +1,285 @@
+package k8s
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// helmReleaseSecretType is the type of the Secrets Helm stores release revisions in
+const helmReleaseSecretType = "helm.sh/release.v1"
+
+// gzipMagic prefixes gzip-compressed release data
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// HelmRevision is one revision of a Helm release
+type HelmRevision struct {
+	Revision      int                    `json:"revision"`
+	Status        string                 `json:"status"` // deployed, superseded, failed, pending-upgrade, ...
+	ChartName     string                 `json:"chart_name"`
+	ChartVersion  string                 `json:"chart_version"`
+	AppVersion    string                 `json:"app_version,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	FirstDeployed string                 `json:"first_deployed,omitempty"`
+	LastDeployed  string                 `json:"last_deployed,omitempty"`
+	Values        map[string]interface{} `json:"values,omitempty"` // user-supplied values of the revision
+}
+
+// HelmReleaseHistory lists the revisions of the Helm release behind a HelmRelease, newest first
+type HelmReleaseHistory struct {
+	Namespace        string         `json:"namespace"`
+	Name             string         `json:"name"`
+	ReleaseName      string         `json:"release_name"`
+	StorageNamespace string         `json:"storage_namespace"`
+	Revisions        []HelmRevision `json:"revisions"`
+	ValuesRedacted   bool           `json:"values_redacted"`
+}
+
+// HelmRollbackResult describes how a HelmRelease was pointed back at an earlier revision
+type HelmRollbackResult struct {
+	Revision       int    `json:"revision"`
+	ChartVersion   string `json:"chart_version"`
+	ValuesRestored bool   `json:"values_restored"`
+	Message        string `json:"message"`
+}
+
+// helmReleaseRecord is the part of Helm's stored release record that is read
+type helmReleaseRecord struct {
+	Version int `json:"version"`
+	Info    struct {
+		Status        string `json:"status"`
+		Description   string `json:"description"`
+		FirstDeployed string `json:"first_deployed"`
+		LastDeployed  string `json:"last_deployed"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// GetHelmReleaseHistory reads the revisions of a HelmRelease's Helm release from Helm's
+// storage Secrets. Revision values are only included with includeValues, since they may
+// hold values that came from Secrets.
+func (c *Client) GetHelmReleaseHistory(ctx context.Context, clusterID, namespace, name string, includeValues bool) (*HelmReleaseHistory, error) {
+	release, err := c.getHelmRelease(ctx, clusterID, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	releaseName, storageNamespace := helmStorageLocation(release)
+
+	revisions, err := c.listHelmRevisions(ctx, clusterID, storageNamespace, releaseName)
+	if err != nil {
+		return nil, err
+	}
+	if !includeValues {
+		for i := range revisions {
+			revisions[i].Values = nil
+		}
+	}
+
+	return &HelmReleaseHistory{
+		Namespace:        namespace,
+		Name:             name,
+		ReleaseName:      releaseName,
+		StorageNamespace: storageNamespace,
+		Revisions:        revisions,
+		ValuesRedacted:   !includeValues,
+	}, nil
+}
+
+// RollbackHelmRelease points a HelmRelease back at an earlier revision and requests a
+// reconcile: the chart version is pinned to the revision's, and when the HelmRelease has no
+// valuesFrom references its inline values are restored too. Values of releases using
+// valuesFrom are left alone, since the revision's values would copy referenced Secret data
+// into the HelmRelease.
+func (c *Client) RollbackHelmRelease(ctx context.Context, clusterID, namespace, name string, revision int) (*HelmRollbackResult, error) {
+	release, err := c.getHelmRelease(ctx, clusterID, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, found, _ := unstructured.NestedMap(release.Object, "spec", "chart", "spec"); !found {
+		return nil, fmt.Errorf("rollback needs a HelmRelease with spec.chart; releases using spec.chartRef follow their source")
+	}
+
+	releaseName, storageNamespace := helmStorageLocation(release)
+	revisions, err := c.listHelmRevisions(ctx, clusterID, storageNamespace, releaseName)
+	if err != nil {
+		return nil, err
+	}
+	var target *HelmRevision
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			target = &revisions[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("revision %d of release %s not found", revision, releaseName)
+	}
+
+	result := &HelmRollbackResult{Revision: revision, ChartVersion: target.ChartVersion}
+	if err := unstructured.SetNestedField(release.Object, target.ChartVersion, "spec", "chart", "spec", "version"); err != nil {
+		return nil, fmt.Errorf("failed to set chart version: %w", err)
+	}
+	if refs, _, _ := unstructured.NestedSlice(release.Object, "spec", "valuesFrom"); len(refs) == 0 {
+		values := target.Values
+		if values == nil {
+			values = map[string]interface{}{}
+		}
+		if err := unstructured.SetNestedMap(release.Object, values, "spec", "values"); err != nil {
+			return nil, fmt.Errorf("failed to set values: %w", err)
+		}
+		result.ValuesRestored = true
+		result.Message = fmt.Sprintf("Pinned chart %s to %s and restored the values of revision %d", target.ChartName, target.ChartVersion, revision)
+	} else {
+		result.Message = fmt.Sprintf("Pinned chart %s to %s; values were not restored because the HelmRelease uses valuesFrom", target.ChartName, target.ChartVersion)
+	}
+
+	annotations := release.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations["reconcile.fluxcd.io/requestedAt"] = time.Now().Format(time.RFC3339)
+	release.SetAnnotations(annotations)
+	applyProvenance(ctx, release)
+
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := c.getGVRForKind(clusterID, "HelmRelease")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.Resource(gvr).Namespace(namespace).Update(ctx, release, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to update helm release: %w", err)
+	}
+	return result, nil
+}
+
+// getHelmRelease fetches a HelmRelease object
+func (c *Client) getHelmRelease(ctx context.Context, clusterID, namespace, name string) (*unstructured.Unstructured, error) {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := c.getGVRForKind(clusterID, "HelmRelease")
+	if err != nil {
+		return nil, err
+	}
+	release, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get helm release: %w", err)
+	}
+	return release, nil
+}
+
+// helmStorageLocation returns the Helm release name and the namespace its revisions are
+// stored in. The helm-controller records both in status.history; before the first install
+// they follow the HelmRelease spec.
+func helmStorageLocation(release *unstructured.Unstructured) (string, string) {
+	releaseName, _, _ := unstructured.NestedString(release.Object, "spec", "releaseName")
+	targetNamespace, _, _ := unstructured.NestedString(release.Object, "spec", "targetNamespace")
+	storageNamespace, _, _ := unstructured.NestedString(release.Object, "status", "storageNamespace")
+	if storageNamespace == "" {
+		storageNamespace, _, _ = unstructured.NestedString(release.Object, "spec", "storageNamespace")
+	}
+	if storageNamespace == "" {
+		storageNamespace = targetNamespace
+	}
+	if storageNamespace == "" {
+		storageNamespace = release.GetNamespace()
+	}
+
+	if history, _, _ := unstructured.NestedSlice(release.Object, "status", "history"); len(history) > 0 {
+		if latest, ok := history[0].(map[string]interface{}); ok {
+			if name, _ := latest["name"].(string); name != "" {
+				return name, storageNamespace
+			}
+		}
+	}
+	if releaseName == "" {
+		releaseName = release.GetName()
+		if targetNamespace != "" {
+			releaseName = targetNamespace + "-" + releaseName
+		}
+	}
+	return releaseName, storageNamespace
+}
+
+// listHelmRevisions decodes every stored revision of a Helm release, newest first
+func (c *Client) listHelmRevisions(ctx context.Context, clusterID, storageNamespace, releaseName string) ([]HelmRevision, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	secrets, err := typedClient.CoreV1().Secrets(storageNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{"owner": "helm", "name": releaseName}.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list helm release secrets: %w", err)
+	}
+
+	revisions := []HelmRevision{}
+	for _, secret := range secrets.Items {
+		if secret.Type != helmReleaseSecretType {
+			continue
+		}
+		record, err := decodeHelmRelease(secret.Data["release"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", secret.Name, err)
+		}
+		revisions = append(revisions, HelmRevision{
+			Revision:      record.Version,
+			Status:        record.Info.Status,
+			ChartName:     record.Chart.Metadata.Name,
+			ChartVersion:  record.Chart.Metadata.Version,
+			AppVersion:    record.Chart.Metadata.AppVersion,
+			Description:   record.Info.Description,
+			FirstDeployed: record.Info.FirstDeployed,
+			LastDeployed:  record.Info.LastDeployed,
+			Values:        record.Config,
+		})
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision > revisions[j].Revision })
+	return revisions, nil
+}
+
+// decodeHelmRelease decodes a release record the way Helm stores it in a Secret: JSON,
+// usually gzipped, then base64-encoded on top of the Secret's own encoding
+func decodeHelmRelease(data []byte) (*helmReleaseRecord, error) {
+	raw, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(raw, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if raw, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+	var record helmReleaseRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Annotations helm-controller honors alongside reconcile.fluxcd.io/requestedAt; each only
+// takes effect when its value matches the request token
+const (
+	helmForceAnnotation = "reconcile.fluxcd.io/forceAt"
+	helmResetAnnotation = "reconcile.fluxcd.io/resetAt"
+)
+
+type forceContextKey struct{}
+
+// WithForce returns a context whose reconcile requests of HelmReleases also set
+// reconcile.fluxcd.io/forceAt, like flux reconcile helmrelease --force. Other kinds ignore it.
+func WithForce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceContextKey{}, true)
+}
+
+// ForceFromContext reports whether reconcile requests made with the context are forced
+func ForceFromContext(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceContextKey{}).(bool)
+	return forced
+}
+
+// HelmRemediationResult describes a force or reset request sent to a HelmRelease. The
+// failure counts and retries are those before the request; retries of -1 retry forever.
+type HelmRemediationResult struct {
+	RequestedAt     string `json:"requested_at"`
+	Forced          bool   `json:"forced"`
+	Reset           bool   `json:"reset"`
+	Failures        int64  `json:"failures"`
+	InstallFailures int64  `json:"install_failures"`
+	UpgradeFailures int64  `json:"upgrade_failures"`
+	InstallRetries  int64  `json:"install_retries"`
+	UpgradeRetries  int64  `json:"upgrade_retries"`
+	Message         string `json:"message"`
+}
+
+// ForceReconcileHelmRelease requests a reconcile that runs a Helm install or upgrade even
+// when nothing changed, like flux reconcile helmrelease --force
+func (c *Client) ForceReconcileHelmRelease(ctx context.Context, clusterID, namespace, name string) (*HelmRemediationResult, error) {
+	result, err := c.requestHelmRemediation(ctx, clusterID, namespace, name, helmForceAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	result.Forced = true
+	result.Message = fmt.Sprintf("Requested a forced Helm install or upgrade of %s/%s", namespace, name)
+	return result, nil
+}
+
+// ResetHelmReleaseFailures requests a reconcile that first clears the install and upgrade
+// failure counts, so a release that used up its remediation retries is attempted again,
+// like flux reconcile helmrelease --reset
+func (c *Client) ResetHelmReleaseFailures(ctx context.Context, clusterID, namespace, name string) (*HelmRemediationResult, error) {
+	result, err := c.requestHelmRemediation(ctx, clusterID, namespace, name, helmResetAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	result.Reset = true
+	if result.Failures+result.InstallFailures+result.UpgradeFailures == 0 {
+		result.Message = fmt.Sprintf("Requested a reconcile of %s/%s; it had no failures to reset", namespace, name)
+	} else {
+		result.Message = fmt.Sprintf("Reset %d install and %d upgrade failures of %s/%s and requested a reconcile",
+			result.InstallFailures, result.UpgradeFailures, namespace, name)
+	}
+	return result, nil
+}
+
+// requestHelmRemediation sets both the reconcile request annotation and the given one to
+// the same token on a HelmRelease and reports its failure counts before the request
+func (c *Client) requestHelmRemediation(ctx context.Context, clusterID, namespace, name, annotation string) (*HelmRemediationResult, error) {
+	release, err := c.getHelmRelease(ctx, clusterID, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if suspended, _, _ := unstructured.NestedBool(release.Object, "spec", "suspend"); suspended {
+		return nil, fmt.Errorf("helm release %s/%s is suspended; resume it first", namespace, name)
+	}
+
+	result := &HelmRemediationResult{
+		RequestedAt:    time.Now().Format(time.RFC3339Nano),
+		InstallRetries: remediationRetries(release, "install"),
+		UpgradeRetries: remediationRetries(release, "upgrade"),
+	}
+	result.Failures, _, _ = unstructured.NestedInt64(release.Object, "status", "failures")
+	result.InstallFailures, _, _ = unstructured.NestedInt64(release.Object, "status", "installFailures")
+	result.UpgradeFailures, _, _ = unstructured.NestedInt64(release.Object, "status", "upgradeFailures")
+
+	annotations := release.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations["reconcile.fluxcd.io/requestedAt"] = result.RequestedAt
+	annotations[annotation] = result.RequestedAt
+	release.SetAnnotations(annotations)
+	applyProvenance(ctx, release)
+
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := c.getGVRForKind(clusterID, "HelmRelease")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.Resource(gvr).Namespace(namespace).Update(ctx, release, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to update helm release: %w", err)
+	}
+	return result, nil
+}
+
+// remediationRetries reads spec.<action>.remediation.retries, which defaults to 0
+func remediationRetries(release *unstructured.Unstructured, action string) int64 {
+	retries, _, _ := unstructured.NestedInt64(release.Object, "spec", action, "remediation", "retries")
+	return retries
+}
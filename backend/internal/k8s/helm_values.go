@@ -0,0 +1,260 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultValuesKey is the key read from a valuesFrom reference when valuesKey is unset
+const defaultValuesKey = "values.yaml"
+
+// redactedValue replaces values that came from a Secret when they are not revealed
+const redactedValue = "**redacted**"
+
+// ValuesSource is one input to the effective values of a HelmRelease: a valuesFrom
+// reference or the inline spec.values, which is always last
+type ValuesSource struct {
+	Kind       string `json:"kind"` // ConfigMap, Secret, HelmRelease (inline spec.values)
+	Name       string `json:"name"`
+	ValuesKey  string `json:"values_key,omitempty"`
+	TargetPath string `json:"target_path,omitempty"`
+	Optional   bool   `json:"optional,omitempty"`
+	Status     string `json:"status"` // applied, skipped (optional and missing), error
+	Error      string `json:"error,omitempty"`
+}
+
+// ValueOrigin is a leaf of the effective values and the sources that set it, as indexes
+// into the sources list
+type ValueOrigin struct {
+	Path       string `json:"path"`
+	Source     int    `json:"source"`
+	Overridden []int  `json:"overridden,omitempty"`
+}
+
+// HelmReleaseValues are the values a HelmRelease passes to Helm, merged the way the Helm
+// controller merges them, with the origin of every key
+type HelmReleaseValues struct {
+	Namespace string                 `json:"namespace"`
+	Name      string                 `json:"name"`
+	Values    map[string]interface{} `json:"values"`
+	Sources   []ValuesSource         `json:"sources"`
+	Keys      []ValueOrigin          `json:"keys"`
+	Redacted  bool                   `json:"redacted"`
+}
+
+// GetHelmReleaseValues resolves the valuesFrom references of a HelmRelease and merges them
+// with its inline values. References are merged in order and spec.values last, so later
+// sources win. Unless revealSecrets is set, values that came from Secrets are redacted.
+// Missing or unreadable references are reported on their source instead of failing.
+func (c *Client) GetHelmReleaseValues(ctx context.Context, clusterID, namespace, name string, revealSecrets bool) (*HelmReleaseValues, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	release, err := c.getHelmRelease(ctx, clusterID, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	merger := newValuesMerger()
+	refs, _, _ := unstructured.NestedSlice(release.Object, "spec", "valuesFrom")
+	for _, item := range refs {
+		ref, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		source := ValuesSource{Status: "applied"}
+		source.Kind, _ = ref["kind"].(string)
+		source.Name, _ = ref["name"].(string)
+		source.ValuesKey, _ = ref["valuesKey"].(string)
+		source.TargetPath, _ = ref["targetPath"].(string)
+		source.Optional, _ = ref["optional"].(bool)
+		if source.ValuesKey == "" {
+			source.ValuesKey = defaultValuesKey
+		}
+
+		var data map[string][]byte
+		switch source.Kind {
+		case "ConfigMap":
+			configMap, getErr := typedClient.CoreV1().ConfigMaps(namespace).Get(ctx, source.Name, metav1.GetOptions{})
+			err = getErr
+			if err == nil {
+				data = make(map[string][]byte, len(configMap.Data))
+				for key, value := range configMap.Data {
+					data[key] = []byte(value)
+				}
+			}
+		case "Secret":
+			secret, getErr := typedClient.CoreV1().Secrets(namespace).Get(ctx, source.Name, metav1.GetOptions{})
+			err = getErr
+			if err == nil {
+				data = secret.Data
+			}
+		default:
+			err = fmt.Errorf("unsupported kind %q", source.Kind)
+		}
+
+		value, found := data[source.ValuesKey]
+		switch {
+		case apierrors.IsNotFound(err) && source.Optional:
+			source.Status = "skipped"
+		case err != nil:
+			source.Status, source.Error = "error", err.Error()
+		case !found && source.Optional:
+			source.Status = "skipped"
+		case !found:
+			source.Status, source.Error = "error", fmt.Sprintf("key %s not found in %s %s", source.ValuesKey, source.Kind, source.Name)
+		case source.TargetPath != "":
+			merger.set(strings.Split(source.TargetPath, "."), string(value), len(merger.sources))
+		default:
+			var values map[string]interface{}
+			if err := yaml.Unmarshal(value, &values); err != nil {
+				source.Status, source.Error = "error", fmt.Sprintf("failed to parse %s: %v", source.ValuesKey, err)
+				break
+			}
+			merger.merge(values, len(merger.sources))
+		}
+		merger.sources = append(merger.sources, source)
+	}
+
+	inline, _, _ := unstructured.NestedMap(release.Object, "spec", "values")
+	merger.merge(inline, len(merger.sources))
+	merger.sources = append(merger.sources, ValuesSource{Kind: "HelmRelease", Name: name, Status: "applied"})
+
+	result := &HelmReleaseValues{
+		Namespace: namespace,
+		Name:      name,
+		Values:    merger.values,
+		Sources:   merger.sources,
+		Keys:      merger.keys(),
+	}
+	if !revealSecrets {
+		for _, key := range result.Keys {
+			if result.Sources[key.Source].Kind == "Secret" {
+				setValuePath(result.Values, strings.Split(key.Path, "."), redactedValue)
+				result.Redacted = true
+			}
+		}
+	}
+	return result, nil
+}
+
+// HasSecretValues reports whether any effective value came from a Secret
+func (v *HelmReleaseValues) HasSecretValues() bool {
+	for _, key := range v.Keys {
+		if v.Sources[key.Source].Kind == "Secret" {
+			return true
+		}
+	}
+	return false
+}
+
+// valuesMerger merges values like Helm does, remembering which source set each leaf
+type valuesMerger struct {
+	values     map[string]interface{}
+	sources    []ValuesSource
+	origins    map[string]int
+	overridden map[string][]int
+}
+
+func newValuesMerger() *valuesMerger {
+	return &valuesMerger{
+		values:     map[string]interface{}{},
+		origins:    map[string]int{},
+		overridden: map[string][]int{},
+	}
+}
+
+// merge deep-merges values into the result: maps are merged key by key, anything else
+// replaces what was there
+func (m *valuesMerger) merge(values map[string]interface{}, source int) {
+	m.mergeInto(m.values, values, nil, source)
+}
+
+func (m *valuesMerger) mergeInto(dst, src map[string]interface{}, path []string, source int) {
+	for key, value := range src {
+		keyPath := append(append([]string{}, path...), key)
+		if srcMap, ok := value.(map[string]interface{}); ok {
+			if dstMap, ok := dst[key].(map[string]interface{}); ok {
+				m.mergeInto(dstMap, srcMap, keyPath, source)
+				continue
+			}
+		}
+		m.replace(keyPath, source)
+		dst[key] = value
+		m.record(keyPath, value, source)
+	}
+}
+
+// set places a single value at path, as a valuesFrom targetPath does
+func (m *valuesMerger) set(path []string, value interface{}, source int) {
+	m.replace(path, source)
+	setValuePath(m.values, path, value)
+	m.record(path, value, source)
+}
+
+// replace forgets the origins of everything at or below path, and of a scalar above it,
+// noting them as overridden by source
+func (m *valuesMerger) replace(path []string, source int) {
+	prefix := strings.Join(path, ".")
+	for leaf, origin := range m.origins {
+		if leaf != prefix && !strings.HasPrefix(leaf, prefix+".") && !strings.HasPrefix(prefix, leaf+".") {
+			continue
+		}
+		delete(m.origins, leaf)
+		if origin != source {
+			m.overridden[prefix] = append(m.overridden[prefix], origin)
+		}
+		if leaf != prefix {
+			delete(m.overridden, leaf)
+		}
+	}
+}
+
+// record marks every leaf of value, placed at path, as set by source
+func (m *valuesMerger) record(path []string, value interface{}, source int) {
+	if values, ok := value.(map[string]interface{}); ok && len(values) > 0 {
+		for key, child := range values {
+			m.record(append(append([]string{}, path...), key), child, source)
+		}
+		return
+	}
+	m.origins[strings.Join(path, ".")] = source
+}
+
+// keys lists every leaf with its origin, sorted by path
+func (m *valuesMerger) keys() []ValueOrigin {
+	keys := make([]ValueOrigin, 0, len(m.origins))
+	for path, source := range m.origins {
+		key := ValueOrigin{Path: path, Source: source}
+		for prefix, overridden := range m.overridden {
+			if prefix == path || strings.HasPrefix(path, prefix+".") {
+				key.Overridden = append(key.Overridden, overridden...)
+			}
+		}
+		sort.Ints(key.Overridden)
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Path < keys[j].Path })
+	return keys
+}
+
+// setValuePath sets value at path, creating or replacing intermediate maps
+func setValuePath(values map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			values[key] = next
+		}
+		values = next
+	}
+	values[path[len(path)-1]] = value
+}
@@ -0,0 +1,369 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Freshness of a running image
+const (
+	ImageUpToDate = "up_to_date"
+	ImageOutdated = "outdated"
+	ImageUnknown  = "unknown" // the tag is not a version, or the image is pinned by digest only
+	ImageError    = "error"   // the registry could not be read
+)
+
+// registryConcurrency bounds the repositories whose tags are listed at once
+const registryConcurrency = 8
+
+// ImageStatus compares an image a workload runs with the newest tag in its registry
+type ImageStatus struct {
+	Workload   EventObject `json:"workload"`
+	Container  string      `json:"container"`
+	Image      string      `json:"image"`
+	Repository string      `json:"repository"`
+	Tag        string      `json:"tag,omitempty"`
+	Digest     string      `json:"digest,omitempty"`
+	LatestTag  string      `json:"latest_tag,omitempty"`
+	Status     string      `json:"status"`
+	Message    string      `json:"message,omitempty"`
+}
+
+// ImageOwner groups the images of the workloads a Kustomization or HelmRelease applied
+type ImageOwner struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace"`
+	Name      string        `json:"name"`
+	Images    []ImageStatus `json:"images"`
+	Outdated  int           `json:"outdated"`
+}
+
+// ImageFreshnessReport lists the images of every Flux-managed workload of a cluster by owner
+type ImageFreshnessReport struct {
+	Owners   []ImageOwner `json:"owners"`
+	Images   int          `json:"images"`
+	Outdated int          `json:"outdated"`
+	Warnings []string     `json:"warnings"`
+}
+
+// imageWorkload is a workload's pod template with the Flux resource that applied it
+type imageWorkload struct {
+	object EventObject
+	owner  EventObject
+	spec   corev1.PodSpec
+}
+
+// GetImageFreshness compares the image tag of every container of the Deployments,
+// StatefulSets, DaemonSets and CronJobs applied by a Kustomization or HelmRelease with the
+// newest tag of the same form in its registry. Registries are read with the image pull
+// Secrets of the workload and of its service account, or else the stored registry credential.
+func (c *Client) GetImageFreshness(ctx context.Context, clusterID string) (*ImageFreshnessReport, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	workloads, err := listImageWorkloads(ctx, typedClient)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImageFreshnessReport{Owners: []ImageOwner{}, Warnings: []string{}}
+	secrets := newPullSecretCache(typedClient)
+	type lookup struct {
+		ref        imageRef
+		credential *registryCredential
+		tags       []string
+		err        error
+	}
+	lookups := map[string]*lookup{}
+	owners := map[EventObject]*ImageOwner{}
+	// keys holds the lookup key of each image of an owner, by index
+	keys := map[EventObject][]string{}
+
+	for _, workload := range workloads {
+		owner, ok := owners[workload.owner]
+		if !ok {
+			owner = &ImageOwner{Kind: workload.owner.Kind, Namespace: workload.owner.Namespace, Name: workload.owner.Name, Images: []ImageStatus{}}
+			owners[workload.owner] = owner
+		}
+		credentials := secrets.credentials(ctx, workload.object.Namespace, workload.spec)
+
+		containers := append(append([]corev1.Container{}, workload.spec.InitContainers...), workload.spec.Containers...)
+		for _, container := range containers {
+			status := ImageStatus{Workload: workload.object, Container: container.Name, Image: container.Image}
+			ref, err := parseImageRef(container.Image)
+			if err != nil {
+				status.Status, status.Message = ImageError, err.Error()
+				owner.Images = append(owner.Images, status)
+				keys[workload.owner] = append(keys[workload.owner], "")
+				continue
+			}
+			status.Repository = ref.Domain + "/" + ref.Repository
+			status.Tag, status.Digest = ref.Tag, ref.Digest
+
+			credential, hasCredential := credentials[ref.Domain]
+			if !hasCredential {
+				credential, hasCredential = c.registries.get(ref.Domain)
+			}
+			key := status.Repository
+			if hasCredential {
+				key += "|" + credential.Source
+			}
+			if _, ok := lookups[key]; !ok {
+				l := &lookup{ref: ref}
+				if hasCredential {
+					l.credential = &credential
+				}
+				lookups[key] = l
+			}
+			owner.Images = append(owner.Images, status)
+			keys[workload.owner] = append(keys[workload.owner], key)
+		}
+	}
+	report.Warnings = append(report.Warnings, secrets.warnings...)
+
+	registry := newRegistryClient()
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, registryConcurrency)
+	for _, l := range lookups {
+		wg.Add(1)
+		go func(l *lookup) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			l.tags, l.err = registry.listTags(ctx, l.ref, l.credential)
+		}(l)
+	}
+	wg.Wait()
+
+	for ownerKey, owner := range owners {
+		for i := range owner.Images {
+			status := &owner.Images[i]
+			if status.Status == ImageError {
+				continue
+			}
+			l := lookups[keys[ownerKey][i]]
+			switch {
+			case l.err != nil:
+				status.Status, status.Message = ImageError, l.err.Error()
+			case status.Tag == "":
+				status.Status, status.Message = ImageUnknown, "pinned by digest without a tag"
+			default:
+				status.LatestTag, status.Status, status.Message = newestTag(status.Tag, l.tags)
+			}
+			if status.Status == ImageOutdated {
+				owner.Outdated++
+			}
+		}
+		report.Images += len(owner.Images)
+		report.Outdated += owner.Outdated
+		report.Owners = append(report.Owners, *owner)
+	}
+
+	sort.Slice(report.Owners, func(i, j int) bool {
+		a, b := report.Owners[i], report.Owners[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+// tagVersionRE splits a tag such as v1.25.3-alpine into its prefix, version numbers and suffix
+var tagVersionRE = regexp.MustCompile(`^(v?)([0-9]+(?:\.[0-9]+)*)(.*)$`)
+
+// tagVersion is a tag parsed as a version. Tags only compare with tags of the same form:
+// the same prefix, as many numbers and the same suffix, so 1.25-alpine is only
+// compared with other -alpine tags and stable releases never with pre-releases.
+type tagVersion struct {
+	form    string
+	numbers []uint64
+}
+
+func parseTagVersion(tag string) (tagVersion, bool) {
+	parts := tagVersionRE.FindStringSubmatch(tag)
+	if parts == nil {
+		return tagVersion{}, false
+	}
+	var version tagVersion
+	for _, part := range strings.Split(parts[2], ".") {
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return tagVersion{}, false
+		}
+		version.numbers = append(version.numbers, number)
+	}
+	version.form = fmt.Sprintf("%s|%d|%s", parts[1], len(version.numbers), parts[3])
+	return version, true
+}
+
+// newer reports whether v is a higher version than other of the same form
+func (v tagVersion) newer(other tagVersion) bool {
+	for i := range v.numbers {
+		if v.numbers[i] != other.numbers[i] {
+			return v.numbers[i] > other.numbers[i]
+		}
+	}
+	return false
+}
+
+// newestTag returns the newest tag of the same form as current among tags, with the
+// freshness of current and a message when it cannot be judged
+func newestTag(current string, tags []string) (string, string, string) {
+	currentVersion, ok := parseTagVersion(current)
+	if !ok {
+		return "", ImageUnknown, fmt.Sprintf("tag %s is not a version", current)
+	}
+
+	newest, newestVersion := current, currentVersion
+	for _, tag := range tags {
+		version, ok := parseTagVersion(tag)
+		if ok && version.form == currentVersion.form && version.newer(newestVersion) {
+			newest, newestVersion = tag, version
+		}
+	}
+	if newest == current {
+		return current, ImageUpToDate, ""
+	}
+	return newest, ImageOutdated, ""
+}
+
+// listImageWorkloads lists the pod templates of the workloads applied by a Flux resource
+func listImageWorkloads(ctx context.Context, typedClient kubernetes.Interface) ([]imageWorkload, error) {
+	opts := metav1.ListOptions{}
+	var workloads []imageWorkload
+	add := func(kind string, meta metav1.ObjectMeta, spec corev1.PodSpec) {
+		owner, ok := fluxOwner(meta.Labels)
+		if !ok {
+			return
+		}
+		workloads = append(workloads, imageWorkload{
+			object: EventObject{Kind: kind, Namespace: meta.Namespace, Name: meta.Name},
+			owner:  owner,
+			spec:   spec,
+		})
+	}
+
+	deployments, err := typedClient.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		add("Deployment", d.ObjectMeta, d.Spec.Template.Spec)
+	}
+
+	statefulSets, err := typedClient.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		add("StatefulSet", s.ObjectMeta, s.Spec.Template.Spec)
+	}
+
+	daemonSets, err := typedClient.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		add("DaemonSet", d.ObjectMeta, d.Spec.Template.Spec)
+	}
+
+	cronJobs, err := typedClient.BatchV1().CronJobs(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, j := range cronJobs.Items {
+		add("CronJob", j.ObjectMeta, j.Spec.JobTemplate.Spec.Template.Spec)
+	}
+
+	return workloads, nil
+}
+
+// fluxOwner returns the Flux resource whose ownership labels an object carries. A HelmRelease
+// wins over a Kustomization, since a Kustomization may apply the HelmRelease, not the object.
+func fluxOwner(objectLabels map[string]string) (EventObject, bool) {
+	for _, kind := range []string{"HelmRelease", "Kustomization"} {
+		keys := fluxOwnerLabels[kind]
+		if name := objectLabels[keys[0]]; name != "" {
+			return EventObject{Kind: kind, Namespace: objectLabels[keys[1]], Name: name}, true
+		}
+	}
+	return EventObject{}, false
+}
+
+// pullSecretCache reads image pull Secrets and service accounts once per report
+type pullSecretCache struct {
+	client          kubernetes.Interface
+	secrets         map[string]map[string]registryCredential
+	serviceAccounts map[string][]corev1.LocalObjectReference
+	warnings        []string
+}
+
+func newPullSecretCache(client kubernetes.Interface) *pullSecretCache {
+	return &pullSecretCache{
+		client:          client,
+		secrets:         map[string]map[string]registryCredential{},
+		serviceAccounts: map[string][]corev1.LocalObjectReference{},
+	}
+}
+
+// credentials returns the registry credentials a pod template pulls with, by registry host.
+// The pod's own pull Secrets come before its service account's.
+func (p *pullSecretCache) credentials(ctx context.Context, namespace string, spec corev1.PodSpec) map[string]registryCredential {
+	serviceAccount := spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	saKey := namespace + "/" + serviceAccount
+	saSecrets, ok := p.serviceAccounts[saKey]
+	if !ok {
+		if sa, err := p.client.CoreV1().ServiceAccounts(namespace).Get(ctx, serviceAccount, metav1.GetOptions{}); err == nil {
+			saSecrets = sa.ImagePullSecrets
+		}
+		p.serviceAccounts[saKey] = saSecrets
+	}
+
+	credentials := map[string]registryCredential{}
+	for _, ref := range append(append([]corev1.LocalObjectReference{}, spec.ImagePullSecrets...), saSecrets...) {
+		for host, credential := range p.secret(ctx, namespace, ref.Name) {
+			if _, ok := credentials[host]; !ok {
+				credentials[host] = credential
+			}
+		}
+	}
+	return credentials
+}
+
+func (p *pullSecretCache) secret(ctx context.Context, namespace, name string) map[string]registryCredential {
+	key := namespace + "/" + name
+	if credentials, ok := p.secrets[key]; ok {
+		return credentials
+	}
+	secret, err := p.client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	var credentials map[string]registryCredential
+	switch {
+	case apierrors.IsNotFound(err):
+		p.warnings = append(p.warnings, fmt.Sprintf("image pull Secret %s not found", key))
+	case err != nil:
+		p.warnings = append(p.warnings, fmt.Sprintf("failed to read image pull Secret %s: %v", key, err))
+	default:
+		credentials = pullSecretCredentials(secret)
+	}
+	p.secrets[key] = credentials
+	return credentials
+}
@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// Impersonation is the orchestrator user a cluster operation is performed for
+type Impersonation struct {
+	User   string
+	Groups []string
+}
+
+type impersonationContextKey struct{}
+
+// WithImpersonation returns a context whose requests to clusters that impersonate users are
+// made as the given user, see SetImpersonation
+func WithImpersonation(ctx context.Context, identity Impersonation) context.Context {
+	return context.WithValue(ctx, impersonationContextKey{}, identity)
+}
+
+// ImpersonationFromContext returns the user stored in the context, if any
+func ImpersonationFromContext(ctx context.Context) (Impersonation, bool) {
+	identity, ok := ctx.Value(impersonationContextKey{}).(Impersonation)
+	return identity, ok
+}
+
+// SetImpersonation sets whether requests to a cluster made for an orchestrator user carry
+// Impersonate-User and Impersonate-Group headers, so the cluster's RBAC and audit log see
+// that user instead of the kubeconfig's identity. The kubeconfig's identity then needs the
+// impersonate verb on users and groups. Requests without a user, such as syncs and health
+// checks, keep the kubeconfig's identity. It takes effect on the next request.
+func (c *Client) SetImpersonation(clusterID string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.impersonate == nil {
+		c.impersonate = make(map[string]bool)
+	}
+	if enabled {
+		c.impersonate[clusterID] = true
+	} else {
+		delete(c.impersonate, clusterID)
+	}
+}
+
+// impersonates reports whether requests to a cluster impersonate their user
+func (c *Client) impersonates(clusterID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.impersonate[clusterID]
+}
+
+// impersonatedConfig returns config set to impersonate the user of ctx when the cluster
+// impersonates users, for connections whose requests do not carry ctx, such as
+// port-forward dials
+func (c *Client) impersonatedConfig(ctx context.Context, clusterID string, config *rest.Config) *rest.Config {
+	identity, ok := ImpersonationFromContext(ctx)
+	if !ok || identity.User == "" || !c.impersonates(clusterID) {
+		return config
+	}
+	config = rest.CopyConfig(config)
+	config.Impersonate = rest.ImpersonationConfig{UserName: identity.User, Groups: identity.Groups}
+	return config
+}
+
+// impersonationWrapper returns the transport wrapper adding the impersonation headers of
+// a cluster's requests
+func (c *Client) impersonationWrapper(clusterID string) transport.WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonatingRoundTripper{client: c, clusterID: clusterID, next: rt}
+	}
+}
+
+// impersonatingRoundTripper sets the impersonation headers from the request context while
+// impersonation is enabled for its cluster
+type impersonatingRoundTripper struct {
+	client    *Client
+	clusterID string
+	next      http.RoundTripper
+}
+
+func (t *impersonatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	identity, ok := ImpersonationFromContext(req.Context())
+	if !ok || identity.User == "" || !t.client.impersonates(t.clusterID) {
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(transport.ImpersonateUserHeader, identity.User)
+	req.Header.Del(transport.ImpersonateGroupHeader)
+	for _, group := range identity.Groups {
+		req.Header.Add(transport.ImpersonateGroupHeader, group)
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (t *impersonatingRoundTripper) WrappedRoundTripper() http.RoundTripper { return t.next }
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// ClusterClient is the multi-cluster Kubernetes API used by the API server, the services
+// and the sync worker. *Client implements it against real clusters; the k8s/fake package
+// provides an in-memory implementation with scriptable failures and latency.
+type ClusterClient interface {
+	// Cluster registry
+	AddCluster(clusterID, kubeconfig string) error
+	AddInClusterConfig(clusterID string) error
+	RemoveCluster(clusterID string)
+	SetClientLimits(clusterID string, limits ClientLimits) error
+	SetImpersonation(clusterID string, enabled bool)
+
+	// Health
+	CheckClusterHealth(clusterID string) (string, error)
+	CheckFluxInstallation(ctx context.Context, clusterID string) (*FluxHealth, error)
+	GetFluxVersion(ctx context.Context, clusterID string) (*FluxVersion, error)
+	GetCapabilities(clusterID string) (*ClusterCapabilities, error)
+	ReviewAccess(ctx context.Context, clusterID string, req AccessReviewRequest) (*AccessReview, error)
+
+	// Flux resources
+	GetFluxResources(clusterID string) ([]models.FluxResource, error)
+	GetFluxStats(clusterID string) (map[string]interface{}, error)
+	GetResourceTree(ctx context.Context, clusterID string, filter ListFilter) ([]ResourceNode, error)
+	InvalidateResourceTree(clusterID string)
+	GetReconcileOrder(ctx context.Context, clusterID string, source *EventObject) (*ReconcileOrder, error)
+	GetSecretKeys(ctx context.Context, clusterID, namespace, name string) (*SecretKeys, error)
+	GetHelmReleaseValues(ctx context.Context, clusterID, namespace, name string, revealSecrets bool) (*HelmReleaseValues, error)
+	GetHelmReleaseHistory(ctx context.Context, clusterID, namespace, name string, includeValues bool) (*HelmReleaseHistory, error)
+	RollbackHelmRelease(ctx context.Context, clusterID, namespace, name string, revision int) (*HelmRollbackResult, error)
+	ForceReconcileHelmRelease(ctx context.Context, clusterID, namespace, name string) (*HelmRemediationResult, error)
+	ResetHelmReleaseFailures(ctx context.Context, clusterID, namespace, name string) (*HelmRemediationResult, error)
+	DiffKustomization(ctx context.Context, clusterID, namespace, name string, revealSecrets bool) (*KustomizationDiff, error)
+	GetResourcesCreatedByFlux(ctx context.Context, clusterID, kind, namespace, name string) ([]map[string]interface{}, error)
+	ReconcileResource(ctx context.Context, clusterID, kind, namespace, name string) error
+	ReconcileWithSource(ctx context.Context, clusterID, kind, namespace, name string) (*SourceReconcileResult, error)
+	WaitForReconcile(ctx context.Context, clusterID, kind, namespace, name string, progress func(ReconcileProgress)) (*ReconcileProgress, error)
+	SuspendResource(ctx context.Context, clusterID, kind, namespace, name string) error
+	ResumeResource(ctx context.Context, clusterID, kind, namespace, name string) error
+	ListSuspendTargets(ctx context.Context, clusterID string, scope SuspendScope) ([]SuspendTarget, error)
+	UpdateFluxResource(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error
+	PatchFluxResource(ctx context.Context, clusterID, kind, namespace, name string, patch ResourcePatch) error
+	CreateNotificationResource(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error
+	CreateFluxResource(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error
+	DeleteNotificationResource(ctx context.Context, clusterID, kind, namespace, name string) error
+	ApplyManifests(ctx context.Context, clusterID string, manifests []byte, opts ApplyOptions) (*ApplyResult, error)
+
+	// Namespace quotas
+	GetNamespaceQuotas(ctx context.Context, clusterID string, threshold float64) ([]NamespaceQuotas, error)
+
+	// Unmanaged resources
+	GetUnmanagedResources(ctx context.Context, clusterID, namespace string) (*UnmanagedReport, error)
+
+	// Image freshness
+	GetImageFreshness(ctx context.Context, clusterID string) (*ImageFreshnessReport, error)
+
+	// Registry credentials
+	SetRegistryCredential(credential RegistryCredential)
+	RemoveRegistryCredential(registry string)
+	TestRegistryCredential(ctx context.Context, credential RegistryCredential) error
+
+	// Workloads
+	ScaleResource(ctx context.Context, clusterID, kind, namespace, name string, replicas int32) error
+	RestartResource(ctx context.Context, clusterID, kind, namespace, name string) error
+	GetWorkloadSnapshot(ctx context.Context, clusterID, kind, namespace, name string) (*WorkloadSnapshot, error)
+	UpdateResourceSpec(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error
+	PatchResource(ctx context.Context, clusterID, kind, namespace, name string, patch ResourcePatch) error
+
+	// Namespaces
+	ListNamespaces(ctx context.Context, clusterID string, filter ListFilter) ([]NamespaceInfo, error)
+	CreateNamespace(ctx context.Context, clusterID string, req NamespaceRequest) error
+	DeleteNamespace(ctx context.Context, clusterID, name string) error
+	GetResourceManifest(ctx context.Context, clusterID, kind, namespace, name string) (map[string]interface{}, error)
+	GetResourceYAML(ctx context.Context, clusterID, kind, namespace, name string, revealSecrets bool) (*ResourceYAML, error)
+	GetResourceDiff(ctx context.Context, clusterID, kind, namespace, name string) (map[string]interface{}, error)
+
+	// Pods and logs
+	GetPodTriage(ctx context.Context, clusterID, kind, namespace, name string) (*PodTriage, error)
+	GetResourceEvents(ctx context.Context, clusterID, kind, namespace, name string, limit int) ([]ResourceEvent, error)
+	ListClusterEvents(ctx context.Context, clusterID, namespace string, since, until time.Time, limit int) ([]ResourceEvent, error)
+	SearchFluxResourceLogs(ctx context.Context, clusterID, kind, namespace, name string, opts LogSearchOptions) (*LogSearchResult, error)
+	ListLogContainers(ctx context.Context, clusterID, kind, namespace, name string) ([]LogContainer, error)
+	StreamContainerLogs(ctx context.Context, clusterID string, container LogContainer, opts LogStreamOptions) (io.ReadCloser, error)
+	DeletePod(ctx context.Context, clusterID, namespace, name string) error
+	DeletePods(ctx context.Context, clusterID, namespace string, req PodDeletionRequest) (*PodDeletionResult, error)
+	EvictPod(ctx context.Context, clusterID, namespace, name string) error
+	ExecPod(ctx context.Context, clusterID, namespace, pod string, opts ExecOptions) (int, error)
+	PortForwardHTTP(ctx context.Context, clusterID, namespace, pod string, req PortForwardRequest) (*PortForwardResponse, error)
+	GetPodLogs(ctx context.Context, clusterID, namespace, podName, containerName string, tailLines int64, follow bool) (string, error)
+	GetPodContainers(ctx context.Context, clusterID, namespace, podName string) ([]string, error)
+	GetPodMetrics(ctx context.Context, clusterID, namespace, name string) (*PodMetrics, error)
+	GetAggregatedLogs(ctx context.Context, filters map[string]interface{}) ([]AggregatedLogEntry, error)
+}
+
+var _ ClusterClient = (*Client)(nil)
@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// KubeconfigContext describes one context of a kubeconfig
+type KubeconfigContext struct {
+	Name      string `json:"name"`
+	Cluster   string `json:"cluster"`
+	Server    string `json:"server"`
+	Namespace string `json:"namespace,omitempty"`
+	Current   bool   `json:"current"`
+}
+
+// KubeconfigContexts lists the contexts of a kubeconfig, sorted by name
+func KubeconfigContexts(kubeconfig string) ([]KubeconfigContext, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+
+	contexts := make([]KubeconfigContext, 0, len(config.Contexts))
+	for name, context := range config.Contexts {
+		entry := KubeconfigContext{
+			Name:      name,
+			Cluster:   context.Cluster,
+			Namespace: context.Namespace,
+			Current:   name == config.CurrentContext,
+		}
+		if cluster, ok := config.Clusters[context.Cluster]; ok {
+			entry.Server = cluster.Server
+		}
+		contexts = append(contexts, entry)
+	}
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].Name < contexts[j].Name })
+	return contexts, nil
+}
+
+// ContextKubeconfig returns a kubeconfig holding only the named context of kubeconfig,
+// with its cluster and user, as its current context. Each context can then be registered
+// as a cluster of its own.
+func ContextKubeconfig(kubeconfig, contextName string) (string, error) {
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+	if _, ok := config.Contexts[contextName]; !ok {
+		return "", fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+
+	config.CurrentContext = contextName
+	if err := clientcmdapi.MinifyConfig(config); err != nil {
+		return "", fmt.Errorf("failed to extract context %q: %w", contextName, err)
+	}
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return string(data), nil
+}
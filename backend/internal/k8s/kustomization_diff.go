@@ -0,0 +1,777 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	yamlv3 "go.yaml.in/yaml/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+// maxArtifactSize caps the uncompressed size of a source artifact read for a diff
+const maxArtifactSize = 64 << 20
+
+// kustomizeFieldManager is the field manager the kustomize-controller applies objects with
+const kustomizeFieldManager = "kustomize-controller"
+
+// kustomizationFileNames are the names kustomize looks for in a directory, in order
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// kustomizationFields are the kustomization.yaml fields the diff build applies; any other
+// field is reported as a warning
+var kustomizationFields = map[string]bool{
+	"apiVersion": true, "kind": true, "metadata": true,
+	"resources": true, "namespace": true, "commonAnnotations": true,
+}
+
+// clusterScopedKinds are the kinds kustomize never sets a namespace on
+var clusterScopedKinds = map[string]bool{
+	"Namespace": true, "ClusterRole": true, "ClusterRoleBinding": true, "CustomResourceDefinition": true,
+	"PersistentVolume": true, "StorageClass": true, "PriorityClass": true, "IngressClass": true,
+	"RuntimeClass": true, "APIService": true, "CSIDriver": true, "CSINode": true, "VolumeAttachment": true,
+	"MutatingWebhookConfiguration": true, "ValidatingWebhookConfiguration": true,
+	"ValidatingAdmissionPolicy": true, "ValidatingAdmissionPolicyBinding": true,
+	"CertificateSigningRequest": true, "FlowSchema": true, "PriorityLevelConfiguration": true, "Node": true,
+}
+
+// substitutionPattern matches the ${VAR}, ${VAR:=default} and ${VAR:-default} forms of
+// post-build variable substitution
+var substitutionPattern = regexp.MustCompile(`\$\{([_a-zA-Z][_a-zA-Z0-9]*)(?::?[=-]([^}]*))?\}`)
+
+// ObjectDiff is the change reconciling a Kustomization would make to one object
+type ObjectDiff struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Action     string `json:"action"`         // created, configured, unchanged, deleted, error
+	Diff       string `json:"diff,omitempty"` // unified diff of the live object and the dry-run result
+	Error      string `json:"error,omitempty"`
+}
+
+// KustomizationDiff previews what reconciling a Kustomization would change, like
+// flux diff kustomization. Warnings list the parts of the Kustomization the diff could
+// not apply, so objects they touch may differ from what the controller would apply.
+type KustomizationDiff struct {
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Source    EventObject    `json:"source"`
+	Revision  string         `json:"revision"`
+	Path      string         `json:"path"`
+	Objects   []ObjectDiff   `json:"objects"`
+	Summary   map[string]int `json:"summary"` // objects per action
+	Warnings  []string       `json:"warnings"`
+
+	SecretVariables int  `json:"secret_variables"` // post-build variables read from Secrets
+	Redacted        bool `json:"redacted"`
+}
+
+// DiffKustomization builds the manifests a Kustomization would apply from its source's
+// current artifact, server-side dry-run applies them and diffs the result against the live
+// objects. With prune enabled, inventory objects missing from the build are reported as
+// deleted. Variables substituted from Secrets are redacted unless revealSecrets is set,
+// and Secret data is always masked.
+//
+// The build supports plain manifest directories and the resources, namespace and
+// commonAnnotations fields of kustomization.yaml files; anything else is reported in
+// Warnings rather than applied.
+func (c *Client) DiffKustomization(ctx context.Context, clusterID, namespace, name string, revealSecrets bool) (*KustomizationDiff, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	if _, ok := c.restConfig(clusterID); !ok {
+		return nil, fmt.Errorf("diff is not supported on cluster %s", clusterID)
+	}
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	kustomization, _, err := c.GetResourceByKind(ctx, clusterID, "Kustomization", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := kustomizationSourceRef(kustomization)
+	if err != nil {
+		return nil, err
+	}
+	source, _, err := c.GetResourceByKind(ctx, clusterID, ref.Kind, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source %s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+	}
+	artifactURL, _, _ := unstructured.NestedString(source.Object, "status", "artifact", "url")
+	if artifactURL == "" {
+		return nil, fmt.Errorf("source %s %s/%s has no artifact", ref.Kind, ref.Namespace, ref.Name)
+	}
+	files, err := fetchArtifact(ctx, typedClient, artifactURL)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KustomizationDiff{
+		Namespace: namespace,
+		Name:      name,
+		Source:    ref,
+		Objects:   []ObjectDiff{},
+		Summary:   map[string]int{},
+		Warnings:  []string{},
+	}
+	result.Revision, _, _ = unstructured.NestedString(source.Object, "status", "artifact", "revision")
+	result.Path, _, _ = unstructured.NestedString(kustomization.Object, "spec", "path")
+
+	builder := &kustomizeBuilder{files: files, visiting: map[string]bool{}}
+	objects, err := builder.build(path.Clean(strings.TrimPrefix(result.Path, "/")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s: %w", result.Path, err)
+	}
+	result.Warnings = append(result.Warnings, builder.warnings...)
+	for _, field := range []string{"patches", "patchesStrategicMerge", "patchesJson6902", "images", "components", "nameSuffix", "namePrefix"} {
+		if _, found, _ := unstructured.NestedFieldNoCopy(kustomization.Object, "spec", field); found {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("spec.%s is not applied", field))
+		}
+	}
+	if _, found, _ := unstructured.NestedMap(kustomization.Object, "spec", "decryption"); found {
+		result.Warnings = append(result.Warnings, "spec.decryption is not applied; encrypted values are diffed as stored in the source")
+	}
+
+	vars, secretVars, err := c.substituteVariables(ctx, typedClient, kustomization, revealSecrets)
+	if err != nil {
+		return nil, err
+	}
+	result.SecretVariables = secretVars
+	if secretVars > 0 && !revealSecrets {
+		result.Redacted = true
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d variables substituted from Secrets are redacted; objects using them show changes", secretVars))
+	}
+	ids := map[string]bool{}
+	for _, obj := range objects {
+		if err := applyKustomizationSpec(obj, kustomization, vars); err != nil {
+			return nil, err
+		}
+		id := strings.Join([]string{obj.GetNamespace(), obj.GetName(), obj.GroupVersionKind().Group, obj.GetKind()}, "_")
+		if ids[id] {
+			return nil, fmt.Errorf("failed to build %s: %s %s/%s is defined more than once", result.Path, obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		}
+		ids[id] = true
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(typedClient.Discovery()))
+	built := map[string]bool{}
+	for _, obj := range objects {
+		diff := diffObject(ctx, client, mapper, obj)
+		built[inventoryID(diff)] = true
+		result.Objects = append(result.Objects, diff)
+	}
+	if prune, _, _ := unstructured.NestedBool(kustomization.Object, "spec", "prune"); prune {
+		entries, _, _ := unstructured.NestedSlice(kustomization.Object, "status", "inventory", "entries")
+		for _, item := range entries {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := entry["id"].(string)
+			version, _ := entry["v"].(string)
+			if built[id] {
+				continue
+			}
+			if diff, ok := diffPrunedObject(ctx, client, mapper, id, version); ok {
+				result.Objects = append(result.Objects, diff)
+			}
+		}
+	}
+
+	for _, diff := range result.Objects {
+		result.Summary[diff.Action]++
+	}
+	return result, nil
+}
+
+// fetchArtifact downloads a source artifact through the API server's service proxy, since
+// the source-controller only serves artifacts inside the cluster, and unpacks it in memory
+func fetchArtifact(ctx context.Context, typedClient kubernetes.Interface, artifactURL string) (map[string][]byte, error) {
+	u, err := url.Parse(artifactURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact URL: %w", err)
+	}
+	// The host is <service>.<namespace>.svc...
+	host := strings.Split(u.Hostname(), ".")
+	if len(host) < 2 {
+		return nil, fmt.Errorf("artifact URL %s does not point at a cluster service", artifactURL)
+	}
+
+	stream, err := typedClient.CoreV1().Services(host[1]).ProxyGet(u.Scheme, host[0], u.Port(), u.Path, nil).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artifact: %w", err)
+	}
+	defer stream.Close()
+
+	gz, err := gzip.NewReader(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	var total int64
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if name == ".." || strings.HasPrefix(name, "../") {
+			continue
+		}
+		if total += header.Size; total > maxArtifactSize {
+			return nil, fmt.Errorf("artifact is larger than %d MiB", maxArtifactSize>>20)
+		}
+		data, err := io.ReadAll(io.LimitReader(archive, header.Size))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from artifact: %w", name, err)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// kustomizeBuilder builds manifests from an unpacked artifact
+type kustomizeBuilder struct {
+	files    map[string][]byte
+	visiting map[string]bool
+	warnings []string
+}
+
+// build returns the objects of a directory. A directory without a kustomization file is
+// built the way the kustomize-controller generates one: from every manifest below it, with
+// subdirectories that have their own kustomization file built as a unit.
+func (b *kustomizeBuilder) build(dir string) ([]*unstructured.Unstructured, error) {
+	if file, ok := b.kustomizationFile(dir); ok {
+		return b.buildKustomization(dir, file)
+	}
+	if !b.isDir(dir) {
+		return nil, fmt.Errorf("path %s not found in the artifact", dir)
+	}
+	return b.buildPlain(dir)
+}
+
+func (b *kustomizeBuilder) buildPlain(dir string) ([]*unstructured.Unstructured, error) {
+	files, dirs := b.list(dir)
+	objects := []*unstructured.Unstructured{}
+	for _, file := range files {
+		if ext := path.Ext(file); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		decoded, err := decodeManifests(file, b.files[file], false)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, decoded...)
+	}
+	for _, sub := range dirs {
+		var decoded []*unstructured.Unstructured
+		var err error
+		if _, ok := b.kustomizationFile(sub); ok {
+			decoded, err = b.build(sub)
+		} else {
+			decoded, err = b.buildPlain(sub)
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, decoded...)
+	}
+	return objects, nil
+}
+
+func (b *kustomizeBuilder) buildKustomization(dir, file string) ([]*unstructured.Unstructured, error) {
+	if b.visiting[dir] {
+		return nil, fmt.Errorf("%s is included in itself", file)
+	}
+	b.visiting[dir] = true
+	defer delete(b.visiting, dir)
+
+	var spec map[string]interface{}
+	if err := yaml.Unmarshal(b.files[file], &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	fields := make([]string, 0, len(spec))
+	for field := range spec {
+		if !kustomizationFields[field] {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		b.warnings = append(b.warnings, fmt.Sprintf("%s: %s is not applied", file, field))
+	}
+
+	objects := []*unstructured.Unstructured{}
+	resources, _ := spec["resources"].([]interface{})
+	for _, item := range resources {
+		resource, _ := item.(string)
+		if strings.Contains(resource, "://") || strings.HasPrefix(resource, "github.com/") {
+			b.warnings = append(b.warnings, fmt.Sprintf("%s: remote resource %s is not fetched", file, resource))
+			continue
+		}
+		resourcePath := path.Join(dir, resource)
+		var decoded []*unstructured.Unstructured
+		var err error
+		if data, ok := b.files[resourcePath]; ok {
+			decoded, err = decodeManifests(resourcePath, data, true)
+		} else if _, ok := b.kustomizationFile(resourcePath); ok {
+			decoded, err = b.build(resourcePath)
+		} else if b.isDir(resourcePath) {
+			err = fmt.Errorf("%s: directory %s has no kustomization file", file, resource)
+		} else {
+			err = fmt.Errorf("%s: resource %s not found", file, resource)
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, decoded...)
+	}
+
+	if namespace, _ := spec["namespace"].(string); namespace != "" {
+		for _, obj := range objects {
+			if !clusterScopedKinds[obj.GetKind()] {
+				obj.SetNamespace(namespace)
+			}
+		}
+	}
+	if annotations, ok := spec["commonAnnotations"].(map[string]interface{}); ok {
+		for _, obj := range objects {
+			addMetadata(obj, nil, annotations)
+		}
+	}
+	return objects, nil
+}
+
+// kustomizationFile returns the kustomization file of a directory
+func (b *kustomizeBuilder) kustomizationFile(dir string) (string, bool) {
+	for _, name := range kustomizationFileNames {
+		file := path.Join(dir, name)
+		if _, ok := b.files[file]; ok {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+func (b *kustomizeBuilder) isDir(dir string) bool {
+	files, dirs := b.list(dir)
+	return len(files) > 0 || len(dirs) > 0
+}
+
+// list returns the files and subdirectories directly in dir, sorted
+func (b *kustomizeBuilder) list(dir string) ([]string, []string) {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+	var files []string
+	dirs := map[string]bool{}
+	for name := range b.files {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if child, _, nested := strings.Cut(strings.TrimPrefix(name, prefix), "/"); nested {
+			dirs[prefix+child] = true
+		} else {
+			files = append(files, name)
+		}
+	}
+	subdirs := make([]string, 0, len(dirs))
+	for sub := range dirs {
+		subdirs = append(subdirs, sub)
+	}
+	sort.Strings(files)
+	sort.Strings(subdirs)
+	return files, subdirs
+}
+
+// decodeManifests decodes the YAML documents of a file. Documents that are not Kubernetes
+// objects are skipped, or rejected when strict.
+func decodeManifests(file string, data []byte, strict bool) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		raw, err := yamlToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		var probe struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}
+		if err := json.Unmarshal(raw, &probe); err != nil || probe.APIVersion == "" || probe.Kind == "" {
+			if strict && len(bytes.TrimSpace(raw)) > 0 && string(bytes.TrimSpace(raw)) != "null" {
+				return nil, fmt.Errorf("%s contains a document that is not a Kubernetes object", file)
+			}
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// yamlToJSON converts a YAML document to JSON with YAML 1.2 rules, as kustomize reads
+// manifests, so keys such as "on" or "n" stay strings
+func yamlToJSON(doc []byte) ([]byte, error) {
+	var value interface{}
+	if err := yamlv3.Unmarshal(doc, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// substituteVariables collects the post-build variables of a Kustomization: substituteFrom
+// references in order, then spec.postBuild.substitute, and counts the variables read from
+// Secrets. Unless revealSecrets is set, their values are redacted.
+func (c *Client) substituteVariables(ctx context.Context, typedClient kubernetes.Interface, kustomization *unstructured.Unstructured, revealSecrets bool) (map[string]string, int, error) {
+	vars := map[string]string{}
+	secretVars := 0
+	refs, _, _ := unstructured.NestedSlice(kustomization.Object, "spec", "postBuild", "substituteFrom")
+	for _, item := range refs {
+		ref, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := ref["kind"].(string)
+		name, _ := ref["name"].(string)
+		optional, _ := ref["optional"].(bool)
+
+		var err error
+		switch kind {
+		case "ConfigMap":
+			var configMap *corev1.ConfigMap
+			configMap, err = typedClient.CoreV1().ConfigMaps(kustomization.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+			if err == nil {
+				for key, value := range configMap.Data {
+					vars[key] = value
+				}
+			}
+		case "Secret":
+			var secret *corev1.Secret
+			secret, err = typedClient.CoreV1().Secrets(kustomization.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+			if err == nil {
+				for key, value := range secret.Data {
+					vars[key] = redactedValue
+					if revealSecrets {
+						vars[key] = string(value)
+					}
+					secretVars++
+				}
+			}
+		default:
+			err = fmt.Errorf("unsupported kind %q", kind)
+		}
+		if err != nil && !(optional && apierrors.IsNotFound(err)) {
+			return nil, 0, fmt.Errorf("failed to read substituteFrom %s %s: %w", kind, name, err)
+		}
+	}
+	substitute, _, _ := unstructured.NestedStringMap(kustomization.Object, "spec", "postBuild", "substitute")
+	for key, value := range substitute {
+		vars[key] = value
+	}
+	return vars, secretVars, nil
+}
+
+// applyKustomizationSpec changes a built object the way the kustomize-controller does
+// before applying it: spec.targetNamespace, spec.commonMetadata, post-build variable
+// substitution, then the labels naming the Kustomization
+func applyKustomizationSpec(obj, kustomization *unstructured.Unstructured, vars map[string]string) error {
+	if targetNamespace, _, _ := unstructured.NestedString(kustomization.Object, "spec", "targetNamespace"); targetNamespace != "" && !clusterScopedKinds[obj.GetKind()] {
+		obj.SetNamespace(targetNamespace)
+	}
+	labels, _, _ := unstructured.NestedMap(kustomization.Object, "spec", "commonMetadata", "labels")
+	annotations, _, _ := unstructured.NestedMap(kustomization.Object, "spec", "commonMetadata", "annotations")
+	addMetadata(obj, labels, annotations)
+
+	_, hasPostBuild, _ := unstructured.NestedMap(kustomization.Object, "spec", "postBuild")
+	disabled := obj.GetLabels()["kustomize.toolkit.fluxcd.io/substitute"] == "disabled" ||
+		obj.GetAnnotations()["kustomize.toolkit.fluxcd.io/substitute"] == "disabled"
+	if hasPostBuild && !disabled {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		data = substitutionPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+			groups := substitutionPattern.FindSubmatch(match)
+			if value, ok := vars[string(groups[1])]; ok {
+				return []byte(value)
+			}
+			return groups[2]
+		})
+		raw, err := yamlToJSON(data)
+		if err != nil {
+			return fmt.Errorf("failed to substitute variables in %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if err := obj.UnmarshalJSON(raw); err != nil {
+			return fmt.Errorf("failed to substitute variables in %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+
+	addMetadata(obj, map[string]interface{}{
+		"kustomize.toolkit.fluxcd.io/name":      kustomization.GetName(),
+		"kustomize.toolkit.fluxcd.io/namespace": kustomization.GetNamespace(),
+	}, nil)
+	return nil
+}
+
+// addMetadata adds labels and annotations to an object, replacing existing keys
+func addMetadata(obj *unstructured.Unstructured, labels, annotations map[string]interface{}) {
+	if len(labels) > 0 {
+		merged := obj.GetLabels()
+		if merged == nil {
+			merged = map[string]string{}
+		}
+		for key, value := range labels {
+			merged[key] = fmt.Sprint(value)
+		}
+		obj.SetLabels(merged)
+	}
+	if len(annotations) > 0 {
+		merged := obj.GetAnnotations()
+		if merged == nil {
+			merged = map[string]string{}
+		}
+		for key, value := range annotations {
+			merged[key] = fmt.Sprint(value)
+		}
+		obj.SetAnnotations(merged)
+	}
+}
+
+// diffObject server-side dry-run applies a built object as the kustomize-controller and
+// diffs the result against the live object
+func diffObject(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured) ObjectDiff {
+	gvk := obj.GroupVersionKind()
+	diff := ObjectDiff{APIVersion: obj.GetAPIVersion(), Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Action: "error"}
+
+	resource, err := resourceFor(client, mapper, obj.GetAPIVersion(), gvk.Kind, obj.GetNamespace())
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	if resource.namespaced && obj.GetNamespace() == "" {
+		diff.Error = "namespace is not set"
+		return diff
+	}
+	if !resource.namespaced {
+		obj.SetNamespace("")
+		diff.Namespace = ""
+	}
+
+	live, err := resource.client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		diff.Error = fmt.Sprintf("failed to get live object: %v", err)
+		return diff
+	}
+	merged, err := resource.client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		FieldManager: kustomizeFieldManager,
+		Force:        true,
+		DryRun:       []string{metav1.DryRunAll},
+	})
+	switch {
+	case apierrors.IsNotFound(err) && live == nil:
+		// Its namespace is created by the same build
+		merged = obj
+	case err != nil:
+		diff.Error = fmt.Sprintf("dry-run failed: %v", err)
+		return diff
+	}
+
+	if gvk.Group == "" && gvk.Kind == "Secret" {
+		maskSecretData(live, merged)
+	}
+	after := diffYAML(merged)
+	switch {
+	case live == nil:
+		diff.Action, diff.Diff = "created", unifiedDiff("", after)
+	case diffYAML(live) == after:
+		diff.Action = "unchanged"
+	default:
+		diff.Action, diff.Diff = "configured", unifiedDiff(diffYAML(live), after)
+	}
+	return diff
+}
+
+// diffPrunedObject reports an inventory object the build no longer contains, which the
+// kustomize-controller would delete. Objects that are already gone or opted out of pruning
+// are skipped.
+func diffPrunedObject(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, id, version string) (ObjectDiff, bool) {
+	// Inventory IDs are <namespace>_<name>_<group>_<kind>
+	parts := strings.Split(id, "_")
+	if len(parts) != 4 {
+		return ObjectDiff{}, false
+	}
+	apiVersion := version
+	if parts[2] != "" {
+		apiVersion = parts[2] + "/" + version
+	}
+	diff := ObjectDiff{APIVersion: apiVersion, Kind: parts[3], Namespace: parts[0], Name: parts[1], Action: "error"}
+
+	resource, err := resourceFor(client, mapper, apiVersion, diff.Kind, diff.Namespace)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff, true
+	}
+	live, err := resource.client.Get(ctx, diff.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return ObjectDiff{}, false
+	}
+	if err != nil {
+		diff.Error = fmt.Sprintf("failed to get live object: %v", err)
+		return diff, true
+	}
+	if live.GetLabels()["kustomize.toolkit.fluxcd.io/prune"] == "disabled" || live.GetAnnotations()["kustomize.toolkit.fluxcd.io/prune"] == "disabled" {
+		return ObjectDiff{}, false
+	}
+	if parts[2] == "" && diff.Kind == "Secret" {
+		maskSecretData(live, nil)
+	}
+	diff.Action, diff.Diff = "deleted", unifiedDiff(diffYAML(live), "")
+	return diff, true
+}
+
+// mappedResource is a client for the resource of a kind, scoped to a namespace when the
+// kind is namespaced
+type mappedResource struct {
+	client     dynamic.ResourceInterface
+	namespaced bool
+}
+
+func resourceFor(client dynamic.Interface, mapper meta.RESTMapper, apiVersion, kind, namespace string) (mappedResource, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return mappedResource{}, err
+	}
+	mapping, err := mapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
+	if err != nil {
+		return mappedResource{}, fmt.Errorf("unknown kind %s: %v", kind, err)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return mappedResource{client: client.Resource(mapping.Resource).Namespace(namespace), namespaced: true}, nil
+	}
+	return mappedResource{client: client.Resource(mapping.Resource)}, nil
+}
+
+// inventoryID returns the Flux inventory ID of a diffed object
+func inventoryID(diff ObjectDiff) string {
+	group, _, found := strings.Cut(diff.APIVersion, "/")
+	if !found {
+		group = ""
+	}
+	return strings.Join([]string{diff.Namespace, diff.Name, group, diff.Kind}, "_")
+}
+
+// maskSecretData replaces the values of Secrets with a placeholder, marking values that
+// differ between the live and dry-run objects so a change still shows in the diff
+func maskSecretData(live, merged *unstructured.Unstructured) {
+	var liveData, mergedData map[string]interface{}
+	if live != nil {
+		liveData, _, _ = unstructured.NestedMap(live.Object, "data")
+		redactSecret(live)
+	}
+	if merged != nil {
+		mergedData, _, _ = unstructured.NestedMap(merged.Object, "data")
+		redactSecret(merged)
+	}
+	for key, value := range mergedData {
+		old, found := liveData[key]
+		switch {
+		case found && old != value:
+			liveData[key], mergedData[key] = redactedValue+" (before)", redactedValue+" (after)"
+		case found:
+			liveData[key], mergedData[key] = redactedValue, redactedValue
+		default:
+			mergedData[key] = redactedValue
+		}
+	}
+	for key := range liveData {
+		if _, found := mergedData[key]; !found {
+			liveData[key] = redactedValue
+		}
+	}
+	if liveData != nil {
+		_ = unstructured.SetNestedMap(live.Object, liveData, "data")
+	}
+	if mergedData != nil {
+		_ = unstructured.SetNestedMap(merged.Object, mergedData, "data")
+	}
+}
+
+// diffYAML renders an object as YAML without the fields the API server maintains
+func diffYAML(obj *unstructured.Unstructured) string {
+	copied := obj.DeepCopy()
+	delete(copied.Object, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp", "selfLink"} {
+		unstructured.RemoveNestedField(copied.Object, "metadata", field)
+	}
+	data, err := yaml.Marshal(copied.Object)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// unifiedDiff returns a unified diff from the live to the desired YAML; either may be empty
+func unifiedDiff(live, desired string) string {
+	var a, b []string
+	if live != "" {
+		a = difflib.SplitLines(strings.TrimSuffix(live, "\n"))
+	}
+	if desired != "" {
+		b = difflib.SplitLines(strings.TrimSuffix(desired, "\n"))
+	}
+	text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{A: a, B: b, FromFile: "live", ToFile: "desired", Context: 3})
+	if err != nil {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// LogContainer identifies one container whose logs can be fetched
+type LogContainer struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Init      bool   `json:"init"`
+}
+
+// LogStreamOptions selects which part of a container log to stream
+type LogStreamOptions struct {
+	TailLines    int64 // 0 streams the whole log
+	Previous     bool  // the previous, terminated container instead of the current one
+	Follow       bool  // keep the stream open for new lines until the container stops
+	SinceSeconds int64 // only lines newer than this many seconds, 0 for all
+}
+
+// ListLogContainers resolves the containers of a pod, of the pods of a workload
+// (Deployment, StatefulSet, DaemonSet, ReplicaSet, Job), or of every pod a Kustomization
+// or HelmRelease deploys
+func (c *Client) ListLogContainers(ctx context.Context, clusterID, kind, namespace, name string) ([]LogContainer, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	var pods []corev1.Pod
+	switch kind {
+	case "Pod":
+		pod, err := typedClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod: %w", err)
+		}
+		pods = []corev1.Pod{*pod}
+	case "Kustomization", "HelmRelease":
+		_, owned, err := c.getOwnedPods(ctx, clusterID, kind, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		pods = owned
+	default:
+		selector, err := c.workloadSelector(ctx, clusterID, kind, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		list, err := typedClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		pods = list.Items
+	}
+
+	containers := []LogContainer{}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.InitContainers {
+			containers = append(containers, LogContainer{Namespace: pod.Namespace, Pod: pod.Name, Container: container.Name, Init: true})
+		}
+		for _, container := range pod.Spec.Containers {
+			containers = append(containers, LogContainer{Namespace: pod.Namespace, Pod: pod.Name, Container: container.Name})
+		}
+	}
+	return containers, nil
+}
+
+// workloadSelector returns the pod selector of a workload
+func (c *Client) workloadSelector(ctx context.Context, clusterID, kind, namespace, name string) (labels.Selector, error) {
+	typedClient, _ := c.typedClient(clusterID)
+
+	var selector *metav1.LabelSelector
+	switch kind {
+	case "Deployment":
+		obj, err := typedClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		selector = obj.Spec.Selector
+	case "StatefulSet":
+		obj, err := typedClient.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		selector = obj.Spec.Selector
+	case "DaemonSet":
+		obj, err := typedClient.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		selector = obj.Spec.Selector
+	case "ReplicaSet":
+		obj, err := typedClient.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replicaset: %w", err)
+		}
+		selector = obj.Spec.Selector
+	case "Job":
+		obj, err := typedClient.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job: %w", err)
+		}
+		selector = obj.Spec.Selector
+	default:
+		return nil, fmt.Errorf("logs cannot be collected for kind %s", kind)
+	}
+
+	if selector == nil {
+		return nil, fmt.Errorf("%s %s/%s has no pod selector", kind, namespace, name)
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod selector: %w", err)
+	}
+	if parsed.Empty() {
+		return nil, fmt.Errorf("%s %s/%s has an empty pod selector", kind, namespace, name)
+	}
+	return parsed, nil
+}
+
+// StreamContainerLogs opens the log stream of one container; the caller closes it
+func (c *Client) StreamContainerLogs(ctx context.Context, clusterID string, container LogContainer, opts LogStreamOptions) (io.ReadCloser, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	logOpts := &corev1.PodLogOptions{
+		Container:  container.Container,
+		Previous:   opts.Previous,
+		Follow:     opts.Follow,
+		Timestamps: true,
+	}
+	if opts.TailLines > 0 {
+		tailLines := opts.TailLines
+		logOpts.TailLines = &tailLines
+	}
+	if opts.SinceSeconds > 0 {
+		sinceSeconds := opts.SinceSeconds
+		logOpts.SinceSeconds = &sinceSeconds
+	}
+
+	stream, err := typedClient.CoreV1().Pods(container.Namespace).GetLogs(container.Pod, logOpts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log stream: %w", err)
+	}
+	return stream, nil
+}
@@ -0,0 +1,261 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Log search limits
+const (
+	DefaultLogSearchTailLines  = 1000
+	DefaultLogSearchMaxMatches = 100
+	MaxLogSearchContext        = 20
+	logSearchConcurrency       = 8
+	logSearchMaxBytes          = 2 * 1024 * 1024 // per container
+)
+
+// LogSearchOptions controls a log search across the pods of a Flux resource
+type LogSearchOptions struct {
+	Query      string
+	Regex      bool
+	IgnoreCase bool
+	Context    int   // lines of context before and after each match
+	TailLines  int64 // lines fetched from the end of each container log
+	MaxMatches int   // per container
+	Previous   bool  // search the previous, terminated container instead
+}
+
+// LogMatch is one matching log line with its surrounding lines
+type LogMatch struct {
+	LineNumber int      `json:"line_number"` // 1-based, within the fetched tail
+	Line       string   `json:"line"`
+	Before     []string `json:"before,omitempty"`
+	After      []string `json:"after,omitempty"`
+}
+
+// ContainerLogMatches are the matches found in the log of one container
+type ContainerLogMatches struct {
+	Namespace string     `json:"namespace"`
+	Pod       string     `json:"pod"`
+	Container string     `json:"container"`
+	Matches   []LogMatch `json:"matches"`
+	Truncated bool       `json:"truncated"` // more matches than MaxMatches
+	Error     string     `json:"error,omitempty"`
+}
+
+// LogSearchResult aggregates the matches of a search per pod and container
+type LogSearchResult struct {
+	Kind               string                `json:"kind"`
+	Namespace          string                `json:"namespace"`
+	Name               string                `json:"name"`
+	Query              string                `json:"query"`
+	PodsSearched       int                   `json:"pods_searched"`
+	ContainersSearched int                   `json:"containers_searched"`
+	TotalMatches       int                   `json:"total_matches"`
+	Results            []ContainerLogMatches `json:"results"`
+}
+
+// Validate checks the query and fills in defaults for unset limits
+func (o *LogSearchOptions) Validate() error {
+	if o.Query == "" {
+		return fmt.Errorf("query is required")
+	}
+	if o.Context < 0 || o.Context > MaxLogSearchContext {
+		return fmt.Errorf("context must be between 0 and %d", MaxLogSearchContext)
+	}
+	if o.TailLines <= 0 {
+		o.TailLines = DefaultLogSearchTailLines
+	}
+	if o.MaxMatches <= 0 {
+		o.MaxMatches = DefaultLogSearchMaxMatches
+	}
+	_, err := o.matcher()
+	return err
+}
+
+// matcher returns the line predicate for the query
+func (o LogSearchOptions) matcher() (func(string) bool, error) {
+	if o.Regex {
+		pattern := o.Query
+		if o.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return re.MatchString, nil
+	}
+	if o.IgnoreCase {
+		query := strings.ToLower(o.Query)
+		return func(line string) bool { return strings.Contains(strings.ToLower(line), query) }, nil
+	}
+	return func(line string) bool { return strings.Contains(line, o.Query) }, nil
+}
+
+// SearchLogText greps log text for the query, returning the matches with their context
+// lines and whether more than opts.MaxMatches lines matched. opts must be validated.
+func SearchLogText(r io.Reader, opts LogSearchOptions) ([]LogMatch, bool, error) {
+	match, err := opts.matcher()
+	if err != nil {
+		return nil, false, err
+	}
+
+	matches := []LogMatch{}
+	var before []string
+	// Matches still collecting their trailing context
+	var pending []int
+	truncated := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNumber++
+
+		remaining := pending[:0]
+		for _, i := range pending {
+			matches[i].After = append(matches[i].After, line)
+			if len(matches[i].After) < opts.Context {
+				remaining = append(remaining, i)
+			}
+		}
+		pending = remaining
+		if truncated && len(pending) == 0 {
+			break
+		}
+
+		if match(line) {
+			if len(matches) >= opts.MaxMatches {
+				truncated = true
+				if len(pending) == 0 {
+					break
+				}
+			} else {
+				matches = append(matches, LogMatch{
+					LineNumber: lineNumber,
+					Line:       line,
+					Before:     append([]string(nil), before...),
+				})
+				if opts.Context > 0 {
+					pending = append(pending, len(matches)-1)
+				}
+			}
+		}
+
+		if opts.Context > 0 {
+			before = append(before, line)
+			if len(before) > opts.Context {
+				before = before[1:]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return matches, truncated, fmt.Errorf("failed to read logs: %w", err)
+	}
+	return matches, truncated, nil
+}
+
+// SearchFluxResourceLogs greps the logs of every container of every pod a Kustomization
+// or HelmRelease deploys, fetching the container logs concurrently
+func (c *Client) SearchFluxResourceLogs(ctx context.Context, clusterID, kind, namespace, name string, opts LogSearchOptions) (*LogSearchResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	_, pods, err := c.getOwnedPods(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	typedClient, _ := c.typedClient(clusterID)
+
+	type target struct {
+		namespace, pod, container string
+	}
+	var targets []target
+	for _, pod := range pods {
+		for _, container := range pod.Spec.InitContainers {
+			targets = append(targets, target{pod.Namespace, pod.Name, container.Name})
+		}
+		for _, container := range pod.Spec.Containers {
+			targets = append(targets, target{pod.Namespace, pod.Name, container.Name})
+		}
+	}
+
+	results := make([]ContainerLogMatches, len(targets))
+	sem := make(chan struct{}, logSearchConcurrency)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t target) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := ContainerLogMatches{Namespace: t.namespace, Pod: t.pod, Container: t.container, Matches: []LogMatch{}}
+			matches, truncated, err := searchContainerLogs(ctx, typedClient, t.namespace, t.pod, t.container, opts)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Matches = append(result.Matches, matches...)
+			result.Truncated = truncated
+			results[i] = result
+		}(i, t)
+	}
+	wg.Wait()
+
+	search := &LogSearchResult{
+		Kind:               kind,
+		Namespace:          namespace,
+		Name:               name,
+		Query:              opts.Query,
+		PodsSearched:       len(pods),
+		ContainersSearched: len(targets),
+		Results:            []ContainerLogMatches{},
+	}
+	for _, result := range results {
+		// Containers without matches are left out unless their logs could not be read
+		if len(result.Matches) == 0 && result.Error == "" {
+			continue
+		}
+		search.TotalMatches += len(result.Matches)
+		search.Results = append(search.Results, result)
+	}
+	sort.Slice(search.Results, func(i, j int) bool {
+		a, b := search.Results[i], search.Results[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Pod != b.Pod {
+			return a.Pod < b.Pod
+		}
+		return a.Container < b.Container
+	})
+	return search, nil
+}
+
+// searchContainerLogs streams the tail of one container log through SearchLogText
+func searchContainerLogs(ctx context.Context, typedClient kubernetes.Interface, namespace, pod, container string, opts LogSearchOptions) ([]LogMatch, bool, error) {
+	tailLines := opts.TailLines
+	req := typedClient.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+		Previous:  opts.Previous,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open log stream: %w", err)
+	}
+	defer stream.Close()
+
+	return SearchLogText(io.LimitReader(stream, logSearchMaxBytes), opts)
+}
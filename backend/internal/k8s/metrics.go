@@ -0,0 +1,253 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// The resource metrics API served by metrics-server. It is read through the dynamic client
+// so clusters without metrics-server only lose the usage figures.
+const metricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+var (
+	podMetricsGVR  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+	nodeMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+)
+
+// Errors reporting why pod metrics could not be read
+var (
+	ErrPodNotFound        = errors.New("pod not found")
+	ErrMetricsUnavailable = errors.New("resource metrics unavailable")
+)
+
+// ResourceUsage is an amount of CPU and memory
+type ResourceUsage struct {
+	CPUMillicores int64 `json:"cpu_millicores"`
+	MemoryBytes   int64 `json:"memory_bytes"`
+}
+
+func (u *ResourceUsage) add(other ResourceUsage) {
+	u.CPUMillicores += other.CPUMillicores
+	u.MemoryBytes += other.MemoryBytes
+}
+
+// ContainerMetrics is the usage of a container next to its requests and limits. Zero
+// requests or limits are unset.
+type ContainerMetrics struct {
+	Name     string        `json:"name"`
+	Usage    ResourceUsage `json:"usage"`
+	Requests ResourceUsage `json:"requests"`
+	Limits   ResourceUsage `json:"limits"`
+}
+
+// NodeMetrics is the usage of the node a pod runs on next to its allocatable capacity
+type NodeMetrics struct {
+	Name        string        `json:"name"`
+	Usage       ResourceUsage `json:"usage"`
+	Allocatable ResourceUsage `json:"allocatable"`
+}
+
+// PodMetrics is the latest metrics-server sample of a pod, with totals over its containers
+type PodMetrics struct {
+	Namespace  string             `json:"namespace"`
+	Name       string             `json:"name"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Window     string             `json:"window"`
+	Usage      ResourceUsage      `json:"usage"`
+	Requests   ResourceUsage      `json:"requests"`
+	Limits     ResourceUsage      `json:"limits"`
+	Containers []ContainerMetrics `json:"containers"`
+	// Node is missing when the pod is not scheduled or the node has no sample yet
+	Node *NodeMetrics `json:"node,omitempty"`
+}
+
+// GetPodMetrics reads the CPU and memory usage of a pod and its node from metrics-server.
+// It returns ErrPodNotFound for a missing pod and ErrMetricsUnavailable when the cluster
+// does not serve the metrics API or has no sample of the pod yet.
+func (c *Client) GetPodMetrics(ctx context.Context, clusterID, namespace, name string) (*PodMetrics, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pod, err := typedClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s/%s", ErrPodNotFound, namespace, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	sample, err := client.Resource(podMetricsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, c.metricsError(clusterID, err, fmt.Sprintf("pod %s/%s", namespace, name))
+	}
+
+	metrics := &PodMetrics{
+		Namespace:  namespace,
+		Name:       name,
+		Timestamp:  sampleTimestamp(sample),
+		Window:     sampleWindow(sample),
+		Containers: []ContainerMetrics{},
+	}
+	usage := containerUsage(sample)
+	for _, container := range pod.Spec.Containers {
+		containerMetrics := ContainerMetrics{
+			Name:     container.Name,
+			Usage:    usage[container.Name],
+			Requests: listUsage(container.Resources.Requests),
+			Limits:   listUsage(container.Resources.Limits),
+		}
+		metrics.Usage.add(containerMetrics.Usage)
+		metrics.Requests.add(containerMetrics.Requests)
+		metrics.Limits.add(containerMetrics.Limits)
+		metrics.Containers = append(metrics.Containers, containerMetrics)
+	}
+
+	if pod.Spec.NodeName != "" {
+		metrics.Node = c.nodeMetrics(ctx, clusterID, client, pod.Spec.NodeName)
+	}
+	return metrics, nil
+}
+
+// nodeMetrics reads the usage and allocatable capacity of a node, or nil when either is
+// unavailable; node figures only give context to a pod's
+func (c *Client) nodeMetrics(ctx context.Context, clusterID string, client dynamic.Interface, nodeName string) *NodeMetrics {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil
+	}
+	node, err := typedClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	sample, err := client.Resource(nodeMetricsGVR).Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	cpu, _, _ := unstructured.NestedString(sample.Object, "usage", "cpu")
+	memory, _, _ := unstructured.NestedString(sample.Object, "usage", "memory")
+	return &NodeMetrics{
+		Name:        nodeName,
+		Usage:       parseUsage(cpu, memory),
+		Allocatable: listUsage(node.Status.Allocatable),
+	}
+}
+
+// listPodUsage returns the total usage of every sampled pod matching filter by
+// "namespace/name", or nil when the cluster does not serve the metrics API
+func (c *Client) listPodUsage(ctx context.Context, client dynamic.Interface, filter ListFilter) map[string]ResourceUsage {
+	// The metrics API supports label selectors but not the field selectors of pods
+	list, err := client.Resource(podMetricsGVR).List(ctx, metav1.ListOptions{LabelSelector: filter.LabelSelector})
+	if err != nil {
+		return nil
+	}
+
+	usage := make(map[string]ResourceUsage, len(list.Items))
+	for i := range list.Items {
+		var total ResourceUsage
+		for _, container := range containerUsage(&list.Items[i]) {
+			total.add(container)
+		}
+		usage[list.Items[i].GetNamespace()+"/"+list.Items[i].GetName()] = total
+	}
+	return usage
+}
+
+// metricsError translates a failed metrics API read, telling a cluster without
+// metrics-server apart from an object it has not sampled yet
+func (c *Client) metricsError(clusterID string, err error, object string) error {
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get metrics: %w", err)
+	}
+	typedClient, _ := c.typedClient(clusterID)
+	if _, discoveryErr := typedClient.Discovery().ServerResourcesForGroupVersion(metricsGroupVersion); discoveryErr != nil {
+		return fmt.Errorf("%w: the cluster does not serve %s; install metrics-server", ErrMetricsUnavailable, metricsGroupVersion)
+	}
+	return fmt.Errorf("%w: metrics-server has no sample of %s yet", ErrMetricsUnavailable, object)
+}
+
+// containerUsage returns the usage of each container in a PodMetrics object
+func containerUsage(sample *unstructured.Unstructured) map[string]ResourceUsage {
+	usage := map[string]ResourceUsage{}
+	containers, _, _ := unstructured.NestedSlice(sample.Object, "containers")
+	for _, item := range containers {
+		container, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		cpu, _, _ := unstructured.NestedString(container, "usage", "cpu")
+		memory, _, _ := unstructured.NestedString(container, "usage", "memory")
+		usage[name] = parseUsage(cpu, memory)
+	}
+	return usage
+}
+
+// parseUsage parses CPU and memory quantities, counting malformed ones as zero
+func parseUsage(cpu, memory string) ResourceUsage {
+	var usage ResourceUsage
+	if q, err := resource.ParseQuantity(cpu); err == nil {
+		usage.CPUMillicores = q.MilliValue()
+	}
+	if q, err := resource.ParseQuantity(memory); err == nil {
+		usage.MemoryBytes = q.Value()
+	}
+	return usage
+}
+
+// listUsage returns the CPU and memory of a resource list
+func listUsage(list corev1.ResourceList) ResourceUsage {
+	return ResourceUsage{
+		CPUMillicores: list.Cpu().MilliValue(),
+		MemoryBytes:   list.Memory().Value(),
+	}
+}
+
+func sampleTimestamp(sample *unstructured.Unstructured) time.Time {
+	timestamp, _, _ := unstructured.NestedString(sample.Object, "timestamp")
+	parsed, _ := time.Parse(time.RFC3339, timestamp)
+	return parsed
+}
+
+func sampleWindow(sample *unstructured.Unstructured) string {
+	window, _, _ := unstructured.NestedString(sample.Object, "window")
+	return window
+}
+
+// annotateUsage sets the usage of pods in a resource tree and sums it into the workloads
+// and Flux resources above them, returning the total of nodes
+func annotateUsage(nodes []ResourceNode, podUsage map[string]ResourceUsage) *ResourceUsage {
+	var total *ResourceUsage
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Kind == "Pod" {
+			if usage, ok := podUsage[node.Namespace+"/"+node.Name]; ok {
+				node.Usage = &usage
+			}
+		} else if children := annotateUsage(node.Children, podUsage); children != nil {
+			node.Usage = children
+		}
+		if node.Usage != nil {
+			if total == nil {
+				total = &ResourceUsage{}
+			}
+			total.add(*node.Usage)
+		}
+	}
+	return total
+}
@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ManagedByLabel marks namespaces created through the orchestrator
+const ManagedByLabel = "app.kubernetes.io/managed-by"
+
+// Errors reporting the state that stopped a namespace change
+var (
+	ErrNamespaceExists      = errors.New("namespace already exists")
+	ErrNamespaceNotFound    = errors.New("namespace not found")
+	ErrNamespaceTerminating = errors.New("namespace is already being deleted")
+)
+
+// NamespaceInfo summarizes a namespace
+type NamespaceInfo struct {
+	Name      string            `json:"name"`
+	Status    string            `json:"status"` // Active or Terminating
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"created_at"`
+	// Managed is set for namespaces created through the orchestrator
+	Managed bool `json:"managed"`
+}
+
+// ListNamespaces lists the namespaces of a cluster matching filter, sorted by name
+func (c *Client) ListNamespaces(ctx context.Context, clusterID string, filter ListFilter) ([]NamespaceInfo, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	list, err := typedClient.CoreV1().Namespaces().List(ctx, filter.listOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	namespaces := make([]NamespaceInfo, 0, len(list.Items))
+	for _, namespace := range list.Items {
+		labels := namespace.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		namespaces = append(namespaces, NamespaceInfo{
+			Name:      namespace.Name,
+			Status:    string(namespace.Status.Phase),
+			Labels:    labels,
+			CreatedAt: namespace.CreationTimestamp.Time,
+			Managed:   labels[ManagedByLabel] == applyFieldManager,
+		})
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces, nil
+}
+
+// NamespaceRequest is a namespace to create
+type NamespaceRequest struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// CreateNamespace creates a namespace labelled as managed by the orchestrator, with the
+// provenance annotations of ctx
+func (c *Client) CreateNamespace(ctx context.Context, clusterID string, req NamespaceRequest) error {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	// Provenance is set through an unstructured object to share applyProvenance
+	meta := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	meta.SetAnnotations(req.Annotations)
+	applyProvenance(ctx, meta)
+
+	labels := map[string]string{}
+	for key, value := range req.Labels {
+		labels[key] = value
+	}
+	labels[ManagedByLabel] = applyFieldManager
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        req.Name,
+		Labels:      labels,
+		Annotations: meta.GetAnnotations(),
+	}}
+
+	_, err := typedClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{FieldManager: applyFieldManager})
+	if apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("%w: %s", ErrNamespaceExists, req.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create namespace: %w", err)
+	}
+	return nil
+}
+
+// DeleteNamespace deletes a namespace and, through Kubernetes garbage collection, every
+// object in it
+func (c *Client) DeleteNamespace(ctx context.Context, clusterID, name string) error {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	namespace, err := typedClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: %s", ErrNamespaceNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get namespace: %w", err)
+	}
+	if namespace.DeletionTimestamp != nil {
+		return fmt.Errorf("%w: %s", ErrNamespaceTerminating, name)
+	}
+
+	// Preconditions make sure the namespace checked above is the one deleted
+	err = typedClient.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &namespace.UID},
+	})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: %s", ErrNamespaceNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete namespace: %w", err)
+	}
+	return nil
+}
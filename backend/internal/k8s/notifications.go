@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// IsNotificationKind reports whether kind is an Alert, Provider or Receiver
+func IsNotificationKind(kind string) bool {
+	return kind == "Alert" || kind == "Provider" || kind == "Receiver"
+}
+
+// reportsReadiness reports whether obj carries a Ready condition. Since v1beta3, Alerts
+// and Providers are static and the notification-controller no longer sets one.
+func reportsReadiness(obj *unstructured.Unstructured) bool {
+	if kind := obj.GetKind(); kind != "Alert" && kind != "Provider" {
+		return true
+	}
+	version := obj.GroupVersionKind().Version
+	return version == "v1beta1" || version == "v1beta2"
+}
+
+// CreateNotificationResource creates an Alert, Provider or Receiver from its manifest.
+// The apiVersion and kind are filled in when missing; the namespace argument wins over
+// the manifest's.
+func (c *Client) CreateNotificationResource(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	if !IsNotificationKind(kind) {
+		return fmt.Errorf("%s is not a notification-controller kind", kind)
+	}
+
+	return c.createResource(ctx, clusterID, kind, namespace, manifest)
+}
+
+// DeleteNotificationResource deletes an Alert, Provider or Receiver
+func (c *Client) DeleteNotificationResource(ctx context.Context, clusterID, kind, namespace, name string) error {
+	if !IsNotificationKind(kind) {
+		return fmt.Errorf("%s is not a notification-controller kind", kind)
+	}
+
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return err
+	}
+
+	gvr, err := c.getGVRForKind(clusterID, kind)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete resource: %w", err)
+	}
+
+	return nil
+}
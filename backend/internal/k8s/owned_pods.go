@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels the Flux controllers set on every object they apply
+var fluxOwnerLabels = map[string][2]string{
+	"Kustomization": {"kustomize.toolkit.fluxcd.io/name", "kustomize.toolkit.fluxcd.io/namespace"},
+	"HelmRelease":   {"helm.toolkit.fluxcd.io/name", "helm.toolkit.fluxcd.io/namespace"},
+}
+
+// OwnedWorkload is a workload applied by a Flux resource
+type OwnedWorkload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Desired   int32  `json:"desired"`
+	Ready     int32  `json:"ready"`
+
+	selector labels.Selector
+}
+
+// OwnsWorkloads reports whether a Flux kind applies workloads whose pods can be inspected
+func OwnsWorkloads(kind string) bool {
+	_, ok := fluxOwnerLabels[kind]
+	return ok
+}
+
+// fluxOwnerSelector returns the label selector matching objects applied by a Flux resource
+func fluxOwnerSelector(kind, namespace, name string) (string, error) {
+	keys, ok := fluxOwnerLabels[kind]
+	if !ok {
+		return "", fmt.Errorf("kind %s does not deploy workloads; expected Kustomization or HelmRelease", kind)
+	}
+	return labels.Set{keys[0]: name, keys[1]: namespace}.String(), nil
+}
+
+// getOwnedPods finds the workloads a Kustomization or HelmRelease applied and the pods
+// they run. Pods do not carry the Flux labels themselves, so they are matched through
+// the selectors of the labelled Deployments, StatefulSets, DaemonSets and Jobs.
+func (c *Client) getOwnedPods(ctx context.Context, clusterID, kind, namespace, name string) ([]OwnedWorkload, []corev1.Pod, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	selector, err := fluxOwnerSelector(kind, namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fail early with a clear error when the Flux resource itself does not exist
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	gvr, err := c.getGVRForKind(clusterID, kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return nil, nil, fmt.Errorf("failed to get flux resource: %w", err)
+	}
+
+	workloads, err := listOwnedWorkloads(ctx, typedClient, selector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]bool)
+	var pods []corev1.Pod
+	addPod := func(pod corev1.Pod) {
+		key := pod.Namespace + "/" + pod.Name
+		if !seen[key] {
+			seen[key] = true
+			pods = append(pods, pod)
+		}
+	}
+
+	// Bare pods applied directly by Flux carry the labels
+	direct, err := typedClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range direct.Items {
+		addPod(pod)
+	}
+
+	podsByNamespace := make(map[string][]corev1.Pod)
+	for _, workload := range workloads {
+		if workload.selector == nil || workload.selector.Empty() {
+			continue
+		}
+		if _, ok := podsByNamespace[workload.Namespace]; !ok {
+			list, err := typedClient.CoreV1().Pods(workload.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to list pods in %s: %w", workload.Namespace, err)
+			}
+			podsByNamespace[workload.Namespace] = list.Items
+		}
+		for _, pod := range podsByNamespace[workload.Namespace] {
+			if workload.selector.Matches(labels.Set(pod.Labels)) {
+				addPod(pod)
+			}
+		}
+	}
+
+	return workloads, pods, nil
+}
+
+// listOwnedWorkloads lists the pod-managing workloads carrying the Flux owner labels
+func listOwnedWorkloads(ctx context.Context, typedClient kubernetes.Interface, selector string) ([]OwnedWorkload, error) {
+	opts := metav1.ListOptions{LabelSelector: selector}
+	var workloads []OwnedWorkload
+
+	deployments, err := typedClient.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		workloads = append(workloads, newOwnedWorkload("Deployment", d.ObjectMeta, d.Spec.Selector, desired, d.Status.ReadyReplicas))
+	}
+
+	statefulSets, err := typedClient.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		desired := int32(1)
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		workloads = append(workloads, newOwnedWorkload("StatefulSet", s.ObjectMeta, s.Spec.Selector, desired, s.Status.ReadyReplicas))
+	}
+
+	daemonSets, err := typedClient.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		workloads = append(workloads, newOwnedWorkload("DaemonSet", d.ObjectMeta, d.Spec.Selector, d.Status.DesiredNumberScheduled, d.Status.NumberReady))
+	}
+
+	jobs, err := typedClient.BatchV1().Jobs(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, j := range jobs.Items {
+		desired := int32(1)
+		if j.Spec.Completions != nil {
+			desired = *j.Spec.Completions
+		}
+		workloads = append(workloads, newOwnedWorkload("Job", j.ObjectMeta, j.Spec.Selector, desired, j.Status.Succeeded))
+	}
+
+	return workloads, nil
+}
+
+// newOwnedWorkload records a workload and its pod selector
+func newOwnedWorkload(kind string, meta metav1.ObjectMeta, selector *metav1.LabelSelector, desired, ready int32) OwnedWorkload {
+	workload := OwnedWorkload{
+		Kind:      kind,
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		Desired:   desired,
+		Ready:     ready,
+	}
+	if selector != nil {
+		if parsed, err := metav1.LabelSelectorAsSelector(selector); err == nil {
+			workload.selector = parsed
+		}
+	}
+	return workload
+}
@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ResourcePatch is a surgical update in one of the patch formats Kubernetes accepts
+type ResourcePatch struct {
+	Type types.PatchType // JSON patch, JSON merge patch or strategic merge patch
+	Data []byte
+}
+
+// InvalidPatchError is returned when the API server rejects a patch, such as a JSON patch
+// whose path does not exist or a strategic merge patch of a custom resource
+type InvalidPatchError struct {
+	Reason string
+}
+
+func (e *InvalidPatchError) Error() string {
+	return e.Reason
+}
+
+// PatchResource patches a resource as the orchestrator's field manager, changing only what
+// the patch names. Strategic merge patches are only supported by built-in kinds; custom
+// resources such as Flux's take a JSON merge patch instead.
+func (c *Client) PatchResource(ctx context.Context, clusterID, kind, namespace, name string, patch ResourcePatch) error {
+	resource, gvr, err := c.GetResourceByKind(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		return err
+	}
+	return c.patch(ctx, clusterID, gvr, resource, kind, patch)
+}
+
+// PatchFluxResource patches a Flux resource like PatchResource, refusing any other kind
+func (c *Client) PatchFluxResource(ctx context.Context, clusterID, kind, namespace, name string, patch ResourcePatch) error {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return err
+	}
+
+	gvr, err := c.getGVRForKind(clusterID, kind)
+	if err != nil {
+		return err
+	}
+
+	resource, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get resource: %w", err)
+	}
+	return c.patch(ctx, clusterID, gvr, resource, kind, patch)
+}
+
+// patch applies a patch to a live resource of gvr
+func (c *Client) patch(ctx context.Context, clusterID string, gvr schema.GroupVersionResource, resource *unstructured.Unstructured, kind string, patch ResourcePatch) error {
+	data, err := withProvenancePatch(ctx, resource, patch)
+	if err != nil {
+		return &InvalidPatchError{Reason: err.Error()}
+	}
+
+	client, _ := c.GetClient(clusterID)
+	_, err = client.Resource(gvr).Namespace(resource.GetNamespace()).Patch(ctx, resource.GetName(), patch.Type, data, metav1.PatchOptions{FieldManager: applyFieldManager})
+	switch {
+	case apierrors.IsUnsupportedMediaType(err):
+		return &InvalidPatchError{Reason: fmt.Sprintf("%s does not support %s patches; custom resources take a JSON merge patch (application/merge-patch+json)", kind, patch.Type)}
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return &InvalidPatchError{Reason: err.Error()}
+	case apierrors.IsConflict(err):
+		return &UpdateConflictError{Reason: err.Error()}
+	case err != nil:
+		return fmt.Errorf("failed to patch resource: %w", err)
+	}
+	return nil
+}
+
+// withProvenancePatch returns a patch's data extended to set the provenance annotations
+func withProvenancePatch(ctx context.Context, live *unstructured.Unstructured, patch ResourcePatch) ([]byte, error) {
+	provenance := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	applyProvenance(ctx, provenance)
+	annotations := provenance.GetAnnotations()
+	if len(annotations) == 0 {
+		return patch.Data, nil
+	}
+
+	if patch.Type == types.JSONPatchType {
+		var operations []interface{}
+		if err := json.Unmarshal(patch.Data, &operations); err != nil {
+			return nil, fmt.Errorf("invalid JSON patch: %w", err)
+		}
+		if live.GetAnnotations() == nil {
+			operations = append(operations, map[string]interface{}{"op": "add", "path": "/metadata/annotations", "value": annotations})
+		} else {
+			for key, value := range annotations {
+				path := "/metadata/annotations/" + strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+				operations = append(operations, map[string]interface{}{"op": "add", "path": path, "value": value})
+			}
+		}
+		return json.Marshal(operations)
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(patch.Data, &object); err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	for key, value := range annotations {
+		if err := unstructured.SetNestedField(object, value, "metadata", "annotations", key); err != nil {
+			return nil, fmt.Errorf("invalid patch: %w", err)
+		}
+	}
+	return json.Marshal(object)
+}
@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PodDeletionRequest selects pods in one namespace by label selector or by name
+type PodDeletionRequest struct {
+	Selector string   `json:"selector"`
+	Pods     []string `json:"pods"`
+	MaxCount int      `json:"max_count"` // refuse to delete more pods than this
+	DryRun   bool     `json:"dry_run"`   // only list the pods that would be deleted
+	Evict    bool     `json:"evict"`     // use the Eviction API so PodDisruptionBudgets apply
+}
+
+// PodDeletionFailure is a pod that could not be deleted
+type PodDeletionFailure struct {
+	Pod   string `json:"pod"`
+	Error string `json:"error"`
+}
+
+// PodDeletionResult lists the matched pods and what happened to them
+type PodDeletionResult struct {
+	Namespace  string               `json:"namespace"`
+	Selector   string               `json:"selector,omitempty"`
+	DryRun     bool                 `json:"dry_run"`
+	Evict      bool                 `json:"evict"`
+	Matched    []string             `json:"matched"`
+	Deleted    []string             `json:"deleted"`
+	Missing    []string             `json:"missing,omitempty"` // named pods that do not exist
+	Failed     []PodDeletionFailure `json:"failed,omitempty"`
+	Blocked    []PodDeletionFailure `json:"blocked,omitempty"`     // evictions refused by a PodDisruptionBudget
+	RetryAfter int                  `json:"retry_after,omitempty"` // seconds to wait before retrying blocked pods
+}
+
+// TooManyPodsError is returned when a batch deletion matches more pods than its MaxCount
+type TooManyPodsError struct {
+	Matched  int
+	MaxCount int
+}
+
+func (e *TooManyPodsError) Error() string {
+	return fmt.Sprintf("%d pods matched, more than the maximum of %d", e.Matched, e.MaxCount)
+}
+
+// PodEvictionBlockedError is returned when evicting a pod would violate a
+// PodDisruptionBudget
+type PodEvictionBlockedError struct {
+	Pod        string
+	Message    string
+	RetryAfter int // seconds suggested by the API server, 0 when unknown
+}
+
+func (e *PodEvictionBlockedError) Error() string {
+	return fmt.Sprintf("eviction of pod %s blocked: %s", e.Pod, e.Message)
+}
+
+// Validate checks that exactly one of Selector and Pods is set and the selector parses
+func (r *PodDeletionRequest) Validate() error {
+	if (r.Selector == "") == (len(r.Pods) == 0) {
+		return fmt.Errorf("exactly one of selector or pods is required")
+	}
+	if r.Selector != "" {
+		selector, err := labels.Parse(r.Selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+		if selector.Empty() {
+			return fmt.Errorf("selector must not match every pod")
+		}
+	}
+	if r.MaxCount <= 0 {
+		return fmt.Errorf("max_count must be positive")
+	}
+	return nil
+}
+
+// DeletePods deletes the pods of a namespace matching a label selector or a list of
+// names. Nothing is deleted when more pods match than req.MaxCount; with req.DryRun the
+// matched pods are only listed.
+func (c *Client) DeletePods(ctx context.Context, clusterID, namespace string, req PodDeletionRequest) (*PodDeletionResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	pods := typedClient.CoreV1().Pods(namespace)
+
+	result := &PodDeletionResult{
+		Namespace: namespace,
+		Selector:  req.Selector,
+		DryRun:    req.DryRun,
+		Evict:     req.Evict,
+		Matched:   []string{},
+		Deleted:   []string{},
+	}
+
+	if req.Selector != "" {
+		list, err := pods.List(ctx, metav1.ListOptions{LabelSelector: req.Selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		for _, pod := range list.Items {
+			result.Matched = append(result.Matched, pod.Name)
+		}
+	} else {
+		seen := make(map[string]bool, len(req.Pods))
+		for _, name := range req.Pods {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if _, err := pods.Get(ctx, name, metav1.GetOptions{}); err != nil {
+				if apierrors.IsNotFound(err) {
+					result.Missing = append(result.Missing, name)
+					continue
+				}
+				return nil, fmt.Errorf("failed to get pod %s: %w", name, err)
+			}
+			result.Matched = append(result.Matched, name)
+		}
+	}
+	sort.Strings(result.Matched)
+
+	if len(result.Matched) > req.MaxCount {
+		return result, &TooManyPodsError{Matched: len(result.Matched), MaxCount: req.MaxCount}
+	}
+	if req.DryRun {
+		return result, nil
+	}
+
+	for _, name := range result.Matched {
+		var err error
+		if req.Evict {
+			err = evictPod(ctx, pods, namespace, name)
+		} else {
+			err = pods.Delete(ctx, name, metav1.DeleteOptions{})
+		}
+		var blocked *PodEvictionBlockedError
+		if errors.As(err, &blocked) {
+			result.Blocked = append(result.Blocked, PodDeletionFailure{Pod: name, Error: blocked.Message})
+			result.RetryAfter = max(result.RetryAfter, blocked.RetryAfter)
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			result.Failed = append(result.Failed, PodDeletionFailure{Pod: name, Error: err.Error()})
+			continue
+		}
+		result.Deleted = append(result.Deleted, name)
+	}
+	return result, nil
+}
+
+// EvictPod evicts a pod through the Eviction API, which refuses with a
+// PodEvictionBlockedError when a PodDisruptionBudget does not allow the disruption
+func (c *Client) EvictPod(ctx context.Context, clusterID, namespace, name string) error {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	if err := evictPod(ctx, typedClient.CoreV1().Pods(namespace), namespace, name); err != nil {
+		var blocked *PodEvictionBlockedError
+		if errors.As(err, &blocked) {
+			return err
+		}
+		return fmt.Errorf("failed to evict pod: %w", err)
+	}
+	return nil
+}
+
+// evictPod posts an Eviction for a pod; the API server answers 429 when a
+// PodDisruptionBudget blocks it
+func evictPod(ctx context.Context, pods corev1client.PodInterface, namespace, name string) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	err := pods.EvictV1(ctx, eviction)
+	if apierrors.IsTooManyRequests(err) {
+		retryAfter, _ := apierrors.SuggestsClientDelay(err)
+		return &PodEvictionBlockedError{Pod: name, Message: err.Error(), RetryAfter: retryAfter}
+	}
+	return err
+}
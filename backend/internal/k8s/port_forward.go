@@ -0,0 +1,203 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// maxPortForwardBody caps the response body returned from a forwarded request
+const maxPortForwardBody = 1 << 20
+
+// portForwardErrorWait is how long to wait for the kubelet to explain a failed forward
+const portForwardErrorWait = 2 * time.Second
+
+// portForwardMethods are the HTTP methods a forwarded request may use
+var portForwardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// PortForwardRequest is an HTTP request sent to a pod port through a port-forward
+type PortForwardRequest struct {
+	Port    int               `json:"port"`
+	Method  string            `json:"method"` // defaults to GET
+	Path    string            `json:"path"`   // defaults to /, may include a query
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// PortForwardResponse is the pod's response to a forwarded request
+type PortForwardResponse struct {
+	StatusCode int               `json:"status_code"`
+	Status     string            `json:"status"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+	Truncated  bool              `json:"truncated"` // body was longer than 1 MiB
+	DurationMS int64             `json:"duration_ms"`
+}
+
+// Validate fills in defaults and checks the request
+func (r *PortForwardRequest) Validate() error {
+	if r.Port < 1 || r.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	if r.Method == "" {
+		r.Method = http.MethodGet
+	}
+	r.Method = strings.ToUpper(r.Method)
+	if !portForwardMethods[r.Method] {
+		return fmt.Errorf("method %s is not allowed", r.Method)
+	}
+	if r.Path == "" {
+		r.Path = "/"
+	}
+	if !strings.HasPrefix(r.Path, "/") {
+		return fmt.Errorf("path must start with /")
+	}
+	return nil
+}
+
+// PortForwardHTTP opens a port-forward to a pod, sends one HTTP request through it and
+// returns the response. Nothing listens locally; the request is written straight to the
+// forwarded stream, which is closed once the response is read.
+func (c *Client) PortForwardHTTP(ctx context.Context, clusterID, namespace, pod string, req PortForwardRequest) (*PortForwardResponse, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+	config, ok := c.restConfig(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("port-forward is not supported on cluster %s", clusterID)
+	}
+	config = c.impersonatedConfig(ctx, clusterID, config)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	url := typedClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward").
+		URL()
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward: %w", err)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", url)
+
+	// Prefer tunneling over WebSockets and fall back to SPDY for API servers older than 1.31
+	tunnelingDialer, err := portforward.NewSPDYOverWebsocketDialer(url, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward: %w", err)
+	}
+	dialer = portforward.NewFallbackDialer(tunnelingDialer, dialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+
+	conn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open port-forward: %w", err)
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks reads when the request is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, corev1.StreamTypeError)
+	headers.Set(corev1.PortHeader, strconv.Itoa(req.Port))
+	headers.Set(corev1.PortForwardRequestIDHeader, "0")
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create error stream: %w", err)
+	}
+	// Nothing is written to the error stream
+	errorStream.Close()
+	forwardErr := make(chan error, 1)
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			forwardErr <- err
+		case len(message) > 0:
+			forwardErr <- errors.New(string(message))
+		}
+		close(forwardErr)
+	}()
+
+	headers.Set(corev1.StreamType, corev1.StreamTypeData)
+	dataStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data stream: %w", err)
+	}
+	defer dataStream.Reset()
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, fmt.Sprintf("http://localhost:%d%s", req.Port, req.Path), strings.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	httpReq.Close = true
+
+	start := time.Now()
+	if err := httpReq.Write(dataStream); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(dataStream), httpReq)
+	if err != nil {
+		// The kubelet reports why it could not connect, such as nothing listening on the port
+		select {
+		case reason, ok := <-forwardErr:
+			if ok && reason != nil {
+				return nil, fmt.Errorf("port-forward to %s:%d failed: %w", pod, req.Port, reason)
+			}
+		case <-time.After(portForwardErrorWait):
+		}
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPortForwardBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	result := &PortForwardResponse{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    make(map[string]string, len(resp.Header)),
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	for key, values := range resp.Header {
+		result.Headers[key] = strings.Join(values, ", ")
+	}
+	if len(body) > maxPortForwardBody {
+		body, result.Truncated = body[:maxPortForwardBody], true
+	}
+	result.Body = string(body)
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package k8s
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Provenance annotations written on objects mutated through the orchestrator
+const (
+	AnnotationLastAction = "fluxorchestrator.io/last-action"
+	AnnotationActor      = "fluxorchestrator.io/actor"
+	AnnotationRequestID  = "fluxorchestrator.io/request-id"
+)
+
+// Provenance describes who triggered a change and from which API request
+type Provenance struct {
+	Action    string
+	Actor     string
+	RequestID string
+}
+
+type provenanceContextKey struct{}
+
+// WithProvenance returns a context carrying provenance for mutating calls
+func WithProvenance(ctx context.Context, p Provenance) context.Context {
+	return context.WithValue(ctx, provenanceContextKey{}, p)
+}
+
+// ProvenanceFromContext returns the provenance stored in the context, if any
+func ProvenanceFromContext(ctx context.Context) (Provenance, bool) {
+	p, ok := ctx.Value(provenanceContextKey{}).(Provenance)
+	return p, ok
+}
+
+// applyProvenance stamps the provenance annotations from ctx onto obj
+func applyProvenance(ctx context.Context, obj *unstructured.Unstructured) {
+	p, ok := ProvenanceFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if p.Action != "" {
+		annotations[AnnotationLastAction] = p.Action
+	}
+	if p.Actor != "" {
+		annotations[AnnotationActor] = p.Actor
+	}
+	if p.RequestID != "" {
+		annotations[AnnotationRequestID] = p.RequestID
+	}
+	obj.SetAnnotations(annotations)
+}
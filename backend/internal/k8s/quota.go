@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultQuotaThreshold is the fraction of a quota at which a namespace counts as near its limit
+const DefaultQuotaThreshold = 0.9
+
+// QuotaUsage is the consumption of one resource tracked by a ResourceQuota
+type QuotaUsage struct {
+	Resource string  `json:"resource"`
+	Hard     string  `json:"hard"`
+	Used     string  `json:"used"`
+	Ratio    float64 `json:"ratio"` // used / hard; 1 when the hard limit is zero
+}
+
+// ResourceQuotaSummary summarizes a ResourceQuota object
+type ResourceQuotaSummary struct {
+	Name      string       `json:"name"`
+	Usage     []QuotaUsage `json:"usage"`
+	MaxRatio  float64      `json:"max_ratio"`
+	NearLimit bool         `json:"near_limit"`
+	Exceeded  bool         `json:"exceeded"`
+}
+
+// LimitRangeItem is one limit of a LimitRange, for a single resource and object type
+type LimitRangeItem struct {
+	Type           string `json:"type"` // Container, Pod, PersistentVolumeClaim
+	Resource       string `json:"resource"`
+	Min            string `json:"min,omitempty"`
+	Max            string `json:"max,omitempty"`
+	Default        string `json:"default,omitempty"`
+	DefaultRequest string `json:"default_request,omitempty"`
+}
+
+// LimitRangeSummary summarizes a LimitRange object
+type LimitRangeSummary struct {
+	Name   string           `json:"name"`
+	Limits []LimitRangeItem `json:"limits"`
+}
+
+// NamespaceQuotas collects the ResourceQuotas and LimitRanges of a namespace
+type NamespaceQuotas struct {
+	Namespace   string                 `json:"namespace"`
+	Quotas      []ResourceQuotaSummary `json:"quotas"`
+	LimitRanges []LimitRangeSummary    `json:"limit_ranges"`
+	MaxRatio    float64                `json:"max_ratio"`
+	NearLimit   bool                   `json:"near_limit"`
+	Exceeded    bool                   `json:"exceeded"`
+}
+
+// GetNamespaceQuotas summarizes ResourceQuota and LimitRange objects per namespace. Quotas
+// using at least threshold of any resource are flagged as near their limit.
+func (c *Client) GetNamespaceQuotas(ctx context.Context, clusterID string, threshold float64) ([]NamespaceQuotas, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	quotas, err := typedClient.CoreV1().ResourceQuotas(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	limitRanges, err := typedClient.CoreV1().LimitRanges(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list limit ranges: %w", err)
+	}
+
+	return SummarizeQuotas(quotas.Items, limitRanges.Items, threshold), nil
+}
+
+// SummarizeQuotas groups quotas and limit ranges by namespace, sorted by namespace
+func SummarizeQuotas(quotas []corev1.ResourceQuota, limitRanges []corev1.LimitRange, threshold float64) []NamespaceQuotas {
+	if threshold <= 0 {
+		threshold = DefaultQuotaThreshold
+	}
+
+	byNamespace := make(map[string]*NamespaceQuotas)
+	namespace := func(name string) *NamespaceQuotas {
+		ns, ok := byNamespace[name]
+		if !ok {
+			ns = &NamespaceQuotas{Namespace: name, Quotas: []ResourceQuotaSummary{}, LimitRanges: []LimitRangeSummary{}}
+			byNamespace[name] = ns
+		}
+		return ns
+	}
+
+	for _, quota := range quotas {
+		summary := summarizeQuota(quota, threshold)
+		ns := namespace(quota.Namespace)
+		ns.Quotas = append(ns.Quotas, summary)
+		if summary.MaxRatio > ns.MaxRatio {
+			ns.MaxRatio = summary.MaxRatio
+		}
+		ns.NearLimit = ns.NearLimit || summary.NearLimit
+		ns.Exceeded = ns.Exceeded || summary.Exceeded
+	}
+
+	for _, limitRange := range limitRanges {
+		ns := namespace(limitRange.Namespace)
+		ns.LimitRanges = append(ns.LimitRanges, summarizeLimitRange(limitRange))
+	}
+
+	result := make([]NamespaceQuotas, 0, len(byNamespace))
+	for _, ns := range byNamespace {
+		result = append(result, *ns)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Namespace < result[j].Namespace })
+	return result
+}
+
+// summarizeQuota computes the usage ratio of every resource a quota limits
+func summarizeQuota(quota corev1.ResourceQuota, threshold float64) ResourceQuotaSummary {
+	summary := ResourceQuotaSummary{Name: quota.Name, Usage: []QuotaUsage{}}
+
+	hard := quota.Status.Hard
+	if len(hard) == 0 {
+		// Not yet processed by the quota controller
+		hard = quota.Spec.Hard
+	}
+	for name, hardQuantity := range hard {
+		used := quota.Status.Used[name]
+		usage := QuotaUsage{
+			Resource: string(name),
+			Hard:     hardQuantity.String(),
+			Used:     used.String(),
+			Ratio:    quantityRatio(used, hardQuantity),
+		}
+		summary.Usage = append(summary.Usage, usage)
+		if usage.Ratio > summary.MaxRatio {
+			summary.MaxRatio = usage.Ratio
+		}
+	}
+	sort.Slice(summary.Usage, func(i, j int) bool { return summary.Usage[i].Resource < summary.Usage[j].Resource })
+
+	summary.NearLimit = summary.MaxRatio >= threshold
+	summary.Exceeded = summary.MaxRatio >= 1
+	return summary
+}
+
+// quantityRatio returns used / hard, treating a zero hard limit as fully consumed
+func quantityRatio(used, hard resource.Quantity) float64 {
+	hardValue := hard.AsApproximateFloat64()
+	if hardValue <= 0 {
+		return 1
+	}
+	return used.AsApproximateFloat64() / hardValue
+}
+
+// summarizeLimitRange flattens a LimitRange into one item per object type and resource
+func summarizeLimitRange(limitRange corev1.LimitRange) LimitRangeSummary {
+	summary := LimitRangeSummary{Name: limitRange.Name, Limits: []LimitRangeItem{}}
+
+	for _, limit := range limitRange.Spec.Limits {
+		resources := make(map[corev1.ResourceName]bool)
+		for _, list := range []corev1.ResourceList{limit.Min, limit.Max, limit.Default, limit.DefaultRequest} {
+			for name := range list {
+				resources[name] = true
+			}
+		}
+		for name := range resources {
+			summary.Limits = append(summary.Limits, LimitRangeItem{
+				Type:           string(limit.Type),
+				Resource:       string(name),
+				Min:            quantityString(limit.Min, name),
+				Max:            quantityString(limit.Max, name),
+				Default:        quantityString(limit.Default, name),
+				DefaultRequest: quantityString(limit.DefaultRequest, name),
+			})
+		}
+	}
+	sort.Slice(summary.Limits, func(i, j int) bool {
+		if summary.Limits[i].Type != summary.Limits[j].Type {
+			return summary.Limits[i].Type < summary.Limits[j].Type
+		}
+		return summary.Limits[i].Resource < summary.Limits[j].Resource
+	})
+	return summary
+}
+
+// quantityString formats a quantity from a resource list, or "" when it is not set
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	quantity, ok := list[name]
+	if !ok {
+		return ""
+	}
+	return quantity.String()
+}
@@ -0,0 +1,347 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Problems that keep a resource from ever being reconciled in dependency order
+const (
+	OrderIssueCycle             = "cycle"
+	OrderIssueMissingDependency = "missing_dependency"
+)
+
+// ReconcileOrderNode is a Kustomization or HelmRelease placed in the reconcile order
+type ReconcileOrderNode struct {
+	EventObject
+	Stage     int           `json:"stage"` // -1 when blocked by a cycle or missing dependency
+	DependsOn []EventObject `json:"depends_on"`
+	Source    *EventObject  `json:"source,omitempty"`
+	Affected  bool          `json:"affected"` // reconciles when the requested source updates
+	Suspended bool          `json:"suspended"`
+	Ready     bool          `json:"ready"`
+	Waiting   bool          `json:"waiting"`           // a dependency is not Ready, so Flux holds the resource back
+	Message   string        `json:"message,omitempty"` // why the resource is blocked or waiting
+}
+
+// ReconcileOrderIssue is a dependency cycle or a dependsOn reference to a missing resource
+type ReconcileOrderIssue struct {
+	Type    string        `json:"type"`
+	Objects []EventObject `json:"objects"` // the cycle in order, or the dependent and its missing dependency
+	Message string        `json:"message"`
+}
+
+// ReconcileOrder is the order Flux reconciles Kustomizations and HelmReleases in, following
+// spec.dependsOn. Resources in a stage wait for every earlier stage they depend on to be
+// Ready; resources in the same stage reconcile concurrently.
+type ReconcileOrder struct {
+	Source  *EventObject           `json:"source,omitempty"`
+	Stages  [][]ReconcileOrderNode `json:"stages"`
+	Blocked []ReconcileOrderNode   `json:"blocked"`
+	Issues  []ReconcileOrderIssue  `json:"issues"`
+}
+
+// GetReconcileOrder computes the dependsOn order of the Kustomizations and HelmReleases on a
+// cluster. With a source, only the resources that reconcile when it produces a new
+// artifact are included, along with the dependencies they wait for.
+func (c *Client) GetReconcileOrder(ctx context.Context, clusterID string, source *EventObject) (*ReconcileOrder, error) {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []unstructured.Unstructured
+	for _, kind := range []string{"Kustomization", "HelmRelease"} {
+		gvr, err := c.getGVRForKind(clusterID, kind)
+		if err != nil {
+			return nil, err
+		}
+		list, err := client.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+		}
+		for _, item := range list.Items {
+			// Lists of custom resources may omit the kind of their items
+			item.SetKind(kind)
+			objects = append(objects, item)
+		}
+	}
+
+	return reconcileOrder(objects, source), nil
+}
+
+// reconcileOrder places each resource one stage after its deepest dependency. Resources
+// in a dependency cycle, or depending on a missing resource or on a blocked one, are
+// never reconciled by Flux and are reported as blocked.
+func reconcileOrder(objects []unstructured.Unstructured, source *EventObject) *ReconcileOrder {
+	nodes := make(map[string]*ReconcileOrderNode, len(objects))
+	var keys []string
+	for i := range objects {
+		node := orderNode(&objects[i])
+		key := orderKey(node.EventObject)
+		nodes[key] = node
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	order := &ReconcileOrder{Source: source, Stages: [][]ReconcileOrderNode{}, Blocked: []ReconcileOrderNode{}, Issues: []ReconcileOrderIssue{}}
+
+	for _, key := range keys {
+		node := nodes[key]
+		for _, dep := range node.DependsOn {
+			if _, ok := nodes[orderKey(dep)]; !ok {
+				order.Issues = append(order.Issues, ReconcileOrderIssue{
+					Type:    OrderIssueMissingDependency,
+					Objects: []EventObject{node.EventObject, dep},
+					Message: fmt.Sprintf("%s depends on %s, which does not exist", orderKey(node.EventObject), orderKey(dep)),
+				})
+			}
+		}
+	}
+	for _, cycle := range dependencyCycles(nodes, keys) {
+		objects := make([]EventObject, len(cycle))
+		names := make([]string, len(cycle))
+		for i, key := range cycle {
+			objects[i] = nodes[key].EventObject
+			names[i] = key
+		}
+		order.Issues = append(order.Issues, ReconcileOrderIssue{
+			Type:    OrderIssueCycle,
+			Objects: objects,
+			Message: fmt.Sprintf("Dependency cycle: %s -> %s", strings.Join(names, " -> "), names[0]),
+		})
+		for _, key := range cycle {
+			nodes[key].Message = "in a dependency cycle"
+		}
+	}
+
+	// Stages are resolved depth first. Nodes on a reported cycle already carry a message;
+	// reaching a node still being resolved means another cycle, which blocks it too.
+	resolved := make(map[string]bool)
+	resolving := make(map[string]bool)
+	var resolve func(key string) int
+	resolve = func(key string) int {
+		node := nodes[key]
+		if resolving[key] {
+			return -1
+		}
+		if resolved[key] {
+			return node.Stage
+		}
+		resolving[key] = true
+		defer func() {
+			resolving[key] = false
+			resolved[key] = true
+		}()
+		if node.Message != "" {
+			node.Stage = -1
+			return -1
+		}
+		node.Stage = 0
+		for _, dep := range node.DependsOn {
+			depKey := orderKey(dep)
+			if _, ok := nodes[depKey]; !ok {
+				node.Stage, node.Message = -1, fmt.Sprintf("dependency %s does not exist", depKey)
+				return -1
+			}
+			stage := resolve(depKey)
+			if stage < 0 {
+				node.Stage, node.Message = -1, fmt.Sprintf("dependency %s is blocked", depKey)
+				return -1
+			}
+			if stage+1 > node.Stage {
+				node.Stage = stage + 1
+			}
+		}
+		return node.Stage
+	}
+	for _, key := range keys {
+		resolve(key)
+	}
+	for _, key := range keys {
+		node := nodes[key]
+		if node.Stage < 0 {
+			continue
+		}
+		for _, dep := range node.DependsOn {
+			if !nodes[orderKey(dep)].Ready {
+				node.Waiting = true
+				node.Message = fmt.Sprintf("waits for %s, which is not Ready", orderKey(dep))
+				break
+			}
+		}
+	}
+
+	// With a source, keep what it triggers and the dependencies those wait for
+	included := make(map[string]bool)
+	var include func(key string)
+	include = func(key string) {
+		node, ok := nodes[key]
+		if !ok || included[key] {
+			return
+		}
+		included[key] = true
+		for _, dep := range node.DependsOn {
+			include(orderKey(dep))
+		}
+	}
+	for _, key := range keys {
+		node := nodes[key]
+		if source == nil {
+			included[key] = true
+			continue
+		}
+		if node.Source != nil && *node.Source == *source {
+			node.Affected = true
+			include(key)
+		}
+	}
+
+	for _, key := range keys {
+		node := nodes[key]
+		if !included[key] {
+			continue
+		}
+		if node.Stage < 0 {
+			order.Blocked = append(order.Blocked, *node)
+			continue
+		}
+		for len(order.Stages) <= node.Stage {
+			order.Stages = append(order.Stages, []ReconcileOrderNode{})
+		}
+		order.Stages[node.Stage] = append(order.Stages[node.Stage], *node)
+	}
+	if source != nil {
+		issues := order.Issues[:0]
+		for _, issue := range order.Issues {
+			if included[orderKey(issue.Objects[0])] {
+				issues = append(issues, issue)
+			}
+		}
+		order.Issues = issues
+	}
+	return order
+}
+
+// orderNode reads the dependencies, source and state of a Kustomization or HelmRelease
+func orderNode(obj *unstructured.Unstructured) *ReconcileOrderNode {
+	node := &ReconcileOrderNode{
+		EventObject: EventObject{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		DependsOn:   []EventObject{},
+	}
+	node.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	node.Ready, _ = readyCondition(obj)
+
+	// dependsOn refers to resources of the same kind, in the same namespace by default
+	deps, _, _ := unstructured.NestedSlice(obj.Object, "spec", "dependsOn")
+	for _, item := range deps {
+		ref, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dep := EventObject{Kind: node.Kind, Namespace: node.Namespace}
+		dep.Name, _ = ref["name"].(string)
+		if namespace, _ := ref["namespace"].(string); namespace != "" {
+			dep.Namespace = namespace
+		}
+		if dep.Name != "" {
+			node.DependsOn = append(node.DependsOn, dep)
+		}
+	}
+
+	switch node.Kind {
+	case "Kustomization":
+		if ref, err := kustomizationSourceRef(obj); err == nil {
+			node.Source = &ref
+		}
+	case "HelmRelease":
+		// A chart from a HelmRepository, GitRepository or Bucket is rebuilt when that
+		// source updates; a chartRef points straight at the source
+		path := []string{"spec", "chart", "spec", "sourceRef"}
+		if kind, _, _ := unstructured.NestedString(obj.Object, "spec", "chartRef", "kind"); kind != "" {
+			path = []string{"spec", "chartRef"}
+		}
+		ref := EventObject{Namespace: node.Namespace}
+		ref.Kind, _, _ = unstructured.NestedString(obj.Object, append(path, "kind")...)
+		ref.Name, _, _ = unstructured.NestedString(obj.Object, append(path, "name")...)
+		if namespace, _, _ := unstructured.NestedString(obj.Object, append(path, "namespace")...); namespace != "" {
+			ref.Namespace = namespace
+		}
+		if ref.Kind != "" && ref.Name != "" {
+			node.Source = &ref
+		}
+	}
+	return node
+}
+
+// dependencyCycles returns every dependency cycle once, each starting at its smallest key
+func dependencyCycles(nodes map[string]*ReconcileOrderNode, keys []string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(nodes))
+	seen := make(map[string]bool)
+	var cycles [][]string
+	var stack []string
+
+	var visit func(key string)
+	visit = func(key string) {
+		state[key] = visiting
+		stack = append(stack, key)
+		for _, dep := range nodes[key].DependsOn {
+			depKey := orderKey(dep)
+			if _, ok := nodes[depKey]; !ok {
+				continue
+			}
+			switch state[depKey] {
+			case unvisited:
+				visit(depKey)
+			case visiting:
+				start := len(stack) - 1
+				for stack[start] != depKey {
+					start--
+				}
+				cycle := rotateToSmallest(append([]string{}, stack[start:]...))
+				if id := strings.Join(cycle, "|"); !seen[id] {
+					seen[id] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[key] = done
+	}
+	for _, key := range keys {
+		if state[key] == unvisited {
+			visit(key)
+		}
+	}
+	return cycles
+}
+
+// rotateToSmallest rotates a cycle so it starts at its smallest key
+func rotateToSmallest(cycle []string) []string {
+	smallest := 0
+	for i := range cycle {
+		if cycle[i] < cycle[smallest] {
+			smallest = i
+		}
+	}
+	return append(cycle[smallest:], cycle[:smallest]...)
+}
+
+// orderKey identifies a resource as Kind/namespace/name
+func orderKey(obj EventObject) string {
+	return obj.Kind + "/" + obj.Namespace + "/" + obj.Name
+}
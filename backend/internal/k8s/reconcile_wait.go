@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// reconcileWaitPollInterval is how often a resource is checked while waiting for it to
+// finish reconciling
+const reconcileWaitPollInterval = 2 * time.Second
+
+// ReconcileCondition is a status condition of a Flux resource
+type ReconcileCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ReconcileProgress is the state of a Flux resource while it handles a reconcile request
+type ReconcileProgress struct {
+	Phase      string               `json:"phase"`   // pending, progressing, ready, failed
+	Handled    bool                 `json:"handled"` // the controller has picked up the request
+	Reason     string               `json:"reason,omitempty"`
+	Message    string               `json:"message,omitempty"`
+	Revision   string               `json:"revision,omitempty"`
+	Conditions []ReconcileCondition `json:"conditions"`
+	ElapsedMS  int64                `json:"elapsed_ms"`
+}
+
+// WaitForReconcile polls a Flux resource until its controller has handled the pending
+// reconcile request and the resource is Ready or has failed. progress is called with the
+// first state seen and again whenever it changes. A resource that fails returns its final
+// state with an error; the wait is bounded by ctx.
+func (c *Client) WaitForReconcile(ctx context.Context, clusterID, kind, namespace, name string, progress func(ReconcileProgress)) (*ReconcileProgress, error) {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := c.getGVRForKind(clusterID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var last *ReconcileProgress
+	ticker := time.NewTicker(reconcileWaitPollInterval)
+	defer ticker.Stop()
+	for {
+		resource, err := client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			return last, fmt.Errorf("%s %s/%s was deleted", kind, namespace, name)
+		case err == nil:
+			current := reconcileProgress(resource, start)
+			if last == nil || !current.sameAs(last) {
+				progress(current)
+			}
+			last = &current
+			switch current.Phase {
+			case "ready":
+				return last, nil
+			case "failed":
+				return last, fmt.Errorf("%s %s/%s failed to reconcile: %s", kind, namespace, name, current.Message)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("timed out waiting for %s %s/%s to become ready", kind, namespace, name)
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcileProgress reads the reconcile state of a Flux resource, following kstatus: the
+// request is pending until status.lastHandledReconcileAt matches it and the spec
+// generation is observed, progressing while Reconciling is True or Ready is Unknown, then
+// ready or failed by the Ready condition
+func reconcileProgress(resource *unstructured.Unstructured, start time.Time) ReconcileProgress {
+	p := ReconcileProgress{Conditions: []ReconcileCondition{}, ElapsedMS: time.Since(start).Milliseconds()}
+
+	requestedAt := resource.GetAnnotations()["reconcile.fluxcd.io/requestedAt"]
+	handledAt, _, _ := unstructured.NestedString(resource.Object, "status", "lastHandledReconcileAt")
+	p.Handled = requestedAt == "" || handledAt == requestedAt
+	observedGeneration, _, _ := unstructured.NestedInt64(resource.Object, "status", "observedGeneration")
+
+	p.Revision, _, _ = unstructured.NestedString(resource.Object, "status", "lastAppliedRevision")
+	if p.Revision == "" {
+		p.Revision, _, _ = unstructured.NestedString(resource.Object, "status", "artifact", "revision")
+	}
+
+	var ready, reconciling *ReconcileCondition
+	conditions, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var parsed ReconcileCondition
+		parsed.Type, _ = condition["type"].(string)
+		parsed.Status, _ = condition["status"].(string)
+		parsed.Reason, _ = condition["reason"].(string)
+		parsed.Message, _ = condition["message"].(string)
+		p.Conditions = append(p.Conditions, parsed)
+	}
+	for i := range p.Conditions {
+		switch p.Conditions[i].Type {
+		case "Ready":
+			ready = &p.Conditions[i]
+		case "Reconciling":
+			reconciling = &p.Conditions[i]
+		}
+	}
+
+	switch {
+	case !p.Handled || observedGeneration < resource.GetGeneration():
+		p.Phase = "pending"
+		p.Message = "Waiting for the controller to pick up the request"
+	case reconciling != nil && reconciling.Status == "True":
+		p.Phase, p.Reason, p.Message = "progressing", reconciling.Reason, reconciling.Message
+	case ready == nil || ready.Status == "Unknown":
+		p.Phase = "progressing"
+		if ready != nil {
+			p.Reason, p.Message = ready.Reason, ready.Message
+		}
+	case ready.Status == "True":
+		p.Phase, p.Reason, p.Message = "ready", ready.Reason, ready.Message
+	default:
+		p.Phase, p.Reason, p.Message = "failed", ready.Reason, ready.Message
+	}
+	return p
+}
+
+// sameAs reports whether two states differ only in elapsed time
+func (p ReconcileProgress) sameAs(other *ReconcileProgress) bool {
+	a, b := p, *other
+	a.ElapsedMS, b.ElapsedMS = 0, 0
+	return reflect.DeepEqual(a, b)
+}
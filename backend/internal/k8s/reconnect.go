@@ -0,0 +1,243 @@
+package k8s
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+)
+
+// Connection failures in a row after which a cluster's clients are rebuilt, and the least
+// time between two rebuilds of the same cluster
+const (
+	reconnectThreshold = 3
+	reconnectBackoff   = 2 * time.Minute
+)
+
+// ErrClusterNotFound is returned by a ClusterSource for clusters that are no longer registered
+var ErrClusterNotFound = errors.New("cluster not registered")
+
+// ClusterSource tells the client which clusters are registered and with which kubeconfig, so
+// it can rebuild clients from the current kubeconfig and drop clients of removed clusters
+type ClusterSource interface {
+	// ActiveClusters returns the IDs of the clusters that should stay connected
+	ActiveClusters() ([]string, error)
+	// Kubeconfig returns the current kubeconfig of a cluster, empty for the in-cluster
+	// configuration, or ErrClusterNotFound when the cluster was removed
+	Kubeconfig(clusterID string) (string, error)
+}
+
+// connState tracks the connection failures of one cluster
+type connState struct {
+	failures    int
+	lastError   string
+	lastRebuild time.Time
+}
+
+// connHealth holds the connection state of every cluster with recent failures
+type connHealth struct {
+	mu     sync.Mutex
+	states map[string]*connState
+}
+
+// failed records a connection failure and reports whether the cluster's clients are due to
+// be rebuilt. Rebuilding is claimed by the caller, so concurrent failures rebuild once.
+func (h *connHealth) failed(clusterID string, err error) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.states == nil {
+		h.states = make(map[string]*connState)
+	}
+	state, ok := h.states[clusterID]
+	if !ok {
+		state = &connState{}
+		h.states[clusterID] = state
+	}
+	state.failures++
+	state.lastError = err.Error()
+
+	if state.failures < reconnectThreshold || time.Since(state.lastRebuild) < reconnectBackoff {
+		return false
+	}
+	state.lastRebuild = time.Now()
+	return true
+}
+
+// reset clears the failures of a cluster, keeping when it was last rebuilt
+func (h *connHealth) reset(clusterID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if state, ok := h.states[clusterID]; ok {
+		state.failures = 0
+		state.lastError = ""
+	}
+}
+
+// forget drops the state of a removed cluster
+func (h *connHealth) forget(clusterID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.states, clusterID)
+}
+
+// failing returns the clusters whose last connection attempt failed
+func (h *connHealth) failing() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var ids []string
+	for id, state := range h.states {
+		if state.failures > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SetClusterSource sets where clients are rebuilt from after persistent connection failures,
+// and which clusters RunReconnector keeps
+func (c *Client) SetClusterSource(source ClusterSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = source
+}
+
+// isConnectionError reports whether err means the client can no longer reach or authenticate
+// to the API server, as after a certificate rotation or an endpoint change, rather than a
+// failure of the request itself
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsUnauthorized(err) {
+		return true
+	}
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		invalidCert      x509.CertificateInvalidError
+		hostname         x509.HostnameError
+		verification     *tls.CertificateVerificationError
+		opErr            *net.OpError
+		dnsErr           *net.DNSError
+		urlErr           *url.Error
+	)
+	switch {
+	case errors.As(err, &unknownAuthority), errors.As(err, &invalidCert), errors.As(err, &hostname),
+		errors.As(err, &verification), errors.As(err, &opErr), errors.As(err, &dnsErr):
+		return true
+	case errors.As(err, &urlErr):
+		return urlErr.Timeout()
+	}
+	return false
+}
+
+// observeConnection records the outcome of a request to a cluster. After reconnectThreshold
+// connection failures in a row its clients are rebuilt.
+func (c *Client) observeConnection(clusterID string, err error) {
+	if !isConnectionError(err) {
+		if err == nil {
+			c.health.reset(clusterID)
+		}
+		return
+	}
+	if !c.health.failed(clusterID, err) {
+		return
+	}
+	log.Printf("Cluster %s failed to connect %d times in a row (%v); rebuilding its clients", clusterID, reconnectThreshold, err)
+	if err := c.Reconnect(clusterID); err != nil {
+		log.Printf("Warning: Failed to rebuild clients of cluster %s: %v", clusterID, err)
+	}
+}
+
+// Reconnect rebuilds the clients of a cluster, dropping cached connections, credentials
+// and discovery. Clusters are rebuilt from their current kubeconfig when a ClusterSource is
+// set, so a changed endpoint or CA is picked up; clusters it no longer knows are removed.
+// In-cluster configurations re-read the mounted service account token and CA.
+func (c *Client) Reconnect(clusterID string) error {
+	c.mu.RLock()
+	source := c.source
+	inCluster := c.inCluster[clusterID]
+	config, hasConfig := c.configs[clusterID]
+	c.mu.RUnlock()
+
+	if inCluster {
+		return c.AddInClusterConfig(clusterID)
+	}
+	if source != nil {
+		kubeconfig, err := source.Kubeconfig(clusterID)
+		if errors.Is(err, ErrClusterNotFound) {
+			log.Printf("Cluster %s is no longer registered; dropping its clients", clusterID)
+			c.RemoveCluster(clusterID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load kubeconfig: %w", err)
+		}
+		if kubeconfig != "" {
+			return c.AddCluster(clusterID, kubeconfig)
+		}
+	}
+	if !hasConfig {
+		// Fake clusters have nothing to rebuild from
+		return nil
+	}
+
+	return c.buildClients(clusterID, rest.CopyConfig(config), false)
+}
+
+// RunReconnector keeps the clients in line with the ClusterSource until ctx is done. Every
+// interval it drops clients of clusters that were deleted or archived, possibly by another
+// replica, and probes clusters whose last request failed to connect, so their clients are
+// rebuilt even while nothing else uses them.
+func (c *Client) RunReconnector(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.evictRemovedClusters()
+		for _, clusterID := range c.health.failing() {
+			if _, ok := c.dynamicClient(clusterID); ok {
+				c.CheckClusterHealth(clusterID)
+			}
+		}
+	}
+}
+
+// evictRemovedClusters drops the clients of clusters the ClusterSource no longer lists
+func (c *Client) evictRemovedClusters() {
+	c.mu.RLock()
+	source := c.source
+	c.mu.RUnlock()
+	if source == nil {
+		return
+	}
+
+	active, err := source.ActiveClusters()
+	if err != nil {
+		log.Printf("Warning: Failed to list clusters to keep connected: %v", err)
+		return
+	}
+	keep := make(map[string]bool, len(active))
+	for _, id := range active {
+		keep[id] = true
+	}
+	for _, id := range c.clusterIDs() {
+		if !keep[id] {
+			log.Printf("Cluster %s was removed; dropping its clients", id)
+			c.RemoveCluster(id)
+		}
+	}
+}
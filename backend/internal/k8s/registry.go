@@ -0,0 +1,368 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Docker Hub answers the registry API on a different host than the one images name
+const (
+	dockerHubDomain  = "docker.io"
+	dockerHubAPIHost = "registry-1.docker.io"
+)
+
+// maxTagPages bounds the pages of a tag list followed for one repository
+const maxTagPages = 20
+
+// imageRef is a parsed container image reference
+type imageRef struct {
+	Domain     string // registry host, docker.io for Docker Hub
+	Repository string // path within the registry, library/nginx for official images
+	Tag        string
+	Digest     string
+}
+
+// parseImageRef parses an image reference such as nginx:1.25 or
+// ghcr.io/org/app:v1.2.3@sha256:abc. Tags default to latest unless a digest is given.
+func parseImageRef(image string) (imageRef, error) {
+	var ref imageRef
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		name, ref.Digest = name[:at], name[at+1:]
+	}
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:colon], name[colon+1:]
+	}
+	if name == "" {
+		return ref, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	ref.Domain, ref.Repository = dockerHubDomain, name
+	if slash := strings.Index(name, "/"); slash >= 0 {
+		first := name[:slash]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Domain, ref.Repository = first, name[slash+1:]
+		}
+	}
+	if ref.Domain == "index.docker.io" {
+		ref.Domain = dockerHubDomain
+	}
+	if ref.Domain == dockerHubDomain && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// apiHost returns the host serving the registry API for the image
+func (r imageRef) apiHost() string {
+	if r.Domain == dockerHubDomain {
+		return dockerHubAPIHost
+	}
+	return r.Domain
+}
+
+// registryCredential is a username and password for a registry, from an image pull Secret
+// or a stored registry credential
+type registryCredential struct {
+	Username string
+	Password string
+	Source   string // namespace/name of the Secret, or the name of the stored credential
+}
+
+// dockerConfig is the content of a kubernetes.io/dockerconfigjson Secret
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// pullSecretCredentials returns the credentials of an image pull Secret by registry host.
+// Secrets of other types are ignored.
+func pullSecretCredentials(secret *corev1.Secret) map[string]registryCredential {
+	var config dockerConfig
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		if json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config) != nil {
+			return nil
+		}
+	case corev1.SecretTypeDockercfg:
+		// The legacy format is the auths map on its own
+		if json.Unmarshal(secret.Data[corev1.DockerConfigKey], &config.Auths) != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	credentials := map[string]registryCredential{}
+	for server, entry := range config.Auths {
+		credential := registryCredential{Username: entry.Username, Password: entry.Password, Source: secret.Namespace + "/" + secret.Name}
+		if entry.Auth != "" {
+			if decoded, err := base64.StdEncoding.DecodeString(entry.Auth); err == nil {
+				credential.Username, credential.Password, _ = strings.Cut(string(decoded), ":")
+			}
+		}
+		if credential.Username == "" && credential.Password == "" {
+			continue
+		}
+		credentials[RegistryHost(server)] = credential
+	}
+	return credentials
+}
+
+// RegistryHost normalizes the server key of a Docker config, such as
+// https://index.docker.io/v1/, to the domain images name
+func RegistryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	if slash := strings.Index(host, "/"); slash >= 0 {
+		host = host[:slash]
+	}
+	switch host {
+	case "index.docker.io", dockerHubAPIHost:
+		return dockerHubDomain
+	}
+	return host
+}
+
+// registryClient reads tag lists from OCI distribution registries over HTTPS
+type registryClient struct {
+	http *http.Client
+}
+
+func newRegistryClient() *registryClient {
+	return &registryClient{http: httpclient.New(15 * time.Second)}
+}
+
+// listTags returns every tag of an image's repository, authenticating with credential when
+// the registry asks for it
+func (c *registryClient) listTags(ctx context.Context, ref imageRef, credential *registryCredential) ([]string, error) {
+	next := fmt.Sprintf("https://%s/v2/%s/tags/list?n=1000", ref.apiHost(), ref.Repository)
+	var tags []string
+	authorization := ""
+
+	for page := 0; next != "" && page < maxTagPages; page++ {
+		resp, err := c.get(ctx, next, authorization)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && authorization == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			scope := fmt.Sprintf("repository:%s:pull", ref.Repository)
+			if authorization, err = c.authorize(ctx, challenge, ref.Domain, scope, credential); err != nil {
+				return nil, err
+			}
+			if resp, err = c.get(ctx, next, authorization); err != nil {
+				return nil, err
+			}
+		}
+
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		err = decodeRegistryResponse(resp, &body)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, body.Tags...)
+		next, err = nextTagPage(resp, next)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+func (c *registryClient) get(ctx context.Context, target, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registry request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// login checks a credential against a registry's API root the way docker login does: the
+// registry must accept the credential, or the token it exchanges the credential for
+func (c *registryClient) login(ctx context.Context, domain string, credential *registryCredential) error {
+	root := fmt.Sprintf("https://%s/v2/", imageRef{Domain: domain}.apiHost())
+	resp, err := c.get(ctx, root, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := c.authorize(ctx, challenge, domain, "", credential)
+		if err != nil {
+			return err
+		}
+		if resp, err = c.get(ctx, root, authorization); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+	return registryStatusError(resp)
+}
+
+// authorize answers a registry's WWW-Authenticate challenge, returning the Authorization
+// header to retry with. Tokens are requested for scope, such as repository:org/app:pull, or
+// for no scope when empty.
+func (c *registryClient) authorize(ctx context.Context, challenge, domain, scope string, credential *registryCredential) (string, error) {
+	scheme, params := parseAuthChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credential == nil {
+			return "", fmt.Errorf("registry %s requires credentials; add an image pull Secret or a registry credential for it", domain)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credential.Username+":"+credential.Password)), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s uses unsupported authentication %q", domain, scheme)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return "", fmt.Errorf("registry %s sent an invalid token realm %q", domain, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	if credential != nil {
+		query.Set("account", credential.Username)
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if credential != nil {
+		req.SetBasicAuth(credential.Username, credential.Password)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("registry token request failed: %w", err)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := decodeRegistryResponse(resp, &token); err != nil {
+		return "", err
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("registry %s returned no token", domain)
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// decodeRegistryResponse decodes a JSON registry response and closes its body
+func decodeRegistryResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if err := registryStatusError(resp); err != nil {
+		return err
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(v); err != nil {
+		return fmt.Errorf("invalid registry response: %w", err)
+	}
+	return nil
+}
+
+// registryStatusError returns the error a registry response's status stands for, or nil
+func registryStatusError(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("registry denied access (%s); check the image pull Secret or registry credential", resp.Status)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("repository not found in the registry")
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("registry returned %s", resp.Status)
+	}
+	return nil
+}
+
+// nextTagPage returns the URL of the next page of a tag list from its Link header, or ""
+func nextTagPage(resp *http.Response, current string) (string, error) {
+	link := resp.Header.Get("Link")
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return "", nil
+	}
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start {
+		return "", nil
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+	next, err := base.Parse(link[start+1 : end])
+	if err != nil {
+		return "", fmt.Errorf("invalid registry Link header: %w", err)
+	}
+	// Pages stay on the registry the list started on
+	if next.Host != base.Host {
+		return "", nil
+	}
+	return next.String(), nil
+}
+
+// parseAuthChallenge splits a WWW-Authenticate header such as
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io" into its scheme
+// and parameters
+func parseAuthChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			params[key], rest, _ = strings.Cut(value, ",")
+		}
+		rest = strings.TrimLeft(strings.TrimSpace(rest), ",")
+		rest = strings.TrimSpace(rest)
+	}
+	return scheme, params
+}
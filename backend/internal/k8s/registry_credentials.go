@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// RegistryCredential is a stored login for a container registry. Image reports use it for
+// images whose workloads have no image pull Secret for the registry.
+type RegistryCredential struct {
+	Name     string // shown as the source of the credential in reports
+	Registry string // host images name, such as myregistry.azurecr.io or docker.io
+	Username string
+	Password string
+}
+
+// registryStore holds the stored registry credentials by registry host
+type registryStore struct {
+	mu          sync.RWMutex
+	credentials map[string]registryCredential
+}
+
+func (s *registryStore) get(host string) (registryCredential, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	credential, ok := s.credentials[host]
+	return credential, ok
+}
+
+// SetRegistryCredential registers a stored registry credential, replacing any other one for
+// the same registry
+func (c *Client) SetRegistryCredential(credential RegistryCredential) {
+	c.registries.mu.Lock()
+	defer c.registries.mu.Unlock()
+	if c.registries.credentials == nil {
+		c.registries.credentials = map[string]registryCredential{}
+	}
+	c.registries.credentials[RegistryHost(credential.Registry)] = registryCredential{
+		Username: credential.Username,
+		Password: credential.Password,
+		Source:   "registry credential " + credential.Name,
+	}
+}
+
+// RemoveRegistryCredential unregisters the stored credential of a registry
+func (c *Client) RemoveRegistryCredential(registry string) {
+	c.registries.mu.Lock()
+	defer c.registries.mu.Unlock()
+	delete(c.registries.credentials, RegistryHost(registry))
+}
+
+// TestRegistryCredential logs in to the credential's registry without registering it
+func (c *Client) TestRegistryCredential(ctx context.Context, credential RegistryCredential) error {
+	host := RegistryHost(credential.Registry)
+	if host == "" {
+		return fmt.Errorf("registry host is required")
+	}
+	return newRegistryClient().login(ctx, host, &registryCredential{Username: credential.Username, Password: credential.Password})
+}
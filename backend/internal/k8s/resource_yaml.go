@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// ResourceYAML is a live object serialized as YAML
+type ResourceYAML struct {
+	YAML     []byte
+	Redacted bool // Secret values were replaced
+}
+
+// GetResourceYAML returns a live object as YAML, exactly as stored apart from
+// metadata.managedFields. Unless revealSecrets is set, the values of a Secret are redacted
+// and the last-applied annotation copying them is dropped.
+func (c *Client) GetResourceYAML(ctx context.Context, clusterID, kind, namespace, name string, revealSecrets bool) (*ResourceYAML, error) {
+	resource, _, err := c.GetResourceByKind(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ResourceYAML{}
+	unstructured.RemoveNestedField(resource.Object, "metadata", "managedFields")
+	if kind == "Secret" && !revealSecrets {
+		result.Redacted = redactSecretValues(resource)
+	}
+
+	result.YAML, err = yaml.Marshal(resource.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize resource: %w", err)
+	}
+	return result, nil
+}
+
+// redactSecretValues replaces the values of a Secret's data and stringData, keeping their
+// keys, and drops the last-applied annotation. It reports whether anything was redacted.
+func redactSecretValues(obj *unstructured.Unstructured) bool {
+	redacted := false
+	for _, field := range []string{"data", "stringData"} {
+		values, found, _ := unstructured.NestedMap(obj.Object, field)
+		if !found {
+			continue
+		}
+		for key := range values {
+			values[key] = redactedValue
+			redacted = true
+		}
+		unstructured.SetNestedMap(obj.Object, values, field)
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[lastAppliedAnnotation]; ok {
+			delete(annotations, lastAppliedAnnotation)
+			if len(annotations) == 0 {
+				annotations = nil
+			}
+			obj.SetAnnotations(annotations)
+			redacted = true
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/metadata"
+)
+
+// lastAppliedAnnotation holds the whole object as last applied by kubectl, Secret data included
+const lastAppliedAnnotation = corev1.LastAppliedConfigAnnotation
+
+var secretsGVR = corev1.SchemeGroupVersion.WithResource("secrets")
+
+// SecretKey is a key of a Secret and the size of its value; the value itself is never returned
+type SecretKey struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+// SecretKeys lists the keys of a Secret
+type SecretKeys struct {
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Type      string      `json:"type"`
+	Keys      []SecretKey `json:"keys"`
+}
+
+// GetSecretKeys returns the keys of a Secret and the sizes of their values
+func (c *Client) GetSecretKeys(ctx context.Context, clusterID, namespace, name string) (*SecretKeys, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	secret, err := typedClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	result := &SecretKeys{Namespace: namespace, Name: name, Type: string(secret.Type), Keys: []SecretKey{}}
+	for key, value := range secret.Data {
+		result.Keys = append(result.Keys, SecretKey{Name: key, Size: len(value)})
+	}
+	sort.Slice(result.Keys, func(i, j int) bool { return result.Keys[i].Name < result.Keys[j].Name })
+	return result, nil
+}
+
+// listSecretMetadata lists the Secrets of a cluster through the metadata API, so their
+// data never leaves the API server. Clusters without a REST config (fake clusters) are
+// listed in full and redacted.
+func (c *Client) listSecretMetadata(ctx context.Context, clusterID string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	config, ok := c.restConfig(clusterID)
+	if !ok {
+		client, err := c.GetClient(clusterID)
+		if err != nil {
+			return nil, err
+		}
+		list, err := client.Resource(secretsGVR).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			redactSecret(&list.Items[i])
+		}
+		return list.Items, nil
+	}
+
+	client, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %w", err)
+	}
+	list, err := client.Resource(secretsGVR).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]unstructured.Unstructured, 0, len(list.Items))
+	for _, item := range list.Items {
+		meta, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&item.ObjectMeta)
+		if err != nil {
+			continue
+		}
+		obj := unstructured.Unstructured{Object: map[string]interface{}{"metadata": meta}}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Secret")
+		redactSecret(&obj)
+		items = append(items, obj)
+	}
+	return items, nil
+}
+
+// redactSecret removes a Secret's data and the annotation copying it
+func redactSecret(obj *unstructured.Unstructured) {
+	delete(obj.Object, "data")
+	delete(obj.Object, "stringData")
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[lastAppliedAnnotation]; ok {
+			delete(annotations, lastAppliedAnnotation)
+			obj.SetAnnotations(annotations)
+		}
+	}
+}
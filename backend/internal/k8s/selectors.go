@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListFilter narrows listed objects with Kubernetes label and field selectors, such as
+// app.kubernetes.io/part-of=payments. Live lists leave the filtering to the API server.
+type ListFilter struct {
+	LabelSelector string
+	FieldSelector string
+
+	labels labels.Selector
+	fields fields.Selector
+}
+
+// NewListFilter parses label and field selectors; empty selectors match everything
+func NewListFilter(labelSelector, fieldSelector string) (ListFilter, error) {
+	filter := ListFilter{LabelSelector: labelSelector, FieldSelector: fieldSelector}
+	var err error
+	if filter.labels, err = labels.Parse(labelSelector); err != nil {
+		return filter, fmt.Errorf("invalid label selector: %w", err)
+	}
+	if filter.fields, err = fields.ParseSelector(fieldSelector); err != nil {
+		return filter, fmt.Errorf("invalid field selector: %w", err)
+	}
+	return filter, nil
+}
+
+// IsZero reports whether the filter matches everything
+func (f ListFilter) IsZero() bool {
+	return f.LabelSelector == "" && f.FieldSelector == ""
+}
+
+func (f ListFilter) listOptions() metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: f.LabelSelector, FieldSelector: f.FieldSelector}
+}
+
+// storedFields are the fields a filter can match on objects that were already fetched
+var storedFields = map[string]bool{"metadata.name": true, "metadata.namespace": true}
+
+// CheckStoredFields returns an error if the field selector uses fields Matches cannot
+// evaluate. Every kind's API supports these; others, such as status.phase, are kind-specific.
+func (f ListFilter) CheckStoredFields() error {
+	if f.fields == nil {
+		return nil
+	}
+	for _, requirement := range f.fields.Requirements() {
+		if !storedFields[requirement.Field] {
+			return fmt.Errorf("field selector on %s is not supported here, only metadata.name and metadata.namespace", requirement.Field)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether an already fetched object matches the filter. The field selector
+// is evaluated on metadata.name and metadata.namespace only; see CheckStoredFields.
+func (f ListFilter) Matches(obj *unstructured.Unstructured) bool {
+	if f.labels != nil && !f.labels.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	if f.fields != nil && !f.fields.Matches(fields.Set{"metadata.name": obj.GetName(), "metadata.namespace": obj.GetNamespace()}) {
+		return false
+	}
+	return true
+}
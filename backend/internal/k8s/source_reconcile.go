@@ -0,0 +1,150 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// sourceReconcilePollInterval is how often a source is checked while waiting for it to
+// handle a reconcile request
+const sourceReconcilePollInterval = 2 * time.Second
+
+// SourceReconcileResult describes the source reconciled before a Kustomization or
+// HelmRelease, like flux reconcile --with-source
+type SourceReconcileResult struct {
+	Kind             string `json:"kind"`
+	Namespace        string `json:"namespace"`
+	Name             string `json:"name"`
+	PreviousRevision string `json:"previous_revision,omitempty"`
+	Revision         string `json:"revision,omitempty"`
+	Updated          bool   `json:"updated"` // the artifact revision changed
+}
+
+// ReconcileWithSource requests a reconcile of the source a Kustomization or HelmRelease
+// uses, waits until the source controller has handled it, then requests a reconcile of the
+// resource itself. A source that is suspended or fails to reconcile stops the resource from
+// being reconciled. The wait is bounded by ctx.
+func (c *Client) ReconcileWithSource(ctx context.Context, clusterID, kind, namespace, name string) (*SourceReconcileResult, error) {
+	ref, err := c.fluxSourceRef(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	result := &SourceReconcileResult{Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name}
+
+	source, gvr, err := c.GetResourceByKind(ctx, clusterID, ref.Kind, ref.Namespace, ref.Name)
+	if err != nil {
+		return result, fmt.Errorf("failed to get source %s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+	}
+	if suspended, _, _ := unstructured.NestedBool(source.Object, "spec", "suspend"); suspended {
+		return result, fmt.Errorf("source %s %s/%s is suspended", ref.Kind, ref.Namespace, ref.Name)
+	}
+	result.PreviousRevision, _, _ = unstructured.NestedString(source.Object, "status", "artifact", "revision")
+
+	requestedAt := time.Now().Format(time.RFC3339Nano)
+	annotations := source.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations["reconcile.fluxcd.io/requestedAt"] = requestedAt
+	source.SetAnnotations(annotations)
+	applyProvenance(ctx, source)
+
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return result, err
+	}
+	if _, err := client.Resource(gvr).Namespace(ref.Namespace).Update(ctx, source, metav1.UpdateOptions{}); err != nil {
+		return result, fmt.Errorf("failed to annotate source: %w", err)
+	}
+
+	ticker := time.NewTicker(sourceReconcilePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return result, fmt.Errorf("timed out waiting for source %s %s/%s to reconcile", ref.Kind, ref.Namespace, ref.Name)
+		case <-ticker.C:
+		}
+
+		source, err := client.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		if handled, _, _ := unstructured.NestedString(source.Object, "status", "lastHandledReconcileAt"); handled != requestedAt {
+			continue
+		}
+		if ready, message := readyCondition(source); !ready {
+			return result, fmt.Errorf("source %s %s/%s failed to reconcile: %s", ref.Kind, ref.Namespace, ref.Name, message)
+		}
+		result.Revision, _, _ = unstructured.NestedString(source.Object, "status", "artifact", "revision")
+		result.Updated = result.Revision != result.PreviousRevision
+		break
+	}
+
+	if err := c.ReconcileResource(ctx, clusterID, kind, namespace, name); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// fluxSourceRef returns the source a Kustomization or HelmRelease fetches its artifact from.
+// For a HelmRelease that is its HelmChart, or its chartRef target.
+func (c *Client) fluxSourceRef(ctx context.Context, clusterID, kind, namespace, name string) (EventObject, error) {
+	switch kind {
+	case "Kustomization":
+		resource, _, err := c.GetResourceByKind(ctx, clusterID, kind, namespace, name)
+		if err != nil {
+			return EventObject{}, err
+		}
+		return kustomizationSourceRef(resource)
+	case "HelmRelease":
+		// The helm-controller records the HelmChart it created in status.helmChart
+		if release, _, err := c.GetResourceByKind(ctx, clusterID, kind, namespace, name); err == nil {
+			if chart, _, _ := unstructured.NestedString(release.Object, "status", "helmChart"); chart != "" {
+				if chartNamespace, chartName, ok := strings.Cut(chart, "/"); ok {
+					return EventObject{Kind: "HelmChart", Namespace: chartNamespace, Name: chartName}, nil
+				}
+			}
+		}
+		ref, ok := c.helmReleaseChart(ctx, clusterID, namespace, name)
+		if !ok {
+			return EventObject{}, fmt.Errorf("HelmRelease %s/%s not found", namespace, name)
+		}
+		return ref, nil
+	default:
+		return EventObject{}, fmt.Errorf("reconciling with source is only supported for Kustomizations and HelmReleases")
+	}
+}
+
+// kustomizationSourceRef returns the source of a Kustomization from its spec.sourceRef
+func kustomizationSourceRef(kustomization *unstructured.Unstructured) (EventObject, error) {
+	ref := EventObject{Namespace: kustomization.GetNamespace()}
+	ref.Kind, _, _ = unstructured.NestedString(kustomization.Object, "spec", "sourceRef", "kind")
+	ref.Name, _, _ = unstructured.NestedString(kustomization.Object, "spec", "sourceRef", "name")
+	if refNamespace, _, _ := unstructured.NestedString(kustomization.Object, "spec", "sourceRef", "namespace"); refNamespace != "" {
+		ref.Namespace = refNamespace
+	}
+	if ref.Kind == "" || ref.Name == "" {
+		return EventObject{}, fmt.Errorf("Kustomization %s/%s has no sourceRef", kustomization.GetNamespace(), kustomization.GetName())
+	}
+	return ref, nil
+}
+
+// readyCondition reports whether an object's Ready condition is not False, and its message
+func readyCondition(obj *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return condition["status"] != "False", message
+	}
+	return true, ""
+}
@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// defaultTreeCacheTTL is how long a built resource tree is served before it is rebuilt;
+// RESOURCE_TREE_CACHE_TTL_SECONDS overrides it and 0 turns the cache off
+const defaultTreeCacheTTL = 30 * time.Second
+
+// treeStaleFactor bounds how stale a tree may be served while a fresh one is built, in TTLs.
+// Older trees make the request wait for the rebuild.
+const treeStaleFactor = 10
+
+// treeCache holds the resource trees built per cluster, keyed by cluster ID and filter
+type treeCache struct {
+	mu      sync.Mutex
+	entries map[string]map[string]*treeBuild
+}
+
+// treeBuild is a resource tree built, or being built, for one cluster and filter. Requests
+// arriving during a build wait for it instead of starting their own.
+type treeBuild struct {
+	done       chan struct{} // closed once tree, err and builtAt are set
+	tree       []ResourceNode
+	err        error
+	builtAt    time.Time
+	refreshing bool // a replacement is being built; guarded by treeCache.mu
+}
+
+func (b *treeBuild) finished() bool {
+	select {
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// lookup returns the build to serve a cluster and filter from. Missing and long stale trees
+// are built for the caller to wait on; a tree past ttl is served as it is while its
+// replacement is built in the background.
+func (t *treeCache) lookup(clusterID, key string, ttl time.Duration, build func() ([]ResourceNode, error)) *treeBuild {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := t.entries[clusterID][key]
+	if current == nil || (current.finished() && time.Since(current.builtAt) > ttl*treeStaleFactor) {
+		next := &treeBuild{done: make(chan struct{})}
+		t.set(clusterID, key, next)
+		go t.run(clusterID, key, next, nil, build)
+		return next
+	}
+	if current.finished() && time.Since(current.builtAt) > ttl && !current.refreshing {
+		current.refreshing = true
+		go t.run(clusterID, key, &treeBuild{done: make(chan struct{})}, current, build)
+	}
+	return current
+}
+
+// run builds a tree. A first build is already in the cache and is dropped when it fails so
+// the next request retries; a refresh replaces the stale build only when it succeeds and
+// the cluster was not invalidated meanwhile.
+func (t *treeCache) run(clusterID, key string, next, stale *treeBuild, build func() ([]ResourceNode, error)) {
+	next.tree, next.err = build()
+	next.builtAt = time.Now()
+	close(next.done)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	current := t.entries[clusterID][key]
+	switch {
+	case stale == nil && next.err != nil && current == next:
+		delete(t.entries[clusterID], key)
+	case stale != nil && next.err != nil:
+		stale.refreshing = false
+		log.Printf("Warning: failed to refresh resource tree of cluster %s: %v", clusterID, next.err)
+	case stale != nil && current == stale:
+		t.set(clusterID, key, next)
+	}
+}
+
+func (t *treeCache) set(clusterID, key string, build *treeBuild) {
+	if t.entries == nil {
+		t.entries = make(map[string]map[string]*treeBuild)
+	}
+	if t.entries[clusterID] == nil {
+		t.entries[clusterID] = make(map[string]*treeBuild)
+	}
+	t.entries[clusterID][key] = build
+}
+
+// forget drops every tree built for a cluster
+func (t *treeCache) forget(clusterID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, clusterID)
+}
+
+// GetResourceTree returns the resource tree of a cluster, from the cache while it is fresh.
+// See buildResourceTree for what it holds.
+func (c *Client) GetResourceTree(ctx context.Context, clusterID string, filter ListFilter) ([]ResourceNode, error) {
+	if c.treeTTL <= 0 {
+		return c.buildResourceTree(ctx, clusterID, filter)
+	}
+	if _, err := c.GetClient(clusterID); err != nil {
+		return nil, err
+	}
+
+	// Builds are shared between requests, so they must outlive the one that started them
+	build := c.trees.lookup(clusterID, filter.LabelSelector+"\n"+filter.FieldSelector, c.treeTTL, func() ([]ResourceNode, error) {
+		return c.buildResourceTree(context.Background(), clusterID, filter)
+	})
+	select {
+	case <-build.done:
+		return build.tree, build.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// InvalidateResourceTree drops the cached resource trees of a cluster, so the next request
+// builds a fresh one
+func (c *Client) InvalidateResourceTree(clusterID string) {
+	c.trees.forget(clusterID)
+}
@@ -0,0 +1,265 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxTriageEvents caps the events returned for each pod or workload
+const maxTriageEvents = 10
+
+// TriageEvent is a Kubernetes event involving a triaged object
+type TriageEvent struct {
+	Type     string    `json:"type"` // Normal, Warning
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// TriageTermination describes the last time a container terminated
+type TriageTermination struct {
+	Reason     string    `json:"reason"`
+	ExitCode   int32     `json:"exit_code"`
+	Message    string    `json:"message,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// TriageContainer is the state of one container of a failing pod
+type TriageContainer struct {
+	Name            string             `json:"name"`
+	Init            bool               `json:"init"`
+	Ready           bool               `json:"ready"`
+	RestartCount    int32              `json:"restart_count"`
+	State           string             `json:"state"` // Running, Waiting, Terminated
+	Reason          string             `json:"reason,omitempty"`
+	Message         string             `json:"message,omitempty"`
+	LastTermination *TriageTermination `json:"last_termination,omitempty"`
+}
+
+// TriagePod is a pod that is not running cleanly, with what is needed to see why
+type TriagePod struct {
+	Namespace    string            `json:"namespace"`
+	Name         string            `json:"name"`
+	Phase        string            `json:"phase"`
+	Reason       string            `json:"reason,omitempty"`
+	Message      string            `json:"message,omitempty"`
+	Node         string            `json:"node,omitempty"`
+	RestartCount int32             `json:"restart_count"`
+	Containers   []TriageContainer `json:"containers"`
+	Events       []TriageEvent     `json:"events"`
+}
+
+// TriageWorkload is a workload below its desired ready count, with its recent events
+type TriageWorkload struct {
+	OwnedWorkload
+	Events []TriageEvent `json:"events"`
+}
+
+// PodTriage is the triage bundle for a Kustomization or HelmRelease
+type PodTriage struct {
+	Kind      string           `json:"kind"`
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+	TotalPods int              `json:"total_pods"`
+	Pods      []TriagePod      `json:"pods"`
+	Workloads []TriageWorkload `json:"workloads"`
+}
+
+// GetPodTriage collects the non-running pods of a Kustomization or HelmRelease with their
+// restart counts, last termination reasons and recent events. Workloads short of ready
+// replicas are included too, since a quota or admission failure leaves no pod to inspect.
+func (c *Client) GetPodTriage(ctx context.Context, clusterID, kind, namespace, name string) (*PodTriage, error) {
+	workloads, pods, err := c.getOwnedPods(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	typedClient, _ := c.typedClient(clusterID)
+
+	triage := &PodTriage{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		TotalPods: len(pods),
+		Pods:      []TriagePod{},
+		Workloads: []TriageWorkload{},
+	}
+
+	var failing []corev1.Pod
+	for _, pod := range pods {
+		if !podHealthy(pod) {
+			failing = append(failing, pod)
+		}
+	}
+	var degraded []OwnedWorkload
+	for _, workload := range workloads {
+		if workload.Ready < workload.Desired {
+			degraded = append(degraded, workload)
+		}
+	}
+
+	// Events are listed once per namespace and matched locally
+	eventsByNamespace := make(map[string][]corev1.Event)
+	namespaceEvents := func(ns string) []corev1.Event {
+		events, ok := eventsByNamespace[ns]
+		if !ok {
+			list, err := typedClient.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				events = list.Items
+			}
+			eventsByNamespace[ns] = events
+		}
+		return events
+	}
+
+	for _, pod := range failing {
+		triage.Pods = append(triage.Pods, triagePod(pod, eventsFor(namespaceEvents(pod.Namespace), func(ref corev1.ObjectReference) bool {
+			return ref.Kind == "Pod" && ref.Name == pod.Name
+		})))
+	}
+	for _, workload := range degraded {
+		triage.Workloads = append(triage.Workloads, TriageWorkload{
+			OwnedWorkload: workload,
+			Events: eventsFor(namespaceEvents(workload.Namespace), func(ref corev1.ObjectReference) bool {
+				if ref.Kind == workload.Kind && ref.Name == workload.Name {
+					return true
+				}
+				// Pod creation failures are reported on the Deployment's ReplicaSets
+				return workload.Kind == "Deployment" && ref.Kind == "ReplicaSet" && strings.HasPrefix(ref.Name, workload.Name+"-")
+			}),
+		})
+	}
+
+	sort.Slice(triage.Pods, func(i, j int) bool {
+		if triage.Pods[i].Namespace != triage.Pods[j].Namespace {
+			return triage.Pods[i].Namespace < triage.Pods[j].Namespace
+		}
+		return triage.Pods[i].Name < triage.Pods[j].Name
+	})
+	return triage, nil
+}
+
+// podHealthy reports whether a pod has completed or is running with every container ready
+func podHealthy(pod corev1.Pod) bool {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return true
+	case corev1.PodRunning:
+		for _, status := range pod.Status.ContainerStatuses {
+			if !status.Ready {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// triagePod summarizes a failing pod
+func triagePod(pod corev1.Pod, events []TriageEvent) TriagePod {
+	result := TriagePod{
+		Namespace:  pod.Namespace,
+		Name:       pod.Name,
+		Phase:      string(pod.Status.Phase),
+		Reason:     pod.Status.Reason,
+		Message:    pod.Status.Message,
+		Node:       pod.Spec.NodeName,
+		Containers: []TriageContainer{},
+		Events:     events,
+	}
+
+	// Scheduling failures only show up as a pod condition
+	if result.Message == "" {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+				result.Reason = condition.Reason
+				result.Message = condition.Message
+			}
+		}
+	}
+
+	for _, status := range pod.Status.InitContainerStatuses {
+		result.Containers = append(result.Containers, triageContainer(status, true))
+		result.RestartCount += status.RestartCount
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		result.Containers = append(result.Containers, triageContainer(status, false))
+		result.RestartCount += status.RestartCount
+	}
+	return result
+}
+
+// triageContainer summarizes a container status
+func triageContainer(status corev1.ContainerStatus, init bool) TriageContainer {
+	container := TriageContainer{
+		Name:         status.Name,
+		Init:         init,
+		Ready:        status.Ready,
+		RestartCount: status.RestartCount,
+	}
+
+	switch {
+	case status.State.Waiting != nil:
+		container.State = "Waiting"
+		container.Reason = status.State.Waiting.Reason
+		container.Message = status.State.Waiting.Message
+	case status.State.Terminated != nil:
+		container.State = "Terminated"
+		container.Reason = status.State.Terminated.Reason
+		container.Message = status.State.Terminated.Message
+	case status.State.Running != nil:
+		container.State = "Running"
+	}
+
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		container.LastTermination = &TriageTermination{
+			Reason:     terminated.Reason,
+			ExitCode:   terminated.ExitCode,
+			Message:    terminated.Message,
+			FinishedAt: terminated.FinishedAt.Time,
+		}
+	}
+	return container
+}
+
+// triageEvent summarizes an event. Events recorded through the events.k8s.io API set
+// EventTime instead of LastTimestamp and may leave Count at zero.
+func triageEvent(event corev1.Event) TriageEvent {
+	lastSeen := event.LastTimestamp.Time
+	if lastSeen.IsZero() {
+		lastSeen = event.EventTime.Time
+	}
+	count := event.Count
+	if count == 0 {
+		count = 1
+	}
+	return TriageEvent{
+		Type:     event.Type,
+		Reason:   event.Reason,
+		Message:  event.Message,
+		Count:    count,
+		LastSeen: lastSeen,
+	}
+}
+
+// eventsFor returns the most recent events whose involved object matches, newest first
+func eventsFor(events []corev1.Event, matches func(corev1.ObjectReference) bool) []TriageEvent {
+	result := []TriageEvent{}
+	for _, event := range events {
+		if !matches(event.InvolvedObject) {
+			continue
+		}
+		result = append(result, triageEvent(event))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].LastSeen.After(result[j].LastSeen) })
+	if len(result) > maxTriageEvents {
+		result = result[:maxTriageEvents]
+	}
+	return result
+}
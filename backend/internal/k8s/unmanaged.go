@@ -0,0 +1,214 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+)
+
+// unmanagedSkippedNamespaces hold cluster components rather than apps, and are only scanned
+// when asked for by name
+var unmanagedSkippedNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// UnmanagedObject is a workload no Flux resource manages, such as one deployed by hand
+type UnmanagedObject struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	// ManagedBy is the app.kubernetes.io/managed-by label, such as Helm for charts installed
+	// outside Flux
+	ManagedBy string `json:"managed_by,omitempty"`
+}
+
+// UnmanagedReport lists the workloads of a cluster that carry no Flux ownership labels and
+// are in no Kustomization's inventory
+type UnmanagedReport struct {
+	Scanned           int               `json:"scanned"`
+	Objects           []UnmanagedObject `json:"objects"`
+	ByNamespace       map[string]int    `json:"by_namespace"`
+	SkippedNamespaces []string          `json:"skipped_namespaces,omitempty"`
+	Warnings          []string          `json:"warnings"`
+}
+
+// unmanagedCandidate is a scanned workload with the group of its kind, to match inventory IDs
+type unmanagedCandidate struct {
+	group string
+	kind  string
+	meta  metav1.ObjectMeta
+}
+
+// GetUnmanagedResources scans Deployments, StatefulSets, DaemonSets, CronJobs, Jobs and bare
+// Pods in namespace, or every namespace but kube-system, kube-public and kube-node-lease when
+// empty. Objects owned by another object, such as Jobs created by a CronJob, are left out.
+func (c *Client) GetUnmanagedResources(ctx context.Context, clusterID, namespace string) (*UnmanagedReport, error) {
+	typedClient, ok := c.typedClient(clusterID)
+	if !ok {
+		return nil, fmt.Errorf("cluster %s not found", clusterID)
+	}
+
+	report := &UnmanagedReport{Objects: []UnmanagedObject{}, ByNamespace: map[string]int{}, Warnings: []string{}}
+	inventory, err := c.inventoryIDs(ctx, clusterID)
+	if err != nil {
+		// Ownership labels still identify what Flux applied
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Kustomization inventories unavailable, only ownership labels were checked: %v", err))
+	}
+
+	candidates, err := listUnmanagedCandidates(ctx, typedClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := map[string]bool{}
+	for _, candidate := range candidates {
+		if namespace == "" && isUnmanagedSkipped(candidate.meta.Namespace) {
+			skipped[candidate.meta.Namespace] = true
+			continue
+		}
+		if len(candidate.meta.OwnerReferences) > 0 {
+			continue
+		}
+		report.Scanned++
+		if fluxOwned(candidate.meta.Labels) {
+			continue
+		}
+		id := strings.Join([]string{candidate.meta.Namespace, candidate.meta.Name, candidate.group, candidate.kind}, "_")
+		if inventory[id] {
+			continue
+		}
+		report.Objects = append(report.Objects, UnmanagedObject{
+			Kind:      candidate.kind,
+			Namespace: candidate.meta.Namespace,
+			Name:      candidate.meta.Name,
+			CreatedAt: candidate.meta.CreationTimestamp.Time,
+			ManagedBy: candidate.meta.Labels[ManagedByLabel],
+		})
+		report.ByNamespace[candidate.meta.Namespace]++
+	}
+
+	for name := range skipped {
+		report.SkippedNamespaces = append(report.SkippedNamespaces, name)
+	}
+	sort.Strings(report.SkippedNamespaces)
+	sort.Slice(report.Objects, func(i, j int) bool {
+		a, b := report.Objects[i], report.Objects[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+// inventoryIDs returns the inventory IDs of every Kustomization in a cluster
+func (c *Client) inventoryIDs(ctx context.Context, clusterID string) (map[string]bool, error) {
+	client, err := c.GetClient(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := c.getGVRForKind(clusterID, "Kustomization")
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kustomizations: %w", err)
+	}
+
+	ids := map[string]bool{}
+	for _, kustomization := range list.Items {
+		entries, _, _ := unstructured.NestedSlice(kustomization.Object, "status", "inventory", "entries")
+		for _, entry := range entries {
+			if entryMap, ok := entry.(map[string]interface{}); ok {
+				if id, _, _ := unstructured.NestedString(entryMap, "id"); id != "" {
+					ids[id] = true
+				}
+			}
+		}
+	}
+	return ids, nil
+}
+
+// fluxOwned reports whether labels carry the ownership labels of a Flux controller
+func fluxOwned(objectLabels map[string]string) bool {
+	for _, keys := range fluxOwnerLabels {
+		if objectLabels[keys[0]] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isUnmanagedSkipped(namespace string) bool {
+	for _, name := range unmanagedSkippedNamespaces {
+		if namespace == name {
+			return true
+		}
+	}
+	return false
+}
+
+// listUnmanagedCandidates lists the workloads the unmanaged report checks
+func listUnmanagedCandidates(ctx context.Context, typedClient kubernetes.Interface, namespace string) ([]unmanagedCandidate, error) {
+	opts := metav1.ListOptions{}
+	var candidates []unmanagedCandidate
+
+	deployments, err := typedClient.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		candidates = append(candidates, unmanagedCandidate{group: "apps", kind: "Deployment", meta: d.ObjectMeta})
+	}
+
+	statefulSets, err := typedClient.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		candidates = append(candidates, unmanagedCandidate{group: "apps", kind: "StatefulSet", meta: s.ObjectMeta})
+	}
+
+	daemonSets, err := typedClient.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		candidates = append(candidates, unmanagedCandidate{group: "apps", kind: "DaemonSet", meta: d.ObjectMeta})
+	}
+
+	cronJobs, err := typedClient.BatchV1().CronJobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, j := range cronJobs.Items {
+		candidates = append(candidates, unmanagedCandidate{group: "batch", kind: "CronJob", meta: j.ObjectMeta})
+	}
+
+	jobs, err := typedClient.BatchV1().Jobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, j := range jobs.Items {
+		candidates = append(candidates, unmanagedCandidate{group: "batch", kind: "Job", meta: j.ObjectMeta})
+	}
+
+	pods, err := typedClient.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, p := range pods.Items {
+		candidates = append(candidates, unmanagedCandidate{kind: "Pod", meta: p.ObjectMeta})
+	}
+
+	return candidates, nil
+}
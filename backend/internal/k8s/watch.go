@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FluxEventType is the kind of change a watch reported
+type FluxEventType string
+
+const (
+	FluxResourceAdded   FluxEventType = "added"
+	FluxResourceUpdated FluxEventType = "updated"
+	FluxResourceDeleted FluxEventType = "deleted"
+)
+
+// FluxEvent is a change to a Flux resource seen by a cluster watch. Deleted events carry
+// the last state the watch knew.
+type FluxEvent struct {
+	Type     FluxEventType
+	Resource models.FluxResource
+}
+
+// FluxEventHandler receives the events of every watched cluster. It is called from the
+// informer goroutines, concurrently for different clusters and kinds.
+type FluxEventHandler func(event FluxEvent)
+
+// watcher tracks the running watch of each cluster
+type watcher struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	handler FluxEventHandler
+	cancels map[string]context.CancelFunc
+}
+
+// WatchFluxResources streams changes to the Flux resources of every cluster to handler
+// until ctx is cancelled. Clusters added later are watched as they are added and removed
+// clusters stop being watched. Objects that exist when a watch starts are delivered as
+// added events. Kinds whose CRD is missing when the watch starts are not watched.
+func (c *Client) WatchFluxResources(ctx context.Context, handler FluxEventHandler) {
+	c.watches.mu.Lock()
+	c.watches.ctx = ctx
+	c.watches.handler = handler
+	c.watches.cancels = make(map[string]context.CancelFunc)
+	c.watches.mu.Unlock()
+
+	for _, clusterID := range c.clusterIDs() {
+		c.startWatch(clusterID)
+	}
+}
+
+// startWatch (re)starts the watch of a cluster once WatchFluxResources has been called
+func (c *Client) startWatch(clusterID string) {
+	c.watches.mu.Lock()
+	defer c.watches.mu.Unlock()
+
+	if c.watches.handler == nil || c.watches.ctx.Err() != nil {
+		return
+	}
+	if cancel, ok := c.watches.cancels[clusterID]; ok {
+		cancel()
+	}
+	client, ok := c.dynamicClient(clusterID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.watches.ctx)
+	c.watches.cancels[clusterID] = cancel
+	go c.runWatch(ctx, clusterID, client, c.watches.handler)
+}
+
+// stopWatch stops the watch of a cluster, if any
+func (c *Client) stopWatch(clusterID string) {
+	c.watches.mu.Lock()
+	defer c.watches.mu.Unlock()
+
+	if cancel, ok := c.watches.cancels[clusterID]; ok {
+		cancel()
+		delete(c.watches.cancels, clusterID)
+	}
+}
+
+// runWatch runs one informer per installed Flux kind until ctx is cancelled
+func (c *Client) runWatch(ctx context.Context, clusterID string, client dynamic.Interface, handler FluxEventHandler) {
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	defer factory.Shutdown()
+
+	for _, kind := range fluxKinds {
+		gvr, err := c.resolveFluxGVR(clusterID, kind.Kind)
+		if err != nil {
+			continue
+		}
+		// Informers retry forever against a missing CRD, so only watch kinds that list
+		if _, err := client.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+			continue
+		}
+
+		kind := kind.Kind
+		emit := func(eventType FluxEventType, obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				handler(FluxEvent{Type: eventType, Resource: c.parseFluxResource(clusterID, kind, u)})
+			}
+		}
+		factory.ForResource(gvr).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { emit(FluxResourceAdded, obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldU, oldOK := oldObj.(*unstructured.Unstructured)
+				newU, newOK := newObj.(*unstructured.Unstructured)
+				if oldOK && newOK && oldU.GetResourceVersion() == newU.GetResourceVersion() {
+					return
+				}
+				emit(FluxResourceUpdated, newObj)
+			},
+			DeleteFunc: func(obj interface{}) { emit(FluxResourceDeleted, obj) },
+		})
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+}
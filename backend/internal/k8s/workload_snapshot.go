@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WorkloadSnapshot is the replica state of a Deployment, StatefulSet, DaemonSet or
+// ReplicaSet at one point in time
+type WorkloadSnapshot struct {
+	Replicas           int64     `json:"replicas"`           // desired
+	ReadyReplicas      int64     `json:"ready_replicas"`     // ready pods
+	UpdatedReplicas    int64     `json:"updated_replicas"`   // pods on the current template
+	AvailableReplicas  int64     `json:"available_replicas"` // ready for at least minReadySeconds
+	Generation         int64     `json:"generation"`
+	ObservedGeneration int64     `json:"observed_generation"`
+	CapturedAt         time.Time `json:"captured_at"`
+}
+
+// Settled reports whether the controller has acted on the latest spec and every desired
+// pod is updated and ready
+func (s *WorkloadSnapshot) Settled() bool {
+	return s.ObservedGeneration >= s.Generation &&
+		s.UpdatedReplicas == s.Replicas &&
+		s.ReadyReplicas == s.Replicas &&
+		s.AvailableReplicas == s.Replicas
+}
+
+// GetWorkloadSnapshot captures the replica state of a workload
+func (c *Client) GetWorkloadSnapshot(ctx context.Context, clusterID, kind, namespace, name string) (*WorkloadSnapshot, error) {
+	resource, _, err := c.GetResourceByKind(ctx, clusterID, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return workloadSnapshot(resource), nil
+}
+
+// workloadSnapshot reads the replica counters of a workload; DaemonSets count scheduled
+// nodes instead of replicas
+func workloadSnapshot(obj *unstructured.Unstructured) *WorkloadSnapshot {
+	field := func(fields ...string) int64 {
+		value, _, _ := unstructured.NestedInt64(obj.Object, fields...)
+		return value
+	}
+
+	snapshot := &WorkloadSnapshot{
+		Generation:         obj.GetGeneration(),
+		ObservedGeneration: field("status", "observedGeneration"),
+		CapturedAt:         time.Now().UTC(),
+	}
+	if obj.GetKind() == "DaemonSet" {
+		snapshot.Replicas = field("status", "desiredNumberScheduled")
+		snapshot.ReadyReplicas = field("status", "numberReady")
+		snapshot.UpdatedReplicas = field("status", "updatedNumberScheduled")
+		snapshot.AvailableReplicas = field("status", "numberAvailable")
+		return snapshot
+	}
+
+	snapshot.Replicas = 1
+	if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		snapshot.Replicas = replicas
+	}
+	snapshot.ReadyReplicas = field("status", "readyReplicas")
+	snapshot.UpdatedReplicas = field("status", "updatedReplicas")
+	snapshot.AvailableReplicas = field("status", "availableReplicas")
+	if obj.GetKind() == "ReplicaSet" {
+		// ReplicaSets have a single template, so every pod is current
+		snapshot.UpdatedReplicas = field("status", "replicas")
+	}
+	return snapshot
+}
@@ -0,0 +1,143 @@
+// Package loadtest seeds many synthetic clusters with many Flux resources, backed by
+// in-memory fakes, so list, dashboard, tree and sync latency can be measured at scale.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Source marks clusters created by load-test mode
+const Source = "loadtest"
+
+// ClusterPrefix prefixes the ID and name of every load-test cluster
+const ClusterPrefix = "loadtest-"
+
+// resourcesPerNamespace spreads resources over namespaces like a real fleet
+const resourcesPerNamespace = 50
+
+// notReadyEvery makes every nth resource NotReady so status filters have work to do
+const notReadyEvery = 10
+
+// kinds are cycled through when generating resources
+var kinds = []struct {
+	apiVersion string
+	kind       string
+}{
+	{"source.toolkit.fluxcd.io/v1", "GitRepository"},
+	{"kustomize.toolkit.fluxcd.io/v1", "Kustomization"},
+	{"helm.toolkit.fluxcd.io/v2", "HelmRelease"},
+	{"source.toolkit.fluxcd.io/v1", "HelmRepository"},
+}
+
+// ClusterID returns the ID of the nth load-test cluster
+func ClusterID(n int) string {
+	return fmt.Sprintf("%s%03d", ClusterPrefix, n)
+}
+
+// Seed registers fake clusters with resourcesPerCluster Flux resources each and syncs
+// them into the database through the same path as the sync worker. Existing load-test
+// clusters are re-registered and resynced, so it is safe to run on every start.
+func Seed(ctx context.Context, db *database.DB, k8sClient *k8s.Client, clusters, resourcesPerCluster int) error {
+	clusterRepo := repository.NewClusterRepository(db)
+	resourceRepo := repository.NewResourceRepository(db)
+
+	for n := 0; n < clusters; n++ {
+		id := ClusterID(n)
+		k8sClient.AddFakeCluster(id, objects(resourcesPerCluster), nil)
+
+		if _, err := clusterRepo.Get(id); repository.IsNotFound(err) {
+			cluster := &models.Cluster{
+				ID:          id,
+				Name:        id,
+				Description: fmt.Sprintf("Load-test cluster with %d Flux resources", resourcesPerCluster),
+				Status:      "unknown",
+				Source:      Source,
+				ServerURL:   fmt.Sprintf("https://%s.loadtest.invalid", id),
+			}
+			if err := clusterRepo.Create(cluster); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		resources, err := k8sClient.GetFluxResources(id)
+		if err != nil {
+			return fmt.Errorf("load-test cluster %s: %w", id, err)
+		}
+		if err := resourceRepo.AssignIDs(id, resources); err != nil {
+			return err
+		}
+		if err := history.RecordTransitions(db, id, resources); err != nil {
+			return err
+		}
+		for i := range resources {
+			if err := resourceRepo.Save(&resources[i]); err != nil {
+				return err
+			}
+		}
+		if err := history.RecordClusterSnapshot(db, id, resources); err != nil {
+			return err
+		}
+		if err := clusterRepo.Update(id, map[string]interface{}{
+			"status":         "healthy",
+			"resource_count": len(resources),
+		}); err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// objects generates count Flux resources spread over namespaces and kinds
+func objects(count int) []runtime.Object {
+	now := time.Now().UTC().Format(time.RFC3339)
+	objects := make([]runtime.Object, 0, count)
+	for i := 0; i < count; i++ {
+		k := kinds[i%len(kinds)]
+		status, reason, message := "True", "ReconciliationSucceeded", "Applied revision: main@sha1:4f2c9e1"
+		if i%notReadyEvery == notReadyEvery-1 {
+			status, reason, message = "False", "ReconciliationFailed", "context deadline exceeded"
+		}
+
+		objects = append(objects, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": k.apiVersion,
+			"kind":       k.kind,
+			"metadata": map[string]interface{}{
+				"name":              fmt.Sprintf("%s-%05d", strings.ToLower(k.kind), i),
+				"namespace":         fmt.Sprintf("team-%03d", i/resourcesPerNamespace),
+				"creationTimestamp": now,
+			},
+			"spec": map[string]interface{}{"interval": "10m"},
+			"status": map[string]interface{}{
+				"lastHandledReconcileAt": now,
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               "Ready",
+						"status":             status,
+						"reason":             reason,
+						"message":            message,
+						"lastTransitionTime": now,
+					},
+				},
+			},
+		}})
+	}
+	return objects
+}
@@ -0,0 +1,178 @@
+package loadtest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/api"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/loadtest"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/rbac"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/webhooks"
+	"go.uber.org/zap"
+)
+
+// The benchmarks seed LOADTEST_CLUSTERS clusters (default 20) with LOADTEST_RESOURCES
+// resources each (default 100) into the throwaway database LOADTEST_DB_NAME, reached
+// with the server's DB_DRIVER, DB_HOST, DB_PORT, DB_USER, DB_PASSWORD and DB_SSLMODE.
+// Without LOADTEST_DB_NAME they are skipped.
+var (
+	seedOnce    sync.Once
+	seedHandler http.Handler
+	seedErr     error
+)
+
+// seeded returns the API of a server running against the seeded database
+func seeded(b *testing.B) http.Handler {
+	b.Helper()
+	name := os.Getenv("LOADTEST_DB_NAME")
+	if name == "" {
+		b.Skip("LOADTEST_DB_NAME names no throwaway database to seed")
+	}
+
+	seedOnce.Do(func() {
+		seedHandler, seedErr = seed(name)
+	})
+	if seedErr != nil {
+		b.Fatalf("seed load-test database: %v", seedErr)
+	}
+	return seedHandler
+}
+
+func seed(name string) (http.Handler, error) {
+	db, err := database.New(database.Config{
+		Driver:   env("DB_DRIVER", "postgres"),
+		Host:     env("DB_HOST", "localhost"),
+		Port:     envInt("DB_PORT", 5432),
+		User:     env("DB_USER", "postgres"),
+		Password: env("DB_PASSWORD", "postgres"),
+		DBName:   name,
+		SSLMode:  env("DB_SSLMODE", "disable"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The server's schema and default roles
+	if err := db.InitSchema(
+		&models.Cluster{},
+		&models.FluxResource{},
+		&models.AzureSubscription{},
+		&models.RegistryCredential{},
+		&models.OAuthProvider{},
+		&models.Activity{},
+		&models.ClusterStatusSnapshot{},
+		&models.ResourceStatusTransition{},
+		&models.DeploymentEvent{},
+		&models.WebhookEvent{},
+		&models.ReportTemplate{},
+		&models.APIToken{},
+		&models.Session{},
+		&models.ProviderGrant{},
+		&models.User{},
+		&models.Role{},
+		&models.Permission{},
+		&models.UserRole{},
+		&models.RolePermission{},
+		&models.RoleMappingRule{},
+		&models.ClusterShare{},
+	); err != nil {
+		return nil, err
+	}
+	if err := rbac.NewManager(db).InitializeDefaultRoles(); err != nil {
+		return nil, err
+	}
+
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	encryptor, err := encryption.NewEncryptor(key)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient := k8s.NewClient()
+	if err := loadtest.Seed(context.Background(), db, k8sClient, envInt("LOADTEST_CLUSTERS", 20), envInt("LOADTEST_RESOURCES", 100)); err != nil {
+		return nil, err
+	}
+	return api.NewServer(db, k8sClient, encryptor, nil, webhooks.NewNotifier(nil, zap.NewNop())), nil
+}
+
+// get requests path and fails the benchmark unless it succeeds
+func get(b *testing.B, handler http.Handler, path string) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != http.StatusOK {
+		b.Fatalf("GET %s: status %d: %s", path, rec.Code, rec.Body.String())
+	}
+}
+
+// benchmark measures requests for paths, one after another per iteration. A %s in a
+// path is replaced by a load-test cluster, a different one on each iteration.
+func benchmark(b *testing.B, paths ...string) {
+	handler := seeded(b)
+	clusters := envInt("LOADTEST_CLUSTERS", 20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cluster := loadtest.ClusterID(i % clusters)
+		for _, path := range paths {
+			get(b, handler, fmt.Sprintf(path, cluster))
+		}
+	}
+}
+
+func BenchmarkListClusters(b *testing.B) {
+	benchmark(b, "/api/v1/clusters?%.0s")
+}
+
+func BenchmarkListResources(b *testing.B) {
+	benchmark(b, "/api/v1/resources?%.0s")
+}
+
+func BenchmarkListResourcesV2(b *testing.B) {
+	benchmark(b, "/api/v2/resources?%.0s")
+}
+
+// BenchmarkDashboard loads the two lists the dashboard shows
+func BenchmarkDashboard(b *testing.B) {
+	benchmark(b, "/api/v1/clusters?%.0s", "/api/v1/resources?%.0s")
+}
+
+func BenchmarkClusterResources(b *testing.B) {
+	benchmark(b, "/api/v1/clusters/%s/resources")
+}
+
+// BenchmarkResourceTree measures trees served from the cache, and rebuilt on every request
+func BenchmarkResourceTree(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		benchmark(b, "/api/v1/clusters/%s/resources/tree")
+	})
+	b.Run("refresh", func(b *testing.B) {
+		benchmark(b, "/api/v1/clusters/%s/resources/tree?refresh=true")
+	})
+}
+
+func env(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
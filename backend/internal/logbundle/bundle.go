@@ -0,0 +1,192 @@
+// Package logbundle collects container logs into zip archives, either streamed directly
+// to a response or built in the background for bundles too large to wait on.
+package logbundle
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+)
+
+// Default size limits, overridable with LOG_BUNDLE_CONTAINER_MAX_BYTES and LOG_BUNDLE_MAX_BYTES
+const (
+	DefaultMaxContainerBytes = 10 << 20
+	DefaultMaxTotalBytes     = 100 << 20
+)
+
+// Options controls what a bundle contains
+type Options struct {
+	TailLines         int64 `json:"tail_lines"` // 0 collects whole logs
+	Previous          bool  `json:"previous"`
+	MaxContainerBytes int64 `json:"max_container_bytes"`
+	MaxTotalBytes     int64 `json:"max_total_bytes"`
+}
+
+// Entry describes one container log in a bundle
+type Entry struct {
+	File      string `json:"file"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Init      bool   `json:"init"`
+	Bytes     int64  `json:"bytes"`
+	Truncated bool   `json:"truncated"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Manifest is written to manifest.json at the root of every bundle
+type Manifest struct {
+	ClusterID  string    `json:"cluster_id"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Previous   bool      `json:"previous"`
+	TailLines  int64     `json:"tail_lines"`
+	CreatedAt  time.Time `json:"created_at"`
+	TotalBytes int64     `json:"total_bytes"`
+	Truncated  bool      `json:"truncated"` // a size limit cut at least one log short
+	Entries    []Entry   `json:"entries"`
+}
+
+// OptionsFromEnv returns the default options with size limits read from the environment
+func OptionsFromEnv() Options {
+	return Options{
+		MaxContainerBytes: envBytes("LOG_BUNDLE_CONTAINER_MAX_BYTES", DefaultMaxContainerBytes),
+		MaxTotalBytes:     envBytes("LOG_BUNDLE_MAX_BYTES", DefaultMaxTotalBytes),
+	}
+}
+
+func envBytes(key string, fallback int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// FileName returns the download name of a bundle
+func FileName(kind, namespace, name string) string {
+	return fmt.Sprintf("logs-%s-%s-%s-%s.zip", namespace, kind, name, time.Now().UTC().Format("20060102-150405"))
+}
+
+// Write streams the logs of containers into a zip written to w. Each log is capped at
+// MaxContainerBytes and the bundle at MaxTotalBytes; containers whose logs cannot be read
+// are recorded in the manifest instead of failing the bundle.
+func Write(ctx context.Context, client k8s.ClusterClient, clusterID, kind, namespace, name string, containers []k8s.LogContainer, opts Options, w io.Writer) (*Manifest, error) {
+	if opts.MaxContainerBytes <= 0 {
+		opts.MaxContainerBytes = DefaultMaxContainerBytes
+	}
+	if opts.MaxTotalBytes <= 0 {
+		opts.MaxTotalBytes = DefaultMaxTotalBytes
+	}
+
+	manifest := &Manifest{
+		ClusterID: clusterID,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Previous:  opts.Previous,
+		TailLines: opts.TailLines,
+		CreatedAt: time.Now().UTC(),
+		Entries:   []Entry{},
+	}
+
+	archive := zip.NewWriter(w)
+	for _, container := range containers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		suffix := ".log"
+		if opts.Previous {
+			suffix = ".previous.log"
+		}
+		entry := Entry{
+			File:      path.Join(container.Namespace, container.Pod, container.Container+suffix),
+			Namespace: container.Namespace,
+			Pod:       container.Pod,
+			Container: container.Container,
+			Init:      container.Init,
+		}
+
+		remaining := opts.MaxTotalBytes - manifest.TotalBytes
+		if remaining <= 0 {
+			entry.Truncated = true
+			entry.Error = "bundle size limit reached"
+			manifest.Truncated = true
+			manifest.Entries = append(manifest.Entries, entry)
+			continue
+		}
+		limit := min(opts.MaxContainerBytes, remaining)
+
+		if err := writeEntry(ctx, archive, client, clusterID, container, opts, limit, &entry); err != nil {
+			return nil, err
+		}
+		if entry.Truncated {
+			manifest.Truncated = true
+		}
+		manifest.TotalBytes += entry.Bytes
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	file, err := archive.Create("manifest.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to add manifest: %w", err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeEntry copies one container log into the archive. Errors opening the log are
+// recorded on the entry; only errors writing the archive itself are returned.
+func writeEntry(ctx context.Context, archive *zip.Writer, client k8s.ClusterClient, clusterID string, container k8s.LogContainer, opts Options, limit int64, entry *Entry) error {
+	stream, err := client.StreamContainerLogs(ctx, clusterID, container, k8s.LogStreamOptions{
+		TailLines: opts.TailLines,
+		Previous:  opts.Previous,
+	})
+	if err != nil {
+		entry.Error = err.Error()
+		return nil
+	}
+	defer stream.Close()
+
+	file, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     entry.File,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", entry.File, err)
+	}
+
+	// Probe for one more byte to tell a log that fits exactly from one that was cut
+	written, err := io.Copy(file, io.LimitReader(stream, limit))
+	entry.Bytes = written
+	if err != nil {
+		entry.Error = fmt.Sprintf("log stream interrupted: %v", err)
+		return nil
+	}
+	if written == limit {
+		var probe [1]byte
+		if n, _ := stream.Read(probe[:]); n > 0 {
+			entry.Truncated = true
+		}
+	}
+	return nil
+}
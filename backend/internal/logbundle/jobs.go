@@ -0,0 +1,176 @@
+package logbundle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/jobs"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Job states
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// Background bundle limits
+const (
+	// JobTTL is how long a finished bundle stays available for download
+	JobTTL = time.Hour
+	// JobTimeout bounds how long a single bundle may take to collect
+	JobTimeout = 15 * time.Minute
+	// maxConcurrentJobs caps bundles being collected at once
+	maxConcurrentJobs = 2
+)
+
+// Job is a log bundle being collected in the background
+type Job struct {
+	ID          string    `json:"id"`
+	ClusterID   string    `json:"cluster_id"`
+	Kind        string    `json:"kind"`
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	Containers  int       `json:"containers"`
+	RequestedBy string    `json:"requested_by"`
+	FileName    string    `json:"file_name"`
+	Manifest    *Manifest `json:"manifest,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	path string
+}
+
+// Manager runs background bundle jobs and keeps their archives on disk until they expire
+type Manager struct {
+	client k8s.ClusterClient
+	dir    string
+	jobs   *jobs.Store[Job]
+}
+
+// NewManager creates a manager storing archives under the system temporary directory
+func NewManager(client k8s.ClusterClient) *Manager {
+	return &Manager{
+		client: client,
+		dir:    filepath.Join(os.TempDir(), "flux-orchestrator-log-bundles"),
+		jobs:   jobs.NewStore[Job](maxConcurrentJobs),
+	}
+}
+
+// Start queues a bundle of the given containers and returns the pending job
+func (m *Manager) Start(clusterID, kind, namespace, name, requestedBy string, containers []k8s.LogContainer, opts Options) Job {
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.New().String(),
+		ClusterID:   clusterID,
+		Kind:        kind,
+		Namespace:   namespace,
+		Name:        name,
+		Status:      JobPending,
+		Containers:  len(containers),
+		RequestedBy: requestedBy,
+		FileName:    FileName(kind, namespace, name),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(JobTimeout + JobTTL),
+	}
+	job.path = filepath.Join(m.dir, job.ID+".zip")
+
+	snapshot := m.jobs.Add(job.ID, job)
+	go m.run(job, containers, opts)
+	return snapshot
+}
+
+// run collects a job's bundle once a slot is free
+func (m *Manager) run(job *Job, containers []k8s.LogContainer, opts Options) {
+	m.jobs.Acquire()
+	defer m.jobs.Release()
+
+	m.jobs.Update(job.ID, func(j *Job) { j.Status = JobRunning })
+
+	ctx, cancel := context.WithTimeout(context.Background(), JobTimeout)
+	defer cancel()
+
+	manifest, err := m.write(ctx, job, containers, opts)
+	m.jobs.Update(job.ID, func(j *Job) {
+		j.CompletedAt = time.Now()
+		j.ExpiresAt = j.CompletedAt.Add(JobTTL)
+		if err != nil {
+			j.Status = JobFailed
+			j.Error = err.Error()
+			return
+		}
+		j.Status = JobCompleted
+		j.Manifest = manifest
+	})
+	if err != nil {
+		os.Remove(job.path)
+		logging.GetLogger().Warn("Log bundle failed",
+			zap.String("job_id", job.ID),
+			zap.String("cluster_id", job.ClusterID),
+			zap.Error(err))
+	}
+}
+
+// write builds the archive for a job on disk
+func (m *Manager) write(ctx context.Context, job *Job, containers []k8s.LogContainer, opts Options) (*Manifest, error) {
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create log bundle directory: %w", err)
+	}
+	file, err := os.Create(job.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer file.Close()
+
+	manifest, err := Write(ctx, m.client, job.ClusterID, job.Kind, job.Namespace, job.Name, containers, opts, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle file: %w", err)
+	}
+	return manifest, nil
+}
+
+// Get returns a copy of a job
+func (m *Manager) Get(id string) (Job, bool) {
+	return m.jobs.Get(id)
+}
+
+// Open returns the archive of a completed job; the caller closes it
+func (m *Manager) Open(id string) (*os.File, Job, error) {
+	job, ok := m.Get(id)
+	if !ok {
+		return nil, Job{}, fmt.Errorf("log bundle %s not found", id)
+	}
+	if job.Status != JobCompleted {
+		return nil, job, fmt.Errorf("log bundle %s is %s", id, job.Status)
+	}
+	file, err := os.Open(job.path)
+	if err != nil {
+		return nil, job, fmt.Errorf("failed to open log bundle: %w", err)
+	}
+	return file, job, nil
+}
+
+// CleanExpired removes expired jobs and their archives
+func (m *Manager) CleanExpired() {
+	now := time.Now()
+	expired := m.jobs.RemoveIf(func(job *Job) bool {
+		return job.Status != JobPending && job.Status != JobRunning && now.After(job.ExpiresAt)
+	})
+	for _, job := range expired {
+		os.Remove(job.path)
+	}
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// prefixPlaceholder is replaced with the installation's metric prefix when serving dashboards
+const prefixPlaceholder = "__METRIC_PREFIX__"
+
+//go:embed dashboards/*.json
+var dashboardFS embed.FS
+
+// DashboardNames lists the bundled Grafana dashboards
+func DashboardNames() ([]string, error) {
+	entries, err := dashboardFS.ReadDir("dashboards")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboards: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Dashboard returns the Grafana dashboard JSON with the metric prefix substituted
+func Dashboard(name string) ([]byte, error) {
+	if name == "" || strings.ContainsAny(name, "/\\.") {
+		return nil, fmt.Errorf("dashboard %q not found", name)
+	}
+
+	data, err := dashboardFS.ReadFile(path.Join("dashboards", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("dashboard %q not found", name)
+	}
+
+	return []byte(strings.ReplaceAll(string(data), prefixPlaceholder, Prefix)), nil
+}
@@ -1,15 +1,36 @@
 package metrics
 
 import (
+	"os"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// DefaultPrefix is the metric name prefix used when METRICS_PREFIX is not set
+const DefaultPrefix = "flux_orchestrator"
+
+// Prefix is the metric name prefix for this installation
+var Prefix = metricPrefix()
+
+// metricPrefix reads the metric name prefix from the environment
+func metricPrefix() string {
+	if prefix := os.Getenv("METRICS_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return DefaultPrefix
+}
+
+// Name returns the fully prefixed name of a metric
+func Name(suffix string) string {
+	return Prefix + "_" + suffix
+}
+
 var (
 	// HTTP metrics
 	HTTPRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "flux_orchestrator_http_requests_total",
+			Name: Name("http_requests_total"),
 			Help: "Total number of HTTP requests",
 		},
 		[]string{"method", "path", "status"},
@@ -17,24 +38,32 @@ var (
 
 	HTTPRequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "flux_orchestrator_http_request_duration_seconds",
+			Name:    Name("http_request_duration_seconds"),
 			Help:    "HTTP request duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		},
 		[]string{"method", "path"},
 	)
 
+	HTTPPanicsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: Name("http_panics_total"),
+			Help: "Total number of HTTP handler panics recovered",
+		},
+		[]string{"method", "path"},
+	)
+
 	// Cluster metrics
 	ClustersTotal = promauto.NewGauge(
 		prometheus.GaugeOpts{
-			Name: "flux_orchestrator_clusters_total",
+			Name: Name("clusters_total"),
 			Help: "Total number of registered clusters",
 		},
 	)
 
 	ClustersHealthy = promauto.NewGauge(
 		prometheus.GaugeOpts{
-			Name: "flux_orchestrator_clusters_healthy",
+			Name: Name("clusters_healthy"),
 			Help: "Number of healthy clusters",
 		},
 	)
@@ -42,7 +71,7 @@ var (
 	// Resource metrics
 	FluxResourcesTotal = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "flux_orchestrator_flux_resources_total",
+			Name: Name("flux_resources_total"),
 			Help: "Total number of Flux resources",
 		},
 		[]string{"cluster_id", "kind", "status"},
@@ -51,7 +80,7 @@ var (
 	// Reconciliation metrics
 	ReconciliationsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "flux_orchestrator_reconciliations_total",
+			Name: Name("reconciliations_total"),
 			Help: "Total number of reconciliation requests",
 		},
 		[]string{"cluster_id", "kind", "status"},
@@ -60,7 +89,7 @@ var (
 	// Sync worker metrics
 	SyncDuration = promauto.NewHistogram(
 		prometheus.HistogramOpts{
-			Name:    "flux_orchestrator_sync_duration_seconds",
+			Name:    Name("sync_duration_seconds"),
 			Help:    "Duration of sync operations",
 			Buckets: []float64{1, 5, 10, 30, 60, 120, 300},
 		},
@@ -68,7 +97,7 @@ var (
 
 	SyncErrorsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "flux_orchestrator_sync_errors_total",
+			Name: Name("sync_errors_total"),
 			Help: "Total number of sync errors",
 		},
 		[]string{"cluster_id", "error_type"},
@@ -77,7 +106,7 @@ var (
 	// Database metrics
 	DatabaseQueriesTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "flux_orchestrator_database_queries_total",
+			Name: Name("database_queries_total"),
 			Help: "Total number of database queries",
 		},
 		[]string{"operation", "table"},
@@ -85,7 +114,7 @@ var (
 
 	DatabaseQueryDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "flux_orchestrator_database_query_duration_seconds",
+			Name:    Name("database_query_duration_seconds"),
 			Help:    "Database query duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		},
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// AlertThresholds configures the recommended alerting rules
+type AlertThresholds struct {
+	HTTPErrorRatePercent   float64 `json:"http_error_rate_percent"`
+	HTTPLatencyP95Seconds  float64 `json:"http_latency_p95_seconds"`
+	SyncErrorsPer15m       float64 `json:"sync_errors_per_15m"`
+	SyncDurationP95Seconds float64 `json:"sync_duration_p95_seconds"`
+	NotReadyResources      float64 `json:"not_ready_resources"`
+	ForMinutes             int     `json:"for_minutes"`
+}
+
+// DefaultAlertThresholds returns the thresholds used when none are configured
+func DefaultAlertThresholds() AlertThresholds {
+	return AlertThresholds{
+		HTTPErrorRatePercent:   5,
+		HTTPLatencyP95Seconds:  2,
+		SyncErrorsPer15m:       3,
+		SyncDurationP95Seconds: 120,
+		NotReadyResources:      0,
+		ForMinutes:             10,
+	}
+}
+
+type alertRule struct {
+	Alert       string            `json:"alert"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ruleGroup struct {
+	Name  string      `json:"name"`
+	Rules []alertRule `json:"rules"`
+}
+
+type prometheusRule struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string            `json:"name"`
+		Namespace string            `json:"namespace,omitempty"`
+		Labels    map[string]string `json:"labels,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		Groups []ruleGroup `json:"groups"`
+	} `json:"spec"`
+}
+
+// PrometheusRuleYAML renders a PrometheusRule manifest alerting on the orchestrator's metrics
+func PrometheusRuleYAML(namespace string, t AlertThresholds) ([]byte, error) {
+	forDuration := fmt.Sprintf("%dm", t.ForMinutes)
+	alert := func(name, expr, severity, summary string) alertRule {
+		return alertRule{
+			Alert:       name,
+			Expr:        expr,
+			For:         forDuration,
+			Labels:      map[string]string{"severity": severity},
+			Annotations: map[string]string{"summary": summary},
+		}
+	}
+
+	rule := prometheusRule{
+		APIVersion: "monitoring.coreos.com/v1",
+		Kind:       "PrometheusRule",
+	}
+	rule.Metadata.Name = "flux-orchestrator"
+	rule.Metadata.Namespace = namespace
+	rule.Metadata.Labels = map[string]string{"app.kubernetes.io/name": "flux-orchestrator"}
+	rule.Spec.Groups = []ruleGroup{
+		{
+			Name: "flux-orchestrator.api",
+			Rules: []alertRule{
+				alert("FluxOrchestratorHighErrorRate",
+					fmt.Sprintf(`100 * sum(rate(%s{status=~"5.."}[5m])) / sum(rate(%s[5m])) > %g`,
+						Name("http_requests_total"), Name("http_requests_total"), t.HTTPErrorRatePercent),
+					"warning",
+					fmt.Sprintf("More than %g%% of orchestrator API requests are failing", t.HTTPErrorRatePercent)),
+				alert("FluxOrchestratorHighLatency",
+					fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(%s_bucket[5m]))) > %g`,
+						Name("http_request_duration_seconds"), t.HTTPLatencyP95Seconds),
+					"warning",
+					fmt.Sprintf("Orchestrator API p95 latency is above %gs", t.HTTPLatencyP95Seconds)),
+				alert("FluxOrchestratorHandlerPanics",
+					fmt.Sprintf(`sum(increase(%s[15m])) > 0`, Name("http_panics_total")),
+					"warning",
+					"Orchestrator API handlers are panicking"),
+			},
+		},
+		{
+			Name: "flux-orchestrator.sync",
+			Rules: []alertRule{
+				alert("FluxOrchestratorSyncErrors",
+					fmt.Sprintf(`sum by (cluster_id) (increase(%s[15m])) > %g`,
+						Name("sync_errors_total"), t.SyncErrorsPer15m),
+					"warning",
+					"Cluster {{ $labels.cluster_id }} is failing to sync"),
+				alert("FluxOrchestratorSlowSync",
+					fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(%s_bucket[30m]))) > %g`,
+						Name("sync_duration_seconds"), t.SyncDurationP95Seconds),
+					"info",
+					fmt.Sprintf("Sync cycles are taking longer than %gs", t.SyncDurationP95Seconds)),
+				alert("FluxOrchestratorClusterUnhealthy",
+					fmt.Sprintf(`%s - %s > 0`, Name("clusters_total"), Name("clusters_healthy")),
+					"critical",
+					"One or more registered clusters are unhealthy"),
+				alert("FluxOrchestratorResourcesNotReady",
+					fmt.Sprintf(`sum by (cluster_id, kind) (%s{status="NotReady"}) > %g`,
+						Name("flux_resources_total"), t.NotReadyResources),
+					"warning",
+					"{{ $value }} {{ $labels.kind }} resources are not ready on cluster {{ $labels.cluster_id }}"),
+			},
+		},
+	}
+
+	return yaml.Marshal(rule)
+}
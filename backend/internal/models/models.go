@@ -8,48 +8,97 @@ import (
 
 // Cluster represents a Kubernetes cluster managed by the orchestrator
 type Cluster struct {
-	ID                  string    `json:"id" gorm:"primaryKey;size:100"`
-	Name                string    `json:"name" gorm:"size:255;uniqueIndex;not null"`
-	Description         string    `json:"description" gorm:"type:text"`
-	KubeConfig          string    `json:"-" gorm:"column:kubeconfig;type:text;not null"` // Hidden from JSON
-	Status              string    `json:"status" gorm:"size:50;default:'unknown'"`       // healthy, unhealthy, unknown
-	Source              string    `json:"source" gorm:"size:50;default:'manual'"`        // manual, azure-aks
-	SourceID            string    `json:"source_id" gorm:"size:255"`                     // Azure resource ID, etc.
-	IsFavorite          bool      `json:"is_favorite" gorm:"default:false"`              // Favorite/pinned cluster
-	HealthCheckInterval int       `json:"health_check_interval" gorm:"default:300"`      // Health check interval in seconds (default 5 min)
-	ResourceCount       int       `json:"resource_count" gorm:"default:0"`               // Cached resource count
-	CreatedAt           time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt           time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                  string         `json:"id" gorm:"primaryKey;size:100"`
+	Name                string         `json:"name" gorm:"size:255;uniqueIndex;not null"`
+	Description         string         `json:"description" gorm:"type:text"`
+	KubeConfig          string         `json:"-" gorm:"column:kubeconfig;type:text;not null"` // Hidden from JSON
+	Status              string         `json:"status" gorm:"size:50;default:'unknown'"`       // healthy, unhealthy, unknown
+	Source              string         `json:"source" gorm:"size:50;default:'manual'"`        // manual, azure-aks
+	SourceID            string         `json:"source_id" gorm:"size:255"`                     // Azure resource ID, etc.
+	IsFavorite          bool           `json:"is_favorite" gorm:"default:false"`              // Favorite/pinned cluster
+	Archived            bool           `json:"archived" gorm:"default:false;index"`           // Not synced and hidden from default lists
+	ArchivedAt          *time.Time     `json:"archived_at,omitempty"`                         // When the cluster was archived
+	HealthCheckInterval int            `json:"health_check_interval" gorm:"default:300"`      // Health check interval in seconds (default 5 min)
+	ClientQPS           float32        `json:"client_qps" gorm:"default:0"`                   // Client-side request rate limit, 0 for the client-go default
+	ClientBurst         int            `json:"client_burst" gorm:"default:0"`                 // Requests allowed above ClientQPS in bursts, 0 for the default
+	ClientTimeout       int            `json:"client_timeout" gorm:"default:0"`               // Request timeout in seconds, 0 for K8S_REQUEST_TIMEOUT_SECONDS
+	ImpersonateUsers    bool           `json:"impersonate_users" gorm:"default:false"`        // Perform user-triggered operations as the orchestrator user
+	ResourceCount       int            `json:"resource_count" gorm:"default:0"`               // Cached resource count
+	FluxStatus          string         `json:"flux_status" gorm:"size:50;default:'unknown'"`  // healthy, empty, degraded, not_installed, unknown
+	FluxMessage         string         `json:"flux_message" gorm:"type:text"`                 // Details about the Flux installation state
+	FluxVersion         string         `json:"flux_version" gorm:"size:50"`                   // Flux distribution version, empty when unknown
+	ServerURL           string         `json:"server_url" gorm:"size:500"`                    // API server the kubeconfig points at
+	CAFingerprint       string         `json:"ca_fingerprint" gorm:"size:64"`                 // SHA-256 of the API server CA certificate
+	Version             int            `json:"version" gorm:"not null;default:1"`             // Incremented on every admin edit for optimistic locking
+	CreatedAt           time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// ClusterShare gives a user, or every holder of a role, access to a cluster when cluster
+// visibility is restricted to shared clusters. Access is read, write or owner, each
+// including the ones before it; owners manage the cluster's shares.
+type ClusterShare struct {
+	ID          string    `json:"id" gorm:"primaryKey;size:100"`
+	ClusterID   string    `json:"cluster_id" gorm:"size:100;not null;uniqueIndex:idx_cluster_share"`
+	SubjectType string    `json:"subject_type" gorm:"size:20;not null;uniqueIndex:idx_cluster_share"` // user, role
+	Subject     string    `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_cluster_share"`     // Lowercased user email, or role ID
+	Access      string    `json:"access" gorm:"size:20;not null"`
+	CreatedBy   string    `json:"created_by" gorm:"size:255"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // AzureSubscription represents an Azure subscription with service principal credentials
 type AzureSubscription struct {
-	ID             string    `json:"id" gorm:"primaryKey;size:100"` // Subscription ID
-	Name           string    `json:"name" gorm:"size:255;not null"`
-	TenantID       string    `json:"tenant_id" gorm:"size:100;not null"`
-	Credentials    string    `json:"-" gorm:"type:text;not null"` // Encrypted JSON: {client_id, client_secret}
-	Status         string    `json:"status" gorm:"size:50;default:'unknown'"` // healthy, unhealthy, unknown
-	ClusterCount   int       `json:"cluster_count" gorm:"default:0"`
-	LastSyncedAt   time.Time `json:"last_synced_at"`
-	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID           string         `json:"id" gorm:"primaryKey;size:100"` // Subscription ID
+	Name         string         `json:"name" gorm:"size:255;not null"`
+	TenantID     string         `json:"tenant_id" gorm:"size:100;not null"`
+	Credentials  string         `json:"-" gorm:"type:text;not null"`             // Encrypted JSON: {client_id, client_secret}
+	Status       string         `json:"status" gorm:"size:50;default:'unknown'"` // healthy, unhealthy, unknown
+	ClusterCount int            `json:"cluster_count" gorm:"default:0"`
+	LastSyncedAt time.Time      `json:"last_synced_at"`
+	Version      int            `json:"version" gorm:"not null;default:1"` // Incremented on every admin edit for optimistic locking
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// RegistryCredential is a login for a container registry used to read image tags and
+// manifests when no image pull Secret in the cluster covers the registry
+type RegistryCredential struct {
+	ID            string         `json:"id" gorm:"primaryKey;size:100"`
+	Name          string         `json:"name" gorm:"size:255;not null"`
+	Provider      string         `json:"provider" gorm:"size:50;not null"`           // acr, ecr, gcr, dockerhub, generic
+	Registry      string         `json:"registry" gorm:"size:255;not null;index"`    // Host images name, such as myregistry.azurecr.io
+	Username      string         `json:"username" gorm:"size:255;not null"`
+	Credentials   string         `json:"-" gorm:"type:text;not null"`                // Encrypted password, token or key
+	Status        string         `json:"status" gorm:"size:50;default:'unknown'"` // healthy, unhealthy, unknown
+	StatusMessage string         `json:"status_message" gorm:"type:text"`
+	LastTestedAt  time.Time      `json:"last_tested_at"`
+	Version       int            `json:"version" gorm:"not null;default:1"` // Incremented on every admin edit for optimistic locking
+	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // OAuthProvider represents an OAuth provider configuration (GitHub, Entra ID)
 type OAuthProvider struct {
-	ID           string    `json:"id" gorm:"primaryKey;size:100"`
-	Name         string    `json:"name" gorm:"size:255;not null"`
-	Provider     string    `json:"provider" gorm:"size:50;not null"` // github, entra
-	ClientID     string    `json:"client_id" gorm:"size:255;not null"`
-	ClientSecret string    `json:"-" gorm:"type:text;not null"` // Encrypted
-	TenantID     string    `json:"tenant_id" gorm:"size:100"` // For Entra ID only
-	RedirectURL  string    `json:"redirect_url" gorm:"size:500;not null"`
-	Scopes       string    `json:"scopes" gorm:"type:text"` // Comma-separated scopes
-	AllowedUsers string    `json:"allowed_users" gorm:"type:text"` // Comma-separated emails/usernames
-	Enabled      bool      `json:"enabled" gorm:"default:false"`
-	Status       string    `json:"status" gorm:"size:50;default:'unknown'"` // healthy, unhealthy, unknown
-	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID           string         `json:"id" gorm:"primaryKey;size:100"`
+	Name         string         `json:"name" gorm:"size:255;not null"`
+	Provider     string         `json:"provider" gorm:"size:50;not null"` // github, entra
+	ClientID     string         `json:"client_id" gorm:"size:255;not null"`
+	ClientSecret string         `json:"-" gorm:"type:text;not null"` // Encrypted
+	TenantID     string         `json:"tenant_id" gorm:"size:100"`   // For Entra ID only
+	RedirectURL  string         `json:"redirect_url" gorm:"size:500;not null"`
+	Scopes       string         `json:"scopes" gorm:"type:text"`        // Comma-separated scopes
+	AllowedUsers string         `json:"allowed_users" gorm:"type:text"` // Comma-separated emails/usernames
+	Enabled      bool           `json:"enabled" gorm:"default:false"`
+	Status       string         `json:"status" gorm:"size:50;default:'unknown'"` // healthy, unhealthy, unknown
+	Version      int            `json:"version" gorm:"not null;default:1"`       // Incremented on every admin edit for optimistic locking
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // Setting represents application settings
@@ -71,10 +120,136 @@ type Activity struct {
 	UserID       string   `json:"user_id" gorm:"size:100"`                     // User who performed the action
 	Status       string   `json:"status" gorm:"size:50;default:'success'"`     // success, failed
 	Message      string   `json:"message" gorm:"type:text"`                    // Additional details or error message
+	Details      string   `json:"details,omitempty" gorm:"type:text"`          // JSON payload, e.g. workload snapshots around a scale or restart
 	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime;index"`
 }
 
-// FluxResource represents a generic Flux resource
+// ClusterStatusSnapshot records Flux resource readiness for a cluster at a point in time
+type ClusterStatusSnapshot struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ClusterID  string    `json:"cluster_id" gorm:"size:100;not null;index:idx_snapshot_cluster_time"`
+	Total      int       `json:"total"`
+	Ready      int       `json:"ready"`
+	NotReady   int       `json:"not_ready"`
+	Unknown    int       `json:"unknown"`
+	RecordedAt time.Time `json:"recorded_at" gorm:"not null;index:idx_snapshot_cluster_time"`
+}
+
+// TableName specifies the table name for ClusterStatusSnapshot
+func (ClusterStatusSnapshot) TableName() string {
+	return "cluster_status_history"
+}
+
+// ResourceStatusTransition records a change in a Flux resource's readiness status
+type ResourceStatusTransition struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ClusterID  string    `json:"cluster_id" gorm:"size:100;not null;index:idx_transition_cluster_time"`
+	ResourceID string    `json:"resource_id" gorm:"size:255;index"`
+	Kind       string    `json:"kind" gorm:"size:50"`
+	Namespace  string    `json:"namespace" gorm:"size:100"`
+	Name       string    `json:"name" gorm:"size:255"`
+	OldStatus  string    `json:"old_status" gorm:"size:50"`
+	NewStatus  string    `json:"new_status" gorm:"size:50"`
+	Message    string    `json:"message" gorm:"type:text"`
+	RecordedAt time.Time `json:"recorded_at" gorm:"not null;index:idx_transition_cluster_time"`
+}
+
+// DeploymentEvent records a Flux resource moving to a new revision: a source fetching a new
+// artifact, a Kustomization applying one, or a HelmRelease deploying a new chart version
+type DeploymentEvent struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ClusterID   string    `json:"cluster_id" gorm:"size:100;not null;index:idx_deployment_cluster_time"`
+	ResourceID  string    `json:"resource_id" gorm:"size:255;index"`
+	Kind        string    `json:"kind" gorm:"size:50"`
+	Namespace   string    `json:"namespace" gorm:"size:100"`
+	Name        string    `json:"name" gorm:"size:255"`
+	Type        string    `json:"type" gorm:"size:50"` // artifact, applied, chart
+	OldRevision string    `json:"old_revision" gorm:"size:255"`
+	NewRevision string    `json:"new_revision" gorm:"size:255"`
+	RecordedAt  time.Time `json:"recorded_at" gorm:"not null;index:idx_deployment_cluster_time;index:idx_deployment_time"`
+}
+
+// TableName specifies the table name for DeploymentEvent
+func (DeploymentEvent) TableName() string {
+	return "deployment_events"
+}
+
+// WebhookEvent persists a notifier event so reconnecting clients can replay what they missed.
+// ID is the event's sequence number, assigned by the event store rather than the database.
+type WebhookEvent struct {
+	ID        int64     `json:"sequence" gorm:"primaryKey;autoIncrement:false"`
+	EventID   string    `json:"id" gorm:"size:36;index"`
+	Type      string    `json:"type" gorm:"size:100;index"`
+	ClusterID string    `json:"cluster_id" gorm:"size:100;index"`
+	Resource  string    `json:"resource" gorm:"type:text"` // JSON object
+	Message   string    `json:"message" gorm:"type:text"`
+	Severity  string    `json:"severity" gorm:"size:20"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for WebhookEvent
+func (WebhookEvent) TableName() string {
+	return "webhook_events"
+}
+
+// ReportTemplate is a Go html/template used to render scheduled reports
+type ReportTemplate struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:100"`
+	Name      string    `json:"name" gorm:"size:255;uniqueIndex;not null"`
+	Subject   string    `json:"subject" gorm:"size:500"`
+	Body      string    `json:"body" gorm:"type:text;not null"`
+	IsDefault bool      `json:"is_default" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Session is a browser login session, stored when SESSION_STORE=database.
+// Only the SHA-256 hash of the session token is stored, and the user is
+// Fernet-encrypted JSON. UserHash, the SHA-256 hash of the user's lowercased
+// email, finds a user's sessions to revoke them.
+type Session struct {
+	TokenHash string    `gorm:"primaryKey;size:64"`
+	UserHash  string    `gorm:"size:64;index"`
+	UserInfo  string    `gorm:"type:text;not null"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// ProviderGrant is a user's consent for the orchestrator to call their git
+// provider as them, holding the provider token they granted. The tokens are
+// Fernet-encrypted and never returned by the API.
+type ProviderGrant struct {
+	ID           string     `json:"id" gorm:"primaryKey;size:100"`
+	UserID       string     `json:"user_id" gorm:"size:255;uniqueIndex:idx_provider_grant"`
+	Provider     string     `json:"provider" gorm:"size:50;uniqueIndex:idx_provider_grant"`
+	Username     string     `json:"username" gorm:"size:255"`
+	Scopes       string     `json:"scopes" gorm:"size:500"` // comma-separated, as granted
+	AccessToken  string     `json:"-" gorm:"type:text;not null"`
+	RefreshToken string     `json:"-" gorm:"type:text"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// APIToken is a bearer token issued to CLI and script clients.
+// Only the SHA-256 hash of the token is stored.
+type APIToken struct {
+	ID          string     `json:"id" gorm:"primaryKey;size:100"`
+	Name        string     `json:"name" gorm:"size:255"`
+	TokenHash   string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	UserID      string     `json:"user_id" gorm:"size:255;index"`
+	Email       string     `json:"email" gorm:"size:255"`
+	Username    string     `json:"username" gorm:"size:255"`
+	DisplayName string     `json:"display_name" gorm:"size:255"`
+	Provider    string     `json:"provider" gorm:"size:50"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// FluxResource represents a generic Flux resource.
+// ID is an opaque surrogate UUID; the resource is identified within a cluster by
+// the unique (cluster_id, kind, name, namespace) index.
 type FluxResource struct {
 	ID            string    `json:"id" gorm:"primaryKey;size:255"`
 	ClusterID     string    `json:"cluster_id" gorm:"size:100;not null;index;uniqueIndex:idx_unique_resource"`
@@ -143,10 +318,12 @@ type HelmRepository struct {
 
 // ReconcileRequest represents a request to reconcile a Flux resource
 type ReconcileRequest struct {
-	ClusterID string `json:"cluster_id"`
-	Kind      string `json:"kind"`
-	Name      string `json:"name"`
-	Namespace string `json:"namespace"`
+	ClusterID  string `json:"cluster_id"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	WithSource bool   `json:"with_source"` // reconcile the source first and wait for it
+	Force      bool   `json:"force"`       // HelmReleases only: run a Helm install or upgrade even when nothing changed
 }
 
 // User represents a user in the system
@@ -198,3 +375,15 @@ type RolePermission struct {
 	PermissionID string    `json:"permission_id" gorm:"primaryKey;size:100"`
 	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
+
+// RoleMappingRule gives users the role when they first sign in and match it. Type is
+// email_domain, group or provider; Value is the domain, identity provider group or provider
+// name to match.
+type RoleMappingRule struct {
+	ID        string    `json:"id" gorm:"primaryKey;size:100"`
+	Type      string    `json:"type" gorm:"size:20;not null"`
+	Value     string    `json:"value" gorm:"size:255;not null"`
+	RoleID    string    `json:"role_id" gorm:"size:100;not null;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,393 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"gorm.io/gorm"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrInvalidConfig is returned for RBAC configurations that cannot be imported
+var ErrInvalidConfig = errors.New("invalid RBAC configuration")
+
+// errDryRun rolls back the transaction of a dry-run import
+var errDryRun = errors.New("dry run")
+
+// Config is the access control configuration of an installation: its permissions, its roles
+// and the roles bound to each user. It is exported and imported as YAML or JSON so access
+// control can be kept in Git and applied to other installations.
+type Config struct {
+	Permissions []PermissionConfig `json:"permissions"`
+	Roles       []RoleConfig       `json:"roles"`
+	Bindings    []BindingConfig    `json:"bindings"`
+}
+
+// PermissionConfig is a permission of a Config
+type PermissionConfig struct {
+	ID          string `json:"id"`
+	Resource    string `json:"resource"`
+	Action      string `json:"action"`
+	Description string `json:"description,omitempty"`
+}
+
+// RoleConfig is a role of a Config with the IDs of its permissions. BuiltIn is informational;
+// built-in roles keep their name and description on import and only take the permissions.
+type RoleConfig struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	BuiltIn     bool     `json:"built_in,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// BindingConfig holds the roles of a user, identified by email. Name and Enabled are left
+// untouched on import when omitted.
+type BindingConfig struct {
+	User    string   `json:"user"`
+	Name    string   `json:"name,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+	Roles   []string `json:"roles"`
+}
+
+// ImportResult lists what an import created, updated and left unchanged, as kind/id entries
+// such as role/operator or binding/jane@example.com
+type ImportResult struct {
+	DryRun    bool     `json:"dry_run"`
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// record adds an entry to the list matching whether it was created or changed
+func (r *ImportResult) record(entry string, created, changed bool) {
+	switch {
+	case created:
+		r.Created = append(r.Created, entry)
+	case changed:
+		r.Updated = append(r.Updated, entry)
+	default:
+		r.Unchanged = append(r.Unchanged, entry)
+	}
+}
+
+// ParseConfig parses an RBAC configuration in YAML or JSON, rejecting unknown fields so a
+// misspelt key fails instead of being dropped
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return &config, nil
+}
+
+// ExportConfig returns the permissions, roles and user-role bindings of the installation,
+// sorted by ID so exports of the same configuration are identical
+func (m *Manager) ExportConfig() (*Config, error) {
+	var permissions []models.Permission
+	if err := m.db.Order("id").Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+	var roles []models.Role
+	if err := m.db.Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	var users []models.User
+	if err := m.db.Preload("Roles").Order("email").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	config := &Config{
+		Permissions: make([]PermissionConfig, 0, len(permissions)),
+		Roles:       make([]RoleConfig, 0, len(roles)),
+		Bindings:    make([]BindingConfig, 0, len(users)),
+	}
+	for _, perm := range permissions {
+		config.Permissions = append(config.Permissions, PermissionConfig{
+			ID:          perm.ID,
+			Resource:    perm.Resource,
+			Action:      perm.Action,
+			Description: perm.Description,
+		})
+	}
+	for _, role := range roles {
+		config.Roles = append(config.Roles, RoleConfig{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			BuiltIn:     role.BuiltIn,
+			Permissions: permissionIDs(role.Permissions),
+		})
+	}
+	for _, user := range users {
+		enabled := user.Enabled
+		config.Bindings = append(config.Bindings, BindingConfig{
+			User:    user.Email,
+			Name:    user.Name,
+			Enabled: &enabled,
+			Roles:   roleIDs(user.Roles),
+		})
+	}
+	return config, nil
+}
+
+// ImportConfig applies an RBAC configuration: missing permissions, roles and users are
+// created, existing ones updated to match, and the permissions of each listed role and the
+// roles of each listed user replaced by the listed ones. Anything not listed is left alone,
+// so importing the same configuration again changes nothing. The import is applied in one
+// transaction; with dryRun it is rolled back and only the result reported.
+func (m *Manager) ImportConfig(config *Config, dryRun bool) (*ImportResult, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{DryRun: dryRun}
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		for _, perm := range config.Permissions {
+			if err := importPermission(tx, perm, result); err != nil {
+				return err
+			}
+		}
+		for _, role := range config.Roles {
+			if err := importRole(tx, role, result); err != nil {
+				return err
+			}
+		}
+		for _, binding := range config.Bindings {
+			if err := importBinding(tx, binding, result); err != nil {
+				return err
+			}
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validate checks a configuration for missing and duplicate entries before anything is written
+func (c *Config) validate() error {
+	permissions := make(map[string]bool, len(c.Permissions))
+	for _, perm := range c.Permissions {
+		if perm.ID == "" || perm.Resource == "" || perm.Action == "" {
+			return fmt.Errorf("%w: permissions need an id, resource and action", ErrInvalidConfig)
+		}
+		if permissions[perm.ID] {
+			return fmt.Errorf("%w: permission %s is listed twice", ErrInvalidConfig, perm.ID)
+		}
+		permissions[perm.ID] = true
+	}
+
+	roles := make(map[string]bool, len(c.Roles))
+	names := make(map[string]bool, len(c.Roles))
+	for _, role := range c.Roles {
+		if role.ID == "" || role.Name == "" {
+			return fmt.Errorf("%w: roles need an id and a name", ErrInvalidConfig)
+		}
+		if roles[role.ID] || names[role.Name] {
+			return fmt.Errorf("%w: role %s is listed twice", ErrInvalidConfig, role.ID)
+		}
+		roles[role.ID], names[role.Name] = true, true
+	}
+
+	users := make(map[string]bool, len(c.Bindings))
+	for _, binding := range c.Bindings {
+		if binding.User == "" {
+			return fmt.Errorf("%w: bindings need a user", ErrInvalidConfig)
+		}
+		if users[binding.User] {
+			return fmt.Errorf("%w: user %s is bound twice", ErrInvalidConfig, binding.User)
+		}
+		users[binding.User] = true
+	}
+	return nil
+}
+
+// importPermission creates or updates one permission
+func importPermission(tx *gorm.DB, perm PermissionConfig, result *ImportResult) error {
+	entry := "permission/" + perm.ID
+	var existing models.Permission
+	err := tx.Where("id = ?", perm.ID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		created := models.Permission{ID: perm.ID, Resource: perm.Resource, Action: perm.Action, Description: perm.Description}
+		if err := tx.Create(&created).Error; err != nil {
+			return fmt.Errorf("failed to create permission %s: %w", perm.ID, err)
+		}
+		result.record(entry, true, false)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load permission %s: %w", perm.ID, err)
+	}
+
+	updates := make(map[string]interface{})
+	if existing.Resource != perm.Resource {
+		updates["resource"] = perm.Resource
+	}
+	if existing.Action != perm.Action {
+		updates["action"] = perm.Action
+	}
+	if existing.Description != perm.Description {
+		updates["description"] = perm.Description
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update permission %s: %w", perm.ID, err)
+		}
+	}
+	result.record(entry, false, len(updates) > 0)
+	return nil
+}
+
+// importRole creates or updates one role and replaces its permissions
+func importRole(tx *gorm.DB, role RoleConfig, result *ImportResult) error {
+	entry := "role/" + role.ID
+
+	var named models.Role
+	if err := tx.Where("name = ? AND id <> ?", role.Name, role.ID).First(&named).Error; err == nil {
+		return fmt.Errorf("%w: role %s: name %q is taken by role %s", ErrInvalidConfig, role.ID, role.Name, named.ID)
+	}
+
+	var permissions []models.Permission
+	if len(role.Permissions) > 0 {
+		if err := tx.Where("id IN ?", role.Permissions).Find(&permissions).Error; err != nil {
+			return fmt.Errorf("failed to load permissions of role %s: %w", role.ID, err)
+		}
+	}
+	if missing := missingIDs(role.Permissions, permissionIDs(permissions)); len(missing) > 0 {
+		return fmt.Errorf("%w: role %s: unknown permissions %s", ErrInvalidConfig, role.ID, strings.Join(missing, ", "))
+	}
+
+	var existing models.Role
+	err := tx.Preload("Permissions").Where("id = ?", role.ID).First(&existing).Error
+	created := errors.Is(err, gorm.ErrRecordNotFound)
+	changed := false
+	switch {
+	case created:
+		existing = models.Role{ID: role.ID, Name: role.Name, Description: role.Description}
+		if err := tx.Create(&existing).Error; err != nil {
+			return fmt.Errorf("failed to create role %s: %w", role.ID, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to load role %s: %w", role.ID, err)
+	case !existing.BuiltIn && (existing.Name != role.Name || existing.Description != role.Description):
+		if err := tx.Model(&existing).Updates(map[string]interface{}{"name": role.Name, "description": role.Description}).Error; err != nil {
+			return fmt.Errorf("failed to update role %s: %w", role.ID, err)
+		}
+		changed = true
+	}
+
+	if !sameIDs(permissionIDs(existing.Permissions), role.Permissions) {
+		if err := tx.Model(&existing).Association("Permissions").Replace(permissions); err != nil {
+			return fmt.Errorf("failed to set permissions of role %s: %w", role.ID, err)
+		}
+		changed = true
+	}
+	result.record(entry, created, changed)
+	return nil
+}
+
+// importBinding creates or updates one user and replaces its roles. Users that have not
+// signed in yet are created, so their roles apply from their first sign-in.
+func importBinding(tx *gorm.DB, binding BindingConfig, result *ImportResult) error {
+	entry := "binding/" + binding.User
+
+	var roles []models.Role
+	if len(binding.Roles) > 0 {
+		if err := tx.Where("id IN ?", binding.Roles).Find(&roles).Error; err != nil {
+			return fmt.Errorf("failed to load roles of user %s: %w", binding.User, err)
+		}
+	}
+	if missing := missingIDs(binding.Roles, roleIDs(roles)); len(missing) > 0 {
+		return fmt.Errorf("%w: user %s: unknown roles %s", ErrInvalidConfig, binding.User, strings.Join(missing, ", "))
+	}
+
+	var user models.User
+	err := tx.Preload("Roles").Where("email = ?", binding.User).First(&user).Error
+	created := errors.Is(err, gorm.ErrRecordNotFound)
+	changed := false
+	if created {
+		user = models.User{ID: binding.User, Email: binding.User, Name: binding.Name, Enabled: true}
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("failed to create user %s: %w", binding.User, err)
+		}
+		// Enabled defaults to true in the database, so a disabled user is stored explicitly
+		if binding.Enabled != nil && !*binding.Enabled {
+			if err := tx.Model(&user).Update("enabled", false).Error; err != nil {
+				return fmt.Errorf("failed to update user %s: %w", binding.User, err)
+			}
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to load user %s: %w", binding.User, err)
+	} else {
+		updates := make(map[string]interface{})
+		if binding.Name != "" && binding.Name != user.Name {
+			updates["name"] = binding.Name
+		}
+		if binding.Enabled != nil && *binding.Enabled != user.Enabled {
+			updates["enabled"] = *binding.Enabled
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&user).Updates(updates).Error; err != nil {
+				return fmt.Errorf("failed to update user %s: %w", binding.User, err)
+			}
+			changed = true
+		}
+	}
+
+	if !sameIDs(roleIDs(user.Roles), binding.Roles) {
+		if err := tx.Model(&user).Association("Roles").Replace(roles); err != nil {
+			return fmt.Errorf("failed to set roles of user %s: %w", binding.User, err)
+		}
+		changed = true
+	}
+	result.record(entry, created, changed)
+	return nil
+}
+
+// permissionIDs returns the sorted IDs of permissions
+func permissionIDs(permissions []models.Permission) []string {
+	ids := make([]string, 0, len(permissions))
+	for _, perm := range permissions {
+		ids = append(ids, perm.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// roleIDs returns the sorted IDs of roles
+func roleIDs(roles []models.Role) []string {
+	ids := make([]string, 0, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// missingIDs returns the IDs of want that are not in have
+func missingIDs(want, have []string) []string {
+	found := make(map[string]bool, len(have))
+	for _, id := range have {
+		found[id] = true
+	}
+	var missing []string
+	for _, id := range want {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// sameIDs reports whether both lists hold the same IDs, ignoring order and repeats
+func sameIDs(a, b []string) bool {
+	return len(missingIDs(a, b)) == 0 && len(missingIDs(b, a)) == 0
+}
@@ -0,0 +1,160 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RoleRef identifies a role in permission reports
+type RoleRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PermissionGrant is a permission a user holds and the roles granting it
+type PermissionGrant struct {
+	ID          string    `json:"id"`
+	Resource    string    `json:"resource"`
+	Action      string    `json:"action"`
+	Description string    `json:"description"`
+	GrantedBy   []RoleRef `json:"granted_by"`
+}
+
+// UserAccess reports the roles and effective permissions of a user. A disabled user's
+// permissions are listed but not effective.
+type UserAccess struct {
+	User        string            `json:"user"`
+	Registered  bool              `json:"registered"`
+	Enabled     bool              `json:"enabled"`
+	Roles       []RoleRef         `json:"roles"`
+	Permissions []PermissionGrant `json:"permissions"`
+}
+
+// Decision explains whether a user holds a permission, the way UserHasPermission decides
+// it. GrantedBy are the user's roles granting the permission; GrantingRoles are all roles
+// that grant it, the roles to ask for when it is denied.
+type Decision struct {
+	User          string    `json:"user,omitempty"`
+	Permission    string    `json:"permission"`
+	Allowed       bool      `json:"allowed"`
+	Reason        string    `json:"reason"`
+	Roles         []RoleRef `json:"roles"`
+	GrantedBy     []RoleRef `json:"granted_by"`
+	GrantingRoles []RoleRef `json:"granting_roles"`
+}
+
+// UserAccess returns the roles and permissions of the user with the given email. Users who
+// have not signed in yet are reported as unregistered, without roles.
+func (m *Manager) UserAccess(email string) (*UserAccess, error) {
+	access := &UserAccess{User: email, Roles: []RoleRef{}, Permissions: []PermissionGrant{}}
+
+	var user models.User
+	err := m.db.Preload("Roles.Permissions").Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return access, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	access.Registered = true
+	access.Enabled = user.Enabled
+	access.Roles = roleRefs(user.Roles)
+
+	grants := make(map[string]*PermissionGrant)
+	for _, role := range sortedRoles(user.Roles) {
+		for _, perm := range role.Permissions {
+			grant, ok := grants[perm.ID]
+			if !ok {
+				grant = &PermissionGrant{ID: perm.ID, Resource: perm.Resource, Action: perm.Action, Description: perm.Description}
+				grants[perm.ID] = grant
+			}
+			grant.GrantedBy = append(grant.GrantedBy, RoleRef{ID: role.ID, Name: role.Name})
+		}
+	}
+	for _, grant := range grants {
+		access.Permissions = append(access.Permissions, *grant)
+	}
+	sort.Slice(access.Permissions, func(i, j int) bool { return access.Permissions[i].ID < access.Permissions[j].ID })
+	return access, nil
+}
+
+// Explain reports whether the user with the given email holds a permission and why. An
+// empty email stands for an anonymous request, which holds no permissions.
+func (m *Manager) Explain(email, permID string) (*Decision, error) {
+	decision := &Decision{User: email, Permission: permID, Roles: []RoleRef{}, GrantedBy: []RoleRef{}, GrantingRoles: []RoleRef{}}
+
+	var perm models.Permission
+	err := m.db.Where("id = ?", permID).First(&perm).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		decision.Reason = fmt.Sprintf("There is no %s permission; see the permission list for the ones that exist", permID)
+		return decision, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permission: %w", err)
+	}
+
+	var granting []models.Role
+	if err := m.db.Joins("JOIN role_permissions ON role_permissions.role_id = roles.id").
+		Where("role_permissions.permission_id = ?", permID).Order("roles.id").Find(&granting).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles granting %s: %w", permID, err)
+	}
+	decision.GrantingRoles = roleRefs(granting)
+
+	if email == "" {
+		decision.Reason = "Anonymous requests hold no permissions; sign in, or enable authentication if it is disabled"
+		return decision, nil
+	}
+
+	var user models.User
+	err = m.db.Preload("Roles.Permissions").Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		decision.Reason = fmt.Sprintf("%s has no user record and therefore no roles", email)
+		return decision, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	decision.Roles = roleRefs(user.Roles)
+
+	for _, role := range sortedRoles(user.Roles) {
+		for _, p := range role.Permissions {
+			if p.ID == permID {
+				decision.GrantedBy = append(decision.GrantedBy, RoleRef{ID: role.ID, Name: role.Name})
+				break
+			}
+		}
+	}
+
+	switch {
+	case !user.Enabled:
+		decision.Reason = fmt.Sprintf("%s is disabled, so none of its roles apply", email)
+	case len(decision.GrantedBy) > 0:
+		decision.Allowed = true
+		decision.Reason = fmt.Sprintf("Granted by the %s role", decision.GrantedBy[0].Name)
+	case len(user.Roles) == 0:
+		decision.Reason = fmt.Sprintf("%s has no roles", email)
+	default:
+		decision.Reason = fmt.Sprintf("None of the roles of %s grant %s", email, permID)
+	}
+	return decision, nil
+}
+
+// sortedRoles returns roles sorted by ID
+func sortedRoles(roles []models.Role) []models.Role {
+	sorted := append([]models.Role(nil), roles...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// roleRefs returns references to roles, sorted by ID
+func roleRefs(roles []models.Role) []RoleRef {
+	refs := make([]RoleRef, 0, len(roles))
+	for _, role := range sortedRoles(roles) {
+		refs = append(refs, RoleRef{ID: role.ID, Name: role.Name})
+	}
+	return refs
+}
@@ -0,0 +1,243 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Role mapping rule types
+const (
+	MappingEmailDomain = "email_domain"
+	MappingGroup       = "group"
+	MappingProvider    = "provider"
+)
+
+// DefaultRoleSetting is the setting holding the role of new users no mapping rule matches;
+// empty gives them no role. Without the setting they get the viewer role.
+const DefaultRoleSetting = "rbac_default_role"
+
+// defaultRole is the role of new users while DefaultRoleSetting is unset
+const defaultRole = "viewer"
+
+var (
+	// ErrInvalidRoleMapping is returned for mapping rules or default roles that cannot be saved
+	ErrInvalidRoleMapping = errors.New("invalid role mapping")
+	// ErrRoleMappingNotFound is returned for mapping rules that do not exist
+	ErrRoleMappingNotFound = errors.New("role mapping rule not found")
+)
+
+// LoginIdentity is who signs in, as their identity provider reports them
+type LoginIdentity struct {
+	Email    string   `json:"email"`
+	Provider string   `json:"provider"`
+	Groups   []string `json:"groups"`
+}
+
+// RoleMappings are the rules giving new users their roles and the role of those matching none
+type RoleMappings struct {
+	DefaultRole string                   `json:"default_role"`
+	Rules       []models.RoleMappingRule `json:"rules"`
+}
+
+// LoginRoles is the outcome of the mapping rules for an identity. Default is set when no
+// rule matched and the roles are the default role's.
+type LoginRoles struct {
+	Roles   []string                 `json:"roles"`
+	Matched []models.RoleMappingRule `json:"matched"`
+	Default bool                     `json:"default"`
+}
+
+// RoleMappings returns the mapping rules, ordered by type and value, and the default role
+func (m *Manager) RoleMappings() (*RoleMappings, error) {
+	mappings := &RoleMappings{Rules: []models.RoleMappingRule{}}
+	if err := m.db.Order("type, value, role_id").Find(&mappings.Rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to load role mappings: %w", err)
+	}
+	role, err := m.DefaultRole()
+	if err != nil {
+		return nil, err
+	}
+	mappings.DefaultRole = role
+	return mappings, nil
+}
+
+// DefaultRole returns the role of new users no mapping rule matches, empty for none
+func (m *Manager) DefaultRole() (string, error) {
+	var setting models.Setting
+	err := m.db.Where("setting_key = ?", DefaultRoleSetting).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultRole, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load default role: %w", err)
+	}
+	return setting.Value, nil
+}
+
+// SetDefaultRole sets the role of new users no mapping rule matches; empty gives them none
+func (m *Manager) SetDefaultRole(roleID string) error {
+	if roleID != "" {
+		if err := m.requireRole(roleID); err != nil {
+			return err
+		}
+	}
+	setting := models.Setting{Key: DefaultRoleSetting, Value: roleID}
+	if err := m.db.Where(models.Setting{Key: DefaultRoleSetting}).Assign(models.Setting{Value: roleID}).FirstOrCreate(&setting).Error; err != nil {
+		return fmt.Errorf("failed to save default role: %w", err)
+	}
+	return nil
+}
+
+// CreateRoleMapping validates and stores a new mapping rule
+func (m *Manager) CreateRoleMapping(rule models.RoleMappingRule) (*models.RoleMappingRule, error) {
+	if err := m.validateRoleMapping(&rule); err != nil {
+		return nil, err
+	}
+	rule.ID = uuid.New().String()
+	if err := m.db.Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create role mapping: %w", err)
+	}
+	return &rule, nil
+}
+
+// UpdateRoleMapping replaces the type, value and role of a mapping rule, returning the rule
+// before and after the update
+func (m *Manager) UpdateRoleMapping(id string, update models.RoleMappingRule) (*models.RoleMappingRule, *models.RoleMappingRule, error) {
+	previous, err := m.roleMapping(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.validateRoleMapping(&update); err != nil {
+		return nil, nil, err
+	}
+	rule := *previous
+	rule.Type, rule.Value, rule.RoleID = update.Type, update.Value, update.RoleID
+	if err := m.db.Save(&rule).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to update role mapping: %w", err)
+	}
+	return previous, &rule, nil
+}
+
+// DeleteRoleMapping deletes a mapping rule, returning it
+func (m *Manager) DeleteRoleMapping(id string) (*models.RoleMappingRule, error) {
+	rule, err := m.roleMapping(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.db.Delete(rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete role mapping: %w", err)
+	}
+	return rule, nil
+}
+
+// EvaluateLogin returns the roles a user signing in for the first time gets: those of every
+// rule the identity matches or, when none matches, the default role. Roles that no longer
+// exist are left out.
+func (m *Manager) EvaluateLogin(identity LoginIdentity) (*LoginRoles, error) {
+	var rules []models.RoleMappingRule
+	if err := m.db.Order("type, value, role_id").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to load role mappings: %w", err)
+	}
+
+	result := &LoginRoles{Roles: []string{}, Matched: []models.RoleMappingRule{}}
+	var wanted []string
+	for _, rule := range rules {
+		if matchesRule(rule, identity) {
+			result.Matched = append(result.Matched, rule)
+			wanted = append(wanted, rule.RoleID)
+		}
+	}
+	if len(result.Matched) == 0 {
+		role, err := m.DefaultRole()
+		if err != nil {
+			return nil, err
+		}
+		result.Default = true
+		if role != "" {
+			wanted = append(wanted, role)
+		}
+	}
+	if len(wanted) == 0 {
+		return result, nil
+	}
+
+	var roles []models.Role
+	if err := m.db.Where("id IN ?", wanted).Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	result.Roles = roleIDs(roles)
+	return result, nil
+}
+
+// matchesRule reports whether an identity matches a mapping rule. Domains, groups and
+// providers compare case-insensitively.
+func matchesRule(rule models.RoleMappingRule, identity LoginIdentity) bool {
+	switch rule.Type {
+	case MappingEmailDomain:
+		at := strings.LastIndex(identity.Email, "@")
+		return at >= 0 && strings.EqualFold(identity.Email[at+1:], rule.Value)
+	case MappingGroup:
+		for _, group := range identity.Groups {
+			if strings.EqualFold(group, rule.Value) {
+				return true
+			}
+		}
+	case MappingProvider:
+		return strings.EqualFold(identity.Provider, rule.Value)
+	}
+	return false
+}
+
+// validateRoleMapping checks a rule's type, value and role, normalizing its value
+func (m *Manager) validateRoleMapping(rule *models.RoleMappingRule) error {
+	rule.Value = strings.TrimSpace(rule.Value)
+	switch rule.Type {
+	case MappingEmailDomain:
+		rule.Value = strings.ToLower(strings.TrimPrefix(rule.Value, "@"))
+		if strings.Contains(rule.Value, "@") {
+			return fmt.Errorf("%w: %s is not an email domain", ErrInvalidRoleMapping, rule.Value)
+		}
+	case MappingProvider:
+		rule.Value = strings.ToLower(rule.Value)
+	case MappingGroup:
+	default:
+		return fmt.Errorf("%w: type must be %s, %s or %s", ErrInvalidRoleMapping, MappingEmailDomain, MappingGroup, MappingProvider)
+	}
+	if rule.Value == "" {
+		return fmt.Errorf("%w: value is required", ErrInvalidRoleMapping)
+	}
+	return m.requireRole(rule.RoleID)
+}
+
+// requireRole returns ErrInvalidRoleMapping unless a role exists
+func (m *Manager) requireRole(roleID string) error {
+	if roleID == "" {
+		return fmt.Errorf("%w: role_id is required", ErrInvalidRoleMapping)
+	}
+	var count int64
+	if err := m.db.Model(&models.Role{}).Where("id = ?", roleID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to load role: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: role %s does not exist", ErrInvalidRoleMapping, roleID)
+	}
+	return nil
+}
+
+// roleMapping loads a mapping rule
+func (m *Manager) roleMapping(id string) (*models.RoleMappingRule, error) {
+	var rule models.RoleMappingRule
+	err := m.db.Where("id = ?", id).First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoleMappingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load role mapping: %w", err)
+	}
+	return &rule, nil
+}
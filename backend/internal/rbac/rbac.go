@@ -2,12 +2,14 @@ package rbac
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/logging"
 	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // ContextKey for storing user info in request context
@@ -38,15 +40,34 @@ func (m *Manager) InitializeDefaultRoles() error {
 		{ID: "cluster.create", Resource: "cluster", Action: "create", Description: "Add new clusters"},
 		{ID: "cluster.update", Resource: "cluster", Action: "update", Description: "Update cluster configuration"},
 		{ID: "cluster.delete", Resource: "cluster", Action: "delete", Description: "Delete clusters"},
+		{ID: "sharing.manage", Resource: "sharing", Action: "manage", Description: "See every cluster and manage who clusters are shared with"},
 		
 		// Resource permissions
 		{ID: "resource.read", Resource: "resource", Action: "read", Description: "View Flux resources"},
 		{ID: "resource.reconcile", Resource: "resource", Action: "reconcile", Description: "Trigger resource reconciliation"},
 		{ID: "resource.suspend", Resource: "resource", Action: "suspend", Description: "Suspend resources"},
 		{ID: "resource.resume", Resource: "resource", Action: "resume", Description: "Resume resources"},
+		{ID: "resource.create", Resource: "resource", Action: "create", Description: "Create Flux resources"},
 		{ID: "resource.update", Resource: "resource", Action: "update", Description: "Update resource configuration"},
 		{ID: "resource.delete", Resource: "resource", Action: "delete", Description: "Delete resources"},
 		
+		// Secret permissions, kept apart from resource.* so Secrets are never exposed or
+		// editable by default
+		{ID: "secret.update", Resource: "secret", Action: "update", Description: "Edit Secret contents"},
+		{ID: "secret.reveal", Resource: "secret", Action: "reveal", Description: "List the keys of Secrets"},
+		
+		// Pod permissions
+		{ID: "pod.exec", Resource: "pod", Action: "exec", Description: "Open a shell in pod containers"},
+		{ID: "pod.portforward", Resource: "pod", Action: "portforward", Description: "Send requests to pod ports through a port-forward"},
+		{ID: "pod.delete", Resource: "pod", Action: "delete", Description: "Delete and evict pods"},
+		
+		// Manifest permissions
+		{ID: "manifest.apply", Resource: "manifest", Action: "apply", Description: "Apply raw manifests to clusters"},
+		
+		// Namespace permissions
+		{ID: "namespace.create", Resource: "namespace", Action: "create", Description: "Create namespaces"},
+		{ID: "namespace.delete", Resource: "namespace", Action: "delete", Description: "Delete namespaces and everything in them"},
+		
 		// Settings permissions
 		{ID: "setting.read", Resource: "setting", Action: "read", Description: "View settings"},
 		{ID: "setting.update", Resource: "setting", Action: "update", Description: "Update settings"},
@@ -66,6 +87,12 @@ func (m *Manager) InitializeDefaultRoles() error {
 		{ID: "azure.create", Resource: "azure", Action: "create", Description: "Add Azure subscriptions"},
 		{ID: "azure.update", Resource: "azure", Action: "update", Description: "Update Azure subscriptions"},
 		{ID: "azure.delete", Resource: "azure", Action: "delete", Description: "Delete Azure subscriptions"},
+		
+		// Registry credential permissions
+		{ID: "registry.read", Resource: "registry", Action: "read", Description: "View container registry credentials"},
+		{ID: "registry.create", Resource: "registry", Action: "create", Description: "Add container registry credentials"},
+		{ID: "registry.update", Resource: "registry", Action: "update", Description: "Update container registry credentials"},
+		{ID: "registry.delete", Resource: "registry", Action: "delete", Description: "Delete container registry credentials"},
 	}
 	
 	// Create permissions
@@ -111,13 +138,22 @@ func (m *Manager) InitializeDefaultRoles() error {
 		}
 	}
 	
-	// Assign permissions to admin role (all permissions)
+	// Assign permissions to admin role (all permissions, including ones added since it was created)
 	var admin models.Role
 	if err := m.db.Preload("Permissions").Where("id = ?", "admin").First(&admin).Error; err == nil {
-		if len(admin.Permissions) == 0 {
-			var allPerms []models.Permission
-			m.db.Find(&allPerms)
-			m.db.Model(&admin).Association("Permissions").Append(allPerms)
+		granted := make(map[string]bool, len(admin.Permissions))
+		for _, perm := range admin.Permissions {
+			granted[perm.ID] = true
+		}
+		var allPerms, missing []models.Permission
+		m.db.Find(&allPerms)
+		for _, perm := range allPerms {
+			if !granted[perm.ID] {
+				missing = append(missing, perm)
+			}
+		}
+		if len(missing) > 0 {
+			m.db.Model(&admin).Association("Permissions").Append(missing)
 		}
 	}
 	
@@ -146,8 +182,9 @@ func (m *Manager) InitializeDefaultRoles() error {
 	return nil
 }
 
-// GetOrCreateUser gets or creates a user from OAuth info
-func (m *Manager) GetOrCreateUser(email, name, provider string) (*models.User, error) {
+// GetOrCreateUser gets or creates a user from OAuth info. New users get the roles the
+// role mapping rules give their email domain, identity provider groups and provider.
+func (m *Manager) GetOrCreateUser(email, name, provider string, groups []string) (*models.User, error) {
 	var user models.User
 	err := m.db.Preload("Roles.Permissions").Where("email = ?", email).First(&user).Error
 	
@@ -165,10 +202,19 @@ func (m *Manager) GetOrCreateUser(email, name, provider string) (*models.User, e
 			return nil, err
 		}
 		
-		// Assign default viewer role to new users
-		var viewerRole models.Role
-		if err := m.db.Where("id = ?", "viewer").First(&viewerRole).Error; err == nil {
-			m.db.Model(&user).Association("Roles").Append(&viewerRole)
+		// Assign the roles the mapping rules give new users
+		login, err := m.EvaluateLogin(LoginIdentity{Email: email, Provider: provider, Groups: groups})
+		if err != nil {
+			logging.GetLogger().Warn("RBAC: Failed to evaluate role mappings", zap.String("user", email), zap.Error(err))
+		} else if len(login.Roles) > 0 {
+			var roles []models.Role
+			if err := m.db.Where("id IN ?", login.Roles).Find(&roles).Error; err == nil {
+				m.db.Model(&user).Association("Roles").Append(roles)
+			}
+			logging.GetLogger().Info("RBAC: Assigned roles to new user",
+				zap.String("user", email),
+				zap.Strings("roles", login.Roles),
+				zap.Bool("default", login.Default))
 		}
 		
 		// Reload with permissions
@@ -178,6 +224,45 @@ func (m *Manager) GetOrCreateUser(email, name, provider string) (*models.User, e
 	return &user, nil
 }
 
+// SyncUserRoles replaces the roles of a user loaded with its roles by the given ones, such as
+// the roles an identity provider's groups map to. Users already holding exactly those roles
+// are left untouched; unknown role IDs are ignored.
+func (m *Manager) SyncUserRoles(user *models.User, roleIDs []string) error {
+	want := make(map[string]bool, len(roleIDs))
+	for _, id := range roleIDs {
+		want[id] = true
+	}
+	current := make(map[string]bool, len(user.Roles))
+	for _, role := range user.Roles {
+		current[role.ID] = true
+	}
+	if len(current) == len(want) {
+		same := true
+		for id := range want {
+			if !current[id] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return nil
+		}
+	}
+	
+	var roles []models.Role
+	if err := m.db.Where("id IN ?", roleIDs).Find(&roles).Error; err != nil {
+		return err
+	}
+	if err := m.db.Model(user).Association("Roles").Replace(roles); err != nil {
+		return err
+	}
+	
+	logging.GetLogger().Info("RBAC: Synced user roles",
+		zap.String("user", user.Email),
+		zap.Strings("roles", roleIDs))
+	return nil
+}
+
 // CheckPermission checks if a user has a specific permission
 func (m *Manager) CheckPermission(user *models.User, resource, action string) bool {
 	if user == nil {
@@ -220,6 +305,44 @@ func (m *Manager) HasAnyPermission(user *models.User, perms ...string) bool {
 	return false
 }
 
+// UserHasPermission reports whether the enabled user with the given email holds a permission
+func (m *Manager) UserHasPermission(email, permID string) bool {
+	var user models.User
+	if err := m.db.Preload("Roles.Permissions").Where("email = ?", email).First(&user).Error; err != nil {
+		return false
+	}
+	return user.Enabled && m.HasAnyPermission(&user, permID)
+}
+
+// UserRoleIDs returns the IDs of the roles of the enabled user with the given email, none
+// for unknown or disabled users
+func (m *Manager) UserRoleIDs(email string) ([]string, error) {
+	var user models.User
+	if err := m.db.Preload("Roles").Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !user.Enabled {
+		return nil, nil
+	}
+	return roleIDs(user.Roles), nil
+}
+
+// UserDisabled reports whether the user with the given email has a user record and it is
+// disabled. Identities without a record, such as JWT callers, are not disabled.
+func (m *Manager) UserDisabled(email string) (bool, error) {
+	var user models.User
+	if err := m.db.Select("enabled").Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !user.Enabled, nil
+}
+
 // Middleware creates RBAC middleware that requires specific permission
 func (m *Manager) Middleware(resource, action string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
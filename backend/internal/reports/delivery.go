@@ -0,0 +1,86 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds the mail server used to deliver reports
+type SMTPConfig struct {
+	Host       string
+	Port       string
+	Username   string
+	Password   string
+	From       string
+	Recipients []string
+}
+
+// SMTPConfigFromEnv reads SMTP settings from the environment.
+// It returns nil when SMTP_HOST or REPORT_RECIPIENTS is not set.
+func SMTPConfigFromEnv() *SMTPConfig {
+	host := os.Getenv("SMTP_HOST")
+	recipients := splitList(os.Getenv("REPORT_RECIPIENTS"))
+	if host == "" || len(recipients) == 0 {
+		return nil
+	}
+
+	cfg := &SMTPConfig{
+		Host:       host,
+		Port:       os.Getenv("SMTP_PORT"),
+		Username:   os.Getenv("SMTP_USERNAME"),
+		Password:   os.Getenv("SMTP_PASSWORD"),
+		From:       os.Getenv("SMTP_FROM"),
+		Recipients: recipients,
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	return cfg
+}
+
+// SendEmail delivers an HTML report to the configured recipients
+func SendEmail(cfg *SMTPConfig, subject, htmlBody string) error {
+	if cfg == nil {
+		return fmt.Errorf("SMTP is not configured")
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.Recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(htmlBody)
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+	if err := smtp.SendMail(addr, auth, cfg.From, cfg.Recipients, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send report email: %w", err)
+	}
+	return nil
+}
+
+// splitList parses a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
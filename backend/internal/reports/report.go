@@ -0,0 +1,204 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+)
+
+// ReportPeriod is the span covered by a weekly report
+const ReportPeriod = 7 * 24 * time.Hour
+
+// recoveryLookback bounds how far before the period a failure may have started
+// and still count towards MTTR when it recovers within the period
+const recoveryLookback = 7 * 24 * time.Hour
+
+// ResourceRef identifies a Flux resource listed in a report
+type ResourceRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Summary holds the aggregated figures for a report section
+type Summary struct {
+	Resources     int           `json:"resources"`
+	Deployments   int           `json:"deployments"`
+	Failures      int           `json:"failures"`
+	FailedActions int           `json:"failed_actions"`
+	Recoveries    int           `json:"recoveries"`
+	MTTR          time.Duration `json:"-"`
+	MTTRSeconds   float64       `json:"mttr_seconds"`
+	Suspended     int           `json:"suspended"`
+}
+
+// ClusterSummary is the report section for a single cluster
+type ClusterSummary struct {
+	ClusterID          string        `json:"cluster_id"`
+	ClusterName        string        `json:"cluster_name"`
+	Summary            Summary       `json:"summary"`
+	SuspendedResources []ResourceRef `json:"suspended_resources"`
+	FailingResources   []ResourceRef `json:"failing_resources"`
+}
+
+// Report is a compiled summary across one or more clusters
+type Report struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	PeriodStart time.Time        `json:"period_start"`
+	PeriodEnd   time.Time        `json:"period_end"`
+	Totals      Summary          `json:"totals"`
+	Clusters    []ClusterSummary `json:"clusters"`
+}
+
+// Build compiles a report for the clusters a scope can read over [start, end), archived
+// ones included. A non-empty clusterIDs slice narrows it to those clusters.
+func Build(db *database.DB, scope repository.Scope, clusterIDs []string, start, end time.Time) (*Report, error) {
+	visible, err := repository.NewClusterRepository(db).List(scope, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load clusters: %w", err)
+	}
+	wanted := make(map[string]bool, len(clusterIDs))
+	for _, id := range clusterIDs {
+		wanted[id] = true
+	}
+	clusters := make([]models.Cluster, 0, len(visible))
+	for _, cluster := range visible {
+		if len(wanted) == 0 || wanted[cluster.ID] {
+			clusters = append(clusters, cluster)
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	report := &Report{
+		GeneratedAt: time.Now(),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Clusters:    make([]ClusterSummary, 0, len(clusters)),
+	}
+
+	var totalRecovery time.Duration
+	for _, cluster := range clusters {
+		section, recovery, err := buildClusterSummary(db, cluster, start, end)
+		if err != nil {
+			return nil, err
+		}
+		report.Clusters = append(report.Clusters, *section)
+
+		report.Totals.Resources += section.Summary.Resources
+		report.Totals.Deployments += section.Summary.Deployments
+		report.Totals.Failures += section.Summary.Failures
+		report.Totals.FailedActions += section.Summary.FailedActions
+		report.Totals.Recoveries += section.Summary.Recoveries
+		report.Totals.Suspended += section.Summary.Suspended
+		totalRecovery += recovery
+	}
+
+	if report.Totals.Recoveries > 0 {
+		report.Totals.MTTR = totalRecovery / time.Duration(report.Totals.Recoveries)
+		report.Totals.MTTRSeconds = report.Totals.MTTR.Seconds()
+	}
+
+	return report, nil
+}
+
+// buildClusterSummary compiles the section for one cluster and returns the
+// summed recovery time so totals can be averaged across clusters
+func buildClusterSummary(db *database.DB, cluster models.Cluster, start, end time.Time) (*ClusterSummary, time.Duration, error) {
+	section := &ClusterSummary{
+		ClusterID:          cluster.ID,
+		ClusterName:        cluster.Name,
+		SuspendedResources: []ResourceRef{},
+		FailingResources:   []ResourceRef{},
+	}
+
+	resources, err := repository.NewResourceRepository(db).ListByCluster(cluster.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	section.Summary.Resources = len(resources)
+	for _, res := range resources {
+		ref := ResourceRef{Kind: res.Kind, Namespace: res.Namespace, Name: res.Name}
+		if isSuspended(res.Metadata) {
+			section.SuspendedResources = append(section.SuspendedResources, ref)
+		}
+		if res.Status == "NotReady" {
+			section.FailingResources = append(section.FailingResources, ref)
+		}
+	}
+	section.Summary.Suspended = len(section.SuspendedResources)
+
+	var transitions []models.ResourceStatusTransition
+	if err := db.Where("cluster_id = ? AND recorded_at >= ? AND recorded_at < ?", cluster.ID, start.Add(-recoveryLookback), end).
+		Order("recorded_at ASC").
+		Find(&transitions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load status transitions for cluster %s: %w", cluster.ID, err)
+	}
+
+	var recovery time.Duration
+	failedSince := make(map[string]time.Time)
+	for _, t := range transitions {
+		inPeriod := !t.RecordedAt.Before(start)
+		switch t.NewStatus {
+		case "NotReady":
+			if _, failing := failedSince[t.ResourceID]; !failing {
+				failedSince[t.ResourceID] = t.RecordedAt
+			}
+			if inPeriod {
+				section.Summary.Failures++
+			}
+		case "Ready":
+			if inPeriod {
+				section.Summary.Deployments++
+			}
+			if since, failing := failedSince[t.ResourceID]; failing {
+				delete(failedSince, t.ResourceID)
+				if inPeriod {
+					section.Summary.Recoveries++
+					recovery += t.RecordedAt.Sub(since)
+				}
+			}
+		}
+	}
+
+	if section.Summary.Recoveries > 0 {
+		section.Summary.MTTR = recovery / time.Duration(section.Summary.Recoveries)
+		section.Summary.MTTRSeconds = section.Summary.MTTR.Seconds()
+	}
+
+	var failedActions int64
+	if err := db.Model(&models.Activity{}).
+		Where("cluster_id = ? AND status = ? AND created_at >= ? AND created_at < ?", cluster.ID, "failed", start, end).
+		Count(&failedActions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count failed actions for cluster %s: %w", cluster.ID, err)
+	}
+	section.Summary.FailedActions = int(failedActions)
+
+	sort.Slice(section.FailingResources, func(i, j int) bool {
+		return section.FailingResources[i].Name < section.FailingResources[j].Name
+	})
+
+	return section, recovery, nil
+}
+
+// isSuspended reports whether the stored object has spec.suspend set
+func isSuspended(metadata string) bool {
+	if metadata == "" {
+		return false
+	}
+	var obj struct {
+		Spec struct {
+			Suspend bool `json:"suspend"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(metadata), &obj); err != nil {
+		return false
+	}
+	return obj.Spec.Suspend
+}
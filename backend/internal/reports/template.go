@@ -0,0 +1,117 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// DefaultSubject is used when no stored template is marked as default
+const DefaultSubject = `Flux weekly report {{date .PeriodStart}} - {{date .PeriodEnd}}`
+
+// DefaultBody is the built-in HTML report template
+const DefaultBody = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Flux weekly report</title></head>
+<body style="font-family: sans-serif; color: #1f2937;">
+<h1>Flux weekly report</h1>
+<p>{{date .PeriodStart}} &ndash; {{date .PeriodEnd}}</p>
+<table cellpadding="6" style="border-collapse: collapse;">
+<tr><th align="left">Deployments</th><td>{{.Totals.Deployments}}</td></tr>
+<tr><th align="left">Failures</th><td>{{.Totals.Failures}}</td></tr>
+<tr><th align="left">Failed actions</th><td>{{.Totals.FailedActions}}</td></tr>
+<tr><th align="left">MTTR</th><td>{{duration .Totals.MTTR}}</td></tr>
+<tr><th align="left">Suspended resources</th><td>{{.Totals.Suspended}}</td></tr>
+</table>
+{{range .Clusters}}
+<h2>{{.ClusterName}}</h2>
+<p>{{.Summary.Resources}} resources &middot; {{.Summary.Deployments}} deployments &middot; {{.Summary.Failures}} failures &middot; MTTR {{duration .Summary.MTTR}}</p>
+{{if .FailingResources}}<h3>Currently failing</h3>
+<ul>{{range .FailingResources}}<li>{{.Kind}} {{.Namespace}}/{{.Name}}</li>{{end}}</ul>{{end}}
+{{if .SuspendedResources}}<h3>Suspended</h3>
+<ul>{{range .SuspendedResources}}<li>{{.Kind}} {{.Namespace}}/{{.Name}}</li>{{end}}</ul>{{end}}
+{{end}}
+<p style="color: #6b7280; font-size: 12px;">Generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</p>
+</body>
+</html>`
+
+// templateFuncs are available to both subject and body templates
+var templateFuncs = map[string]interface{}{
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"duration": func(d time.Duration) string {
+		if d == 0 {
+			return "n/a"
+		}
+		return d.Round(time.Minute).String()
+	},
+}
+
+// ValidateTemplate checks that a subject and body parse
+func ValidateTemplate(subject, body string) error {
+	if _, err := texttemplate.New("subject").Funcs(templateFuncs).Parse(subject); err != nil {
+		return fmt.Errorf("invalid subject template: %w", err)
+	}
+	if _, err := htmltemplate.New("body").Funcs(templateFuncs).Parse(body); err != nil {
+		return fmt.Errorf("invalid body template: %w", err)
+	}
+	return nil
+}
+
+// LoadTemplate returns the stored template with the given ID, the stored
+// default template when id is empty, or the built-in template as a fallback
+func LoadTemplate(db *database.DB, id string) (*models.ReportTemplate, error) {
+	var tmpl models.ReportTemplate
+	if id != "" {
+		if err := db.First(&tmpl, "id = ?", id).Error; err != nil {
+			return nil, fmt.Errorf("failed to load report template %s: %w", id, err)
+		}
+		return &tmpl, nil
+	}
+
+	if err := db.Where("is_default = ?", true).First(&tmpl).Error; err == nil {
+		return &tmpl, nil
+	}
+
+	return &models.ReportTemplate{
+		Name:    "built-in",
+		Subject: DefaultSubject,
+		Body:    DefaultBody,
+	}, nil
+}
+
+// Render executes a template against a report and returns the subject and HTML body
+func Render(tmpl *models.ReportTemplate, report *Report) (string, string, error) {
+	subject := tmpl.Subject
+	if subject == "" {
+		subject = DefaultSubject
+	}
+
+	subjectTmpl, err := texttemplate.New("subject").Funcs(templateFuncs).Parse(subject)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid subject template: %w", err)
+	}
+	bodyTmpl, err := htmltemplate.New("body").Funcs(templateFuncs).Parse(tmpl.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid body template: %w", err)
+	}
+
+	var subjectBuf, bodyBuf bytes.Buffer
+	if err := subjectTmpl.Execute(&subjectBuf, report); err != nil {
+		return "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := bodyTmpl.Execute(&bodyBuf, report); err != nil {
+		return "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+
+	// Header injection guard: subjects end up in an email header
+	cleanSubject := strings.NewReplacer("\r", " ", "\n", " ").Replace(subjectBuf.String())
+	return cleanSubject, bodyBuf.String(), nil
+}
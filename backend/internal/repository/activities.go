@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+)
+
+// ActivityRepository provides access to the audit log
+type ActivityRepository struct {
+	db *database.DB
+}
+
+// NewActivityRepository creates an activity repository
+func NewActivityRepository(db *database.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// List returns the most recent activities on the clusters a scope can read, optionally
+// for a single cluster. Restricted scopes see no activities outside a cluster.
+func (r *ActivityRepository) List(scope Scope, clusterID string, limit int) ([]models.Activity, error) {
+	query := scope.Apply(r.db, r.db.Order("created_at DESC").Limit(limit), "cluster_id")
+	if clusterID != "" {
+		query = query.Where("cluster_id = ?", clusterID)
+	}
+
+	var activities []models.Activity
+	err := query.Find(&activities).Error
+	return activities, wrap(err, "failed to list activities")
+}
+
+// ListBetween returns the activities of a cluster recorded within a time window, newest first
+func (r *ActivityRepository) ListBetween(clusterID string, since, until time.Time, limit int) ([]models.Activity, error) {
+	var activities []models.Activity
+	err := r.db.Where("cluster_id = ? AND created_at >= ? AND created_at < ?", clusterID, since, until).
+		Order("created_at DESC").Limit(limit).Find(&activities).Error
+	return activities, wrap(err, "failed to list activities")
+}
+
+// Get returns a single activity by ID, when it is on a cluster the scope can read
+func (r *ActivityRepository) Get(scope Scope, id string) (*models.Activity, error) {
+	var activity models.Activity
+	if err := scope.Apply(r.db, r.db.Model(&models.Activity{}), "cluster_id").First(&activity, "id = ?", id).Error; err != nil {
+		return nil, wrap(err, "failed to get activity %s", id)
+	}
+	return &activity, nil
+}
+
+// UpdateDetails replaces the details of an activity
+func (r *ActivityRepository) UpdateDetails(id uint, details string) error {
+	err := r.db.Model(&models.Activity{}).Where("id = ?", id).Update("details", details).Error
+	return wrap(err, "failed to update activity %d", id)
+}
+
+// Create records an activity
+func (r *ActivityRepository) Create(activity *models.Activity) error {
+	return wrap(r.db.Create(activity).Error, "failed to record activity")
+}
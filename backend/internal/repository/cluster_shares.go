@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Cluster share access levels, each including the ones before it
+const (
+	AccessRead  = "read"
+	AccessWrite = "write"
+	AccessOwner = "owner"
+)
+
+// Cluster share subject types
+const (
+	ShareUser = "user"
+	ShareRole = "role"
+)
+
+// accessRank orders access levels; unknown levels rank 0, below read
+var accessRank = map[string]int{AccessRead: 1, AccessWrite: 2, AccessOwner: 3}
+
+// ValidAccess reports whether access is a known access level
+func ValidAccess(access string) bool {
+	return accessRank[access] > 0
+}
+
+// HasAccess reports whether granted access includes the wanted access
+func HasAccess(granted, wanted string) bool {
+	return accessRank[granted] > 0 && accessRank[granted] >= accessRank[wanted]
+}
+
+// Scope is who a query runs for. A restricted scope only sees the clusters shared with its
+// user, directly or through one of its roles, and their resources; the zero Scope sees
+// everything.
+type Scope struct {
+	Restricted bool
+	Email      string
+	RoleIDs    []string
+}
+
+// Unrestricted is the scope of queries that see every cluster
+var Unrestricted = Scope{}
+
+// sharedWith returns the shares giving a restricted scope access
+func (s Scope) sharedWith(db *gorm.DB) *gorm.DB {
+	query := db.Model(&models.ClusterShare{})
+	if len(s.RoleIDs) == 0 {
+		return query.Where("subject_type = ? AND subject = ?", ShareUser, strings.ToLower(s.Email))
+	}
+	return query.Where("((subject_type = ? AND subject = ?) OR (subject_type = ? AND subject IN ?))",
+		ShareUser, strings.ToLower(s.Email), ShareRole, s.RoleIDs)
+}
+
+// Apply limits a query on column, a cluster ID column, to the clusters the scope can read
+func (s Scope) Apply(db *database.DB, query *gorm.DB, column string) *gorm.DB {
+	if !s.Restricted {
+		return query
+	}
+	return query.Where(column+" IN (?)", s.sharedWith(db.DB).Select("cluster_id"))
+}
+
+// ClusterShareRepository provides access to the shares of clusters
+type ClusterShareRepository struct {
+	db *database.DB
+}
+
+// NewClusterShareRepository creates a cluster share repository
+func NewClusterShareRepository(db *database.DB) *ClusterShareRepository {
+	return &ClusterShareRepository{db: db}
+}
+
+// List returns the shares of a cluster, users before roles
+func (r *ClusterShareRepository) List(clusterID string) ([]models.ClusterShare, error) {
+	shares := []models.ClusterShare{}
+	err := r.db.Where("cluster_id = ?", clusterID).Order("subject_type DESC, subject").Find(&shares).Error
+	return shares, wrap(err, "failed to list shares of cluster %s", clusterID)
+}
+
+// Put shares a cluster with the share's subject, replacing the access of an existing share
+// with the same subject. It returns the previous access, empty for new shares.
+func (r *ClusterShareRepository) Put(share *models.ClusterShare) (string, error) {
+	if share.SubjectType == ShareUser {
+		share.Subject = strings.ToLower(share.Subject)
+	}
+
+	var existing models.ClusterShare
+	err := r.db.Where("cluster_id = ? AND subject_type = ? AND subject = ?", share.ClusterID, share.SubjectType, share.Subject).First(&existing).Error
+	if IsNotFound(err) {
+		share.ID = uuid.New().String()
+		return "", wrap(r.db.Create(share).Error, "failed to share cluster %s", share.ClusterID)
+	}
+	if err != nil {
+		return "", wrap(err, "failed to load share of cluster %s", share.ClusterID)
+	}
+
+	previous := existing.Access
+	existing.Access = share.Access
+	if err := r.db.Save(&existing).Error; err != nil {
+		return "", wrap(err, "failed to update share of cluster %s", share.ClusterID)
+	}
+	*share = existing
+	return previous, nil
+}
+
+// Delete removes a share of a cluster, returning it
+func (r *ClusterShareRepository) Delete(clusterID, id string) (*models.ClusterShare, error) {
+	var share models.ClusterShare
+	if err := r.db.Where("cluster_id = ? AND id = ?", clusterID, id).First(&share).Error; err != nil {
+		return nil, wrap(err, "failed to get share %s of cluster %s", id, clusterID)
+	}
+	if err := r.db.Delete(&share).Error; err != nil {
+		return nil, wrap(err, "failed to delete share %s of cluster %s", id, clusterID)
+	}
+	return &share, nil
+}
+
+// Access returns the highest access a scope has on a cluster, owner for unrestricted scopes
+// and empty when the cluster is not shared with it
+func (r *ClusterShareRepository) Access(scope Scope, clusterID string) (string, error) {
+	if !scope.Restricted {
+		return AccessOwner, nil
+	}
+	var levels []string
+	if err := scope.sharedWith(r.db.DB).Where("cluster_id = ?", clusterID).Pluck("access", &levels).Error; err != nil {
+		return "", wrap(err, "failed to load access to cluster %s", clusterID)
+	}
+	access := ""
+	for _, level := range levels {
+		if accessRank[level] > accessRank[access] {
+			access = level
+		}
+	}
+	return access, nil
+}
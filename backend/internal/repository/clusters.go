@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// clusterSummaryColumns are returned by list and get calls; the kubeconfig is never selected
+var clusterSummaryColumns = []string{"id", "name", "description", "status", "flux_status", "flux_message", "flux_version", "is_favorite", "archived", "archived_at", "health_check_interval", "client_qps", "client_burst", "client_timeout", "impersonate_users", "server_url", "ca_fingerprint", "version", "created_at", "updated_at"}
+
+// ClusterRepository provides access to stored clusters
+type ClusterRepository struct {
+	db *database.DB
+}
+
+// NewClusterRepository creates a cluster repository
+func NewClusterRepository(db *database.DB) *ClusterRepository {
+	return &ClusterRepository{db: db}
+}
+
+// List returns the clusters a scope can read, newest first, without kubeconfigs.
+// Archived clusters are only included when includeArchived is set.
+func (r *ClusterRepository) List(scope Scope, includeArchived bool) ([]models.Cluster, error) {
+	var clusters []models.Cluster
+	query := scope.Apply(r.db, r.db.Select(clusterSummaryColumns).Order("created_at DESC"), "id")
+	if !includeArchived {
+		query = query.Where("archived = ?", false)
+	}
+	err := query.Find(&clusters).Error
+	return clusters, wrap(err, "failed to list clusters")
+}
+
+// Get returns a cluster without its kubeconfig
+func (r *ClusterRepository) Get(id string) (*models.Cluster, error) {
+	var cluster models.Cluster
+	if err := r.db.Select(clusterSummaryColumns).Where("id = ?", id).First(&cluster).Error; err != nil {
+		return nil, wrap(err, "failed to get cluster %s", id)
+	}
+	return &cluster, nil
+}
+
+// FindByServer returns the first cluster whose kubeconfig points at the given API server URL
+func (r *ClusterRepository) FindByServer(serverURL string) (*models.Cluster, error) {
+	var cluster models.Cluster
+	if err := r.db.Select(clusterSummaryColumns).Where("server_url = ?", serverURL).Order("created_at ASC").First(&cluster).Error; err != nil {
+		return nil, wrap(err, "failed to find cluster for server %s", serverURL)
+	}
+	return &cluster, nil
+}
+
+// GetWithKubeConfig returns a cluster including its encrypted kubeconfig
+func (r *ClusterRepository) GetWithKubeConfig(id string) (*models.Cluster, error) {
+	var cluster models.Cluster
+	if err := r.db.Where("id = ?", id).First(&cluster).Error; err != nil {
+		return nil, wrap(err, "failed to get cluster %s", id)
+	}
+	return &cluster, nil
+}
+
+// Name returns the cluster name, or an empty string when the cluster is unknown
+func (r *ClusterRepository) Name(id string) string {
+	var cluster models.Cluster
+	if err := r.db.Select("name").Where("id = ?", id).First(&cluster).Error; err != nil {
+		return ""
+	}
+	return cluster.Name
+}
+
+// Create stores a new cluster, replacing any soft-deleted cluster with the same ID or name
+func (r *ClusterRepository) Create(cluster *models.Cluster) error {
+	if err := PurgeDeleted(r.db, &models.Cluster{}, "id = ? OR name = ?", cluster.ID, cluster.Name); err != nil {
+		return wrap(err, "failed to purge deleted cluster %s", cluster.ID)
+	}
+	return wrap(r.db.Create(cluster).Error, "failed to create cluster %s", cluster.ID)
+}
+
+// Save persists all fields of an existing cluster
+func (r *ClusterRepository) Save(cluster *models.Cluster) error {
+	return wrap(r.db.Save(cluster).Error, "failed to save cluster %s", cluster.ID)
+}
+
+// Update applies column updates to a cluster
+func (r *ClusterRepository) Update(id string, updates map[string]interface{}) error {
+	result := r.db.Model(&models.Cluster{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return wrap(result.Error, "failed to update cluster %s", id)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.Get(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateVersion applies column updates to a cluster when it is still at the given version.
+// It returns ErrConflict when the cluster was edited since that version was read.
+func (r *ClusterRepository) UpdateVersion(id string, version int, updates map[string]interface{}) error {
+	return wrap(UpdateVersioned(r.db, &models.Cluster{}, id, version, updates), "failed to update cluster %s", id)
+}
+
+// SetArchived archives or restores a cluster, recording when it was archived
+func (r *ClusterRepository) SetArchived(id string, archived bool) error {
+	var archivedAt *time.Time
+	if archived {
+		now := time.Now()
+		archivedAt = &now
+	}
+	return r.Update(id, map[string]interface{}{
+		"archived":    archived,
+		"archived_at": archivedAt,
+		"version":     gorm.Expr("version + 1"),
+	})
+}
+
+// Delete soft-deletes a cluster and removes its cached Flux resources and shares.
+// Status history is kept so past reports still cover the cluster.
+func (r *ClusterRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Cluster{}, "id = ?", id)
+		if result.Error != nil {
+			return wrap(result.Error, "failed to delete cluster %s", id)
+		}
+		if result.RowsAffected == 0 {
+			return wrap(ErrNotFound, "failed to delete cluster %s", id)
+		}
+		if err := tx.Delete(&models.ClusterShare{}, "cluster_id = ?", id).Error; err != nil {
+			return wrap(err, "failed to delete shares of cluster %s", id)
+		}
+		return wrap(tx.Delete(&models.FluxResource{}, "cluster_id = ?", id).Error, "failed to delete resources of cluster %s", id)
+	})
+}
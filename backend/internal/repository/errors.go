@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ErrConflict is returned when a record was modified since the caller read it
+var ErrConflict = errors.New("record was modified concurrently")
+
+// wrap converts GORM errors into repository errors with context.
+// Callers test for missing records with errors.Is(err, ErrNotFound).
+func wrap(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	msg := fmt.Sprintf(format, args...)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%s: %w", msg, ErrNotFound)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// IsNotFound reports whether err indicates a missing record
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// IsConflict reports whether err indicates a concurrent modification
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
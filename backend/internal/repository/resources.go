@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// ResourceRepository provides access to synced Flux resources
+type ResourceRepository struct {
+	db *database.DB
+}
+
+// NewResourceRepository creates a resource repository
+func NewResourceRepository(db *database.DB) *ResourceRepository {
+	return &ResourceRepository{db: db}
+}
+
+// List returns resources across the clusters a scope can read, optionally filtered by kind
+func (r *ResourceRepository) List(scope Scope, kind string) ([]models.FluxResource, error) {
+	query := scope.Apply(r.db, r.db.Model(&models.FluxResource{}), "cluster_id")
+	if kind != "" {
+		query = query.Where("kind = ?", kind).Order("cluster_id, namespace, name")
+	} else {
+		query = query.Order("cluster_id, kind, namespace, name")
+	}
+
+	var resources []models.FluxResource
+	err := query.Find(&resources).Error
+	return resources, wrap(err, "failed to list resources")
+}
+
+// ListByCluster returns the resources of one cluster
+func (r *ResourceRepository) ListByCluster(clusterID string) ([]models.FluxResource, error) {
+	var resources []models.FluxResource
+	err := r.db.Where("cluster_id = ?", clusterID).Order("kind, namespace, name").Find(&resources).Error
+	return resources, wrap(err, "failed to list resources for cluster %s", clusterID)
+}
+
+// Get returns a single resource by ID, when it is in a cluster the scope can read. Legacy
+// "clusterID/kind/namespace/name" IDs issued before resources had surrogate keys are still
+// accepted.
+func (r *ResourceRepository) Get(scope Scope, id string) (*models.FluxResource, error) {
+	var res models.FluxResource
+	err := r.db.Where("id = ?", id).First(&res).Error
+	if IsNotFound(err) {
+		if clusterID, kind, namespace, name, ok := ParseLegacyResourceID(id); ok {
+			legacy, legacyErr := r.GetByKey(clusterID, kind, namespace, name)
+			if legacyErr != nil {
+				return nil, legacyErr
+			}
+			res, err = *legacy, nil
+		}
+	}
+	if err != nil {
+		return nil, wrap(err, "failed to get resource %s", id)
+	}
+	if scope.Restricted {
+		var visible int64
+		if err := scope.Apply(r.db, r.db.Model(&models.FluxResource{}), "cluster_id").Where("id = ?", res.ID).Count(&visible).Error; err != nil {
+			return nil, wrap(err, "failed to get resource %s", id)
+		}
+		if visible == 0 {
+			return nil, wrap(ErrNotFound, "failed to get resource %s", id)
+		}
+	}
+	return &res, nil
+}
+
+// GetByKey returns a resource by its cluster and Kubernetes coordinates
+func (r *ResourceRepository) GetByKey(clusterID, kind, namespace, name string) (*models.FluxResource, error) {
+	var res models.FluxResource
+	if err := r.db.Where("cluster_id = ? AND kind = ? AND namespace = ? AND name = ?", clusterID, kind, namespace, name).First(&res).Error; err != nil {
+		return nil, wrap(err, "failed to get resource %s/%s/%s/%s", clusterID, kind, namespace, name)
+	}
+	return &res, nil
+}
+
+// AssignIDs sets the ID of freshly synced resources: resources already stored keep their
+// ID and new resources get a UUID. It must run before transitions are recorded or the
+// resources are saved.
+func (r *ResourceRepository) AssignIDs(clusterID string, resources []models.FluxResource) error {
+	var stored []models.FluxResource
+	if err := r.db.Select("id", "kind", "namespace", "name").Where("cluster_id = ?", clusterID).Find(&stored).Error; err != nil {
+		return wrap(err, "failed to load resource IDs for cluster %s", clusterID)
+	}
+
+	ids := make(map[string]string, len(stored))
+	for _, res := range stored {
+		ids[resourceKey(res.Kind, res.Namespace, res.Name)] = res.ID
+	}
+	for i := range resources {
+		if id, ok := ids[resourceKey(resources[i].Kind, resources[i].Namespace, resources[i].Name)]; ok {
+			resources[i].ID = id
+		} else {
+			resources[i].ID = uuid.New().String()
+		}
+	}
+	return nil
+}
+
+// AssignID sets the ID of a single resource seen by a watch, keeping the stored ID when
+// the resource is already known. It returns the stored copy, or nil for a new resource.
+func (r *ResourceRepository) AssignID(res *models.FluxResource) (*models.FluxResource, error) {
+	stored, err := r.GetByKey(res.ClusterID, res.Kind, res.Namespace, res.Name)
+	if IsNotFound(err) {
+		res.ID = uuid.New().String()
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.ID = stored.ID
+	return stored, nil
+}
+
+// ParseLegacyResourceID splits a legacy "clusterID/kind/namespace/name" resource ID
+func ParseLegacyResourceID(id string) (clusterID, kind, namespace, name string, ok bool) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 4 {
+		return "", "", "", "", false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return "", "", "", "", false
+		}
+	}
+	return parts[0], parts[1], parts[2], parts[3], true
+}
+
+// resourceKey identifies a resource within a cluster
+func resourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// DeleteByKey removes a stored resource by its cluster and Kubernetes coordinates
+func (r *ResourceRepository) DeleteByKey(clusterID, kind, namespace, name string) error {
+	err := r.db.Where("cluster_id = ? AND kind = ? AND namespace = ? AND name = ?", clusterID, kind, namespace, name).Delete(&models.FluxResource{}).Error
+	return wrap(err, "failed to delete resource %s/%s/%s/%s", clusterID, kind, namespace, name)
+}
+
+// Save upserts a resource
+func (r *ResourceRepository) Save(res *models.FluxResource) error {
+	return wrap(r.db.Save(res).Error, "failed to save resource %s", res.ID)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// UpdateVersioned applies column updates to the record with the given id only when its
+// version column still equals version, incrementing the version. It returns ErrNotFound
+// when the record does not exist and ErrConflict when another writer changed it first.
+func UpdateVersioned(db *database.DB, model interface{}, id string, version int, updates map[string]interface{}) error {
+	values := make(map[string]interface{}, len(updates)+1)
+	for column, value := range updates {
+		values[column] = value
+	}
+	values["version"] = gorm.Expr("version + 1")
+
+	result := db.Model(model).Where("id = ? AND version = ?", id, version).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+	if err := db.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return ErrConflict
+}
+
+// PurgeDeleted permanently removes soft-deleted records matching the query so a new record
+// can reuse their primary key or unique columns
+func PurgeDeleted(db *database.DB, model interface{}, query string, args ...interface{}) error {
+	return db.Unscoped().Where(query, args...).Where("deleted_at IS NOT NULL").Delete(model).Error
+}
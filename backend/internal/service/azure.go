@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/azure"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+)
+
+// azureService is the database and Azure SDK backed AzureService
+type azureService struct {
+	db          *database.DB
+	azureClient *azure.Client
+	k8sClient   k8s.ClusterClient
+	encryptor   *encryption.Encryptor
+}
+
+// NewAzureService creates an AzureService
+func NewAzureService(db *database.DB, azureClient *azure.Client, k8sClient k8s.ClusterClient, encryptor *encryption.Encryptor) AzureService {
+	return &azureService{
+		db:          db,
+		azureClient: azureClient,
+		k8sClient:   k8sClient,
+		encryptor:   encryptor,
+	}
+}
+
+// LoadSubscriptions registers stored subscription credentials with the Azure client
+func (s *azureService) LoadSubscriptions() error {
+	var subscriptions []models.AzureSubscription
+	if err := s.db.Find(&subscriptions).Error; err != nil {
+		return fmt.Errorf("failed to load Azure subscriptions: %w", err)
+	}
+
+	for _, sub := range subscriptions {
+		decrypted, err := s.encryptor.Decrypt(sub.Credentials)
+		if err != nil {
+			log.Printf("Warning: Failed to decrypt credentials for subscription %s: %v", sub.ID, err)
+			continue
+		}
+
+		creds, err := azure.DecodeCredentials(decrypted)
+		if err != nil {
+			log.Printf("Warning: Failed to decode credentials for subscription %s: %v", sub.ID, err)
+			continue
+		}
+
+		s.azureClient.AddCredentials(sub.ID, creds)
+		log.Printf("Loaded Azure subscription: %s", sub.Name)
+	}
+	return nil
+}
+
+func (s *azureService) ListSubscriptions() ([]models.AzureSubscription, error) {
+	var subscriptions []models.AzureSubscription
+	if err := s.db.Find(&subscriptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list Azure subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (s *azureService) GetSubscription(id string) (*models.AzureSubscription, error) {
+	var subscription models.AzureSubscription
+	if err := s.db.First(&subscription, "id = ?", id).Error; err != nil {
+		if repository.IsNotFound(err) {
+			return nil, fmt.Errorf("Azure subscription %s: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get Azure subscription %s: %w", id, err)
+	}
+	return &subscription, nil
+}
+
+func (s *azureService) CreateSubscription(ctx context.Context, input AzureSubscriptionInput) (*models.AzureSubscription, error) {
+	if input.Name == "" || input.SubscriptionID == "" || input.TenantID == "" || input.ClientID == "" || input.ClientSecret == "" {
+		return nil, invalid("Missing required fields")
+	}
+
+	creds := &azure.Credentials{
+		TenantID:       input.TenantID,
+		ClientID:       input.ClientID,
+		ClientSecret:   input.ClientSecret,
+		SubscriptionID: input.SubscriptionID,
+	}
+
+	// Test connection before storing anything
+	s.azureClient.AddCredentials(input.SubscriptionID, creds)
+	if err := s.azureClient.TestConnection(ctx, input.SubscriptionID); err != nil {
+		s.azureClient.RemoveCredentials(input.SubscriptionID)
+		return nil, &Error{Kind: ErrUnauthorized, Message: "Failed to authenticate with Azure", Err: err}
+	}
+
+	encoded, err := azure.EncodeCredentials(creds)
+	if err != nil {
+		s.azureClient.RemoveCredentials(input.SubscriptionID)
+		return nil, fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	encrypted, err := s.encryptor.Encrypt(encoded)
+	if err != nil {
+		s.azureClient.RemoveCredentials(input.SubscriptionID)
+		return nil, fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+
+	subscription := &models.AzureSubscription{
+		ID:          input.SubscriptionID,
+		Name:        input.Name,
+		TenantID:    input.TenantID,
+		Credentials: encrypted,
+		Status:      "healthy",
+	}
+	if err := repository.PurgeDeleted(s.db, &models.AzureSubscription{}, "id = ?", input.SubscriptionID); err != nil {
+		s.azureClient.RemoveCredentials(input.SubscriptionID)
+		return nil, fmt.Errorf("failed to purge deleted Azure subscription: %w", err)
+	}
+	if err := s.db.Create(subscription).Error; err != nil {
+		s.azureClient.RemoveCredentials(input.SubscriptionID)
+		return nil, fmt.Errorf("failed to save Azure subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (s *azureService) DeleteSubscription(id string) error {
+	if err := s.db.Delete(&models.AzureSubscription{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete Azure subscription: %w", err)
+	}
+
+	s.azureClient.RemoveCredentials(id)
+
+	// Delete all associated clusters
+	var clusterIDs []string
+	if err := s.db.Model(&models.Cluster{}).Where("source = ? AND source_id LIKE ?", "azure-aks", fmt.Sprintf("/subscriptions/%s/%%", id)).Pluck("id", &clusterIDs).Error; err != nil {
+		log.Printf("Warning: Failed to find associated clusters: %v", err)
+		return nil
+	}
+	clusters := repository.NewClusterRepository(s.db)
+	for _, clusterID := range clusterIDs {
+		if err := clusters.Delete(clusterID); err != nil {
+			log.Printf("Warning: Failed to delete associated cluster %s: %v", clusterID, err)
+		}
+	}
+	return nil
+}
+
+func (s *azureService) TestConnection(ctx context.Context, id string) error {
+	if err := s.azureClient.TestConnection(ctx, id); err != nil {
+		return &Error{Kind: ErrUnauthorized, Message: "Connection test failed", Err: err}
+	}
+	return nil
+}
+
+func (s *azureService) DiscoverClusters(ctx context.Context, id string) ([]azure.AKSCluster, error) {
+	return s.azureClient.DiscoverClusters(ctx, id)
+}
+
+// SyncClusters imports every AKS cluster in the subscription, creating or updating cluster records
+func (s *azureService) SyncClusters(ctx context.Context, id string) (*AzureSyncResult, error) {
+	aksClusters, err := s.azureClient.DiscoverClusters(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AzureSyncResult{Clusters: []models.Cluster{}}
+	for _, aksCluster := range aksClusters {
+		kubeconfig, err := s.azureClient.GenerateKubeconfig(ctx, aksCluster)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to generate kubeconfig for %s: %v", aksCluster.Name, err))
+			continue
+		}
+
+		encryptedKubeconfig, err := s.encryptor.Encrypt(kubeconfig)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to encrypt kubeconfig for %s: %v", aksCluster.Name, err))
+			continue
+		}
+
+		clusterID := fmt.Sprintf("aks-%s", aksCluster.Name)
+		cluster := models.Cluster{
+			ID:          clusterID,
+			Name:        aksCluster.Name,
+			Description: fmt.Sprintf("AKS cluster in %s (%s nodes, k8s %s)", aksCluster.Location, fmt.Sprint(aksCluster.NodeCount), aksCluster.KubernetesVersion),
+			KubeConfig:  encryptedKubeconfig,
+			Status:      "unknown",
+			Source:      "azure-aks",
+			SourceID:    aksCluster.ID,
+		}
+		if fingerprint, err := k8s.KubeconfigFingerprint(kubeconfig); err == nil {
+			cluster.ServerURL = fingerprint.Server
+			cluster.CAFingerprint = fingerprint.CAFingerprint
+		}
+
+		var existing models.Cluster
+		if err := s.db.First(&existing, "id = ?", clusterID).Error; err == nil {
+			cluster.CreatedAt = existing.CreatedAt
+			cluster.Version = existing.Version + 1
+			cluster.Archived = existing.Archived
+			cluster.ArchivedAt = existing.ArchivedAt
+			cluster.ClientQPS = existing.ClientQPS
+			cluster.ClientBurst = existing.ClientBurst
+			cluster.ClientTimeout = existing.ClientTimeout
+			cluster.ImpersonateUsers = existing.ImpersonateUsers
+			if err := s.db.Save(&cluster).Error; err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("Failed to update cluster %s: %v", aksCluster.Name, err))
+				continue
+			}
+			// Refresh the stored credentials of archived clusters without reconnecting them
+			if cluster.Archived {
+				cluster.KubeConfig = ""
+				result.Clusters = append(result.Clusters, cluster)
+				continue
+			}
+		} else if repository.IsNotFound(err) {
+			clusters := repository.NewClusterRepository(s.db)
+			if cluster.ServerURL != "" {
+				if duplicate, err := clusters.FindByServer(cluster.ServerURL); err == nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("Skipped %s: API server already registered as cluster %s", aksCluster.Name, duplicate.Name))
+					continue
+				}
+			}
+			if err := clusters.Create(&cluster); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("Failed to create cluster %s: %v", aksCluster.Name, err))
+				continue
+			}
+		} else {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to look up cluster %s: %v", aksCluster.Name, err))
+			continue
+		}
+
+		if err := s.k8sClient.SetClientLimits(clusterID, ClusterClientLimits(&cluster)); err != nil {
+			log.Printf("Warning: Ignoring invalid client limits of cluster %s: %v", aksCluster.Name, err)
+		}
+		s.k8sClient.SetImpersonation(clusterID, cluster.ImpersonateUsers)
+		if err := s.k8sClient.AddCluster(clusterID, kubeconfig); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to add cluster %s to k8s client: %v", aksCluster.Name, err))
+			continue
+		}
+
+		status, err := s.k8sClient.CheckClusterHealth(clusterID)
+		if err != nil {
+			log.Printf("Warning: Failed to check health for cluster %s: %v", aksCluster.Name, err)
+			status = "unhealthy"
+		}
+		cluster.Status = status
+		s.db.Model(&models.Cluster{}).Where("id = ?", clusterID).Update("status", status)
+
+		cluster.KubeConfig = ""
+		result.Clusters = append(result.Clusters, cluster)
+	}
+
+	if err := s.db.Model(&models.AzureSubscription{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_synced_at": time.Now(),
+			"cluster_count":  len(result.Clusters),
+		}).Error; err != nil {
+		log.Printf("Warning: Failed to update subscription sync time: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,518 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// clusterService is the database and Kubernetes backed ClusterService
+type clusterService struct {
+	repo      *repository.ClusterRepository
+	shares    *repository.ClusterShareRepository
+	k8sClient k8s.ClusterClient
+	encryptor *encryption.Encryptor
+}
+
+// NewClusterService creates a ClusterService
+func NewClusterService(db *database.DB, k8sClient k8s.ClusterClient, encryptor *encryption.Encryptor) ClusterService {
+	return &clusterService{
+		repo:      repository.NewClusterRepository(db),
+		shares:    repository.NewClusterShareRepository(db),
+		k8sClient: k8sClient,
+		encryptor: encryptor,
+	}
+}
+
+// clusterSource is the k8s.ClusterSource backed by the clusters table
+type clusterSource struct {
+	repo      *repository.ClusterRepository
+	encryptor *encryption.Encryptor
+}
+
+// NewClusterSource creates a k8s.ClusterSource listing the clusters that are registered and
+// not archived, with their decrypted kubeconfigs
+func NewClusterSource(db *database.DB, encryptor *encryption.Encryptor) k8s.ClusterSource {
+	return &clusterSource{repo: repository.NewClusterRepository(db), encryptor: encryptor}
+}
+
+func (s *clusterSource) ActiveClusters() ([]string, error) {
+	clusters, err := s.repo.List(repository.Unrestricted, false)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		ids = append(ids, cluster.ID)
+	}
+	return ids, nil
+}
+
+func (s *clusterSource) Kubeconfig(clusterID string) (string, error) {
+	cluster, err := s.repo.GetWithKubeConfig(clusterID)
+	if repository.IsNotFound(err) {
+		return "", fmt.Errorf("cluster %s: %w", clusterID, k8s.ErrClusterNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	// Archived clusters stay disconnected
+	if cluster.Archived {
+		return "", fmt.Errorf("cluster %s is archived: %w", clusterID, k8s.ErrClusterNotFound)
+	}
+	if cluster.KubeConfig == "" {
+		return "", nil
+	}
+	kubeconfig, err := s.encryptor.Decrypt(cluster.KubeConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt kubeconfig: %w", err)
+	}
+	return kubeconfig, nil
+}
+
+func (s *clusterService) List(scope repository.Scope, includeArchived bool) ([]models.Cluster, error) {
+	return s.repo.List(scope, includeArchived)
+}
+
+func (s *clusterService) Get(id string) (*models.Cluster, error) {
+	return s.repo.Get(id)
+}
+
+func (s *clusterService) Name(id string) string {
+	return s.repo.Name(id)
+}
+
+func (s *clusterService) Create(ctx context.Context, input ClusterInput) (*models.Cluster, error) {
+	if input.Name == "" || input.KubeConfig == "" {
+		return nil, invalid("Name and kubeconfig are required")
+	}
+
+	fingerprint, err := k8s.KubeconfigFingerprint(input.KubeConfig)
+	if err != nil {
+		return nil, &Error{Kind: ErrInvalid, Message: "Invalid kubeconfig", Err: err}
+	}
+
+	if !input.AllowDuplicate {
+		existing, err := s.repo.FindByServer(fingerprint.Server)
+		if err == nil {
+			return nil, &DuplicateClusterError{Existing: existing}
+		}
+		if !repository.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	if err := input.Limits.Validate(); err != nil {
+		return nil, &Error{Kind: ErrInvalid, Message: "Invalid client limits", Err: err}
+	}
+
+	clusterID := uuid.New().String()
+	if err := s.k8sClient.SetClientLimits(clusterID, input.Limits); err != nil {
+		return nil, &Error{Kind: ErrInvalid, Message: "Invalid client limits", Err: err}
+	}
+	s.k8sClient.SetImpersonation(clusterID, input.ImpersonateUsers)
+	if err := s.k8sClient.AddCluster(clusterID, input.KubeConfig); err != nil {
+		s.k8sClient.RemoveCluster(clusterID)
+		return nil, &Error{Kind: ErrUnreachable, Message: "Failed to connect to cluster", Err: err}
+	}
+
+	status, _ := s.k8sClient.CheckClusterHealth(clusterID)
+
+	encryptedKubeconfig, err := s.encryptor.Encrypt(input.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt kubeconfig: %w", err)
+	}
+
+	cluster := &models.Cluster{
+		ID:               clusterID,
+		Name:             input.Name,
+		Description:      input.Description,
+		KubeConfig:       encryptedKubeconfig,
+		Status:           status,
+		ServerURL:        fingerprint.Server,
+		CAFingerprint:    fingerprint.CAFingerprint,
+		ClientQPS:        input.Limits.QPS,
+		ClientBurst:      input.Limits.Burst,
+		ClientTimeout:    int(input.Limits.Timeout / time.Second),
+		ImpersonateUsers: input.ImpersonateUsers,
+	}
+	if err := s.repo.Create(cluster); err != nil {
+		s.k8sClient.RemoveCluster(clusterID)
+		return nil, err
+	}
+	if input.Owner != "" {
+		owner := &models.ClusterShare{ClusterID: clusterID, SubjectType: repository.ShareUser, Subject: input.Owner, Access: repository.AccessOwner, CreatedBy: input.Owner}
+		if _, err := s.shares.Put(owner); err != nil {
+			log.Printf("Warning: Failed to make %s the owner of cluster %s: %v", input.Owner, clusterID, err)
+		}
+	}
+
+	cluster.KubeConfig = ""
+	return cluster, nil
+}
+
+// ClusterClientLimits returns the client limits stored on a cluster
+func ClusterClientLimits(cluster *models.Cluster) k8s.ClientLimits {
+	return k8s.ClientLimits{
+		QPS:     cluster.ClientQPS,
+		Burst:   cluster.ClientBurst,
+		Timeout: time.Duration(cluster.ClientTimeout) * time.Second,
+	}
+}
+
+// ImportContexts returns the contexts of input's kubeconfig it selects, failing when a
+// selected context does not exist so nothing is registered from a mistyped request
+func ImportContexts(input ClusterImport) ([]k8s.KubeconfigContext, error) {
+	if input.KubeConfig == "" {
+		return nil, invalid("Kubeconfig is required")
+	}
+	contexts, err := k8s.KubeconfigContexts(input.KubeConfig)
+	if err != nil {
+		return nil, &Error{Kind: ErrInvalid, Message: "Invalid kubeconfig", Err: err}
+	}
+	if len(contexts) == 0 {
+		return nil, invalid("Kubeconfig has no contexts")
+	}
+	if len(input.Contexts) == 0 {
+		return contexts, nil
+	}
+
+	byName := make(map[string]k8s.KubeconfigContext, len(contexts))
+	for _, context := range contexts {
+		byName[context.Name] = context
+	}
+	selected := make([]k8s.KubeconfigContext, 0, len(input.Contexts))
+	seen := make(map[string]bool, len(input.Contexts))
+	for _, name := range input.Contexts {
+		context, ok := byName[name]
+		if !ok {
+			return nil, invalid(fmt.Sprintf("Context %q not found in kubeconfig", name))
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, context)
+		}
+	}
+	return selected, nil
+}
+
+func (s *clusterService) Import(ctx context.Context, input ClusterImport) ([]ClusterImportResult, error) {
+	contexts, err := ImportContexts(input)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ClusterImportResult, 0, len(contexts))
+	for _, context := range contexts {
+		result := ClusterImportResult{Context: context}
+		kubeconfig, err := k8s.ContextKubeconfig(input.KubeConfig, context.Name)
+		if err != nil {
+			result.Err = &Error{Kind: ErrInvalid, Message: "Invalid context", Err: err}
+			results = append(results, result)
+			continue
+		}
+
+		if input.DryRun {
+			fingerprint, err := k8s.KubeconfigFingerprint(kubeconfig)
+			if err != nil {
+				result.Err = &Error{Kind: ErrInvalid, Message: "Invalid context", Err: err}
+			} else if existing, err := s.repo.FindByServer(fingerprint.Server); err == nil {
+				result.Existing = existing
+			} else if !repository.IsNotFound(err) {
+				return nil, err
+			}
+			results = append(results, result)
+			continue
+		}
+
+		name := input.Names[context.Name]
+		if name == "" {
+			name = context.Name
+		}
+		cluster, err := s.Create(ctx, ClusterInput{
+			Name:           name,
+			Description:    input.Description,
+			KubeConfig:     kubeconfig,
+			Owner:          input.Owner,
+			AllowDuplicate: input.AllowDuplicate,
+		})
+		var duplicate *DuplicateClusterError
+		if errors.As(err, &duplicate) {
+			result.Existing = duplicate.Existing
+		}
+		result.Cluster, result.Err = cluster, err
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *clusterService) Update(ctx context.Context, id string, update ClusterUpdate) ([]string, error) {
+	cluster, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	version := cluster.Version
+	if update.Version != nil {
+		// Fail before reconnecting the cluster when the caller's copy is already stale
+		if *update.Version != cluster.Version {
+			return nil, fmt.Errorf("cluster %s: %w", id, repository.ErrConflict)
+		}
+		version = *update.Version
+	}
+
+	updates := make(map[string]interface{})
+	if update.ClientQPS != nil || update.ClientBurst != nil || update.ClientTimeout != nil {
+		if update.ClientQPS != nil {
+			cluster.ClientQPS = *update.ClientQPS
+		}
+		if update.ClientBurst != nil {
+			cluster.ClientBurst = *update.ClientBurst
+		}
+		if update.ClientTimeout != nil {
+			cluster.ClientTimeout = *update.ClientTimeout
+		}
+		limits := ClusterClientLimits(cluster)
+		if err := limits.Validate(); err != nil {
+			return nil, &Error{Kind: ErrInvalid, Message: "Invalid client limits", Err: err}
+		}
+		// Connected clients are rebuilt with the new limits; archived clusters pick them
+		// up when they are unarchived
+		if !cluster.Archived {
+			if err := s.k8sClient.SetClientLimits(id, limits); err != nil {
+				return nil, &Error{Kind: ErrUnreachable, Message: "Failed to apply client limits", Err: err}
+			}
+		}
+		updates["client_qps"] = cluster.ClientQPS
+		updates["client_burst"] = cluster.ClientBurst
+		updates["client_timeout"] = cluster.ClientTimeout
+	}
+	if update.ImpersonateUsers != nil {
+		if !cluster.Archived {
+			s.k8sClient.SetImpersonation(id, *update.ImpersonateUsers)
+		}
+		updates["impersonate_users"] = *update.ImpersonateUsers
+	}
+	if update.KubeConfig != "" {
+		fingerprint, err := k8s.KubeconfigFingerprint(update.KubeConfig)
+		if err != nil {
+			return nil, &Error{Kind: ErrInvalid, Message: "Invalid kubeconfig", Err: err}
+		}
+		// Guard against pasting another cluster's credentials into this entry
+		if fingerprint.ChangedFrom(cluster.ServerURL, cluster.CAFingerprint) {
+			if !update.ConfirmServerChange {
+				return nil, &Error{Kind: ErrServerChange, Message: fmt.Sprintf(
+					"Kubeconfig points at %s but this cluster is registered with %s; set confirm_server_change to replace it",
+					fingerprint.Server, cluster.ServerURL)}
+			}
+			log.Printf("Warning: Cluster %s API server changed from %s to %s", id, cluster.ServerURL, fingerprint.Server)
+		}
+		updates["server_url"] = fingerprint.Server
+		updates["ca_fingerprint"] = fingerprint.CAFingerprint
+
+		// Archived clusters stay disconnected until they are unarchived
+		if !cluster.Archived {
+			if err := s.k8sClient.AddCluster(id, update.KubeConfig); err != nil {
+				return nil, &Error{Kind: ErrUnreachable, Message: "Failed to connect to cluster", Err: err}
+			}
+		}
+
+		encryptedKubeconfig, err := s.encryptor.Encrypt(update.KubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt kubeconfig: %w", err)
+		}
+		updates["kubeconfig"] = encryptedKubeconfig
+	}
+	if update.Name != "" {
+		updates["name"] = update.Name
+	}
+	if update.Description != "" {
+		updates["description"] = update.Description
+	}
+	if update.HealthCheckInterval != nil {
+		updates["health_check_interval"] = *update.HealthCheckInterval
+	}
+
+	if err := s.repo.UpdateVersion(id, version, updates); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (s *clusterService) Delete(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.k8sClient.RemoveCluster(id)
+	return nil
+}
+
+func (s *clusterService) CheckHealth(ctx context.Context, id string) (*ClusterHealth, error) {
+	cluster, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Archived {
+		return nil, archived(id)
+	}
+
+	status, err := s.k8sClient.CheckClusterHealth(id)
+	if updateErr := s.repo.Update(id, map[string]interface{}{"status": status}); updateErr != nil && !repository.IsNotFound(updateErr) {
+		return nil, updateErr
+	}
+	if err != nil {
+		return nil, &Error{Kind: ErrUnreachable, Message: "Cluster unhealthy", Err: err}
+	}
+
+	health := &ClusterHealth{Status: status}
+
+	// Report Flux installation state separately from cluster connectivity
+	if fluxHealth, err := s.k8sClient.CheckFluxInstallation(ctx, id); err == nil {
+		s.repo.Update(id, map[string]interface{}{
+			"flux_status":  fluxHealth.Status,
+			"flux_message": fluxHealth.Message,
+			"flux_version": fluxHealth.Version,
+		})
+		health.FluxStatus = fluxHealth.Status
+		health.FluxMessage = fluxHealth.Message
+	}
+
+	return health, nil
+}
+
+func (s *clusterService) FluxHealth(ctx context.Context, id string) (*k8s.FluxHealth, error) {
+	cluster, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Archived {
+		return nil, archived(id)
+	}
+
+	health, err := s.k8sClient.CheckFluxInstallation(ctx, id)
+	if err != nil {
+		return nil, &Error{Kind: ErrUnreachable, Message: "Failed to check Flux controllers", Err: err}
+	}
+	if err := s.repo.Update(id, map[string]interface{}{
+		"flux_status":  health.Status,
+		"flux_message": health.Message,
+		"flux_version": health.Version,
+	}); err != nil && !repository.IsNotFound(err) {
+		return nil, err
+	}
+	return health, nil
+}
+
+func (s *clusterService) ToggleFavorite(id string) (*models.Cluster, error) {
+	cluster, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.IsFavorite = !cluster.IsFavorite
+	if err := s.repo.Update(id, map[string]interface{}{"is_favorite": cluster.IsFavorite}); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+func (s *clusterService) Archive(id string) (*models.Cluster, error) {
+	if err := s.repo.SetArchived(id, true); err != nil {
+		return nil, err
+	}
+	s.k8sClient.RemoveCluster(id)
+	return s.repo.Get(id)
+}
+
+func (s *clusterService) Unarchive(id string) (*models.Cluster, error) {
+	cluster, err := s.repo.GetWithKubeConfig(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reconnect with the retained credentials; an unreachable cluster is still restored
+	// and reported as unhealthy by the next health check
+	if err := s.k8sClient.SetClientLimits(id, ClusterClientLimits(cluster)); err != nil {
+		log.Printf("Warning: Ignoring invalid client limits of cluster %s: %v", id, err)
+	}
+	s.k8sClient.SetImpersonation(id, cluster.ImpersonateUsers)
+	if cluster.KubeConfig == "" {
+		err = s.k8sClient.AddInClusterConfig(id)
+	} else {
+		var kubeconfig string
+		if kubeconfig, err = s.encryptor.Decrypt(cluster.KubeConfig); err != nil {
+			return nil, fmt.Errorf("failed to decrypt kubeconfig: %w", err)
+		}
+		err = s.k8sClient.AddCluster(id, kubeconfig)
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to reconnect unarchived cluster %s: %v", id, err)
+	}
+
+	if err := s.repo.SetArchived(id, false); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(id)
+}
+
+func (s *clusterService) Access(scope repository.Scope, id string) (string, error) {
+	if _, err := s.repo.Get(id); err != nil {
+		return "", err
+	}
+	return s.shares.Access(scope, id)
+}
+
+func (s *clusterService) Shares(id string) ([]models.ClusterShare, error) {
+	if _, err := s.repo.Get(id); err != nil {
+		return nil, err
+	}
+	return s.shares.List(id)
+}
+
+func (s *clusterService) Share(id string, share models.ClusterShare) (*models.ClusterShare, string, error) {
+	if _, err := s.repo.Get(id); err != nil {
+		return nil, "", err
+	}
+	if err := ValidateShare(&share); err != nil {
+		return nil, "", err
+	}
+	share.ClusterID = id
+	previous, err := s.shares.Put(&share)
+	if err != nil {
+		return nil, "", err
+	}
+	return &share, previous, nil
+}
+
+func (s *clusterService) Unshare(id, shareID string) (*models.ClusterShare, error) {
+	return s.shares.Delete(id, shareID)
+}
+
+// ValidateShare checks the subject and access of a cluster share, trimming its subject
+func ValidateShare(share *models.ClusterShare) error {
+	share.Subject = strings.TrimSpace(share.Subject)
+	if share.SubjectType != repository.ShareUser && share.SubjectType != repository.ShareRole {
+		return invalid(fmt.Sprintf("subject_type must be %s or %s", repository.ShareUser, repository.ShareRole))
+	}
+	if share.Subject == "" {
+		return invalid("subject is required")
+	}
+	if !repository.ValidAccess(share.Access) {
+		return invalid(fmt.Sprintf("access must be %s, %s or %s", repository.AccessRead, repository.AccessWrite, repository.AccessOwner))
+	}
+	return nil
+}
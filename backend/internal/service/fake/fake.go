@@ -0,0 +1,776 @@
+// Package fake provides in-memory implementations of the service interfaces
+// for exercising API handlers without a database or Kubernetes cluster.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/azure"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/service"
+)
+
+// Call records an action performed against a fake service
+type Call struct {
+	Method    string
+	ClusterID string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// ClusterService is an in-memory service.ClusterService
+type ClusterService struct {
+	mu       sync.Mutex
+	clusters map[string]*models.Cluster
+	shares   []models.ClusterShare
+	nextID   int
+
+	// Health is returned by CheckHealth and Flux by FluxHealth; HealthErr makes both fail
+	Health    service.ClusterHealth
+	Flux      k8s.FluxHealth
+	HealthErr error
+}
+
+// NewClusterService creates a fake cluster service seeded with clusters
+func NewClusterService(clusters ...models.Cluster) *ClusterService {
+	f := &ClusterService{
+		clusters: make(map[string]*models.Cluster),
+		Health:   service.ClusterHealth{Status: "healthy"},
+		Flux:     k8s.FluxHealth{Status: k8s.FluxStatusHealthy, Namespace: "flux-system", CRDsInstalled: true, HasResources: true, Degraded: []string{}},
+	}
+	for i := range clusters {
+		cluster := clusters[i]
+		f.clusters[cluster.ID] = &cluster
+	}
+	return f
+}
+
+func (f *ClusterService) List(scope repository.Scope, includeArchived bool) ([]models.Cluster, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	clusters := make([]models.Cluster, 0, len(f.clusters))
+	for _, cluster := range f.clusters {
+		if cluster.Archived && !includeArchived {
+			continue
+		}
+		if f.access(scope, cluster.ID) == "" {
+			continue
+		}
+		clusters = append(clusters, *cluster)
+	}
+	return clusters, nil
+}
+
+func (f *ClusterService) Get(id string) (*models.Cluster, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cluster, ok := f.clusters[id]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	copied := *cluster
+	return &copied, nil
+}
+
+func (f *ClusterService) Name(id string) string {
+	if cluster, err := f.Get(id); err == nil {
+		return cluster.Name
+	}
+	return ""
+}
+
+func (f *ClusterService) Create(ctx context.Context, input service.ClusterInput) (*models.Cluster, error) {
+	if input.Name == "" || input.KubeConfig == "" {
+		return nil, &service.Error{Kind: service.ErrInvalid, Message: "Name and kubeconfig are required"}
+	}
+	if err := input.Limits.Validate(); err != nil {
+		return nil, &service.Error{Kind: service.ErrInvalid, Message: "Invalid client limits", Err: err}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextID++
+	cluster := &models.Cluster{
+		ID:               fmt.Sprintf("fake-%d", f.nextID),
+		Name:             input.Name,
+		Description:      input.Description,
+		Status:           f.Health.Status,
+		ClientQPS:        input.Limits.QPS,
+		ClientBurst:      input.Limits.Burst,
+		ClientTimeout:    int(input.Limits.Timeout / time.Second),
+		ImpersonateUsers: input.ImpersonateUsers,
+		Version:          1,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+	f.clusters[cluster.ID] = cluster
+	if input.Owner != "" {
+		f.putShare(models.ClusterShare{ClusterID: cluster.ID, SubjectType: repository.ShareUser, Subject: input.Owner, Access: repository.AccessOwner, CreatedBy: input.Owner})
+	}
+	copied := *cluster
+	return &copied, nil
+}
+
+func (f *ClusterService) Import(ctx context.Context, input service.ClusterImport) ([]service.ClusterImportResult, error) {
+	contexts, err := service.ImportContexts(input)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]service.ClusterImportResult, 0, len(contexts))
+	for _, context := range contexts {
+		result := service.ClusterImportResult{Context: context}
+		if !input.DryRun {
+			name := input.Names[context.Name]
+			if name == "" {
+				name = context.Name
+			}
+			result.Cluster, result.Err = f.Create(ctx, service.ClusterInput{Name: name, Description: input.Description, KubeConfig: input.KubeConfig})
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (f *ClusterService) Update(ctx context.Context, id string, update service.ClusterUpdate) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cluster, ok := f.clusters[id]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	if update.Version != nil && *update.Version != cluster.Version {
+		return nil, fmt.Errorf("cluster %s: %w", id, repository.ErrConflict)
+	}
+	cluster.Version++
+
+	var fields []string
+	if update.Name != "" {
+		cluster.Name = update.Name
+		fields = append(fields, "name")
+	}
+	if update.Description != "" {
+		cluster.Description = update.Description
+		fields = append(fields, "description")
+	}
+	if update.KubeConfig != "" {
+		fields = append(fields, "kubeconfig")
+	}
+	if update.HealthCheckInterval != nil {
+		cluster.HealthCheckInterval = *update.HealthCheckInterval
+		fields = append(fields, "health_check_interval")
+	}
+	if update.ClientQPS != nil {
+		cluster.ClientQPS = *update.ClientQPS
+		fields = append(fields, "client_qps")
+	}
+	if update.ClientBurst != nil {
+		cluster.ClientBurst = *update.ClientBurst
+		fields = append(fields, "client_burst")
+	}
+	if update.ClientTimeout != nil {
+		cluster.ClientTimeout = *update.ClientTimeout
+		fields = append(fields, "client_timeout")
+	}
+	if update.ImpersonateUsers != nil {
+		cluster.ImpersonateUsers = *update.ImpersonateUsers
+		fields = append(fields, "impersonate_users")
+	}
+	return fields, nil
+}
+
+func (f *ClusterService) Delete(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.clusters[id]; !ok {
+		return fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	delete(f.clusters, id)
+	kept := f.shares[:0]
+	for _, share := range f.shares {
+		if share.ClusterID != id {
+			kept = append(kept, share)
+		}
+	}
+	f.shares = kept
+	return nil
+}
+
+func (f *ClusterService) Access(scope repository.Scope, id string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.clusters[id]; !ok {
+		return "", fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	return f.access(scope, id), nil
+}
+
+func (f *ClusterService) Shares(id string) ([]models.ClusterShare, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.clusters[id]; !ok {
+		return nil, fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	shares := []models.ClusterShare{}
+	for _, share := range f.shares {
+		if share.ClusterID == id {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+func (f *ClusterService) Share(id string, share models.ClusterShare) (*models.ClusterShare, string, error) {
+	if err := service.ValidateShare(&share); err != nil {
+		return nil, "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.clusters[id]; !ok {
+		return nil, "", fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	share.ClusterID = id
+	stored, previous := f.putShare(share)
+	return &stored, previous, nil
+}
+
+func (f *ClusterService) Unshare(id, shareID string) (*models.ClusterShare, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, share := range f.shares {
+		if share.ClusterID == id && share.ID == shareID {
+			f.shares = append(f.shares[:i], f.shares[i+1:]...)
+			return &share, nil
+		}
+	}
+	return nil, fmt.Errorf("share %s of cluster %s: %w", shareID, id, repository.ErrNotFound)
+}
+
+// putShare stores a share, replacing the access of one with the same subject, and returns
+// it with the previous access. Callers hold f.mu.
+func (f *ClusterService) putShare(share models.ClusterShare) (models.ClusterShare, string) {
+	if share.SubjectType == repository.ShareUser {
+		share.Subject = strings.ToLower(share.Subject)
+	}
+	for i, existing := range f.shares {
+		if existing.ClusterID == share.ClusterID && existing.SubjectType == share.SubjectType && existing.Subject == share.Subject {
+			f.shares[i].Access = share.Access
+			return f.shares[i], existing.Access
+		}
+	}
+	f.nextID++
+	share.ID = fmt.Sprintf("fake-share-%d", f.nextID)
+	share.CreatedAt = time.Now()
+	f.shares = append(f.shares, share)
+	return share, ""
+}
+
+// access returns the highest access a scope has on a cluster. Callers hold f.mu.
+func (f *ClusterService) access(scope repository.Scope, id string) string {
+	if !scope.Restricted {
+		return repository.AccessOwner
+	}
+	access := ""
+	for _, share := range f.shares {
+		if share.ClusterID != id {
+			continue
+		}
+		matches := share.SubjectType == repository.ShareUser && strings.EqualFold(share.Subject, scope.Email)
+		for _, role := range scope.RoleIDs {
+			matches = matches || (share.SubjectType == repository.ShareRole && share.Subject == role)
+		}
+		if matches && !repository.HasAccess(access, share.Access) {
+			access = share.Access
+		}
+	}
+	return access
+}
+
+func (f *ClusterService) CheckHealth(ctx context.Context, id string) (*service.ClusterHealth, error) {
+	cluster, err := f.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Archived {
+		return nil, &service.Error{Kind: service.ErrArchived, Message: fmt.Sprintf("Cluster %s is archived", id)}
+	}
+	if f.HealthErr != nil {
+		return nil, &service.Error{Kind: service.ErrUnreachable, Message: "Cluster unhealthy", Err: f.HealthErr}
+	}
+	health := f.Health
+	return &health, nil
+}
+
+func (f *ClusterService) FluxHealth(ctx context.Context, id string) (*k8s.FluxHealth, error) {
+	cluster, err := f.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Archived {
+		return nil, &service.Error{Kind: service.ErrArchived, Message: fmt.Sprintf("Cluster %s is archived", id)}
+	}
+	if f.HealthErr != nil {
+		return nil, &service.Error{Kind: service.ErrUnreachable, Message: "Failed to check Flux controllers", Err: f.HealthErr}
+	}
+	health := f.Flux
+	return &health, nil
+}
+
+func (f *ClusterService) ToggleFavorite(id string) (*models.Cluster, error) {
+	f.mu.Lock()
+	cluster, ok := f.clusters[id]
+	if ok {
+		cluster.IsFavorite = !cluster.IsFavorite
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	return f.Get(id)
+}
+
+func (f *ClusterService) Archive(id string) (*models.Cluster, error) {
+	return f.setArchived(id, true)
+}
+
+func (f *ClusterService) Unarchive(id string) (*models.Cluster, error) {
+	return f.setArchived(id, false)
+}
+
+func (f *ClusterService) setArchived(id string, archived bool) (*models.Cluster, error) {
+	f.mu.Lock()
+	cluster, ok := f.clusters[id]
+	if ok {
+		cluster.Archived = archived
+		cluster.ArchivedAt = nil
+		if archived {
+			now := time.Now()
+			cluster.ArchivedAt = &now
+		}
+		cluster.Version++
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: %w", id, repository.ErrNotFound)
+	}
+	return f.Get(id)
+}
+
+// ResourceService is an in-memory service.ResourceService that records actions
+type ResourceService struct {
+	mu        sync.Mutex
+	resources map[string]models.FluxResource
+
+	// Clusters decides which clusters restricted scopes see; without it they see all
+	Clusters *ClusterService
+
+	// Calls lists every action performed, in order; ActionErr makes actions fail
+	Calls     []Call
+	ActionErr error
+}
+
+// NewResourceService creates a fake resource service seeded with resources
+func NewResourceService(resources ...models.FluxResource) *ResourceService {
+	f := &ResourceService{resources: make(map[string]models.FluxResource)}
+	for _, res := range resources {
+		f.resources[res.ID] = res
+	}
+	return f
+}
+
+func (f *ResourceService) List(scope repository.Scope, kind string) ([]models.FluxResource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	resources := []models.FluxResource{}
+	for _, res := range f.resources {
+		if (kind == "" || res.Kind == kind) && f.visible(scope, res.ClusterID) {
+			resources = append(resources, res)
+		}
+	}
+	return resources, nil
+}
+
+func (f *ResourceService) ListByCluster(clusterID string) ([]models.FluxResource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	resources := []models.FluxResource{}
+	for _, res := range f.resources {
+		if res.ClusterID == clusterID {
+			resources = append(resources, res)
+		}
+	}
+	return resources, nil
+}
+
+func (f *ResourceService) Get(scope repository.Scope, id string) (*models.FluxResource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	res, ok := f.resources[id]
+	if !ok {
+		clusterID, kind, namespace, name, legacy := repository.ParseLegacyResourceID(id)
+		if !legacy {
+			return nil, fmt.Errorf("resource %s: %w", id, repository.ErrNotFound)
+		}
+		found, err := f.getByKey(clusterID, kind, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		res = *found
+	}
+	if !f.visible(scope, res.ClusterID) {
+		return nil, fmt.Errorf("resource %s: %w", id, repository.ErrNotFound)
+	}
+	return &res, nil
+}
+
+// visible reports whether a scope can read a cluster's resources
+func (f *ResourceService) visible(scope repository.Scope, clusterID string) bool {
+	if !scope.Restricted || f.Clusters == nil {
+		return true
+	}
+	access, err := f.Clusters.Access(scope, clusterID)
+	return err == nil && access != ""
+}
+
+func (f *ResourceService) GetByKey(clusterID, kind, namespace, name string) (*models.FluxResource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.getByKey(clusterID, kind, namespace, name)
+}
+
+func (f *ResourceService) getByKey(clusterID, kind, namespace, name string) (*models.FluxResource, error) {
+	for _, res := range f.resources {
+		if res.ClusterID == clusterID && res.Kind == kind && res.Namespace == namespace && res.Name == name {
+			return &res, nil
+		}
+	}
+	return nil, fmt.Errorf("resource %s/%s/%s/%s: %w", clusterID, kind, namespace, name, repository.ErrNotFound)
+}
+
+func (f *ResourceService) Sync(clusterID string) (int, error) {
+	f.record(Call{Method: "Sync", ClusterID: clusterID})
+	if f.ActionErr != nil {
+		return 0, f.ActionErr
+	}
+	resources, _ := f.ListByCluster(clusterID)
+	return len(resources), nil
+}
+
+func (f *ResourceService) Reconcile(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return f.action("Reconcile", clusterID, kind, namespace, name)
+}
+
+func (f *ResourceService) ReconcileWithSource(ctx context.Context, clusterID, kind, namespace, name string) (*k8s.SourceReconcileResult, error) {
+	if err := f.action("ReconcileWithSource", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	return &k8s.SourceReconcileResult{Kind: "GitRepository", Namespace: namespace, Name: name}, nil
+}
+
+func (f *ResourceService) WaitForReconcile(ctx context.Context, clusterID, kind, namespace, name string, progress func(k8s.ReconcileProgress)) (*k8s.ReconcileProgress, error) {
+	if err := f.action("WaitForReconcile", clusterID, kind, namespace, name); err != nil {
+		return nil, err
+	}
+	result := k8s.ReconcileProgress{Phase: "ready", Handled: true, Conditions: []k8s.ReconcileCondition{{Type: "Ready", Status: "True"}}}
+	progress(result)
+	return &result, nil
+}
+
+func (f *ResourceService) Suspend(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return f.action("Suspend", clusterID, kind, namespace, name)
+}
+
+func (f *ResourceService) Resume(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return f.action("Resume", clusterID, kind, namespace, name)
+}
+
+func (f *ResourceService) Update(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error {
+	return f.action("Update", clusterID, kind, namespace, name)
+}
+
+func (f *ResourceService) Patch(ctx context.Context, clusterID, kind, namespace, name string, patch k8s.ResourcePatch) error {
+	return f.action("Patch", clusterID, kind, namespace, name)
+}
+
+func (f *ResourceService) Create(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	metadata, _ := manifest["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	return f.action("Create", clusterID, kind, namespace, name)
+}
+
+func (f *ResourceService) Delete(ctx context.Context, clusterID, kind, namespace, name string) error {
+	if err := f.action("Delete", clusterID, kind, namespace, name); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, res := range f.resources {
+		if res.ClusterID == clusterID && res.Kind == kind && res.Namespace == namespace && res.Name == name {
+			delete(f.resources, id)
+		}
+	}
+	return nil
+}
+
+func (f *ResourceService) action(method, clusterID, kind, namespace, name string) error {
+	f.record(Call{Method: method, ClusterID: clusterID, Kind: kind, Namespace: namespace, Name: name})
+	return f.ActionErr
+}
+
+func (f *ResourceService) record(call Call) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Calls = append(f.Calls, call)
+}
+
+// AzureService is an in-memory service.AzureService
+type AzureService struct {
+	mu            sync.Mutex
+	subscriptions map[string]*models.AzureSubscription
+
+	// Clusters is returned by discovery; ConnectionErr makes connection tests fail
+	Clusters      []azure.AKSCluster
+	ConnectionErr error
+}
+
+// NewAzureService creates a fake Azure service seeded with subscriptions
+func NewAzureService(subscriptions ...models.AzureSubscription) *AzureService {
+	f := &AzureService{subscriptions: make(map[string]*models.AzureSubscription)}
+	for i := range subscriptions {
+		sub := subscriptions[i]
+		f.subscriptions[sub.ID] = &sub
+	}
+	return f
+}
+
+func (f *AzureService) LoadSubscriptions() error {
+	return nil
+}
+
+func (f *AzureService) ListSubscriptions() ([]models.AzureSubscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	subscriptions := make([]models.AzureSubscription, 0, len(f.subscriptions))
+	for _, sub := range f.subscriptions {
+		subscriptions = append(subscriptions, *sub)
+	}
+	return subscriptions, nil
+}
+
+func (f *AzureService) GetSubscription(id string) (*models.AzureSubscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub, ok := f.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("Azure subscription %s: %w", id, repository.ErrNotFound)
+	}
+	copied := *sub
+	return &copied, nil
+}
+
+func (f *AzureService) CreateSubscription(ctx context.Context, input service.AzureSubscriptionInput) (*models.AzureSubscription, error) {
+	if input.Name == "" || input.SubscriptionID == "" || input.TenantID == "" || input.ClientID == "" || input.ClientSecret == "" {
+		return nil, &service.Error{Kind: service.ErrInvalid, Message: "Missing required fields"}
+	}
+	if f.ConnectionErr != nil {
+		return nil, &service.Error{Kind: service.ErrUnauthorized, Message: "Failed to authenticate with Azure", Err: f.ConnectionErr}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub := &models.AzureSubscription{
+		ID:       input.SubscriptionID,
+		Name:     input.Name,
+		TenantID: input.TenantID,
+		Status:   "healthy",
+	}
+	f.subscriptions[sub.ID] = sub
+	copied := *sub
+	return &copied, nil
+}
+
+func (f *AzureService) DeleteSubscription(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.subscriptions, id)
+	return nil
+}
+
+func (f *AzureService) TestConnection(ctx context.Context, id string) error {
+	if f.ConnectionErr != nil {
+		return &service.Error{Kind: service.ErrUnauthorized, Message: "Connection test failed", Err: f.ConnectionErr}
+	}
+	return nil
+}
+
+func (f *AzureService) DiscoverClusters(ctx context.Context, id string) ([]azure.AKSCluster, error) {
+	return f.Clusters, nil
+}
+
+func (f *AzureService) SyncClusters(ctx context.Context, id string) (*service.AzureSyncResult, error) {
+	result := &service.AzureSyncResult{Clusters: []models.Cluster{}}
+	for _, aks := range f.Clusters {
+		result.Clusters = append(result.Clusters, models.Cluster{
+			ID:       fmt.Sprintf("aks-%s", aks.Name),
+			Name:     aks.Name,
+			Status:   "healthy",
+			Source:   "azure-aks",
+			SourceID: aks.ID,
+		})
+	}
+	return result, nil
+}
+
+// RegistryService is an in-memory service.RegistryService
+type RegistryService struct {
+	mu          sync.Mutex
+	credentials map[string]*models.RegistryCredential
+
+	// LoginErr makes logins and connection tests fail
+	LoginErr error
+}
+
+// NewRegistryService creates a fake registry service seeded with credentials
+func NewRegistryService(credentials ...models.RegistryCredential) *RegistryService {
+	f := &RegistryService{credentials: make(map[string]*models.RegistryCredential)}
+	for i := range credentials {
+		credential := credentials[i]
+		f.credentials[credential.ID] = &credential
+	}
+	return f
+}
+
+func (f *RegistryService) LoadCredentials() error {
+	return nil
+}
+
+func (f *RegistryService) List() ([]models.RegistryCredential, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	credentials := make([]models.RegistryCredential, 0, len(f.credentials))
+	for _, credential := range f.credentials {
+		credentials = append(credentials, *credential)
+	}
+	sort.Slice(credentials, func(i, j int) bool { return credentials[i].Registry < credentials[j].Registry })
+	return credentials, nil
+}
+
+func (f *RegistryService) Get(id string) (*models.RegistryCredential, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	credential, ok := f.credentials[id]
+	if !ok {
+		return nil, fmt.Errorf("registry credential %s: %w", id, repository.ErrNotFound)
+	}
+	copied := *credential
+	return &copied, nil
+}
+
+func (f *RegistryService) Create(ctx context.Context, input service.RegistryCredentialInput) (*models.RegistryCredential, error) {
+	if input.Name == "" || input.Provider == "" || input.Registry == "" || input.Username == "" || input.Password == "" {
+		return nil, &service.Error{Kind: service.ErrInvalid, Message: "Missing required fields"}
+	}
+	if f.LoginErr != nil {
+		return nil, &service.Error{Kind: service.ErrUnauthorized, Message: fmt.Sprintf("Failed to log in to %s", input.Registry), Err: f.LoginErr}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	credential := &models.RegistryCredential{
+		ID:       fmt.Sprintf("registry-%d", len(f.credentials)+1),
+		Name:     input.Name,
+		Provider: input.Provider,
+		Registry: input.Registry,
+		Username: input.Username,
+		Status:   "healthy",
+		Version:  1,
+	}
+	f.credentials[credential.ID] = credential
+	copied := *credential
+	return &copied, nil
+}
+
+func (f *RegistryService) Update(ctx context.Context, id string, update service.RegistryCredentialUpdate) (*models.RegistryCredential, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	credential, ok := f.credentials[id]
+	if !ok {
+		return nil, fmt.Errorf("registry credential %s: %w", id, repository.ErrNotFound)
+	}
+	if update.Version != nil && *update.Version != credential.Version {
+		return nil, repository.ErrConflict
+	}
+	if (update.Registry != "" || update.Username != "" || update.Password != "") && f.LoginErr != nil {
+		return nil, &service.Error{Kind: service.ErrUnauthorized, Message: "Failed to log in", Err: f.LoginErr}
+	}
+	if update.Name != "" {
+		credential.Name = update.Name
+	}
+	if update.Registry != "" {
+		credential.Registry = update.Registry
+	}
+	if update.Username != "" {
+		credential.Username = update.Username
+	}
+	credential.Version++
+	copied := *credential
+	return &copied, nil
+}
+
+func (f *RegistryService) Delete(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.credentials[id]; !ok {
+		return fmt.Errorf("registry credential %s: %w", id, repository.ErrNotFound)
+	}
+	delete(f.credentials, id)
+	return nil
+}
+
+func (f *RegistryService) Test(ctx context.Context, id string) error {
+	if _, err := f.Get(id); err != nil {
+		return err
+	}
+	if f.LoginErr != nil {
+		return &service.Error{Kind: service.ErrUnauthorized, Message: "Connection test failed", Err: f.LoginErr}
+	}
+	return nil
+}
+
+// Compile-time interface checks
+var (
+	_ service.ClusterService  = (*ClusterService)(nil)
+	_ service.ResourceService = (*ResourceService)(nil)
+	_ service.AzureService    = (*AzureService)(nil)
+	_ service.RegistryService = (*RegistryService)(nil)
+)
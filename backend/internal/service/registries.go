@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/encryption"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Registry providers, which set the expected registry host and default username
+const (
+	RegistryACR       = "acr"
+	RegistryECR       = "ecr"
+	RegistryGCR       = "gcr"
+	RegistryDockerHub = "dockerhub"
+	RegistryGeneric   = "generic"
+)
+
+var (
+	acrHost = regexp.MustCompile(`^[a-z0-9]+\.azurecr\.(io|cn|us)$`)
+	ecrHost = regexp.MustCompile(`^[0-9]{12}\.dkr\.ecr\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+	gcrHost = regexp.MustCompile(`^([a-z]+\.)?gcr\.io$|^[a-z0-9-]+-docker\.pkg\.dev$`)
+)
+
+// registryService is the database backed RegistryService. Credentials are kept encrypted in
+// the database and registered in plain text with the Kubernetes client, which uses them for
+// image reports.
+type registryService struct {
+	db        *database.DB
+	k8sClient k8s.ClusterClient
+	encryptor *encryption.Encryptor
+}
+
+// NewRegistryService creates a RegistryService
+func NewRegistryService(db *database.DB, k8sClient k8s.ClusterClient, encryptor *encryption.Encryptor) RegistryService {
+	return &registryService{
+		db:        db,
+		k8sClient: k8sClient,
+		encryptor: encryptor,
+	}
+}
+
+// LoadCredentials registers stored registry credentials with the Kubernetes client
+func (s *registryService) LoadCredentials() error {
+	var credentials []models.RegistryCredential
+	if err := s.db.Find(&credentials).Error; err != nil {
+		return fmt.Errorf("failed to load registry credentials: %w", err)
+	}
+
+	for i := range credentials {
+		credential, err := s.decrypt(&credentials[i])
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		s.k8sClient.SetRegistryCredential(credential)
+		log.Printf("Loaded registry credential: %s", credentials[i].Name)
+	}
+	return nil
+}
+
+func (s *registryService) List() ([]models.RegistryCredential, error) {
+	var credentials []models.RegistryCredential
+	if err := s.db.Order("registry").Find(&credentials).Error; err != nil {
+		return nil, fmt.Errorf("failed to list registry credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+func (s *registryService) Get(id string) (*models.RegistryCredential, error) {
+	var credential models.RegistryCredential
+	if err := s.db.First(&credential, "id = ?", id).Error; err != nil {
+		if repository.IsNotFound(err) {
+			return nil, fmt.Errorf("registry credential %s: %w", id, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get registry credential %s: %w", id, err)
+	}
+	return &credential, nil
+}
+
+// Create tests a registry login and stores it when the registry accepts it
+func (s *registryService) Create(ctx context.Context, input RegistryCredentialInput) (*models.RegistryCredential, error) {
+	input.Registry = k8s.RegistryHost(strings.TrimSpace(input.Registry))
+	input.Username = strings.TrimSpace(input.Username)
+	if err := normalizeRegistryInput(&input); err != nil {
+		return nil, err
+	}
+	if input.Name == "" || input.Registry == "" || input.Username == "" || input.Password == "" {
+		return nil, invalid("Missing required fields")
+	}
+	if err := s.checkUnique(input.Registry, ""); err != nil {
+		return nil, err
+	}
+
+	login := k8s.RegistryCredential{Name: input.Name, Registry: input.Registry, Username: input.Username, Password: input.Password}
+	// Test the login before storing anything
+	if err := s.k8sClient.TestRegistryCredential(ctx, login); err != nil {
+		return nil, &Error{Kind: ErrUnauthorized, Message: fmt.Sprintf("Failed to log in to %s", input.Registry), Err: err}
+	}
+
+	encrypted, err := s.encryptor.Encrypt(input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+
+	credential := &models.RegistryCredential{
+		ID:           uuid.New().String(),
+		Name:         input.Name,
+		Provider:     input.Provider,
+		Registry:     input.Registry,
+		Username:     input.Username,
+		Credentials:  encrypted,
+		Status:       "healthy",
+		LastTestedAt: time.Now(),
+	}
+	if err := repository.PurgeDeleted(s.db, &models.RegistryCredential{}, "registry = ?", input.Registry); err != nil {
+		return nil, fmt.Errorf("failed to purge deleted registry credential: %w", err)
+	}
+	if err := s.db.Create(credential).Error; err != nil {
+		return nil, fmt.Errorf("failed to save registry credential: %w", err)
+	}
+
+	s.k8sClient.SetRegistryCredential(login)
+	return credential, nil
+}
+
+// Update changes a stored credential. A changed registry, username or password is tested
+// before it is saved.
+func (s *registryService) Update(ctx context.Context, id string, update RegistryCredentialUpdate) (*models.RegistryCredential, error) {
+	existing, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	version := existing.Version
+	if update.Version != nil {
+		version = *update.Version
+	}
+
+	login, err := s.decrypt(existing)
+	if err != nil {
+		return nil, err
+	}
+	input := RegistryCredentialInput{
+		Name:     existing.Name,
+		Provider: existing.Provider,
+		Registry: existing.Registry,
+		Username: existing.Username,
+		Password: login.Password,
+	}
+	if update.Name != "" {
+		input.Name = update.Name
+	}
+	if update.Registry != "" {
+		input.Registry = k8s.RegistryHost(strings.TrimSpace(update.Registry))
+	}
+	if update.Username != "" {
+		input.Username = strings.TrimSpace(update.Username)
+	}
+	if update.Password != "" {
+		input.Password = update.Password
+	}
+	if err := normalizeRegistryInput(&input); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"name": input.Name}
+	loginChanged := input.Registry != existing.Registry || input.Username != existing.Username || update.Password != ""
+	if loginChanged {
+		if input.Registry != existing.Registry {
+			if err := s.checkUnique(input.Registry, id); err != nil {
+				return nil, err
+			}
+		}
+		login = k8s.RegistryCredential{Name: input.Name, Registry: input.Registry, Username: input.Username, Password: input.Password}
+		if err := s.k8sClient.TestRegistryCredential(ctx, login); err != nil {
+			return nil, &Error{Kind: ErrUnauthorized, Message: fmt.Sprintf("Failed to log in to %s", input.Registry), Err: err}
+		}
+		encrypted, err := s.encryptor.Encrypt(input.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt credentials: %w", err)
+		}
+		updates["registry"] = input.Registry
+		updates["username"] = input.Username
+		updates["credentials"] = encrypted
+		updates["status"] = "healthy"
+		updates["status_message"] = ""
+		updates["last_tested_at"] = time.Now()
+	}
+
+	if err := repository.UpdateVersioned(s.db, &models.RegistryCredential{}, id, version, updates); err != nil {
+		return nil, err
+	}
+
+	if input.Registry != existing.Registry {
+		s.k8sClient.RemoveRegistryCredential(existing.Registry)
+	}
+	login.Name = input.Name
+	s.k8sClient.SetRegistryCredential(login)
+	return s.Get(id)
+}
+
+func (s *registryService) Delete(id string) error {
+	credential, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Delete(&models.RegistryCredential{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete registry credential: %w", err)
+	}
+	s.k8sClient.RemoveRegistryCredential(credential.Registry)
+	return nil
+}
+
+// Test logs in to the registry with a stored credential and records the outcome on it
+func (s *registryService) Test(ctx context.Context, id string) error {
+	credential, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	login, err := s.decrypt(credential)
+	if err != nil {
+		return err
+	}
+
+	testErr := s.k8sClient.TestRegistryCredential(ctx, login)
+	status := map[string]interface{}{"status": "healthy", "status_message": "", "last_tested_at": time.Now()}
+	if testErr != nil {
+		status["status"], status["status_message"] = "unhealthy", testErr.Error()
+	}
+	if err := s.db.Model(&models.RegistryCredential{}).Where("id = ?", id).Updates(status).Error; err != nil {
+		log.Printf("Warning: Failed to update registry credential status: %v", err)
+	}
+
+	if testErr != nil {
+		return &Error{Kind: ErrUnauthorized, Message: "Connection test failed", Err: testErr}
+	}
+	return nil
+}
+
+// decrypt returns the login of a stored credential
+func (s *registryService) decrypt(credential *models.RegistryCredential) (k8s.RegistryCredential, error) {
+	password, err := s.encryptor.Decrypt(credential.Credentials)
+	if err != nil {
+		return k8s.RegistryCredential{}, fmt.Errorf("failed to decrypt registry credential %s: %w", credential.ID, err)
+	}
+	return k8s.RegistryCredential{
+		Name:     credential.Name,
+		Registry: credential.Registry,
+		Username: credential.Username,
+		Password: password,
+	}, nil
+}
+
+// checkUnique refuses a second credential for a registry, since reports use one per registry
+func (s *registryService) checkUnique(registry, exceptID string) error {
+	query := s.db.Model(&models.RegistryCredential{}).Where("registry = ?", registry)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check registry credentials: %w", err)
+	}
+	if count > 0 {
+		return invalid(fmt.Sprintf("A credential for registry %s already exists", registry))
+	}
+	return nil
+}
+
+// normalizeRegistryInput fills in the registry and username a provider always uses and
+// checks the registry host belongs to the provider
+func normalizeRegistryInput(input *RegistryCredentialInput) error {
+	switch input.Provider {
+	case RegistryACR:
+		// Service principal application ID, admin user or repository-scoped token name
+		if input.Registry != "" && !acrHost.MatchString(input.Registry) {
+			return invalid("ACR registries are named <registry>.azurecr.io")
+		}
+	case RegistryECR:
+		// The password is a token from aws ecr get-login-password, valid for 12 hours
+		if input.Username == "" {
+			input.Username = "AWS"
+		}
+		if input.Registry != "" && !ecrHost.MatchString(input.Registry) {
+			return invalid("ECR registries are named <account>.dkr.ecr.<region>.amazonaws.com")
+		}
+	case RegistryGCR:
+		// The password is a service account JSON key
+		if input.Username == "" {
+			input.Username = "_json_key"
+		}
+		if input.Registry != "" && !gcrHost.MatchString(input.Registry) {
+			return invalid("GCR registries are gcr.io, <region>.gcr.io or <region>-docker.pkg.dev")
+		}
+	case RegistryDockerHub:
+		if input.Registry == "" {
+			input.Registry = "docker.io"
+		}
+		if input.Registry != "docker.io" {
+			return invalid("Docker Hub credentials are for docker.io")
+		}
+	case RegistryGeneric:
+	default:
+		return invalid("Provider must be one of acr, ecr, gcr, dockerhub or generic")
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/history"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+)
+
+// resourceService is the database and Kubernetes backed ResourceService
+type resourceService struct {
+	db        *database.DB
+	repo      *repository.ResourceRepository
+	k8sClient k8s.ClusterClient
+}
+
+// NewResourceService creates a ResourceService
+func NewResourceService(db *database.DB, k8sClient k8s.ClusterClient) ResourceService {
+	return &resourceService{
+		db:        db,
+		repo:      repository.NewResourceRepository(db),
+		k8sClient: k8sClient,
+	}
+}
+
+func (s *resourceService) List(scope repository.Scope, kind string) ([]models.FluxResource, error) {
+	return s.repo.List(scope, kind)
+}
+
+func (s *resourceService) ListByCluster(clusterID string) ([]models.FluxResource, error) {
+	return s.repo.ListByCluster(clusterID)
+}
+
+func (s *resourceService) Get(scope repository.Scope, id string) (*models.FluxResource, error) {
+	return s.repo.Get(scope, id)
+}
+
+func (s *resourceService) GetByKey(clusterID, kind, namespace, name string) (*models.FluxResource, error) {
+	return s.repo.GetByKey(clusterID, kind, namespace, name)
+}
+
+func (s *resourceService) Sync(clusterID string) (int, error) {
+	cluster, err := repository.NewClusterRepository(s.db).Get(clusterID)
+	if err != nil {
+		return 0, err
+	}
+	if cluster.Archived {
+		return 0, archived(clusterID)
+	}
+
+	resources, err := s.k8sClient.GetFluxResources(clusterID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.repo.AssignIDs(clusterID, resources); err != nil {
+		return 0, err
+	}
+
+	if err := history.RecordTransitions(s.db, clusterID, resources); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := history.RecordDeployments(s.db, clusterID, resources); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	for _, res := range resources {
+		if err := s.repo.Save(&res); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if err := history.RecordClusterSnapshot(s.db, clusterID, resources); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	return len(resources), nil
+}
+
+func (s *resourceService) Reconcile(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return s.k8sClient.ReconcileResource(ctx, clusterID, kind, namespace, name)
+}
+
+func (s *resourceService) ReconcileWithSource(ctx context.Context, clusterID, kind, namespace, name string) (*k8s.SourceReconcileResult, error) {
+	return s.k8sClient.ReconcileWithSource(ctx, clusterID, kind, namespace, name)
+}
+
+func (s *resourceService) WaitForReconcile(ctx context.Context, clusterID, kind, namespace, name string, progress func(k8s.ReconcileProgress)) (*k8s.ReconcileProgress, error) {
+	return s.k8sClient.WaitForReconcile(ctx, clusterID, kind, namespace, name, progress)
+}
+
+func (s *resourceService) Suspend(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return s.k8sClient.SuspendResource(ctx, clusterID, kind, namespace, name)
+}
+
+func (s *resourceService) Resume(ctx context.Context, clusterID, kind, namespace, name string) error {
+	return s.k8sClient.ResumeResource(ctx, clusterID, kind, namespace, name)
+}
+
+func (s *resourceService) Update(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error {
+	return s.k8sClient.UpdateFluxResource(ctx, clusterID, kind, namespace, name, patch, force)
+}
+
+func (s *resourceService) Patch(ctx context.Context, clusterID, kind, namespace, name string, patch k8s.ResourcePatch) error {
+	return s.k8sClient.PatchFluxResource(ctx, clusterID, kind, namespace, name, patch)
+}
+
+func (s *resourceService) Create(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error {
+	switch {
+	case k8s.IsNotificationKind(kind):
+		return s.k8sClient.CreateNotificationResource(ctx, clusterID, kind, namespace, manifest)
+	case k8s.IsCreatableFluxKind(kind):
+		return s.k8sClient.CreateFluxResource(ctx, clusterID, kind, namespace, manifest)
+	}
+	return invalid(fmt.Sprintf("Only Alerts, Providers, Receivers, Kustomizations, HelmReleases, GitRepositories and HelmRepositories can be created, not %s", kind))
+}
+
+// Delete removes the resource from the cluster and drops its stored copy, since syncs
+// only upsert the resources they find
+func (s *resourceService) Delete(ctx context.Context, clusterID, kind, namespace, name string) error {
+	if !k8s.IsNotificationKind(kind) {
+		return invalid(fmt.Sprintf("Only Alerts, Providers and Receivers can be deleted, not %s", kind))
+	}
+	if err := s.k8sClient.DeleteNotificationResource(ctx, clusterID, kind, namespace, name); err != nil {
+		return err
+	}
+	return s.repo.DeleteByKey(clusterID, kind, namespace, name)
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/azure"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/k8s"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/repository"
+)
+
+// Error kinds returned by services; handlers map them to HTTP status codes
+var (
+	ErrInvalid      = errors.New("invalid request")
+	ErrUnreachable  = errors.New("cluster unreachable")
+	ErrUnauthorized = errors.New("authentication failed")
+	ErrServerChange = errors.New("kubeconfig points at a different server")
+	ErrDuplicate    = errors.New("cluster already registered")
+	ErrArchived     = errors.New("cluster is archived")
+)
+
+// Error is a service error carrying a message that is safe to show to API clients
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Is matches the error kind so callers can use errors.Is(err, ErrInvalid)
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateClusterError is returned when a new cluster's API server is already registered
+type DuplicateClusterError struct {
+	Existing *models.Cluster
+}
+
+func (e *DuplicateClusterError) Error() string {
+	return fmt.Sprintf("API server %s is already registered as cluster %q", e.Existing.ServerURL, e.Existing.Name)
+}
+
+// Is matches ErrDuplicate so callers can use errors.Is(err, ErrDuplicate)
+func (e *DuplicateClusterError) Is(target error) bool {
+	return target == ErrDuplicate
+}
+
+// invalid returns an ErrInvalid service error
+func invalid(message string) error {
+	return &Error{Kind: ErrInvalid, Message: message}
+}
+
+// archived returns an ErrArchived service error for the given cluster
+func archived(id string) error {
+	return &Error{Kind: ErrArchived, Message: fmt.Sprintf("Cluster %s is archived; unarchive it to resume syncing", id)}
+}
+
+// ClusterInput holds the fields for registering a cluster.
+// AllowDuplicate registers the cluster even when its API server is already registered.
+// Limits are the rate limits and request timeout of the cluster's clients; zero values
+// keep the defaults. ImpersonateUsers performs operations users trigger as those users.
+// Owner, an email, is given owner access to the cluster when set.
+type ClusterInput struct {
+	Name             string
+	Description      string
+	KubeConfig       string
+	Limits           k8s.ClientLimits
+	ImpersonateUsers bool
+	Owner            string
+	AllowDuplicate   bool
+}
+
+// ClusterImport holds a kubeconfig whose contexts are registered as separate clusters.
+// Contexts selects the contexts to register, all of them when empty, and Names maps a
+// context to the name of its cluster, the context name by default. DryRun only reports
+// the selected contexts and which of them are already registered. Owner is given owner
+// access to every registered cluster, as with ClusterInput.
+type ClusterImport struct {
+	KubeConfig     string
+	Contexts       []string
+	Names          map[string]string
+	Description    string
+	Owner          string
+	AllowDuplicate bool
+	DryRun         bool
+}
+
+// ClusterImportResult reports the outcome for one imported context: the registered
+// Cluster, or Err with Existing set when its API server was already registered
+type ClusterImportResult struct {
+	Context  k8s.KubeconfigContext
+	Cluster  *models.Cluster
+	Existing *models.Cluster
+	Err      error
+}
+
+// ClusterUpdate holds optional cluster fields to change; empty values are left untouched.
+// Version is the cluster version the caller last read; when nil the current version is used.
+// A kubeconfig for a different API server than the stored one is rejected unless
+// ConfirmServerChange is set.
+type ClusterUpdate struct {
+	Name                string
+	Description         string
+	KubeConfig          string
+	HealthCheckInterval *int
+	ClientQPS           *float32
+	ClientBurst         *int
+	ClientTimeout       *int
+	ImpersonateUsers    *bool
+	Version             *int
+	ConfirmServerChange bool
+}
+
+// ClusterHealth reports cluster connectivity and Flux installation state
+type ClusterHealth struct {
+	Status      string
+	FluxStatus  string
+	FluxMessage string
+}
+
+// ClusterService manages registered clusters.
+// Archived clusters keep their credentials and history but are not synced or health checked.
+type ClusterService interface {
+	// List returns the clusters a scope can read
+	List(scope repository.Scope, includeArchived bool) ([]models.Cluster, error)
+	Get(id string) (*models.Cluster, error)
+	Name(id string) string
+	Create(ctx context.Context, input ClusterInput) (*models.Cluster, error)
+	// Import registers contexts of a multi-context kubeconfig as clusters. A context that
+	// fails does not stop the others; its error is reported in its result.
+	Import(ctx context.Context, input ClusterImport) ([]ClusterImportResult, error)
+	Update(ctx context.Context, id string, update ClusterUpdate) ([]string, error)
+	Delete(id string) error
+	CheckHealth(ctx context.Context, id string) (*ClusterHealth, error)
+	// FluxHealth inspects the Flux controllers of a cluster and records the resulting
+	// Flux status on it
+	FluxHealth(ctx context.Context, id string) (*k8s.FluxHealth, error)
+	ToggleFavorite(id string) (*models.Cluster, error)
+	Archive(id string) (*models.Cluster, error)
+	Unarchive(id string) (*models.Cluster, error)
+	// Access returns the highest access a scope has on a cluster, empty when the cluster is
+	// not shared with it
+	Access(scope repository.Scope, id string) (string, error)
+	Shares(id string) ([]models.ClusterShare, error)
+	// Share gives the share's subject access to a cluster, replacing the access of an
+	// existing share with the same subject, and returns the previous access
+	Share(id string, share models.ClusterShare) (*models.ClusterShare, string, error)
+	Unshare(id, shareID string) (*models.ClusterShare, error)
+}
+
+// ResourceService manages Flux resources stored from and acted on in clusters
+type ResourceService interface {
+	// List and Get only see resources of clusters the scope can read
+	List(scope repository.Scope, kind string) ([]models.FluxResource, error)
+	ListByCluster(clusterID string) ([]models.FluxResource, error)
+	Get(scope repository.Scope, id string) (*models.FluxResource, error)
+	GetByKey(clusterID, kind, namespace, name string) (*models.FluxResource, error)
+	Sync(clusterID string) (int, error)
+	Reconcile(ctx context.Context, clusterID, kind, namespace, name string) error
+	// ReconcileWithSource reconciles a Kustomization's or HelmRelease's source first and
+	// waits for it, like flux reconcile --with-source
+	ReconcileWithSource(ctx context.Context, clusterID, kind, namespace, name string) (*k8s.SourceReconcileResult, error)
+	// WaitForReconcile waits until a requested reconcile has been handled and the resource
+	// is Ready or has failed, reporting each change of state to progress
+	WaitForReconcile(ctx context.Context, clusterID, kind, namespace, name string, progress func(k8s.ReconcileProgress)) (*k8s.ReconcileProgress, error)
+	Suspend(ctx context.Context, clusterID, kind, namespace, name string) error
+	Resume(ctx context.Context, clusterID, kind, namespace, name string) error
+	// Update server-side applies spec fields; force takes over fields another manager owns
+	Update(ctx context.Context, clusterID, kind, namespace, name string, patch map[string]interface{}, force bool) error
+	// Patch applies a JSON patch, JSON merge patch or strategic merge patch to a Flux resource
+	Patch(ctx context.Context, clusterID, kind, namespace, name string, patch k8s.ResourcePatch) error
+	// Create adds notification-controller resources (Alert, Provider, Receiver) and the
+	// Kustomizations, HelmReleases, GitRepositories and HelmRepositories apps are onboarded
+	// with. Delete removes notification-controller resources.
+	Create(ctx context.Context, clusterID, kind, namespace string, manifest map[string]interface{}) error
+	Delete(ctx context.Context, clusterID, kind, namespace, name string) error
+}
+
+// AzureSubscriptionInput holds the fields for registering an Azure subscription
+type AzureSubscriptionInput struct {
+	Name           string
+	SubscriptionID string
+	TenantID       string
+	ClientID       string
+	ClientSecret   string
+}
+
+// AzureSyncResult reports the outcome of importing AKS clusters
+type AzureSyncResult struct {
+	Clusters []models.Cluster
+	Errors   []string
+}
+
+// AzureService manages Azure subscriptions and AKS cluster discovery
+type AzureService interface {
+	LoadSubscriptions() error
+	ListSubscriptions() ([]models.AzureSubscription, error)
+	GetSubscription(id string) (*models.AzureSubscription, error)
+	CreateSubscription(ctx context.Context, input AzureSubscriptionInput) (*models.AzureSubscription, error)
+	DeleteSubscription(id string) error
+	TestConnection(ctx context.Context, id string) error
+	DiscoverClusters(ctx context.Context, id string) ([]azure.AKSCluster, error)
+	SyncClusters(ctx context.Context, id string) (*AzureSyncResult, error)
+}
+
+// RegistryCredentialInput holds the fields for storing a container registry credential.
+// Registry and Username may be left empty where the provider implies them.
+type RegistryCredentialInput struct {
+	Name     string
+	Provider string
+	Registry string
+	Username string
+	Password string
+}
+
+// RegistryCredentialUpdate holds optional registry credential fields to change; empty values
+// are left untouched. Version is the credential version the caller last read; when nil the
+// current version is used.
+type RegistryCredentialUpdate struct {
+	Name     string
+	Registry string
+	Username string
+	Password string
+	Version  *int
+}
+
+// RegistryService manages the container registry credentials image reports log in with
+type RegistryService interface {
+	LoadCredentials() error
+	List() ([]models.RegistryCredential, error)
+	Get(id string) (*models.RegistryCredential, error)
+	Create(ctx context.Context, input RegistryCredentialInput) (*models.RegistryCredential, error)
+	Update(ctx context.Context, id string, update RegistryCredentialUpdate) (*models.RegistryCredential, error)
+	Delete(id string) error
+	Test(ctx context.Context, id string) error
+}
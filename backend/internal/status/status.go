@@ -0,0 +1,115 @@
+package status
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the health of a single subsystem
+type State string
+
+const (
+	StateOK       State = "ok"
+	StateDegraded State = "degraded"
+	StateDown     State = "down"
+	StateDisabled State = "disabled"
+)
+
+// severity orders states so the overall status is the worst component
+var severity = map[State]int{
+	StateDisabled: 0,
+	StateOK:       1,
+	StateDegraded: 2,
+	StateDown:     3,
+}
+
+// Component is the reported state of one subsystem
+type Component struct {
+	Name    string                 `json:"name"`
+	Status  State                  `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report is the combined status of all subsystems
+type Report struct {
+	Status     State       `json:"status"`
+	CheckedAt  time.Time   `json:"checked_at"`
+	Components []Component `json:"components"`
+}
+
+// NewReport combines components into a report whose status is the worst component state.
+// Disabled components do not affect the overall status.
+func NewReport(components []Component) Report {
+	overall := StateOK
+	for _, c := range components {
+		if severity[c.Status] > severity[overall] {
+			overall = c.Status
+		}
+	}
+	return Report{
+		Status:     overall,
+		CheckedAt:  time.Now(),
+		Components: components,
+	}
+}
+
+// SyncTracker records the outcome of the periodic sync worker
+type SyncTracker struct {
+	mu        sync.RWMutex
+	interval  time.Duration
+	lastStart time.Time
+	lastEnd   time.Time
+	lastError string
+	clusters  int
+	failures  int
+}
+
+// SyncRun is a snapshot of the sync worker's most recent cycle
+type SyncRun struct {
+	Interval  time.Duration
+	LastStart time.Time
+	LastEnd   time.Time
+	LastError string
+	Clusters  int
+	Failures  int
+}
+
+// SyncWorker tracks the process-wide sync worker
+var SyncWorker = &SyncTracker{}
+
+// SetInterval records the configured sync interval
+func (t *SyncTracker) SetInterval(interval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interval = interval
+}
+
+// RecordRun records a completed sync cycle. err is set when the cycle could not run at all;
+// failures counts clusters that failed to sync within an otherwise completed cycle.
+func (t *SyncTracker) RecordRun(start time.Time, clusters, failures int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastStart = start
+	t.lastEnd = time.Now()
+	t.clusters = clusters
+	t.failures = failures
+	t.lastError = ""
+	if err != nil {
+		t.lastError = err.Error()
+	}
+}
+
+// Snapshot returns the most recent sync cycle
+func (t *SyncTracker) Snapshot() SyncRun {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return SyncRun{
+		Interval:  t.interval,
+		LastStart: t.lastStart,
+		LastEnd:   t.lastEnd,
+		LastError: t.lastError,
+		Clusters:  t.clusters,
+		Failures:  t.failures,
+	}
+}
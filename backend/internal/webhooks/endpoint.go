@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Endpoint is a webhook destination and the payload schema it expects
+type Endpoint struct {
+	URL           string     `json:"url"`
+	SchemaVersion int        `json:"schema_version,omitempty"`
+	TLS           *TLSConfig `json:"tls,omitempty"`
+
+	tlsConfig *tls.Config
+}
+
+// ParseEndpoints builds the endpoint list from WEBHOOK_URLS and WEBHOOK_CONFIG.
+// URLs from urlsStr use defaultVersion; configStr is a JSON array of endpoints
+// and entries without a schema_version also use defaultVersion.
+func ParseEndpoints(urlsStr, configStr string, defaultVersion int) ([]Endpoint, error) {
+	if !ValidSchemaVersion(defaultVersion) {
+		return nil, fmt.Errorf("unsupported webhook schema version %d", defaultVersion)
+	}
+
+	var endpoints []Endpoint
+	for _, url := range ParseWebhookURLs(urlsStr) {
+		endpoints = append(endpoints, Endpoint{URL: url, SchemaVersion: defaultVersion})
+	}
+
+	if strings.TrimSpace(configStr) == "" {
+		return endpoints, nil
+	}
+
+	var configured []Endpoint
+	if err := json.Unmarshal([]byte(configStr), &configured); err != nil {
+		return nil, fmt.Errorf("invalid webhook config: %w", err)
+	}
+	for i, endpoint := range configured {
+		endpoint.URL = strings.TrimSpace(endpoint.URL)
+		if endpoint.URL == "" {
+			return nil, fmt.Errorf("webhook config entry %d has no url", i)
+		}
+		if endpoint.SchemaVersion == 0 {
+			endpoint.SchemaVersion = defaultVersion
+		}
+		if !ValidSchemaVersion(endpoint.SchemaVersion) {
+			return nil, fmt.Errorf("webhook %s: unsupported schema version %d", endpoint.URL, endpoint.SchemaVersion)
+		}
+		if endpoint.TLS != nil {
+			tlsConfig, err := endpoint.TLS.build()
+			if err != nil {
+				return nil, fmt.Errorf("webhook %s: %w", endpoint.URL, err)
+			}
+			endpoint.tlsConfig = tlsConfig
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
@@ -3,12 +3,15 @@ package webhooks
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -22,10 +25,14 @@ const (
 	EventResourceFailed       EventType = "resource.failed"
 	EventSyncCompleted        EventType = "sync.completed"
 	EventSyncFailed           EventType = "sync.failed"
+	EventFluxStatusChanged    EventType = "flux.status.changed"
+	EventReportGenerated      EventType = "report.generated"
 )
 
-// Event represents a webhook event
+// Event represents a webhook event. It is encoded per endpoint using the
+// endpoint's payload schema version (see EncodePayload).
 type Event struct {
+	ID        string                 `json:"id,omitempty"`
 	Type      EventType              `json:"type"`
 	Timestamp time.Time              `json:"timestamp"`
 	ClusterID string                 `json:"cluster_id,omitempty"`
@@ -36,27 +43,50 @@ type Event struct {
 
 // Notifier sends webhook notifications
 type Notifier struct {
-	webhookURLs []string
-	client      *http.Client
-	logger      *zap.Logger
-	enabled     bool
+	endpoints []Endpoint
+	client    *http.Client
+	clients   map[string]*http.Client // endpoint URL -> client with custom TLS
+	logger    *zap.Logger
+	enabled   bool
+	store     *Store       // recent events for replay; nil keeps none
+	pending   atomic.Int64 // deliveries in flight
+	failures  atomic.Int64 // deliveries that errored or returned non-2xx
 }
 
 // NewNotifier creates a new webhook notifier
-func NewNotifier(webhookURLs []string, logger *zap.Logger) *Notifier {
+func NewNotifier(endpoints []Endpoint, logger *zap.Logger) *Notifier {
+	clients := make(map[string]*http.Client)
+	for _, endpoint := range endpoints {
+		if endpoint.tlsConfig != nil {
+			clients[endpoint.URL] = newEndpointClient(endpoint.tlsConfig, 10*time.Second)
+		}
+	}
+
 	return &Notifier{
-		webhookURLs: webhookURLs,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		logger:  logger,
-		enabled: len(webhookURLs) > 0,
+		endpoints: endpoints,
+		client:    httpclient.New(10 * time.Second),
+		clients:   clients,
+		logger:    logger,
+		enabled:   len(endpoints) > 0,
 	}
 }
 
-// Notify sends a webhook notification
+// SetStore records every event in store, whether or not webhook endpoints are configured
+func (n *Notifier) SetStore(store *Store) {
+	n.store = store
+}
+
+// Store returns the event store, or nil when events are not recorded
+func (n *Notifier) Store() *Store {
+	if n == nil {
+		return nil
+	}
+	return n.store
+}
+
+// Notify records the event and sends a webhook notification
 func (n *Notifier) Notify(event Event) {
-	if !n.enabled {
+	if !n.enabled && n.store == nil {
 		return
 	}
 
@@ -65,21 +95,43 @@ func (n *Notifier) Notify(event Event) {
 		event.Timestamp = time.Now()
 	}
 
-	// Marshal event to JSON
-	payload, err := json.Marshal(event)
-	if err != nil {
-		n.logger.Error("Failed to marshal webhook event", zap.Error(err))
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+
+	if n.store != nil {
+		n.store.Add(event)
+	}
+	if !n.enabled {
 		return
 	}
 
-	// Send to all configured webhook URLs
-	for _, url := range n.webhookURLs {
-		go n.sendWebhook(url, payload, event)
+	// Encode once per schema version and send to all configured endpoints
+	payloads := make(map[int][]byte)
+	for _, endpoint := range n.endpoints {
+		payload, ok := payloads[endpoint.SchemaVersion]
+		if !ok {
+			var err error
+			payload, err = EncodePayload(event, endpoint.SchemaVersion)
+			if err != nil {
+				n.logger.Error("Failed to marshal webhook event",
+					zap.Int("schema_version", endpoint.SchemaVersion),
+					zap.Error(err),
+				)
+				continue
+			}
+			payloads[endpoint.SchemaVersion] = payload
+		}
+		n.pending.Add(1)
+		go n.sendWebhook(endpoint, payload, event)
 	}
 }
 
-// sendWebhook sends the webhook to a single URL
-func (n *Notifier) sendWebhook(url string, payload []byte, event Event) {
+// sendWebhook sends the webhook to a single endpoint
+func (n *Notifier) sendWebhook(endpoint Endpoint, payload []byte, event Event) {
+	url := endpoint.URL
+	defer n.pending.Add(-1)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -89,20 +141,28 @@ func (n *Notifier) sendWebhook(url string, payload []byte, event Event) {
 			zap.String("url", url),
 			zap.Error(err),
 		)
+		n.failures.Add(1)
 		return
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "FluxOrchestrator/1.0")
 	req.Header.Set("X-Flux-Event-Type", string(event.Type))
+	req.Header.Set("X-Flux-Schema-Version", strconv.Itoa(endpoint.SchemaVersion))
+
+	client := n.client
+	if endpointClient, ok := n.clients[url]; ok {
+		client = endpointClient
+	}
 
-	resp, err := n.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		n.logger.Error("Failed to send webhook",
 			zap.String("url", url),
 			zap.String("event_type", string(event.Type)),
 			zap.Error(err),
 		)
+		n.failures.Add(1)
 		return
 	}
 	defer resp.Body.Close()
@@ -119,6 +179,7 @@ func (n *Notifier) sendWebhook(url string, payload []byte, event Event) {
 			zap.String("event_type", string(event.Type)),
 			zap.Int("status_code", resp.StatusCode),
 		)
+		n.failures.Add(1)
 	}
 }
 
@@ -193,6 +254,67 @@ func (n *Notifier) NotifySyncFailed(clusterID, message string) {
 	})
 }
 
+// NotifyFluxStatusChanged notifies when the Flux installation state of a cluster changes
+func (n *Notifier) NotifyFluxStatusChanged(clusterID, oldStatus, newStatus, message string) {
+	if oldStatus == newStatus {
+		return
+	}
+
+	severity := "info"
+	switch newStatus {
+	case "not_installed", "degraded":
+		severity = "error"
+	case "empty":
+		severity = "warning"
+	}
+
+	n.Notify(Event{
+		Type:      EventFluxStatusChanged,
+		ClusterID: clusterID,
+		Message:   fmt.Sprintf("Flux status changed from %s to %s: %s", oldStatus, newStatus, message),
+		Severity:  severity,
+	})
+}
+
+// NotifyReportGenerated posts a compiled report summary
+func (n *Notifier) NotifyReportGenerated(subject string, report interface{}) {
+	n.Notify(Event{
+		Type:     EventReportGenerated,
+		Resource: map[string]interface{}{"report": report},
+		Message:  subject,
+		Severity: "info",
+	})
+}
+
+// Enabled reports whether any webhook endpoints are configured
+func (n *Notifier) Enabled() bool {
+	return n != nil && n.enabled
+}
+
+// EndpointCount returns the number of configured webhook endpoints
+func (n *Notifier) EndpointCount() int {
+	if n == nil {
+		return 0
+	}
+	return len(n.endpoints)
+}
+
+// Pending returns the number of webhook deliveries currently in flight
+func (n *Notifier) Pending() int64 {
+	if n == nil {
+		return 0
+	}
+	return n.pending.Load()
+}
+
+// Failures returns the number of failed webhook deliveries since startup
+func (n *Notifier) Failures() int64 {
+	if n == nil {
+		return 0
+	}
+	return n.failures.Load()
+}
+
 // ParseWebhookURLs parses a comma-separated string of webhook URLs
 func ParseWebhookURLs(urlsStr string) []string {
 	if urlsStr == "" {
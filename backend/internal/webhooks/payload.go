@@ -0,0 +1,122 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payload schema versions understood by receivers
+const (
+	SchemaV1 = 1
+	SchemaV2 = 2
+
+	// LatestSchemaVersion is the newest payload schema
+	LatestSchemaVersion = SchemaV2
+)
+
+// PayloadV1 is the original flat event payload
+type PayloadV1 struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Type          EventType              `json:"type"`
+	Timestamp     time.Time              `json:"timestamp"`
+	ClusterID     string                 `json:"cluster_id,omitempty"`
+	Resource      map[string]interface{} `json:"resource,omitempty"`
+	Message       string                 `json:"message"`
+	Severity      string                 `json:"severity"`
+}
+
+// PayloadV2 nests the event subject and separates typed resource fields from extra data
+type PayloadV2 struct {
+	SchemaVersion int                    `json:"schema_version"`
+	ID            string                 `json:"id"`
+	Type          EventType              `json:"type"`
+	OccurredAt    time.Time              `json:"occurred_at"`
+	Severity      string                 `json:"severity"`
+	Message       string                 `json:"message"`
+	Cluster       *ClusterRef            `json:"cluster,omitempty"`
+	Resource      *ResourceRef           `json:"resource,omitempty"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+}
+
+// ClusterRef identifies the cluster an event relates to
+type ClusterRef struct {
+	ID string `json:"id"`
+}
+
+// ResourceRef identifies the Flux resource an event relates to
+type ResourceRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ValidSchemaVersion reports whether a payload schema version is supported
+func ValidSchemaVersion(version int) bool {
+	return version >= SchemaV1 && version <= LatestSchemaVersion
+}
+
+// EncodePayload marshals an event using the given payload schema version
+func EncodePayload(event Event, version int) ([]byte, error) {
+	switch version {
+	case SchemaV1:
+		return json.Marshal(PayloadV1{
+			SchemaVersion: SchemaV1,
+			Type:          event.Type,
+			Timestamp:     event.Timestamp,
+			ClusterID:     event.ClusterID,
+			Resource:      event.Resource,
+			Message:       event.Message,
+			Severity:      event.Severity,
+		})
+	case SchemaV2:
+		return json.Marshal(toPayloadV2(event))
+	default:
+		return nil, fmt.Errorf("unsupported webhook schema version %d", version)
+	}
+}
+
+// toPayloadV2 converts an event to the v2 payload shape
+func toPayloadV2(event Event) PayloadV2 {
+	payload := PayloadV2{
+		SchemaVersion: SchemaV2,
+		ID:            event.ID,
+		Type:          event.Type,
+		OccurredAt:    event.Timestamp,
+		Severity:      event.Severity,
+		Message:       event.Message,
+	}
+	if payload.ID == "" {
+		payload.ID = uuid.New().String()
+	}
+	if event.ClusterID != "" {
+		payload.Cluster = &ClusterRef{ID: event.ClusterID}
+	}
+
+	for key, value := range event.Resource {
+		switch key {
+		case "kind", "namespace", "name":
+			if payload.Resource == nil {
+				payload.Resource = &ResourceRef{}
+			}
+			str, _ := value.(string)
+			switch key {
+			case "kind":
+				payload.Resource.Kind = str
+			case "namespace":
+				payload.Resource.Namespace = str
+			case "name":
+				payload.Resource.Name = str
+			}
+		default:
+			if payload.Data == nil {
+				payload.Data = make(map[string]interface{})
+			}
+			payload.Data[key] = value
+		}
+	}
+
+	return payload
+}
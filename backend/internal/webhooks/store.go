@@ -0,0 +1,252 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/database"
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// DefaultStoreCapacity is the number of recent events kept in memory
+const DefaultStoreCapacity = 1000
+
+// subscriberBuffer is how many events a slow subscriber may fall behind before it is dropped
+const subscriberBuffer = 64
+
+// StoredEvent is an event with the sequence number clients replay from
+type StoredEvent struct {
+	Sequence int64 `json:"sequence"`
+	Event
+}
+
+// Store keeps recent events in a ring buffer backed by the database, so clients that
+// reconnect can replay the events they missed, and fans new events out to subscribers.
+// It is safe for concurrent use.
+type Store struct {
+	mu          sync.RWMutex
+	db          *database.DB
+	logger      *zap.Logger
+	ring        []StoredEvent
+	start       int // index of the oldest event in ring
+	count       int
+	lastSeq     int64
+	subscribers map[chan StoredEvent]struct{}
+}
+
+// NewStore creates an event store holding up to capacity events in memory. When db is
+// set, events are persisted and the most recent ones are loaded back on startup.
+func NewStore(db *database.DB, capacity int, logger *zap.Logger) (*Store, error) {
+	if capacity <= 0 {
+		capacity = DefaultStoreCapacity
+	}
+	s := &Store{
+		db:          db,
+		logger:      logger,
+		ring:        make([]StoredEvent, capacity),
+		subscribers: make(map[chan StoredEvent]struct{}),
+	}
+	if db == nil {
+		return s, nil
+	}
+
+	var records []models.WebhookEvent
+	if err := db.Order("id DESC").Limit(capacity).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		s.push(fromRecord(records[i]))
+	}
+	return s, nil
+}
+
+// Add assigns the event the next sequence number, stores it and delivers it to subscribers
+func (s *Store) Add(event Event) StoredEvent {
+	s.mu.Lock()
+	s.lastSeq++
+	stored := StoredEvent{Sequence: s.lastSeq, Event: event}
+	s.push(stored)
+	for ch := range s.subscribers {
+		select {
+		case ch <- stored:
+		default:
+			// The subscriber fell behind; closing tells it to reconnect and replay
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	s.mu.Unlock()
+
+	if s.db != nil {
+		if err := s.db.Create(toRecord(stored)).Error; err != nil {
+			s.logger.Warn("Failed to persist event",
+				zap.Int64("sequence", stored.Sequence),
+				zap.String("event_type", string(event.Type)),
+				zap.Error(err),
+			)
+		}
+	}
+	return stored
+}
+
+// push appends an event to the ring, overwriting the oldest when full; s.mu must be held
+func (s *Store) push(event StoredEvent) {
+	if s.count < len(s.ring) {
+		s.ring[(s.start+s.count)%len(s.ring)] = event
+		s.count++
+	} else {
+		s.ring[s.start] = event
+		s.start = (s.start + 1) % len(s.ring)
+	}
+	if event.Sequence > s.lastSeq {
+		s.lastSeq = event.Sequence
+	}
+}
+
+// EventFilter selects events by cluster. The zero filter matches every event.
+type EventFilter struct {
+	ClusterID string // only the events of this cluster
+	// Restricted limits events to those of ClusterIDs, the clusters a caller can read
+	Restricted bool
+	ClusterIDs []string
+}
+
+// Matches reports whether the filter selects an event
+func (f EventFilter) Matches(event Event) bool {
+	if f.ClusterID != "" && event.ClusterID != f.ClusterID {
+		return false
+	}
+	if !f.Restricted {
+		return true
+	}
+	for _, id := range f.ClusterIDs {
+		if event.ClusterID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Recent returns up to limit events after the since sequence number that the filter
+// selects, oldest first. With since 0 it returns the newest events. Events older than the
+// ring buffer are read from the database.
+func (s *Store) Recent(since int64, limit int, filter EventFilter) ([]StoredEvent, error) {
+	if limit <= 0 {
+		limit = len(s.ring)
+	}
+
+	s.mu.RLock()
+	inMemory := s.db == nil || s.count == 0 || since <= 0 || since >= s.ring[s.start].Sequence-1
+	if inMemory {
+		events := make([]StoredEvent, 0)
+		for i := 0; i < s.count; i++ {
+			event := s.ring[(s.start+i)%len(s.ring)]
+			if event.Sequence <= since || !filter.Matches(event.Event) {
+				continue
+			}
+			events = append(events, event)
+		}
+		s.mu.RUnlock()
+
+		if len(events) > limit {
+			if since <= 0 {
+				events = events[len(events)-limit:]
+			} else {
+				events = events[:limit]
+			}
+		}
+		return events, nil
+	}
+	s.mu.RUnlock()
+
+	query := s.db.Where("id > ?", since)
+	if filter.ClusterID != "" {
+		query = query.Where("cluster_id = ?", filter.ClusterID)
+	}
+	if filter.Restricted {
+		query = query.Where("cluster_id IN ?", filter.ClusterIDs)
+	}
+	var records []models.WebhookEvent
+	if err := query.Order("id ASC").Limit(limit).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	events := make([]StoredEvent, 0, len(records))
+	for _, record := range records {
+		events = append(events, fromRecord(record))
+	}
+	return events, nil
+}
+
+// LastSequence returns the sequence number of the newest event
+func (s *Store) LastSequence() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastSeq
+}
+
+// Subscribe returns a channel receiving every event added from now on, and a function
+// that ends the subscription. The channel is closed if the subscriber falls behind.
+func (s *Store) Subscribe() (<-chan StoredEvent, func()) {
+	ch := make(chan StoredEvent, subscriberBuffer)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Prune deletes persisted events older than cutoff and returns how many were deleted.
+// The in-memory buffer is unaffected.
+func (s *Store) Prune(cutoff time.Time) (int64, error) {
+	if s.db == nil {
+		return 0, nil
+	}
+	result := s.db.Where("created_at < ?", cutoff).Delete(&models.WebhookEvent{})
+	return result.RowsAffected, result.Error
+}
+
+func toRecord(event StoredEvent) *models.WebhookEvent {
+	record := &models.WebhookEvent{
+		ID:        event.Sequence,
+		EventID:   event.ID,
+		Type:      string(event.Type),
+		ClusterID: event.ClusterID,
+		Message:   event.Message,
+		Severity:  event.Severity,
+		CreatedAt: event.Timestamp,
+	}
+	if len(event.Resource) > 0 {
+		if data, err := json.Marshal(event.Resource); err == nil {
+			record.Resource = string(data)
+		}
+	}
+	return record
+}
+
+func fromRecord(record models.WebhookEvent) StoredEvent {
+	event := StoredEvent{
+		Sequence: record.ID,
+		Event: Event{
+			ID:        record.EventID,
+			Type:      EventType(record.Type),
+			Timestamp: record.CreatedAt,
+			ClusterID: record.ClusterID,
+			Message:   record.Message,
+			Severity:  record.Severity,
+		},
+	}
+	if record.Resource != "" {
+		json.Unmarshal([]byte(record.Resource), &event.Resource)
+	}
+	return event
+}
@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Forcebyte/flux-orchestrator/backend/internal/httpclient"
+)
+
+// TLSConfig configures TLS for a single webhook endpoint. Certificates may be
+// given as file paths or inline PEM; inline PEM takes precedence.
+type TLSConfig struct {
+	CAFile     string `json:"ca_file,omitempty"`
+	CAPEM      string `json:"ca_pem,omitempty"`
+	CertFile   string `json:"cert_file,omitempty"`
+	CertPEM    string `json:"cert_pem,omitempty"`
+	KeyFile    string `json:"key_file,omitempty"`
+	KeyPEM     string `json:"key_pem,omitempty"`
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// build loads the certificates and returns the resulting tls.Config
+func (c *TLSConfig) build() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+
+	caPEM, err := pemOrFile(c.CAPEM, c.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("CA bundle contains no certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	certPEM, err := pemOrFile(c.CertPEM, c.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+	keyPEM, err := pemOrFile(c.KeyPEM, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client key: %w", err)
+	}
+	if len(certPEM) > 0 || len(keyPEM) > 0 {
+		if len(certPEM) == 0 || len(keyPEM) == 0 {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// pemOrFile returns inline PEM when set, otherwise the contents of path
+func pemOrFile(inline, path string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(path)
+}
+
+// newEndpointClient returns an HTTP client using the endpoint's TLS settings
+func newEndpointClient(tlsConfig *tls.Config, timeout time.Duration) *http.Client {
+	transport := httpclient.NewTransport()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
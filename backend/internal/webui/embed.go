@@ -0,0 +1,22 @@
+//go:build embed_frontend
+
+package webui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// dist is populated by `make build-embedded`, which copies frontend/dist here
+//
+//go:embed all:dist
+var dist embed.FS
+
+// Embedded returns the SPA compiled into the binary
+func Embedded() fs.FS {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		return nil
+	}
+	return sub
+}
@@ -0,0 +1,10 @@
+//go:build !embed_frontend
+
+package webui
+
+import "io/fs"
+
+// Embedded returns nil when the binary is built without the embed_frontend tag
+func Embedded() fs.FS {
+	return nil
+}
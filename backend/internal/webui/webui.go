@@ -0,0 +1,239 @@
+// Package webui serves the compiled frontend SPA, either embedded in the
+// binary (embed_frontend build tag) or from FRONTEND_DIR on disk.
+package webui
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultDir is the on-disk SPA location used when nothing is embedded
+const DefaultDir = "./frontend/dist"
+
+// minGzipSize is the smallest file worth compressing
+const minGzipSize = 1024
+
+// Cache policies
+const (
+	cacheImmutable = "public, max-age=31536000, immutable"
+	cacheDefault   = "public, max-age=3600"
+	cacheNone      = "no-cache"
+)
+
+// indexURLPattern matches root-relative and ./-relative src and href attributes in index.html
+var indexURLPattern = regexp.MustCompile(`(\s(?:src|href)=")(\./|/)(/?)`)
+
+// FS returns the embedded SPA, or FRONTEND_DIR (default DefaultDir) on disk
+func FS() fs.FS {
+	if embedded := Embedded(); embedded != nil {
+		return embedded
+	}
+	dir := os.Getenv("FRONTEND_DIR")
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return os.DirFS(dir)
+}
+
+// asset is a file loaded into memory with its precomputed encodings
+type asset struct {
+	name        string
+	contentType string
+	etag        string
+	modTime     time.Time
+	raw         []byte
+	gzip        []byte
+	brotli      []byte
+}
+
+// Handler serves SPA assets from memory
+type Handler struct {
+	assets map[string]*asset
+	index  *asset
+}
+
+// New loads every file in fsys into memory. Precompressed .gz and .br
+// siblings produced by the frontend build are used when present; other
+// compressible files are gzipped here. basePath is the URL prefix the app
+// is served under ("" for the root); asset links in index.html are
+// rewritten to it and it is exposed to the SPA in a meta tag.
+func New(fsys fs.FS, basePath string) (*Handler, error) {
+	h := &Handler{assets: make(map[string]*asset)}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".br") {
+			return nil
+		}
+
+		raw, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if name == "index.html" {
+			raw = rewriteIndex(raw, basePath)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(raw)
+		a := &asset{
+			name:        name,
+			contentType: contentType(name, raw),
+			etag:        `"` + hex.EncodeToString(sum[:8]) + `"`,
+			modTime:     info.ModTime(),
+			raw:         raw,
+		}
+		// Precompressed variants of index.html would miss the rewrite
+		if br, err := fs.ReadFile(fsys, name+".br"); err == nil && name != "index.html" {
+			a.brotli = br
+		}
+		if gz, err := fs.ReadFile(fsys, name+".gz"); err == nil && name != "index.html" {
+			a.gzip = gz
+		} else if len(raw) >= minGzipSize && compressible(a.contentType) {
+			a.gzip = gzipBytes(raw)
+		}
+
+		h.assets[name] = a
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.index = h.assets["index.html"]
+	return h, nil
+}
+
+// ServeHTTP serves a static asset, falling back to index.html for client-side routes
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	a, found := h.assets[name]
+	if !found {
+		// Missing hashed assets and files with extensions are real 404s, not routes
+		if strings.HasPrefix(name, "assets/") || path.Ext(name) != "" || h.index == nil {
+			http.NotFound(w, r)
+			return
+		}
+		a = h.index
+	}
+
+	w.Header().Set("Cache-Control", cacheControl(a.name))
+	h.serveAsset(w, r, a)
+}
+
+// serveAsset writes an asset using the best encoding the client accepts
+func (h *Handler) serveAsset(w http.ResponseWriter, r *http.Request, a *asset) {
+	body := a.raw
+	etag := a.etag
+	accept := r.Header.Get("Accept-Encoding")
+
+	if a.brotli != nil || a.gzip != nil {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	switch {
+	case a.brotli != nil && acceptsEncoding(accept, "br"):
+		body = a.brotli
+		etag = strings.TrimSuffix(a.etag, `"`) + `-br"`
+		w.Header().Set("Content-Encoding", "br")
+	case a.gzip != nil && acceptsEncoding(accept, "gzip"):
+		body = a.gzip
+		etag = strings.TrimSuffix(a.etag, `"`) + `-gz"`
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
+	w.Header().Set("Content-Type", a.contentType)
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, a.name, a.modTime, bytes.NewReader(body))
+}
+
+// rewriteIndex points index.html asset links at basePath and adds the
+// flux-base-path meta tag the SPA reads to build API and router URLs
+func rewriteIndex(raw []byte, basePath string) []byte {
+	raw = indexURLPattern.ReplaceAllFunc(raw, func(match []byte) []byte {
+		groups := indexURLPattern.FindSubmatch(match)
+		if len(groups[3]) > 0 {
+			// Protocol-relative URL, leave untouched
+			return match
+		}
+		return append(append([]byte{}, groups[1]...), []byte(basePath+"/")...)
+	})
+
+	meta := []byte(`<meta name="flux-base-path" content="` + html.EscapeString(basePath) + `" />`)
+	if i := bytes.Index(raw, []byte("</head>")); i >= 0 {
+		return append(append(append([]byte{}, raw[:i]...), append(meta, '\n')...), raw[i:]...)
+	}
+	return raw
+}
+
+// cacheControl returns the caching policy for an asset. Vite emits
+// content-hashed file names under assets/, so those never change.
+func cacheControl(name string) string {
+	switch {
+	case name == "index.html":
+		return cacheNone
+	case strings.HasPrefix(name, "assets/"):
+		return cacheImmutable
+	default:
+		return cacheDefault
+	}
+}
+
+// contentType resolves the MIME type from the extension, sniffing when unknown
+func contentType(name string, raw []byte) string {
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		return ct
+	}
+	return http.DetectContentType(raw)
+}
+
+// compressible reports whether a content type benefits from gzip
+func compressible(contentType string) bool {
+	for _, prefix := range []string{"text/", "application/javascript", "application/json", "image/svg+xml", "application/manifest+json", "application/xml"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows an encoding
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(fields[0]), encoding) {
+			continue
+		}
+		for _, param := range fields[1:] {
+			if strings.ReplaceAll(strings.TrimSpace(param), " ", "") == "q=0" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipBytes compresses data at the best compression level
+func gzipBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
@@ -8,14 +8,16 @@ import Clusters from './components/Clusters';
 import ClusterDetail from './components/ClusterDetail';
 import Settings from './components/Settings';
 import LogAggregation from './components/LogAggregation';
+import DeploymentTimeline from './components/DeploymentTimeline';
 import { Login } from './components/Login';
+import { BASE_PATH } from './api';
 import './App.css';
 
 const IS_DEMO_MODE = import.meta.env.VITE_DEMO_MODE === 'true';
 
 const Sidebar: React.FC = () => {
   const location = useLocation();
-  const { user, authEnabled, logout } = useAuth();
+  const { user, authEnabled, anonymousReadOnly, login, logout } = useAuth();
   const { darkMode, toggleDarkMode } = useTheme();
   const [mobileMenuOpen, setMobileMenuOpen] = React.useState(false);
 
@@ -44,7 +46,7 @@ const Sidebar: React.FC = () => {
       <div className={`sidebar ${mobileMenuOpen ? 'mobile-open' : ''}`}>
       <div className="sidebar-header">
         <div className="logo-container">
-          <img src="/flux-logo.png" alt="Flux Logo" className="sidebar-logo" />
+          <img src={`${BASE_PATH}/flux-logo.png`} alt="Flux Logo" className="sidebar-logo" />
         </div>
         <h1>Flux Orchestrator</h1>
         <p>Multi-Cluster GitOps</p>
@@ -71,6 +73,13 @@ const Sidebar: React.FC = () => {
         >
           Audit
         </Link>
+        <Link
+          to="/timeline"
+          className={`nav-item ${location.pathname === '/timeline' ? 'active' : ''}`}
+          onClick={closeMobileMenu}
+        >
+          Timeline
+        </Link>
         <Link
           to="/logs"
           className={`nav-item ${location.pathname === '/logs' ? 'active' : ''}`}
@@ -126,13 +135,39 @@ const Sidebar: React.FC = () => {
           </button>
         </div>
       )}
+      {authEnabled && !user && anonymousReadOnly && (
+        <div style={{
+          marginTop: 'auto',
+          padding: '1rem',
+          borderTop: '1px solid rgba(255, 255, 255, 0.1)',
+        }}>
+          <div style={{ fontSize: '0.875rem', color: '#9ca3af', marginBottom: '0.75rem' }}>
+            Read-only view
+          </div>
+          <button
+            onClick={login}
+            style={{
+              width: '100%',
+              padding: '0.5rem',
+              background: 'rgba(255, 255, 255, 0.1)',
+              color: 'white',
+              border: '1px solid rgba(255, 255, 255, 0.2)',
+              borderRadius: '4px',
+              fontSize: '0.875rem',
+              cursor: 'pointer',
+            }}
+          >
+            Sign In
+          </button>
+        </div>
+      )}
       </div>
     </>
   );
 };
 
 const AppContent: React.FC = () => {
-  const { isAuthenticated, isLoading, authEnabled, checkAuth } = useAuth();
+  const { isAuthenticated, isLoading, authEnabled, anonymousReadOnly, checkAuth } = useAuth();
 
   if (isLoading) {
     return (
@@ -150,7 +185,7 @@ const AppContent: React.FC = () => {
     );
   }
 
-  if (authEnabled && !isAuthenticated) {
+  if (authEnabled && !isAuthenticated && !anonymousReadOnly) {
     return <Login onLoginSuccess={checkAuth} />;
   }
 
@@ -176,6 +211,7 @@ const AppContent: React.FC = () => {
           <Route path="/clusters" element={<Clusters />} />
           <Route path="/clusters/:id" element={<ClusterDetail />} />
           <Route path="/audit" element={<Audit />} />
+          <Route path="/timeline" element={<DeploymentTimeline />} />
           <Route path="/logs" element={<LogAggregation />} />
           <Route path="/settings" element={<Settings />} />
         </Routes>
@@ -188,7 +224,7 @@ const App: React.FC = () => {
   return (
     <ThemeProvider>
       <AuthProvider>
-        <Router>
+        <Router basename={BASE_PATH || undefined}>
           <AppContent />
         </Router>
       </AuthProvider>
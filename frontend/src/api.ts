@@ -1,18 +1,24 @@
 import axios from 'axios';
-import { Cluster, FluxResource, ReconcileRequest, FluxStats, FluxResourceChild, Setting, ResourceNode, AzureSubscription, AKSCluster, AzureCredentials, Activity, OAuthProvider } from './types';
+import { Cluster, ClusterImportRequest, ClusterImportResponse, FluxResource, ReconcileRequest, FluxStats, FluxResourceChild, Setting, ResourceNode, AzureSubscription, AKSCluster, AzureCredentials, Activity, OAuthProvider, SystemStatus, OnboardingState, RecentEvents, ClusterQuotas, PodTriage, LogSearchParams, LogSearchResult, LogBundleRequest, LogBundleJob, PodDeletionRequest, PodDeletionResult, ResourceEvent, LogStreamParams, ExecParams, SecretKeys, HelmReleaseValues, HelmReleaseHistory, HelmRollbackResult, HelmRemediationResult, SourceReconcileResult, ReconcileProgress, ReconcileWaitResult, KustomizationDiff, PortForwardRequest, PortForwardResponse, FluxHealth, FluxVersion, ReconcileOrder, SuspendScope, SuspendTarget, BulkAction, BulkJob, ApplyResult, DeploymentTimeline, TimelineParams, IncidentFeed, ListSelectors, UnmanagedReport, ImageFreshnessReport, NamespaceSummary, RegistryCredential, RegistryCredentialInput, PodMetrics, ClusterCapabilities, AccessReviewRequest, AccessReview, ProviderGrants, SourceCommit, SourceRefs, ClusterShare, ClusterShares, QuotaUsage, SyncJob, FluxResourceFields, FluxResourceCreateResult } from './types';
 import {
   demoClusterApi,
   demoResourceApi,
   demoFluxApi,
   demoSettingsApi,
   demoAzureApi,
+  demoRegistryApi,
   demoActivityApi,
   demoOAuthApi,
+  demoGrantApi,
   demoExportApi,
   demoLogsApi,
+  demoTimelineApi,
 } from './demoApi';
 
-const API_BASE = '/api/v1';
+// URL prefix the app is served under, injected into index.html by the backend (BASE_PATH)
+export const BASE_PATH = document.querySelector('meta[name="flux-base-path"]')?.getAttribute('content') ?? '';
+
+const API_BASE = `${BASE_PATH}/api/v1`;
 
 // Check if we're in demo mode
 const IS_DEMO_MODE = import.meta.env.VITE_DEMO_MODE === 'true';
@@ -21,26 +27,101 @@ const api = axios.create({
   baseURL: API_BASE,
 });
 
+// Reads the Server-Sent Events of a reconcile with ?wait=true until its done or error event
+const readReconcileEvents = async (
+  body: ReadableStream<Uint8Array>,
+  onProgress: (progress: ReconcileProgress) => void
+): Promise<ReconcileWaitResult> => {
+  const reader = body.getReader();
+  const decoder = new TextDecoder();
+  let buffer = '';
+  for (;;) {
+    const { value, done } = await reader.read();
+    if (done) throw new Error('The reconcile stream closed before the resource was ready');
+    buffer += decoder.decode(value, { stream: true });
+    let boundary: number;
+    while ((boundary = buffer.indexOf('\n\n')) >= 0) {
+      const block = buffer.slice(0, boundary);
+      buffer = buffer.slice(boundary + 2);
+      let event = 'message';
+      let data = '';
+      for (const line of block.split('\n')) {
+        if (line.startsWith('event: ')) event = line.slice(7);
+        else if (line.startsWith('data: ')) data += line.slice(6);
+      }
+      if (!data) continue; // keep-alive comment
+      const payload = JSON.parse(data);
+      if (event === 'progress') onProgress(payload);
+      else if (event === 'done') return payload;
+      else if (event === 'error') throw new Error(payload.error);
+    }
+  }
+};
+
 // Export the appropriate API based on mode
 export const clusterApi = IS_DEMO_MODE ? demoClusterApi : {
-  list: () => api.get<Cluster[]>('/clusters'),
+  list: (includeArchived = false) =>
+    api.get<Cluster[]>('/clusters', { params: includeArchived ? { include_archived: true } : undefined }),
   get: (id: string) => api.get<Cluster>(`/clusters/${id}`),
-  create: (data: { name: string; description: string; kubeconfig: string }) =>
+  create: (data: { name: string; description: string; kubeconfig: string; client_qps?: number; client_burst?: number; client_timeout?: number; impersonate_users?: boolean; allow_duplicate?: boolean }) =>
     api.post<Cluster>('/clusters', data),
-  update: (id: string, data: Partial<{ name: string; description: string; kubeconfig: string; health_check_interval: number }>) =>
+  import: (data: ClusterImportRequest) => api.post<ClusterImportResponse>('/clusters/import', data),
+  update: (id: string, data: Partial<{ name: string; description: string; kubeconfig: string; health_check_interval: number; client_qps: number; client_burst: number; client_timeout: number; impersonate_users: boolean; version: number; confirm_server_change: boolean }>) =>
     api.put(`/clusters/${id}`, data),
   delete: (id: string) => api.delete(`/clusters/${id}`),
   checkHealth: (id: string) => api.get(`/clusters/${id}/health`),
-  syncResources: (id: string) => api.post(`/clusters/${id}/sync`),
-  getResourceTree: (id: string) => api.get<{ tree: ResourceNode[]; count: number }>(`/clusters/${id}/resources/tree`),
+  // Queues a full sync and returns its job (202); poll getSyncJob or wait for its sync.* event
+  syncResources: (id: string) => api.post<SyncJob>(`/clusters/${id}/sync`),
+  getSyncJob: (id: string, jobId: string) => api.get<SyncJob>(`/clusters/${id}/sync/${jobId}`),
+  getResourceTree: (id: string, selectors: ListSelectors = {}, refresh = false) =>
+    api.get<{ tree: ResourceNode[]; count: number }>(`/clusters/${id}/resources/tree`, {
+      params: refresh ? { ...selectors, refresh: true } : selectors
+    }),
+  // Flux kinds the cluster serves, so actions on kinds without a CRD can be left out
+  getCapabilities: (id: string) => api.get<ClusterCapabilities>(`/clusters/${id}/capabilities`),
+  // Whether the cluster lets the stored credentials (or the impersonated user) perform an action
+  accessReview: (id: string, params: AccessReviewRequest) =>
+    api.get<AccessReview>(`/clusters/${id}/access-review`, { params }),
+  getQuotas: (id: string, threshold?: number) =>
+    api.get<ClusterQuotas>(`/clusters/${id}/quotas`, { params: threshold ? { threshold } : undefined }),
+  // Workloads deployed outside Flux; kube-system and the other kube- namespaces only when named
+  getUnmanaged: (id: string, namespace?: string) =>
+    api.get<UnmanagedReport>(`/clusters/${id}/unmanaged`, { params: namespace ? { namespace } : undefined }),
+  // Running image tags against the newest in their registries, grouped by Flux owner
+  getImageFreshness: (id: string, outdatedOnly = false) =>
+    api.get<ImageFreshnessReport>(`/clusters/${id}/images`, { params: outdatedOnly ? { outdated: true } : undefined }),
   toggleFavorite: (id: string) => api.post<Cluster>(`/clusters/${id}/favorite`),
+  archive: (id: string) => api.post<Cluster>(`/clusters/${id}/archive`),
+  unarchive: (id: string) => api.post<Cluster>(`/clusters/${id}/unarchive`),
   exportCluster: (id: string, format: 'json' | 'csv' = 'json') => 
     api.get(`/clusters/${id}/export?format=${format}`, { responseType: 'blob' }),
+  // Shares (managed by owners of the cluster and holders of sharing.manage)
+  listShares: (id: string) => api.get<ClusterShares>(`/clusters/${id}/shares`),
+  putShare: (id: string, share: Pick<ClusterShare, 'subject_type' | 'subject' | 'access'>) =>
+    api.put<ClusterShare>(`/clusters/${id}/shares`, share),
+  deleteShare: (id: string, shareId: string) => api.delete<{ message: string }>(`/clusters/${id}/shares/${shareId}`),
+  // Activities, readiness transitions, deployments and Kubernetes events in one chronological feed
+  getIncidentFeed: (id: string, params?: { since?: string; until?: string; namespace?: string; limit?: number }) =>
+    api.get<IncidentFeed>(`/clusters/${id}/incident`, { params }),
+  // Server-side applies YAML documents (needs the manifest.apply permission)
+  applyManifests: (id: string, manifests: string, params: { namespace?: string; dry_run?: boolean; force?: boolean } = {}) =>
+    api.post<ApplyResult>(`/clusters/${id}/apply`, manifests, {
+      params, headers: { 'Content-Type': 'application/yaml' }
+    }),
+  listNamespaces: (id: string, selectors: ListSelectors = {}) =>
+    api.get<{ namespaces: NamespaceSummary[]; count: number }>(`/clusters/${id}/namespaces`, { params: selectors }),
+  // Namespaces (need the namespace.create and namespace.delete permissions; 422 when blocked by a guardrail)
+  createNamespace: (id: string, namespace: { name: string; labels?: Record<string, string>; annotations?: Record<string, string> }) =>
+    api.post<{ message: string; name: string }>(`/clusters/${id}/namespaces`, namespace),
+  deleteNamespace: (id: string, namespace: string) =>
+    api.delete<{ message: string }>(`/clusters/${id}/namespaces/${namespace}`),
 };
 
 export const resourceApi = IS_DEMO_MODE ? demoResourceApi : {
-  listAll: (kind?: string) => api.get<FluxResource[]>('/resources', { params: { kind } }),
-  listByCluster: (clusterId: string) => api.get<FluxResource[]>(`/clusters/${clusterId}/resources`),
+  // Stored lists can only select fields on metadata.name and metadata.namespace
+  listAll: (kind?: string, selectors: ListSelectors = {}) => api.get<FluxResource[]>('/resources', { params: { kind, ...selectors } }),
+  listByCluster: (clusterId: string, selectors: ListSelectors = {}) =>
+    api.get<FluxResource[]>(`/clusters/${clusterId}/resources`, { params: selectors }),
   get: (id: string) => api.get<FluxResource>(`/resources/${id}`),
   reconcile: (data: ReconcileRequest) => api.post('/resources/reconcile', data),
   // Resource management
@@ -48,8 +129,9 @@ export const resourceApi = IS_DEMO_MODE ? demoResourceApi : {
     api.post(`/clusters/${clusterId}/resources/${kind}/${namespace}/${name}/scale`, { replicas }),
   restart: (clusterId: string, kind: string, namespace: string, name: string) =>
     api.post(`/clusters/${clusterId}/resources/${kind}/${namespace}/${name}/restart`),
-  updateSpec: (clusterId: string, kind: string, namespace: string, name: string, patch: any) =>
-    api.put(`/clusters/${clusterId}/resources/${kind}/${namespace}/${name}/spec`, patch),
+  // force takes ownership of fields another field manager owns instead of failing with 409
+  updateSpec: (clusterId: string, kind: string, namespace: string, name: string, patch: any, force?: boolean) =>
+    api.put(`/clusters/${clusterId}/resources/${kind}/${namespace}/${name}/spec`, patch, { params: force ? { force: true } : undefined }),
   // Pod management
   getPodLogs: (clusterId: string, namespace: string, podName: string, container?: string, tail?: number) =>
     api.get<{ logs: string }>(`/clusters/${clusterId}/pods/${namespace}/${podName}/logs`, {
@@ -57,8 +139,41 @@ export const resourceApi = IS_DEMO_MODE ? demoResourceApi : {
     }),
   getPodContainers: (clusterId: string, namespace: string, podName: string) =>
     api.get<{ containers: string[] }>(`/clusters/${clusterId}/pods/${namespace}/${podName}/containers`),
-  deletePod: (clusterId: string, namespace: string, podName: string) =>
-    api.delete(`/clusters/${clusterId}/pods/${namespace}/${podName}`),
+  // CPU and memory from metrics-server; 503 when the cluster does not run it
+  getPodMetrics: (clusterId: string, namespace: string, podName: string) =>
+    api.get<PodMetrics>(`/clusters/${clusterId}/pods/${namespace}/${podName}/metrics`),
+  deletePod: (clusterId: string, namespace: string, podName: string, evict?: boolean) =>
+    api.delete(`/clusters/${clusterId}/pods/${namespace}/${podName}`, {
+      params: evict ? { evict: true } : undefined
+    }),
+  deletePods: (clusterId: string, namespace: string, data: PodDeletionRequest) =>
+    api.post<PodDeletionResult>(`/clusters/${clusterId}/namespaces/${namespace}/pods/delete`, data),
+  // One HTTP request to a pod port through a port-forward (needs the pod.portforward permission)
+  portForward: (clusterId: string, namespace: string, podName: string, data: PortForwardRequest) =>
+    api.post<PortForwardResponse>(`/clusters/${clusterId}/pods/${namespace}/${podName}/portforward`, data),
+  // Kubernetes events of a Flux resource or workload
+  getEvents: (clusterId: string, params: { kind: string; namespace: string; name: string; limit?: number }) =>
+    api.get<{ events: ResourceEvent[] }>(`/clusters/${clusterId}/events`, { params }),
+  // Key names of a Secret (needs the secret.reveal permission, audited)
+  getSecretKeys: (clusterId: string, namespace: string, name: string) =>
+    api.get<SecretKeys>(`/clusters/${clusterId}/secrets/${namespace}/${name}/keys`),
+  // Live object as YAML without managedFields; Secret values are redacted without secret.reveal
+  getYAML: (clusterId: string, kind: string, namespace: string, name: string) =>
+    api.get<string>(`/clusters/${clusterId}/resources/${kind}/${namespace}/${name}/yaml`, { responseType: 'text' }),
+  // Log bundles
+  downloadPodLogs: (clusterId: string, namespace: string, podName: string, tail?: number) =>
+    api.get<Blob>(`/clusters/${clusterId}/pods/${namespace}/${podName}/logs/download`, {
+      params: { tail }, responseType: 'blob'
+    }),
+  downloadWorkloadLogs: (clusterId: string, kind: string, namespace: string, name: string, tail?: number) =>
+    api.get<Blob>(`/clusters/${clusterId}/workloads/${kind}/${namespace}/${name}/logs/download`, {
+      params: { tail }, responseType: 'blob'
+    }),
+  createLogBundle: (clusterId: string, data: LogBundleRequest) =>
+    api.post<LogBundleJob>(`/clusters/${clusterId}/log-bundles`, data),
+  getLogBundle: (id: string) => api.get<LogBundleJob>(`/log-bundles/${id}`),
+  downloadLogBundle: (id: string) =>
+    api.get<Blob>(`/log-bundles/${id}/download`, { responseType: 'blob' }),
 };
 
 export const fluxApi = IS_DEMO_MODE ? demoFluxApi : {
@@ -67,18 +182,89 @@ export const fluxApi = IS_DEMO_MODE ? demoFluxApi : {
   
   // Existing methods
   getStats: (clusterId: string) => api.get<FluxStats>(`/clusters/${clusterId}/flux/stats`),
+  getHealth: (clusterId: string) => api.get<FluxHealth>(`/clusters/${clusterId}/flux/health`),
+  getVersion: (clusterId: string) => api.get<FluxVersion>(`/clusters/${clusterId}/flux/version`),
+  // source is Kind/namespace/name; without it every Kustomization and HelmRelease is ordered
+  getReconcileOrder: (clusterId: string, source?: string) =>
+    api.get<ReconcileOrder>(`/clusters/${clusterId}/flux/order`, { params: source ? { source } : undefined }),
   getResource: (clusterId: string, kind: string, namespace: string, name: string) =>
     api.get<FluxResource>(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}`),
-  updateResource: (clusterId: string, kind: string, namespace: string, name: string, patch: any) =>
-    api.put(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}`, patch),
-  reconcile: (clusterId: string, kind: string, namespace: string, name: string) =>
-    api.post(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/reconcile`),
+  // force takes ownership of fields another field manager owns instead of failing with 409
+  updateResource: (clusterId: string, kind: string, namespace: string, name: string, patch: any, force?: boolean) =>
+    api.put(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}`, patch, { params: force ? { force: true } : undefined }),
+  // withSource reconciles the Kustomization's or HelmRelease's source first and waits for its artifact;
+  // force (HelmReleases only) also runs a Helm install or upgrade when nothing changed
+  reconcile: (clusterId: string, kind: string, namespace: string, name: string, withSource?: boolean, force?: boolean) =>
+    api.post<{ message: string; forced?: boolean; source?: SourceReconcileResult }>(
+      `/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/reconcile`,
+      undefined,
+      { params: { ...(withSource ? { with_source: true } : {}), ...(force ? { force: true } : {}) } }
+    ),
+  // Reconciles and streams the resource's conditions until it is Ready, fails or times out
+  reconcileAndWait: async (
+    clusterId: string, kind: string, namespace: string, name: string,
+    onProgress: (progress: ReconcileProgress) => void, withSource?: boolean, force?: boolean
+  ): Promise<ReconcileWaitResult> => {
+    const params = new URLSearchParams({ wait: 'true' });
+    if (withSource) params.set('with_source', 'true');
+    if (force) params.set('force', 'true');
+    const response = await fetch(`${API_BASE}/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/reconcile?${params}`, {
+      method: 'POST',
+      credentials: 'same-origin',
+      headers: { Accept: 'text/event-stream' },
+    });
+    if (!response.ok || !response.body) {
+      const body = await response.json().catch(() => ({}));
+      throw new Error(body.error || `Reconcile failed with status ${response.status}`);
+    }
+    return readReconcileEvents(response.body, onProgress);
+  },
   suspend: (clusterId: string, kind: string, namespace: string, name: string) =>
     api.post(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/suspend`),
   resume: (clusterId: string, kind: string, namespace: string, name: string) =>
     api.post(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/resume`),
+  // Cluster-wide suspend or resume, run as a background job; dry runs only list the targets
+  previewBulkAction: (clusterId: string, action: BulkAction, scope: SuspendScope) =>
+    api.post<{ action: string; scope: SuspendScope; targets: SuspendTarget[] }>(`/clusters/${clusterId}/flux/bulk`, { action, selector: scope, dry_run: true }),
+  startBulkAction: (clusterId: string, action: BulkAction, scope: SuspendScope) =>
+    api.post<BulkJob>(`/clusters/${clusterId}/flux/bulk`, { action, selector: scope }),
+  getBulkJob: (id: string) => api.get<BulkJob>(`/bulk-jobs/${id}`),
   getChildren: (clusterId: string, kind: string, namespace: string, name: string) =>
     api.get<{ resources: FluxResourceChild[]; count: number }>(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/resources`),
+  getTriage: (clusterId: string, kind: string, namespace: string, name: string) =>
+    api.get<PodTriage>(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/triage`),
+  // The GitHub commit a GitRepository last fetched, read as the user when they granted GitHub access
+  getSourceCommit: (clusterId: string, namespace: string, name: string) =>
+    api.get<SourceCommit>(`/clusters/${clusterId}/flux/GitRepository/${namespace}/${name}/commit`),
+  // Branches and tags of a GitRepository's remote, or of a Kustomization's GitRepository
+  getSourceRefs: (clusterId: string, kind: string, namespace: string, name: string) =>
+    api.get<SourceRefs>(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/refs`),
+  getHelmValues: (clusterId: string, namespace: string, name: string) =>
+    api.get<HelmReleaseValues>(`/clusters/${clusterId}/flux/HelmRelease/${namespace}/${name}/values`),
+  getHelmHistory: (clusterId: string, namespace: string, name: string) =>
+    api.get<HelmReleaseHistory>(`/clusters/${clusterId}/flux/HelmRelease/${namespace}/${name}/history`),
+  rollbackHelmRelease: (clusterId: string, namespace: string, name: string, revision: number) =>
+    api.post<HelmRollbackResult>(`/clusters/${clusterId}/flux/HelmRelease/${namespace}/${name}/rollback`, { revision }),
+  // Runs a Helm install or upgrade even when nothing changed, like flux reconcile helmrelease --force
+  forceReconcileHelmRelease: (clusterId: string, namespace: string, name: string) =>
+    api.post<HelmRemediationResult>(`/clusters/${clusterId}/flux/HelmRelease/${namespace}/${name}/force`),
+  // Clears install and upgrade failure counts so remediation retries start over, like --reset
+  resetHelmReleaseFailures: (clusterId: string, namespace: string, name: string) =>
+    api.post<HelmRemediationResult>(`/clusters/${clusterId}/flux/HelmRelease/${namespace}/${name}/reset`),
+  getKustomizationDiff: (clusterId: string, namespace: string, name: string) =>
+    api.get<KustomizationDiff>(`/clusters/${clusterId}/flux/Kustomization/${namespace}/${name}/diff`),
+  searchLogs: (clusterId: string, kind: string, namespace: string, name: string, params: LogSearchParams) =>
+    api.get<LogSearchResult>(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}/logs/search`, { params }),
+  // Creates a Kustomization, HelmRelease, GitRepository or HelmRepository from guided fields or
+  // a manifest, or an Alert, Provider or Receiver from a manifest; dryRun returns the manifest
+  // without creating it
+  create: (clusterId: string, kind: string, body: FluxResourceFields | { manifest: Record<string, any> }, dryRun = false) =>
+    api.post<FluxResourceCreateResult>(`/clusters/${clusterId}/flux/${kind}`, body, { params: dryRun ? { dry_run: true } : undefined }),
+  // Notification-controller resources (Alert, Provider, Receiver)
+  createNotification: (clusterId: string, kind: string, namespace: string, manifest: any) =>
+    api.post(`/clusters/${clusterId}/flux/${kind}`, { manifest: { ...manifest, metadata: { ...manifest.metadata, namespace } } }),
+  deleteNotification: (clusterId: string, kind: string, namespace: string, name: string) =>
+    api.delete(`/clusters/${clusterId}/flux/${kind}/${namespace}/${name}`),
 };
 
 export const settingsApi = IS_DEMO_MODE ? demoSettingsApi : {
@@ -110,6 +296,27 @@ export const azureApi = IS_DEMO_MODE ? demoAzureApi : {
   syncClusters: (id: string) => api.post<{ synced: number; failed: number; clusters: Array<{ name: string; status: string; error?: string }> }>(`/azure/subscriptions/${id}/sync`),
 };
 
+export const registryApi = IS_DEMO_MODE ? demoRegistryApi : {
+  // List container registry credentials
+  list: () => api.get<RegistryCredential[]>('/registries'),
+
+  // Get a specific credential
+  get: (id: string) => api.get<RegistryCredential>(`/registries/${id}`),
+
+  // Store a credential; the registry must accept it first
+  create: (data: RegistryCredentialInput) => api.post<RegistryCredential>('/registries', data),
+
+  // Update a credential; an empty password keeps the stored one
+  update: (id: string, data: Partial<RegistryCredentialInput> & { version?: number }) =>
+    api.put<RegistryCredential>(`/registries/${id}`, data),
+
+  // Delete a credential
+  delete: (id: string) => api.delete(`/registries/${id}`),
+
+  // Log in to the registry with the stored credential
+  test: (id: string) => api.post<{ status: string; message: string }>(`/registries/${id}/test`),
+};
+
 export const activityApi = IS_DEMO_MODE ? demoActivityApi : {
   // List recent activities
   list: (params?: { limit?: number; cluster_id?: string }) => 
@@ -149,6 +356,7 @@ export const oauthApi = IS_DEMO_MODE ? demoOAuthApi : {
     scopes?: string;
     allowed_users?: string;
     enabled?: boolean;
+    version?: number;
   }) => api.put(`/oauth/providers/${id}`, data),
   
   // Delete a provider
@@ -158,12 +366,78 @@ export const oauthApi = IS_DEMO_MODE ? demoOAuthApi : {
   testProvider: (id: string) => api.post<{ status: string; message: string }>(`/oauth/providers/${id}/test`),
 };
 
+export const grantApi = IS_DEMO_MODE ? demoGrantApi : {
+  list: () => api.get<ProviderGrants>('/auth/grants'),
+  // The consent page is a browser navigation, not an XHR
+  authorizeUrl: (provider: string) => `${API_BASE}/auth/grants/${provider}/authorize`,
+  revoke: (provider: string) => api.delete<{ message: string }>(`/auth/grants/${provider}`),
+};
+
 export const exportApi = IS_DEMO_MODE ? demoExportApi : {
   // Export resources as CSV or JSON
   exportResources: (params?: { format?: 'json' | 'csv'; status?: string; kind?: string }) =>
     api.get('/resources/export', { params, responseType: 'blob' }),
 };
 
+// System status API - no demo mode yet. A 503 still carries the report.
+export const systemApi = {
+  getStatus: () =>
+    api.get<SystemStatus>('/system/status', {
+      validateStatus: (status) => status === 200 || status === 503,
+    }),
+};
+
+// Recorded events - no demo mode yet
+export const eventsApi = {
+  recent: (params?: { since?: number; limit?: number; cluster_id?: string }) =>
+    api.get<RecentEvents>('/events/recent', { params }),
+  // WebSocket URL replaying the events after since, then streaming new ones
+  streamUrl: (since: number) => {
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    return `${protocol}//${window.location.host}${API_BASE}/events/stream?since=${since}`;
+  },
+};
+
+// Live pod logs - no demo mode yet
+export const logStreamApi = {
+  // WebSocket URL streaming a container's log, following new lines unless follow is false
+  podUrl: (clusterId: string, namespace: string, podName: string, params: LogStreamParams) => {
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const query = new URLSearchParams();
+    Object.entries(params).forEach(([key, value]) => {
+      if (value !== undefined && value !== '') {
+        query.set(key, String(value));
+      }
+    });
+    return `${protocol}//${window.location.host}${API_BASE}/clusters/${clusterId}/pods/${namespace}/${podName}/logs/stream?${query}`;
+  },
+};
+
+// Pod exec sessions - no demo mode yet
+export const execApi = {
+  // WebSocket URL attached to a command in a container, /bin/sh unless command is given
+  podUrl: (clusterId: string, namespace: string, podName: string, params: ExecParams) => {
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const query = new URLSearchParams();
+    if (params.container) {
+      query.set('container', params.container);
+    }
+    params.command?.forEach((arg) => query.append('command', arg));
+    if (params.tty !== undefined) {
+      query.set('tty', String(params.tty));
+    }
+    return `${protocol}//${window.location.host}${API_BASE}/clusters/${clusterId}/pods/${namespace}/${podName}/exec?${query}`;
+  },
+};
+
+export const onboardingApi = {
+  get: () => api.get<OnboardingState>('/onboarding'),
+  setSkipped: (step: string, skipped: boolean) => api.put<OnboardingState>(`/onboarding/steps/${step}`, { skipped }),
+  dismiss: () => api.post<OnboardingState>('/onboarding/dismiss'),
+  reset: () => api.post<OnboardingState>('/onboarding/reset'),
+  generateEncryptionKey: () => api.post<{ key: string }>('/onboarding/encryption-key'),
+};
+
 // RBAC API - no demo mode yet
 export const rbacApi = {
   // Users
@@ -174,6 +448,8 @@ export const rbacApi = {
   deleteUser: (id: string) => api.delete(`/rbac/users/${id}`),
   assignUserRoles: (id: string, roleIds: string[]) =>
     api.put(`/rbac/users/${id}/roles`, { role_ids: roleIds }),
+  disableUser: (id: string) => api.post(`/rbac/users/${id}/disable`),
+  enableUser: (id: string) => api.post(`/rbac/users/${id}/enable`),
   
   // Roles
   listRoles: () => api.get('/rbac/roles'),
@@ -188,6 +464,33 @@ export const rbacApi = {
   
   // Permissions
   listPermissions: () => api.get('/rbac/permissions'),
+
+  // Roles of new users
+  listRoleMappings: () => api.get('/rbac/role-mappings'),
+  createRoleMapping: (data: { type: string; value: string; role_id: string }) =>
+    api.post('/rbac/role-mappings', data),
+  updateRoleMapping: (id: string, data: { type: string; value: string; role_id: string }) =>
+    api.put(`/rbac/role-mappings/${id}`, data),
+  deleteRoleMapping: (id: string) => api.delete(`/rbac/role-mappings/${id}`),
+  setDefaultRole: (roleId: string) => api.put('/rbac/role-mappings/default', { role_id: roleId }),
+  evaluateRoleMappings: (params: { email?: string; provider?: string; group?: string[] }) =>
+    api.get('/rbac/role-mappings/evaluate', { params, paramsSerializer: { indexes: null } }),
+
+  // The current user's own access
+  myPermissions: () => api.get('/me/permissions'),
+  explainPermission: (permission: string) =>
+    api.get('/me/permissions/explain', { params: { permission } }),
+  // Hourly quotas of tree builds, syncs and exports; over them requests answer 429
+  myQuotas: () => api.get<{ window_seconds: number; quotas: QuotaUsage[] }>('/me/quotas'),
+
+  // Configuration as code
+  exportConfig: (format: 'json' | 'yaml') =>
+    api.get(`/rbac/export?format=${format}`, { responseType: 'blob' }),
+  importConfig: (config: string, dryRun = false) =>
+    api.post('/rbac/import', config, {
+      params: dryRun ? { dry_run: true } : undefined,
+      headers: { 'Content-Type': 'application/yaml' },
+    }),
 };
 
 export const logsApi = IS_DEMO_MODE ? demoLogsApi : {
@@ -195,4 +498,9 @@ export const logsApi = IS_DEMO_MODE ? demoLogsApi : {
     api.get(`/logs/aggregated?${params.toString()}`),
 };
 
+// Revisions deployed across clusters, newest first; defaults to the last 24 hours
+export const timelineApi = IS_DEMO_MODE ? demoTimelineApi : {
+  list: (params?: TimelineParams) => api.get<DeploymentTimeline>('/timeline', { params }),
+};
+
 export default api;
@@ -1,6 +1,6 @@
 import React, { useState, useEffect } from 'react';
 import { activityApi } from '../api';
-import { Activity } from '../types';
+import { Activity, ActivityChangeSet, WorkloadActionDetails, WorkloadSnapshot } from '../types';
 import '../styles/ActivityFeed.css';
 
 interface ActivityFeedProps {
@@ -8,6 +8,69 @@ interface ActivityFeedProps {
   limit?: number;
 }
 
+const formatSnapshot = (snapshot?: WorkloadSnapshot) =>
+  snapshot ? `${snapshot.ready_replicas}/${snapshot.replicas} ready, ${snapshot.updated_replicas} updated` : 'unknown';
+
+// Shows the before/after replica state recorded with scale and restart actions
+const renderWorkloadDetails = (activity: Activity) => {
+  if (!activity.details || (activity.action !== 'scale' && activity.action !== 'restart')) {
+    return null;
+  }
+  let details: WorkloadActionDetails;
+  try {
+    details = JSON.parse(activity.details);
+  } catch {
+    return null;
+  }
+  return (
+    <div className="activity-snapshot">
+      <span>Before: {formatSnapshot(details.before)}</span>
+      <span>→</span>
+      <span>After: {formatSnapshot(details.after)}</span>
+      <span className="activity-snapshot-state">
+        {details.following ? 'rolling out…' : details.settled ? 'settled' : details.after ? 'not settled' : ''}
+      </span>
+    </div>
+  );
+};
+
+const formatValue = (value: unknown) =>
+  value === undefined ? '—' : typeof value === 'string' ? value || '""' : JSON.stringify(value);
+
+// Shows the old and new values and the parameters recorded with other changes
+const renderChangeSet = (activity: Activity) => {
+  if (!activity.details || activity.action === 'scale' || activity.action === 'restart') {
+    return null;
+  }
+  let details: ActivityChangeSet;
+  try {
+    details = JSON.parse(activity.details);
+  } catch {
+    return null;
+  }
+  const parameters = Object.entries(details.parameters || {});
+  if (!details.changes?.length && parameters.length === 0) {
+    return null;
+  }
+  return (
+    <div className="activity-changes">
+      {details.changes?.map((change) => (
+        <div key={change.field} className="activity-change">
+          <span className="activity-change-field">{change.field}</span>
+          <span className="activity-change-old">{formatValue(change.old)}</span>
+          <span>→</span>
+          <span className="activity-change-new">{formatValue(change.new)}</span>
+        </div>
+      ))}
+      {parameters.length > 0 && (
+        <div className="activity-change-params">
+          {parameters.map(([name, value]) => `${name}=${formatValue(value)}`).join(', ')}
+        </div>
+      )}
+    </div>
+  );
+};
+
 const ActivityFeed: React.FC<ActivityFeedProps> = ({ clusterId, limit = 50 }) => {
   const [activities, setActivities] = useState<Activity[]>([]);
   const [loading, setLoading] = useState(true);
@@ -100,6 +163,8 @@ const ActivityFeed: React.FC<ActivityFeedProps> = ({ clusterId, limit = 50 }) =>
               {activity.message && (
                 <div className="activity-message">{activity.message}</div>
               )}
+              {renderWorkloadDetails(activity)}
+              {renderChangeSet(activity)}
             </div>
           </div>
         ))}
@@ -0,0 +1,127 @@
+import React, { useState } from 'react';
+import { clusterApi } from '../api';
+import { ApplyResult } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+import '../styles/BulkSuspend.css';
+import '../styles/ApplyManifests.css';
+
+interface ApplyManifestsProps {
+  clusterId: string;
+  clusterName: string;
+  onClose: () => void;
+  onApplied: () => void;
+}
+
+const statusClass = (action: string) =>
+  action === 'error' ? 'error' : action === 'unchanged' ? 'skipped' : 'applied';
+
+// Server-side applies pasted YAML documents to a cluster, with a dry run to check them first
+const ApplyManifests: React.FC<ApplyManifestsProps> = ({ clusterId, clusterName, onClose, onApplied }) => {
+  const [manifests, setManifests] = useState('');
+  const [namespace, setNamespace] = useState('');
+  const [force, setForce] = useState(false);
+  const [result, setResult] = useState<ApplyResult | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const apply = async (dryRun: boolean) => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await clusterApi.applyManifests(clusterId, manifests, {
+        namespace: namespace.trim() || undefined,
+        dry_run: dryRun || undefined,
+        force: force || undefined,
+      });
+      setResult(response.data);
+      if (!dryRun) onApplied();
+    } catch (err: any) {
+      setResult(null);
+      setError(err.response?.data?.error || 'Failed to apply manifests');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Apply Manifests</h2>
+            <div className="resource-info">
+              <span className="badge">Cluster</span>
+              <span>{clusterName}</span>
+            </div>
+          </div>
+          <button className="btn-close" onClick={onClose}>✕</button>
+        </div>
+
+        <div className="bulk-suspend-form">
+          <input
+            type="text"
+            placeholder="Default namespace"
+            value={namespace}
+            onChange={e => setNamespace(e.target.value)}
+          />
+          <label>
+            <input type="checkbox" checked={force} onChange={e => setForce(e.target.checked)} />
+            Force conflicts
+          </label>
+          <button className="btn btn-sm btn-secondary" onClick={() => apply(true)} disabled={loading || !manifests.trim()}>
+            Dry Run
+          </button>
+          <button className="btn btn-sm btn-primary" onClick={() => apply(false)} disabled={loading || !manifests.trim()}>
+            Apply
+          </button>
+        </div>
+
+        <div className="triage-body">
+          <textarea
+            className="apply-manifests-input"
+            placeholder={'apiVersion: source.toolkit.fluxcd.io/v1\nkind: GitRepository\n...'}
+            value={manifests}
+            onChange={e => { setManifests(e.target.value); setResult(null); }}
+            spellCheck={false}
+          />
+
+          {loading && <div className="loading">Applying...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {result && (
+            <>
+              <p className="helm-values-hint">
+                {result.dry_run ? 'Dry run, nothing was changed: ' : ''}
+                {Object.entries(result.summary).map(([action, count]) => `${count} ${action}`).join(', ')}
+              </p>
+              <table className="triage-containers">
+                <thead>
+                  <tr>
+                    <th>Kind</th>
+                    <th>Object</th>
+                    <th>Result</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {result.objects.map(obj => (
+                    <tr key={`${obj.api_version}/${obj.kind}/${obj.namespace}/${obj.name}`}>
+                      <td>{obj.kind}</td>
+                      <td>{obj.namespace ? `${obj.namespace}/${obj.name}` : obj.name}</td>
+                      <td className={`helm-values-status ${statusClass(obj.action)}`}>
+                        {obj.action === 'error' ? obj.error : obj.action}
+                      </td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ApplyManifests;
@@ -0,0 +1,223 @@
+import React, { useState, useEffect } from 'react';
+import { clusterApi, fluxApi } from '../api';
+import { BulkAction, SuspendScope, SuspendTarget, BulkJob } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+import '../styles/BulkSuspend.css';
+
+interface BulkSuspendProps {
+  clusterId: string;
+  clusterName: string;
+  action: BulkAction;
+  // Kinds the cluster serves; both when unknown
+  availableKinds?: Kind[];
+  onClose: () => void;
+  onDone: () => void;
+}
+
+type Kind = 'Kustomization' | 'HelmRelease';
+
+// How each action is worded; skipped is how resources it leaves alone are described
+const wording: Record<BulkAction, { verb: string; past: string; skipped: string; hint: string }> = {
+  suspend: {
+    verb: 'Suspend', past: 'suspended', skipped: 'already suspended',
+    hint: 'Freeze reconciliation of every matching resource, for example during an incident. Preview the resources first.',
+  },
+  resume: {
+    verb: 'Resume', past: 'resumed', skipped: 'already resumed',
+    hint: 'Resume reconciliation of every matching resource. Preview the resources first.',
+  },
+  reconcile: {
+    verb: 'Reconcile', past: 'reconcile requested', skipped: 'suspended, skipped',
+    hint: 'Request a reconcile of every matching resource. Suspended resources are skipped, since Flux ignores the request. Preview the resources first.',
+  },
+};
+
+// Suspends, resumes or reconciles every Kustomization and HelmRelease in a scope,
+// previewing the matched resources before the background job starts
+const BulkSuspend: React.FC<BulkSuspendProps> = ({
+  clusterId, clusterName, action, availableKinds = ['Kustomization', 'HelmRelease'], onClose, onDone,
+}) => {
+  const [kinds, setKinds] = useState<Kind[]>(availableKinds);
+  const [namespace, setNamespace] = useState('');
+  const [labelSelector, setLabelSelector] = useState('');
+  const [targets, setTargets] = useState<SuspendTarget[] | null>(null);
+  const [job, setJob] = useState<BulkJob | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [namespaces, setNamespaces] = useState<string[]>([]);
+
+  useEffect(() => {
+    // Suggestions only; the namespace can still be typed when they fail to load
+    clusterApi.listNamespaces(clusterId)
+      .then(response => setNamespaces(response.data.namespaces.map(ns => ns.name)))
+      .catch(() => setNamespaces([]));
+  }, [clusterId]);
+
+  const { verb, past, skipped, hint } = wording[action];
+  const scope = (): SuspendScope => ({ kinds, namespace: namespace.trim(), label_selector: labelSelector.trim() });
+  const pending = targets?.filter(t => (action === 'resume' ? t.suspended : !t.suspended)) ?? [];
+
+  const toggleKind = (kind: Kind) => {
+    setKinds(prev => (prev.includes(kind) ? prev.filter(k => k !== kind) : [...prev, kind]));
+    setTargets(null);
+  };
+
+  const preview = async (e: React.FormEvent) => {
+    e.preventDefault();
+    try {
+      setLoading(true);
+      setError(null);
+      setJob(null);
+      const response = await fluxApi.previewBulkAction(clusterId, action, scope());
+      setTargets(response.data.targets);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to list resources');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const start = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      let current = (await fluxApi.startBulkAction(clusterId, action, scope())).data;
+      setJob(current);
+      while (current.status === 'pending' || current.status === 'running') {
+        await new Promise((resolve) => setTimeout(resolve, 2000));
+        current = (await fluxApi.getBulkJob(current.id)).data;
+        setJob(current);
+      }
+      onDone();
+    } catch (err: any) {
+      setError(err.response?.data?.error || `Failed to ${action} resources`);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const running = job !== null && (job.status === 'pending' || job.status === 'running');
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>{verb} All</h2>
+            <div className="resource-info">
+              <span className="badge">Cluster</span>
+              <span>{clusterName}</span>
+            </div>
+          </div>
+          <button className="btn-close" onClick={onClose}>✕</button>
+        </div>
+
+        <form className="bulk-suspend-form" onSubmit={preview}>
+          {availableKinds.map(kind => (
+            <label key={kind}>
+              <input type="checkbox" checked={kinds.includes(kind)} onChange={() => toggleKind(kind)} disabled={running} />
+              {kind}s
+            </label>
+          ))}
+          <input
+            type="text"
+            placeholder="Namespace (all)"
+            list="bulk-namespaces"
+            value={namespace}
+            onChange={e => { setNamespace(e.target.value); setTargets(null); }}
+            disabled={running}
+          />
+          <datalist id="bulk-namespaces">
+            {namespaces.map(ns => <option key={ns} value={ns} />)}
+          </datalist>
+          <input
+            type="text"
+            placeholder="Label selector, e.g. team=payments"
+            value={labelSelector}
+            onChange={e => { setLabelSelector(e.target.value); setTargets(null); }}
+            disabled={running}
+          />
+          <button type="submit" className="btn btn-sm btn-secondary" disabled={loading || kinds.length === 0}>
+            Preview
+          </button>
+        </form>
+
+        <div className="triage-body">
+          {error && <div className="error-message">{error}</div>}
+
+          {job ? (
+            <>
+              <div className="bulk-suspend-summary">
+                <span>Status: {job.status}</span>
+                <span className="helm-values-status applied">{job.summary.applied} {past}</span>
+                <span className="helm-values-status skipped">{job.summary.skipped} {skipped}</span>
+                <span className="helm-values-status error">{job.summary.failed} failed</span>
+              </div>
+              {job.error && <div className="error-message">{job.error}</div>}
+              <table className="triage-containers">
+                <thead>
+                  <tr>
+                    <th>Kind</th>
+                    <th>Resource</th>
+                    <th>Result</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {job.results.map(result => (
+                    <tr key={`${result.kind}/${result.namespace}/${result.name}`}>
+                      <td>{result.kind}</td>
+                      <td>{result.namespace}/{result.name}</td>
+                      <td className={`helm-values-status ${result.status === 'failed' ? 'error' : result.status}`}>
+                        {result.status === 'failed' ? result.error : result.status === 'applied' ? past : skipped}
+                      </td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+            </>
+          ) : targets === null ? (
+            <p className="helm-values-hint">{hint}</p>
+          ) : targets.length === 0 ? (
+            <div className="triage-no-events">No Kustomizations or HelmReleases match this scope.</div>
+          ) : (
+            <>
+              <p className="helm-values-hint">
+                {pending.length} of {targets.length} matching resources will be {action === 'reconcile' ? 'reconciled' : past}; the rest are {skipped}.
+              </p>
+              <table className="triage-containers">
+                <thead>
+                  <tr>
+                    <th>Kind</th>
+                    <th>Resource</th>
+                    <th>Current</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {targets.map(target => (
+                    <tr key={`${target.kind}/${target.namespace}/${target.name}`}>
+                      <td>{target.kind}</td>
+                      <td>{target.namespace}/{target.name}</td>
+                      <td>{target.suspended ? 'suspended' : 'active'}</td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+              <div className="bulk-suspend-confirm">
+                <button
+                  className={`btn btn-sm ${action === 'suspend' ? 'btn-warning' : action === 'resume' ? 'btn-success' : 'btn-primary'}`}
+                  onClick={start}
+                  disabled={loading || pending.length === 0}
+                >
+                  {verb} {pending.length} resources
+                </button>
+              </div>
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default BulkSuspend;
@@ -1,13 +1,27 @@
 import React, { useState, useEffect } from 'react';
 import { useParams, Link } from 'react-router-dom';
 import { clusterApi, resourceApi, fluxApi } from '../api';
-import { Cluster, FluxResource, FluxStats, FluxResourceChild } from '../types';
+import { Cluster, FluxResource, FluxStats, FluxHealth, FluxVersion, ClusterCapabilities, FluxResourceChild, ReconcileProgress, ObjectRef, BulkAction } from '../types';
 import { useToast } from '../hooks/useToast';
 import Toast from './Toast';
 import ResourceTree from './ResourceTree';
+import NamespaceQuotas from './NamespaceQuotas';
+import UnmanagedResources from './UnmanagedResources';
+import ImageFreshness from './ImageFreshness';
 import FluxResourceEditDialog from './FluxResourceEditDialog';
 import KustomizationDetail from './KustomizationDetail';
 import ResourceDiffViewer from './ResourceDiffViewer';
+import PodTriage from './PodTriage';
+import LogSearch from './LogSearch';
+import ResourceEvents from './ResourceEvents';
+import HelmValues from './HelmValues';
+import HelmHistory from './HelmHistory';
+import KustomizationDiff from './KustomizationDiff';
+import ReconcileOrder from './ReconcileOrder';
+import BulkSuspend from './BulkSuspend';
+import ResourceYAML from './ResourceYAML';
+import ApplyManifests from './ApplyManifests';
+import IncidentFeed from './IncidentFeed';
 import '../styles/ClusterDetail.css';
 
 const ClusterDetail: React.FC = () => {
@@ -15,16 +29,32 @@ const ClusterDetail: React.FC = () => {
   const [cluster, setCluster] = useState<Cluster | null>(null);
   const [resources, setResources] = useState<FluxResource[]>([]);
   const [fluxStats, setFluxStats] = useState<FluxStats | null>(null);
+  const [fluxHealth, setFluxHealth] = useState<FluxHealth | null>(null);
+  const [fluxVersion, setFluxVersion] = useState<FluxVersion | null>(null);
+  const [capabilities, setCapabilities] = useState<ClusterCapabilities | null>(null);
   const [loading, setLoading] = useState(true);
   const [activeTab, setActiveTab] = useState<string>('all');
   const [expandedResources, setExpandedResources] = useState<Set<string>>(new Set());
   const [resourceChildren, setResourceChildren] = useState<Record<string, FluxResourceChild[]>>({});
   const [loadingChildren, setLoadingChildren] = useState<Set<string>>(new Set());
   const [isReconciling, setIsReconciling] = useState<Set<string>>(new Set());
+  const [reconcileProgress, setReconcileProgress] = useState<Record<string, ReconcileProgress>>({});
   const [isSuspending, setIsSuspending] = useState<Set<string>>(new Set());
   const [editingResource, setEditingResource] = useState<FluxResource | null>(null);
   const [kustomizationDetail, setKustomizationDetail] = useState<{ namespace: string; name: string } | null>(null);
   const [viewingDiff, setViewingDiff] = useState<{ kind: string; namespace: string; name: string } | null>(null);
+  const [triaging, setTriaging] = useState<{ kind: string; namespace: string; name: string } | null>(null);
+  const [searchingLogs, setSearchingLogs] = useState<{ kind: string; namespace: string; name: string } | null>(null);
+  const [viewingEvents, setViewingEvents] = useState<{ kind: string; namespace: string; name: string } | null>(null);
+  const [viewingYAML, setViewingYAML] = useState<{ kind: string; namespace: string; name: string } | null>(null);
+  const [viewingValues, setViewingValues] = useState<{ namespace: string; name: string } | null>(null);
+  const [viewingHistory, setViewingHistory] = useState<{ namespace: string; name: string } | null>(null);
+  const [previewingDiff, setPreviewingDiff] = useState<{ namespace: string; name: string } | null>(null);
+  const [viewingOrder, setViewingOrder] = useState<{ source?: ObjectRef } | null>(null);
+  const [bulkAction, setBulkAction] = useState<BulkAction | null>(null);
+  const [applying, setApplying] = useState(false);
+  const [viewingIncident, setViewingIncident] = useState(false);
+  const [downloadingLogs, setDownloadingLogs] = useState<Set<string>>(new Set());
   const { toasts, removeToast, success, error, info } = useToast();
 
   useEffect(() => {
@@ -36,16 +66,22 @@ const ClusterDetail: React.FC = () => {
   const loadData = async () => {
     if (!id) return;
     try {
-      const [clusterRes, resourcesRes, statsRes] = await Promise.all([
+      const [clusterRes, resourcesRes, statsRes, healthRes, versionRes, capabilitiesRes] = await Promise.all([
         clusterApi.get(id),
         resourceApi.listByCluster(id),
         fluxApi.getStats(id).catch(() => ({ data: null })),
+        fluxApi.getHealth(id).catch(() => ({ data: null })),
+        fluxApi.getVersion(id).catch(() => ({ data: null })),
+        clusterApi.getCapabilities(id).catch(() => ({ data: null })),
       ]);
       setCluster(clusterRes.data);
       setResources(resourcesRes.data);
       if (statsRes.data) {
         setFluxStats(statsRes.data);
       }
+      setFluxHealth(healthRes.data);
+      setFluxVersion(versionRes.data);
+      setCapabilities(capabilitiesRes.data);
     } catch (err) {
       console.error('Failed to load data:', err);
       error('Failed to load cluster data');
@@ -76,15 +112,56 @@ const ClusterDetail: React.FC = () => {
     }
   };
 
-  const handleReconcile = async (resource: FluxResource) => {
+  const handleReconcile = async (resource: FluxResource, withSource = false) => {
     setIsReconciling((prev) => new Set(prev).add(resource.id));
     try {
-      await fluxApi.reconcile(resource.cluster_id, resource.kind, resource.namespace, resource.name);
-      success(`Reconciliation triggered for ${resource.name}`);
-      setTimeout(loadData, 2000);
-    } catch (err) {
+      // Alerts and Providers have no status to wait for
+      if (resource.kind === 'Alert' || resource.kind === 'Provider') {
+        await fluxApi.reconcile(resource.cluster_id, resource.kind, resource.namespace, resource.name);
+        success(`Reconciliation triggered for ${resource.name}`);
+        return;
+      }
+      const result = await fluxApi.reconcileAndWait(
+        resource.cluster_id, resource.kind, resource.namespace, resource.name,
+        (progress) => setReconcileProgress((prev) => ({ ...prev, [resource.id]: progress })),
+        withSource
+      );
+      const source = result.source;
+      if (source) {
+        const revision = source.updated ? `fetched ${source.revision}` : 'no new revision';
+        success(`${source.kind} ${source.name}: ${revision}. ${resource.name} is ready`);
+      } else {
+        success(`${resource.name} reconciled and ready${result.progress.revision ? ` at ${result.progress.revision}` : ''}`);
+      }
+    } catch (err: any) {
       console.error('Failed to reconcile:', err);
-      error(`Failed to trigger reconciliation for ${resource.name}`);
+      error(err.response?.data?.error || err.message || `Failed to reconcile ${resource.name}`);
+    } finally {
+      setIsReconciling((prev) => {
+        const next = new Set(prev);
+        next.delete(resource.id);
+        return next;
+      });
+      setTimeout(() => setReconcileProgress((prev) => {
+        const next = { ...prev };
+        delete next[resource.id];
+        return next;
+      }), 3000);
+      loadData();
+    }
+  };
+
+  const handleRemediate = async (resource: FluxResource, action: 'force' | 'reset') => {
+    setIsReconciling((prev) => new Set(prev).add(resource.id));
+    try {
+      const response = action === 'force'
+        ? await fluxApi.forceReconcileHelmRelease(resource.cluster_id, resource.namespace, resource.name)
+        : await fluxApi.resetHelmReleaseFailures(resource.cluster_id, resource.namespace, resource.name);
+      success(response.data.message);
+      setTimeout(loadData, 1000);
+    } catch (err: any) {
+      console.error(`Failed to ${action} HelmRelease:`, err);
+      error(err.response?.data?.error || `Failed to remediate ${resource.name}`);
     } finally {
       setIsReconciling((prev) => {
         const next = new Set(prev);
@@ -130,11 +207,87 @@ const ClusterDetail: React.FC = () => {
     }
   };
 
+  const saveLogBundle = (data: Blob, fileName: string) => {
+    const url = window.URL.createObjectURL(new Blob([data], { type: 'application/zip' }));
+    const link = document.createElement('a');
+    link.href = url;
+    link.setAttribute('download', fileName);
+    document.body.appendChild(link);
+    link.click();
+    link.remove();
+    window.URL.revokeObjectURL(url);
+  };
+
+  // Downloads a zip of every pod's logs, falling back to a background bundle when the
+  // resource has more containers than the server will stream in one request
+  const handleShowCommit = async (resource: FluxResource) => {
+    try {
+      const { data } = await fluxApi.getSourceCommit(resource.cluster_id, resource.namespace, resource.name);
+      info(`${data.repository}@${data.sha.slice(0, 7)} by ${data.author_name}: ${data.message.split('\n')[0]}`, 10000);
+    } catch (err: any) {
+      error(err.response?.data?.error || `Failed to look up the commit of ${resource.name}`);
+    }
+  };
+
+  const handleDownloadLogs = async (resource: FluxResource) => {
+    setDownloadingLogs((prev) => new Set(prev).add(resource.id));
+    try {
+      try {
+        const response = await resourceApi.downloadWorkloadLogs(resource.cluster_id, resource.kind, resource.namespace, resource.name);
+        saveLogBundle(response.data, `logs-${resource.namespace}-${resource.kind}-${resource.name}.zip`);
+        success(`Downloaded logs for ${resource.name}`);
+        return;
+      } catch (err: any) {
+        if (err.response?.status !== 413) throw err;
+      }
+
+      info(`Collecting logs for ${resource.name} in the background...`);
+      let job = (await resourceApi.createLogBundle(resource.cluster_id, {
+        kind: resource.kind,
+        namespace: resource.namespace,
+        name: resource.name,
+      })).data;
+      while (job.status === 'pending' || job.status === 'running') {
+        await new Promise((resolve) => setTimeout(resolve, 2000));
+        job = (await resourceApi.getLogBundle(job.id)).data;
+      }
+      if (job.status === 'failed') {
+        throw new Error(job.error);
+      }
+      const response = await resourceApi.downloadLogBundle(job.id);
+      saveLogBundle(response.data, job.file_name);
+      success(`Downloaded logs for ${resource.name}`);
+    } catch (err) {
+      console.error('Failed to download logs:', err);
+      error(`Failed to download logs for ${resource.name}`);
+    } finally {
+      setDownloadingLogs((prev) => {
+        const next = new Set(prev);
+        next.delete(resource.id);
+        return next;
+      });
+    }
+  };
+
+  const handleDeleteNotification = async (resource: FluxResource) => {
+    if (!confirm(`Delete ${resource.kind} ${resource.namespace}/${resource.name}? Suspend it instead to pause it temporarily.`)) {
+      return;
+    }
+    try {
+      await fluxApi.deleteNotification(resource.cluster_id, resource.kind, resource.namespace, resource.name);
+      success(`${resource.name} deleted`);
+      loadData();
+    } catch (err) {
+      console.error('Failed to delete:', err);
+      error(`Failed to delete ${resource.name}`);
+    }
+  };
+
   const handleEdit = (resource: FluxResource) => {
     setEditingResource(resource);
   };
 
-  const handleSaveEdit = async (patch: any) => {
+  const handleSaveEdit = async (patch: any, force?: boolean) => {
     if (!editingResource) return;
 
     try {
@@ -143,7 +296,8 @@ const ClusterDetail: React.FC = () => {
         editingResource.kind,
         editingResource.namespace,
         editingResource.name,
-        patch
+        patch,
+        force
       );
       success(`${editingResource.name} updated successfully`);
       setTimeout(loadData, 2000);
@@ -155,9 +309,18 @@ const ClusterDetail: React.FC = () => {
   const handleSync = async () => {
     if (!id) return;
     try {
-      await clusterApi.syncResources(id);
-      info('Cluster sync initiated - refreshing resources...');
-      setTimeout(loadData, 3000);
+      let job = (await clusterApi.syncResources(id)).data;
+      info('Cluster sync queued - resources refresh when it completes');
+      while (job.status === 'pending' || job.status === 'running') {
+        await new Promise((resolve) => setTimeout(resolve, 2000));
+        job = (await clusterApi.getSyncJob(id, job.id)).data;
+      }
+      if (job.status === 'failed') {
+        error(`Cluster sync failed: ${job.error}`);
+        return;
+      }
+      success(`Synced ${job.count} resources`);
+      loadData();
     } catch (err) {
       console.error('Failed to sync:', err);
       error('Failed to sync cluster');
@@ -192,6 +355,11 @@ const ClusterDetail: React.FC = () => {
     }
   };
 
+  // Kinds whose CRDs the cluster lacks get no actions; everything is offered when discovery failed
+  const serves = (kind: string) => !capabilities || capabilities.kinds.some((k) => k.kind === kind && k.installed);
+  const missingKinds = capabilities?.kinds.filter((k) => !k.installed).map((k) => k.kind) ?? [];
+  const bulkKinds = (['Kustomization', 'HelmRelease'] as const).filter(serves);
+
   const filteredResources = activeTab === 'all'
     ? resources
     : resources.filter((r) => r.kind === activeTab);
@@ -254,6 +422,7 @@ const ClusterDetail: React.FC = () => {
             </div>
             <h2>{cluster.name}</h2>
             {cluster.description && <p className="header-subtitle">{cluster.description}</p>}
+            {cluster.server_url && <p className="header-subtitle">API server: {cluster.server_url}</p>}
           </div>
           <div className="header-actions">
             <span className={`status-badge status-${cluster.status}`}>
@@ -263,11 +432,81 @@ const ClusterDetail: React.FC = () => {
               <span className="btn-icon">↻</span>
               Sync Resources
             </button>
+            {bulkKinds.length > 0 && (
+              <>
+                <button className="btn btn-secondary" onClick={() => setViewingOrder({})}>
+                  <span className="btn-icon">⇶</span>
+                  Reconcile Order
+                </button>
+                <button className="btn btn-warning" onClick={() => setBulkAction('suspend')}>
+                  <span className="btn-icon">⏸</span>
+                  Suspend All
+                </button>
+                <button className="btn btn-secondary" onClick={() => setBulkAction('resume')}>
+                  <span className="btn-icon">▶</span>
+                  Resume All
+                </button>
+                <button className="btn btn-secondary" onClick={() => setBulkAction('reconcile')}>
+                  <span className="btn-icon">⟳</span>
+                  Reconcile All
+                </button>
+              </>
+            )}
+            <button className="btn btn-secondary" onClick={() => setApplying(true)}>
+              <span className="btn-icon">⇪</span>
+              Apply Manifests
+            </button>
+            <button className="btn btn-secondary" onClick={() => setViewingIncident(true)}>
+              <span className="btn-icon">🧭</span>
+              Incident View
+            </button>
           </div>
         </div>
       </div>
 
       <div className="content">
+        {/* Flux Controller Health */}
+        {fluxHealth && fluxHealth.crds_installed && (
+          <div className="stats-section">
+            <h3 className="stats-title">
+              Flux Controllers <span className={`flux-health-badge flux-health-${fluxHealth.status}`}>{fluxHealth.status}</span>
+            </h3>
+            <div className="stats-grid flux-controllers">
+              {fluxHealth.controllers.map((controller) => (
+                <div
+                  key={controller.name}
+                  className={`stat-card flux-controller-card ${controller.degraded ? 'flux-controller-degraded' : ''}`}
+                >
+                  <div className="flux-controller-header">
+                    <h4>{controller.name}</h4>
+                    {controller.version && <span className="flux-controller-version">{controller.version}</span>}
+                  </div>
+                  {controller.installed ? (
+                    <div className="flux-controller-detail">
+                      {controller.ready_replicas}/{controller.replicas} available
+                      {controller.restarts > 0 && ` · ${controller.restarts} restarts`}
+                    </div>
+                  ) : (
+                    <div className="flux-controller-detail">Not installed{controller.required ? ' (required)' : ''}</div>
+                  )}
+                  {controller.reason && <div className="flux-controller-reason">{controller.reason}</div>}
+                  {controller.degraded && controller.message && (
+                    <div className="flux-controller-message">{controller.message}</div>
+                  )}
+                </div>
+              ))}
+            </div>
+            {fluxVersion && (
+              <p className="flux-version-summary">
+                {fluxVersion.distribution ? `Flux ${fluxVersion.distribution}` : 'Flux version unknown (not installed by the flux CLI)'}
+                {fluxVersion.components.filter(c => c.installed && !fluxHealth.controllers.some(h => h.name === c.name)).map(c => ` · ${c.name} ${c.version || ''}`)}
+                {fluxVersion.apis.length > 0 && ` · APIs: ${fluxVersion.apis.map(api => `${api.group.split('.')[0]}/${api.preferred}`).join(', ')}`}
+                {missingKinds.length > 0 && ` · Not installed: ${missingKinds.join(', ')}`}
+              </p>
+            )}
+          </div>
+        )}
+
         {/* Flux Stats Cards */}
         {fluxStats && (
           <div className="stats-section">
@@ -359,6 +598,24 @@ const ClusterDetail: React.FC = () => {
             >
               🌳 Tree View
             </button>
+            <button
+              className={`tab ${activeTab === 'quotas' ? 'active' : ''}`}
+              onClick={() => setActiveTab('quotas')}
+            >
+              📏 Quotas
+            </button>
+            <button
+              className={`tab ${activeTab === 'unmanaged' ? 'active' : ''}`}
+              onClick={() => setActiveTab('unmanaged')}
+            >
+              🧹 Unmanaged
+            </button>
+            <button
+              className={`tab ${activeTab === 'images' ? 'active' : ''}`}
+              onClick={() => setActiveTab('images')}
+            >
+              🏷️ Images
+            </button>
             {Object.entries(resourcesByKind)
               .sort(([a], [b]) => a.localeCompare(b))
               .map(([kind, count]) => (
@@ -376,6 +633,18 @@ const ClusterDetail: React.FC = () => {
             <div className="tab-content">
               <ResourceTree clusterId={id!} />
             </div>
+          ) : activeTab === 'quotas' ? (
+            <div className="tab-content">
+              <NamespaceQuotas clusterId={id!} />
+            </div>
+          ) : activeTab === 'unmanaged' ? (
+            <div className="tab-content">
+              <UnmanagedResources clusterId={id!} />
+            </div>
+          ) : activeTab === 'images' ? (
+            <div className="tab-content">
+              <ImageFreshness clusterId={id!} />
+            </div>
           ) : filteredResources.length === 0 ? (
             <div className="empty-state">
               <div className="empty-icon">📦</div>
@@ -405,6 +674,7 @@ const ClusterDetail: React.FC = () => {
                         {groupResources.map((resource) => {
                           const isExpanded = expandedResources.has(resource.id);
                           const isReconcilingNow = isReconciling.has(resource.id);
+                          const progress = reconcileProgress[resource.id];
                           const isSuspendingNow = isSuspending.has(resource.id);
                           const suspended = isSuspended(resource);
                           const children = resourceChildren[resource.id];
@@ -446,59 +716,191 @@ const ClusterDetail: React.FC = () => {
                                         </>
                                       )}
                                     </div>
+                                    {progress && (
+                                      <div className={`reconcile-progress phase-${progress.phase}`} title={progress.message}>
+                                        <div className="reconcile-progress-bar">
+                                          <div className="reconcile-progress-fill" />
+                                        </div>
+                                        <span className="reconcile-progress-text">
+                                          {progress.phase}{progress.reason ? ` · ${progress.reason}` : ''} · {Math.round(progress.elapsed_ms / 1000)}s
+                                        </span>
+                                      </div>
+                                    )}
                                   </div>
                                 </div>
-                                <div className="resource-actions" onClick={(e) => e.stopPropagation()}>
-                                  {resource.kind === 'Kustomization' && (
-                                    <button
-                                      className="btn btn-sm btn-info"
-                                      onClick={() => setKustomizationDetail({
-                                        namespace: resource.namespace,
-                                        name: resource.name
-                                      })}
-                                    >
-                                      📦 View Details
-                                    </button>
-                                  )}
-                                  <button
-                                    className="btn btn-sm btn-secondary"
-                                    onClick={() => handleEdit(resource)}
-                                  >
-                                    ✏️ Edit
-                                  </button>
-                                  {suspended ? (
-                                    <button
-                                      className={`btn btn-sm btn-success ${isSuspendingNow ? 'btn-loading' : ''}`}
-                                      onClick={() => handleResume(resource)}
-                                      disabled={isSuspendingNow}
-                                    >
-                                      {isSuspendingNow ? 'Resuming...' : '▶ Resume'}
-                                    </button>
-                                  ) : (
-                                    <>
+                                {serves(resource.kind) ? (
+                                  <div className="resource-actions" onClick={(e) => e.stopPropagation()}>
+                                    {resource.kind === 'Kustomization' && (
                                       <button
-                                        className={`btn btn-sm btn-primary ${isReconcilingNow ? 'btn-loading' : ''}`}
-                                        onClick={() => handleReconcile(resource)}
-                                        disabled={isReconcilingNow}
+                                        className="btn btn-sm btn-info"
+                                        onClick={() => setKustomizationDetail({
+                                          namespace: resource.namespace,
+                                          name: resource.name
+                                        })}
                                       >
-                                        {isReconcilingNow ? 'Reconciling...' : '↻ Reconcile'}
+                                        📦 View Details
                                       </button>
+                                    )}
+                                    <button
+                                      className="btn btn-sm btn-secondary"
+                                      onClick={() => handleEdit(resource)}
+                                    >
+                                      ✏️ Edit
+                                    </button>
+                                    {suspended ? (
                                       <button
-                                        className="btn btn-sm btn-secondary"
-                                        onClick={() => setViewingDiff({ kind: resource.kind, namespace: resource.namespace, name: resource.name })}
+                                        className={`btn btn-sm btn-success ${isSuspendingNow ? 'btn-loading' : ''}`}
+                                        onClick={() => handleResume(resource)}
+                                        disabled={isSuspendingNow}
                                       >
-                                        🔍 View Diff
+                                        {isSuspendingNow ? 'Resuming...' : '▶ Resume'}
                                       </button>
+                                    ) : (
+                                      <>
+                                        <button
+                                          className={`btn btn-sm btn-primary ${isReconcilingNow ? 'btn-loading' : ''}`}
+                                          onClick={() => handleReconcile(resource)}
+                                          disabled={isReconcilingNow}
+                                        >
+                                          {isReconcilingNow ? 'Reconciling...' : '↻ Reconcile'}
+                                        </button>
+                                        {(resource.kind === 'Kustomization' || resource.kind === 'HelmRelease') && (
+                                          <button
+                                            className="btn btn-sm btn-secondary"
+                                            onClick={() => handleReconcile(resource, true)}
+                                            disabled={isReconcilingNow}
+                                            title="Fetch the latest revision of the source first, like flux reconcile --with-source"
+                                          >
+                                            ↻ With Source
+                                          </button>
+                                        )}
+                                        <button
+                                          className="btn btn-sm btn-secondary"
+                                          onClick={() => setViewingDiff({ kind: resource.kind, namespace: resource.namespace, name: resource.name })}
+                                        >
+                                          🔍 View Diff
+                                        </button>
+                                        <button
+                                          className="btn btn-sm btn-secondary"
+                                          onClick={() => setViewingEvents({ kind: resource.kind, namespace: resource.namespace, name: resource.name })}
+                                        >
+                                          📜 Events
+                                        </button>
+                                        <button
+                                          className="btn btn-sm btn-secondary"
+                                          onClick={() => setViewingYAML({ kind: resource.kind, namespace: resource.namespace, name: resource.name })}
+                                        >
+                                          📄 YAML
+                                        </button>
+                                        {resource.kind === 'HelmRelease' && (
+                                          <>
+                                            <button
+                                              className="btn btn-sm btn-secondary"
+                                              onClick={() => setViewingValues({ namespace: resource.namespace, name: resource.name })}
+                                            >
+                                              ⎈ Values
+                                            </button>
+                                            <button
+                                              className="btn btn-sm btn-secondary"
+                                              onClick={() => setViewingHistory({ namespace: resource.namespace, name: resource.name })}
+                                            >
+                                              🕘 History
+                                            </button>
+                                            <button
+                                              className="btn btn-sm btn-secondary"
+                                              onClick={() => handleRemediate(resource, 'force')}
+                                              disabled={isReconcilingNow}
+                                              title="Run a Helm install or upgrade even if nothing changed, like flux reconcile helmrelease --force"
+                                            >
+                                              ⚡ Force
+                                            </button>
+                                            {resource.status === 'NotReady' && (
+                                              <button
+                                                className="btn btn-sm btn-warning"
+                                                onClick={() => handleRemediate(resource, 'reset')}
+                                                disabled={isReconcilingNow}
+                                                title="Clear the install and upgrade failure counts so remediation retries start over"
+                                              >
+                                                ⟲ Reset Failures
+                                              </button>
+                                            )}
+                                          </>
+                                        )}
+                                        {resource.kind === 'Kustomization' && (
+                                          <button
+                                            className="btn btn-sm btn-secondary"
+                                            onClick={() => setPreviewingDiff({ namespace: resource.namespace, name: resource.name })}
+                                            title="Dry-run the source's current revision against the cluster, like flux diff kustomization"
+                                          >
+                                            ± Dry-run Diff
+                                          </button>
+                                        )}
+                                        {['GitRepository', 'HelmRepository', 'OCIRepository', 'Bucket'].includes(resource.kind) && (
+                                          <button
+                                            className="btn btn-sm btn-secondary"
+                                            onClick={() => setViewingOrder({ source: { kind: resource.kind, namespace: resource.namespace, name: resource.name } })}
+                                            title="Show what reconciles, and in which order, when this source produces a new revision"
+                                          >
+                                            ⇶ Reconcile Order
+                                          </button>
+                                        )}
+                                        {resource.kind === 'GitRepository' && (
+                                          <button
+                                            className="btn btn-sm btn-secondary"
+                                            onClick={() => handleShowCommit(resource)}
+                                            title="Show the GitHub commit this repository last fetched"
+                                          >
+                                            🔗 Commit
+                                          </button>
+                                        )}
+                                        {(resource.kind === 'Kustomization' || resource.kind === 'HelmRelease') && (
+                                          <>
+                                            <button
+                                              className="btn btn-sm btn-secondary"
+                                              onClick={() => setTriaging({ kind: resource.kind, namespace: resource.namespace, name: resource.name })}
+                                            >
+                                              🩺 Triage Pods
+                                            </button>
+                                            <button
+                                              className="btn btn-sm btn-secondary"
+                                              onClick={() => setSearchingLogs({ kind: resource.kind, namespace: resource.namespace, name: resource.name })}
+                                            >
+                                              🔎 Search Logs
+                                            </button>
+                                            <button
+                                              className={`btn btn-sm btn-secondary ${downloadingLogs.has(resource.id) ? 'btn-loading' : ''}`}
+                                              onClick={() => handleDownloadLogs(resource)}
+                                              disabled={downloadingLogs.has(resource.id)}
+                                            >
+                                              {downloadingLogs.has(resource.id) ? 'Downloading...' : '📦 Download Logs'}
+                                            </button>
+                                          </>
+                                        )}
+                                        <button
+                                          className={`btn btn-sm btn-warning ${isSuspendingNow ? 'btn-loading' : ''}`}
+                                          onClick={() => handleSuspend(resource)}
+                                          disabled={isSuspendingNow}
+                                        >
+                                          {isSuspendingNow ? 'Suspending...' : '⏸ Suspend'}
+                                        </button>
+                                      </>
+                                    )}
+                                    {['Alert', 'Provider', 'Receiver'].includes(resource.kind) && (
                                       <button
-                                        className={`btn btn-sm btn-warning ${isSuspendingNow ? 'btn-loading' : ''}`}
-                                        onClick={() => handleSuspend(resource)}
-                                        disabled={isSuspendingNow}
+                                        className="btn btn-sm btn-danger"
+                                        onClick={() => handleDeleteNotification(resource)}
                                       >
-                                        {isSuspendingNow ? 'Suspending...' : '⏸ Suspend'}
+                                        🗑 Delete
                                       </button>
-                                    </>
-                                  )}
-                                </div>
+                                    )}
+                                  </div>
+                                ) : (
+                                  <div className="resource-actions">
+                                    <span className="crd-missing" title="The cluster does not serve this kind, so it cannot be changed">
+                                      {resource.kind} CRD not installed
+                                    </span>
+                                  </div>
+                                )}
                               </div>
 
                               {isExpanded && (
@@ -601,6 +1003,109 @@ const ClusterDetail: React.FC = () => {
           onClose={() => setViewingDiff(null)}
         />
       )}
+
+      {triaging && id && (
+        <PodTriage
+          clusterId={id}
+          kind={triaging.kind}
+          namespace={triaging.namespace}
+          name={triaging.name}
+          onClose={() => setTriaging(null)}
+        />
+      )}
+
+      {searchingLogs && id && (
+        <LogSearch
+          clusterId={id}
+          kind={searchingLogs.kind}
+          namespace={searchingLogs.namespace}
+          name={searchingLogs.name}
+          onClose={() => setSearchingLogs(null)}
+        />
+      )}
+
+      {viewingEvents && id && (
+        <ResourceEvents
+          clusterId={id}
+          kind={viewingEvents.kind}
+          namespace={viewingEvents.namespace}
+          name={viewingEvents.name}
+          onClose={() => setViewingEvents(null)}
+        />
+      )}
+
+      {viewingValues && id && (
+        <HelmValues
+          clusterId={id}
+          namespace={viewingValues.namespace}
+          name={viewingValues.name}
+          onClose={() => setViewingValues(null)}
+        />
+      )}
+
+      {viewingHistory && id && (
+        <HelmHistory
+          clusterId={id}
+          namespace={viewingHistory.namespace}
+          name={viewingHistory.name}
+          onClose={() => setViewingHistory(null)}
+        />
+      )}
+
+      {previewingDiff && id && (
+        <KustomizationDiff
+          clusterId={id}
+          namespace={previewingDiff.namespace}
+          name={previewingDiff.name}
+          onClose={() => setPreviewingDiff(null)}
+        />
+      )}
+
+      {viewingOrder && id && (
+        <ReconcileOrder
+          clusterId={id}
+          source={viewingOrder.source}
+          onClose={() => setViewingOrder(null)}
+        />
+      )}
+
+      {viewingYAML && id && (
+        <ResourceYAML
+          clusterId={id}
+          kind={viewingYAML.kind}
+          namespace={viewingYAML.namespace}
+          name={viewingYAML.name}
+          onClose={() => setViewingYAML(null)}
+        />
+      )}
+
+      {bulkAction && id && (
+        <BulkSuspend
+          clusterId={id}
+          clusterName={cluster.name}
+          action={bulkAction}
+          availableKinds={bulkKinds}
+          onClose={() => setBulkAction(null)}
+          onDone={loadData}
+        />
+      )}
+
+      {applying && id && (
+        <ApplyManifests
+          clusterId={id}
+          clusterName={cluster.name}
+          onClose={() => setApplying(false)}
+          onApplied={loadData}
+        />
+      )}
+
+      {viewingIncident && id && (
+        <IncidentFeed
+          clusterId={id}
+          clusterName={cluster.name}
+          onClose={() => setViewingIncident(false)}
+        />
+      )}
     </div>
   );
 };
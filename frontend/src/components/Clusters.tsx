@@ -1,29 +1,45 @@
 import React, { useState, useEffect } from 'react';
 import { clusterApi } from '../api';
-import { Cluster } from '../types';
+import { Cluster, ClusterImportResult } from '../types';
 import { useNavigate } from 'react-router-dom';
 import { useToast } from '../hooks/useToast';
 import Toast from './Toast';
 
+// Compares version strings like v2.3.0 numerically, part by part
+const compareVersions = (a: string, b: string) => {
+  const pa = a.replace(/^v/, '').split(/[.-]/).map(part => parseInt(part, 10) || 0);
+  const pb = b.replace(/^v/, '').split(/[.-]/).map(part => parseInt(part, 10) || 0);
+  for (let i = 0; i < Math.max(pa.length, pb.length); i++) {
+    if ((pa[i] || 0) !== (pb[i] || 0)) return (pa[i] || 0) - (pb[i] || 0);
+  }
+  return 0;
+};
+
 const Clusters: React.FC = () => {
   const [clusters, setClusters] = useState<Cluster[]>([]);
   const [loading, setLoading] = useState(true);
   const [showModal, setShowModal] = useState(false);
+  const [showArchived, setShowArchived] = useState(false);
   const [formData, setFormData] = useState({
     name: '',
     description: '',
     kubeconfig: '',
   });
+  // Contexts found in a pasted kubeconfig; with more than one, each selected context is
+  // imported as a cluster of its own
+  const [contexts, setContexts] = useState<ClusterImportResult[] | null>(null);
+  const [selectedContexts, setSelectedContexts] = useState<string[]>([]);
+  const multiContext = contexts !== null && contexts.length > 1;
   const navigate = useNavigate();
   const { toasts, removeToast, success, error, info } = useToast();
 
   useEffect(() => {
     loadClusters();
-  }, []);
+  }, [showArchived]);
 
   const loadClusters = async () => {
     try {
-      const response = await clusterApi.list();
+      const response = await clusterApi.list(showArchived);
       setClusters(response.data);
     } catch (err) {
       console.error('Failed to load clusters:', err);
@@ -33,15 +49,74 @@ const Clusters: React.FC = () => {
     }
   };
 
-  const handleSubmit = async (e: React.FormEvent) => {
+  const closeModal = () => {
+    setShowModal(false);
+    setFormData({ name: '', description: '', kubeconfig: '' });
+    setContexts(null);
+    setSelectedContexts([]);
+  };
+
+  const handleFindContexts = async () => {
+    try {
+      const response = await clusterApi.import({ kubeconfig: formData.kubeconfig, dry_run: true });
+      const results = response.data.results;
+      setContexts(results);
+      // Contexts whose API server is already registered start unselected
+      setSelectedContexts(results.filter(r => !r.existing_cluster && !r.error).map(r => r.context.name));
+      if (results.length === 1) {
+        info('The kubeconfig has a single context; add it as one cluster');
+      }
+    } catch (err: any) {
+      error(err.response?.data?.error || 'Failed to read kubeconfig contexts');
+    }
+  };
+
+  const toggleContext = (name: string) => {
+    setSelectedContexts(prev => prev.includes(name) ? prev.filter(n => n !== name) : [...prev, name]);
+  };
+
+  const handleImport = async () => {
+    try {
+      const response = await clusterApi.import({
+        kubeconfig: formData.kubeconfig,
+        description: formData.description,
+        contexts: selectedContexts,
+      });
+      const failed = response.data.results.filter(r => r.error);
+      if (response.data.imported > 0) {
+        success(`Imported ${response.data.imported} cluster(s)`);
+      }
+      if (failed.length > 0) {
+        error(failed.map(r => `${r.context.name}: ${r.error}`).join('; '));
+      } else {
+        closeModal();
+      }
+      loadClusters();
+    } catch (err: any) {
+      console.error('Failed to import clusters:', err);
+      error(err.response?.data?.error || 'Failed to import clusters');
+    }
+  };
+
+  const handleSubmit = async (e: React.FormEvent, allowDuplicate = false) => {
     e.preventDefault();
+    if (multiContext) {
+      await handleImport();
+      return;
+    }
     try {
-      await clusterApi.create(formData);
-      setShowModal(false);
-      setFormData({ name: '', description: '', kubeconfig: '' });
+      await clusterApi.create({ ...formData, allow_duplicate: allowDuplicate });
+      closeModal();
       success(`Cluster "${formData.name}" created successfully`);
       loadClusters();
-    } catch (err) {
+    } catch (err: any) {
+      const existing: Cluster | undefined = err.response?.status === 409 ? err.response.data?.existing_cluster : undefined;
+      if (existing) {
+        if (confirm(`This API server is already registered as cluster "${existing.name}". Add it again anyway?`)) {
+          await handleSubmit(e, true);
+        }
+        return;
+      }
       console.error('Failed to create cluster:', err);
       error('Failed to create cluster. Please check your kubeconfig.');
     }
@@ -63,13 +138,30 @@ const Clusters: React.FC = () => {
   const handleSync = async (id: string) => {
     try {
       await clusterApi.syncResources(id);
-      info('Sync triggered successfully');
+      info('Sync queued');
     } catch (err) {
       console.error('Failed to sync cluster:', err);
       error('Failed to sync cluster');
     }
   };
 
+  const handleToggleArchived = async (cluster: Cluster) => {
+    if (!cluster.archived && !confirm(`Archive cluster "${cluster.name}"? It will stop syncing and be hidden from the cluster list. Credentials and history are kept.`)) return;
+    try {
+      if (cluster.archived) {
+        await clusterApi.unarchive(cluster.id);
+        success(`Cluster "${cluster.name}" restored`);
+      } else {
+        await clusterApi.archive(cluster.id);
+        success(`Cluster "${cluster.name}" archived`);
+      }
+      loadClusters();
+    } catch (err) {
+      console.error('Failed to update archive state:', err);
+      error(`Failed to ${cluster.archived ? 'unarchive' : 'archive'} cluster`);
+    }
+  };
+
   const handleToggleFavorite = async (id: string, e: React.MouseEvent) => {
     e.stopPropagation();
     try {
@@ -107,6 +199,13 @@ const Clusters: React.FC = () => {
     return a.name.localeCompare(b.name);
   });
 
+  // Clusters running an older Flux release than the newest in the fleet are flagged
+  const latestFluxVersion = clusters
+    .map(c => c.flux_version)
+    .filter((v): v is string => !!v)
+    .sort(compareVersions)
+    .pop();
+
   if (loading) {
     return <div className="loading">Loading clusters...</div>;
   }
@@ -120,9 +219,19 @@ const Clusters: React.FC = () => {
           <h2>🗄️ Clusters</h2>
           <p>Manage your Kubernetes clusters and monitor their health</p>
         </div>
-        <button className="btn btn-primary" onClick={() => setShowModal(true)}>
-          + Add Cluster
-        </button>
+        <div style={{ display: 'flex', alignItems: 'center', gap: '12px' }}>
+          <label style={{ display: 'flex', alignItems: 'center', gap: '6px', cursor: 'pointer' }}>
+            <input
+              type="checkbox"
+              checked={showArchived}
+              onChange={(e) => setShowArchived(e.target.checked)}
+            />
+            Show archived
+          </label>
+          <button className="btn btn-primary" onClick={() => setShowModal(true)}>
+            + Add Cluster
+          </button>
+        </div>
       </div>
 
       <div className="dashboard-content">{clusters.length === 0 ? (
@@ -136,7 +245,7 @@ const Clusters: React.FC = () => {
         ) : (
           <div className="grid">
             {sortedClusters.map((cluster) => (
-              <div key={cluster.id} className="cluster-card" onClick={() => navigate(`/clusters/${cluster.id}`)}>
+              <div key={cluster.id} className="cluster-card" style={cluster.archived ? { opacity: 0.6 } : undefined} onClick={() => navigate(`/clusters/${cluster.id}`)}>
                 <div style={{ display: 'flex', justifyContent: 'space-between', alignItems: 'start' }}>
                   <div style={{ flex: 1 }}>
                     <div style={{ display: 'flex', alignItems: 'center', gap: '8px', marginBottom: '8px' }}>
@@ -148,9 +257,24 @@ const Clusters: React.FC = () => {
                         {cluster.is_favorite ? '⭐' : '☆'}
                       </button>
                       <h4 style={{ margin: 0 }}>{cluster.name}</h4>
+                      {cluster.archived && (
+                        <span className="status-badge status-unknown" title={cluster.archived_at ? `Archived ${new Date(cluster.archived_at).toLocaleString()}` : 'Archived'}>
+                          archived
+                        </span>
+                      )}
                       {cluster.source === 'azure-aks' && (
                         <span className="source-badge" title="Azure AKS">☁️</span>
                       )}
+                      {cluster.flux_version && (
+                        <span
+                          className={`flux-version-badge ${latestFluxVersion && compareVersions(cluster.flux_version, latestFluxVersion) < 0 ? 'flux-version-outdated' : ''}`}
+                          title={latestFluxVersion && compareVersions(cluster.flux_version, latestFluxVersion) < 0
+                            ? `Flux ${cluster.flux_version}; other clusters run ${latestFluxVersion}`
+                            : `Flux ${cluster.flux_version}`}
+                        >
+                          Flux {cluster.flux_version}
+                        </span>
+                      )}
                       {cluster.resource_count !== undefined && cluster.resource_count > 0 && (
                         <span className="resource-count-badge" title={`${cluster.resource_count} resources`}>
                           {cluster.resource_count}
@@ -164,15 +288,17 @@ const Clusters: React.FC = () => {
                   </span>
                 </div>
                 <div style={{ marginTop: '15px', display: 'flex', gap: '8px', flexWrap: 'wrap' }}>
-                  <button
-                    className="btn btn-sm btn-success"
-                    onClick={(e) => {
-                      e.stopPropagation();
-                      handleSync(cluster.id);
-                    }}
-                  >
-                    Sync
-                  </button>
+                  {!cluster.archived && (
+                    <button
+                      className="btn btn-sm btn-success"
+                      onClick={(e) => {
+                        e.stopPropagation();
+                        handleSync(cluster.id);
+                      }}
+                    >
+                      Sync
+                    </button>
+                  )}
                   <button
                     className="btn btn-sm"
                     onClick={(e) => handleExport(cluster.id, 'json', e)}
@@ -187,6 +313,15 @@ const Clusters: React.FC = () => {
                   >
                     📥 CSV
                   </button>
+                  <button
+                    className="btn btn-sm btn-secondary"
+                    onClick={(e) => {
+                      e.stopPropagation();
+                      handleToggleArchived(cluster);
+                    }}
+                  >
+                    {cluster.archived ? 'Unarchive' : 'Archive'}
+                  </button>
                   <button
                     className="btn btn-sm btn-danger"
                     onClick={(e) => {
@@ -204,20 +339,22 @@ const Clusters: React.FC = () => {
       </div>
 
       {showModal && (
-        <div className="modal-overlay" onClick={() => setShowModal(false)}>
+        <div className="modal-overlay" onClick={closeModal}>
           <div className="modal" onClick={(e) => e.stopPropagation()}>
             <h3>Add New Cluster</h3>
             <form onSubmit={handleSubmit}>
-              <div className="form-group">
-                <label>Cluster Name *</label>
-                <input
-                  type="text"
-                  className="form-control"
-                  value={formData.name}
-                  onChange={(e) => setFormData({ ...formData, name: e.target.value })}
-                  required
-                />
-              </div>
+              {!multiContext && (
+                <div className="form-group">
+                  <label>Cluster Name *</label>
+                  <input
+                    type="text"
+                    className="form-control"
+                    value={formData.name}
+                    onChange={(e) => setFormData({ ...formData, name: e.target.value })}
+                    required
+                  />
+                </div>
+              )}
               <div className="form-group">
                 <label>Description</label>
                 <input
@@ -232,17 +369,53 @@ const Clusters: React.FC = () => {
                 <textarea
                   className="form-control"
                   value={formData.kubeconfig}
-                  onChange={(e) => setFormData({ ...formData, kubeconfig: e.target.value })}
+                  onChange={(e) => {
+                    setFormData({ ...formData, kubeconfig: e.target.value });
+                    setContexts(null);
+                  }}
                   required
                   placeholder="Paste your kubeconfig content here..."
                 />
+                <button
+                  type="button"
+                  className="btn btn-secondary"
+                  style={{ marginTop: '8px' }}
+                  onClick={handleFindContexts}
+                  disabled={!formData.kubeconfig}
+                >
+                  Find Contexts
+                </button>
               </div>
+              {multiContext && (
+                <div className="form-group">
+                  <label>Contexts to import (each becomes a cluster named after its context)</label>
+                  {contexts.map(result => (
+                    <label
+                      key={result.context.name}
+                      style={{ display: 'flex', alignItems: 'center', gap: '6px', cursor: 'pointer' }}
+                    >
+                      <input
+                        type="checkbox"
+                        checked={selectedContexts.includes(result.context.name)}
+                        onChange={() => toggleContext(result.context.name)}
+                        disabled={!!result.error}
+                      />
+                      <span>
+                        <strong>{result.context.name}</strong> {result.context.server}
+                        {result.context.current && ' (current)'}
+                        {result.existing_cluster && ` (already registered as "${result.existing_cluster.name}")`}
+                        {result.error && ` (${result.error})`}
+                      </span>
+                    </label>
+                  ))}
+                </div>
+              )}
               <div className="modal-actions">
-                <button type="button" className="btn btn-secondary" onClick={() => setShowModal(false)}>
+                <button type="button" className="btn btn-secondary" onClick={closeModal}>
                   Cancel
                 </button>
-                <button type="submit" className="btn btn-primary">
-                  Add Cluster
+                <button type="submit" className="btn btn-primary" disabled={multiContext && selectedContexts.length === 0}>
+                  {multiContext ? `Import ${selectedContexts.length} Cluster(s)` : 'Add Cluster'}
                 </button>
               </div>
             </form>
@@ -1,8 +1,12 @@
 import React, { useState, useEffect } from 'react';
 import { useNavigate } from 'react-router-dom';
 import { resourceApi, clusterApi } from '../api';
-import { FluxResource, Cluster } from '../types';
+import { FluxResource, Cluster, OrchestratorEvent } from '../types';
+import { useToast } from '../hooks/useToast';
+import { useEventStream } from '../hooks/useEventStream';
 import ActivityFeed from './ActivityFeed';
+import Onboarding from './Onboarding';
+import Toast from './Toast';
 import '../styles/Dashboard.css';
 
 const Dashboard: React.FC = () => {
@@ -13,6 +17,7 @@ const Dashboard: React.FC = () => {
   const [statusFilter, setStatusFilter] = useState<string>('all');
   const [kindFilter, setKindFilter] = useState<string>('all');
   const [clusterFilter, setClusterFilter] = useState<string>('all');
+  const { toasts, removeToast, showToast } = useToast();
 
   useEffect(() => {
     loadData();
@@ -20,6 +25,17 @@ const Dashboard: React.FC = () => {
     return () => clearInterval(interval);
   }, []);
 
+  // Surface health transitions as they happen, including those replayed after a reconnect
+  useEventStream((event: OrchestratorEvent, replayed: boolean) => {
+    if (event.type !== 'cluster.health.changed' && event.type !== 'flux.status.changed') {
+      return;
+    }
+    const cluster = clusters.find(c => c.id === event.cluster_id)?.name ?? event.cluster_id;
+    const when = replayed ? ` (${new Date(event.timestamp).toLocaleTimeString()})` : '';
+    showToast(`${cluster}: ${event.message}${when}`, event.severity);
+    loadData();
+  });
+
   const loadData = async () => {
     try {
       const [resourcesRes, clustersRes] = await Promise.all([
@@ -128,6 +144,8 @@ const Dashboard: React.FC = () => {
         </button>
       </div>
 
+      <Onboarding />
+
       {/* Quick Filters */}
       <div className="quick-filters">
         <button 
@@ -359,6 +377,8 @@ const Dashboard: React.FC = () => {
           <p>Add a cluster and sync to see Flux resources</p>
         </div>
       )}
+
+      <Toast toasts={toasts} removeToast={removeToast} />
     </div>
   );
 };
@@ -371,6 +391,9 @@ function getKindIcon(kind: string): string {
     HelmRepository: '📊',
     OCIRepository: '🗂️',
     Bucket: '🪣',
+    Alert: '🔔',
+    Provider: '📣',
+    Receiver: '📥',
   };
   return iconMap[kind] || '📄';
 }
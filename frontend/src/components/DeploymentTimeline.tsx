@@ -0,0 +1,142 @@
+import React, { useState, useEffect } from 'react';
+import { clusterApi, timelineApi } from '../api';
+import { Cluster, DeploymentEvent, TimelineParams } from '../types';
+import '../styles/Dashboard.css';
+import '../styles/DeploymentTimeline.css';
+
+const windows: Record<string, number> = { '15m': 15, '30m': 30, '1h': 60, '3h': 180 };
+
+const typeLabels: Record<DeploymentEvent['type'], string> = {
+  artifact: 'new artifact',
+  applied: 'applied',
+  chart: 'chart upgraded',
+};
+
+// Lists the revisions Flux deployed across clusters, newest first, to answer "what changed
+// around 14:05" during an incident
+const DeploymentTimeline: React.FC = () => {
+  const [clusters, setClusters] = useState<Cluster[]>([]);
+  const [clusterId, setClusterId] = useState('');
+  const [kind, setKind] = useState('');
+  const [around, setAround] = useState('');
+  const [windowSize, setWindowSize] = useState('30m');
+  const [events, setEvents] = useState<DeploymentEvent[]>([]);
+  const [truncated, setTruncated] = useState(false);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    clusterApi.list().then(response => setClusters(response.data)).catch(() => setClusters([]));
+  }, []);
+
+  useEffect(() => {
+    loadTimeline();
+  }, [clusterId, kind, around, windowSize]);
+
+  const loadTimeline = async () => {
+    const params: TimelineParams = { cluster_id: clusterId || undefined, kind: kind || undefined };
+    if (around) {
+      // datetime-local values are in the browser's time zone
+      const center = new Date(around).getTime();
+      const span = windows[windowSize] * 60000;
+      params.since = new Date(center - span).toISOString();
+      params.until = new Date(center + span).toISOString();
+    }
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await timelineApi.list(params);
+      setEvents(response.data.events);
+      setTruncated(response.data.truncated);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load timeline');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  // Events are newest first; a day heading goes before the first event of each day
+  const dayOf = (timestamp: string) => new Date(timestamp).toLocaleDateString();
+
+  return (
+    <div className="dashboard">
+      <div className="dashboard-header">
+        <div>
+          <h2>🕒 Deployment Timeline</h2>
+          <p>Revisions deployed by Flux across clusters</p>
+        </div>
+      </div>
+
+      <div className="dashboard-content">
+        <div className="dashboard-card">
+          <div className="timeline-filters">
+            <select value={clusterId} onChange={e => setClusterId(e.target.value)}>
+              <option value="">All clusters</option>
+              {clusters.map(cluster => (
+                <option key={cluster.id} value={cluster.id}>{cluster.name}</option>
+              ))}
+            </select>
+            <select value={kind} onChange={e => setKind(e.target.value)}>
+              <option value="">All kinds</option>
+              {['Kustomization', 'HelmRelease', 'GitRepository', 'OCIRepository', 'Bucket', 'HelmChart'].map(k => (
+                <option key={k} value={k}>{k}</option>
+              ))}
+            </select>
+            <label>
+              Around
+              <input type="datetime-local" value={around} onChange={e => setAround(e.target.value)} />
+            </label>
+            {around ? (
+              <>
+                <select value={windowSize} onChange={e => setWindowSize(e.target.value)}>
+                  {Object.keys(windows).map(w => (
+                    <option key={w} value={w}>± {w}</option>
+                  ))}
+                </select>
+                <button className="btn btn-sm btn-secondary" onClick={() => setAround('')}>Last 24 hours</button>
+              </>
+            ) : (
+              <span className="timeline-range">Last 24 hours</span>
+            )}
+          </div>
+
+          {loading && <div className="loading">Loading timeline...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {!loading && !error && events.length === 0 && (
+            <div className="timeline-empty">No deployments in this window</div>
+          )}
+
+          {!loading && !error && events.length > 0 && (
+            <ol className="timeline-list">
+              {events.map((event, i) => (
+                <React.Fragment key={event.id}>
+                  {(i === 0 || dayOf(events[i - 1].recorded_at) !== dayOf(event.recorded_at)) && (
+                    <li className="timeline-day">{dayOf(event.recorded_at)}</li>
+                  )}
+                  <li className={`timeline-event timeline-${event.type}`}>
+                    <span className="timeline-time">{new Date(event.recorded_at).toLocaleTimeString()}</span>
+                    <span className="timeline-cluster">{event.cluster_name}</span>
+                    <span className="timeline-resource">
+                      <span className="badge">{event.kind}</span> {event.namespace}/{event.name}
+                    </span>
+                    <span className="timeline-type">{typeLabels[event.type]}</span>
+                    <span className="timeline-revision">
+                      {event.old_revision && <><code>{event.old_revision}</code> → </>}
+                      <code>{event.new_revision}</code>
+                    </span>
+                  </li>
+                </React.Fragment>
+              ))}
+            </ol>
+          )}
+
+          {truncated && <p className="timeline-range">Only the newest events are shown; narrow the window to see the rest.</p>}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default DeploymentTimeline;
@@ -1,17 +1,30 @@
 import React, { useState, useEffect } from 'react';
-import { FluxResource } from '../types';
+import { FluxResource, SourceRefs } from '../types';
+import { fluxApi } from '../api';
 import '../styles/FluxResourceEditDialog.css';
 
 interface FluxResourceEditDialogProps {
   resource: FluxResource;
   onClose: () => void;
-  onSave: (updates: any) => Promise<void>;
+  onSave: (updates: any, force?: boolean) => Promise<void>;
 }
 
 const FluxResourceEditDialog: React.FC<FluxResourceEditDialogProps> = ({ resource, onClose, onSave }) => {
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
+  // The patch that conflicted with another field manager, kept to retry it with force
+  const [conflictPatch, setConflictPatch] = useState<any>(null);
   const [formData, setFormData] = useState<Record<string, any>>({});
+  // Branches and tags of the remote, suggested for the ref fields of GitRepositories
+  const [refs, setRefs] = useState<SourceRefs | null>(null);
+  const [refsError, setRefsError] = useState<string | null>(null);
+
+  useEffect(() => {
+    if (resource.kind !== 'GitRepository') return;
+    fluxApi.getSourceRefs(resource.cluster_id, resource.kind, resource.namespace, resource.name)
+      .then(({ data }) => setRefs(data))
+      .catch((err: any) => setRefsError(err.response?.data?.error || 'Failed to list branches and tags'));
+  }, [resource]);
 
   useEffect(() => {
     // Parse metadata to get current spec
@@ -61,10 +74,28 @@ const FluxResourceEditDialog: React.FC<FluxResourceEditDialogProps> = ({ resourc
     setFormData(prev => ({ ...prev, [field]: value }));
   };
 
+  const save = async (patch: any, force: boolean) => {
+    setLoading(true);
+    setError(null);
+    setConflictPatch(null);
+
+    try {
+      await onSave(patch, force);
+      onClose();
+    } catch (err: any) {
+      setError(err.response?.data?.error || err.message || 'Failed to update resource');
+      if (err.response?.status === 409) {
+        setConflictPatch(patch);
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
-    setLoading(true);
     setError(null);
+    setConflictPatch(null);
 
     try {
       // Build the patch based on resource kind
@@ -112,12 +143,9 @@ const FluxResourceEditDialog: React.FC<FluxResourceEditDialogProps> = ({ resourc
         patch.spec.interval = formData.interval;
       }
 
-      await onSave(patch);
-      onClose();
+      await save(patch, false);
     } catch (err: any) {
-      setError(err.response?.data?.error || err.message || 'Failed to update resource');
-    } finally {
-      setLoading(false);
+      setError(err.message || 'Failed to update resource');
     }
   };
 
@@ -130,7 +158,17 @@ const FluxResourceEditDialog: React.FC<FluxResourceEditDialogProps> = ({ resourc
         </div>
 
         {error && (
-          <div className="dialog-error">{error}</div>
+          <div className="dialog-error">
+            {error}
+            {conflictPatch && (
+              <div className="dialog-conflict-actions">
+                <span>Force Save takes these fields over from the other manager, which may set them back on its next reconcile.</span>
+                <button type="button" className="btn btn-danger" onClick={() => save(conflictPatch, true)} disabled={loading}>
+                  Force Save
+                </button>
+              </div>
+            )}
+          </div>
         )}
 
         <form onSubmit={handleSubmit}>
@@ -166,7 +204,12 @@ const FluxResourceEditDialog: React.FC<FluxResourceEditDialogProps> = ({ resourc
                     value={formData.ref_branch || ''}
                     onChange={(e) => handleChange('ref_branch', e.target.value)}
                     placeholder="main"
+                    list="git-ref-branches"
                   />
+                  <datalist id="git-ref-branches">
+                    {refs?.branches.map((ref) => <option key={ref.name} value={ref.name} />)}
+                  </datalist>
+                  {refsError && <small className="form-hint">{refsError}</small>}
                 </div>
 
                 <div className="form-group">
@@ -176,7 +219,11 @@ const FluxResourceEditDialog: React.FC<FluxResourceEditDialogProps> = ({ resourc
                     className="form-control"
                     value={formData.ref_tag || ''}
                     onChange={(e) => handleChange('ref_tag', e.target.value)}
+                    list="git-ref-tags"
                   />
+                  <datalist id="git-ref-tags">
+                    {refs?.tags.map((ref) => <option key={ref.name} value={ref.name} />)}
+                  </datalist>
                 </div>
 
                 <div className="form-group">
@@ -0,0 +1,155 @@
+import React, { useState, useEffect } from 'react';
+import { fluxApi } from '../api';
+import { HelmReleaseHistory, HelmRevision } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+
+interface HelmHistoryProps {
+  clusterId: string;
+  namespace: string;
+  name: string;
+  onClose: () => void;
+}
+
+// Lists the Helm release revisions of a HelmRelease and rolls back to one of them
+const HelmHistory: React.FC<HelmHistoryProps> = ({ clusterId, namespace, name, onClose }) => {
+  const [history, setHistory] = useState<HelmReleaseHistory | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [notice, setNotice] = useState<string | null>(null);
+  const [rollingBack, setRollingBack] = useState<number | null>(null);
+  const [expanded, setExpanded] = useState<number | null>(null);
+
+  useEffect(() => {
+    loadHistory();
+  }, [clusterId, namespace, name]);
+
+  const loadHistory = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await fluxApi.getHelmHistory(clusterId, namespace, name);
+      setHistory(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load release history');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleRollback = async (revision: HelmRevision) => {
+    if (!confirm(`Roll ${name} back to revision ${revision.revision} (chart ${revision.chart_version})? ` +
+      'If the HelmRelease is applied from Git, the next Kustomization sync will undo this unless Git is reverted too.')) return;
+    try {
+      setRollingBack(revision.revision);
+      setError(null);
+      const response = await fluxApi.rollbackHelmRelease(clusterId, namespace, name, revision.revision);
+      setNotice(response.data.message);
+      await loadHistory();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Rollback failed');
+    } finally {
+      setRollingBack(null);
+    }
+  };
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Release History</h2>
+            <div className="resource-info">
+              <span className="badge">HelmRelease</span>
+              <span>{namespace}/{name}</span>
+              {history && <span className="triage-init">release {history.storage_namespace}/{history.release_name}</span>}
+            </div>
+          </div>
+          <div className="triage-controls">
+            <button className="btn btn-sm btn-secondary" onClick={loadHistory} disabled={loading}>
+              ↻ Refresh
+            </button>
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <div className="triage-body">
+          {loading && <div className="loading">Loading history...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {notice && <p className="helm-values-hint">{notice}</p>}
+
+          {!loading && history && history.revisions.length === 0 && (
+            <div className="triage-no-events">No revisions found. The release has not been installed yet.</div>
+          )}
+
+          {!loading && history && history.revisions.length > 0 && (
+            <>
+              {history.values_redacted && (
+                <p className="helm-values-hint">Values are hidden; viewing them needs the secret.reveal permission.</p>
+              )}
+              <table className="triage-containers">
+                <thead>
+                  <tr>
+                    <th>Revision</th>
+                    <th>Status</th>
+                    <th>Chart</th>
+                    <th>App version</th>
+                    <th>Deployed</th>
+                    <th>Description</th>
+                    <th></th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {history.revisions.map((revision, idx) => (
+                    <React.Fragment key={revision.revision}>
+                      <tr>
+                        <td>{revision.revision}</td>
+                        <td className={`helm-values-status ${revision.status === 'failed' ? 'error' : revision.status === 'deployed' ? 'applied' : 'skipped'}`}>
+                          {revision.status}
+                        </td>
+                        <td>{revision.chart_name}@{revision.chart_version}</td>
+                        <td>{revision.app_version || '-'}</td>
+                        <td>{revision.last_deployed ? new Date(revision.last_deployed).toLocaleString() : '-'}</td>
+                        <td>{revision.description || '-'}</td>
+                        <td>
+                          {revision.values && (
+                            <button
+                              className="btn btn-sm btn-secondary"
+                              onClick={() => setExpanded(expanded === revision.revision ? null : revision.revision)}
+                            >
+                              Values
+                            </button>
+                          )}
+                          {idx > 0 && (
+                            <button
+                              className="btn btn-sm btn-warning"
+                              onClick={() => handleRollback(revision)}
+                              disabled={rollingBack !== null}
+                            >
+                              {rollingBack === revision.revision ? 'Rolling back...' : '↩ Rollback'}
+                            </button>
+                          )}
+                        </td>
+                      </tr>
+                      {expanded === revision.revision && revision.values && (
+                        <tr>
+                          <td colSpan={7}>
+                            <pre className="helm-values-yaml">{JSON.stringify(revision.values, null, 2)}</pre>
+                          </td>
+                        </tr>
+                      )}
+                    </React.Fragment>
+                  ))}
+                </tbody>
+              </table>
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default HelmHistory;
@@ -0,0 +1,143 @@
+import React, { useState, useEffect } from 'react';
+import { fluxApi } from '../api';
+import { HelmReleaseValues, ValuesSource } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+
+interface HelmValuesProps {
+  clusterId: string;
+  namespace: string;
+  name: string;
+  onClose: () => void;
+}
+
+const sourceLabel = (source: ValuesSource) =>
+  source.kind === 'HelmRelease' ? 'spec.values' : `${source.kind}/${source.name}`;
+
+// Shows the values a HelmRelease passes to Helm and which valuesFrom reference or inline value set each key
+const HelmValues: React.FC<HelmValuesProps> = ({ clusterId, namespace, name, onClose }) => {
+  const [result, setResult] = useState<HelmReleaseValues | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [filter, setFilter] = useState('');
+
+  useEffect(() => {
+    loadValues();
+  }, [clusterId, namespace, name]);
+
+  const loadValues = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await fluxApi.getHelmValues(clusterId, namespace, name);
+      setResult(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to resolve values');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const keys = result?.keys.filter((key) => key.path.toLowerCase().includes(filter.toLowerCase())) || [];
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Effective Values</h2>
+            <div className="resource-info">
+              <span className="badge">HelmRelease</span>
+              <span>{namespace}/{name}</span>
+            </div>
+          </div>
+          <div className="triage-controls">
+            <button className="btn btn-sm btn-secondary" onClick={loadValues} disabled={loading}>
+              ↻ Refresh
+            </button>
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <div className="triage-body">
+          {loading && <div className="loading">Resolving values...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {!loading && !error && result && (
+            <>
+              <h3>Sources</h3>
+              <p className="helm-values-hint">Merged top to bottom; later sources win.</p>
+              <table className="triage-containers">
+                <thead>
+                  <tr>
+                    <th>#</th>
+                    <th>Source</th>
+                    <th>Key</th>
+                    <th>Target path</th>
+                    <th>Status</th>
+                  </tr>
+                </thead>
+                <tbody>
+                  {result.sources.map((source, idx) => (
+                    <tr key={idx}>
+                      <td>{idx + 1}</td>
+                      <td>{sourceLabel(source)}{source.optional && <span className="triage-init"> (optional)</span>}</td>
+                      <td>{source.values_key || '-'}</td>
+                      <td>{source.target_path || '-'}</td>
+                      <td className={`helm-values-status ${source.status}`}>
+                        {source.status}
+                        {source.error && <div className="triage-pod-message">{source.error}</div>}
+                      </td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+
+              <h3>Keys</h3>
+              <input
+                type="text"
+                className="form-control helm-values-filter"
+                placeholder="Filter keys, e.g. image.tag"
+                value={filter}
+                onChange={(e) => setFilter(e.target.value)}
+              />
+              {keys.length === 0 ? (
+                <div className="triage-no-events">No values set.</div>
+              ) : (
+                <table className="triage-containers">
+                  <thead>
+                    <tr>
+                      <th>Key</th>
+                      <th>Set by</th>
+                      <th>Overrides</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {keys.map((key) => (
+                      <tr key={key.path}>
+                        <td><code>{key.path}</code></td>
+                        <td>{sourceLabel(result.sources[key.source])}</td>
+                        <td className="triage-init">
+                          {(key.overridden || []).map((idx) => sourceLabel(result.sources[idx])).join(', ') || '-'}
+                        </td>
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+              )}
+
+              <h3>Values</h3>
+              {result.redacted && (
+                <p className="helm-values-hint">Values from Secrets are redacted; revealing them needs the secret.reveal permission.</p>
+              )}
+              <pre className="helm-values-yaml">{JSON.stringify(result.values, null, 2)}</pre>
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default HelmValues;
@@ -0,0 +1,116 @@
+import React, { useState, useEffect } from 'react';
+import { ImageFreshnessReport } from '../types';
+import { clusterApi } from '../api';
+import '../styles/NamespaceQuotas.css';
+
+interface ImageFreshnessProps {
+  clusterId: string;
+}
+
+const statusLabels: Record<string, string> = {
+  up_to_date: 'Up to date',
+  outdated: 'Outdated',
+  unknown: 'Unknown',
+  error: 'Error',
+};
+
+const ImageFreshness: React.FC<ImageFreshnessProps> = ({ clusterId }) => {
+  const [data, setData] = useState<ImageFreshnessReport | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [outdatedOnly, setOutdatedOnly] = useState(false);
+
+  useEffect(() => {
+    loadReport();
+  }, [clusterId, outdatedOnly]);
+
+  const loadReport = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await clusterApi.getImageFreshness(clusterId, outdatedOnly);
+      setData(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to check image freshness');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (loading) {
+    return <div className="quota-loading">Checking registries for newer image tags...</div>;
+  }
+
+  if (error) {
+    return (
+      <div className="quota-error">
+        <p>Error: {error}</p>
+        <button onClick={loadReport} className="btn-retry">Retry</button>
+      </div>
+    );
+  }
+
+  return (
+    <div className="namespace-quotas">
+      <div className="quota-header">
+        <h3>Image Freshness</h3>
+        {data && (
+          <div className="quota-summary">
+            <span>{data.images} image(s) checked</span>
+            {data.outdated > 0 && <span className="quota-at-risk">⬆️ {data.outdated} outdated</span>}
+          </div>
+        )}
+        <div className="quota-actions">
+          <label>
+            <input
+              type="checkbox"
+              checked={outdatedOnly}
+              onChange={(e) => setOutdatedOnly(e.target.checked)}
+            />
+            Outdated only
+          </label>
+          <button onClick={loadReport} className="btn-tree-action">Refresh</button>
+        </div>
+      </div>
+
+      {data?.warnings.map((warning) => (
+        <div key={warning} className="quota-app at-risk">⚠️ {warning}</div>
+      ))}
+
+      {!data || data.owners.length === 0 ? (
+        <div className="quota-empty">
+          {outdatedOnly ? 'No outdated images' : 'No workloads applied by a Kustomization or HelmRelease'}
+        </div>
+      ) : (
+        data.owners.map((owner) => (
+          <div
+            key={`${owner.kind}/${owner.namespace}/${owner.name}`}
+            className={`quota-namespace ${owner.outdated > 0 ? 'near-limit' : 'ok'}`}
+          >
+            <div className="quota-namespace-header">
+              <span className="quota-namespace-name">{owner.kind} {owner.namespace}/{owner.name}</span>
+              {owner.outdated > 0 && <span className="quota-badge near-limit">{owner.outdated} outdated</span>}
+            </div>
+            <div className="quota-apps">
+              {owner.images.map((image) => (
+                <div
+                  key={`${image.workload.kind}/${image.workload.namespace}/${image.workload.name}/${image.container}`}
+                  className={`quota-app ${image.status === 'outdated' ? 'at-risk' : ''}`}
+                  title={image.message}
+                >
+                  <span className="quota-app-kind">{image.workload.kind}</span>
+                  <span className="quota-app-name">{image.workload.namespace}/{image.workload.name} · {image.container}</span>
+                  <span className="quota-kind-count">{image.repository}:{image.tag || image.digest}</span>
+                  {image.status === 'outdated' && <span className="quota-kind-count">→ {image.latest_tag}</span>}
+                  <span className="quota-kind-count">{statusLabels[image.status]}</span>
+                </div>
+              ))}
+            </div>
+          </div>
+        ))
+      )}
+    </div>
+  );
+};
+
+export default ImageFreshness;
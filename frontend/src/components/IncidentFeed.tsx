@@ -0,0 +1,172 @@
+import React, { useState, useEffect } from 'react';
+import { clusterApi } from '../api';
+import { IncidentEntry, IncidentFeed as Feed } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+import '../styles/BulkSuspend.css';
+import '../styles/IncidentFeed.css';
+
+interface IncidentFeedProps {
+  clusterId: string;
+  clusterName: string;
+  onClose: () => void;
+}
+
+const sources: Record<IncidentEntry['source'], string> = {
+  activity: 'Activities',
+  transition: 'Status changes',
+  deployment: 'Deployments',
+  event: 'Kubernetes events',
+};
+
+const windows: Record<string, number> = { '15m': 15, '30m': 30, '1h': 60, '3h': 180 };
+
+// Merges activities, readiness transitions, deployments and Kubernetes events of a cluster
+// into one chronological feed for post-incident review
+const IncidentFeed: React.FC<IncidentFeedProps> = ({ clusterId, clusterName, onClose }) => {
+  const [around, setAround] = useState('');
+  const [windowSize, setWindowSize] = useState('30m');
+  const [namespace, setNamespace] = useState('');
+  const [hidden, setHidden] = useState<Set<string>>(new Set());
+  const [feed, setFeed] = useState<Feed | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    loadFeed();
+  }, [clusterId, around, windowSize]);
+
+  const loadFeed = async () => {
+    const params: { since?: string; until?: string; namespace?: string } = { namespace: namespace.trim() || undefined };
+    if (around) {
+      // datetime-local values are in the browser's time zone
+      const center = new Date(around).getTime();
+      const span = windows[windowSize] * 60000;
+      params.since = new Date(center - span).toISOString();
+      params.until = new Date(center + span).toISOString();
+    }
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await clusterApi.getIncidentFeed(clusterId, params);
+      setFeed(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load the incident feed');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const toggleSource = (source: string) => {
+    setHidden(prev => {
+      const next = new Set(prev);
+      if (next.has(source)) next.delete(source);
+      else next.add(source);
+      return next;
+    });
+  };
+
+  const entries = feed?.entries.filter(entry => !hidden.has(entry.source)) ?? [];
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Incident View</h2>
+            <div className="resource-info">
+              <span className="badge">Cluster</span>
+              <span>{clusterName}</span>
+            </div>
+          </div>
+          <div className="triage-controls">
+            <button className="btn btn-sm btn-secondary" onClick={loadFeed} disabled={loading}>
+              ↻ Refresh
+            </button>
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <form className="bulk-suspend-form" onSubmit={e => { e.preventDefault(); loadFeed(); }}>
+          <label>
+            Around
+            <input type="datetime-local" value={around} onChange={e => setAround(e.target.value)} />
+          </label>
+          {around ? (
+            <>
+              <select value={windowSize} onChange={e => setWindowSize(e.target.value)}>
+                {Object.keys(windows).map(w => (
+                  <option key={w} value={w}>± {w}</option>
+                ))}
+              </select>
+              <button type="button" className="btn btn-sm btn-secondary" onClick={() => setAround('')}>Last hour</button>
+            </>
+          ) : (
+            <span className="helm-values-hint">Last hour</span>
+          )}
+          <input
+            type="text"
+            placeholder="Namespace (all)"
+            value={namespace}
+            onChange={e => setNamespace(e.target.value)}
+          />
+          <button type="submit" className="btn btn-sm btn-secondary" disabled={loading}>Apply</button>
+        </form>
+
+        <div className="triage-body">
+          {loading && <div className="loading">Loading...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {!loading && !error && feed && (
+            <>
+              <div className="incident-sources">
+                {(Object.keys(sources) as IncidentEntry['source'][]).map(source => (
+                  <label key={source}>
+                    <input type="checkbox" checked={!hidden.has(source)} onChange={() => toggleSource(source)} />
+                    {sources[source]} ({feed.counts[source] || 0})
+                  </label>
+                ))}
+              </div>
+              {feed.warnings.map(warning => (
+                <div key={warning} className="error-message">Not included: {warning}</div>
+              ))}
+              {entries.length === 0 ? (
+                <div className="triage-no-events">Nothing happened on this cluster in this window.</div>
+              ) : (
+                <table className="triage-containers incident-table">
+                  <thead>
+                    <tr>
+                      <th>Time</th>
+                      <th>Source</th>
+                      <th>Object</th>
+                      <th>What happened</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {entries.map((entry, i) => (
+                      <tr key={i} className={`incident-${entry.severity}`}>
+                        <td className="incident-time">{new Date(entry.time).toLocaleTimeString()}</td>
+                        <td>{entry.source}</td>
+                        <td>
+                          <span className="badge">{entry.kind}</span>{' '}
+                          {entry.namespace ? `${entry.namespace}/${entry.name}` : entry.name}
+                        </td>
+                        <td>{entry.summary}</td>
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+              )}
+              <p className="helm-values-hint">
+                Kubernetes keeps events for about an hour, so older windows show only the orchestrator's own records.
+              </p>
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default IncidentFeed;
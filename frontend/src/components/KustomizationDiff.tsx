@@ -0,0 +1,157 @@
+import React, { useState, useEffect } from 'react';
+import { fluxApi } from '../api';
+import { KustomizationDiff as KustomizationDiffResult, ObjectDiff } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+import '../styles/ResourceDiffViewer.css';
+
+interface KustomizationDiffProps {
+  clusterId: string;
+  namespace: string;
+  name: string;
+  onClose: () => void;
+}
+
+const actionClass: Record<ObjectDiff['action'], string> = {
+  created: 'applied',
+  configured: 'skipped',
+  deleted: 'error',
+  error: 'error',
+  unchanged: '',
+};
+
+// Previews what the next reconcile of a Kustomization would create, change and prune
+const KustomizationDiff: React.FC<KustomizationDiffProps> = ({ clusterId, namespace, name, onClose }) => {
+  const [result, setResult] = useState<KustomizationDiffResult | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [showUnchanged, setShowUnchanged] = useState(false);
+  const [expanded, setExpanded] = useState<string | null>(null);
+
+  useEffect(() => {
+    loadDiff();
+  }, [clusterId, namespace, name]);
+
+  const loadDiff = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await fluxApi.getKustomizationDiff(clusterId, namespace, name);
+      setResult(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to diff kustomization');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const objectKey = (obj: ObjectDiff) => `${obj.api_version}/${obj.kind}/${obj.namespace || ''}/${obj.name}`;
+
+  const lineClass = (line: string) => {
+    if (line.startsWith('+') && !line.startsWith('+++')) return 'diff-added';
+    if (line.startsWith('-') && !line.startsWith('---')) return 'diff-removed';
+    return 'diff-unchanged';
+  };
+
+  const objects = result ? result.objects.filter(obj => showUnchanged || obj.action !== 'unchanged') : [];
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Diff</h2>
+            <div className="resource-info">
+              <span className="badge">Kustomization</span>
+              <span>{namespace}/{name}</span>
+              {result && <span className="triage-init">{result.source.kind} {result.source.name} @ {result.revision}</span>}
+            </div>
+          </div>
+          <div className="triage-controls">
+            <label>
+              <input type="checkbox" checked={showUnchanged} onChange={e => setShowUnchanged(e.target.checked)} /> Show unchanged
+            </label>
+            <button className="btn btn-sm btn-secondary" onClick={loadDiff} disabled={loading}>
+              ↻ Refresh
+            </button>
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <div className="triage-body">
+          {loading && <div className="loading">Building and dry-running {result?.path || 'manifests'}...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {!loading && result && (
+            <>
+              <p className="helm-values-hint">
+                {['created', 'configured', 'deleted', 'unchanged', 'error']
+                  .filter(action => result.summary[action])
+                  .map(action => `${result.summary[action]} ${action}`)
+                  .join(', ') || 'No objects built'} from {result.path || './'}
+              </p>
+              {result.warnings.map(warning => (
+                <p key={warning} className="helm-values-hint">⚠ {warning}</p>
+              ))}
+
+              {objects.length === 0 ? (
+                <div className="triage-no-events">No changes. The cluster matches {result.revision}.</div>
+              ) : (
+                <table className="triage-containers">
+                  <thead>
+                    <tr>
+                      <th>Action</th>
+                      <th>Kind</th>
+                      <th>Namespace</th>
+                      <th>Name</th>
+                      <th></th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {objects.map(obj => (
+                      <React.Fragment key={objectKey(obj)}>
+                        <tr>
+                          <td className={`helm-values-status ${actionClass[obj.action]}`}>{obj.action}</td>
+                          <td>{obj.kind}</td>
+                          <td>{obj.namespace || '-'}</td>
+                          <td>{obj.name}</td>
+                          <td>
+                            {(obj.diff || obj.error) && (
+                              <button
+                                className="btn btn-sm btn-secondary"
+                                onClick={() => setExpanded(expanded === objectKey(obj) ? null : objectKey(obj))}
+                              >
+                                {obj.error ? 'Error' : 'Diff'}
+                              </button>
+                            )}
+                          </td>
+                        </tr>
+                        {expanded === objectKey(obj) && (
+                          <tr>
+                            <td colSpan={5}>
+                              {obj.error && <div className="error-message">{obj.error}</div>}
+                              {obj.diff && (
+                                <pre className="helm-values-yaml">
+                                  {obj.diff.split('\n').map((line, idx) => (
+                                    <div key={idx} className={lineClass(line)}>{line}</div>
+                                  ))}
+                                </pre>
+                              )}
+                            </td>
+                          </tr>
+                        )}
+                      </React.Fragment>
+                    ))}
+                  </tbody>
+                </table>
+              )}
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default KustomizationDiff;
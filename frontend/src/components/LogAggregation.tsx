@@ -34,6 +34,7 @@ const LogAggregation: React.FC = () => {
   const [searchTerm, setSearchTerm] = useState('');
   const [tailLines, setTailLines] = useState(100);
   const [levelFilter, setLevelFilter] = useState<string[]>([]);
+  const [namespaceOptions, setNamespaceOptions] = useState<string[]>([]);
 
   useEffect(() => {
     loadClusters();
@@ -46,6 +47,12 @@ const LogAggregation: React.FC = () => {
     }
   }, [selectedClusters]);
 
+  // Suggest the namespaces of every selected cluster
+  useEffect(() => {
+    Promise.all(selectedClusters.map(id => clusterApi.listNamespaces(id).then(r => r.data.namespaces).catch(() => [])))
+      .then(lists => setNamespaceOptions(Array.from(new Set(lists.flat().map(ns => ns.name))).sort()));
+  }, [selectedClusters]);
+
   useEffect(() => {
     if (autoRefresh) {
       const interval = setInterval(() => {
@@ -333,10 +340,14 @@ const LogAggregation: React.FC = () => {
             <label>📦 Namespace</label>
             <input
               type="text"
+              list="log-namespaces"
               value={namespace}
               onChange={e => setNamespace(e.target.value)}
               placeholder="flux-system, default, kube-system..."
             />
+            <datalist id="log-namespaces">
+              {namespaceOptions.map(ns => <option key={ns} value={ns} />)}
+            </datalist>
           </div>
 
           <div className="filter-section">
@@ -0,0 +1,143 @@
+import React, { useState } from 'react';
+import { fluxApi } from '../api';
+import { LogSearchResult } from '../types';
+import '../styles/LogSearch.css';
+
+interface LogSearchProps {
+  clusterId: string;
+  kind: string;
+  namespace: string;
+  name: string;
+  onClose: () => void;
+}
+
+const LogSearch: React.FC<LogSearchProps> = ({ clusterId, kind, namespace, name, onClose }) => {
+  const [query, setQuery] = useState('');
+  const [regex, setRegex] = useState(false);
+  const [ignoreCase, setIgnoreCase] = useState(true);
+  const [previous, setPrevious] = useState(false);
+  const [contextLines, setContextLines] = useState(2);
+  const [tail, setTail] = useState(1000);
+  const [result, setResult] = useState<LogSearchResult | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const search = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!query) return;
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await fluxApi.searchLogs(clusterId, kind, namespace, name, {
+        q: query,
+        regex,
+        ignore_case: ignoreCase,
+        previous,
+        context: contextLines,
+        tail,
+      });
+      setResult(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to search logs');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content log-search-modal" onClick={e => e.stopPropagation()}>
+        <div className="log-search-header">
+          <div>
+            <h2>Search Logs</h2>
+            <div className="resource-info">
+              <span className="badge">{kind}</span>
+              <span>{namespace}/{name}</span>
+            </div>
+          </div>
+          <button className="btn-close" onClick={onClose}>✕</button>
+        </div>
+
+        <form className="log-search-form" onSubmit={search}>
+          <input
+            type="text"
+            className="log-search-input"
+            placeholder={regex ? 'Regular expression, e.g. timeout|refused' : 'Text to find, e.g. connection refused'}
+            value={query}
+            onChange={(e) => setQuery(e.target.value)}
+            autoFocus
+          />
+          <button type="submit" className="btn btn-primary" disabled={loading || !query}>
+            {loading ? 'Searching...' : '🔎 Search'}
+          </button>
+          <div className="log-search-options">
+            <label>
+              <input type="checkbox" checked={regex} onChange={(e) => setRegex(e.target.checked)} />
+              Regex
+            </label>
+            <label>
+              <input type="checkbox" checked={ignoreCase} onChange={(e) => setIgnoreCase(e.target.checked)} />
+              Ignore case
+            </label>
+            <label>
+              <input type="checkbox" checked={previous} onChange={(e) => setPrevious(e.target.checked)} />
+              Previous container
+            </label>
+            <label>
+              Context
+              <select value={contextLines} onChange={(e) => setContextLines(Number(e.target.value))}>
+                {[0, 1, 2, 5, 10].map((n) => <option key={n} value={n}>{n}</option>)}
+              </select>
+            </label>
+            <label>
+              Tail
+              <select value={tail} onChange={(e) => setTail(Number(e.target.value))}>
+                {[500, 1000, 5000, 10000].map((n) => <option key={n} value={n}>{n}</option>)}
+              </select>
+            </label>
+          </div>
+        </form>
+
+        <div className="log-search-body">
+          {error && <div className="error-message">{error}</div>}
+
+          {result && !error && (
+            <>
+              <div className="log-search-summary">
+                {result.total_matches} match(es) in {result.pods_searched} pod(s), {result.containers_searched} container(s)
+              </div>
+              {result.results.map((container) => (
+                <div key={`${container.namespace}/${container.pod}/${container.container}`} className="log-search-container">
+                  <div className="log-search-container-header">
+                    <span className="log-search-pod">{container.namespace}/{container.pod}</span>
+                    <span className="log-search-container-name">{container.container}</span>
+                    <span className="log-search-count">
+                      {container.matches.length}{container.truncated ? '+' : ''} match(es)
+                    </span>
+                  </div>
+                  {container.error && <div className="error-message">{container.error}</div>}
+                  {container.matches.map((match) => (
+                    <pre key={match.line_number} className="log-search-match">
+                      {match.before?.map((line, idx) => (
+                        <div key={`b${idx}`} className="log-context">{line}</div>
+                      ))}
+                      <div className="log-hit">
+                        <span className="log-line-number">{match.line_number}</span>
+                        {match.line}
+                      </div>
+                      {match.after?.map((line, idx) => (
+                        <div key={`a${idx}`} className="log-context">{line}</div>
+                      ))}
+                    </pre>
+                  ))}
+                </div>
+              ))}
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default LogSearch;
@@ -1,5 +1,5 @@
 import React, { useEffect, useState } from 'react';
-import { fluxApi } from '../api';
+import { useAuth } from '../contexts/AuthContext';
 
 interface LoginProps {
   onLoginSuccess: () => void;
@@ -7,6 +7,7 @@ interface LoginProps {
 
 export const Login: React.FC<LoginProps> = () => {
   const [error, setError] = useState<string>('');
+  const { login } = useAuth();
 
   useEffect(() => {
     // Check for error parameter in URL
@@ -20,6 +21,7 @@ export const Login: React.FC<LoginProps> = () => {
         token_exchange_failed: 'Failed to exchange OAuth token. Please try again.',
         user_info_failed: 'Failed to retrieve user information.',
         unauthorized: 'You are not authorized to access this application.',
+        user_disabled: 'Your account has been disabled. Ask an administrator to re-enable it.',
         session_failed: 'Failed to create session. Please try again.',
       };
       
@@ -32,10 +34,8 @@ export const Login: React.FC<LoginProps> = () => {
 
   const handleLogin = async () => {
     try {
-      // Redirect to backend OAuth flow
-      if (fluxApi.axios) {
-        window.location.href = `${fluxApi.axios.defaults.baseURL}/auth/login`;
-      }
+      // Redirect to backend OAuth flow, or back through the authenticating proxy
+      login();
     } catch (err) {
       setError('Failed to initiate login');
     }
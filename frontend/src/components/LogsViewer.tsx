@@ -1,7 +1,10 @@
-import React, { useState, useEffect } from 'react';
-import { resourceApi } from '../api';
+import React, { useState, useEffect, useRef } from 'react';
+import { resourceApi, logStreamApi } from '../api';
+import { LogStreamMessage } from '../types';
 import '../styles/LogsViewer.css';
 
+const IS_DEMO_MODE = import.meta.env.VITE_DEMO_MODE === 'true';
+
 interface LogsViewerProps {
   clusterId: string;
   namespace: string;
@@ -16,7 +19,10 @@ const LogsViewer: React.FC<LogsViewerProps> = ({ clusterId, namespace, podName,
   const [tailLines, setTailLines] = useState<number>(1000);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
-  const [autoRefresh, setAutoRefresh] = useState(false);
+  const [follow, setFollow] = useState(false);
+  const [streamEnded, setStreamEnded] = useState(false);
+  const [downloadingAll, setDownloadingAll] = useState(false);
+  const contentRef = useRef<HTMLDivElement>(null);
 
   const fetchContainers = async () => {
     try {
@@ -57,19 +63,49 @@ const LogsViewer: React.FC<LogsViewerProps> = ({ clusterId, namespace, podName,
   }, [clusterId, namespace, podName]);
 
   useEffect(() => {
-    if (selectedContainer) {
+    if (selectedContainer && !follow) {
       fetchLogs();
     }
-  }, [selectedContainer, tailLines]);
+  }, [selectedContainer, tailLines, follow]);
 
+  // Follow streams the last tailLines lines and then every new one, keeping at most tailLines
   useEffect(() => {
-    if (autoRefresh && selectedContainer) {
-      const interval = setInterval(() => {
-        fetchLogs();
-      }, 5000);
-      return () => clearInterval(interval);
+    if (!follow || !selectedContainer) return;
+
+    let lines: string[] = [];
+    setLogs('');
+    setError(null);
+    setStreamEnded(false);
+    setLoading(false);
+
+    const socket = new WebSocket(logStreamApi.podUrl(clusterId, namespace, podName, {
+      container: selectedContainer,
+      tail: tailLines,
+    }));
+    socket.onmessage = (message) => {
+      const data: LogStreamMessage = JSON.parse(message.data);
+      if (data.type === 'line') {
+        lines.push(data.line ?? '');
+        if (lines.length > tailLines) {
+          lines = lines.slice(-tailLines);
+        }
+        setLogs(lines.join('\n'));
+      } else if (data.type === 'error') {
+        setError(data.error || 'Log stream failed');
+      } else {
+        setStreamEnded(true);
+      }
+    };
+    socket.onerror = () => setError('Failed to stream logs');
+
+    return () => socket.close();
+  }, [follow, clusterId, namespace, podName, selectedContainer, tailLines]);
+
+  useEffect(() => {
+    if (follow && contentRef.current) {
+      contentRef.current.scrollTop = contentRef.current.scrollHeight;
     }
-  }, [autoRefresh, selectedContainer, tailLines]);
+  }, [logs, follow]);
 
   const handleDownload = () => {
     const blob = new Blob([logs], { type: 'text/plain' });
@@ -81,6 +117,24 @@ const LogsViewer: React.FC<LogsViewerProps> = ({ clusterId, namespace, podName,
     URL.revokeObjectURL(url);
   };
 
+  // Downloads every container of the pod, including init containers, as one zip
+  const handleDownloadAll = async () => {
+    setDownloadingAll(true);
+    try {
+      const response = await resourceApi.downloadPodLogs(clusterId, namespace, podName, tailLines);
+      const url = URL.createObjectURL(new Blob([response.data], { type: 'application/zip' }));
+      const a = document.createElement('a');
+      a.href = url;
+      a.download = `${podName}-logs.zip`;
+      a.click();
+      URL.revokeObjectURL(url);
+    } catch (err: any) {
+      setError(err.response?.status === 413 ? 'Too many containers to download at once' : 'Failed to download logs');
+    } finally {
+      setDownloadingAll(false);
+    }
+  };
+
   return (
     <div className="logs-viewer-overlay" onClick={onClose}>
       <div className="logs-viewer" onClick={(e) => e.stopPropagation()}>
@@ -119,33 +173,40 @@ const LogsViewer: React.FC<LogsViewerProps> = ({ clusterId, namespace, podName,
             </select>
           </div>
 
-          <div className="control-group">
-            <label>
-              <input
-                type="checkbox"
-                checked={autoRefresh}
-                onChange={(e) => setAutoRefresh(e.target.checked)}
-              />
-              Auto-refresh (5s)
-            </label>
-          </div>
+          {!IS_DEMO_MODE && (
+            <div className="control-group">
+              <label>
+                <input
+                  type="checkbox"
+                  checked={follow}
+                  onChange={(e) => setFollow(e.target.checked)}
+                />
+                Follow (live)
+              </label>
+            </div>
+          )}
 
-          <button onClick={fetchLogs} disabled={loading}>
+          <button onClick={fetchLogs} disabled={loading || follow}>
             {loading ? 'Loading...' : 'Refresh'}
           </button>
 
           <button onClick={handleDownload} disabled={!logs}>
             Download
           </button>
+
+          <button onClick={handleDownloadAll} disabled={downloadingAll}>
+            {downloadingAll ? 'Downloading...' : 'Download All (zip)'}
+          </button>
         </div>
 
-        <div className="logs-content">
+        <div className="logs-content" ref={contentRef}>
           {error && <div className="logs-error">{error}</div>}
           {loading && !logs ? (
             <div className="logs-loading">Loading logs...</div>
           ) : (
             <pre>{logs}</pre>
           )}
+          {follow && streamEnded && <div className="logs-loading">Log ended, the container is no longer running</div>}
         </div>
       </div>
     </div>
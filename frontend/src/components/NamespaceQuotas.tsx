@@ -0,0 +1,171 @@
+import React, { useState, useEffect } from 'react';
+import { ClusterQuotas, NamespaceQuotas as NamespaceQuotasType } from '../types';
+import { clusterApi } from '../api';
+import '../styles/NamespaceQuotas.css';
+
+interface NamespaceQuotasProps {
+  clusterId: string;
+}
+
+const formatPercent = (ratio: number) => `${Math.round(ratio * 100)}%`;
+
+const usageClass = (ratio: number, threshold: number) => {
+  if (ratio >= 1) return 'exceeded';
+  if (ratio >= threshold) return 'near-limit';
+  return 'ok';
+};
+
+const NamespaceQuotas: React.FC<NamespaceQuotasProps> = ({ clusterId }) => {
+  const [data, setData] = useState<ClusterQuotas | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [nearLimitOnly, setNearLimitOnly] = useState(false);
+
+  useEffect(() => {
+    loadQuotas();
+  }, [clusterId]);
+
+  const loadQuotas = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await clusterApi.getQuotas(clusterId);
+      setData(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load namespace quotas');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const renderNamespace = (ns: NamespaceQuotasType, threshold: number) => (
+    <div key={ns.namespace} className={`quota-namespace ${usageClass(ns.max_ratio, threshold)}`}>
+      <div className="quota-namespace-header">
+        <span className="quota-namespace-name">📁 {ns.namespace}</span>
+        {ns.quotas.length > 0 && (
+          <span className={`quota-badge ${usageClass(ns.max_ratio, threshold)}`}>
+            {ns.exceeded ? 'Exceeded' : ns.near_limit ? 'Near limit' : 'OK'} · {formatPercent(ns.max_ratio)}
+          </span>
+        )}
+        {Object.entries(ns.apps_by_kind)
+          .sort(([a], [b]) => a.localeCompare(b))
+          .map(([kind, count]) => (
+            <span key={kind} className="quota-kind-count">{kind}: {count}</span>
+          ))}
+      </div>
+
+      {ns.quotas.map((quota) => (
+        <div key={quota.name} className="quota-block">
+          <div className="quota-block-title">ResourceQuota {quota.name}</div>
+          {quota.usage.map((usage) => (
+            <div key={usage.resource} className="quota-usage-row">
+              <span className="quota-resource">{usage.resource}</span>
+              <div className="quota-bar">
+                <div
+                  className={`quota-bar-fill ${usageClass(usage.ratio, threshold)}`}
+                  style={{ width: `${Math.min(usage.ratio, 1) * 100}%` }}
+                />
+              </div>
+              <span className="quota-values">
+                {usage.used} / {usage.hard} ({formatPercent(usage.ratio)})
+              </span>
+            </div>
+          ))}
+        </div>
+      ))}
+
+      {ns.limit_ranges.map((limitRange) => (
+        <div key={limitRange.name} className="quota-block">
+          <div className="quota-block-title">LimitRange {limitRange.name}</div>
+          <table className="limit-range-table">
+            <thead>
+              <tr>
+                <th>Type</th>
+                <th>Resource</th>
+                <th>Min</th>
+                <th>Max</th>
+                <th>Default</th>
+                <th>Default Request</th>
+              </tr>
+            </thead>
+            <tbody>
+              {limitRange.limits.map((limit) => (
+                <tr key={`${limit.type}/${limit.resource}`}>
+                  <td>{limit.type}</td>
+                  <td>{limit.resource}</td>
+                  <td>{limit.min || '-'}</td>
+                  <td>{limit.max || '-'}</td>
+                  <td>{limit.default || '-'}</td>
+                  <td>{limit.default_request || '-'}</td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        </div>
+      ))}
+
+      {ns.apps.length > 0 && (
+        <div className="quota-apps">
+          {ns.apps.map((app) => (
+            <div key={app.id} className={`quota-app ${app.at_risk ? 'at-risk' : ''}`}>
+              <span className="quota-app-kind">{app.kind}</span>
+              <span className="quota-app-name">{app.namespace}/{app.name}</span>
+              <span className={`status-badge ${app.status.toLowerCase()}`}>{app.status}</span>
+              {app.at_risk && (
+                <span className="quota-app-warning" title={app.message}>
+                  ⚠️ Not ready in a namespace near its quota
+                </span>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+
+  if (loading) {
+    return <div className="quota-loading">Loading namespace quotas...</div>;
+  }
+
+  if (error) {
+    return (
+      <div className="quota-error">
+        <p>Error: {error}</p>
+        <button onClick={loadQuotas} className="btn-retry">Retry</button>
+      </div>
+    );
+  }
+
+  if (!data || data.namespaces.length === 0) {
+    return <div className="quota-empty">No ResourceQuotas or LimitRanges found in this cluster</div>;
+  }
+
+  const namespaces = nearLimitOnly ? data.namespaces.filter((ns) => ns.near_limit) : data.namespaces;
+
+  return (
+    <div className="namespace-quotas">
+      <div className="quota-header">
+        <h3>Namespace Quotas</h3>
+        <div className="quota-summary">
+          <span>{data.near_limit} namespace(s) at or above {formatPercent(data.threshold)}</span>
+          {data.at_risk > 0 && <span className="quota-at-risk">⚠️ {data.at_risk} app(s) at risk</span>}
+        </div>
+        <div className="quota-actions">
+          <label>
+            <input
+              type="checkbox"
+              checked={nearLimitOnly}
+              onChange={(e) => setNearLimitOnly(e.target.checked)}
+            />
+            Near limit only
+          </label>
+          <button onClick={loadQuotas} className="btn-tree-action">Refresh</button>
+        </div>
+      </div>
+
+      {namespaces.map((ns) => renderNamespace(ns, data.threshold))}
+    </div>
+  );
+};
+
+export default NamespaceQuotas;
@@ -226,6 +226,7 @@ const AddProviderDialog: React.FC<AddProviderDialogProps> = ({ provider, onClose
           scopes: formData.scopes,
           allowed_users: formData.allowed_users,
           enabled: formData.enabled,
+          version: provider!.version,
         };
 
         if (formData.provider === 'entra') {
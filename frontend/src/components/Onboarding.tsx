@@ -0,0 +1,129 @@
+import React, { useState, useEffect } from 'react';
+import { useNavigate } from 'react-router-dom';
+import { onboardingApi } from '../api';
+import { OnboardingState, OnboardingStepStatus } from '../types';
+
+const stepIcons: Record<OnboardingStepStatus, string> = {
+  complete: '✅',
+  pending: '⬜',
+  skipped: '⏭️',
+};
+
+// Where each step is completed in the UI; steps without a page are configured through the environment
+const stepLinks: Record<string, string> = {
+  cluster: '/clusters',
+  oauth: '/settings',
+};
+
+const Onboarding: React.FC = () => {
+  const navigate = useNavigate();
+  const [state, setState] = useState<OnboardingState | null>(null);
+  const [generatedKey, setGeneratedKey] = useState<string | null>(null);
+
+  useEffect(() => {
+    loadState();
+  }, []);
+
+  const loadState = async () => {
+    try {
+      const response = await onboardingApi.get();
+      setState(response.data);
+    } catch (err) {
+      // Onboarding is optional; hide it when the backend does not provide it
+      console.error('Failed to load onboarding state:', err);
+    }
+  };
+
+  const handleSkip = async (step: string, skipped: boolean) => {
+    try {
+      const response = await onboardingApi.setSkipped(step, skipped);
+      setState(response.data);
+    } catch (err) {
+      console.error('Failed to update onboarding step:', err);
+    }
+  };
+
+  const handleDismiss = async () => {
+    try {
+      const response = await onboardingApi.dismiss();
+      setState(response.data);
+    } catch (err) {
+      console.error('Failed to dismiss onboarding:', err);
+    }
+  };
+
+  const handleGenerateKey = async () => {
+    try {
+      const response = await onboardingApi.generateEncryptionKey();
+      setGeneratedKey(response.data.key);
+    } catch (err) {
+      console.error('Failed to generate encryption key:', err);
+    }
+  };
+
+  if (!state || state.completed || state.dismissed) {
+    return null;
+  }
+
+  const done = state.steps.filter((step) => step.status !== 'pending').length;
+
+  return (
+    <div className="setting-section" style={{ marginBottom: '20px' }}>
+      <div style={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center' }}>
+        <h3 style={{ margin: 0 }}>
+          🚀 Getting started ({done}/{state.steps.length})
+        </h3>
+        <button className="btn btn-sm btn-secondary" onClick={handleDismiss}>
+          Dismiss
+        </button>
+      </div>
+      {state.steps.map((step) => (
+        <div
+          className="setting-item"
+          key={step.id}
+          style={step.id === state.current_step ? { fontWeight: 600 } : undefined}
+        >
+          <div style={{ display: 'flex', alignItems: 'center', gap: '8px' }}>
+            <span>{stepIcons[step.status]}</span>
+            <strong>{step.title}</strong>
+            {step.optional && <span className="setting-hint">(optional)</span>}
+          </div>
+          <p className="setting-description">{step.description}</p>
+          <p className="setting-hint">{step.message}</p>
+          {step.status === 'pending' && (
+            <div style={{ display: 'flex', gap: '8px' }}>
+              {stepLinks[step.id] && (
+                <button className="btn btn-sm btn-primary" onClick={() => navigate(stepLinks[step.id])}>
+                  Set up
+                </button>
+              )}
+              {step.id === 'encryption' && (
+                <button className="btn btn-sm" onClick={handleGenerateKey}>
+                  Generate key
+                </button>
+              )}
+              {step.optional && (
+                <button className="btn btn-sm btn-secondary" onClick={() => handleSkip(step.id, true)}>
+                  Skip
+                </button>
+              )}
+            </div>
+          )}
+          {step.status === 'skipped' && (
+            <button className="btn btn-sm btn-secondary" onClick={() => handleSkip(step.id, false)}>
+              Undo skip
+            </button>
+          )}
+          {step.id === 'encryption' && generatedKey && (
+            <p className="setting-hint">
+              Set ENCRYPTION_KEY to <code>{generatedKey}</code> and restart. Keep it safe: stored credentials cannot be
+              decrypted without it.
+            </p>
+          )}
+        </div>
+      ))}
+    </div>
+  );
+};
+
+export default Onboarding;
@@ -0,0 +1,147 @@
+import React, { useState, useEffect, useRef } from 'react';
+import { resourceApi, execApi } from '../api';
+import { ExecServerMessage } from '../types';
+import '../styles/LogsViewer.css';
+import '../styles/PodExec.css';
+
+interface PodExecProps {
+  clusterId: string;
+  namespace: string;
+  podName: string;
+  onClose: () => void;
+}
+
+// Strips terminal escape sequences; the session runs without a TTY but programs may still color output
+const stripAnsi = (text: string) => text.replace(/\x1b\[[0-9;?]*[A-Za-z]/g, '');
+
+// PodExec runs a shell in a container without a TTY: each submitted line is sent as input
+// and output is appended as it arrives
+const PodExec: React.FC<PodExecProps> = ({ clusterId, namespace, podName, onClose }) => {
+  const [containers, setContainers] = useState<string[]>([]);
+  const [selectedContainer, setSelectedContainer] = useState<string>('');
+  const [output, setOutput] = useState<string>('');
+  const [input, setInput] = useState<string>('');
+  const [connected, setConnected] = useState(false);
+  const [status, setStatus] = useState<string | null>(null);
+  const [session, setSession] = useState(0);
+  const socketRef = useRef<WebSocket | null>(null);
+  const contentRef = useRef<HTMLDivElement>(null);
+
+  useEffect(() => {
+    resourceApi.getPodContainers(clusterId, namespace, podName)
+      .then((response) => {
+        setContainers(response.data.containers);
+        if (response.data.containers.length > 0) {
+          setSelectedContainer(response.data.containers[0]);
+        }
+      })
+      .catch((err) => console.error('Failed to fetch containers:', err));
+  }, [clusterId, namespace, podName]);
+
+  useEffect(() => {
+    if (!selectedContainer) return;
+
+    setOutput('');
+    setStatus(null);
+    const decoder = new TextDecoder();
+    let ended = false;
+
+    const socket = new WebSocket(execApi.podUrl(clusterId, namespace, podName, {
+      container: selectedContainer,
+      tty: false,
+    }));
+    socket.binaryType = 'arraybuffer';
+    socketRef.current = socket;
+
+    socket.onopen = () => setConnected(true);
+    socket.onmessage = (message) => {
+      if (message.data instanceof ArrayBuffer) {
+        const text = stripAnsi(decoder.decode(message.data, { stream: true }));
+        setOutput((current) => current + text);
+        return;
+      }
+      const data: ExecServerMessage = JSON.parse(message.data);
+      ended = true;
+      setStatus(data.type === 'exit' ? `Session ended (exit code ${data.code})` : `Session failed: ${data.error}`);
+    };
+    socket.onclose = () => {
+      // A replaced session closes after its successor opened
+      if (socketRef.current !== socket) return;
+      setConnected(false);
+      if (!ended) {
+        setStatus('Could not open a session. Exec needs the pod.exec permission and a running container with /bin/sh.');
+      }
+    };
+
+    return () => {
+      ended = true;
+      socket.close();
+    };
+  }, [clusterId, namespace, podName, selectedContainer, session]);
+
+  useEffect(() => {
+    if (contentRef.current) {
+      contentRef.current.scrollTop = contentRef.current.scrollHeight;
+    }
+  }, [output]);
+
+  const handleSubmit = (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!socketRef.current || !connected) return;
+    socketRef.current.send(JSON.stringify({ type: 'stdin', data: input + '\n' }));
+    setOutput((current) => current + `$ ${input}\n`);
+    setInput('');
+  };
+
+  return (
+    <div className="logs-viewer-overlay" onClick={onClose}>
+      <div className="logs-viewer" onClick={(e) => e.stopPropagation()}>
+        <div className="logs-header">
+          <h3>Exec: {podName}</h3>
+          <button className="close-button" onClick={onClose}>×</button>
+        </div>
+
+        <div className="logs-controls">
+          <div className="control-group">
+            <label>Container:</label>
+            <select
+              value={selectedContainer}
+              onChange={(e) => setSelectedContainer(e.target.value)}
+            >
+              {containers.map((container) => (
+                <option key={container} value={container}>
+                  {container}
+                </option>
+              ))}
+            </select>
+          </div>
+
+          <button onClick={() => setSession((n) => n + 1)} disabled={connected}>
+            Reconnect
+          </button>
+          <span className="exec-state">{connected ? '● Connected' : '○ Disconnected'}</span>
+        </div>
+
+        <div className="logs-content" ref={contentRef}>
+          <pre>{output}</pre>
+          {status && <div className="logs-loading">{status}</div>}
+        </div>
+
+        <form className="exec-input" onSubmit={handleSubmit}>
+          <span>$</span>
+          <input
+            type="text"
+            value={input}
+            onChange={(e) => setInput(e.target.value)}
+            placeholder={connected ? 'Type a command and press Enter' : ''}
+            disabled={!connected}
+            autoFocus
+            spellCheck={false}
+          />
+        </form>
+      </div>
+    </div>
+  );
+};
+
+export default PodExec;
@@ -0,0 +1,132 @@
+import React, { useState } from 'react';
+import { resourceApi } from '../api';
+import { PortForwardResponse } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/PodPortForward.css';
+
+interface PodPortForwardProps {
+  clusterId: string;
+  namespace: string;
+  podName: string;
+  onClose: () => void;
+}
+
+const METHODS = ['GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE', 'OPTIONS'];
+
+// PodPortForward sends HTTP requests to a pod port through a port-forward, e.g. to check a failing app's health endpoint
+const PodPortForward: React.FC<PodPortForwardProps> = ({ clusterId, namespace, podName, onClose }) => {
+  const [port, setPort] = useState(8080);
+  const [method, setMethod] = useState('GET');
+  const [path, setPath] = useState('/healthz');
+  const [body, setBody] = useState('');
+  const [response, setResponse] = useState<PortForwardResponse | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  const handleSend = async (e: React.FormEvent) => {
+    e.preventDefault();
+    try {
+      setLoading(true);
+      setError(null);
+      const result = await resourceApi.portForward(clusterId, namespace, podName, {
+        port,
+        method,
+        path,
+        body: body || undefined,
+      });
+      setResponse(result.data);
+    } catch (err: any) {
+      setResponse(null);
+      if (err.response?.status === 403) {
+        setError('Port-forwarding needs the pod.portforward permission');
+      } else {
+        setError(err.response?.data?.error || 'Port-forward failed');
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const hasBody = !['GET', 'HEAD', 'OPTIONS'].includes(method);
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Port Forward</h2>
+            <div className="resource-info">
+              <span className="badge">Pod</span>
+              <span>{namespace}/{podName}</span>
+            </div>
+          </div>
+          <div className="triage-controls">
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <div className="triage-body">
+          <form className="port-forward-form" onSubmit={handleSend}>
+            <select value={method} onChange={(e) => setMethod(e.target.value)}>
+              {METHODS.map((m) => (
+                <option key={m} value={m}>{m}</option>
+              ))}
+            </select>
+            <input
+              type="number"
+              className="port-forward-port"
+              min={1}
+              max={65535}
+              value={port}
+              onChange={(e) => setPort(parseInt(e.target.value) || 0)}
+              required
+            />
+            <input
+              type="text"
+              className="port-forward-path"
+              value={path}
+              onChange={(e) => setPath(e.target.value)}
+              placeholder="/healthz"
+            />
+            <button type="submit" className="btn btn-sm btn-primary" disabled={loading}>
+              {loading ? 'Sending...' : 'Send'}
+            </button>
+          </form>
+          {hasBody && (
+            <textarea
+              className="port-forward-body"
+              value={body}
+              onChange={(e) => setBody(e.target.value)}
+              placeholder="Request body"
+              rows={4}
+            />
+          )}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {response && (
+            <div className="port-forward-response">
+              <div className={`port-forward-status ${response.status_code >= 400 ? 'failed' : 'ok'}`}>
+                {response.status} <span className="triage-init">in {response.duration_ms} ms</span>
+              </div>
+              <table className="triage-containers">
+                <tbody>
+                  {Object.entries(response.headers).map(([key, value]) => (
+                    <tr key={key}>
+                      <td>{key}</td>
+                      <td>{value}</td>
+                    </tr>
+                  ))}
+                </tbody>
+              </table>
+              <pre className="port-forward-output">{response.body}</pre>
+              {response.truncated && <div className="triage-init">Body truncated to 1 MiB.</div>}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default PodPortForward;
@@ -0,0 +1,218 @@
+import React, { useState, useEffect } from 'react';
+import { fluxApi, resourceApi } from '../api';
+import { PodTriage as PodTriageType, TriageEvent } from '../types';
+import LogsViewer from './LogsViewer';
+import '../styles/PodTriage.css';
+
+interface PodTriageProps {
+  clusterId: string;
+  kind: string;
+  namespace: string;
+  name: string;
+  onClose: () => void;
+}
+
+const renderEvents = (events: TriageEvent[]) => {
+  if (events.length === 0) {
+    return <div className="triage-no-events">No recent events</div>;
+  }
+  return (
+    <ul className="triage-events">
+      {events.map((event, idx) => (
+        <li key={idx} className={`triage-event ${event.type.toLowerCase()}`}>
+          <span className="triage-event-reason">{event.reason}</span>
+          {event.count > 1 && <span className="triage-event-count">×{event.count}</span>}
+          <span className="triage-event-message">{event.message}</span>
+          {event.last_seen && (
+            <span className="triage-event-time">{new Date(event.last_seen).toLocaleString()}</span>
+          )}
+        </li>
+      ))}
+    </ul>
+  );
+};
+
+const PodTriage: React.FC<PodTriageProps> = ({ clusterId, kind, namespace, name, onClose }) => {
+  const [triage, setTriage] = useState<PodTriageType | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [logsView, setLogsView] = useState<{ namespace: string; podName: string } | null>(null);
+  const [recycling, setRecycling] = useState(false);
+
+  useEffect(() => {
+    loadTriage();
+  }, [clusterId, kind, namespace, name]);
+
+  const loadTriage = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await fluxApi.getTriage(clusterId, kind, namespace, name);
+      setTriage(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to triage pods');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  // Evicts every failing pod so its controller recreates it, one batch per namespace.
+  // Evictions respect PodDisruptionBudgets; blocked pods are reported and left running.
+  const handleRecycle = async () => {
+    if (!triage || triage.pods.length === 0) return;
+    const names = triage.pods.map((pod) => `${pod.namespace}/${pod.name}`);
+    if (!confirm(`Evict ${names.length} failing pod(s)? Their controllers will recreate them.\n\n${names.join('\n')}`)) {
+      return;
+    }
+
+    const byNamespace: Record<string, string[]> = {};
+    triage.pods.forEach((pod) => {
+      byNamespace[pod.namespace] = [...(byNamespace[pod.namespace] || []), pod.name];
+    });
+
+    const blocked: string[] = [];
+    try {
+      setRecycling(true);
+      for (const [podNamespace, pods] of Object.entries(byNamespace)) {
+        try {
+          await resourceApi.deletePods(clusterId, podNamespace, { pods, max_count: pods.length, evict: true });
+        } catch (err: any) {
+          if (err.response?.status !== 429) throw err;
+          const result = err.response.data?.result;
+          (result?.blocked || []).forEach((b: { pod: string }) => blocked.push(`${podNamespace}/${b.pod}`));
+        }
+      }
+      await loadTriage();
+      if (blocked.length > 0) {
+        setError(`PodDisruptionBudgets blocked eviction of ${blocked.join(', ')}; try again later`);
+      }
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to evict pods');
+    } finally {
+      setRecycling(false);
+    }
+  };
+
+  return (
+    <>
+      <div className="modal-overlay" onClick={onClose}>
+        <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+          <div className="triage-header">
+            <div>
+              <h2>Pod Triage</h2>
+              <div className="resource-info">
+                <span className="badge">{kind}</span>
+                <span>{namespace}/{name}</span>
+              </div>
+            </div>
+            <div className="triage-controls">
+              <button className="btn btn-sm btn-secondary" onClick={loadTriage} disabled={loading}>
+                ↻ Refresh
+              </button>
+              {triage && triage.pods.length > 0 && (
+                <button className="btn btn-sm btn-danger" onClick={handleRecycle} disabled={recycling || loading}>
+                  {recycling ? 'Evicting...' : '♻ Recycle Failing Pods'}
+                </button>
+              )}
+              <button className="btn-close" onClick={onClose}>✕</button>
+            </div>
+          </div>
+
+          <div className="triage-body">
+            {loading && <div className="loading">Collecting pod status and events...</div>}
+
+            {error && <div className="error-message">{error}</div>}
+
+            {!loading && !error && triage && (
+              <>
+                <div className="triage-summary">
+                  {triage.pods.length === 0 && triage.workloads.length === 0
+                    ? `✅ All ${triage.total_pods} pod(s) are running`
+                    : `${triage.pods.length} of ${triage.total_pods} pod(s) not running, ${triage.workloads.length} workload(s) below desired replicas`}
+                </div>
+
+                {triage.workloads.map((workload) => (
+                  <div key={`${workload.kind}/${workload.namespace}/${workload.name}`} className="triage-card">
+                    <div className="triage-card-header">
+                      <span className="triage-kind">{workload.kind}</span>
+                      <span className="triage-name">{workload.namespace}/{workload.name}</span>
+                      <span className="triage-replicas">{workload.ready}/{workload.desired} ready</span>
+                    </div>
+                    {renderEvents(workload.events)}
+                  </div>
+                ))}
+
+                {triage.pods.map((pod) => (
+                  <div key={`${pod.namespace}/${pod.name}`} className="triage-card">
+                    <div className="triage-card-header">
+                      <span className="triage-kind">Pod</span>
+                      <span className="triage-name">{pod.namespace}/{pod.name}</span>
+                      <span className="triage-phase">{pod.phase}</span>
+                      {pod.restart_count > 0 && (
+                        <span className="triage-restarts">{pod.restart_count} restart(s)</span>
+                      )}
+                      <button
+                        className="btn btn-sm btn-secondary"
+                        onClick={() => setLogsView({ namespace: pod.namespace, podName: pod.name })}
+                      >
+                        📄 Logs
+                      </button>
+                    </div>
+                    {pod.message && (
+                      <div className="triage-pod-message">
+                        {pod.reason && <strong>{pod.reason}: </strong>}
+                        {pod.message}
+                      </div>
+                    )}
+                    <table className="triage-containers">
+                      <thead>
+                        <tr>
+                          <th>Container</th>
+                          <th>State</th>
+                          <th>Restarts</th>
+                          <th>Last Termination</th>
+                        </tr>
+                      </thead>
+                      <tbody>
+                        {pod.containers.map((container) => (
+                          <tr key={container.name}>
+                            <td>
+                              {container.name}
+                              {container.init && <span className="triage-init"> (init)</span>}
+                            </td>
+                            <td title={container.message}>
+                              {container.state}
+                              {container.reason && ` (${container.reason})`}
+                            </td>
+                            <td>{container.restart_count}</td>
+                            <td>
+                              {container.last_termination
+                                ? `${container.last_termination.reason} (exit ${container.last_termination.exit_code}) at ${new Date(container.last_termination.finished_at).toLocaleString()}`
+                                : '-'}
+                            </td>
+                          </tr>
+                        ))}
+                      </tbody>
+                    </table>
+                    {renderEvents(pod.events)}
+                  </div>
+                ))}
+              </>
+            )}
+          </div>
+        </div>
+      </div>
+
+      {logsView && (
+        <LogsViewer
+          clusterId={clusterId}
+          namespace={logsView.namespace}
+          podName={logsView.podName}
+          onClose={() => setLogsView(null)}
+        />
+      )}
+    </>
+  );
+};
+
+export default PodTriage;
@@ -0,0 +1,121 @@
+import React, { useState, useEffect } from 'react';
+import { grantApi } from '../api';
+import { ProviderGrants as Grants } from '../types';
+import '../styles/OAuthProviders.css';
+
+// Outcomes of the consent flow, reported back to the settings page as ?grant=
+const OUTCOMES: Record<string, string> = {
+  granted: 'GitHub access granted.',
+  denied: 'GitHub access was not granted.',
+  account_mismatch: 'The GitHub account that granted access is not the one you signed in with.',
+  unauthenticated: 'Sign in with GitHub before granting access.',
+  token_exchange_failed: 'GitHub did not issue a token; try again.',
+  failed: 'The granted token could not be stored.',
+};
+
+// Lets users grant, and revoke, a token the orchestrator keeps to call their git provider as them
+const ProviderGrants: React.FC = () => {
+  const [grants, setGrants] = useState<Grants | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [notice, setNotice] = useState<string | null>(() => {
+    const outcome = new URLSearchParams(window.location.search).get('grant');
+    return outcome ? OUTCOMES[outcome] || null : null;
+  });
+
+  useEffect(() => {
+    loadGrants();
+  }, []);
+
+  const loadGrants = async () => {
+    try {
+      setError(null);
+      const response = await grantApi.list();
+      setGrants(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load provider access');
+    }
+  };
+
+  const handleRevoke = async (provider: string) => {
+    if (!confirm(`Revoke ${provider} access? Features acting as you fall back to anonymous access.`)) {
+      return;
+    }
+
+    try {
+      const response = await grantApi.revoke(provider);
+      setNotice(response.data.message);
+      await loadGrants();
+    } catch (err: any) {
+      alert(err.response?.data?.error || 'Failed to revoke access');
+    }
+  };
+
+  if (!grants || (grants.available.length === 0 && grants.grants.length === 0)) {
+    return error ? <div className="oauth-error">{error}</div> : null;
+  }
+
+  return (
+    <div className="oauth-container">
+      <div className="oauth-header">
+        <h3>🔑 Git Provider Access</h3>
+      </div>
+
+      {error && <div className="oauth-error">{error}</div>}
+      {notice && <div className="oauth-info">{notice}</div>}
+
+      <div className="oauth-list">
+        {grants.available.filter(p => !grants.grants.some(g => g.provider === p)).map((provider) => (
+          <div key={provider} className="oauth-card">
+            <div className="oauth-card-body">
+              <p>
+                Let the orchestrator keep a {provider} token so features such as commit lookups act as you
+                and can read private repositories. It asks for the {grants.scopes.join(', ')} scope(s); the token is
+                stored encrypted and can be revoked at any time.
+              </p>
+              <a className="btn-primary" href={grantApi.authorizeUrl(provider)}>Grant {provider} access</a>
+            </div>
+          </div>
+        ))}
+        {grants.grants.map((grant) => (
+          <div key={grant.id} className="oauth-card">
+            <div className="oauth-card-header">
+              <div className="oauth-card-title">
+                <h4>{grant.provider}</h4>
+                <span className="enabled-badge">✓ Granted</span>
+              </div>
+              <div className="oauth-card-actions">
+                <button className="btn-icon btn-danger" onClick={() => handleRevoke(grant.provider)} title="Revoke">
+                  🗑️
+                </button>
+              </div>
+            </div>
+            <div className="oauth-card-body">
+              <div className="oauth-info">
+                <div className="info-row">
+                  <span className="label">Account:</span>
+                  <span className="value">{grant.username}</span>
+                </div>
+                <div className="info-row">
+                  <span className="label">Scopes:</span>
+                  <span className="value">{grant.scopes || 'none'}</span>
+                </div>
+                <div className="info-row">
+                  <span className="label">Granted:</span>
+                  <span className="value">{new Date(grant.created_at).toLocaleString()}</span>
+                </div>
+                {grant.last_used_at && (
+                  <div className="info-row">
+                    <span className="label">Last Used:</span>
+                    <span className="value">{new Date(grant.last_used_at).toLocaleString()}</span>
+                  </div>
+                )}
+              </div>
+            </div>
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+};
+
+export default ProviderGrants;
@@ -27,13 +27,44 @@ interface Permission {
   description: string;
 }
 
+interface RoleRef {
+  id: string;
+  name: string;
+}
+
+interface UserAccess {
+  user: string;
+  registered: boolean;
+  enabled: boolean;
+  roles: RoleRef[];
+  permissions: (Permission & { granted_by: RoleRef[] })[];
+}
+
+interface Decision {
+  permission: string;
+  allowed: boolean;
+  reason: string;
+  granted_by: RoleRef[];
+  granting_roles: RoleRef[];
+}
+
+interface ImportResult {
+  dry_run: boolean;
+  created: string[] | null;
+  updated: string[] | null;
+  unchanged: string[] | null;
+}
+
 const RBACSettings: React.FC = () => {
-  const [activeTab, setActiveTab] = useState<'users' | 'roles' | 'permissions'>('users');
+  const [activeTab, setActiveTab] = useState<'users' | 'roles' | 'permissions' | 'access'>('users');
   const [users, setUsers] = useState<User[]>([]);
   const [roles, setRoles] = useState<Role[]>([]);
   const [permissions, setPermissions] = useState<Permission[]>([]);
+  const [access, setAccess] = useState<UserAccess | null>(null);
+  const [decision, setDecision] = useState<Decision | null>(null);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
+  const [notice, setNotice] = useState<string | null>(null);
   
   // Selected items for editing
   const [selectedUser, setSelectedUser] = useState<User | null>(null);
@@ -60,6 +91,13 @@ const RBACSettings: React.FC = () => {
       } else if (activeTab === 'permissions') {
         const response = await rbacApi.listPermissions();
         setPermissions(response.data);
+      } else if (activeTab === 'access') {
+        const [accessResponse, permissionsResponse] = await Promise.all([
+          rbacApi.myPermissions(),
+          rbacApi.listPermissions(),
+        ]);
+        setAccess(accessResponse.data);
+        setPermissions(permissionsResponse.data);
       }
     } catch (err: any) {
       setError(err.response?.data?.error || 'Failed to load data');
@@ -70,7 +108,12 @@ const RBACSettings: React.FC = () => {
 
   const handleToggleUserEnabled = async (user: User) => {
     try {
-      await rbacApi.updateUser(user.id, { enabled: !user.enabled });
+      if (user.enabled) {
+        if (!confirm(`Disable ${user.email}? They are signed out at once and cannot log in until re-enabled.`)) return;
+        await rbacApi.disableUser(user.id);
+      } else {
+        await rbacApi.enableUser(user.id);
+      }
       loadData();
     } catch (err: any) {
       setError(err.response?.data?.error || 'Failed to update user');
@@ -134,6 +177,60 @@ const RBACSettings: React.FC = () => {
     }
   };
 
+  const handleExplain = async (permission: string) => {
+    setDecision(null);
+    if (!permission) return;
+    try {
+      const response = await rbacApi.explainPermission(permission);
+      setDecision(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to explain permission');
+    }
+  };
+
+  const handleExportConfig = async () => {
+    try {
+      const response = await rbacApi.exportConfig('yaml');
+      const url = window.URL.createObjectURL(new Blob([response.data]));
+      const link = document.createElement('a');
+      link.href = url;
+      link.setAttribute('download', 'rbac.yaml');
+      document.body.appendChild(link);
+      link.click();
+      link.remove();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to export RBAC configuration');
+    }
+  };
+
+  // Imports an exported configuration, previewing its changes with a dry run first
+  const handleImportConfig = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0];
+    e.target.value = '';
+    if (!file) return;
+
+    try {
+      setError(null);
+      setNotice(null);
+      const config = await file.text();
+      const preview: ImportResult = (await rbacApi.importConfig(config, true)).data;
+      const created = preview.created || [];
+      const updated = preview.updated || [];
+      if (created.length === 0 && updated.length === 0) {
+        setNotice('The RBAC configuration is already up to date.');
+        return;
+      }
+      const summary = [...created.map(entry => `+ ${entry}`), ...updated.map(entry => `~ ${entry}`)].join('\n');
+      if (!confirm(`Import ${file.name}? It creates ${created.length} and updates ${updated.length} entries:\n\n${summary}`)) return;
+
+      const result: ImportResult = (await rbacApi.importConfig(config)).data;
+      setNotice(`Imported ${file.name}: ${(result.created || []).length} created, ${(result.updated || []).length} updated.`);
+      loadData();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to import RBAC configuration');
+    }
+  };
+
   const groupPermissionsByResource = (perms: Permission[]) => {
     const grouped: { [key: string]: Permission[] } = {};
     perms.forEach(p => {
@@ -164,9 +261,25 @@ const RBACSettings: React.FC = () => {
         >
           Permissions
         </button>
+        <button
+          className={activeTab === 'access' ? 'active' : ''}
+          onClick={() => setActiveTab('access')}
+        >
+          My Access
+        </button>
+        <div className="rbac-config-actions">
+          <button onClick={handleExportConfig} title="Download roles, permissions and user roles as YAML">
+            Export
+          </button>
+          <label title="Apply an exported YAML or JSON configuration">
+            Import
+            <input type="file" accept=".yaml,.yml,.json" onChange={handleImportConfig} hidden />
+          </label>
+        </div>
       </div>
 
       {error && <div className="error-message">{error}</div>}
+      {notice && <div className="notice-message">{notice}</div>}
 
       {loading ? (
         <div className="loading">Loading...</div>
@@ -278,6 +391,60 @@ const RBACSettings: React.FC = () => {
               ))}
             </div>
           )}
+
+          {activeTab === 'access' && access && (
+            <div className="permissions-list">
+              <h3>My Access</h3>
+              <p>
+                {!access.user
+                  ? 'You are not signed in, so you hold no permissions.'
+                  : !access.registered
+                    ? `${access.user} has no user record yet, so it holds no roles.`
+                    : `${access.user}${access.enabled ? '' : ' (disabled, so none of these apply)'} holds the roles: ${access.roles.map(r => r.name).join(', ') || 'none'}.`}
+              </p>
+
+              <div className="explain-permission">
+                <label>
+                  Why can't I…
+                  <select defaultValue="" onChange={e => handleExplain(e.target.value)}>
+                    <option value="">Choose a permission</option>
+                    {permissions.map(p => (
+                      <option key={p.id} value={p.id}>{p.id} — {p.description}</option>
+                    ))}
+                  </select>
+                </label>
+                {decision && (
+                  <div className={`explain-decision ${decision.allowed ? 'allowed' : 'denied'}`}>
+                    <strong>{decision.allowed ? 'Allowed' : 'Denied'}:</strong> {decision.reason}.
+                    {!decision.allowed && decision.granting_roles.length > 0 && (
+                      <div>Roles granting {decision.permission}: {decision.granting_roles.map(r => r.name).join(', ')}</div>
+                    )}
+                  </div>
+                )}
+              </div>
+
+              <div className="permission-group">
+                <table>
+                  <thead>
+                    <tr>
+                      <th>Permission</th>
+                      <th>Description</th>
+                      <th>Granted By</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {access.permissions.map(p => (
+                      <tr key={p.id}>
+                        <td><code>{p.id}</code></td>
+                        <td>{p.description}</td>
+                        <td>{p.granted_by.map(r => r.name).join(', ')}</td>
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+              </div>
+            </div>
+          )}
         </div>
       )}
 
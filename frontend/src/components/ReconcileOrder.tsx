@@ -0,0 +1,135 @@
+import React, { useState, useEffect } from 'react';
+import { fluxApi } from '../api';
+import { ReconcileOrder as ReconcileOrderResult, ReconcileOrderNode, ObjectRef } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+
+interface ReconcileOrderProps {
+  clusterId: string;
+  source?: ObjectRef; // limit to what a new artifact of this source triggers
+  onClose: () => void;
+}
+
+const refName = (ref: ObjectRef) => `${ref.namespace ? `${ref.namespace}/` : ''}${ref.name}`;
+
+// Shows the stages Flux reconciles Kustomizations and HelmReleases in by their dependsOn
+const ReconcileOrder: React.FC<ReconcileOrderProps> = ({ clusterId, source, onClose }) => {
+  const [order, setOrder] = useState<ReconcileOrderResult | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  const sourceParam = source ? `${source.kind}/${source.namespace}/${source.name}` : undefined;
+
+  useEffect(() => {
+    loadOrder();
+  }, [clusterId, sourceParam]);
+
+  const loadOrder = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await fluxApi.getReconcileOrder(clusterId, sourceParam);
+      setOrder(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to compute reconcile order');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const state = (node: ReconcileOrderNode) => {
+    if (node.stage < 0) return { label: 'blocked', className: 'error' };
+    if (node.suspended) return { label: 'suspended', className: 'skipped' };
+    if (node.waiting) return { label: 'waiting', className: 'skipped' };
+    return node.ready ? { label: 'ready', className: 'applied' } : { label: 'not ready', className: 'error' };
+  };
+
+  const renderRows = (nodes: ReconcileOrderNode[], stageLabel: string) =>
+    nodes.map((node, idx) => (
+      <tr key={`${node.kind}/${node.namespace}/${node.name}`}>
+        <td>{idx === 0 ? stageLabel : ''}</td>
+        <td>{node.kind}</td>
+        <td>
+          {refName(node)}
+          {node.affected && order?.source && <span className="triage-init"> triggered</span>}
+        </td>
+        <td>{node.depends_on.length > 0 ? node.depends_on.map(refName).join(', ') : '-'}</td>
+        <td className={`helm-values-status ${state(node).className}`} title={node.message}>{state(node).label}</td>
+      </tr>
+    ));
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Reconcile Order</h2>
+            <div className="resource-info">
+              {source ? (
+                <>
+                  <span className="badge">{source.kind}</span>
+                  <span>{refName(source)}</span>
+                </>
+              ) : (
+                <span>All Kustomizations and HelmReleases</span>
+              )}
+            </div>
+          </div>
+          <div className="triage-controls">
+            <button className="btn btn-sm btn-secondary" onClick={loadOrder} disabled={loading}>
+              ↻ Refresh
+            </button>
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <div className="triage-body">
+          {loading && <div className="loading">Resolving dependsOn...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {!loading && order && (
+            <>
+              <p className="helm-values-hint">
+                {source
+                  ? `A new artifact from ${source.kind} ${refName(source)} reconciles the resources marked triggered, once the stages before them are Ready.`
+                  : 'Resources reconcile once every resource they depend on is Ready; resources in the same stage reconcile concurrently.'}
+              </p>
+              {order.issues.map(issue => (
+                <div key={issue.message} className="error-message">
+                  {issue.type === 'cycle' ? '⟲ ' : '⚠ '}{issue.message}
+                </div>
+              ))}
+
+              {order.stages.length === 0 && order.blocked.length === 0 ? (
+                <div className="triage-no-events">
+                  {source ? 'No Kustomization or HelmRelease uses this source.' : 'No Kustomizations or HelmReleases found.'}
+                </div>
+              ) : (
+                <table className="triage-containers">
+                  <thead>
+                    <tr>
+                      <th>Stage</th>
+                      <th>Kind</th>
+                      <th>Resource</th>
+                      <th>Depends On</th>
+                      <th>State</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {order.stages.map((nodes, stage) => (
+                      <React.Fragment key={stage}>{renderRows(nodes, String(stage + 1))}</React.Fragment>
+                    ))}
+                    {renderRows(order.blocked, 'Blocked')}
+                  </tbody>
+                </table>
+              )}
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ReconcileOrder;
@@ -0,0 +1,359 @@
+import React, { useState, useEffect } from 'react';
+import { registryApi } from '../api';
+import { RegistryCredential, RegistryProvider } from '../types';
+import '../styles/AzureSubscriptions.css';
+
+// Per-provider form hints; registry and username are filled in by the server when left empty
+const PROVIDERS: Record<RegistryProvider, { label: string; registry: string; username: string; password: string; note: string }> = {
+  acr: {
+    label: 'Azure Container Registry',
+    registry: 'myregistry.azurecr.io',
+    username: 'Service principal app ID or admin user',
+    password: 'Client secret or admin password',
+    note: 'The service principal needs the AcrPull role on the registry.',
+  },
+  ecr: {
+    label: 'Amazon ECR',
+    registry: '123456789012.dkr.ecr.us-east-1.amazonaws.com',
+    username: 'AWS',
+    password: 'Output of aws ecr get-login-password',
+    note: 'ECR tokens expire after 12 hours; update the password when tests start failing.',
+  },
+  gcr: {
+    label: 'Google Container / Artifact Registry',
+    registry: 'gcr.io or europe-docker.pkg.dev',
+    username: '_json_key',
+    password: 'Service account JSON key',
+    note: 'The service account needs the Artifact Registry Reader role.',
+  },
+  dockerhub: {
+    label: 'Docker Hub',
+    registry: 'docker.io',
+    username: 'Docker Hub username',
+    password: 'Personal access token',
+    note: 'A read-only access token is enough.',
+  },
+  generic: {
+    label: 'Other registry',
+    registry: 'registry.example.com',
+    username: 'Username',
+    password: 'Password or token',
+    note: 'Any registry implementing the OCI distribution API.',
+  },
+};
+
+const RegistryCredentials: React.FC = () => {
+  const [credentials, setCredentials] = useState<RegistryCredential[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [showAddDialog, setShowAddDialog] = useState(false);
+  const [editing, setEditing] = useState<RegistryCredential | null>(null);
+
+  useEffect(() => {
+    loadCredentials();
+  }, []);
+
+  const loadCredentials = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await registryApi.list();
+      setCredentials(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load registry credentials');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleDelete = async (credential: RegistryCredential) => {
+    if (!confirm(`Delete the credential for ${credential.registry}? Image reports will read it anonymously or with image pull Secrets only.`)) {
+      return;
+    }
+
+    try {
+      await registryApi.delete(credential.id);
+      await loadCredentials();
+    } catch (err: any) {
+      alert(err.response?.data?.error || 'Failed to delete registry credential');
+    }
+  };
+
+  const handleTestConnection = async (id: string) => {
+    try {
+      const response = await registryApi.test(id);
+      alert(response.data.message);
+    } catch (err: any) {
+      alert(err.response?.data?.error || 'Connection test failed');
+    } finally {
+      loadCredentials();
+    }
+  };
+
+  if (loading) {
+    return <div className="azure-loading">Loading registry credentials...</div>;
+  }
+
+  return (
+    <div className="azure-container">
+      <div className="azure-header">
+        <h3>📦 Container Registries</h3>
+        <button className="btn-add" onClick={() => setShowAddDialog(true)}>
+          + Add Registry
+        </button>
+      </div>
+
+      {error && <div className="azure-error">{error}</div>}
+
+      {credentials.length === 0 ? (
+        <div className="azure-empty">
+          <p>No registry credentials configured.</p>
+          <p>Image reports use image pull Secrets from the cluster; add a credential for private registries they do not cover.</p>
+          <button className="btn-primary" onClick={() => setShowAddDialog(true)}>
+            Add Your First Registry
+          </button>
+        </div>
+      ) : (
+        <div className="azure-list">
+          {credentials.map((credential) => (
+            <div key={credential.id} className="azure-card">
+              <div className="azure-card-header">
+                <div className="azure-card-title">
+                  <h4>{credential.name}</h4>
+                  <span className={`status-badge status-${credential.status}`}>
+                    {credential.status}
+                  </span>
+                </div>
+                <div className="azure-card-actions">
+                  <button
+                    className="btn-icon"
+                    onClick={() => handleTestConnection(credential.id)}
+                    title="Test Connection"
+                  >
+                    🔌
+                  </button>
+                  <button
+                    className="btn-icon"
+                    onClick={() => setEditing(credential)}
+                    title="Edit"
+                  >
+                    ✏️
+                  </button>
+                  <button
+                    className="btn-icon btn-danger"
+                    onClick={() => handleDelete(credential)}
+                    title="Delete"
+                  >
+                    🗑️
+                  </button>
+                </div>
+              </div>
+              <div className="azure-card-body">
+                <div className="azure-info">
+                  <div className="info-row">
+                    <span className="label">Registry:</span>
+                    <span className="value">{credential.registry}</span>
+                  </div>
+                  <div className="info-row">
+                    <span className="label">Provider:</span>
+                    <span className="value">{PROVIDERS[credential.provider]?.label || credential.provider}</span>
+                  </div>
+                  <div className="info-row">
+                    <span className="label">Username:</span>
+                    <span className="value">{credential.username}</span>
+                  </div>
+                  {credential.last_tested_at && (
+                    <div className="info-row">
+                      <span className="label">Last Tested:</span>
+                      <span className="value">
+                        {new Date(credential.last_tested_at).toLocaleString()}
+                      </span>
+                    </div>
+                  )}
+                  {credential.status_message && (
+                    <div className="info-row">
+                      <span className="label">Last Error:</span>
+                      <span className="value">{credential.status_message}</span>
+                    </div>
+                  )}
+                </div>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+
+      {(showAddDialog || editing) && (
+        <RegistryCredentialDialog
+          credential={editing}
+          onClose={() => {
+            setShowAddDialog(false);
+            setEditing(null);
+          }}
+          onSuccess={() => {
+            setShowAddDialog(false);
+            setEditing(null);
+            loadCredentials();
+          }}
+        />
+      )}
+    </div>
+  );
+};
+
+interface RegistryCredentialDialogProps {
+  credential: RegistryCredential | null; // null to add a new credential
+  onClose: () => void;
+  onSuccess: () => void;
+}
+
+const RegistryCredentialDialog: React.FC<RegistryCredentialDialogProps> = ({ credential, onClose, onSuccess }) => {
+  const [formData, setFormData] = useState({
+    name: credential?.name || '',
+    provider: credential?.provider || ('acr' as RegistryProvider),
+    registry: credential?.registry || '',
+    username: credential?.username || '',
+    password: '',
+  });
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const hints = PROVIDERS[formData.provider];
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+
+    if (!formData.name || (!credential && !formData.password)) {
+      setError('Name and password are required');
+      return;
+    }
+
+    try {
+      setSaving(true);
+      setError(null);
+
+      if (credential) {
+        await registryApi.update(credential.id, {
+          name: formData.name,
+          registry: formData.registry,
+          username: formData.username,
+          password: formData.password,
+          version: credential.version,
+        });
+      } else {
+        await registryApi.create(formData);
+      }
+
+      onSuccess();
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to save registry credential');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content" onClick={(e) => e.stopPropagation()}>
+        <div className="modal-header">
+          <h3>{credential ? `Edit ${credential.name}` : 'Add Registry Credential'}</h3>
+          <button className="btn-close" onClick={onClose}>×</button>
+        </div>
+
+        {error && <div className="modal-error">{error}</div>}
+
+        <form onSubmit={handleSubmit}>
+          <div className="form-group">
+            <label htmlFor="registry-name">Name</label>
+            <input
+              id="registry-name"
+              type="text"
+              value={formData.name}
+              onChange={(e) => setFormData({ ...formData, name: e.target.value })}
+              placeholder="Production registry"
+              disabled={saving}
+            />
+          </div>
+
+          <div className="form-group">
+            <label htmlFor="registry-provider">Provider</label>
+            <select
+              id="registry-provider"
+              value={formData.provider}
+              onChange={(e) => setFormData({ ...formData, provider: e.target.value as RegistryProvider })}
+              disabled={saving || !!credential}
+            >
+              {Object.entries(PROVIDERS).map(([value, provider]) => (
+                <option key={value} value={value}>{provider.label}</option>
+              ))}
+            </select>
+          </div>
+
+          <div className="form-group">
+            <label htmlFor="registry-host">Registry</label>
+            <input
+              id="registry-host"
+              type="text"
+              value={formData.registry}
+              onChange={(e) => setFormData({ ...formData, registry: e.target.value })}
+              placeholder={hints.registry}
+              disabled={saving}
+            />
+          </div>
+
+          <div className="form-group">
+            <label htmlFor="registry-username">Username</label>
+            <input
+              id="registry-username"
+              type="text"
+              value={formData.username}
+              onChange={(e) => setFormData({ ...formData, username: e.target.value })}
+              placeholder={hints.username}
+              disabled={saving}
+            />
+          </div>
+
+          <div className="form-group">
+            <label htmlFor="registry-password">Password</label>
+            {formData.provider === 'gcr' ? (
+              <textarea
+                id="registry-password"
+                value={formData.password}
+                onChange={(e) => setFormData({ ...formData, password: e.target.value })}
+                placeholder={credential ? 'Leave empty to keep the stored key' : hints.password}
+                rows={5}
+                disabled={saving}
+              />
+            ) : (
+              <input
+                id="registry-password"
+                type="password"
+                value={formData.password}
+                onChange={(e) => setFormData({ ...formData, password: e.target.value })}
+                placeholder={credential ? 'Leave empty to keep the stored password' : hints.password}
+                disabled={saving}
+              />
+            )}
+          </div>
+
+          <div className="form-info">
+            <p>
+              <strong>Note:</strong> {hints.note} The login is tested before it is saved, and image
+              pull Secrets in the cluster take precedence over it.
+            </p>
+          </div>
+
+          <div className="modal-footer">
+            <button type="button" onClick={onClose} disabled={saving}>
+              Cancel
+            </button>
+            <button type="submit" disabled={saving} className="btn-primary">
+              {saving ? 'Testing...' : credential ? 'Save' : 'Add Registry'}
+            </button>
+          </div>
+        </form>
+      </div>
+    </div>
+  );
+};
+
+export default RegistryCredentials;
@@ -1,9 +1,30 @@
-import React, { useState } from 'react';
-import { resourceApi } from '../api';
+import React, { useEffect, useState } from 'react';
+import { clusterApi, resourceApi } from '../api';
+import { AccessReviewRequest, SecretKeys } from '../types';
 import '../styles/ResourceActionMenu.css';
 import Toast from './Toast';
+import PodExec from './PodExec';
+import PodPortForward from './PodPortForward';
 import { useToast } from '../hooks/useToast';
 
+const IS_DEMO_MODE = import.meta.env.VITE_DEMO_MODE === 'true';
+
+type MenuAction = 'logs' | 'exec' | 'portForward' | 'showKeys' | 'restart' | 'scale' | 'evict' | 'delete';
+
+// The Kubernetes permission each action needs; restart and scale update the workload itself
+const requiredAccess = (action: MenuAction, kind: string, namespace: string, name: string): AccessReviewRequest => {
+  const target = { namespace, name };
+  switch (action) {
+    case 'logs': return { verb: 'get', resource: 'pods', subresource: 'log', ...target };
+    case 'exec': return { verb: 'create', resource: 'pods', subresource: 'exec', ...target };
+    case 'portForward': return { verb: 'create', resource: 'pods', subresource: 'portforward', ...target };
+    case 'showKeys': return { verb: 'get', resource: 'secrets', ...target };
+    case 'evict': return { verb: 'create', resource: 'pods', subresource: 'eviction', ...target };
+    case 'delete': return { verb: 'delete', resource: 'pods', ...target };
+    default: return { verb: 'update', resource: `${kind.toLowerCase()}s.apps`, ...target };
+  }
+};
+
 interface ResourceActionMenuProps {
   clusterId: string;
   kind: string;
@@ -23,14 +44,49 @@ const ResourceActionMenu: React.FC<ResourceActionMenuProps> = ({
 }) => {
   const [showMenu, setShowMenu] = useState(false);
   const [showScaleDialog, setShowScaleDialog] = useState(false);
+  const [showExec, setShowExec] = useState(false);
+  const [showPortForward, setShowPortForward] = useState(false);
+  const [secretKeys, setSecretKeys] = useState<SecretKeys | null>(null);
   const [replicas, setReplicas] = useState<number>(1);
   const [loading, setLoading] = useState(false);
+  // Reasons the cluster refuses actions, so they are shown disabled instead of failing
+  const [denied, setDenied] = useState<Partial<Record<MenuAction, string>>>({});
   const { toasts, success, error, removeToast } = useToast();
 
   const canScale = ['Deployment', 'StatefulSet', 'ReplicaSet'].includes(kind);
   const canRestart = ['Deployment', 'StatefulSet', 'DaemonSet'].includes(kind);
   const canViewLogs = kind === 'Pod';
   const canDelete = kind === 'Pod';
+  const canExec = kind === 'Pod' && !IS_DEMO_MODE;
+  const canPortForward = kind === 'Pod';
+  const canShowKeys = kind === 'Secret';
+
+  // Each time the menu opens, ask the cluster which of its actions the credentials may
+  // perform; actions stay enabled when the review itself fails
+  useEffect(() => {
+    if (!showMenu) return;
+    const shown: [MenuAction, boolean][] = [
+      ['logs', canViewLogs], ['exec', canExec], ['portForward', canPortForward], ['showKeys', canShowKeys],
+      ['restart', canRestart], ['scale', canScale], ['evict', canDelete], ['delete', canDelete],
+    ];
+    let cancelled = false;
+    Promise.all(shown.filter(([, visible]) => visible).map(async ([action]) => {
+      try {
+        const response = await clusterApi.accessReview(clusterId, requiredAccess(action, kind, namespace, name));
+        if (response.data.allowed) return null;
+        const who = response.data.user || 'the cluster credentials';
+        return [action, response.data.reason || `Not allowed for ${who} on this cluster`] as const;
+      } catch {
+        return null;
+      }
+    })).then((results) => {
+      if (cancelled) return;
+      const next: Partial<Record<MenuAction, string>> = {};
+      results.forEach((result) => { if (result) next[result[0]] = result[1]; });
+      setDenied(next);
+    });
+    return () => { cancelled = true; };
+  }, [showMenu, clusterId, kind, namespace, name]);
 
   const handleRestart = async () => {
     setLoading(true);
@@ -67,14 +123,38 @@ const ResourceActionMenu: React.FC<ResourceActionMenuProps> = ({
     }
   };
 
-  const handleDelete = async () => {
+  // Eviction goes through the Eviction API and is refused (429) by PodDisruptionBudgets
+  const handleDelete = async (evict = false) => {
     setLoading(true);
     try {
-      await resourceApi.deletePod(clusterId, namespace, name);
-      success(`Deleted Pod ${namespace}/${name}`);
+      await resourceApi.deletePod(clusterId, namespace, name, evict);
+      success(`${evict ? 'Evicted' : 'Deleted'} Pod ${namespace}/${name}`);
       onActionComplete?.();
     } catch (err: any) {
-      error(`Failed to delete: ${err.response?.data?.error || err.message}`);
+      const action = evict ? 'evict' : 'delete';
+      if (err.response?.status === 429) {
+        error(`Cannot ${action} now: ${err.response.data?.error || 'blocked by a PodDisruptionBudget'}`);
+      } else {
+        error(`Failed to ${action}: ${err.response?.data?.error || err.message}`);
+      }
+    } finally {
+      setLoading(false);
+      setShowMenu(false);
+    }
+  };
+
+  // Lists key names only; values never leave the cluster
+  const handleShowKeys = async () => {
+    setLoading(true);
+    try {
+      const response = await resourceApi.getSecretKeys(clusterId, namespace, name);
+      setSecretKeys(response.data);
+    } catch (err: any) {
+      if (err.response?.status === 403) {
+        error('Listing Secret keys requires the secret.reveal permission');
+      } else {
+        error(`Failed to list keys: ${err.response?.data?.error || err.message}`);
+      }
     } finally {
       setLoading(false);
       setShowMenu(false);
@@ -86,7 +166,7 @@ const ResourceActionMenu: React.FC<ResourceActionMenuProps> = ({
     onLogsClick?.();
   };
 
-  if (!canScale && !canRestart && !canViewLogs && !canDelete) {
+  if (!canScale && !canRestart && !canViewLogs && !canDelete && !canShowKeys) {
     return null;
   }
 
@@ -109,22 +189,42 @@ const ResourceActionMenu: React.FC<ResourceActionMenuProps> = ({
           <div className="action-menu-backdrop" onClick={() => setShowMenu(false)} />
           <div className="action-menu-dropdown">
             {canViewLogs && (
-              <button onClick={handleViewLogs}>
+              <button onClick={handleViewLogs} disabled={!!denied.logs} title={denied.logs}>
                 📋 View Logs
               </button>
             )}
+            {canExec && (
+              <button onClick={() => { setShowMenu(false); setShowExec(true); }} disabled={!!denied.exec} title={denied.exec}>
+                🖥️ Exec Shell
+              </button>
+            )}
+            {canPortForward && (
+              <button onClick={() => { setShowMenu(false); setShowPortForward(true); }} disabled={!!denied.portForward} title={denied.portForward}>
+                🔌 Port Forward
+              </button>
+            )}
+            {canShowKeys && (
+              <button onClick={handleShowKeys} disabled={loading || !!denied.showKeys} title={denied.showKeys}>
+                🔑 Show Keys
+              </button>
+            )}
             {canRestart && (
-              <button onClick={handleRestart} disabled={loading}>
+              <button onClick={handleRestart} disabled={loading || !!denied.restart} title={denied.restart}>
                 🔄 Restart
               </button>
             )}
             {canScale && (
-              <button onClick={() => { setShowMenu(false); setShowScaleDialog(true); }}>
+              <button onClick={() => { setShowMenu(false); setShowScaleDialog(true); }} disabled={!!denied.scale} title={denied.scale}>
                 📊 Scale
               </button>
             )}
             {canDelete && (
-              <button onClick={handleDelete} disabled={loading} className="danger">
+              <button onClick={() => handleDelete(true)} disabled={loading || !!denied.evict} title={denied.evict}>
+                ⏏️ Evict Pod
+              </button>
+            )}
+            {canDelete && (
+              <button onClick={() => handleDelete()} disabled={loading || !!denied.delete} title={denied.delete} className="danger">
                 🗑️ Delete Pod
               </button>
             )}
@@ -132,6 +232,49 @@ const ResourceActionMenu: React.FC<ResourceActionMenuProps> = ({
         </>
       )}
 
+      {showExec && (
+        <PodExec
+          clusterId={clusterId}
+          namespace={namespace}
+          podName={name}
+          onClose={() => setShowExec(false)}
+        />
+      )}
+
+      {showPortForward && (
+        <PodPortForward
+          clusterId={clusterId}
+          namespace={namespace}
+          podName={name}
+          onClose={() => setShowPortForward(false)}
+        />
+      )}
+
+      {secretKeys && (
+        <div className="scale-dialog-overlay" onClick={() => setSecretKeys(null)}>
+          <div className="scale-dialog" onClick={(e) => e.stopPropagation()}>
+            <h3>Secret Keys</h3>
+            <p>{namespace}/{name} ({secretKeys.type})</p>
+            {secretKeys.keys.length === 0 ? (
+              <p>This Secret has no keys.</p>
+            ) : (
+              <ul className="secret-keys">
+                {secretKeys.keys.map((key) => (
+                  <li key={key.name}>
+                    <code>{key.name}</code> <span>{key.size} bytes</span>
+                  </li>
+                ))}
+              </ul>
+            )}
+            <div className="scale-dialog-actions">
+              <button onClick={() => setSecretKeys(null)} className="primary">
+                Close
+              </button>
+            </div>
+          </div>
+        </div>
+      )}
+
       {showScaleDialog && (
         <div className="scale-dialog-overlay" onClick={() => setShowScaleDialog(false)}>
           <div className="scale-dialog" onClick={(e) => e.stopPropagation()}>
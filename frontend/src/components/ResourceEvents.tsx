@@ -0,0 +1,97 @@
+import React, { useState, useEffect } from 'react';
+import { resourceApi } from '../api';
+import { ResourceEvent } from '../types';
+import '../styles/PodTriage.css';
+import '../styles/ResourceEvents.css';
+
+interface ResourceEventsProps {
+  clusterId: string;
+  kind: string;
+  namespace: string;
+  name: string;
+  onClose: () => void;
+}
+
+// Shows the Kubernetes events of a resource, including its chart or ReplicaSets
+const ResourceEvents: React.FC<ResourceEventsProps> = ({ clusterId, kind, namespace, name, onClose }) => {
+  const [events, setEvents] = useState<ResourceEvent[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [warningsOnly, setWarningsOnly] = useState(false);
+
+  useEffect(() => {
+    loadEvents();
+  }, [clusterId, kind, namespace, name]);
+
+  const loadEvents = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await resourceApi.getEvents(clusterId, { kind, namespace, name });
+      setEvents(response.data.events);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load events');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const shown = warningsOnly ? events.filter((event) => event.type === 'Warning') : events;
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>Events</h2>
+            <div className="resource-info">
+              <span className="badge">{kind}</span>
+              <span>{namespace}/{name}</span>
+            </div>
+          </div>
+          <div className="triage-controls">
+            <label className="events-filter">
+              <input type="checkbox" checked={warningsOnly} onChange={(e) => setWarningsOnly(e.target.checked)} />
+              Warnings only
+            </label>
+            <button className="btn btn-sm btn-secondary" onClick={loadEvents} disabled={loading}>
+              ↻ Refresh
+            </button>
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <div className="triage-body">
+          {loading && <div className="loading">Loading events...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {!loading && !error && shown.length === 0 && (
+            <div className="triage-no-events">No events found. Kubernetes keeps events for about an hour.</div>
+          )}
+
+          {!loading && !error && shown.length > 0 && (
+            <ul className="triage-events">
+              {shown.map((event, idx) => (
+                <li key={idx} className={`triage-event ${event.type.toLowerCase()}`}>
+                  <span className="triage-event-reason">{event.reason}</span>
+                  {event.count > 1 && <span className="triage-event-count">×{event.count}</span>}
+                  {(event.object.kind !== kind || event.object.name !== name) && (
+                    <span className="events-object">{event.object.kind}/{event.object.name}</span>
+                  )}
+                  <span className="triage-event-message">{event.message}</span>
+                  {event.source && <span className="events-source">{event.source}</span>}
+                  {event.last_seen && (
+                    <span className="triage-event-time">{new Date(event.last_seen).toLocaleString()}</span>
+                  )}
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ResourceEvents;
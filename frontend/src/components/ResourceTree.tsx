@@ -1,6 +1,6 @@
 import React, { useState, useEffect } from 'react';
-import { ResourceNode } from '../types';
-import { clusterApi } from '../api';
+import { ListSelectors, PodMetrics, ResourceNode, ResourceUsage } from '../types';
+import { clusterApi, resourceApi } from '../api';
 import ResourceActionMenu from './ResourceActionMenu';
 import LogsViewer from './LogsViewer';
 import '../styles/ResourceTree.css';
@@ -17,16 +17,23 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
   const [logsView, setLogsView] = useState<{ namespace: string; podName: string } | null>(null);
   const [showComputeOnly, setShowComputeOnly] = useState(false);
   const [viewMode, setViewMode] = useState<'tree' | 'graph'>('tree');
+  // Selectors are applied by the API server; the inputs only take effect on Apply
+  const [selectors, setSelectors] = useState<ListSelectors>({});
+  const [labelInput, setLabelInput] = useState('');
+  const [fieldInput, setFieldInput] = useState('');
+  // Container breakdowns of pods opened from their usage, or the error loading one
+  const [podMetrics, setPodMetrics] = useState<Record<string, PodMetrics | string>>({});
 
   useEffect(() => {
     loadTree();
-  }, [clusterId]);
+  }, [clusterId, selectors]);
 
-  const loadTree = async () => {
+  // The server caches trees briefly; refresh rebuilds it, such as after an action
+  const loadTree = async (refresh = false) => {
     try {
       setLoading(true);
       setError(null);
-      const response = await clusterApi.getResourceTree(clusterId);
+      const response = await clusterApi.getResourceTree(clusterId, selectors, refresh);
       setTree(response.data.tree);
     } catch (err: any) {
       setError(err.response?.data?.error || 'Failed to load resource tree');
@@ -65,6 +72,80 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
     setExpandedNodes(new Set());
   };
 
+  const togglePodMetrics = async (node: ResourceNode) => {
+    if (podMetrics[node.id]) {
+      setPodMetrics(prev => {
+        const next = { ...prev };
+        delete next[node.id];
+        return next;
+      });
+      return;
+    }
+    try {
+      const response = await resourceApi.getPodMetrics(clusterId, node.namespace, node.name);
+      setPodMetrics(prev => ({ ...prev, [node.id]: response.data }));
+    } catch (err: any) {
+      setPodMetrics(prev => ({ ...prev, [node.id]: err.response?.data?.error || 'Failed to load pod metrics' }));
+    }
+  };
+
+  const formatCPU = (millicores: number) =>
+    millicores >= 1000 ? `${(millicores / 1000).toFixed(2)} cores` : `${millicores}m`;
+
+  const formatMemory = (bytes: number) => {
+    const mi = bytes / (1024 * 1024);
+    return mi >= 1024 ? `${(mi / 1024).toFixed(2)}Gi` : `${Math.round(mi)}Mi`;
+  };
+
+  const formatUsage = (usage: ResourceUsage) =>
+    `CPU ${formatCPU(usage.cpu_millicores)} · Mem ${formatMemory(usage.memory_bytes)}`;
+
+  // Usage against a request or limit, or a dash when it is unset
+  const formatShare = (used: number, of: number, format: (value: number) => string) =>
+    of > 0 ? `${format(of)} (${Math.round((used / of) * 100)}%)` : '—';
+
+  const renderPodMetrics = (metrics: PodMetrics | string) => {
+    if (typeof metrics === 'string') {
+      return <div className="pod-metrics pod-metrics-error">{metrics}</div>;
+    }
+    return (
+      <div className="pod-metrics">
+        <table>
+          <thead>
+            <tr>
+              <th>Container</th>
+              <th>CPU</th>
+              <th>CPU request</th>
+              <th>CPU limit</th>
+              <th>Memory</th>
+              <th>Memory request</th>
+              <th>Memory limit</th>
+            </tr>
+          </thead>
+          <tbody>
+            {metrics.containers.map(container => (
+              <tr key={container.name}>
+                <td>{container.name}</td>
+                <td>{formatCPU(container.usage.cpu_millicores)}</td>
+                <td>{formatShare(container.usage.cpu_millicores, container.requests.cpu_millicores, formatCPU)}</td>
+                <td>{formatShare(container.usage.cpu_millicores, container.limits.cpu_millicores, formatCPU)}</td>
+                <td>{formatMemory(container.usage.memory_bytes)}</td>
+                <td>{formatShare(container.usage.memory_bytes, container.requests.memory_bytes, formatMemory)}</td>
+                <td>{formatShare(container.usage.memory_bytes, container.limits.memory_bytes, formatMemory)}</td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+        {metrics.node && (
+          <div className="pod-metrics-node">
+            Node {metrics.node.name}: CPU {formatShare(metrics.node.usage.cpu_millicores, metrics.node.allocatable.cpu_millicores, formatCPU)} in use,
+            memory {formatShare(metrics.node.usage.memory_bytes, metrics.node.allocatable.memory_bytes, formatMemory)} in use
+          </div>
+        )}
+      </div>
+    );
+  };
+
   const getHealthClass = (health: string) => {
     switch (health) {
       case 'Healthy':
@@ -84,6 +165,11 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
       HelmRelease: '⎈',
       GitRepository: '📚',
       HelmRepository: '📊',
+      OCIRepository: '🗂️',
+      Bucket: '🪣',
+      Alert: '🔔',
+      Provider: '📣',
+      Receiver: '📥',
       Deployment: '🚀',
       StatefulSet: '💾',
       DaemonSet: '👥',
@@ -154,6 +240,17 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
               {node.status && node.status !== 'Unknown' && (
                 <span className="status-text">{node.status}</span>
               )}
+              {node.usage && (node.kind === 'Pod' ? (
+                <button
+                  className="usage-text usage-toggle"
+                  onClick={() => togglePodMetrics(node)}
+                  title="Show usage by container"
+                >
+                  {formatUsage(node.usage)}
+                </button>
+              ) : (
+                <span className="usage-text" title="Total usage of the pods below">{formatUsage(node.usage)}</span>
+              ))}
             </div>
           </div>
 
@@ -163,9 +260,11 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
             namespace={node.namespace || ''}
             name={node.name}
             onLogsClick={() => setLogsView({ namespace: node.namespace || '', podName: node.name })}
-            onActionComplete={loadTree}
+            onActionComplete={() => loadTree(true)}
           />
         </div>
+
+        {podMetrics[node.id] && renderPodMetrics(podMetrics[node.id])}
         
         {isExpanded && hasChildren && (
           <div className="node-children">
@@ -176,6 +275,43 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
     );
   };
 
+  const applySelectors = (e: React.FormEvent) => {
+    e.preventDefault();
+    setSelectors({
+      labelSelector: labelInput.trim() || undefined,
+      fieldSelector: fieldInput.trim() || undefined,
+    });
+  };
+
+  const clearSelectors = () => {
+    setLabelInput('');
+    setFieldInput('');
+    setSelectors({});
+  };
+
+  const hasSelectors = Boolean(selectors.labelSelector || selectors.fieldSelector);
+
+  const renderSelectors = () => (
+    <form className="tree-selectors" onSubmit={applySelectors}>
+      <input
+        type="text"
+        value={labelInput}
+        onChange={(e) => setLabelInput(e.target.value)}
+        placeholder="Label selector, e.g. app.kubernetes.io/part-of=payments"
+      />
+      <input
+        type="text"
+        value={fieldInput}
+        onChange={(e) => setFieldInput(e.target.value)}
+        placeholder="Field selector, e.g. metadata.namespace=payments"
+      />
+      <button type="submit" className="btn-tree-action">Apply</button>
+      {hasSelectors && (
+        <button type="button" onClick={clearSelectors} className="btn-tree-action">Clear</button>
+      )}
+    </form>
+  );
+
   if (loading) {
     return <div className="tree-loading">Loading resource tree...</div>;
   }
@@ -184,12 +320,14 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
     return (
       <div className="tree-error">
         <p>Error: {error}</p>
-        <button onClick={loadTree} className="btn-retry">Retry</button>
+        <button onClick={hasSelectors ? clearSelectors : () => loadTree(true)} className="btn-retry">
+          {hasSelectors ? 'Clear Selectors' : 'Retry'}
+        </button>
       </div>
     );
   }
 
-  if (tree.length === 0) {
+  if (tree.length === 0 && !hasSelectors) {
     return <div className="tree-empty">No resources found</div>;
   }
 
@@ -220,9 +358,10 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
             >
               📊 Graph View
             </button>
-            <button onClick={loadTree} className="btn-tree-action">Refresh</button>
+            <button onClick={() => loadTree(true)} className="btn-tree-action">Refresh</button>
           </div>
         </div>
+        {renderSelectors()}
         
         <div className="graph-view">
           <div className="graph-container">
@@ -261,12 +400,16 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
           </button>
           <button onClick={expandAll} className="btn-tree-action">Expand All</button>
           <button onClick={collapseAll} className="btn-tree-action">Collapse All</button>
-          <button onClick={loadTree} className="btn-tree-action">Refresh</button>
+          <button onClick={() => loadTree(true)} className="btn-tree-action">Refresh</button>
         </div>
       </div>
+      {renderSelectors()}
       
       <div className="tree-content">
         {filteredTree.map(node => renderNode(node))}
+        {hasSelectors && filteredTree.length === 0 && (
+          <div className="tree-empty">No resources match the selectors</div>
+        )}
       </div>
       
       <div className="tree-footer">
@@ -315,6 +458,7 @@ const ResourceTree: React.FC<ResourceTreeProps> = ({ clusterId }) => {
           
           <div className="graph-node-status">
             <span className="status-text">{node.status}</span>
+            {node.usage && <span className="usage-text">{formatUsage(node.usage)}</span>}
           </div>
         </div>
 
@@ -0,0 +1,98 @@
+import React, { useState, useEffect } from 'react';
+import { resourceApi } from '../api';
+import '../styles/PodTriage.css';
+import '../styles/HelmValues.css';
+import '../styles/ResourceYAML.css';
+
+interface ResourceYAMLProps {
+  clusterId: string;
+  kind: string;
+  namespace: string;
+  name: string;
+  onClose: () => void;
+}
+
+// Shows the live object of a resource as YAML, exactly as stored in the cluster
+const ResourceYAML: React.FC<ResourceYAMLProps> = ({ clusterId, kind, namespace, name, onClose }) => {
+  const [yaml, setYaml] = useState('');
+  const [redacted, setRedacted] = useState(false);
+  const [copied, setCopied] = useState(false);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    loadYAML();
+  }, [clusterId, kind, namespace, name]);
+
+  const loadYAML = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await resourceApi.getYAML(clusterId, kind, namespace, name);
+      setYaml(response.data);
+      setRedacted((response as { headers?: Record<string, string> }).headers?.['x-secret-values-redacted'] === 'true');
+    } catch (err: any) {
+      // Errors are JSON even though the request expects text
+      let message = err.response?.data?.error;
+      if (!message && typeof err.response?.data === 'string') {
+        try {
+          message = JSON.parse(err.response.data).error;
+        } catch {
+          message = undefined;
+        }
+      }
+      setError(message || 'Failed to load YAML');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const copy = async () => {
+    await navigator.clipboard.writeText(yaml);
+    setCopied(true);
+    setTimeout(() => setCopied(false), 1500);
+  };
+
+  return (
+    <div className="modal-overlay" onClick={onClose}>
+      <div className="modal-content triage-modal" onClick={e => e.stopPropagation()}>
+        <div className="triage-header">
+          <div>
+            <h2>YAML</h2>
+            <div className="resource-info">
+              <span className="badge">{kind}</span>
+              <span>{namespace}/{name}</span>
+            </div>
+          </div>
+          <div className="triage-controls">
+            <button className="btn btn-sm btn-secondary" onClick={copy} disabled={loading || !yaml}>
+              {copied ? '✓ Copied' : '⧉ Copy'}
+            </button>
+            <button className="btn btn-sm btn-secondary" onClick={loadYAML} disabled={loading}>
+              ↻ Refresh
+            </button>
+            <button className="btn-close" onClick={onClose}>✕</button>
+          </div>
+        </div>
+
+        <div className="triage-body">
+          {loading && <div className="loading">Loading...</div>}
+
+          {error && <div className="error-message">{error}</div>}
+
+          {!loading && !error && (
+            <>
+              <p className="helm-values-hint">
+                The live object without metadata.managedFields.
+                {redacted && ' Secret values are redacted; the secret.reveal permission shows them.'}
+              </p>
+              <pre className="helm-values-yaml resource-yaml">{yaml}</pre>
+            </>
+          )}
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ResourceYAML;
@@ -1,17 +1,27 @@
 import React, { useState, useEffect } from 'react';
 import { settingsApi } from '../api';
 import AzureSubscriptions from './AzureSubscriptions';
+import RegistryCredentials from './RegistryCredentials';
 import OAuthProviders from './OAuthProviders';
+import ProviderGrants from './ProviderGrants';
 import RBACSettings from './RBACSettings';
+import SystemStatus from './SystemStatus';
 import '../styles/Settings.css';
 
 const Settings: React.FC = () => {
-  const [activeTab, setActiveTab] = useState<'general' | 'azure' | 'oauth' | 'rbac'>('general');
+  // The GitHub consent flow returns here with ?grant=
+  const [activeTab, setActiveTab] = useState<'general' | 'azure' | 'registries' | 'oauth' | 'rbac' | 'system'>(
+    new URLSearchParams(window.location.search).has('grant') ? 'oauth' : 'general'
+  );
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [saving, setSaving] = useState(false);
   const [autoSyncInterval, setAutoSyncInterval] = useState<string>('5');
   const [auditLogRetention, setAuditLogRetention] = useState<string>('90');
+  const [scaleProtectedNamespaces, setScaleProtectedNamespaces] = useState<string>('');
+  const [scaleMaxReplicas, setScaleMaxReplicas] = useState<string>('0');
+  const [namespaceNamePattern, setNamespaceNamePattern] = useState<string>('');
+  const [namespaceProtected, setNamespaceProtected] = useState<string>('');
   const [cleaningUp, setCleaningUp] = useState(false);
   const [showCleanupModal, setShowCleanupModal] = useState(false);
 
@@ -36,6 +46,26 @@ const Settings: React.FC = () => {
       if (retention) {
         setAuditLogRetention(retention.value);
       }
+
+      // Find scale guardrail settings
+      const protectedNamespaces = response.data.find(s => s.key === 'scale_protected_namespaces');
+      if (protectedNamespaces) {
+        setScaleProtectedNamespaces(protectedNamespaces.value.split(',').map(ns => ns.trim()).filter(Boolean).join(', '));
+      }
+      const maxReplicas = response.data.find(s => s.key === 'scale_max_replicas');
+      if (maxReplicas) {
+        setScaleMaxReplicas(maxReplicas.value);
+      }
+
+      // Find namespace guardrail settings
+      const namePattern = response.data.find(s => s.key === 'namespace_name_pattern');
+      if (namePattern) {
+        setNamespaceNamePattern(namePattern.value === '.*' ? '' : namePattern.value);
+      }
+      const protectedList = response.data.find(s => s.key === 'namespace_protected');
+      if (protectedList) {
+        setNamespaceProtected(protectedList.value.split(',').map(ns => ns.trim()).filter(Boolean).join(', '));
+      }
     } catch (err: any) {
       // If settings table doesn't exist yet, use defaults (will be created on first save)
       const errorMsg = err.response?.data?.error || '';
@@ -94,6 +124,56 @@ const Settings: React.FC = () => {
     }
   };
 
+  const handleSaveScaleGuardrails = async () => {
+    try {
+      setSaving(true);
+      setError(null);
+
+      const max = parseInt(scaleMaxReplicas);
+      if (isNaN(max) || max < 0) {
+        setError('Max replicas must be 0 (unlimited) or a positive number');
+        return;
+      }
+
+      // Settings cannot be empty, a lone comma clears the list
+      await settingsApi.update('scale_protected_namespaces', scaleProtectedNamespaces.trim() || ',');
+      await settingsApi.update('scale_max_replicas', String(max));
+      await loadSettings();
+      setError('✅ Scale guardrails updated');
+      setTimeout(() => setError(null), 4000);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to update setting');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleSaveNamespaceGuardrails = async () => {
+    try {
+      setSaving(true);
+      setError(null);
+
+      const pattern = namespaceNamePattern.trim();
+      try {
+        new RegExp(pattern);
+      } catch {
+        setError('Naming convention must be a valid regular expression');
+        return;
+      }
+
+      // Settings cannot be empty, .* allows any name and a lone comma clears the list
+      await settingsApi.update('namespace_name_pattern', pattern || '.*');
+      await settingsApi.update('namespace_protected', namespaceProtected.trim() || ',');
+      await loadSettings();
+      setError('✅ Namespace guardrails updated');
+      setTimeout(() => setError(null), 4000);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to update setting');
+    } finally {
+      setSaving(false);
+    }
+  };
+
   const handleCleanupNow = () => {
     setShowCleanupModal(true);
   };
@@ -198,6 +278,12 @@ const Settings: React.FC = () => {
         >
           Azure AKS
         </button>
+        <button
+          className={`tab-button ${activeTab === 'registries' ? 'active' : ''}`}
+          onClick={() => setActiveTab('registries')}
+        >
+          Registries
+        </button>
         <button
           className={`tab-button ${activeTab === 'oauth' ? 'active' : ''}`}
           onClick={() => setActiveTab('oauth')}
@@ -210,6 +296,12 @@ const Settings: React.FC = () => {
         >
           RBAC
         </button>
+        <button
+          className={`tab-button ${activeTab === 'system' ? 'active' : ''}`}
+          onClick={() => setActiveTab('system')}
+        >
+          System
+        </button>
       </div>
 
       {activeTab === 'general' && (
@@ -294,6 +386,104 @@ const Settings: React.FC = () => {
               </div>
             </div>
 
+            <div className="setting-section">
+              <h3>Scale Guardrails</h3>
+              <div className="setting-item">
+                <label htmlFor="scale-protected-namespaces">
+                  <strong>Protected Namespaces</strong>
+                  <p className="setting-description">
+                    Comma-separated namespaces whose workloads cannot be scaled to zero.
+                    Wildcards such as <code>prod-*</code> are allowed.
+                  </p>
+                </label>
+                <div className="setting-control">
+                  <input
+                    id="scale-protected-namespaces"
+                    type="text"
+                    placeholder="kube-system, prod-*"
+                    value={scaleProtectedNamespaces}
+                    onChange={(e) => setScaleProtectedNamespaces(e.target.value)}
+                    disabled={saving}
+                  />
+                </div>
+              </div>
+              <div className="setting-item">
+                <label htmlFor="scale-max-replicas">
+                  <strong>Maximum Replicas</strong>
+                  <p className="setting-description">
+                    The most replicas a workload can be scaled to. 0 means unlimited.
+                  </p>
+                </label>
+                <div className="setting-control">
+                  <input
+                    id="scale-max-replicas"
+                    type="number"
+                    min="0"
+                    value={scaleMaxReplicas}
+                    onChange={(e) => setScaleMaxReplicas(e.target.value)}
+                    disabled={saving}
+                  />
+                  <button
+                    onClick={handleSaveScaleGuardrails}
+                    disabled={saving}
+                    className="btn-save"
+                  >
+                    {saving ? 'Saving...' : 'Save'}
+                  </button>
+                </div>
+              </div>
+            </div>
+
+            <div className="setting-section">
+              <h3>Namespace Guardrails</h3>
+              <div className="setting-item">
+                <label htmlFor="namespace-name-pattern">
+                  <strong>Naming Convention</strong>
+                  <p className="setting-description">
+                    A regular expression every created namespace name must match in full.
+                    Leave empty to allow any valid name.
+                  </p>
+                </label>
+                <div className="setting-control">
+                  <input
+                    id="namespace-name-pattern"
+                    type="text"
+                    placeholder="(dev|staging|prod)-[a-z0-9-]+"
+                    value={namespaceNamePattern}
+                    onChange={(e) => setNamespaceNamePattern(e.target.value)}
+                    disabled={saving}
+                  />
+                </div>
+              </div>
+              <div className="setting-item">
+                <label htmlFor="namespace-protected">
+                  <strong>Protected Namespaces</strong>
+                  <p className="setting-description">
+                    Comma-separated namespaces that cannot be created or deleted, on top of
+                    default, kube-system, kube-public, kube-node-lease and flux-system.
+                    Wildcards such as <code>prod-*</code> are allowed.
+                  </p>
+                </label>
+                <div className="setting-control">
+                  <input
+                    id="namespace-protected"
+                    type="text"
+                    placeholder="prod-*, shared-services"
+                    value={namespaceProtected}
+                    onChange={(e) => setNamespaceProtected(e.target.value)}
+                    disabled={saving}
+                  />
+                  <button
+                    onClick={handleSaveNamespaceGuardrails}
+                    disabled={saving}
+                    className="btn-save"
+                  >
+                    {saving ? 'Saving...' : 'Save'}
+                  </button>
+                </div>
+              </div>
+            </div>
+
             <div className="setting-section">
               <h3>Information</h3>
               <div className="info-box">
@@ -317,13 +507,24 @@ const Settings: React.FC = () => {
         <AzureSubscriptions />
       )}
 
+      {activeTab === 'registries' && (
+        <RegistryCredentials />
+      )}
+
       {activeTab === 'oauth' && (
-        <OAuthProviders />
+        <>
+          <ProviderGrants />
+          <OAuthProviders />
+        </>
       )}
 
       {activeTab === 'rbac' && (
         <RBACSettings />
       )}
+
+      {activeTab === 'system' && (
+        <SystemStatus />
+      )}
     </div>
     </>
   );
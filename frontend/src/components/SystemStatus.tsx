@@ -0,0 +1,86 @@
+import React, { useState, useEffect } from 'react';
+import { systemApi } from '../api';
+import { SystemStatus as SystemStatusReport, ComponentState } from '../types';
+
+const stateColors: Record<ComponentState, string> = {
+  ok: '#16a34a',
+  degraded: '#d97706',
+  down: '#dc2626',
+  disabled: '#6b7280',
+};
+
+const formatValue = (value: unknown): string => {
+  if (value !== null && typeof value === 'object') {
+    return Object.entries(value as Record<string, unknown>)
+      .map(([k, v]) => `${k}: ${v}`)
+      .join(', ') || 'none';
+  }
+  return String(value);
+};
+
+const SystemStatus: React.FC = () => {
+  const [report, setReport] = useState<SystemStatusReport | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    loadStatus();
+    const interval = setInterval(loadStatus, 30000);
+    return () => clearInterval(interval);
+  }, []);
+
+  const loadStatus = async () => {
+    try {
+      setError(null);
+      const response = await systemApi.getStatus();
+      setReport(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to load system status');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (loading) {
+    return <div className="loading">Loading system status...</div>;
+  }
+
+  return (
+    <div className="settings-content">
+      {error && <div className="settings-error">{error}</div>}
+
+      {report && (
+        <div className="setting-section">
+          <h3>
+            System Status:{' '}
+            <span style={{ color: stateColors[report.status] }}>{report.status}</span>
+          </h3>
+          <p className="setting-hint">
+            Checked {new Date(report.checked_at).toLocaleString()}
+          </p>
+          {report.components.map((component) => (
+            <div className="setting-item" key={component.name}>
+              <strong>{component.name}</strong>{' '}
+              <span style={{ color: stateColors[component.status] }}>{component.status}</span>
+              {component.message && (
+                <p className="setting-description">{component.message}</p>
+              )}
+              {component.details && (
+                <p className="setting-hint">
+                  {Object.entries(component.details)
+                    .map(([key, value]) => `${key}: ${formatValue(value)}`)
+                    .join(' · ')}
+                </p>
+              )}
+            </div>
+          ))}
+          <button onClick={loadStatus} className="btn-save">
+            Refresh
+          </button>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default SystemStatus;
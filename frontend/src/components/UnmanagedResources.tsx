@@ -0,0 +1,111 @@
+import React, { useState, useEffect } from 'react';
+import { UnmanagedReport } from '../types';
+import { clusterApi } from '../api';
+import '../styles/NamespaceQuotas.css';
+
+interface UnmanagedResourcesProps {
+  clusterId: string;
+}
+
+const UnmanagedResources: React.FC<UnmanagedResourcesProps> = ({ clusterId }) => {
+  const [data, setData] = useState<UnmanagedReport | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [namespace, setNamespace] = useState('');
+
+  useEffect(() => {
+    loadReport();
+  }, [clusterId]);
+
+  const loadReport = async () => {
+    try {
+      setLoading(true);
+      setError(null);
+      const response = await clusterApi.getUnmanaged(clusterId, namespace.trim() || undefined);
+      setData(response.data);
+    } catch (err: any) {
+      setError(err.response?.data?.error || 'Failed to scan for unmanaged resources');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleSubmit = (e: React.FormEvent) => {
+    e.preventDefault();
+    loadReport();
+  };
+
+  if (loading) {
+    return <div className="quota-loading">Scanning for unmanaged resources...</div>;
+  }
+
+  if (error) {
+    return (
+      <div className="quota-error">
+        <p>Error: {error}</p>
+        <button onClick={loadReport} className="btn-retry">Retry</button>
+      </div>
+    );
+  }
+
+  const byNamespace = data
+    ? Object.keys(data.by_namespace).sort().map((ns) => ({
+        namespace: ns,
+        objects: data.objects.filter((o) => o.namespace === ns),
+      }))
+    : [];
+
+  return (
+    <div className="namespace-quotas">
+      <div className="quota-header">
+        <h3>Unmanaged Resources</h3>
+        {data && (
+          <div className="quota-summary">
+            <span>{data.objects.length} of {data.scanned} workload(s) not managed by Flux</span>
+            {data.skipped_namespaces && data.skipped_namespaces.length > 0 && (
+              <span>Skipped {data.skipped_namespaces.join(', ')}</span>
+            )}
+          </div>
+        )}
+        <form className="quota-actions" onSubmit={handleSubmit}>
+          <input
+            type="text"
+            placeholder="All namespaces"
+            value={namespace}
+            onChange={(e) => setNamespace(e.target.value)}
+          />
+          <button type="submit" className="btn-tree-action">Scan</button>
+        </form>
+      </div>
+
+      {data?.warnings.map((warning) => (
+        <div key={warning} className="quota-app at-risk">⚠️ {warning}</div>
+      ))}
+
+      {byNamespace.length === 0 ? (
+        <div className="quota-empty">Every workload scanned is managed by Flux</div>
+      ) : (
+        byNamespace.map(({ namespace: ns, objects }) => (
+          <div key={ns} className="quota-namespace near-limit">
+            <div className="quota-namespace-header">
+              <span className="quota-namespace-name">📁 {ns}</span>
+              <span className="quota-kind-count">{objects.length} unmanaged</span>
+            </div>
+            <div className="quota-apps">
+              {objects.map((obj) => (
+                <div key={`${obj.kind}/${obj.name}`} className="quota-app">
+                  <span className="quota-app-kind">{obj.kind}</span>
+                  <span className="quota-app-name">{obj.name}</span>
+                  {obj.managed_by && <span className="quota-kind-count">managed by {obj.managed_by}</span>}
+                  <span className="quota-kind-count">created {new Date(obj.created_at).toLocaleDateString()}</span>
+                </div>
+              ))}
+            </div>
+          </div>
+        ))
+      )}
+    </div>
+  );
+};
+
+export default UnmanagedResources;
@@ -17,6 +17,7 @@ interface AuthContextType {
   isAuthenticated: boolean;
   isLoading: boolean;
   authEnabled: boolean;
+  anonymousReadOnly: boolean;
   login: () => void;
   logout: () => Promise<void>;
   checkAuth: () => Promise<void>;
@@ -40,6 +41,9 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
   const [user, setUser] = useState<UserInfo | null>(null);
   const [isLoading, setIsLoading] = useState(true);
   const [authEnabled, setAuthEnabled] = useState(false);
+  const [anonymousReadOnly, setAnonymousReadOnly] = useState(false);
+  // Behind an authenticating proxy, the proxy signs users in and out
+  const [proxyAuth, setProxyAuth] = useState<{ logoutUrl?: string } | null>(null);
 
   const checkAuth = async () => {
     try {
@@ -62,8 +66,10 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
       }
       
       // Check if auth is enabled
-      const statusResponse = await fluxApi.axios.get<{ enabled: boolean }>('/auth/status');
+      const statusResponse = await fluxApi.axios.get<{ enabled: boolean; anonymous_read_only?: boolean; mode?: 'oauth' | 'proxy'; logout_url?: string }>('/auth/status');
       setAuthEnabled(statusResponse.data.enabled);
+      setAnonymousReadOnly(!!statusResponse.data.anonymous_read_only);
+      setProxyAuth(statusResponse.data.mode === 'proxy' ? { logoutUrl: statusResponse.data.logout_url || undefined } : null);
       
       if (!statusResponse.data.enabled) {
         // Auth is disabled, no need to check user
@@ -90,8 +96,13 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
   };
 
   const login = () => {
+    if (proxyAuth) {
+      // Reloading sends the browser through the proxy's sign-in again
+      window.location.reload();
+      return;
+    }
     if (fluxApi.axios) {
-      window.location.href = `${fluxApi.axios.defaults.baseURL}/auth/login`;
+      window.location.href = `${fluxApi.axios.defaults.baseURL}/auth/login?return_to=${encodeURIComponent(window.location.pathname + window.location.search + window.location.hash)}`;
     }
   };
 
@@ -100,6 +111,10 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
       if (fluxApi.axios) {
         await fluxApi.axios.post('/auth/logout');
       }
+      if (proxyAuth?.logoutUrl) {
+        window.location.href = proxyAuth.logoutUrl;
+        return;
+      }
       setUser(null);
     } catch (error) {
       console.error('Logout failed:', error);
@@ -115,6 +130,7 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
     isAuthenticated: !!user,
     isLoading,
     authEnabled,
+    anonymousReadOnly,
     login,
     logout,
     checkAuth,
@@ -5,11 +5,12 @@ import {
   mockActivities, 
   mockFluxStats, 
   mockAzureSubscriptions, 
+  mockRegistryCredentials,
   mockOAuthProviders,
   mockSettings,
   mockLogs 
 } from './mockData';
-import type { Cluster, ResourceNode } from './types';
+import type { Cluster, ClusterImportRequest, ClusterImportResponse, ClusterImportResult, KubeconfigContext, ClusterQuotas, LogBundleJob, LogBundleRequest, LogSearchParams, PodDeletionRequest, PodDeletionResult, LogSearchResult, PodTriage, ResourceEvent, ResourceNode, SecretKeys, HelmReleaseValues, HelmReleaseHistory, HelmRollbackResult, HelmRemediationResult, SourceReconcileResult, ReconcileProgress, ReconcileWaitResult, KustomizationDiff, PortForwardRequest, PortForwardResponse, FluxHealth, FluxVersion, ReconcileOrder, SuspendScope, SuspendTarget, BulkAction, BulkJob, ApplyResult, DeploymentEvent, DeploymentTimeline, TimelineParams, IncidentEntry, IncidentFeed, UnmanagedReport, ImageFreshnessReport, ImageOwner, NamespaceSummary, RegistryCredentialInput, PodMetrics, ClusterCapabilities, AccessReviewRequest, AccessReview, ProviderGrants, SourceCommit, SourceRefs, ClusterShare, SyncJob, FluxResourceFields, FluxResourceCreateResult } from './types';
 
 // Simulates network delay
 const delay = (ms: number = 300) => new Promise(resolve => setTimeout(resolve, ms));
@@ -20,8 +21,42 @@ const mockResponse = async <T,>(data: T) => {
   return { data };
 };
 
+// Mock Kustomizations and HelmReleases in a suspend scope; label selectors match everything
+const demoSuspendTargets = (scope: SuspendScope): SuspendTarget[] =>
+  mockResources
+    .filter(r => (scope.kinds?.length ? scope.kinds : ['Kustomization', 'HelmRelease']).includes(r.kind as 'Kustomization' | 'HelmRelease'))
+    .filter(r => !scope.namespace || r.namespace === scope.namespace)
+    .map(r => ({ kind: r.kind, namespace: r.namespace, name: r.name, suspended: false }));
+
+// Mock deployments over the last few hours, newest first
+const demoDeployments = (): DeploymentEvent[] => {
+  const at = (minutesAgo: number) => new Date(Date.now() - minutesAgo * 60000).toISOString();
+  const event = (id: number, minutesAgo: number, resource: typeof mockResources[number], type: DeploymentEvent['type'], oldRevision: string, newRevision: string): DeploymentEvent => {
+    const cluster = mockClusters.find(c => c.id === resource.cluster_id);
+    return {
+      id, cluster_id: resource.cluster_id, cluster_name: cluster?.name || resource.cluster_id, resource_id: resource.id,
+      kind: resource.kind, namespace: resource.namespace, name: resource.name, type,
+      old_revision: oldRevision, new_revision: newRevision, recorded_at: at(minutesAgo),
+    };
+  };
+  return [
+    event(5, 12, mockResources[4], 'applied', 'main@sha1:4e1c2b7', 'main@sha1:9af03d1'),
+    event(4, 14, mockResources[1], 'chart', 'ingress-nginx@4.9.0', 'ingress-nginx@4.10.1'),
+    event(3, 15, mockResources[0], 'applied', 'main@sha1:7d2e9c0', 'main@sha1:b81f6a4'),
+    event(2, 16, mockResources[2], 'artifact', 'main@sha1:7d2e9c0', 'main@sha1:b81f6a4'),
+    event(1, 190, mockResources[5], 'chart', 'kube-prometheus-stack@56.0.0', 'kube-prometheus-stack@56.2.1'),
+  ];
+};
+
+// Demo syncs complete at once
+const demoSyncJob = (clusterId: string, jobId: string): SyncJob => ({
+  id: jobId, cluster_id: clusterId, status: 'completed', count: mockResources.filter(r => r.cluster_id === clusterId).length,
+  requested_by: 'demo', created_at: new Date().toISOString(), completed_at: new Date().toISOString(),
+  expires_at: new Date(Date.now() + 3600000).toISOString(),
+});
+
 export const demoClusterApi = {
-  list: () => mockResponse(mockClusters),
+  list: (includeArchived = false) => mockResponse(mockClusters.filter(c => includeArchived || !c.archived)),
   get: (id: string) => mockResponse(mockClusters.find(c => c.id === id) || mockClusters[0]),
   create: (data: { name: string; description: string; kubeconfig: string }) =>
     mockResponse({ 
@@ -35,11 +70,35 @@ export const demoClusterApi = {
       created_at: new Date().toISOString(),
       updated_at: new Date().toISOString(),
     } as Cluster),
+  import: (data: ClusterImportRequest) => {
+    const contexts: KubeconfigContext[] = [
+      { name: 'dev', cluster: 'dev', server: 'https://dev.demo.example.com', current: true },
+      { name: 'staging', cluster: 'staging', server: 'https://staging.demo.example.com', namespace: 'flux-system', current: false },
+    ];
+    const results: ClusterImportResult[] = contexts
+      .filter(c => !data.contexts?.length || data.contexts.includes(c.name))
+      .map(context => ({
+        context,
+        cluster: data.dry_run ? undefined : {
+          id: `demo-cluster-${context.name}-${Date.now()}`,
+          name: data.names?.[context.name] || context.name,
+          description: data.description || '',
+          status: 'healthy',
+          source: 'manual',
+          server_url: context.server,
+          version: 1,
+          created_at: new Date().toISOString(),
+          updated_at: new Date().toISOString(),
+        } as Cluster,
+      }));
+    return mockResponse<ClusterImportResponse>({ imported: data.dry_run ? 0 : results.length, results });
+  },
   update: (id: string, data: Partial<Cluster>) =>
     mockResponse({ ...mockClusters.find(c => c.id === id), ...data }),
   delete: () => mockResponse({}),
   checkHealth: () => mockResponse({ status: 'healthy', message: 'Cluster is healthy' }),
-  syncResources: () => mockResponse({ synced: 12, message: 'Resources synced successfully' }),
+  syncResources: (id: string) => mockResponse(demoSyncJob(id, `${id}-sync`)),
+  getSyncJob: (id: string, jobId: string) => mockResponse(demoSyncJob(id, jobId)),
   getResourceTree: (id: string) => {
     const clusterResources = mockResources.filter(r => r.cluster_id === id);
     const tree: ResourceNode[] = clusterResources.map(r => ({
@@ -54,11 +113,145 @@ export const demoClusterApi = {
     }));
     return mockResponse({ tree, count: tree.length });
   },
+  // The third demo cluster runs an older Flux release, serving OCIRepository and Bucket at v1beta2 only
+  getCapabilities: (id: string) => {
+    const sourceBeta = id === 'demo-cluster-3' ? 'v1beta2' : 'v1';
+    const kinds: [string, string, string][] = [
+      ['Kustomization', 'kustomize', 'v1'], ['HelmRelease', 'helm', 'v2'],
+      ['GitRepository', 'source', 'v1'], ['HelmRepository', 'source', 'v1'],
+      ['OCIRepository', 'source', sourceBeta], ['Bucket', 'source', sourceBeta],
+      ['Alert', 'notification', 'v1beta3'], ['Provider', 'notification', 'v1beta3'], ['Receiver', 'notification', 'v1'],
+    ];
+    return mockResponse<ClusterCapabilities>({
+      kinds: kinds.map(([kind, group, version]) => ({
+        kind, group: `${group}.toolkit.fluxcd.io`, installed: true, version, versions: [version],
+      })),
+    });
+  },
+  // Demo clusters allow every action
+  accessReview: (_id: string, params: AccessReviewRequest) =>
+    mockResponse<AccessReview>({ ...params, group: params.group || '', allowed: true }),
+  getQuotas: (id: string, threshold = 0.9) =>
+    mockResponse<ClusterQuotas>({ cluster_id: id, threshold, namespaces: [], near_limit: 0, at_risk: 0 }),
+  getImageFreshness: (id: string, outdatedOnly = false) => {
+    const cluster = mockClusters.find(c => c.id === id) || mockClusters[0];
+    const owners: ImageOwner[] = [
+      {
+        kind: 'HelmRelease', namespace: 'monitoring', name: 'grafana', outdated: 1,
+        images: [{
+          workload: { kind: 'Deployment', namespace: 'monitoring', name: 'grafana' }, container: 'grafana',
+          image: 'docker.io/grafana/grafana:10.2.3', repository: 'docker.io/grafana/grafana', tag: '10.2.3', latest_tag: '11.4.0', status: 'outdated',
+        }],
+      },
+      {
+        kind: 'Kustomization', namespace: 'flux-system', name: 'apps', outdated: 0,
+        images: [{
+          workload: { kind: 'Deployment', namespace: 'podinfo', name: 'podinfo' }, container: 'podinfod',
+          image: 'ghcr.io/stefanprodan/podinfo:6.7.1', repository: 'ghcr.io/stefanprodan/podinfo', tag: '6.7.1', latest_tag: '6.7.1', status: 'up_to_date',
+        }],
+      },
+    ];
+    return mockResponse<ImageFreshnessReport>({
+      cluster_id: cluster.id, cluster_name: cluster.name, images: 2, outdated: 1, warnings: [],
+      owners: outdatedOnly ? owners.filter(o => o.outdated > 0) : owners,
+    });
+  },
+  getUnmanaged: (id: string, namespace?: string) => {
+    const cluster = mockClusters.find(c => c.id === id) || mockClusters[0];
+    const objects = [
+      { kind: 'Deployment', namespace: 'default', name: 'debug-shell', created_at: new Date(Date.now() - 3 * 86400000).toISOString() },
+      { kind: 'Deployment', namespace: 'monitoring', name: 'grafana', created_at: new Date(Date.now() - 40 * 86400000).toISOString(), managed_by: 'Helm' },
+      { kind: 'CronJob', namespace: 'payments', name: 'manual-backfill', created_at: new Date(Date.now() - 7 * 86400000).toISOString() },
+    ].filter(o => !namespace || o.namespace === namespace);
+    const byNamespace: Record<string, number> = {};
+    objects.forEach(o => { byNamespace[o.namespace] = (byNamespace[o.namespace] || 0) + 1; });
+    return mockResponse<UnmanagedReport>({
+      cluster_id: cluster.id, cluster_name: cluster.name, namespace, scanned: objects.length + 24,
+      objects, by_namespace: byNamespace, skipped_namespaces: namespace ? [] : ['kube-node-lease', 'kube-public', 'kube-system'], warnings: [],
+    });
+  },
   toggleFavorite: (id: string) => {
     const cluster = mockClusters.find(c => c.id === id);
     return mockResponse({ ...cluster, is_favorite: !cluster?.is_favorite } as Cluster);
   },
+  archive: (id: string) => {
+    const cluster = mockClusters.find(c => c.id === id);
+    return mockResponse({ ...cluster, archived: true, archived_at: new Date().toISOString() } as Cluster);
+  },
+  unarchive: (id: string) => {
+    const cluster = mockClusters.find(c => c.id === id);
+    return mockResponse({ ...cluster, archived: false, archived_at: undefined } as Cluster);
+  },
   exportCluster: () => mockResponse(new Blob(['mock export data'], { type: 'application/json' })),
+  listShares: (id: string) => mockResponse({
+    shares: [{
+      id: `${id}-owner`, cluster_id: id, subject_type: 'user', subject: 'demo@example.com', access: 'owner',
+      created_by: 'demo@example.com', created_at: new Date().toISOString(), updated_at: new Date().toISOString(),
+    }] as ClusterShare[],
+    visibility_enabled: false,
+  }),
+  putShare: (id: string, share: Pick<ClusterShare, 'subject_type' | 'subject' | 'access'>) => mockResponse({
+    ...share, id: `${id}-${share.subject}`, cluster_id: id, created_by: 'demo@example.com',
+    created_at: new Date().toISOString(), updated_at: new Date().toISOString(),
+  } as ClusterShare),
+  deleteShare: () => mockResponse({ message: 'Share deleted' }),
+  getIncidentFeed: (id: string, params?: { since?: string; until?: string; namespace?: string }) => {
+    const until = params?.until ? new Date(params.until) : new Date();
+    const since = params?.since ? new Date(params.since) : new Date(until.getTime() - 3600000);
+    const cluster = mockClusters.find(c => c.id === id) || mockClusters[0];
+    const deployments: IncidentEntry[] = demoDeployments().filter(d => d.cluster_id === cluster.id).map(d => ({
+      time: d.recorded_at, source: 'deployment', severity: 'info', kind: d.kind, namespace: d.namespace, name: d.name,
+      summary: `${d.type} ${d.old_revision} → ${d.new_revision}`, detail: d,
+    }));
+    const at = (minutesAgo: number) => new Date(Date.now() - minutesAgo * 60000).toISOString();
+    // A failed upgrade and its rollback on the production cluster
+    const incident: IncidentEntry[] = cluster.id !== 'demo-cluster-1' ? [] : [
+      { time: at(13), source: 'event', severity: 'warning', kind: 'Pod', namespace: 'ingress-nginx', name: 'ingress-nginx-controller-7d9f8b-x2k4p',
+        summary: 'BackOff: Back-off restarting failed container controller (×6)', detail: {} },
+      { time: at(11), source: 'transition', severity: 'error', kind: 'HelmRelease', namespace: 'ingress-nginx', name: 'nginx-ingress',
+        summary: 'Ready → NotReady: Helm upgrade failed: timed out waiting for the condition', detail: {} },
+      { time: at(6), source: 'activity', severity: 'info', kind: 'HelmRelease', name: 'nginx-ingress',
+        summary: 'rollback by demo-user: Rolled back to revision 4', detail: {} },
+      { time: at(3), source: 'transition', severity: 'info', kind: 'HelmRelease', namespace: 'ingress-nginx', name: 'nginx-ingress',
+        summary: 'NotReady → Ready: Helm rollback to previous release succeeded', detail: {} },
+    ];
+    const entries = [...deployments, ...incident]
+      .filter(e => !params?.namespace || e.namespace === params.namespace)
+      .filter(e => new Date(e.time) >= since && new Date(e.time) < until)
+      .sort((a, b) => a.time.localeCompare(b.time));
+    const counts: Record<string, number> = {};
+    entries.forEach(e => { counts[e.source] = (counts[e.source] || 0) + 1; });
+    return mockResponse<IncidentFeed>({
+      cluster_id: cluster.id, cluster_name: cluster.name, since: since.toISOString(), until: until.toISOString(),
+      entries, counts, warnings: [],
+    });
+  },
+  applyManifests: (_id: string, manifests: string, params: { namespace?: string; dry_run?: boolean; force?: boolean } = {}) => {
+    const objects = manifests.split(/^---\s*$/m).filter(doc => /^kind:/m.test(doc)).map(doc => {
+      const field = (key: string) => doc.match(new RegExp(`^\\s*${key}:\\s*(\\S+)`, 'm'))?.[1] ?? '';
+      return {
+        api_version: field('apiVersion'),
+        kind: field('kind'),
+        namespace: field('namespace') || params.namespace || undefined,
+        name: field('name'),
+        action: 'created' as const,
+      };
+    });
+    return mockResponse<ApplyResult>({ dry_run: !!params.dry_run, objects, summary: { created: objects.length } });
+  },
+  listNamespaces: (id: string) => {
+    const counts: Record<string, number> = { default: 0, 'kube-system': 0 };
+    mockResources.filter(r => r.cluster_id === id).forEach(r => { counts[r.namespace] = (counts[r.namespace] || 0) + 1; });
+    const namespaces: NamespaceSummary[] = Object.keys(counts).sort().map(name => ({
+      name, status: 'Active', labels: { 'kubernetes.io/metadata.name': name },
+      created_at: '2024-12-01T10:00:00Z', managed: false, flux_resources: counts[name],
+    }));
+    return mockResponse({ namespaces, count: namespaces.length });
+  },
+  createNamespace: (_id: string, namespace: { name: string; labels?: Record<string, string>; annotations?: Record<string, string> }) =>
+    mockResponse({ message: 'Namespace created successfully', name: namespace.name }),
+  deleteNamespace: () =>
+    mockResponse({ message: 'Namespace deletion started' }),
 };
 
 export const demoResourceApi = {
@@ -82,26 +275,244 @@ export const demoResourceApi = {
     mockResponse({ logs: 'Demo log line 1\nDemo log line 2\nDemo log line 3\n...\nDemo log line 100' }),
   getPodContainers: () =>
     mockResponse({ containers: ['main', 'sidecar', 'init'] }),
+  getPodMetrics: (_clusterId: string, namespace: string, podName: string) =>
+    mockResponse<PodMetrics>({
+      namespace, name: podName, timestamp: new Date().toISOString(), window: '15s',
+      usage: { cpu_millicores: 142, memory_bytes: 201326592 },
+      requests: { cpu_millicores: 200, memory_bytes: 268435456 },
+      limits: { cpu_millicores: 500, memory_bytes: 536870912 },
+      containers: [
+        { name: 'main', usage: { cpu_millicores: 130, memory_bytes: 184549376 }, requests: { cpu_millicores: 150, memory_bytes: 201326592 }, limits: { cpu_millicores: 400, memory_bytes: 402653184 } },
+        { name: 'sidecar', usage: { cpu_millicores: 12, memory_bytes: 16777216 }, requests: { cpu_millicores: 50, memory_bytes: 67108864 }, limits: { cpu_millicores: 100, memory_bytes: 134217728 } },
+      ],
+      node: { name: 'demo-node-1', usage: { cpu_millicores: 1840, memory_bytes: 6442450944 }, allocatable: { cpu_millicores: 3860, memory_bytes: 14495514624 } },
+    }),
   deletePod: () =>
     mockResponse({ status: 'success', message: 'Pod deleted successfully' }),
+  deletePods: (_clusterId: string, namespace: string, data: PodDeletionRequest) =>
+    mockResponse<PodDeletionResult>({
+      namespace, selector: data.selector, dry_run: !!data.dry_run, evict: !!data.evict, matched: data.pods || [],
+      deleted: data.dry_run ? [] : data.pods || [],
+    }),
+  portForward: (_clusterId: string, _namespace: string, _podName: string, data: PortForwardRequest) =>
+    mockResponse<PortForwardResponse>({
+      status_code: 200, status: '200 OK', headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ status: 'ok', port: data.port, path: data.path || '/' }), truncated: false, duration_ms: 12,
+    }),
+  getEvents: (_clusterId: string, params: { kind: string; namespace: string; name: string }) =>
+    mockResponse<{ events: ResourceEvent[] }>({
+      events: params.kind === 'HelmRelease' && params.name === 'redis' ? [{
+        type: 'Warning', reason: 'UpgradeFailed', count: 4, source: 'helm-controller',
+        message: 'Helm upgrade failed for release data/redis with chart redis@20.6.2: context deadline exceeded',
+        last_seen: new Date(Date.now() - 2 * 60 * 1000).toISOString(),
+        object: { kind: 'HelmRelease', namespace: params.namespace, name: params.name },
+      }] : [],
+    }),
+  getSecretKeys: (_clusterId: string, namespace: string, name: string) =>
+    mockResponse<SecretKeys>({
+      namespace, name, type: 'Opaque',
+      keys: [{ name: 'password', size: 24 }, { name: 'username', size: 5 }],
+    }),
+  getYAML: (_clusterId: string, kind: string, namespace: string, name: string) => {
+    const resource = mockResources.find(r => r.kind === kind && r.namespace === namespace && r.name === name);
+    const spec = resource ? JSON.parse(resource.metadata || '{}') : {};
+    return mockResponse<string>([
+      `apiVersion: ${kind === 'HelmRelease' ? 'helm.toolkit.fluxcd.io/v2' : 'kustomize.toolkit.fluxcd.io/v1'}`,
+      `kind: ${kind}`,
+      'metadata:',
+      `  name: ${name}`,
+      `  namespace: ${namespace}`,
+      'spec:',
+      ...Object.entries(spec).map(([key, value]) => `  ${key}: ${JSON.stringify(value)}`),
+      'status:',
+      '  conditions:',
+      '  - type: Ready',
+      `    status: "${resource?.status === 'Ready' ? 'True' : 'False'}"`,
+      `    message: ${JSON.stringify(resource?.message ?? '')}`,
+      '',
+    ].join('\n'));
+  },
+  downloadPodLogs: () =>
+    mockResponse(new Blob(['demo logs'], { type: 'application/zip' })),
+  downloadWorkloadLogs: () =>
+    mockResponse(new Blob(['demo logs'], { type: 'application/zip' })),
+  createLogBundle: (clusterId: string, data: LogBundleRequest) =>
+    mockResponse<LogBundleJob>({
+      id: `demo-bundle-${Date.now()}`, cluster_id: clusterId, kind: data.kind, namespace: data.namespace, name: data.name,
+      status: 'completed', containers: 1, requested_by: 'demo', file_name: `logs-${data.namespace}-${data.kind}-${data.name}.zip`,
+      created_at: new Date().toISOString(), expires_at: new Date(Date.now() + 3600000).toISOString(),
+    }),
+  getLogBundle: (id: string) =>
+    mockResponse<LogBundleJob>({
+      id, cluster_id: 'demo', kind: 'Pod', namespace: 'default', name: 'demo', status: 'completed', containers: 1,
+      requested_by: 'demo', file_name: 'logs-demo.zip', created_at: new Date().toISOString(),
+      expires_at: new Date(Date.now() + 3600000).toISOString(),
+    }),
+  downloadLogBundle: () =>
+    mockResponse(new Blob(['demo logs'], { type: 'application/zip' })),
 };
 
 export const demoFluxApi = {
   axios: null, // Not used in demo mode
   getStats: (clusterId: string) =>
     mockResponse(mockFluxStats[clusterId] || mockFluxStats['demo-cluster-1']),
+  getReconcileOrder: (_clusterId: string, source?: string) => {
+    const node = (kind: string, namespace: string, name: string, stage: number, dependsOn: string[], sourceName: string, extra = {}) => ({
+      kind, namespace, name, stage,
+      depends_on: dependsOn.map(dep => ({ kind, namespace, name: dep })),
+      source: { kind: 'GitRepository', namespace: 'flux-system', name: sourceName },
+      affected: !!source, suspended: false, ready: true, waiting: false, ...extra,
+    });
+    return mockResponse<ReconcileOrder>({
+      source: source ? { kind: source.split('/')[0], namespace: source.split('/')[1], name: source.split('/')[2] } : undefined,
+      stages: [
+        [node('Kustomization', 'flux-system', 'flux-system', 0, [], 'flux-system'), node('Kustomization', 'flux-system', 'infrastructure', 0, [], 'flux-system')],
+        [node('Kustomization', 'flux-system', 'apps', 1, ['infrastructure'], 'flux-system', { ready: false })],
+        [node('Kustomization', 'flux-system', 'monitoring', 2, ['apps'], 'flux-system', {
+          ready: false, waiting: true, message: 'waits for Kustomization/flux-system/apps, which is not Ready',
+        })],
+      ],
+      blocked: [node('Kustomization', 'flux-system', 'tenants', -1, ['tenant-base'], 'flux-system', {
+        ready: false, message: 'dependency Kustomization/flux-system/tenant-base does not exist',
+      })],
+      issues: [{
+        type: 'missing_dependency',
+        objects: [{ kind: 'Kustomization', namespace: 'flux-system', name: 'tenants' }, { kind: 'Kustomization', namespace: 'flux-system', name: 'tenant-base' }],
+        message: 'Kustomization/flux-system/tenants depends on Kustomization/flux-system/tenant-base, which does not exist',
+      }],
+    });
+  },
+  // The third demo cluster runs an older Flux release
+  getVersion: (clusterId: string) => {
+    const distribution = clusterId === 'demo-cluster-3' ? 'v2.2.3' : 'v2.4.0';
+    const versions: Record<string, string> = clusterId === 'demo-cluster-3'
+      ? { 'source-controller': 'v1.2.5', 'kustomize-controller': 'v1.2.2', 'helm-controller': 'v0.37.4', 'notification-controller': 'v1.2.4' }
+      : { 'source-controller': 'v1.4.1', 'kustomize-controller': 'v1.4.0', 'helm-controller': 'v1.1.0', 'notification-controller': 'v1.4.0' };
+    return mockResponse<FluxVersion>({
+      namespace: 'flux-system',
+      distribution,
+      components: [...Object.keys(versions), 'image-reflector-controller', 'image-automation-controller'].map(name => ({
+        name,
+        installed: name in versions,
+        image: versions[name] ? `ghcr.io/fluxcd/${name}:${versions[name]}` : undefined,
+        version: versions[name],
+      })),
+      apis: [
+        { group: 'helm.toolkit.fluxcd.io', preferred: 'v2', versions: ['v2', 'v2beta2', 'v2beta1'] },
+        { group: 'kustomize.toolkit.fluxcd.io', preferred: 'v1', versions: ['v1', 'v1beta2'] },
+        { group: 'notification.toolkit.fluxcd.io', preferred: 'v1', versions: ['v1', 'v1beta3'] },
+        { group: 'source.toolkit.fluxcd.io', preferred: 'v1', versions: ['v1', 'v1beta2'] },
+      ],
+    });
+  },
+  // The third demo cluster has a crash looping helm-controller
+  getHealth: (clusterId: string) => {
+    const degraded = clusterId === 'demo-cluster-3' ? ['helm-controller'] : [];
+    return mockResponse<FluxHealth>({
+      status: degraded.length ? 'degraded' : 'healthy',
+      namespace: 'flux-system',
+      crds_installed: true,
+      has_resources: true,
+      controllers: ['source-controller', 'kustomize-controller', 'helm-controller', 'notification-controller'].map(name => ({
+        name,
+        installed: true,
+        required: name === 'source-controller' || name === 'kustomize-controller',
+        ready: !degraded.includes(name),
+        degraded: degraded.includes(name),
+        replicas: 1,
+        ready_replicas: degraded.includes(name) ? 0 : 1,
+        version: 'v1.4.0',
+        restarts: degraded.includes(name) ? 12 : 0,
+        reason: degraded.includes(name) ? 'CrashLoopBackOff' : undefined,
+        message: degraded.includes(name) ? `pod ${name}-6c7b8d9f5-r4t7w: back-off 5m0s restarting failed container=manager` : undefined,
+      })),
+      degraded,
+      message: degraded.length ? `Degraded controllers: ${degraded.join(', ')}` : undefined,
+    });
+  },
   getResource: (clusterId: string, kind: string, namespace: string, name: string) =>
     mockResponse(mockResources.find(r => 
       r.cluster_id === clusterId && r.kind === kind && r.namespace === namespace && r.name === name
     ) || mockResources[0]),
   updateResource: () =>
     mockResponse({ status: 'success', message: 'Resource updated successfully' }),
-  reconcile: () =>
-    mockResponse({ status: 'success', message: 'Reconciliation triggered' }),
+  reconcile: (_clusterId: string, _kind: string, namespace: string, _name: string, withSource?: boolean, force?: boolean) =>
+    mockResponse<{ message: string; forced?: boolean; source?: SourceReconcileResult }>({
+      message: 'Reconciliation triggered',
+      forced: !!force,
+      source: withSource ? {
+        kind: 'GitRepository', namespace, name: 'flux-system',
+        previous_revision: 'main@sha1:4f2a9c1', revision: 'main@sha1:8b7d3e0', updated: true,
+      } : undefined,
+    }),
+  reconcileAndWait: async (
+    _clusterId: string, _kind: string, namespace: string, name: string,
+    onProgress: (progress: ReconcileProgress) => void, withSource?: boolean, _force?: boolean
+  ): Promise<ReconcileWaitResult> => {
+    const started = Date.now();
+    const step = async (progress: Omit<ReconcileProgress, 'elapsed_ms'>) => {
+      await delay(800);
+      const current = { ...progress, elapsed_ms: Date.now() - started };
+      onProgress(current);
+      return current;
+    };
+    await step({ phase: 'pending', handled: false, message: 'Waiting for the controller to pick up the request', conditions: [] });
+    await step({
+      phase: 'progressing', handled: true, reason: 'Progressing', message: 'Reconciliation in progress',
+      conditions: [{ type: 'Reconciling', status: 'True', reason: 'Progressing', message: 'Reconciliation in progress' }],
+    });
+    const progress = await step({
+      phase: 'ready', handled: true, reason: 'ReconciliationSucceeded', message: 'Applied revision: main@sha1:8b7d3e0',
+      revision: 'main@sha1:8b7d3e0',
+      conditions: [{ type: 'Ready', status: 'True', reason: 'ReconciliationSucceeded', message: 'Applied revision: main@sha1:8b7d3e0' }],
+    });
+    return {
+      message: `Reconciled ${namespace}/${name} and it is ready at ${progress.revision}`,
+      progress,
+      source: withSource ? {
+        kind: 'GitRepository', namespace, name: 'flux-system',
+        previous_revision: 'main@sha1:4f2a9c1', revision: 'main@sha1:8b7d3e0', updated: true,
+      } : undefined,
+    };
+  },
   suspend: () =>
     mockResponse({ status: 'success', message: 'Resource suspended' }),
   resume: () =>
     mockResponse({ status: 'success', message: 'Resource resumed' }),
+  previewBulkAction: (_clusterId: string, action: BulkAction, scope: SuspendScope) =>
+    mockResponse<{ action: string; scope: SuspendScope; targets: SuspendTarget[] }>({
+      action, scope, targets: demoSuspendTargets(scope),
+    }),
+  startBulkAction: (clusterId: string, action: BulkAction, scope: SuspendScope) => {
+    const targets = demoSuspendTargets(scope);
+    const skips = (t: SuspendTarget) => (action === 'resume' ? !t.suspended : t.suspended);
+    const skipped = targets.filter(skips).length;
+    return mockResponse<BulkJob>({
+      id: `demo-bulk-${Date.now()}`, cluster_id: clusterId, action, scope, status: 'completed', requested_by: 'demo',
+      summary: { matched: targets.length, applied: targets.length - skipped, skipped, failed: 0 },
+      results: targets.map(t => ({
+        kind: t.kind, namespace: t.namespace, name: t.name,
+        status: skips(t) ? 'skipped' as const : 'applied' as const,
+      })),
+      created_at: new Date().toISOString(), completed_at: new Date().toISOString(),
+      expires_at: new Date(Date.now() + 3600000).toISOString(),
+    });
+  },
+  getBulkJob: (id: string) =>
+    mockResponse<BulkJob>({
+      id, cluster_id: 'demo', action: 'suspend', scope: {}, status: 'completed', requested_by: 'demo',
+      summary: { matched: 0, applied: 0, skipped: 0, failed: 0 }, results: [],
+      created_at: new Date().toISOString(), expires_at: new Date(Date.now() + 3600000).toISOString(),
+    }),
+  create: (_clusterId: string, kind: string, body: FluxResourceFields | { manifest: Record<string, any> }, dryRun = false) =>
+    mockResponse<FluxResourceCreateResult>({
+      ...(dryRun ? { dry_run: true } : { message: 'Resource created successfully' }),
+      manifest: 'manifest' in body ? body.manifest : { kind, metadata: { name: body.name, namespace: body.namespace || 'flux-system' }, spec: {} },
+    }),
+  createNotification: () =>
+    mockResponse({ message: 'Resource created successfully' }),
+  deleteNotification: () =>
+    mockResponse({ message: 'Resource deleted successfully' }),
   getChildren: () =>
     mockResponse({
       resources: [
@@ -111,6 +522,94 @@ export const demoFluxApi = {
       ],
       count: 3,
     }),
+  getTriage: (_clusterId: string, kind: string, namespace: string, name: string) =>
+    mockResponse<PodTriage>({ kind, namespace, name, total_pods: 0, pods: [], workloads: [] }),
+  getSourceCommit: (_clusterId: string, _namespace: string, _name: string) =>
+    mockResponse<SourceCommit>({
+      repository: 'fluxcd/flux2-kustomize-helm-example',
+      sha: '4f2c9e1a7b3d5e8f0c2a4b6d8e0f1a3c5e7b9d1f',
+      message: 'Bump podinfo to 6.5.4',
+      author_name: 'Demo User',
+      author_email: 'demo@example.com',
+      date: new Date(Date.now() - 2 * 3600000).toISOString(),
+      url: 'https://github.com/fluxcd/flux2-kustomize-helm-example',
+      as_user: false,
+    }),
+  getSourceRefs: (_clusterId: string, _kind: string, namespace: string, name: string) =>
+    mockResponse<SourceRefs>({
+      namespace, name,
+      url: 'https://github.com/fluxcd/flux2-kustomize-helm-example',
+      branch: 'main',
+      branches: [
+        { name: 'main', sha: '4f2c9e1a7b3d5e8f0c2a4b6d8e0f1a3c5e7b9d1f' },
+        { name: 'staging', sha: '9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b' },
+      ],
+      tags: [
+        { name: 'v1.0.0', sha: '1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b' },
+        { name: 'v1.1.0', sha: '4f2c9e1a7b3d5e8f0c2a4b6d8e0f1a3c5e7b9d1f' },
+      ],
+      as_user: false,
+    }),
+  getHelmValues: (_clusterId: string, namespace: string, name: string) =>
+    mockResponse<HelmReleaseValues>({
+      namespace, name,
+      values: { replicaCount: 2, image: { repository: 'ghcr.io/stefanprodan/podinfo', tag: '6.5.0' }, auth: { password: '**redacted**' } },
+      sources: [
+        { kind: 'ConfigMap', name: `${name}-defaults`, values_key: 'values.yaml', status: 'applied' },
+        { kind: 'Secret', name: `${name}-auth`, values_key: 'password', target_path: 'auth.password', status: 'applied' },
+        { kind: 'ConfigMap', name: `${name}-overrides`, values_key: 'values.yaml', optional: true, status: 'skipped' },
+        { kind: 'HelmRelease', name, status: 'applied' },
+      ],
+      keys: [
+        { path: 'auth.password', source: 1 },
+        { path: 'image.repository', source: 0 },
+        { path: 'image.tag', source: 3, overridden: [0] },
+        { path: 'replicaCount', source: 3, overridden: [0] },
+      ],
+      redacted: true,
+    }),
+  getHelmHistory: (_clusterId: string, namespace: string, name: string) =>
+    mockResponse<HelmReleaseHistory>({
+      namespace, name, release_name: name, storage_namespace: namespace, values_redacted: true,
+      revisions: [
+        { revision: 3, status: 'deployed', chart_name: name, chart_version: '6.5.0', description: 'Upgrade complete', last_deployed: new Date(Date.now() - 3600 * 1000).toISOString() },
+        { revision: 2, status: 'superseded', chart_name: name, chart_version: '6.4.1', description: 'Upgrade complete', last_deployed: new Date(Date.now() - 86400 * 1000).toISOString() },
+        { revision: 1, status: 'superseded', chart_name: name, chart_version: '6.4.0', description: 'Install complete', last_deployed: new Date(Date.now() - 7 * 86400 * 1000).toISOString() },
+      ],
+    }),
+  rollbackHelmRelease: (_clusterId: string, _namespace: string, name: string, revision: number) =>
+    mockResponse<HelmRollbackResult>({
+      revision, chart_version: '6.4.1', values_restored: true,
+      message: `Pinned chart ${name} to 6.4.1 and restored the values of revision ${revision}`,
+    }),
+  forceReconcileHelmRelease: (_clusterId: string, namespace: string, name: string) =>
+    mockResponse<HelmRemediationResult>({
+      requested_at: new Date().toISOString(), forced: true, reset: false,
+      failures: 0, install_failures: 0, upgrade_failures: 0, install_retries: 3, upgrade_retries: 3,
+      message: `Requested a forced Helm install or upgrade of ${namespace}/${name}`,
+    }),
+  resetHelmReleaseFailures: (_clusterId: string, namespace: string, name: string) =>
+    mockResponse<HelmRemediationResult>({
+      requested_at: new Date().toISOString(), forced: false, reset: true,
+      failures: 4, install_failures: 0, upgrade_failures: 4, install_retries: 3, upgrade_retries: 3,
+      message: `Reset 0 install and 4 upgrade failures of ${namespace}/${name} and requested a reconcile`,
+    }),
+  getKustomizationDiff: (_clusterId: string, namespace: string, name: string) =>
+    mockResponse<KustomizationDiff>({
+      namespace, name, source: { kind: 'GitRepository', namespace, name }, revision: 'main@sha1:4f2a9c1', path: './apps',
+      objects: [
+        {
+          api_version: 'apps/v1', kind: 'Deployment', namespace: 'default', name: 'podinfo', action: 'configured',
+          diff: '--- live\n+++ desired\n@@ -8,5 +8,5 @@\n   namespace: default\n spec:\n-  replicas: 2\n+  replicas: 3\n   selector:\n',
+        },
+        { api_version: 'v1', kind: 'Service', namespace: 'default', name: 'podinfo', action: 'unchanged' },
+      ],
+      summary: { configured: 1, unchanged: 1 }, warnings: [], secret_variables: 0, redacted: false,
+    }),
+  searchLogs: (_clusterId: string, kind: string, namespace: string, name: string, params: LogSearchParams) =>
+    mockResponse<LogSearchResult>({
+      kind, namespace, name, query: params.q, pods_searched: 0, containers_searched: 0, total_matches: 0, results: [],
+    }),
 };
 
 export const demoSettingsApi = {
@@ -188,6 +687,35 @@ export const demoActivityApi = {
     mockResponse(mockActivities.find(a => a.id === id) || mockActivities[0]),
 };
 
+export const demoRegistryApi = {
+  list: () => mockResponse(mockRegistryCredentials),
+  get: (id: string) =>
+    mockResponse(mockRegistryCredentials.find(c => c.id === id) || mockRegistryCredentials[0]),
+  create: (data: RegistryCredentialInput) =>
+    mockResponse({
+      id: `demo-registry-${Date.now()}`,
+      name: data.name,
+      provider: data.provider,
+      registry: data.registry || (data.provider === 'dockerhub' ? 'docker.io' : ''),
+      username: data.username || (data.provider === 'ecr' ? 'AWS' : data.provider === 'gcr' ? '_json_key' : ''),
+      has_credentials: true,
+      status: 'healthy',
+      last_tested_at: new Date().toISOString(),
+      version: 1,
+      created_at: new Date().toISOString(),
+      updated_at: new Date().toISOString(),
+    }),
+  update: (id: string, data: Partial<RegistryCredentialInput> & { version?: number }) =>
+    mockResponse({
+      ...(mockRegistryCredentials.find(c => c.id === id) || mockRegistryCredentials[0]),
+      ...data,
+      updated_at: new Date().toISOString(),
+    }),
+  delete: () => mockResponse({ message: 'Registry credential deleted successfully' }),
+  test: () =>
+    mockResponse({ status: 'healthy', message: 'Connection successful' }),
+};
+
 export const demoOAuthApi = {
   listProviders: () => mockResponse(mockOAuthProviders),
   getProvider: (id: string) =>
@@ -209,6 +737,26 @@ export const demoOAuthApi = {
     mockResponse({ status: 'success', message: 'OAuth configuration is valid' }),
 };
 
+// Demo mode has no sign-in, so there is nothing to grant
+export const demoGrantApi = {
+  list: () => mockResponse<ProviderGrants>({ available: [], scopes: ['repo'], grants: [] }),
+  authorizeUrl: (_provider: string) => '#',
+  revoke: (_provider: string) => mockResponse({ message: 'Revoked' }),
+};
+
+export const demoTimelineApi = {
+  list: (params?: TimelineParams) => {
+    const until = params?.until ? new Date(params.until) : new Date();
+    const since = params?.since ? new Date(params.since) : new Date(until.getTime() - 24 * 3600000);
+    const events = demoDeployments()
+      .filter(e => !params?.cluster_id || e.cluster_id === params.cluster_id)
+      .filter(e => !params?.kind || e.kind === params.kind)
+      .filter(e => !params?.namespace || e.namespace === params.namespace)
+      .filter(e => new Date(e.recorded_at) >= since && new Date(e.recorded_at) < until);
+    return mockResponse<DeploymentTimeline>({ since: since.toISOString(), until: until.toISOString(), events, truncated: false });
+  },
+};
+
 export const demoExportApi = {
   exportResources: () =>
     mockResponse(new Blob(['mock export data'], { type: 'application/json' })),
@@ -0,0 +1,82 @@
+import { useEffect, useRef } from 'react';
+import { eventsApi } from '../api';
+import { OrchestratorEvent } from '../types';
+
+// Last sequence seen, kept across reloads so a refreshed tab replays what it missed
+const LAST_SEQUENCE_KEY = 'flux-orchestrator.events.last-sequence';
+
+const MAX_RECONNECT_DELAY = 30000;
+
+const IS_DEMO_MODE = import.meta.env.VITE_DEMO_MODE === 'true';
+
+interface StreamMessage {
+  type: 'event' | 'replayed';
+  event?: OrchestratorEvent;
+  latest_sequence?: number;
+}
+
+const readLastSequence = (): number => {
+  const value = Number(sessionStorage.getItem(LAST_SEQUENCE_KEY));
+  return Number.isFinite(value) && value > 0 ? value : 0;
+};
+
+// useEventStream subscribes to the backend event stream. On every (re)connect the server
+// replays the events recorded since the last one received, so onEvent sees health
+// transitions that happened while the connection was down.
+export const useEventStream = (onEvent: (event: OrchestratorEvent, replayed: boolean) => void) => {
+  const onEventRef = useRef(onEvent);
+  onEventRef.current = onEvent;
+
+  useEffect(() => {
+    if (IS_DEMO_MODE) {
+      return;
+    }
+
+    let socket: WebSocket | null = null;
+    let reconnectTimer: ReturnType<typeof setTimeout> | undefined;
+    let attempts = 0;
+    let stopped = false;
+    let lastSequence = readLastSequence();
+
+    const connect = () => {
+      let replaying = lastSequence > 0;
+      socket = new WebSocket(eventsApi.streamUrl(lastSequence));
+
+      socket.onmessage = (message) => {
+        const data: StreamMessage = JSON.parse(message.data);
+        if (data.type === 'replayed') {
+          replaying = false;
+          attempts = 0;
+          // A fresh tab starts from the newest event instead of replaying history
+          if (lastSequence === 0 && data.latest_sequence) {
+            lastSequence = data.latest_sequence;
+            sessionStorage.setItem(LAST_SEQUENCE_KEY, String(lastSequence));
+          }
+          return;
+        }
+        if (data.event && data.event.sequence > lastSequence) {
+          lastSequence = data.event.sequence;
+          sessionStorage.setItem(LAST_SEQUENCE_KEY, String(lastSequence));
+          onEventRef.current(data.event, replaying);
+        }
+      };
+
+      socket.onclose = () => {
+        if (stopped) {
+          return;
+        }
+        const delay = Math.min(1000 * 2 ** attempts, MAX_RECONNECT_DELAY);
+        attempts++;
+        reconnectTimer = setTimeout(connect, delay);
+      };
+    };
+
+    connect();
+
+    return () => {
+      stopped = true;
+      clearTimeout(reconnectTimer);
+      socket?.close();
+    };
+  }, []);
+};
@@ -1,5 +1,5 @@
 // Mock data for demo mode
-import { Cluster, FluxResource, Activity, FluxStats, AzureSubscription, OAuthProvider } from './types';
+import { Cluster, FluxResource, Activity, FluxStats, AzureSubscription, OAuthProvider, RegistryCredential } from './types';
 
 export const mockClusters: Cluster[] = [
   {
@@ -11,7 +11,9 @@ export const mockClusters: Cluster[] = [
     is_favorite: true,
     health_check_interval: 300,
     resource_count: 12,
+    flux_version: 'v2.4.0',
     created_at: '2024-01-15T10:00:00Z',
+    version: 1,
     updated_at: '2024-12-27T14:30:00Z',
   },
   {
@@ -24,7 +26,9 @@ export const mockClusters: Cluster[] = [
     is_favorite: false,
     health_check_interval: 300,
     resource_count: 8,
+    flux_version: 'v2.4.0',
     created_at: '2024-02-20T09:00:00Z',
+    version: 1,
     updated_at: '2024-12-27T14:25:00Z',
   },
   {
@@ -36,7 +40,9 @@ export const mockClusters: Cluster[] = [
     is_favorite: false,
     health_check_interval: 300,
     resource_count: 15,
+    flux_version: 'v2.2.3',
     created_at: '2024-03-10T11:00:00Z',
+    version: 1,
     updated_at: '2024-12-27T14:20:00Z',
   },
 ];
@@ -186,6 +192,7 @@ export const mockActivities: Activity[] = [
     user_id: 'demo-user',
     status: 'success',
     message: 'HelmRelease resumed',
+    details: JSON.stringify({ changes: [{ field: 'spec.suspend', old: true, new: false }] }),
     created_at: '2024-12-27T14:25:00Z',
   },
   {
@@ -246,6 +253,36 @@ export const mockAzureSubscriptions: AzureSubscription[] = [
     cluster_count: 1,
     last_synced_at: '2024-12-27T14:00:00Z',
     created_at: '2024-01-10T08:00:00Z',
+    version: 1,
+    updated_at: '2024-12-27T14:00:00Z',
+  },
+];
+
+export const mockRegistryCredentials: RegistryCredential[] = [
+  {
+    id: 'demo-registry-1',
+    name: 'Production ACR',
+    provider: 'acr',
+    registry: 'demoprod.azurecr.io',
+    username: 'xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx',
+    has_credentials: true,
+    status: 'healthy',
+    last_tested_at: '2024-12-27T14:00:00Z',
+    created_at: '2024-02-01T09:00:00Z',
+    version: 1,
+    updated_at: '2024-12-27T14:00:00Z',
+  },
+  {
+    id: 'demo-registry-2',
+    name: 'Docker Hub',
+    provider: 'dockerhub',
+    registry: 'docker.io',
+    username: 'demo-bot',
+    has_credentials: true,
+    status: 'healthy',
+    last_tested_at: '2024-12-27T14:00:00Z',
+    created_at: '2024-03-12T11:30:00Z',
+    version: 1,
     updated_at: '2024-12-27T14:00:00Z',
   },
 ];
@@ -262,6 +299,7 @@ export const mockOAuthProviders: OAuthProvider[] = [
     enabled: true,
     status: 'healthy',
     created_at: '2024-01-05T10:00:00Z',
+    version: 1,
     updated_at: '2024-12-27T10:00:00Z',
   },
   {
@@ -276,6 +314,7 @@ export const mockOAuthProviders: OAuthProvider[] = [
     enabled: false,
     status: 'unknown',
     created_at: '2024-02-15T09:00:00Z',
+    version: 1,
     updated_at: '2024-12-27T09:00:00Z',
   },
 ];
@@ -283,6 +322,8 @@ export const mockOAuthProviders: OAuthProvider[] = [
 export const mockSettings = [
   { key: 'auto_sync_interval_minutes', value: '5', updated_at: '2024-12-27T10:00:00Z' },
   { key: 'audit_log_retention_days', value: '90', updated_at: '2024-12-27T10:00:00Z' },
+  { key: 'scale_protected_namespaces', value: 'flux-system,kube-system', updated_at: '2024-12-27T10:00:00Z' },
+  { key: 'scale_max_replicas', value: '20', updated_at: '2024-12-27T10:00:00Z' },
 ];
 // Generate mock log entries
 const generateMockLogs = () => {
@@ -6,12 +6,77 @@ export interface Cluster {
   source?: 'manual' | 'azure-aks';
   source_id?: string;
   is_favorite?: boolean;
+  archived?: boolean;
+  archived_at?: string;
   health_check_interval?: number;
+  client_qps?: number;
+  client_burst?: number;
+  client_timeout?: number;
+  impersonate_users?: boolean;
   resource_count?: number;
+  flux_version?: string;
+  server_url?: string;
+  ca_fingerprint?: string;
   created_at: string;
+  version: number;
   updated_at: string;
 }
 
+export interface KubeconfigContext {
+  name: string;
+  cluster: string;
+  server: string;
+  namespace?: string;
+  current: boolean;
+}
+
+export interface ClusterImportResult {
+  context: KubeconfigContext;
+  cluster?: Cluster;
+  existing_cluster?: Cluster;
+  error?: string;
+}
+
+export interface ClusterImportResponse {
+  imported: number;
+  results: ClusterImportResult[];
+}
+
+export interface ClusterImportRequest {
+  kubeconfig: string;
+  contexts?: string[];
+  names?: Record<string, string>;
+  description?: string;
+  allow_duplicate?: boolean;
+  dry_run?: boolean;
+}
+
+// Who a cluster is shared with; enforced when the server runs with CLUSTER_VISIBILITY=shared
+export interface ClusterShare {
+  id: string;
+  cluster_id: string;
+  subject_type: 'user' | 'role';
+  subject: string; // email of a user or ID of a role
+  access: 'read' | 'write' | 'owner';
+  created_by?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ClusterShares {
+  shares: ClusterShare[];
+  visibility_enabled: boolean;
+}
+
+// Usage of an hourly quota of expensive actions; a limit of 0 means unlimited
+export interface QuotaUsage {
+  action: 'tree_builds' | 'syncs' | 'exports';
+  limit: number;
+  used: number;
+  remaining: number;
+  resets_at?: string;
+}
+
 export interface FluxResource {
   id: string;
   cluster_id: string;
@@ -26,11 +91,138 @@ export interface FluxResource {
   metadata?: string;
 }
 
+// Guided fields a Kustomization, HelmRelease, GitRepository or HelmRepository is created from
+export interface FluxResourceFields {
+  name: string;
+  namespace?: string;
+  interval?: string;
+  source?: { kind: string; name: string; namespace?: string };
+  path?: string;
+  prune?: boolean;
+  target_namespace?: string;
+  chart?: string;
+  version?: string;
+  values?: Record<string, any>;
+  url?: string;
+  branch?: string;
+  tag?: string;
+  semver?: string;
+  secret_ref?: string;
+  type?: 'default' | 'oci';
+}
+
+export interface FluxResourceCreateResult {
+  message?: string;
+  dry_run?: boolean;
+  manifest: Record<string, any>;
+}
+
 export interface FluxStats {
   kustomizations: ResourceStats;
   helmReleases: ResourceStats;
   gitRepositories: ResourceStats;
   helmRepositories: ResourceStats;
+  ociRepositories?: ResourceStats;
+  buckets?: ResourceStats;
+  alerts?: ResourceStats;
+  providers?: ResourceStats;
+  receivers?: ResourceStats;
+}
+
+export interface FluxControllerStatus {
+  name: string;
+  installed: boolean;
+  required: boolean;
+  ready: boolean;
+  degraded: boolean;
+  replicas: number;
+  ready_replicas: number;
+  version?: string;
+  restarts: number;
+  reason?: string;
+  message?: string;
+}
+
+export interface FluxHealth {
+  status: 'healthy' | 'empty' | 'degraded' | 'not_installed' | 'unknown';
+  namespace: string;
+  crds_installed: boolean;
+  has_resources: boolean;
+  controllers: FluxControllerStatus[];
+  degraded: string[];
+  message?: string;
+}
+
+export interface FluxComponent {
+  name: string;
+  installed: boolean;
+  image?: string;
+  version?: string;
+}
+
+export interface FluxVersion {
+  namespace: string;
+  distribution?: string;
+  components: FluxComponent[];
+  apis: { group: string; preferred: string; versions: string[] }[];
+}
+
+// Whether a cluster serves a Flux kind; version is the one the orchestrator uses
+export interface FluxKindCapability {
+  kind: string;
+  group: string;
+  installed: boolean;
+  version?: string;
+  versions: string[];
+}
+
+export interface ClusterCapabilities {
+  kinds: FluxKindCapability[];
+}
+
+// An action to check with a SelfSubjectAccessReview; resource may carry its group, as in deployments.apps
+export interface AccessReviewRequest {
+  verb: string;
+  resource: string;
+  group?: string;
+  subresource?: string;
+  namespace?: string;
+  name?: string;
+}
+
+// user is set when the cluster impersonates the signed-in user and answered for them
+export interface AccessReview extends AccessReviewRequest {
+  group: string;
+  allowed: boolean;
+  denied?: boolean;
+  reason?: string;
+  evaluation_error?: string;
+  user?: string;
+}
+
+export interface ObjectRef {
+  kind: string;
+  namespace?: string;
+  name: string;
+}
+
+// A Kustomization or HelmRelease in the dependsOn order; stage is -1 when blocked
+export interface ReconcileOrderNode extends ObjectRef {
+  stage: number;
+  depends_on: ObjectRef[];
+  source?: ObjectRef;
+  affected: boolean;
+  suspended: boolean;
+  ready: boolean;
+  waiting: boolean;
+  message?: string;
+}
+
+export interface ReconcileOrder {
+  source?: ObjectRef;
+  stages: ReconcileOrderNode[][];
+  blocked: ReconcileOrderNode[];
+  issues: { type: 'cycle' | 'missing_dependency'; objects: ObjectRef[]; message: string }[];
 }
 
 export interface ResourceStats {
@@ -45,11 +237,405 @@ export interface FluxResourceChild {
   version: string;
 }
 
+export interface TriageEvent {
+  type: string;
+  reason: string;
+  message: string;
+  count: number;
+  last_seen: string;
+}
+
+export interface ResourceEvent extends TriageEvent {
+  first_seen?: string;
+  source?: string;
+  object: { kind: string; namespace?: string; name: string };
+}
+
+export interface TriageContainer {
+  name: string;
+  init: boolean;
+  ready: boolean;
+  restart_count: number;
+  state: string;
+  reason?: string;
+  message?: string;
+  last_termination?: {
+    reason: string;
+    exit_code: number;
+    message?: string;
+    finished_at: string;
+  };
+}
+
+export interface TriagePod {
+  namespace: string;
+  name: string;
+  phase: string;
+  reason?: string;
+  message?: string;
+  node?: string;
+  restart_count: number;
+  containers: TriageContainer[];
+  events: TriageEvent[];
+}
+
+export interface TriageWorkload {
+  kind: string;
+  namespace: string;
+  name: string;
+  desired: number;
+  ready: number;
+  events: TriageEvent[];
+}
+
+export interface PodTriage {
+  kind: string;
+  namespace: string;
+  name: string;
+  total_pods: number;
+  pods: TriagePod[];
+  workloads: TriageWorkload[];
+}
+
+export interface LogMatch {
+  line_number: number;
+  line: string;
+  before?: string[];
+  after?: string[];
+}
+
+export interface ContainerLogMatches {
+  namespace: string;
+  pod: string;
+  container: string;
+  matches: LogMatch[];
+  truncated: boolean;
+  error?: string;
+}
+
+export interface LogSearchResult {
+  kind: string;
+  namespace: string;
+  name: string;
+  query: string;
+  pods_searched: number;
+  containers_searched: number;
+  total_matches: number;
+  results: ContainerLogMatches[];
+}
+
+// Frame of a live pod log stream; 'end' follows the last line
+export interface LogStreamMessage {
+  type: 'line' | 'end' | 'error';
+  timestamp?: string;
+  line?: string;
+  error?: string;
+}
+
+export interface LogStreamParams {
+  container?: string;
+  follow?: boolean;
+  tail?: number;
+  since_seconds?: number;
+}
+
+// Text frame ending an exec session; output arrives as binary frames before it
+export interface ExecServerMessage {
+  type: 'exit' | 'error';
+  code: number;
+  error?: string;
+}
+
+export interface ExecParams {
+  container?: string;
+  command?: string[];
+  tty?: boolean;
+}
+
+export interface LogSearchParams {
+  q: string;
+  regex?: boolean;
+  ignore_case?: boolean;
+  context?: number;
+  tail?: number;
+  previous?: boolean;
+}
+
+export interface PodDeletionRequest {
+  selector?: string;
+  pods?: string[];
+  max_count?: number;
+  dry_run?: boolean;
+  evict?: boolean;
+}
+
+// One input to a HelmRelease's values: a valuesFrom reference, or the inline spec.values (kind HelmRelease), always last
+export interface ValuesSource {
+  kind: string;
+  name: string;
+  values_key?: string;
+  target_path?: string;
+  optional?: boolean;
+  status: 'applied' | 'skipped' | 'error';
+  error?: string;
+}
+
+// A leaf of the effective values; source and overridden are indexes into HelmReleaseValues.sources
+export interface ValueOrigin {
+  path: string;
+  source: number;
+  overridden?: number[];
+}
+
+export interface HelmReleaseValues {
+  namespace: string;
+  name: string;
+  values: Record<string, any>;
+  sources: ValuesSource[];
+  keys: ValueOrigin[];
+  redacted: boolean;
+}
+
+export interface HelmRevision {
+  revision: number;
+  status: string;
+  chart_name: string;
+  chart_version: string;
+  app_version?: string;
+  description?: string;
+  first_deployed?: string;
+  last_deployed?: string;
+  values?: Record<string, any>;
+}
+
+// Revisions of the Helm release behind a HelmRelease, newest first
+export interface HelmReleaseHistory {
+  namespace: string;
+  name: string;
+  release_name: string;
+  storage_namespace: string;
+  revisions: HelmRevision[];
+  values_redacted: boolean;
+}
+
+export interface HelmRollbackResult {
+  revision: number;
+  chart_version: string;
+  values_restored: boolean;
+  message: string;
+}
+
+// A force or reset request sent to a HelmRelease, with its failure counts before it
+export interface HelmRemediationResult {
+  requested_at: string;
+  forced: boolean;
+  reset: boolean;
+  failures: number;
+  install_failures: number;
+  upgrade_failures: number;
+  install_retries: number;
+  upgrade_retries: number;
+  message: string;
+}
+
+// Keys of a Secret and the sizes of their values; values are never returned
+export interface SecretKeys {
+  namespace: string;
+  name: string;
+  type: string;
+  keys: { name: string; size: number }[];
+}
+
+// An HTTP request sent to a pod port through a port-forward
+export interface PortForwardRequest {
+  port: number;
+  method?: string;
+  path?: string;
+  headers?: Record<string, string>;
+  body?: string;
+}
+
+export interface PortForwardResponse {
+  status_code: number;
+  status: string;
+  headers: Record<string, string>;
+  body: string;
+  truncated: boolean;
+  duration_ms: number;
+}
+
+export interface PodDeletionResult {
+  namespace: string;
+  selector?: string;
+  dry_run: boolean;
+  evict: boolean;
+  matched: string[];
+  deleted: string[];
+  missing?: string[];
+  failed?: { pod: string; error: string }[];
+  blocked?: { pod: string; error: string }[];
+  retry_after?: number;
+}
+
+export interface LogBundleRequest {
+  kind: string;
+  namespace: string;
+  name: string;
+  tail_lines?: number;
+  previous?: boolean;
+}
+
+export interface LogBundleJob {
+  id: string;
+  cluster_id: string;
+  kind: string;
+  namespace: string;
+  name: string;
+  status: 'pending' | 'running' | 'completed' | 'failed';
+  error?: string;
+  containers: number;
+  requested_by: string;
+  file_name: string;
+  created_at: string;
+  completed_at?: string;
+  expires_at: string;
+}
+
+export type BulkAction = 'suspend' | 'resume' | 'reconcile';
+
+// Narrows a bulk suspend, resume or reconcile; empty fields match everything
+export interface SuspendScope {
+  kinds?: ('Kustomization' | 'HelmRelease')[];
+  namespace?: string;
+  label_selector?: string;
+}
+
+export interface SuspendTarget {
+  kind: string;
+  namespace: string;
+  name: string;
+  suspended: boolean;
+}
+
+export interface BulkJobResult {
+  kind: string;
+  namespace: string;
+  name: string;
+  status: 'applied' | 'skipped' | 'failed';
+  error?: string;
+}
+
+export interface BulkJob {
+  id: string;
+  cluster_id: string;
+  action: BulkAction;
+  scope: SuspendScope;
+  status: 'pending' | 'running' | 'completed' | 'failed';
+  error?: string;
+  requested_by: string;
+  summary: { matched: number; applied: number; skipped: number; failed: number };
+  results: BulkJobResult[];
+  created_at: string;
+  completed_at?: string;
+  expires_at: string;
+}
+
+// A full cluster sync queued by POST /clusters/{id}/sync
+export interface SyncJob {
+  id: string;
+  cluster_id: string;
+  status: 'pending' | 'running' | 'completed' | 'failed';
+  count: number;
+  error?: string;
+  requested_by: string;
+  created_at: string;
+  started_at?: string;
+  completed_at?: string;
+  expires_at: string;
+}
+
 export interface ReconcileRequest {
   cluster_id: string;
   kind: string;
   name: string;
   namespace: string;
+  with_source?: boolean;
+  force?: boolean; // HelmReleases only, like flux reconcile --force
+}
+
+// The source reconciled before a Kustomization or HelmRelease with with_source
+export interface SourceReconcileResult {
+  kind: string;
+  namespace: string;
+  name: string;
+  previous_revision?: string;
+  revision?: string;
+  updated: boolean;
+}
+
+export interface ReconcileCondition {
+  type: string;
+  status: string;
+  reason?: string;
+  message?: string;
+}
+
+// The state of a Flux resource while it handles a reconcile started with ?wait=true
+export interface ReconcileProgress {
+  phase: 'pending' | 'progressing' | 'ready' | 'failed';
+  handled: boolean;
+  reason?: string;
+  message?: string;
+  revision?: string;
+  conditions: ReconcileCondition[];
+  elapsed_ms: number;
+}
+
+export interface ReconcileWaitResult {
+  message: string;
+  progress: ReconcileProgress;
+  source?: SourceReconcileResult;
+}
+
+// The change reconciling a Kustomization would make to one object
+export interface ObjectDiff {
+  api_version: string;
+  kind: string;
+  namespace?: string;
+  name: string;
+  action: 'created' | 'configured' | 'unchanged' | 'deleted' | 'error';
+  diff?: string; // unified diff from the live object to the dry-run result
+  error?: string;
+}
+
+// A preview of a Kustomization's next reconcile, like flux diff kustomization
+export interface KustomizationDiff {
+  namespace: string;
+  name: string;
+  source: { kind: string; namespace: string; name: string };
+  revision: string;
+  path: string;
+  objects: ObjectDiff[];
+  summary: Record<string, number>;
+  warnings: string[];
+  secret_variables: number;
+  redacted: boolean;
+}
+
+// The outcome of server-side applying one object of raw manifests
+export interface AppliedObject {
+  api_version: string;
+  kind: string;
+  namespace?: string;
+  name: string;
+  action: 'created' | 'configured' | 'unchanged' | 'error';
+  error?: string;
+}
+
+export interface ApplyResult {
+  dry_run: boolean;
+  objects: AppliedObject[];
+  summary: Record<string, number>;
 }
 
 export interface Setting {
@@ -68,19 +654,235 @@ export interface ResourceNode {
   created_at: string;
   children: ResourceNode[];
   metadata?: Record<string, any>;
+  // Pod usage, or the total of the pods below; only on clusters running metrics-server
+  usage?: ResourceUsage;
+}
+
+export interface ResourceUsage {
+  cpu_millicores: number;
+  memory_bytes: number;
+}
+
+export interface ContainerMetrics {
+  name: string;
+  usage: ResourceUsage;
+  requests: ResourceUsage; // zero when unset
+  limits: ResourceUsage;
+}
+
+export interface PodMetrics {
+  namespace: string;
+  name: string;
+  timestamp: string;
+  window: string;
+  usage: ResourceUsage;
+  requests: ResourceUsage;
+  limits: ResourceUsage;
+  containers: ContainerMetrics[];
+  node?: {
+    name: string;
+    usage: ResourceUsage;
+    allocatable: ResourceUsage;
+  };
+}
+
+export interface QuotaUsage {
+  resource: string;
+  hard: string;
+  used: string;
+  ratio: number;
+}
+
+export interface ResourceQuotaSummary {
+  name: string;
+  usage: QuotaUsage[];
+  max_ratio: number;
+  near_limit: boolean;
+  exceeded: boolean;
+}
+
+export interface LimitRangeItem {
+  type: string;
+  resource: string;
+  min?: string;
+  max?: string;
+  default?: string;
+  default_request?: string;
+}
+
+export interface QuotaApp {
+  id: string;
+  kind: string;
+  name: string;
+  namespace: string;
+  status: string;
+  message: string;
+  at_risk: boolean;
+}
+
+export interface NamespaceQuotas {
+  namespace: string;
+  quotas: ResourceQuotaSummary[];
+  limit_ranges: { name: string; limits: LimitRangeItem[] }[];
+  max_ratio: number;
+  near_limit: boolean;
+  exceeded: boolean;
+  apps: QuotaApp[];
+  apps_by_kind: Record<string, number>;
+}
+
+export interface ClusterQuotas {
+  cluster_id: string;
+  threshold: number;
+  namespaces: NamespaceQuotas[];
+  near_limit: number;
+  at_risk: number;
+}
+
+export interface NamespaceSummary {
+  name: string;
+  status: 'Active' | 'Terminating';
+  labels: Record<string, string>;
+  created_at: string;
+  managed: boolean; // created through the orchestrator
+  flux_resources: number;
+}
+
+// A workload with no Flux ownership labels that is in no Kustomization inventory
+export interface UnmanagedObject {
+  kind: string;
+  namespace: string;
+  name: string;
+  created_at: string;
+  managed_by?: string; // app.kubernetes.io/managed-by, such as Helm
+}
+
+export interface ImageStatus {
+  workload: { kind: string; namespace: string; name: string };
+  container: string;
+  image: string;
+  repository: string;
+  tag?: string;
+  digest?: string;
+  latest_tag?: string;
+  status: 'up_to_date' | 'outdated' | 'unknown' | 'error';
+  message?: string;
+}
+
+// The images of the workloads one Kustomization or HelmRelease applied
+export interface ImageOwner {
+  kind: string;
+  namespace: string;
+  name: string;
+  images: ImageStatus[];
+  outdated: number;
+}
+
+export interface ImageFreshnessReport {
+  cluster_id: string;
+  cluster_name: string;
+  owners: ImageOwner[];
+  images: number;
+  outdated: number;
+  warnings: string[];
+}
+
+export interface UnmanagedReport {
+  cluster_id: string;
+  cluster_name: string;
+  namespace?: string;
+  scanned: number;
+  objects: UnmanagedObject[];
+  by_namespace: Record<string, number>;
+  skipped_namespaces?: string[];
+  warnings: string[];
 }
 
 export interface AzureSubscription {
   id: string;
   name: string;
   tenant_id: string;
+  has_credentials: boolean;
   status: string;
   cluster_count: number;
   last_synced_at?: string;
   created_at: string;
+  version: number;
   updated_at: string;
 }
 
+export type RegistryProvider = 'acr' | 'ecr' | 'gcr' | 'dockerhub' | 'generic';
+
+export interface RegistryCredential {
+  id: string;
+  name: string;
+  provider: RegistryProvider;
+  registry: string;
+  username: string;
+  has_credentials: boolean;
+  status: string;
+  status_message?: string;
+  last_tested_at?: string;
+  version: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface RegistryCredentialInput {
+  name: string;
+  provider: RegistryProvider;
+  registry?: string;
+  username?: string;
+  password: string;
+}
+
+// A user's consent for the orchestrator to call their git provider as them; the token is never returned
+export interface ProviderGrant {
+  id: string;
+  user_id: string;
+  provider: string;
+  username: string;
+  scopes: string;
+  expires_at?: string;
+  last_used_at?: string;
+  created_at: string;
+}
+
+export interface ProviderGrants {
+  available: string[]; // providers the caller can grant
+  scopes: string[]; // scopes a new grant asks for
+  grants: ProviderGrant[];
+}
+
+// The commit a GitRepository last fetched; as_user when read with the caller's grant
+export interface SourceCommit {
+  repository: string;
+  sha: string;
+  message: string;
+  author_name: string;
+  author_email: string;
+  date: string;
+  url: string;
+  as_user: boolean;
+}
+
+// A branch or tag of a GitRepository's remote and the commit it points at
+export interface GitRef {
+  name: string;
+  sha: string;
+}
+
+export interface SourceRefs {
+  namespace: string;
+  name: string;
+  url: string;
+  branch?: string;
+  tag?: string;
+  branches: GitRef[];
+  tags: GitRef[];
+  as_user: boolean;
+}
+
 export interface AKSCluster {
   id: string;
   name: string;
@@ -109,14 +911,108 @@ export interface Activity {
   user_id: string;
   status: 'success' | 'failed';
   message: string;
+  details?: string; // JSON: WorkloadActionDetails for scale and restart, otherwise an ActivityChangeSet
   created_at: string;
 }
 
+// A Flux resource moving to a new revision, recorded by the sync
+export interface DeploymentEvent {
+  id: number;
+  cluster_id: string;
+  cluster_name: string;
+  resource_id: string;
+  kind: string;
+  namespace: string;
+  name: string;
+  type: 'artifact' | 'applied' | 'chart';
+  old_revision: string;
+  new_revision: string;
+  recorded_at: string;
+}
+
+export interface DeploymentTimeline {
+  since: string;
+  until: string;
+  events: DeploymentEvent[];
+  truncated: boolean;
+}
+
+export interface TimelineParams {
+  cluster_id?: string;
+  kind?: string;
+  namespace?: string;
+  since?: string;
+  until?: string;
+  around?: string;
+  window?: string;
+  limit?: number;
+}
+
+// Kubernetes selectors narrowing a list, e.g. app.kubernetes.io/part-of=payments
+export interface ListSelectors {
+  labelSelector?: string;
+  fieldSelector?: string;
+}
+
+// One entry of a cluster's incident feed; detail is the activity, transition, deployment or event
+export interface IncidentEntry {
+  time: string;
+  source: 'activity' | 'transition' | 'deployment' | 'event';
+  severity: 'info' | 'warning' | 'error';
+  kind: string;
+  namespace?: string;
+  name: string;
+  summary: string;
+  detail: unknown;
+}
+
+export interface IncidentFeed {
+  cluster_id: string;
+  cluster_name: string;
+  since: string;
+  until: string;
+  entries: IncidentEntry[];
+  counts: Record<string, number>;
+  warnings: string[];
+}
+
+export interface WorkloadSnapshot {
+  replicas: number;
+  ready_replicas: number;
+  updated_replicas: number;
+  available_replicas: number;
+  generation: number;
+  observed_generation: number;
+  captured_at: string;
+}
+
+export interface WorkloadActionDetails {
+  requested_replicas?: number;
+  before?: WorkloadSnapshot;
+  after?: WorkloadSnapshot;
+  settled: boolean;
+  following: boolean;
+}
+
+// One field an action changed; old is absent when it was unknown, new when it was removed
+export interface ActivityChange {
+  field: string;
+  old?: unknown;
+  new?: unknown;
+}
+
+// Details of activities that change something, such as cluster and setting updates
+export interface ActivityChangeSet {
+  parameters?: Record<string, unknown>;
+  changes?: ActivityChange[];
+}
+
 export interface OAuthProvider {
   id: string;
   name: string;
   provider: 'github' | 'entra';
   client_id: string;
+  has_client_secret: boolean;
   tenant_id?: string;
   redirect_url: string;
   scopes?: string;
@@ -124,5 +1020,58 @@ export interface OAuthProvider {
   enabled: boolean;
   status: 'healthy' | 'unhealthy' | 'unknown';
   created_at: string;
+  version: number;
   updated_at: string;
 }
+
+export type ComponentState = 'ok' | 'degraded' | 'down' | 'disabled';
+
+export interface SystemComponent {
+  name: string;
+  status: ComponentState;
+  message?: string;
+  details?: Record<string, unknown>;
+}
+
+export interface SystemStatus {
+  status: ComponentState;
+  checked_at: string;
+  components: SystemComponent[];
+}
+
+export type OnboardingStepStatus = 'complete' | 'pending' | 'skipped';
+
+export interface OnboardingStep {
+  id: string;
+  title: string;
+  description: string;
+  optional: boolean;
+  status: OnboardingStepStatus;
+  message: string;
+}
+
+export interface OnboardingState {
+  steps: OnboardingStep[];
+  current_step?: string;
+  completed: boolean;
+  dismissed: boolean;
+  completed_at?: string;
+}
+
+export type EventSeverity = 'info' | 'warning' | 'error';
+
+export interface OrchestratorEvent {
+  sequence: number;
+  id: string;
+  type: string;
+  timestamp: string;
+  cluster_id?: string;
+  resource?: Record<string, unknown>;
+  message: string;
+  severity: EventSeverity;
+}
+
+export interface RecentEvents {
+  events: OrchestratorEvent[];
+  latest_sequence: number;
+}
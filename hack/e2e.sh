@@ -0,0 +1,78 @@
+#!/usr/bin/env bash
+# Runs the end-to-end suite: creates a kind cluster with Flux and the podinfo fixtures,
+# starts PostgreSQL and the orchestrator, then drives the API with tools/e2e.
+#
+# Requires docker, kind, kubectl and the flux CLI. Set E2E_KEEP=true to leave the
+# cluster, database and server running for debugging.
+set -euo pipefail
+
+ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+CLUSTER_NAME="${E2E_CLUSTER_NAME:-flux-orchestrator-e2e}"
+DB_CONTAINER="${E2E_DB_CONTAINER:-flux-orchestrator-e2e-db}"
+DB_PORT="${E2E_DB_PORT:-55432}"
+PORT="${E2E_PORT:-18080}"
+WORK_DIR="$(mktemp -d)"
+KUBECONFIG_FILE="${WORK_DIR}/kubeconfig"
+SERVER_LOG="${WORK_DIR}/server.log"
+SERVER_PID=""
+
+for tool in docker kind kubectl flux go; do
+  if ! command -v "${tool}" >/dev/null 2>&1; then
+    echo "e2e: ${tool} is required" >&2
+    exit 1
+  fi
+done
+
+cleanup() {
+  status=$?
+  if [ "${status}" -ne 0 ] && [ -f "${SERVER_LOG}" ]; then
+    echo "e2e: server log:" >&2
+    tail -n 100 "${SERVER_LOG}" >&2
+  fi
+  if [ "${E2E_KEEP:-false}" = "true" ]; then
+    echo "e2e: E2E_KEEP=true, leaving cluster ${CLUSTER_NAME}, database ${DB_CONTAINER} and server (pid ${SERVER_PID}) running"
+    echo "e2e: kubeconfig ${KUBECONFIG_FILE}, server log ${SERVER_LOG}"
+    exit "${status}"
+  fi
+  [ -n "${SERVER_PID}" ] && kill "${SERVER_PID}" 2>/dev/null || true
+  docker rm -f "${DB_CONTAINER}" >/dev/null 2>&1 || true
+  kind delete cluster --name "${CLUSTER_NAME}" >/dev/null 2>&1 || true
+  rm -rf "${WORK_DIR}"
+  exit "${status}"
+}
+trap cleanup EXIT
+
+echo "e2e: creating kind cluster ${CLUSTER_NAME}"
+kind create cluster --name "${CLUSTER_NAME}" --kubeconfig "${KUBECONFIG_FILE}" --wait 120s
+export KUBECONFIG="${KUBECONFIG_FILE}"
+
+echo "e2e: installing Flux"
+flux install --timeout 5m
+
+echo "e2e: applying fixtures"
+kubectl apply -f "${ROOT}/hack/e2e/fixtures.yaml"
+kubectl -n flux-system wait kustomization/podinfo --for=condition=Ready --timeout=5m
+
+echo "e2e: starting PostgreSQL"
+docker rm -f "${DB_CONTAINER}" >/dev/null 2>&1 || true
+docker run -d --name "${DB_CONTAINER}" -p "${DB_PORT}:5432" \
+  -e POSTGRES_PASSWORD=postgres -e POSTGRES_DB=flux_orchestrator postgres:16-alpine >/dev/null
+until docker exec "${DB_CONTAINER}" pg_isready -U postgres >/dev/null 2>&1; do sleep 1; done
+
+echo "e2e: starting flux-orchestrator on :${PORT}"
+(cd "${ROOT}" && go build -o "${WORK_DIR}/flux-orchestrator" ./backend/cmd/server)
+ENCRYPTION_KEY="$(head -c 32 /dev/urandom | base64 | tr '+/' '-_')" \
+  DB_HOST=localhost DB_PORT="${DB_PORT}" DB_USER=postgres DB_PASSWORD=postgres \
+  DB_NAME=flux_orchestrator DB_SSLMODE=disable PORT="${PORT}" \
+  "${WORK_DIR}/flux-orchestrator" >"${SERVER_LOG}" 2>&1 &
+SERVER_PID=$!
+
+for _ in $(seq 1 60); do
+  curl -sf "http://localhost:${PORT}/health" >/dev/null && break
+  sleep 1
+done
+curl -sf "http://localhost:${PORT}/health" >/dev/null
+
+echo "e2e: running suite"
+(cd "${ROOT}" && E2E_BASE_URL="http://localhost:${PORT}" E2E_KUBECONFIG="${KUBECONFIG_FILE}" \
+  go run -tags e2e ./tools/e2e)
@@ -0,0 +1,420 @@
+//go:build e2e
+
+// Command e2e drives a running flux-orchestrator against a real cluster with Flux and the
+// hack/e2e fixtures installed, exercising the endpoints backed by k8s.Client. It is run
+// by hack/e2e.sh, which sets E2E_BASE_URL and E2E_KUBECONFIG.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Fixture names from hack/e2e/fixtures.yaml
+const (
+	fluxNamespace = "flux-system"
+	appNamespace  = "podinfo"
+	appName       = "podinfo"
+	appContainer  = "podinfod"
+)
+
+// syncTimeout bounds how long a cluster sync job is polled
+const syncTimeout = 2 * time.Minute
+
+// fluxResource is the subset of a Flux resource response the suite checks
+type fluxResource struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status"`
+	Metadata  string `json:"metadata"`
+}
+
+// syncJob is the subset of a sync job the suite checks
+type syncJob struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+	Error  string `json:"error"`
+}
+
+// treeNode is a node of the resource tree response
+type treeNode struct {
+	Kind      string     `json:"kind"`
+	Name      string     `json:"name"`
+	Namespace string     `json:"namespace"`
+	Children  []treeNode `json:"children"`
+}
+
+// suite holds the state shared between steps
+type suite struct {
+	baseURL    string
+	kubeconfig string
+	http       *http.Client
+	clusterID  string
+	podName    string
+}
+
+// step is a named check; steps run in order and stop at the first failure
+type step struct {
+	name string
+	run  func(s *suite) error
+}
+
+func main() {
+	baseURL := strings.TrimSuffix(os.Getenv("E2E_BASE_URL"), "/")
+	kubeconfigPath := os.Getenv("E2E_KUBECONFIG")
+	if baseURL == "" || kubeconfigPath == "" {
+		log.Fatal("E2E_BASE_URL and E2E_KUBECONFIG are required; run hack/e2e.sh")
+	}
+	kubeconfig, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		log.Fatalf("Failed to read kubeconfig: %v", err)
+	}
+
+	s := &suite{
+		baseURL:    baseURL,
+		kubeconfig: string(kubeconfig),
+		http:       &http.Client{Timeout: 60 * time.Second},
+	}
+
+	steps := []step{
+		{"register cluster", (*suite).registerCluster},
+		{"check cluster health", (*suite).checkHealth},
+		{"sync resources", (*suite).syncResources},
+		{"list resources", (*suite).listResources},
+		{"reconcile kustomization", (*suite).reconcile},
+		{"suspend kustomization", func(s *suite) error { return s.setSuspended(true) }},
+		{"resume kustomization", func(s *suite) error { return s.setSuspended(false) }},
+		{"get resource tree", (*suite).resourceTree},
+		{"find podinfo pod", (*suite).findPod},
+		{"get pod containers", (*suite).podContainers},
+		{"get pod logs", (*suite).podLogs},
+		{"delete cluster", (*suite).deleteCluster},
+	}
+
+	failed := false
+	for _, st := range steps {
+		start := time.Now()
+		if err := st.run(s); err != nil {
+			fmt.Printf("FAIL  %s (%s): %v\n", st.name, time.Since(start).Round(time.Millisecond), err)
+			failed = true
+			break
+		}
+		fmt.Printf("PASS  %s (%s)\n", st.name, time.Since(start).Round(time.Millisecond))
+	}
+
+	if failed {
+		// Leave no cluster behind so the suite can be rerun against the same server
+		if s.clusterID != "" {
+			s.do(http.MethodDelete, "/api/v1/clusters/"+s.clusterID, nil, http.StatusOK, nil)
+		}
+		os.Exit(1)
+	}
+}
+
+func (s *suite) registerCluster() error {
+	var cluster struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	body := map[string]interface{}{
+		"name":        fmt.Sprintf("e2e-%d", time.Now().Unix()),
+		"description": "Registered by the end-to-end suite",
+		"kubeconfig":  s.kubeconfig,
+		// Reruns against the same server register the same API server again
+		"allow_duplicate": true,
+	}
+	if err := s.do(http.MethodPost, "/api/v1/clusters", body, http.StatusCreated, &cluster); err != nil {
+		return err
+	}
+	s.clusterID = cluster.ID
+	if cluster.Status != "healthy" {
+		return fmt.Errorf("expected status healthy, got %q", cluster.Status)
+	}
+	return nil
+}
+
+func (s *suite) checkHealth() error {
+	var health struct {
+		Status      string `json:"status"`
+		FluxStatus  string `json:"flux_status"`
+		FluxMessage string `json:"flux_message"`
+	}
+	if err := s.do(http.MethodGet, s.clusterPath("/health"), nil, http.StatusOK, &health); err != nil {
+		return err
+	}
+	if health.Status != "healthy" {
+		return fmt.Errorf("expected status healthy, got %q", health.Status)
+	}
+	if health.FluxStatus != "healthy" {
+		return fmt.Errorf("expected flux status healthy, got %q: %s", health.FluxStatus, health.FluxMessage)
+	}
+	return nil
+}
+
+// syncResources queues a sync and polls its job until it finishes
+func (s *suite) syncResources() error {
+	var job syncJob
+	if err := s.do(http.MethodPost, s.clusterPath("/sync"), nil, http.StatusAccepted, &job); err != nil {
+		return err
+	}
+	if job.ID == "" {
+		return fmt.Errorf("sync returned no job ID")
+	}
+
+	deadline := time.Now().Add(syncTimeout)
+	for job.Status != "completed" && job.Status != "failed" {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("sync job %s still %s after %s", job.ID, job.Status, syncTimeout)
+		}
+		time.Sleep(time.Second)
+		if err := s.do(http.MethodGet, s.clusterPath("/sync/"+job.ID), nil, http.StatusOK, &job); err != nil {
+			return err
+		}
+	}
+	if job.Status == "failed" {
+		return fmt.Errorf("sync job %s failed: %s", job.ID, job.Error)
+	}
+	if job.Count < 2 {
+		return fmt.Errorf("expected at least 2 resources, synced %d", job.Count)
+	}
+	return nil
+}
+
+func (s *suite) listResources() error {
+	var resources []fluxResource
+	if err := s.do(http.MethodGet, s.clusterPath("/resources"), nil, http.StatusOK, &resources); err != nil {
+		return err
+	}
+	for _, kind := range []string{"GitRepository", "Kustomization"} {
+		res := findResource(resources, kind, fluxNamespace, appName)
+		if res == nil {
+			return fmt.Errorf("%s %s/%s was not synced", kind, fluxNamespace, appName)
+		}
+		if res.ID == "" {
+			return fmt.Errorf("%s %s/%s has no ID", kind, fluxNamespace, appName)
+		}
+		if res.Status != "Ready" {
+			return fmt.Errorf("%s %s/%s is %s", kind, fluxNamespace, appName, res.Status)
+		}
+	}
+	return nil
+}
+
+func (s *suite) reconcile() error {
+	if err := s.do(http.MethodPost, s.kustomizationPath("/reconcile"), nil, http.StatusOK, nil); err != nil {
+		return err
+	}
+	obj, err := s.kustomization()
+	if err != nil {
+		return err
+	}
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	if annotations["reconcile.fluxcd.io/requestedAt"] == nil {
+		return fmt.Errorf("reconcile.fluxcd.io/requestedAt annotation was not set")
+	}
+	return nil
+}
+
+func (s *suite) setSuspended(suspended bool) error {
+	action := "/resume"
+	if suspended {
+		action = "/suspend"
+	}
+	if err := s.do(http.MethodPost, s.kustomizationPath(action), nil, http.StatusOK, nil); err != nil {
+		return err
+	}
+	obj, err := s.kustomization()
+	if err != nil {
+		return err
+	}
+	spec, _ := obj["spec"].(map[string]interface{})
+	if got, _ := spec["suspend"].(bool); got != suspended {
+		return fmt.Errorf("expected spec.suspend %t, got %v", suspended, spec["suspend"])
+	}
+	return nil
+}
+
+func (s *suite) resourceTree() error {
+	var result struct {
+		Tree []treeNode `json:"tree"`
+	}
+	if err := s.do(http.MethodGet, s.clusterPath("/resources/tree"), nil, http.StatusOK, &result); err != nil {
+		return err
+	}
+	if findNode(result.Tree, "Kustomization", fluxNamespace, appName) == nil {
+		return fmt.Errorf("Kustomization %s/%s is missing from the tree", fluxNamespace, appName)
+	}
+	if findNode(result.Tree, "Deployment", appNamespace, appName) == nil {
+		return fmt.Errorf("Deployment %s/%s is missing from the tree", appNamespace, appName)
+	}
+	return nil
+}
+
+// findPod looks up a running podinfo pod directly, since pods are nested under
+// ReplicaSets in the tree and are not listed by any other endpoint
+func (s *suite) findPod() error {
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(s.kubeconfig))
+	if err != nil {
+		return fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	pods, err := clientset.CoreV1().Pods(appNamespace).List(context.Background(), metav1.ListOptions{LabelSelector: "app=" + appName})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			s.podName = pod.Name
+			return nil
+		}
+	}
+	return fmt.Errorf("no running %s pod in namespace %s", appName, appNamespace)
+}
+
+func (s *suite) podContainers() error {
+	var result struct {
+		Containers []string `json:"containers"`
+	}
+	if err := s.do(http.MethodGet, s.podPath("/containers"), nil, http.StatusOK, &result); err != nil {
+		return err
+	}
+	for _, name := range result.Containers {
+		if name == appContainer {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected container %s, got %v", appContainer, result.Containers)
+}
+
+func (s *suite) podLogs() error {
+	var result struct {
+		Logs string `json:"logs"`
+	}
+	path := s.podPath("/logs") + "?container=" + appContainer + "&tail=50"
+	if err := s.do(http.MethodGet, path, nil, http.StatusOK, &result); err != nil {
+		return err
+	}
+	if strings.TrimSpace(result.Logs) == "" {
+		return fmt.Errorf("pod %s/%s returned no logs", appNamespace, s.podName)
+	}
+	return nil
+}
+
+func (s *suite) deleteCluster() error {
+	if err := s.do(http.MethodDelete, "/api/v1/clusters/"+s.clusterID, nil, http.StatusOK, nil); err != nil {
+		return err
+	}
+	err := s.do(http.MethodGet, "/api/v1/clusters/"+s.clusterID, nil, http.StatusNotFound, nil)
+	s.clusterID = ""
+	return err
+}
+
+// kustomization returns the live fixture Kustomization object
+func (s *suite) kustomization() (map[string]interface{}, error) {
+	var res fluxResource
+	if err := s.do(http.MethodGet, s.kustomizationPath(""), nil, http.StatusOK, &res); err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(res.Metadata), &obj); err != nil {
+		return nil, fmt.Errorf("failed to decode resource metadata: %w", err)
+	}
+	return obj, nil
+}
+
+func (s *suite) clusterPath(suffix string) string {
+	return "/api/v1/clusters/" + s.clusterID + suffix
+}
+
+func (s *suite) kustomizationPath(suffix string) string {
+	return s.clusterPath(fmt.Sprintf("/flux/Kustomization/%s/%s%s", fluxNamespace, appName, suffix))
+}
+
+func (s *suite) podPath(suffix string) string {
+	return s.clusterPath(fmt.Sprintf("/pods/%s/%s%s", appNamespace, s.podName, suffix))
+}
+
+// do sends a request and decodes the response into out, failing unless the response has
+// the wanted status code
+func (s *suite) do(method, path string, body interface{}, want int, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.StatusCode != want {
+		return fmt.Errorf("%s %s: expected %d, got %d: %s", method, path, want, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("%s %s: failed to decode response: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+func findResource(resources []fluxResource, kind, namespace, name string) *fluxResource {
+	for i := range resources {
+		res := &resources[i]
+		if res.Kind == kind && res.Namespace == namespace && res.Name == name {
+			return res
+		}
+	}
+	return nil
+}
+
+// findNode searches the tree depth first
+func findNode(nodes []treeNode, kind, namespace, name string) *treeNode {
+	for i := range nodes {
+		n := &nodes[i]
+		if n.Kind == kind && n.Namespace == namespace && n.Name == name {
+			return n
+		}
+		if found := findNode(n.Children, kind, namespace, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
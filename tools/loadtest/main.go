@@ -0,0 +1,339 @@
+//go:build loadtest
+
+// Command loadtest measures list, dashboard, tree and sync latency against a server
+// running in load-test mode (LOADTEST_CLUSTERS set), and optionally compares the results
+// with a saved baseline to catch regressions.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// clusterPrefix matches loadtest.ClusterPrefix in the backend
+const clusterPrefix = "loadtest-"
+
+// endpoint is a scenario measured by the load test. Paths may contain {id}, replaced by
+// a random load-test cluster on every request.
+type endpoint struct {
+	name   string
+	method string
+	paths  []string // requested in parallel; the scenario takes as long as the slowest
+	job    bool     // paths answer 202 with a job polled at path/{jobId} until it finishes
+}
+
+// jobPollInterval is how often a queued job is polled
+const jobPollInterval = 100 * time.Millisecond
+
+var endpoints = []endpoint{
+	{"clusters", http.MethodGet, []string{"/api/v1/clusters"}, false},
+	{"clusters-v2", http.MethodGet, []string{"/api/v2/clusters"}, false},
+	{"resources", http.MethodGet, []string{"/api/v1/resources"}, false},
+	{"resources-v2", http.MethodGet, []string{"/api/v2/resources"}, false},
+	{"dashboard", http.MethodGet, []string{"/api/v1/clusters", "/api/v1/resources"}, false},
+	{"cluster-resources", http.MethodGet, []string{"/api/v1/clusters/{id}/resources"}, false},
+	{"tree", http.MethodGet, []string{"/api/v1/clusters/{id}/resources/tree"}, false},
+	{"system-status", http.MethodGet, []string{"/api/v1/system/status"}, false},
+	{"sync", http.MethodPost, []string{"/api/v1/clusters/{id}/sync"}, true},
+}
+
+// Result summarizes the latency of one endpoint
+type Result struct {
+	Endpoint string        `json:"endpoint"`
+	Requests int           `json:"requests"`
+	Errors   int           `json:"errors"`
+	P50      time.Duration `json:"p50"`
+	P95      time.Duration `json:"p95"`
+	P99      time.Duration `json:"p99"`
+	Max      time.Duration `json:"max"`
+	RPS      float64       `json:"rps"`
+}
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:8080", "orchestrator base URL")
+	duration := flag.Duration("duration", 20*time.Second, "how long to load each endpoint")
+	concurrency := flag.Int("concurrency", 10, "concurrent requests per endpoint")
+	only := flag.String("endpoints", "", "comma-separated endpoints to measure (default all)")
+	output := flag.String("output", "", "write results as JSON to this file")
+	baselinePath := flag.String("baseline", "", "compare p95 latency with results saved by -output")
+	maxRegression := flag.Float64("max-regression", 20, "fail when p95 exceeds the baseline by more than this percentage")
+	flag.Parse()
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	url := strings.TrimSuffix(*baseURL, "/")
+
+	clusterIDs, err := loadtestClusters(client, url)
+	if err != nil {
+		log.Fatalf("Failed to list clusters: %v", err)
+	}
+	if len(clusterIDs) == 0 {
+		log.Fatalf("No %s* clusters found; start the server with LOADTEST_CLUSTERS set", clusterPrefix)
+	}
+	fmt.Printf("Measuring %d load-test clusters for %s per endpoint at concurrency %d\n\n", len(clusterIDs), *duration, *concurrency)
+
+	selected := selectEndpoints(*only)
+	results := make([]Result, 0, len(selected))
+	for _, ep := range selected {
+		results = append(results, run(client, url, ep, clusterIDs, *duration, *concurrency))
+	}
+
+	printResults(results)
+
+	if *output != "" {
+		data, _ := json.MarshalIndent(results, "", "  ")
+		if err := os.WriteFile(*output, data, 0o644); err != nil {
+			log.Fatalf("Failed to write results: %v", err)
+		}
+	}
+
+	if *baselinePath != "" {
+		if regressions := compare(results, *baselinePath, *maxRegression); regressions > 0 {
+			fmt.Printf("\n%d endpoint(s) regressed by more than %.0f%%\n", regressions, *maxRegression)
+			os.Exit(1)
+		}
+	}
+}
+
+// loadtestClusters returns the IDs of the clusters seeded by load-test mode
+func loadtestClusters(client *http.Client, url string) ([]string, error) {
+	resp, err := client.Get(url + "/api/v1/clusters")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var clusters []struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&clusters); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, c := range clusters {
+		if strings.HasPrefix(c.ID, clusterPrefix) {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+
+// selectEndpoints returns the endpoints named in only, or every endpoint
+func selectEndpoints(only string) []endpoint {
+	if only == "" {
+		return endpoints
+	}
+	var selected []endpoint
+	for _, name := range strings.Split(only, ",") {
+		found := false
+		for _, ep := range endpoints {
+			if ep.name == strings.TrimSpace(name) {
+				selected = append(selected, ep)
+				found = true
+			}
+		}
+		if !found {
+			log.Fatalf("Unknown endpoint %q", name)
+		}
+	}
+	return selected
+}
+
+// run loads one endpoint for the given duration and summarizes its latency
+func run(client *http.Client, url string, ep endpoint, clusterIDs []string, duration time.Duration, concurrency int) Result {
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		errors    int
+		wg        sync.WaitGroup
+	)
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for time.Now().Before(deadline) {
+				clusterID := clusterIDs[rng.Intn(len(clusterIDs))]
+				latency, err := request(client, url, ep, clusterID)
+
+				mu.Lock()
+				latencies = append(latencies, latency)
+				if err != nil {
+					errors++
+				}
+				mu.Unlock()
+			}
+		}(int64(worker))
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result := Result{
+		Endpoint: ep.name,
+		Requests: len(latencies),
+		Errors:   errors,
+		P50:      percentile(latencies, 50),
+		P95:      percentile(latencies, 95),
+		P99:      percentile(latencies, 99),
+		RPS:      float64(len(latencies)) / elapsed.Seconds(),
+	}
+	if len(latencies) > 0 {
+		result.Max = latencies[len(latencies)-1]
+	}
+	return result
+}
+
+// request performs one scenario, issuing its paths in parallel. For a job scenario the
+// latency runs until every job has finished.
+func request(client *http.Client, url string, ep endpoint, clusterID string) (time.Duration, error) {
+	start := time.Now()
+	errs := make(chan error, len(ep.paths))
+	for _, path := range ep.paths {
+		go func(path string) {
+			path = url + strings.ReplaceAll(path, "{id}", clusterID)
+			body, err := call(client, ep.method, path)
+			if err == nil && ep.job {
+				err = waitForJob(client, path, body)
+			}
+			errs <- err
+		}(path)
+	}
+
+	var firstErr error
+	for range ep.paths {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Since(start), firstErr
+}
+
+// call issues one request and returns its body. The whole body is read so serialization
+// is part of the measured latency.
+func call(client *http.Client, method, url string) ([]byte, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// waitForJob polls the job named by a 202 response body until it completes or fails.
+// Concurrent requests for a cluster share one job, so each waits for the same sync.
+func waitForJob(client *http.Client, url string, body []byte) error {
+	var job struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &job); err != nil || job.ID == "" {
+		return fmt.Errorf("%s: response names no job", url)
+	}
+	for job.Status != "completed" && job.Status != "failed" {
+		time.Sleep(jobPollInterval)
+		body, err := call(client, http.MethodGet, url+"/"+job.ID)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, &job); err != nil {
+			return fmt.Errorf("%s/%s: %v", url, job.ID, err)
+		}
+	}
+	if job.Status == "failed" {
+		return fmt.Errorf("%s: job %s failed: %s", url, job.ID, job.Error)
+	}
+	return nil
+}
+
+// percentile returns the pth percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := (len(sorted)*p+99)/100 - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
+
+func printResults(results []Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENDPOINT\tREQUESTS\tERRORS\tP50\tP95\tP99\tMAX\tRPS")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%.1f\n", r.Endpoint, r.Requests, r.Errors,
+			round(r.P50), round(r.P95), round(r.P99), round(r.Max), r.RPS)
+	}
+	w.Flush()
+}
+
+// compare reports endpoints whose p95 latency regressed against the baseline and
+// returns how many did
+func compare(results []Result, baselinePath string, maxRegression float64) int {
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		log.Fatalf("Failed to read baseline: %v", err)
+	}
+	var baseline []Result
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		log.Fatalf("Failed to parse baseline: %v", err)
+	}
+	previous := make(map[string]Result, len(baseline))
+	for _, r := range baseline {
+		previous[r.Endpoint] = r
+	}
+
+	fmt.Println()
+	regressions := 0
+	for _, r := range results {
+		base, ok := previous[r.Endpoint]
+		if !ok || base.P95 == 0 {
+			continue
+		}
+		change := (float64(r.P95) - float64(base.P95)) / float64(base.P95) * 100
+		verdict := "ok"
+		if change > maxRegression {
+			verdict = "REGRESSED"
+			regressions++
+		}
+		fmt.Printf("%-18s p95 %s -> %s (%+.1f%%) %s\n", r.Endpoint, round(base.P95), round(r.P95), change, verdict)
+	}
+	return regressions
+}
+
+func round(d time.Duration) time.Duration {
+	if d > time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(10 * time.Microsecond)
+}